@@ -0,0 +1,277 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// FailbackPolicy is the policy of an ActivePassiveClient for switching back to
+// its primary.
+type FailbackPolicy int
+
+const (
+	// FailbackAuto switches back to the primary once it has been healthy for
+	// ActivePassiveOptions.FailbackDelay.
+	FailbackAuto FailbackPolicy = iota
+	// FailbackManual only switches back to the primary with
+	// ActivePassiveClient.Failback.
+	FailbackManual
+)
+
+type ActivePassiveOptions struct {
+	// Primary and Standby are the clients of the active and passive
+	// deployments, independent of each other, e.g. in two regions. The
+	// replication of the data between them is not handled by the client.
+	Primary, Standby UniversalClient
+
+	// HealthCheckInterval is the interval between the PINGs of the primary,
+	// which time out after the interval. Default is 1 second.
+	HealthCheckInterval time.Duration
+	// FailoverThreshold is the number of consecutive failures of the primary,
+	// health checks or commands failing with a network error, after which the
+	// client fails over to the standby. Default is 3.
+	FailoverThreshold int
+	// Failback is the policy for switching back to the primary. Default is
+	// FailbackAuto.
+	Failback FailbackPolicy
+	// FailbackDelay is the time the primary must be healthy before FailbackAuto
+	// switches back to it. Default is 30 seconds.
+	FailbackDelay time.Duration
+
+	// OnSwitch is called when the client fails over to the standby, with
+	// standby true, and when it switches back to the primary.
+	OnSwitch func(standby bool)
+}
+
+func (opt *ActivePassiveOptions) init() {
+	if opt.HealthCheckInterval <= 0 {
+		opt.HealthCheckInterval = time.Second
+	}
+	if opt.FailoverThreshold <= 0 {
+		opt.FailoverThreshold = 3
+	}
+	if opt.FailbackDelay <= 0 {
+		opt.FailbackDelay = 30 * time.Second
+	}
+}
+
+// ActivePassiveClient sends the commands to a primary deployment, and to a
+// standby deployment while the primary is failing. Unlike FailoverClient, it
+// doesn't rely on Sentinel: it health-checks the primary itself, and fails
+// over after ActivePassiveOptions.FailoverThreshold consecutive failures.
+//
+// The pipelines are sent to the active deployment when they are executed.
+// Stateful commands such as WATCH and the PubSub are not failed over: use
+// Active to run them on the active deployment.
+type ActivePassiveClient struct {
+	cmdable
+
+	opt *ActivePassiveOptions
+
+	mu           sync.Mutex
+	standby      bool
+	failures     int
+	healthySince time.Time
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewActivePassiveClient returns a client of opt.Primary and opt.Standby,
+// health-checking the primary until the client is closed.
+func NewActivePassiveClient(opt *ActivePassiveOptions) *ActivePassiveClient {
+	opt.init()
+	c := &ActivePassiveClient{
+		opt:    opt,
+		closed: make(chan struct{}),
+	}
+	c.cmdable = c.Process
+
+	c.wg.Add(1)
+	go c.healthCheck()
+	return c
+}
+
+// Active returns the client of the deployment the commands are sent to.
+func (c *ActivePassiveClient) Active() UniversalClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.active()
+}
+
+func (c *ActivePassiveClient) active() UniversalClient {
+	if c.standby {
+		return c.opt.Standby
+	}
+	return c.opt.Primary
+}
+
+// IsFailedOver reports whether the commands are sent to the standby.
+func (c *ActivePassiveClient) IsFailedOver() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.standby
+}
+
+// Failover switches to the standby, whatever the health of the primary.
+func (c *ActivePassiveClient) Failover() {
+	c.switchTo(true)
+}
+
+// Failback switches back to the primary, whatever its health.
+func (c *ActivePassiveClient) Failback() {
+	c.switchTo(false)
+}
+
+func (c *ActivePassiveClient) switchTo(standby bool) {
+	c.mu.Lock()
+	if c.standby == standby {
+		c.mu.Unlock()
+		return
+	}
+	c.standby = standby
+	c.failures = 0
+	c.healthySince = time.Time{}
+	c.mu.Unlock()
+
+	if c.opt.OnSwitch != nil {
+		c.opt.OnSwitch(standby)
+	}
+}
+
+// Do creates a Cmd from the args and processes the cmd.
+func (c *ActivePassiveClient) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(ctx, args...)
+	_ = c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process processes cmd with the active deployment.
+func (c *ActivePassiveClient) Process(ctx context.Context, cmd Cmder) error {
+	c.mu.Lock()
+	active, standby := c.active(), c.standby
+	c.mu.Unlock()
+
+	err := active.Process(ctx, cmd)
+	if !standby {
+		c.primaryResult(err)
+	}
+	return err
+}
+
+func (c *ActivePassiveClient) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.Pipeline().Pipelined(ctx, fn)
+}
+
+func (c *ActivePassiveClient) Pipeline() Pipeliner {
+	return c.pipeline(UniversalClient.Pipeline)
+}
+
+func (c *ActivePassiveClient) TxPipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.TxPipeline().Pipelined(ctx, fn)
+}
+
+func (c *ActivePassiveClient) TxPipeline() Pipeliner {
+	return c.pipeline(UniversalClient.TxPipeline)
+}
+
+func (c *ActivePassiveClient) pipeline(newPipeline func(UniversalClient) Pipeliner) Pipeliner {
+	pipe := Pipeline{
+		exec: func(ctx context.Context, cmds []Cmder) error {
+			c.mu.Lock()
+			active, standby := c.active(), c.standby
+			c.mu.Unlock()
+
+			inner := newPipeline(active)
+			for _, cmd := range cmds {
+				_ = inner.Process(ctx, cmd)
+			}
+			_, err := inner.Exec(ctx)
+			if !standby {
+				c.primaryResult(err)
+			}
+			return err
+		},
+	}
+	pipe.init()
+	return &pipe
+}
+
+// Close stops the health checks and closes the primary and standby clients.
+func (c *ActivePassiveClient) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	c.wg.Wait()
+
+	err := c.opt.Primary.Close()
+	if err2 := c.opt.Standby.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+func (c *ActivePassiveClient) healthCheck() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.opt.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.closed:
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.opt.HealthCheckInterval)
+		err := c.opt.Primary.Ping(ctx).Err()
+		cancel()
+		c.primaryResult(err)
+	}
+}
+
+// primaryResult records the result of a health check or a command of the
+// primary, failing over or back when needed.
+func (c *ActivePassiveClient) primaryResult(err error) {
+	failed := isServerFailure(err)
+
+	c.mu.Lock()
+	var switchTo *bool
+	switch {
+	case failed:
+		c.healthySince = time.Time{}
+		c.failures++
+		if !c.standby && c.failures >= c.opt.FailoverThreshold {
+			standby := true
+			switchTo = &standby
+		}
+	default:
+		c.failures = 0
+		if c.healthySince.IsZero() {
+			c.healthySince = time.Now()
+		}
+		if c.standby && c.opt.Failback == FailbackAuto && time.Since(c.healthySince) >= c.opt.FailbackDelay {
+			standby := false
+			switchTo = &standby
+		}
+	}
+	c.mu.Unlock()
+
+	if switchTo != nil {
+		c.switchTo(*switchTo)
+	}
+}
+
+// isServerFailure reports whether err is a failure of the server rather
+// than of the command, such as a network error or a timeout.
+func isServerFailure(err error) bool {
+	if err == nil || err == Nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if isRedisError(err) {
+		// the server is up, but it may not serve the commands
+		return isLoadingError(err) || HasErrorPrefix(err, "MASTERDOWN")
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// downConn fails once its deployment is down.
+type downConn struct {
+	net.Conn
+	down *int32
+}
+
+func (c *downConn) Write(b []byte) (int, error) {
+	if atomic.LoadInt32(c.down) == 1 {
+		return 0, errors.New("deployment is down")
+	}
+	return c.Conn.Write(b)
+}
+
+var _ = Describe("ActivePassiveClient", func() {
+	var client *redis.ActivePassiveClient
+	var primaryDown int32
+
+	var mu sync.Mutex
+	var switches []bool
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&primaryDown, 0)
+		switches = nil
+
+		opt := redisOptions()
+		opt.MaxRetries = -1
+		opt.Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if atomic.LoadInt32(&primaryDown) == 1 {
+				return nil, errors.New("deployment is down")
+			}
+			cn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &downConn{Conn: cn, down: &primaryDown}, nil
+		}
+		primary := redis.NewClient(opt)
+
+		opt = redisOptions()
+		opt.DB = 14
+		standby := redis.NewClient(opt)
+
+		Expect(primary.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		Expect(standby.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		Expect(primary.Set(ctx, "deployment", "primary", 0).Err()).NotTo(HaveOccurred())
+		Expect(standby.Set(ctx, "deployment", "standby", 0).Err()).NotTo(HaveOccurred())
+
+		client = redis.NewActivePassiveClient(&redis.ActivePassiveOptions{
+			Primary:             primary,
+			Standby:             standby,
+			HealthCheckInterval: 10 * time.Millisecond,
+			FailbackDelay:       50 * time.Millisecond,
+			OnSwitch: func(standby bool) {
+				mu.Lock()
+				switches = append(switches, standby)
+				mu.Unlock()
+			},
+		})
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should fail over to the standby and back", func() {
+		Expect(client.Get(ctx, "deployment").Val()).To(Equal("primary"))
+
+		atomic.StoreInt32(&primaryDown, 1)
+		Eventually(client.IsFailedOver).Should(BeTrue())
+		Expect(client.Get(ctx, "deployment").Val()).To(Equal("standby"))
+
+		cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Get(ctx, "deployment")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds[0].(*redis.StringCmd).Val()).To(Equal("standby"))
+
+		atomic.StoreInt32(&primaryDown, 0)
+		Eventually(client.IsFailedOver).Should(BeFalse())
+		Expect(client.Get(ctx, "deployment").Val()).To(Equal("primary"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(switches).To(Equal([]bool{true, false}))
+	})
+
+	It("should switch with Failover and Failback", func() {
+		client.Failover()
+		Expect(client.Get(ctx, "deployment").Val()).To(Equal("standby"))
+		client.Failback()
+		Expect(client.Get(ctx, "deployment").Val()).To(Equal("primary"))
+	})
+})
@@ -0,0 +1,175 @@
+// Package aws authenticates the clients of Amazon ElastiCache and MemoryDB
+// with IAM: the password of the IAM-enabled user is a short-lived token signed
+// with the AWS credentials of the application.
+//
+//	provider, err := aws.NewProvider(&aws.Options{
+//		UserID:    "app",
+//		CacheName: "my-cache",
+//		Region:    "us-east-1",
+//	})
+//	defer provider.Close()
+//
+//	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+//		Addrs:                        []string{"clustercfg.my-cache.xxxxxx.use1.cache.amazonaws.com:6379"},
+//		StreamingCredentialsProvider: provider,
+//		TLSConfig:                    &tls.Config{},
+//	})
+//
+// The tokens are valid 15 minutes. The provider renews them periodically and
+// the clients authenticate their connections again with the new tokens, which
+// also extends the 12 hours after which the servers close the IAM-authenticated
+// connections.
+//
+// The tokens are signed with Signature Version 4 by the package itself, to
+// spare the dependency on the AWS SDK: set Options.Credentials to retrieve the
+// credentials of the SDK, e.g. of an IAM role.
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/auth"
+)
+
+// tokenExpiry is the validity of the tokens accepted by ElastiCache and
+// MemoryDB.
+const tokenExpiry = 15 * time.Minute
+
+// Credentials are the AWS credentials signing the tokens.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the token of temporary credentials, e.g. of an IAM role.
+	SessionToken string
+}
+
+// CredentialsFunc retrieves the AWS credentials, e.g. with the Retrieve
+// method of the credentials provider of the AWS SDK.
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// EnvCredentials retrieves the credentials of the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+func EnvCredentials(ctx context.Context) (Credentials, error) {
+	creds := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return Credentials{}, errors.New("aws: AWS_ACCESS_KEY_ID or AWS_SECRET_ACCESS_KEY is not set")
+	}
+	return creds, nil
+}
+
+type Options struct {
+	// UserID is the ID of the IAM-enabled user, which is also its username.
+	UserID string
+	// CacheName is the name of the ElastiCache replication group or
+	// serverless cache, or of the MemoryDB cluster.
+	CacheName string
+	// Region is the AWS region of the cache. Default is the AWS_REGION
+	// environment variable.
+	Region string
+	// Service is the service of the cache: "elasticache" or "memorydb".
+	// Default is "elasticache".
+	Service string
+	// Serverless is set for the ElastiCache serverless caches.
+	Serverless bool
+
+	// Credentials retrieve the AWS credentials signing the tokens. Default is
+	// EnvCredentials.
+	Credentials CredentialsFunc
+	// RefreshInterval is the interval between the renewals of the token,
+	// shorter than its validity of 15 minutes. Default is 10 minutes.
+	RefreshInterval time.Duration
+	// OnError is called with the errors of the periodic renewals, after which
+	// the current token is kept until the next renewal. Default is to ignore
+	// them.
+	OnError func(err error)
+}
+
+func (opt *Options) init() error {
+	if opt.UserID == "" {
+		return errors.New("aws: UserID is required")
+	}
+	if opt.CacheName == "" {
+		return errors.New("aws: CacheName is required")
+	}
+	if opt.Region == "" {
+		opt.Region = os.Getenv("AWS_REGION")
+		if opt.Region == "" {
+			return errors.New("aws: Region is required")
+		}
+	}
+	if opt.Service == "" {
+		opt.Service = "elasticache"
+	}
+	if opt.Credentials == nil {
+		opt.Credentials = EnvCredentials
+	}
+	if opt.RefreshInterval <= 0 || opt.RefreshInterval >= tokenExpiry {
+		opt.RefreshInterval = 10 * time.Minute
+	}
+	return nil
+}
+
+// Provider is a redis.StreamingCredentialsProvider of the IAM authentication
+// tokens of a user.
+type Provider struct {
+	opt     Options
+	renewer *auth.Renewer
+}
+
+// NewProvider returns a provider of the tokens of opt, renewing them until it
+// is closed. The first token is generated by the first call of Credentials.
+func NewProvider(opt *Options) (*Provider, error) {
+	p := new(Provider)
+	if opt != nil {
+		p.opt = *opt
+	}
+	if err := p.opt.init(); err != nil {
+		return nil, err
+	}
+	p.renewer = auth.NewRenewer(p.fetch, p.opt.OnError)
+	return p, nil
+}
+
+// Credentials returns the user ID and a valid token.
+func (p *Provider) Credentials(ctx context.Context) (username, password string, err error) {
+	return p.renewer.Credentials(ctx)
+}
+
+// Subscribe calls fn each time the token is renewed, until unsubscribe is
+// called.
+func (p *Provider) Subscribe(fn func()) (unsubscribe func()) {
+	return p.renewer.Subscribe(fn)
+}
+
+// Token generates a new token.
+func (p *Provider) Token(ctx context.Context) (string, error) {
+	creds, err := p.opt.Credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return presignConnect(&p.opt, creds, time.Now()), nil
+}
+
+// Close stops the renewals of the token.
+func (p *Provider) Close() error {
+	return p.renewer.Close()
+}
+
+func (p *Provider) fetch(ctx context.Context) (auth.Token, error) {
+	token, err := p.Token(ctx)
+	if err != nil {
+		return auth.Token{}, err
+	}
+	return auth.Token{
+		Username: p.opt.UserID,
+		Password: token,
+		RenewAt:  time.Now().Add(p.opt.RefreshInterval),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of the empty payload of the connect
+// requests.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// presignConnect returns the token of opt: the connect request of the user to
+// the cache presigned at t, without its scheme.
+func presignConnect(opt *Options, creds Credentials, t time.Time) string {
+	query := map[string]string{
+		"Action": "connect",
+		"User":   opt.UserID,
+	}
+	if opt.Serverless {
+		query["ResourceType"] = "ServerlessCache"
+	}
+	return presign(opt.CacheName, "/", query, emptyPayloadHash, opt.Service, opt.Region, creds, t, tokenExpiry)
+}
+
+// presign signs the GET request of host and path with Signature Version 4 in
+// its query, and returns the request without its scheme.
+func presign(
+	host, path string, query map[string]string, payloadHash, service, region string,
+	creds Credentials, t time.Time, expires time.Duration,
+) string {
+	t = t.UTC()
+	date := t.Format("20060102")
+	amzDate := t.Format("20060102T150405Z")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+
+	params := make(map[string]string, len(query)+6)
+	for k, v := range query {
+		params[k] = v
+	}
+	params["X-Amz-Algorithm"] = "AWS4-HMAC-SHA256"
+	params["X-Amz-Credential"] = creds.AccessKeyID + "/" + scope
+	params["X-Amz-Date"] = amzDate
+	params["X-Amz-Expires"] = strconv.Itoa(int(expires / time.Second))
+	params["X-Amz-SignedHeaders"] = "host"
+	if creds.SessionToken != "" {
+		params["X-Amz-Security-Token"] = creds.SessionToken
+	}
+	canonicalQuery := canonicalQueryString(params)
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		path,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return host + path + "?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+func canonicalQueryString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(uriEncode(k))
+		b.WriteByte('=')
+		b.WriteString(uriEncode(params[k]))
+	}
+	return b.String()
+}
+
+// uriEncode percent-encodes s except its unreserved characters, as required
+// by Signature Version 4.
+func uriEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return h.Sum(nil)
+}
@@ -0,0 +1,163 @@
+// Package azure authenticates the clients of Azure Cache for Redis and Azure
+// Managed Redis with Microsoft Entra ID: the username is the object ID of the
+// identity of the application, and the password its access token.
+//
+// The tokens are acquired by Options.Token, e.g. with a credential of the
+// azidentity package, which the package does not depend on:
+//
+//	cred, err := azidentity.NewDefaultAzureCredential(nil)
+//	provider, err := azure.NewProvider(&azure.Options{
+//		Token: func(ctx context.Context, scopes []string) (string, time.Time, error) {
+//			tok, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: scopes})
+//			return tok.Token, tok.ExpiresOn, err
+//		},
+//	})
+//	defer provider.Close()
+//
+//	rdb := redis.NewClient(&redis.Options{
+//		Addr:                         "my-cache.redis.cache.windows.net:6380",
+//		StreamingCredentialsProvider: provider,
+//		TLSConfig:                    &tls.Config{},
+//	})
+//
+// The provider renews the tokens before they expire, and the clients
+// authenticate their connections again with the new tokens.
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/auth"
+)
+
+// DefaultScope is the scope of the Entra ID tokens of Azure Cache for Redis.
+const DefaultScope = "https://redis.azure.com/.default"
+
+// TokenFunc acquires an Entra ID access token for scopes, and returns it with
+// its expiration time.
+type TokenFunc func(ctx context.Context, scopes []string) (token string, expiresOn time.Time, err error)
+
+type Options struct {
+	// Token acquires the access tokens. Required.
+	Token TokenFunc
+	// Scopes of the tokens. Default is DefaultScope.
+	Scopes []string
+	// Username is the username of the identity. Default is its object ID, the
+	// "oid" claim of its tokens.
+	Username string
+
+	// ExpirationRefreshRatio is the fraction of the lifetime of a token after
+	// which it is renewed, from 0 to 1. Default is 0.7.
+	ExpirationRefreshRatio float64
+	// LowerRefreshBound is the minimum time before the expiration of a token
+	// at which it is renewed. Default is 2 minutes.
+	LowerRefreshBound time.Duration
+	// OnError is called with the errors of the renewals, after which the
+	// renewal is retried. Default is to ignore them.
+	OnError func(err error)
+}
+
+func (opt *Options) init() error {
+	if opt.Token == nil {
+		return errors.New("azure: Token is required")
+	}
+	if len(opt.Scopes) == 0 {
+		opt.Scopes = []string{DefaultScope}
+	}
+	if opt.ExpirationRefreshRatio <= 0 || opt.ExpirationRefreshRatio > 1 {
+		opt.ExpirationRefreshRatio = 0.7
+	}
+	if opt.LowerRefreshBound <= 0 {
+		opt.LowerRefreshBound = 2 * time.Minute
+	}
+	return nil
+}
+
+// Provider is a redis.StreamingCredentialsProvider of the Entra ID tokens of
+// an identity.
+type Provider struct {
+	opt     Options
+	renewer *auth.Renewer
+}
+
+// NewProvider returns a provider of the tokens of opt, renewing them until it
+// is closed. The first token is acquired by the first call of Credentials.
+func NewProvider(opt *Options) (*Provider, error) {
+	p := new(Provider)
+	if opt != nil {
+		p.opt = *opt
+	}
+	if err := p.opt.init(); err != nil {
+		return nil, err
+	}
+	p.renewer = auth.NewRenewer(p.fetch, p.opt.OnError)
+	return p, nil
+}
+
+// Credentials returns the username and a valid token.
+func (p *Provider) Credentials(ctx context.Context) (username, password string, err error) {
+	return p.renewer.Credentials(ctx)
+}
+
+// Subscribe calls fn each time the token is renewed, until unsubscribe is
+// called.
+func (p *Provider) Subscribe(fn func()) (unsubscribe func()) {
+	return p.renewer.Subscribe(fn)
+}
+
+// Close stops the renewals of the token.
+func (p *Provider) Close() error {
+	return p.renewer.Close()
+}
+
+func (p *Provider) fetch(ctx context.Context) (auth.Token, error) {
+	token, expiresOn, err := p.opt.Token(ctx, p.opt.Scopes)
+	if err != nil {
+		return auth.Token{}, err
+	}
+	username := p.opt.Username
+	if username == "" {
+		if username, err = objectID(token); err != nil {
+			return auth.Token{}, err
+		}
+	}
+
+	now := time.Now()
+	renewAt := now.Add(time.Duration(float64(expiresOn.Sub(now)) * p.opt.ExpirationRefreshRatio))
+	if latest := expiresOn.Add(-p.opt.LowerRefreshBound); renewAt.After(latest) {
+		renewAt = latest
+	}
+	return auth.Token{
+		Username: username,
+		Password: token,
+		RenewAt:  renewAt,
+	}, nil
+}
+
+// objectID returns the "oid" claim of the JWT token, without verifying it.
+func objectID(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("azure: the token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("azure: invalid token payload: %w", err)
+	}
+	var claims struct {
+		OID string `json:"oid"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("azure: invalid token payload: %w", err)
+	}
+	if claims.OID == "" {
+		return "", errors.New("azure: the token has no oid claim, set Options.Username")
+	}
+	return claims.OID, nil
+}
@@ -0,0 +1,97 @@
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testToken(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		enc.EncodeToString([]byte(payload)) + "." +
+		enc.EncodeToString([]byte("signature"))
+}
+
+func TestObjectID(t *testing.T) {
+	oid, err := objectID(testToken(`{"oid":"0b1c2d3e","tid":"tenant"}`))
+	if err != nil || oid != "0b1c2d3e" {
+		t.Fatalf("got %q, %v", oid, err)
+	}
+	if _, err := objectID(testToken(`{"tid":"tenant"}`)); err == nil {
+		t.Fatal("got nil error without oid")
+	}
+	if _, err := objectID("opaque"); err == nil {
+		t.Fatal("got nil error with an opaque token")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	var acquired int32
+	p, err := NewProvider(&Options{
+		Token: func(ctx context.Context, scopes []string) (string, time.Time, error) {
+			if len(scopes) != 1 || scopes[0] != DefaultScope {
+				t.Errorf("got scopes %q", scopes)
+			}
+			atomic.AddInt32(&acquired, 1)
+			return testToken(`{"oid":"object-id"}`), time.Now().Add(100 * time.Millisecond), nil
+		},
+		LowerRefreshBound: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	renewed := make(chan struct{}, 1)
+	defer p.Subscribe(func() {
+		select {
+		case renewed <- struct{}{}:
+		default:
+		}
+	})()
+
+	username, password, err := p.Credentials(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "object-id" || password == "" {
+		t.Fatalf("got %q, %q", username, password)
+	}
+
+	select {
+	case <-renewed:
+	case <-time.After(time.Second):
+		t.Fatal("the token was not renewed")
+	}
+	if n := atomic.LoadInt32(&acquired); n < 2 {
+		t.Fatalf("got %d tokens, want at least 2", n)
+	}
+}
+
+func TestProviderUsername(t *testing.T) {
+	p, err := NewProvider(&Options{
+		Token: func(ctx context.Context, scopes []string) (string, time.Time, error) {
+			return "opaque", time.Now().Add(time.Hour), nil
+		},
+		Username: "app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	username, password, err := p.Credentials(context.Background())
+	if err != nil || username != "app" || password != "opaque" {
+		t.Fatalf("got %q, %q, %v", username, password, err)
+	}
+}
+
+func TestProviderNilOptions(t *testing.T) {
+	if _, err := NewProvider(nil); err == nil {
+		t.Fatal("got nil error without Token")
+	}
+}
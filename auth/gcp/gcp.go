@@ -0,0 +1,164 @@
+// Package gcp authenticates the clients of Memorystore for Redis Cluster and
+// Memorystore for Valkey with IAM: the password is an OAuth 2.0 access token
+// of the service account of the application.
+//
+//	provider, err := gcp.NewProvider(nil)
+//	defer provider.Close()
+//
+//	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+//		Addrs:                        []string{"10.0.0.3:6379"},
+//		StreamingCredentialsProvider: provider,
+//		TLSConfig:                    &tls.Config{RootCAs: memorystoreCAs},
+//	})
+//
+// By default the tokens are fetched from the metadata server of the compute
+// instance, e.g. of Compute Engine, GKE or Cloud Run. Set Options.Token to
+// fetch them otherwise, e.g. with a TokenSource of the golang.org/x/oauth2
+// package, which the package does not depend on:
+//
+//	ts, err := google.DefaultTokenSource(ctx, gcp.Scope)
+//	provider, err := gcp.NewProvider(&gcp.Options{
+//		Token: func(ctx context.Context) (string, time.Time, error) {
+//			tok, err := ts.Token()
+//			if err != nil {
+//				return "", time.Time{}, err
+//			}
+//			return tok.AccessToken, tok.Expiry, nil
+//		},
+//	})
+//
+// The provider renews the tokens before they expire, and the clients
+// authenticate their connections again with the new tokens.
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/auth"
+)
+
+// Scope is the OAuth 2.0 scope of the access tokens of Memorystore.
+const Scope = "https://www.googleapis.com/auth/cloud-platform"
+
+// TokenFunc fetches an access token, and returns it with its expiration time.
+type TokenFunc func(ctx context.Context) (token string, expiry time.Time, err error)
+
+type Options struct {
+	// Token fetches the access tokens. Default is MetadataToken.
+	Token TokenFunc
+	// RefreshBefore is the time before the expiration of a token at which it
+	// is renewed. Default is 5 minutes.
+	RefreshBefore time.Duration
+	// OnError is called with the errors of the renewals, after which the
+	// renewal is retried. Default is to ignore them.
+	OnError func(err error)
+}
+
+func (opt *Options) init() {
+	if opt.Token == nil {
+		opt.Token = MetadataToken
+	}
+	if opt.RefreshBefore <= 0 {
+		opt.RefreshBefore = 5 * time.Minute
+	}
+}
+
+// MetadataToken fetches an access token of the default service account of the
+// compute instance from its metadata server, at the host of the
+// GCE_METADATA_HOST environment variable, or metadata.google.internal.
+func MetadataToken(ctx context.Context) (string, time.Time, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	url := "http://" + host + "/computeMetadata/v1/instance/service-accounts/default/token"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("gcp: metadata server replied %s: %s", resp.Status, body)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return "", time.Time{}, fmt.Errorf("gcp: invalid token of the metadata server: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", time.Time{}, errors.New("gcp: the metadata server replied no token")
+	}
+	return tok.AccessToken, time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second), nil
+}
+
+// Provider is a redis.StreamingCredentialsProvider of the access tokens of a
+// service account.
+type Provider struct {
+	opt     Options
+	renewer *auth.Renewer
+}
+
+// NewProvider returns a provider of the tokens of opt, renewing them until it
+// is closed. The first token is fetched by the first call of Credentials.
+func NewProvider(opt *Options) (*Provider, error) {
+	p := new(Provider)
+	if opt != nil {
+		p.opt = *opt
+	}
+	p.opt.init()
+	p.renewer = auth.NewRenewer(p.fetch, p.opt.OnError)
+	return p, nil
+}
+
+// Credentials returns a valid token as the password, without username.
+func (p *Provider) Credentials(ctx context.Context) (username, password string, err error) {
+	return p.renewer.Credentials(ctx)
+}
+
+// Subscribe calls fn each time the token is renewed, until unsubscribe is
+// called.
+func (p *Provider) Subscribe(fn func()) (unsubscribe func()) {
+	return p.renewer.Subscribe(fn)
+}
+
+// Close stops the renewals of the token.
+func (p *Provider) Close() error {
+	return p.renewer.Close()
+}
+
+func (p *Provider) fetch(ctx context.Context) (auth.Token, error) {
+	token, expiry, err := p.opt.Token(ctx)
+	if err != nil {
+		return auth.Token{}, err
+	}
+
+	renewAt := expiry.Add(-p.opt.RefreshBefore)
+	// renew the short-lived tokens halfway through their lifetime
+	if half := time.Now().Add(time.Until(expiry) / 2); renewAt.Before(half) {
+		renewAt = half
+	}
+	return auth.Token{
+		Password: token,
+		RenewAt:  renewAt,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package gcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMetadataToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/computeMetadata/v1/instance/service-accounts/default/token" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`))
+	}))
+	defer srv.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	token, expiry, err := MetadataToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "ya29.token" {
+		t.Fatalf("got %q", token)
+	}
+	if d := time.Until(expiry); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("got expiry in %s", d)
+	}
+}
+
+func TestMetadataTokenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no service account", http.StatusNotFound)
+	}))
+	defer srv.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+
+	if _, _, err := MetadataToken(context.Background()); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	var fetched int32
+	p, err := NewProvider(&Options{
+		Token: func(ctx context.Context) (string, time.Time, error) {
+			atomic.AddInt32(&fetched, 1)
+			return "token", time.Now().Add(100 * time.Millisecond), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	renewed := make(chan struct{}, 1)
+	defer p.Subscribe(func() {
+		select {
+		case renewed <- struct{}{}:
+		default:
+		}
+	})()
+
+	username, password, err := p.Credentials(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "" || password != "token" {
+		t.Fatalf("got %q, %q", username, password)
+	}
+
+	select {
+	case <-renewed:
+	case <-time.After(time.Second):
+		t.Fatal("the token was not renewed")
+	}
+	if n := atomic.LoadInt32(&fetched); n < 2 {
+		t.Fatalf("got %d tokens, want at least 2", n)
+	}
+}
@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// autoPipeliner batches the commands processed concurrently by a client into
+// pipelines, see Options.AutoPipeline.
+//
+// The commands are queued, and pipelined by up to PoolSize flushers, each
+// writing and reading the commands queued while the previous pipeline was
+// processed. So the commands are pipelined without delay when the client is
+// idle, and in larger pipelines as the load increases.
+type autoPipeliner struct {
+	client      *baseClient
+	maxSize     int
+	maxFlushers int
+
+	mu       sync.Mutex
+	queue    []*autoPipelineCmd
+	flushers int
+}
+
+type autoPipelineCmd struct {
+	ctx  context.Context
+	cmd  Cmder
+	done chan struct{}
+}
+
+func newAutoPipeliner(client *baseClient) *autoPipeliner {
+	p := &autoPipeliner{
+		client:      client,
+		maxSize:     client.opt.AutoPipelineMaxSize,
+		maxFlushers: client.opt.PoolSize,
+	}
+	if p.maxSize <= 0 {
+		p.maxSize = 100
+	}
+	if p.maxFlushers <= 0 {
+		p.maxFlushers = 1
+	}
+	return p
+}
+
+// process queues cmd and waits for its reply. cmd is dequeued once ctx is
+// done before it is written. Once written, cmd waits for the pipeline, which
+// can't be canceled as it is shared with the commands of the other
+// goroutines, but which is processed with the latest deadline of its
+// commands.
+func (p *autoPipeliner) process(ctx context.Context, cmd Cmder) error {
+	ac := &autoPipelineCmd{
+		ctx:  ctx,
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	p.queue = append(p.queue, ac)
+	if p.flushers < p.maxFlushers {
+		p.flushers++
+		go p.flush()
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-ac.done:
+	case <-ctx.Done():
+		if !p.dequeue(ac) {
+			// cmd is being written and read by a flusher
+			<-ac.done
+			return cmd.Err()
+		}
+		cmd.SetErr(ctx.Err())
+	}
+	return cmd.Err()
+}
+
+// dequeue removes ac from the queue, reporting whether it was still queued.
+func (p *autoPipeliner) dequeue(ac *autoPipelineCmd) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, queued := range p.queue {
+		if queued == ac {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// flush pipelines the queued commands until the queue is empty.
+func (p *autoPipeliner) flush() {
+	for {
+		p.mu.Lock()
+		n := len(p.queue)
+		if n == 0 {
+			p.flushers--
+			p.mu.Unlock()
+			return
+		}
+		if n > p.maxSize {
+			n = p.maxSize
+		}
+		batch := p.queue[:n:n]
+		p.queue = p.queue[n:]
+		if len(p.queue) == 0 {
+			p.queue = nil
+		}
+		p.mu.Unlock()
+
+		p.exec(batch)
+	}
+}
+
+func (p *autoPipeliner) exec(batch []*autoPipelineCmd) {
+	cmds := make([]Cmder, 0, len(batch))
+	var deadline time.Time
+	hasDeadline := true
+	for _, ac := range batch {
+		if err := ac.ctx.Err(); err != nil {
+			ac.cmd.SetErr(err)
+			continue
+		}
+		cmds = append(cmds, ac.cmd)
+
+		if d, ok := ac.ctx.Deadline(); !ok {
+			hasDeadline = false
+		} else if d.After(deadline) {
+			deadline = d
+		}
+	}
+
+	if len(cmds) > 0 {
+		ctx := context.Background()
+		if hasDeadline {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		err := p.client.pipelineWithRetries(ctx, cmds, p.client.pipelineProcessCmds)
+		if err != nil && !isRedisError(err) {
+			// e.g. the dial errors, which are not set by the pipeline
+			setCmdsErr(cmds, err)
+		}
+	}
+
+	for _, ac := range batch {
+		close(ac.done)
+	}
+}
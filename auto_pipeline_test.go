@@ -0,0 +1,184 @@
+package redis_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// slowWriteConn counts the writes, slowed down for the commands to queue up.
+type slowWriteConn struct {
+	net.Conn
+	writes *int32
+}
+
+func (cn slowWriteConn) Write(b []byte) (int, error) {
+	atomic.AddInt32(cn.writes, 1)
+	time.Sleep(5 * time.Millisecond)
+	return cn.Conn.Write(b)
+}
+
+func TestAutoPipeline(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var writes int32
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         srv.Addr(),
+		PoolSize:     1,
+		AutoPipeline: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			cn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return slowWriteConn{Conn: cn, writes: &writes}, nil
+		},
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&writes, 0)
+
+	const n = 100
+	var wg sync.WaitGroup
+	seen := make([]int32, n+1)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := rdb.Incr(ctx, "counter").Result()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			atomic.AddInt32(&seen[v], 1)
+		}()
+	}
+	wg.Wait()
+
+	for v := 1; v <= n; v++ {
+		if seen[v] != 1 {
+			t.Fatalf("got INCR reply %d %d times", v, seen[v])
+		}
+	}
+	if w := atomic.LoadInt32(&writes); w >= n/2 {
+		t.Fatalf("got %d writes for %d commands", w, n)
+	}
+
+	// the errors are the errors of their commands
+	if err := rdb.Set(ctx, "key", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Incr(ctx, "key").Err(); err == nil {
+		t.Fatal("INCR of a string succeeded")
+	}
+	if v, err := rdb.Get(ctx, "key").Result(); err != nil || v != "v" {
+		t.Fatalf("got %q, %v", v, err)
+	}
+	if err := rdb.Get(ctx, "missing").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+}
+
+func TestAutoPipelineCanceled(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), AutoPipeline: true})
+	defer rdb.Close()
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := rdb.Set(canceled, "key", "v", 0).Err(); err != context.Canceled {
+		t.Fatalf("got %v, wanted context.Canceled", err)
+	}
+	if err := rdb.Get(ctx, "key").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+}
+
+func TestAutoPipelineDialError(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         "127.0.0.1:1",
+		AutoPipeline: true,
+		MaxRetries:   -1,
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err == nil {
+		t.Fatal("PING succeeded without a server")
+	}
+}
+
+// blockingWriteConn blocks the writes while block is not closed.
+type blockingWriteConn struct {
+	net.Conn
+	block *atomic.Value // chan struct{}
+}
+
+func (cn blockingWriteConn) Write(b []byte) (int, error) {
+	if block, _ := cn.block.Load().(chan struct{}); block != nil {
+		<-block
+	}
+	return cn.Conn.Write(b)
+}
+
+func TestAutoPipelineDeadline(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	block := new(atomic.Value)
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         srv.Addr(),
+		PoolSize:     1,
+		AutoPipeline: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			cn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return blockingWriteConn{Conn: cn, block: block}, nil
+		},
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	unblock := make(chan struct{})
+	block.Store(unblock)
+
+	// the pipeline of the first command is blocked
+	written := make(chan error)
+	go func() {
+		written <- rdb.Set(ctx, "a", "v", 0).Err()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := rdb.Set(deadlineCtx, "b", "v", 0).Err(); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, wanted context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("returned after %s", elapsed)
+	}
+
+	block.Store(chan struct{}(nil))
+	close(unblock)
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Get(ctx, "b").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+}
@@ -314,3 +314,79 @@ func staticGoroutine(b *testing.B, stub ClientStubFunc, concurrency int) {
 	}
 	close(c)
 }
+
+func BenchmarkHotCommands(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+	}
+
+	b.Run("Get", func(b *testing.B) {
+		rdb := NewClientStub([]byte("$5\r\nhello\r\n"))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.Get(ctx, keys[i%len(keys)])
+		}
+	})
+	b.Run("GetRelease", func(b *testing.B) {
+		rdb := NewClientStub([]byte("$5\r\nhello\r\n"))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.Get(ctx, keys[i%len(keys)]).Release()
+		}
+	})
+	b.Run("GetInto", func(b *testing.B) {
+		rdb := NewClientStub([]byte("$5\r\nhello\r\n"))
+		fn := func(b []byte) error { return nil }
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.GetInto(ctx, keys[i%len(keys)], fn)
+		}
+	})
+	b.Run("MGet", func(b *testing.B) {
+		rdb := NewClientStub([]byte("*4\r\n$5\r\nhello\r\n$-1\r\n$5\r\nhello\r\n$5\r\nhello\r\n"))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.MGet(ctx, keys[:4]...)
+		}
+	})
+	b.Run("MGetBytes", func(b *testing.B) {
+		rdb := NewClientStub([]byte("*4\r\n$5\r\nhello\r\n$-1\r\n$5\r\nhello\r\n$5\r\nhello\r\n"))
+		dst := make([][]byte, 0, 4)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			dst = rdb.MGetBytes(ctx, dst, keys[:4]...).Val()
+		}
+	})
+	b.Run("Set", func(b *testing.B) {
+		rdb := NewClientStub([]byte("+OK\r\n"))
+		var value interface{} = "value"
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.Set(ctx, keys[i%len(keys)], value, time.Hour)
+		}
+	})
+	b.Run("HSet", func(b *testing.B) {
+		rdb := NewClientStub([]byte(":1\r\n"))
+		var field, value interface{} = "field", "value"
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.HSet(ctx, keys[i%len(keys)], field, value)
+		}
+	})
+	b.Run("Expire", func(b *testing.B) {
+		rdb := NewClientStub([]byte(":1\r\n"))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			rdb.Expire(ctx, keys[i%len(keys)], time.Hour)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// broadcastPingInterval is the duration the connection of a
+// BroadcastTracking waits for an invalidation before it sends a PING to check
+// the server, which must answer within the same duration.
+var broadcastPingInterval = 30 * time.Second
+
+// BroadcastTracking receives the invalidations of the keys matching some
+// prefixes, with CLIENT TRACKING in broadcast mode on a dedicated connection,
+// for the applications maintaining their own caches. Unlike ClientCache, it
+// does not cache anything: the callbacks registered with OnInvalidate are
+// called with the keys which were modified.
+//
+// The connection is dialed again when it fails, with the retry backoffs of
+// the client. As the invalidations are lost while it is disconnected, the
+// callbacks are then called with no keys, meaning that every key may have
+// been modified, as when the server is flushed.
+type BroadcastTracking struct {
+	client   *baseClient
+	prefixes []string
+
+	handlersMu sync.Mutex
+	handlers   []*invalidateHandler
+
+	mu     sync.Mutex
+	cn     *pool.Conn
+	closed bool
+	done   chan struct{}
+}
+
+type invalidateHandler struct {
+	prefix string
+	fn     func(keys []string)
+}
+
+// TrackBroadcast starts tracking the keys matching prefixes in broadcast
+// mode, CLIENT TRACKING ON BCAST PREFIX ..., every key without prefixes. It
+// returns once the connection of the tracking is set up. It requires RESP3.
+//
+// The BroadcastTracking must be closed before the client.
+func (c *Client) TrackBroadcast(ctx context.Context, prefixes ...string) (*BroadcastTracking, error) {
+	if c.opt.Protocol == 2 {
+		return nil, errors.New("redis: broadcast tracking requires RESP3")
+	}
+	t := &BroadcastTracking{
+		client:   c.baseClient,
+		prefixes: prefixes,
+		done:     make(chan struct{}),
+	}
+	cn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.cn = cn
+	go t.run(cn)
+	return t, nil
+}
+
+// OnInvalidate registers fn to be called with the invalidated keys matching
+// prefix, every key when it is empty, and returns a func removing it. The
+// prefix need not be one of the tracked prefixes, e.g. "user:1" while tracking
+// "user:".
+//
+// fn is called with no keys when every key may have been modified, and must
+// not block, as the invalidations are read once it returns.
+func (t *BroadcastTracking) OnInvalidate(prefix string, fn func(keys []string)) (remove func()) {
+	h := &invalidateHandler{prefix: prefix, fn: fn}
+
+	t.handlersMu.Lock()
+	t.handlers = append(t.handlers, h)
+	t.handlersMu.Unlock()
+
+	return func() {
+		t.handlersMu.Lock()
+		defer t.handlersMu.Unlock()
+		for i, other := range t.handlers {
+			if other == h {
+				t.handlers = append(t.handlers[:i:i], t.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Close stops the tracking and closes its connection.
+func (t *BroadcastTracking) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	t.closed = true
+	cn := t.cn
+	t.cn = nil
+	t.mu.Unlock()
+
+	var err error
+	if cn != nil {
+		err = t.client.connPool.CloseConn(cn)
+	}
+	<-t.done
+	return err
+}
+
+// connect dials a connection and enables the broadcast tracking with it.
+func (t *BroadcastTracking) connect(ctx context.Context) (*pool.Conn, error) {
+	cn, err := t.client.newConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.client.cache != nil {
+		// the handshake enabled the tracking of the ClientCache, whose mode
+		// can't be switched
+		err = t.client.connCmd(ctx, cn, NewStatusCmd(ctx, "client", "tracking", "off"))
+	}
+	if err == nil {
+		args := make([]interface{}, 0, 4+2*len(t.prefixes))
+		args = append(args, "client", "tracking", "on", "bcast")
+		for _, prefix := range t.prefixes {
+			args = append(args, "prefix", prefix)
+		}
+		err = t.client.connCmd(ctx, cn, NewStatusCmd(ctx, args...))
+	}
+	if err != nil {
+		_ = t.client.connPool.CloseConn(cn)
+		return nil, err
+	}
+	return cn, nil
+}
+
+func (t *BroadcastTracking) run(cn *pool.Conn) {
+	defer close(t.done)
+
+	ctx := context.Background()
+	opt := t.client.opt
+	for {
+		err := t.receive(ctx, cn)
+		if !t.setConn(cn, nil) {
+			return
+		}
+		_ = t.client.connPool.CloseConn(cn)
+		internal.Log(ctx, opt.Logger, internal.LevelWarn,
+			"redis: broadcast tracking connection failed", "error", err)
+
+		for attempt := 0; ; attempt++ {
+			time.Sleep(internal.RetryBackoff(attempt, opt.MinRetryBackoff, opt.MaxRetryBackoff))
+			if t.isClosed() {
+				return
+			}
+			if cn, err = t.connect(ctx); err == nil {
+				break
+			}
+			internal.Log(ctx, opt.Logger, internal.LevelWarn,
+				"redis: broadcast tracking reconnection failed", "error", err)
+		}
+		if !t.setConn(nil, cn) {
+			_ = t.client.connPool.CloseConn(cn)
+			return
+		}
+
+		// the invalidations were lost while disconnected
+		t.invalidate(nil)
+	}
+}
+
+// setConn replaces the connection old by cn, and reports whether the tracking
+// is still open.
+func (t *BroadcastTracking) setConn(old, cn *pool.Conn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || t.cn != old {
+		return false
+	}
+	t.cn = cn
+	return true
+}
+
+func (t *BroadcastTracking) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// receive reads the invalidations of cn until it fails, sending a PING when
+// none was received for broadcastPingInterval.
+func (t *BroadcastTracking) receive(ctx context.Context, cn *pool.Conn) error {
+	pinged := false
+	for {
+		err := cn.WithReader(ctx, broadcastPingInterval, func(rd *proto.Reader) error {
+			return t.read(rd)
+		})
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() && !pinged {
+			pinged = true
+			if err := cn.WithWriter(ctx, t.client.opt.WriteTimeout, func(wr *proto.Writer) error {
+				return writeCmd(wr, NewStatusCmd(ctx, "ping"))
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		pinged = false
+	}
+}
+
+// read reads an invalidation, or the reply of a PING.
+func (t *BroadcastTracking) read(rd *proto.Reader) error {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return err
+	}
+	if typ != proto.RespPush {
+		_, err := rd.ReadReply()
+		return err
+	}
+
+	reply, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	payload, _ := reply.([]interface{})
+	if len(payload) < 2 || payload[0] != "invalidate" {
+		return nil
+	}
+	keys, ok := payload[1].([]interface{})
+	if !ok {
+		// FLUSHALL and FLUSHDB invalidate every key
+		t.invalidate(nil)
+		return nil
+	}
+	invalidated := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key, ok := key.(string); ok {
+			invalidated = append(invalidated, key)
+		}
+	}
+	t.invalidate(invalidated)
+	return nil
+}
+
+// invalidate calls the handlers with the keys matching their prefix, or with
+// no keys when keys is nil.
+func (t *BroadcastTracking) invalidate(keys []string) {
+	t.handlersMu.Lock()
+	handlers := t.handlers
+	t.handlersMu.Unlock()
+
+	for _, h := range handlers {
+		if keys == nil {
+			h.fn(nil)
+			continue
+		}
+		var matched []string
+		for _, key := range keys {
+			if strings.HasPrefix(key, h.prefix) {
+				matched = append(matched, key)
+			}
+		}
+		if len(matched) > 0 {
+			h.fn(matched)
+		}
+	}
+}
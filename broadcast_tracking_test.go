@@ -0,0 +1,92 @@
+package redis_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestBroadcastTracking(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	rdb := redis.NewClient(&redis.Options{
+		Addr: srv.Addr(),
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			cn, err := net.Dial(network, addr)
+			if err == nil {
+				mu.Lock()
+				conns = append(conns, cn)
+				mu.Unlock()
+			}
+			return cn, err
+		},
+	})
+	defer rdb.Close()
+
+	tracking, err := rdb.TrackBroadcast(ctx, "user:", "session:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracking.Close()
+
+	users := make(chan []string, 10)
+	user1 := make(chan []string, 10)
+	tracking.OnInvalidate("user:", func(keys []string) { users <- keys })
+	remove := tracking.OnInvalidate("user:1", func(keys []string) { user1 <- keys })
+
+	receive := func(ch chan []string, want []string) {
+		t.Helper()
+		select {
+		case keys := <-ch:
+			if !reflect.DeepEqual(keys, want) {
+				t.Fatalf("got %q, wanted %q", keys, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no invalidation of %q", want)
+		}
+	}
+
+	for _, key := range []string{"other", "session:1", "user:2", "user:1"} {
+		if err := rdb.Set(ctx, key, "value", 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receive(users, []string{"user:2"})
+	receive(users, []string{"user:1"})
+	receive(user1, []string{"user:1"})
+
+	remove()
+	if err := rdb.Del(ctx, "user:1").Err(); err != nil {
+		t.Fatal(err)
+	}
+	receive(users, []string{"user:1"})
+	if len(user1) != 0 {
+		t.Fatalf("removed callback called with %q", <-user1)
+	}
+
+	// the connection of the tracking is the first one
+	mu.Lock()
+	_ = conns[0].Close()
+	mu.Unlock()
+	receive(users, nil)
+	if err := rdb.Set(ctx, "user:3", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	receive(users, []string{"user:3"})
+
+	if err := tracking.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracking.Close(); err != redis.ErrClosed {
+		t.Fatalf("got %v, wanted ErrClosed", err)
+	}
+}
@@ -0,0 +1,310 @@
+// Package cache implements a read-through and write-through cache of Go
+// values stored in Redis.
+//
+//	c := cache.New(rdb, &cache.Options{StaleTTL: time.Minute})
+//
+//	var user User
+//	err := c.Once(ctx, "user:42", 10*time.Minute, &user, func(ctx context.Context) (interface{}, error) {
+//		return db.LoadUser(ctx, 42)
+//	})
+//
+// Concurrent calls of Once for the same key in the process share a single
+// call of the loader. Values are encoded with a Codec, JSON by default.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrCacheMiss is returned by Get when the key is not cached.
+	ErrCacheMiss = errors.New("cache: key is missing")
+	// ErrNotFound is returned by loaders when there is no value for the key.
+	// With Options.NegativeTTL, it is cached and returned by Once and Get.
+	ErrNotFound = errors.New("cache: not found")
+)
+
+type rediser interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Codec encodes the cached values.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// JSONCodec encodes the values with encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error)   { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(b []byte, v interface{}) error { return json.Unmarshal(b, v) }
+
+// GobCodec encodes the values with encoding/gob.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(b []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+type Options struct {
+	// Codec of the values. Default is JSONCodec.
+	Codec Codec
+	// StaleTTL keeps the values for StaleTTL after their TTL expired. Once
+	// returns such stale values immediately and reloads them in the background.
+	StaleTTL time.Duration
+	// NegativeTTL caches ErrNotFound returned by the loaders for NegativeTTL.
+	// Default is not to cache it.
+	NegativeTTL time.Duration
+	// OnRefreshError is called with the errors of the background reloads of
+	// stale values. Default is to ignore them.
+	OnRefreshError func(key string, err error)
+}
+
+// Cache caches values in Redis.
+type Cache struct {
+	client rediser
+	opt    Options
+
+	group group
+}
+
+// New returns a cache storing the values in client.
+func New(client rediser, opt *Options) *Cache {
+	c := &Cache{client: client}
+	if opt != nil {
+		c.opt = *opt
+	}
+	if c.opt.Codec == nil {
+		c.opt.Codec = JSONCodec{}
+	}
+	return c
+}
+
+// An item is stored as a header followed by the encoded value:
+// the format version, the flags and the time until which the item is fresh,
+// in milliseconds since the epoch, or 0 when it does not expire.
+const (
+	itemVersion    = 1
+	itemHeaderLen  = 10
+	itemFlagAbsent = 1
+)
+
+type item struct {
+	value      []byte
+	absent     bool
+	freshUntil time.Time
+}
+
+func (it *item) stale() bool {
+	return !it.freshUntil.IsZero() && time.Now().After(it.freshUntil)
+}
+
+func encodeItem(it *item) []byte {
+	b := make([]byte, itemHeaderLen, itemHeaderLen+len(it.value))
+	b[0] = itemVersion
+	if it.absent {
+		b[1] = itemFlagAbsent
+	}
+	if !it.freshUntil.IsZero() {
+		binary.BigEndian.PutUint64(b[2:], uint64(it.freshUntil.UnixMilli()))
+	}
+	return append(b, it.value...)
+}
+
+func decodeItem(b []byte) (*item, error) {
+	if len(b) < itemHeaderLen || b[0] != itemVersion {
+		return nil, errors.New("cache: invalid cached item")
+	}
+	it := &item{
+		value:  b[itemHeaderLen:],
+		absent: b[1]&itemFlagAbsent != 0,
+	}
+	if ms := int64(binary.BigEndian.Uint64(b[2:])); ms != 0 {
+		it.freshUntil = time.UnixMilli(ms)
+	}
+	return it, nil
+}
+
+// Get loads the cached value of key into dst. It returns ErrCacheMiss when the
+// key is not cached, ErrNotFound when its absence is cached. Stale values are
+// returned.
+func (c *Cache) Get(ctx context.Context, key string, dst interface{}) error {
+	it, err := c.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return c.decode(it, dst)
+}
+
+// Set caches value for key for ttl, without expiration when ttl is zero, or
+// removes key when ttl is negative.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if ttl < 0 {
+		return c.Delete(ctx, key)
+	}
+	b, err := c.opt.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.set(ctx, key, &item{value: b}, ttl)
+}
+
+// Delete removes key from the cache.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
+// Once loads the value of key into dst from the cache or, on misses, from the
+// loader, caching it for ttl. Concurrent calls in the process share the call of
+// the loader, whose errors are returned and not cached, except ErrNotFound
+// when Options.NegativeTTL is set.
+//
+// Errors reaching Redis are not fatal: the value is loaded.
+func (c *Cache) Once(
+	ctx context.Context, key string, ttl time.Duration, dst interface{},
+	loader func(ctx context.Context) (interface{}, error),
+) error {
+	it, err := c.get(ctx, key)
+	if err == nil {
+		if it.stale() {
+			c.refresh(key, ttl, loader)
+		}
+		return c.decode(it, dst)
+	}
+
+	v, err, _ := c.group.do(key, func() (interface{}, error) {
+		return c.load(ctx, key, ttl, loader)
+	})
+	if err != nil {
+		return err
+	}
+	return c.decode(v.(*item), dst)
+}
+
+// refresh reloads key in the background, unless it is already being reloaded.
+func (c *Cache) refresh(key string, ttl time.Duration, loader func(ctx context.Context) (interface{}, error)) {
+	go func() {
+		_, err, shared := c.group.do(key, func() (interface{}, error) {
+			return c.load(context.Background(), key, ttl, loader)
+		})
+		if err != nil && !shared && c.opt.OnRefreshError != nil {
+			c.opt.OnRefreshError(key, err)
+		}
+	}()
+}
+
+func (c *Cache) load(
+	ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (interface{}, error),
+) (*item, error) {
+	v, err := loader(ctx)
+	if err == ErrNotFound && c.opt.NegativeTTL > 0 {
+		it := &item{absent: true}
+		_ = c.set(ctx, key, it, c.opt.NegativeTTL)
+		return it, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := c.opt.Codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	it := &item{value: b}
+	_ = c.set(ctx, key, it, ttl)
+	return it, nil
+}
+
+func (c *Cache) get(ctx context.Context, key string) (*item, error) {
+	b, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeItem(b)
+}
+
+func (c *Cache) set(ctx context.Context, key string, it *item, ttl time.Duration) error {
+	if ttl <= 0 {
+		// cached without expiration
+		it.freshUntil = time.Time{}
+		return c.client.Set(ctx, key, encodeItem(it), 0).Err()
+	}
+	it.freshUntil = time.Now().Add(ttl)
+	expiration := ttl
+	if !it.absent {
+		expiration += c.opt.StaleTTL
+	}
+	return c.client.Set(ctx, key, encodeItem(it), expiration).Err()
+}
+
+func (c *Cache) decode(it *item, dst interface{}) error {
+	if it.absent {
+		return ErrNotFound
+	}
+	if err := c.opt.Codec.Unmarshal(it.value, dst); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
+
+// group deduplicates the concurrent calls of a function with the same key.
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// do calls fn, unless it is already being called for key, and returns its
+// result. It reports whether the result was shared with another caller.
+func (g *group) do(key string, fn func() (interface{}, error)) (interface{}, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if cl, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		cl.wg.Wait()
+		return cl.val, cl.err, true
+	}
+	cl := new(call)
+	cl.wg.Add(1)
+	g.calls[key] = cl
+	g.mu.Unlock()
+
+	cl.val, cl.err = fn()
+	cl.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return cl.val, cl.err, false
+}
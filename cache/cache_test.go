@@ -0,0 +1,170 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/cache"
+)
+
+// fakeRedis stores the values in memory, ignoring their expiration.
+type fakeRedis struct {
+	mu   sync.Mutex
+	vals map[string]string
+	ttls map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{vals: make(map[string]string), ttls: make(map[string]time.Duration)}
+}
+
+func (r *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmd := redis.NewStringCmd(ctx)
+	v, ok := r.vals[key]
+	if !ok {
+		cmd.SetErr(redis.Nil)
+	}
+	cmd.SetVal(v)
+	return cmd
+}
+
+func (r *fakeRedis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.vals[key] = string(value.([]byte))
+	r.ttls[key] = expiration
+	cmd := redis.NewStatusCmd(ctx)
+	cmd.SetVal("OK")
+	return cmd
+}
+
+func (r *fakeRedis) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmd := redis.NewIntCmd(ctx)
+	for _, key := range keys {
+		if _, ok := r.vals[key]; ok {
+			delete(r.vals, key)
+			cmd.SetVal(cmd.Val() + 1)
+		}
+	}
+	return cmd
+}
+
+type user struct {
+	Name string
+}
+
+func TestOnce(t *testing.T) {
+	ctx := context.Background()
+	rdb := newFakeRedis()
+	c := cache.New(rdb, nil)
+
+	var calls int32
+	loader := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &user{Name: "alice"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var u user
+			if err := c.Once(ctx, "user:1", time.Minute, &u, loader); err != nil {
+				t.Error(err)
+			}
+			if u.Name != "alice" {
+				t.Errorf("got %q, want alice", u.Name)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("loader was called %d times, want 1", calls)
+	}
+
+	var u user
+	if err := c.Get(ctx, "user:1", &u); err != nil || u.Name != "alice" {
+		t.Fatalf("got %+v, %v", u, err)
+	}
+	if err := c.Delete(ctx, "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, "user:1", &u); err != cache.ErrCacheMiss {
+		t.Fatalf("got %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestOnceStale(t *testing.T) {
+	ctx := context.Background()
+	rdb := newFakeRedis()
+	c := cache.New(rdb, &cache.Options{StaleTTL: time.Hour, Codec: cache.GobCodec{}})
+
+	if err := c.Set(ctx, "user:1", &user{Name: "old"}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := rdb.ttls["user:1"]; ttl != time.Hour+time.Millisecond {
+		t.Errorf("got expiration %v", ttl)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	refreshed := make(chan struct{})
+	var u user
+	err := c.Once(ctx, "user:1", time.Minute, &u, func(ctx context.Context) (interface{}, error) {
+		defer close(refreshed)
+		return &user{Name: "new"}, nil
+	})
+	if err != nil || u.Name != "old" {
+		t.Fatalf("got %+v, %v, want the stale value", u, err)
+	}
+
+	<-refreshed
+	deadline := time.Now().Add(time.Second)
+	for u.Name != "new" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		if err := c.Get(ctx, "user:1", &u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if u.Name != "new" {
+		t.Errorf("stale value was not refreshed")
+	}
+}
+
+func TestOnceNegative(t *testing.T) {
+	ctx := context.Background()
+	loadErr := errors.New("db is down")
+
+	var calls int
+	loader := func(ctx context.Context) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, loadErr
+		}
+		return nil, cache.ErrNotFound
+	}
+
+	c := cache.New(newFakeRedis(), &cache.Options{NegativeTTL: time.Minute})
+	var u user
+	if err := c.Once(ctx, "user:2", time.Minute, &u, loader); err != loadErr {
+		t.Fatalf("got %v, want %v", err, loadErr)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.Once(ctx, "user:2", time.Minute, &u, loader); err != cache.ErrNotFound {
+			t.Fatalf("got %v, want ErrNotFound", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("loader was called %d times, want 2", calls)
+	}
+}
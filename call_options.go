@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// CallOptions override the Options of a client for some commands, see
+// Client.WithOptions. Their zero fields do not override the options.
+type CallOptions struct {
+	// ReadTimeout overrides Options.ReadTimeout, with the same values:
+	// -1 disables the timeout and -2 does not set a deadline.
+	ReadTimeout time.Duration
+	// WriteTimeout overrides Options.WriteTimeout, with the same values.
+	WriteTimeout time.Duration
+	// MaxRetries overrides Options.MaxRetries, with the same values:
+	// -1 disables the retries.
+	MaxRetries int
+	// RetryPolicy, if set, decides whether the failed commands are retried
+	// and after which backoff, instead of MaxRetries and of the retry options
+	// of the client.
+	RetryPolicy RetryPolicy
+}
+
+// RetryPolicy decides whether the failed commands are retried, see
+// CallOptions.RetryPolicy and Client.WithRetryPolicy.
+type RetryPolicy interface {
+	// Retry is called when the attempt of cmd failed with err, the first
+	// attempt being 0. retryable reports whether the client retries err by
+	// default, e.g. a network error or a LOADING error, but not the timeout
+	// of a blocking command. It returns whether cmd is retried, and the
+	// backoff before it is.
+	Retry(cmd Cmder, attempt int, err error, retryable bool) (retry bool, backoff time.Duration)
+}
+
+// RetryPolicyFunc is a function implementing RetryPolicy.
+type RetryPolicyFunc func(cmd Cmder, attempt int, err error, retryable bool) (bool, time.Duration)
+
+// Retry calls fn.
+func (fn RetryPolicyFunc) Retry(cmd Cmder, attempt int, err error, retryable bool) (bool, time.Duration) {
+	return fn(cmd, attempt, err, retryable)
+}
+
+// BackoffRetryPolicy retries the retryable errors up to MaxRetries times,
+// with an exponential backoff between MinBackoff and MaxBackoff as the
+// retry options of the clients. Its zero value does not retry.
+type BackoffRetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Retry implements RetryPolicy.
+func (p BackoffRetryPolicy) Retry(cmd Cmder, attempt int, err error, retryable bool) (bool, time.Duration) {
+	if !retryable || attempt >= p.MaxRetries {
+		return false, 0
+	}
+	return true, internal.RetryBackoff(attempt+1, p.MinBackoff, p.MaxBackoff)
+}
+
+type callOptionsKey struct{}
+
+// withCallOptions returns ctx with the call options of its commands.
+func withCallOptions(ctx context.Context, opt *CallOptions) context.Context {
+	return context.WithValue(ctx, callOptionsKey{}, opt)
+}
+
+// callOptions returns the call options of ctx, or nil.
+func callOptions(ctx context.Context) *CallOptions {
+	opt, _ := ctx.Value(callOptionsKey{}).(*CallOptions)
+	return opt
+}
+
+func (opt *CallOptions) readTimeout(timeout time.Duration) time.Duration {
+	if opt == nil {
+		return timeout
+	}
+	return overrideTimeout(opt.ReadTimeout, timeout)
+}
+
+func (opt *CallOptions) writeTimeout(timeout time.Duration) time.Duration {
+	if opt == nil {
+		return timeout
+	}
+	return overrideTimeout(opt.WriteTimeout, timeout)
+}
+
+// overrideTimeout returns the timeout of Options.init for override, or
+// timeout when override is zero.
+func overrideTimeout(override, timeout time.Duration) time.Duration {
+	switch override {
+	case 0:
+		return timeout
+	case -1:
+		return 0
+	case -2:
+		return -1
+	}
+	return override
+}
+
+func (opt *CallOptions) maxRetries(maxRetries int) int {
+	if opt == nil || opt.MaxRetries == 0 {
+		return maxRetries
+	}
+	if opt.MaxRetries == -1 {
+		return 0
+	}
+	return opt.MaxRetries
+}
+
+//------------------------------------------------------------------------------
+
+// CallClient processes the commands of a Client with CallOptions, see
+// Client.WithOptions.
+type CallClient struct {
+	cmdable
+
+	client *Client
+	opt    CallOptions
+}
+
+// WithOptions returns a client processing the commands with opt, overriding
+// the Options of the client, e.g.
+//
+//	rdb.WithOptions(redis.CallOptions{ReadTimeout: 50 * time.Millisecond}).Get(ctx, key)
+//
+// Unlike WithTimeout, it shares the client and its hooks and is cheap enough
+// to be used for each command.
+func (c *Client) WithOptions(opt CallOptions) *CallClient {
+	cc := &CallClient{
+		client: c,
+		opt:    opt,
+	}
+	cc.cmdable = cc.Process
+	return cc
+}
+
+// WithRetryPolicy returns a client processing the commands retried as
+// decided by policy, e.g. to retry the idempotent reads but not the writes:
+//
+//	rdb.WithRetryPolicy(redis.BackoffRetryPolicy{MaxRetries: 5}).Get(ctx, key)
+func (c *Client) WithRetryPolicy(policy RetryPolicy) *CallClient {
+	return c.WithOptions(CallOptions{RetryPolicy: policy})
+}
+
+// Do creates a Cmd from the args and processes the cmd with the call options.
+func (c *CallClient) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(ctx, args...)
+	_ = c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process processes cmd with the call options.
+func (c *CallClient) Process(ctx context.Context, cmd Cmder) error {
+	return c.client.Process(withCallOptions(ctx, &c.opt), cmd)
+}
@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type callOptionsHook struct {
+	opt *CallOptions
+}
+
+func (h *callOptionsHook) DialHook(next DialHook) DialHook { return next }
+
+func (h *callOptionsHook) ProcessHook(next ProcessHook) ProcessHook {
+	return func(ctx context.Context, cmd Cmder) error {
+		h.opt = callOptions(ctx)
+		return nil
+	}
+}
+
+func (h *callOptionsHook) ProcessPipelineHook(next ProcessPipelineHook) ProcessPipelineHook {
+	return next
+}
+
+func TestCallOptions(t *testing.T) {
+	rdb := NewClient(&Options{ReadTimeout: time.Second, MaxRetries: 2})
+	defer rdb.Close()
+	hook := new(callOptionsHook)
+	rdb.AddHook(hook)
+
+	rdb.Get(ctx, "key")
+	if hook.opt != nil {
+		t.Fatalf("got call options %+v", hook.opt)
+	}
+	get := NewStringCmd(ctx, "get", "key")
+	if timeout := rdb.cmdTimeout(get, hook.opt); timeout != time.Second {
+		t.Fatalf("got read timeout %s", timeout)
+	}
+	if n := hook.opt.maxRetries(rdb.opt.MaxRetries); n != 2 {
+		t.Fatalf("got %d retries", n)
+	}
+
+	rdb.WithOptions(CallOptions{ReadTimeout: 50 * time.Millisecond, WriteTimeout: -1, MaxRetries: -1}).Get(ctx, "key")
+	if hook.opt == nil {
+		t.Fatal("got no call options")
+	}
+	if timeout := rdb.cmdTimeout(get, hook.opt); timeout != 50*time.Millisecond {
+		t.Fatalf("got read timeout %s", timeout)
+	}
+	if timeout := hook.opt.writeTimeout(rdb.opt.WriteTimeout); timeout != 0 {
+		t.Fatalf("got write timeout %s", timeout)
+	}
+	if n := hook.opt.maxRetries(rdb.opt.MaxRetries); n != 0 {
+		t.Fatalf("got %d retries", n)
+	}
+
+	// the blocking commands keep their timeout
+	blpop := NewStringSliceCmd(ctx, "blpop", "list", 5)
+	blpop.setReadTimeout(5 * time.Second)
+	if timeout := rdb.cmdTimeout(blpop, hook.opt); timeout != 15*time.Second {
+		t.Fatalf("got read timeout %s", timeout)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	var dials int
+	rdb := NewClient(&Options{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials++
+			return nil, dialErr
+		},
+		MaxRetries: -1,
+	})
+	defer rdb.Close()
+
+	var attempts []int
+	policy := RetryPolicyFunc(func(cmd Cmder, attempt int, err error, retryable bool) (bool, time.Duration) {
+		if !retryable || err != dialErr || cmd.Name() != "get" {
+			t.Fatalf("got %s failed with %v, retryable %t", cmd.Name(), err, retryable)
+		}
+		attempts = append(attempts, attempt)
+		return attempt < 2, time.Millisecond
+	})
+	if err := rdb.WithRetryPolicy(policy).Get(ctx, "key").Err(); err != dialErr {
+		t.Fatalf("got %v, wanted the dial error", err)
+	}
+	if dials != 3 || len(attempts) != 3 || attempts[2] != 2 {
+		t.Fatalf("got %d dials and attempts %v, wanted 3", dials, attempts)
+	}
+}
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	get := NewStringCmd(ctx, "get", "key")
+	policy := BackoffRetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	if retry, backoff := policy.Retry(get, 0, io.EOF, true); !retry || backoff < time.Millisecond || backoff > 10*time.Millisecond {
+		t.Fatalf("got %t after %s, wanted a retry", retry, backoff)
+	}
+	if retry, _ := policy.Retry(get, 2, io.EOF, true); retry {
+		t.Fatal("retried after MaxRetries")
+	}
+	if retry, _ := policy.Retry(get, 0, errors.New("ERR syntax error"), false); retry {
+		t.Fatal("retried an error not retryable")
+	}
+	if retry, _ := (BackoffRetryPolicy{}).Retry(get, 0, io.EOF, true); retry {
+		t.Fatal("the zero policy retried")
+	}
+}
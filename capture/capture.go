@@ -0,0 +1,327 @@
+// Package capture captures the commands of a client with their timing and
+// replays them against another server, for load testing and validating
+// migrations:
+//
+//	f, _ := os.Create("traffic.jsonl")
+//	w := capture.NewWriter(f)
+//	client.AddHook(w)
+//	...
+//	stats, err := capture.Replay(ctx, f, target, &capture.ReplayOptions{Speed: 2})
+//
+// The capture is a JSON document per command. The commands are captured as
+// sent, after their arguments are formatted, but without the commands of the
+// connection handshake such as AUTH and SELECT: the target client must be
+// configured with the database and credentials of the captured one.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// Record is a captured command.
+type Record struct {
+	// At is the time the command was sent, from the start of the capture.
+	At time.Duration `json:"at"`
+	// Duration is the time it took to process the command.
+	Duration time.Duration `json:"duration"`
+	// Pipeline is the number of the pipeline of the command, or 0 when it was
+	// not pipelined.
+	Pipeline int64 `json:"pipeline,omitempty"`
+	// Args are the arguments of the command, as sent to Redis.
+	Args []string `json:"args"`
+	// Err is the error of the command, if any.
+	Err string `json:"err,omitempty"`
+}
+
+// plainRecord is a Record without its JSON methods.
+type plainRecord Record
+
+// jsonRecord encodes the arguments in base64 when they are not valid UTF-8,
+// which JSON strings cannot hold.
+type jsonRecord struct {
+	plainRecord
+	Base64 bool `json:"base64,omitempty"`
+}
+
+func (r *Record) MarshalJSON() ([]byte, error) {
+	jr := jsonRecord{plainRecord: plainRecord(*r)}
+	for _, arg := range r.Args {
+		if !utf8.ValidString(arg) {
+			jr.Base64 = true
+			break
+		}
+	}
+	if jr.Base64 {
+		jr.Args = make([]string, len(r.Args))
+		for i, arg := range r.Args {
+			jr.Args[i] = base64.StdEncoding.EncodeToString([]byte(arg))
+		}
+	}
+	return json.Marshal(jr)
+}
+
+func (r *Record) UnmarshalJSON(b []byte) error {
+	var jr jsonRecord
+	if err := json.Unmarshal(b, &jr); err != nil {
+		return err
+	}
+	*r = Record(jr.plainRecord)
+	if jr.Base64 {
+		for i, arg := range r.Args {
+			decoded, err := base64.StdEncoding.DecodeString(arg)
+			if err != nil {
+				return fmt.Errorf("capture: invalid argument: %w", err)
+			}
+			r.Args[i] = string(decoded)
+		}
+	}
+	return nil
+}
+
+// Writer is a hook writing the commands of a client to an io.Writer. It is
+// safe for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	enc      *json.Encoder
+	start    time.Time
+	pipeline int64
+	err      error
+}
+
+var _ redis.Hook = (*Writer)(nil)
+
+// NewWriter returns a hook writing the commands to w from now on. The
+// commands are written as they complete: w should be buffered when the
+// commands are frequent.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		enc:   json.NewEncoder(w),
+		start: time.Now(),
+	}
+}
+
+// Err returns the first error writing the capture. The commands are not
+// captured after an error.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *Writer) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (w *Writer) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		// the client sets the error of the command once the hooks returned
+		w.write(start, time.Since(start), 0, cmd, err)
+		return err
+	}
+}
+
+func (w *Writer) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		dur := time.Since(start)
+
+		w.mu.Lock()
+		w.pipeline++
+		pipeline := w.pipeline
+		w.mu.Unlock()
+		for _, cmd := range cmds {
+			w.write(start, dur, pipeline, cmd, cmd.Err())
+		}
+		return err
+	}
+}
+
+func (w *Writer) write(start time.Time, dur time.Duration, pipeline int64, cmd redis.Cmder, cmdErr error) {
+	args, err := wireArgs(cmd.Args())
+	rec := &Record{
+		At:       start.Sub(w.start),
+		Duration: dur,
+		Pipeline: pipeline,
+		Args:     args,
+	}
+	if cmdErr != nil {
+		rec.Err = cmdErr.Error()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return
+	}
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.err = w.enc.Encode(rec)
+}
+
+// wireArgs formats args as the client sends them.
+func wireArgs(args []interface{}) ([]string, error) {
+	var buf bytes.Buffer
+	if err := proto.NewWriter(&buf).WriteArgs(args); err != nil {
+		return nil, err
+	}
+	v, err := proto.NewReader(&buf).ReadReply()
+	if err != nil {
+		return nil, err
+	}
+	vals := v.([]interface{})
+	ss := make([]string, len(vals))
+	for i, val := range vals {
+		ss[i] = val.(string)
+	}
+	return ss, nil
+}
+
+// Reader reads the records of a capture.
+type Reader struct {
+	dec *json.Decoder
+}
+
+// NewReader returns a reader of the capture r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(bufio.NewReader(r))}
+}
+
+// Next returns the next record, or io.EOF at the end of the capture.
+func (r *Reader) Next() (*Record, error) {
+	rec := new(Record)
+	if err := r.dec.Decode(rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+type ReplayOptions struct {
+	// Speed is the speed of the replay relative to the capture, e.g. 2 replays
+	// the commands twice as fast. Default is 1.
+	Speed float64
+	// NoWait replays the commands as fast as possible, ignoring their timing.
+	NoWait bool
+}
+
+// ReplayStats are the statistics of a replay.
+type ReplayStats struct {
+	// Commands is the number of replayed commands.
+	Commands int
+	// Errors is the number of replayed commands that failed.
+	Errors int
+	// Mismatches is the number of replayed commands whose error differs from
+	// the captured one, including redis.Nil.
+	Mismatches int
+	// Duration is the duration of the replay.
+	Duration time.Duration
+}
+
+// Replay sends the commands of the capture r to client, in the order and at
+// the pace they were captured, and the pipelined commands in pipelines. The
+// commands are sent one after the other: a replay slower than the capture
+// falls behind rather than sending the commands concurrently. It stops at the
+// end of the capture, or at the first error reading it.
+func Replay(ctx context.Context, r io.Reader, client redis.UniversalClient, opt *ReplayOptions) (*ReplayStats, error) {
+	var o ReplayOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.Speed <= 0 {
+		o.Speed = 1
+	}
+
+	stats := new(ReplayStats)
+	start := time.Now()
+	defer func() { stats.Duration = time.Since(start) }()
+
+	rd := NewReader(r)
+	var batch []*Record
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if !o.NoWait {
+			at := time.Duration(float64(batch[0].At) / o.Speed)
+			if err := internal.Sleep(ctx, time.Until(start.Add(at))); err != nil {
+				return err
+			}
+		}
+		replay(ctx, client, batch, stats)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return stats, flush()
+		}
+		if err != nil {
+			return stats, err
+		}
+		if len(batch) > 0 && (rec.Pipeline == 0 || rec.Pipeline != batch[0].Pipeline) {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+		if len(rec.Args) > 0 {
+			batch = append(batch, rec)
+		}
+	}
+}
+
+func replay(ctx context.Context, client redis.UniversalClient, recs []*Record, stats *ReplayStats) {
+	cmds := make([]*redis.Cmd, len(recs))
+	if len(recs) == 1 && recs[0].Pipeline == 0 {
+		cmds[0] = client.Do(ctx, args(recs[0])...)
+	} else {
+		pipe := client.Pipeline()
+		for i, rec := range recs {
+			cmds[i] = pipe.Do(ctx, args(rec)...)
+		}
+		_, _ = pipe.Exec(ctx)
+	}
+
+	for i, cmd := range cmds {
+		stats.Commands++
+		var got string
+		if err := cmd.Err(); err != nil {
+			got = err.Error()
+			if err != redis.Nil {
+				stats.Errors++
+			}
+		}
+		if got != recs[i].Err {
+			stats.Mismatches++
+		}
+	}
+}
+
+func args(rec *Record) []interface{} {
+	args := make([]interface{}, len(rec.Args))
+	for i, arg := range rec.Args {
+		args[i] = arg
+	}
+	return args
+}
@@ -0,0 +1,108 @@
+package capture_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/capture"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func newClient(t *testing.T) (*redistest.Server, *redis.Client) {
+	srv := redistest.NewServer()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+		srv.Close()
+	})
+	return srv, client
+}
+
+func TestCaptureReplay(t *testing.T) {
+	ctx := context.Background()
+	_, src := newClient(t)
+
+	var buf bytes.Buffer
+	w := capture.NewWriter(&buf)
+	src.AddHook(w)
+
+	src.Set(ctx, "key", "value", time.Minute)
+	src.Get(ctx, "missing")
+	src.Set(ctx, "binary", []byte{0xff, 0x00}, 0)
+	_, err := src.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, "counter")
+		pipe.Incr(ctx, "counter")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := capture.NewReader(bytes.NewReader(buf.Bytes()))
+	var got [][]string
+	var pipelines []int64
+	for {
+		rec, err := rd.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, rec.Args)
+		pipelines = append(pipelines, rec.Pipeline)
+	}
+	want := [][]string{
+		{"set", "key", "value", "ex", "60"},
+		{"get", "missing"},
+		{"set", "binary", "\xff\x00"},
+		{"incr", "counter"},
+		{"incr", "counter"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(pipelines, []int64{0, 0, 0, 1, 1}) {
+		t.Fatalf("got pipelines %v", pipelines)
+	}
+
+	dstSrv, dst := newClient(t)
+	stats, err := capture.Replay(ctx, &buf, dst, &capture.ReplayOptions{Speed: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Commands != 5 || stats.Errors != 0 || stats.Mismatches != 0 {
+		t.Fatalf("got %+v", stats)
+	}
+	if v, _ := dstSrv.Get("counter"); v != "2" {
+		t.Fatalf("got counter %q", v)
+	}
+	if v, _ := dstSrv.Get("binary"); v != "\xff\x00" {
+		t.Fatalf("got binary %q", v)
+	}
+}
+
+func TestReplayMismatches(t *testing.T) {
+	ctx := context.Background()
+	_, src := newClient(t)
+	var buf bytes.Buffer
+	src.AddHook(capture.NewWriter(&buf))
+	src.Set(ctx, "key", "value", 0)
+	src.Get(ctx, "key")
+
+	// the key is missing from the target
+	_, dst := newClient(t)
+	buf.Truncate(0)
+	src.Get(ctx, "key")
+	stats, err := capture.Replay(ctx, &buf, dst, &capture.ReplayOptions{NoWait: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Commands != 1 || stats.Mismatches != 1 || stats.Errors != 0 {
+		t.Fatalf("got %+v", stats)
+	}
+}
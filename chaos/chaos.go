@@ -0,0 +1,247 @@
+// Package chaos implements hooks injecting faults into the commands of a
+// client, for testing the resilience of applications:
+//
+//	client.AddHook(chaos.Latency(50*time.Millisecond, 20*time.Millisecond))
+//	client.AddHook(chaos.Loading(0.01))
+//	client.AddHook(&chaos.Drop{Probability: 0.001})
+//
+// The faults of the hooks added to a client are seen by the application as
+// is. To exercise the redirections of a ClusterClient, add the MOVED and ASK
+// faults to the clients of its nodes with ClusterOptions.OnNewNode instead.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// Matcher selects the commands affected by a fault.
+type Matcher func(cmd redis.Cmder) bool
+
+// Commands matches the commands named names, case-insensitively.
+func Commands(names ...string) Matcher {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = struct{}{}
+	}
+	return func(cmd redis.Cmder) bool {
+		_, ok := set[cmd.Name()]
+		return ok
+	}
+}
+
+// KeyPrefix matches the commands whose first key starts with prefix.
+func KeyPrefix(prefix string) Matcher {
+	return func(cmd redis.Cmder) bool {
+		args := cmd.Args()
+		if len(args) < 2 {
+			return false
+		}
+		key, ok := args[1].(string)
+		return ok && strings.HasPrefix(key, prefix)
+	}
+}
+
+// NewError returns an error replied by Redis with the message msg, such as
+// "TRYAGAIN Multiple keys request during rehashing of slot", which the client
+// handles as the same error replied by a server.
+func NewError(msg string) error {
+	return proto.RedisError(msg)
+}
+
+// Fault is a hook delaying and failing the matching commands. The commands of
+// a pipeline fail independently, which injects partial failures, but the
+// commands of a transaction fail or succeed together. Failed commands are not
+// sent to Redis.
+type Fault struct {
+	// Probability of the fault for each matching command, from 0 to 1.
+	Probability float64
+	// Match selects the commands. Default is all the commands.
+	Match Matcher
+
+	// Delay delays the commands before they are sent.
+	Delay time.Duration
+	// Jitter adds a random delay up to Jitter to Delay.
+	Jitter time.Duration
+	// Err fails the commands with Err.
+	Err error
+}
+
+var _ redis.Hook = (*Fault)(nil)
+
+// Latency delays all the commands by delay plus a random jitter up to jitter.
+func Latency(delay, jitter time.Duration) *Fault {
+	return &Fault{Probability: 1, Delay: delay, Jitter: jitter}
+}
+
+// Errors fails the commands with err with the given probability.
+func Errors(err error, probability float64) *Fault {
+	return &Fault{Probability: probability, Err: err}
+}
+
+// Moved fails the commands with a MOVED redirection to addr with the given
+// probability.
+func Moved(slot int, addr string, probability float64) *Fault {
+	return Errors(NewError(fmt.Sprintf("MOVED %d %s", slot, addr)), probability)
+}
+
+// Ask fails the commands with an ASK redirection to addr with the given
+// probability.
+func Ask(slot int, addr string, probability float64) *Fault {
+	return Errors(NewError(fmt.Sprintf("ASK %d %s", slot, addr)), probability)
+}
+
+// Loading fails the commands with the LOADING error of a server loading its
+// dataset with the given probability.
+func Loading(probability float64) *Fault {
+	return Errors(NewError("LOADING Redis is loading the dataset in memory"), probability)
+}
+
+func (f *Fault) hits(cmd redis.Cmder) bool {
+	if f.Match != nil && !f.Match(cmd) {
+		return false
+	}
+	return f.Probability >= 1 || rand.Float64() < f.Probability
+}
+
+func (f *Fault) delay(ctx context.Context) error {
+	d := f.Delay
+	if f.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(f.Jitter)))
+	}
+	if d <= 0 {
+		return nil
+	}
+	return internal.Sleep(ctx, d)
+}
+
+func (f *Fault) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (f *Fault) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !f.hits(cmd) {
+			return next(ctx, cmd)
+		}
+		if err := f.delay(ctx); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		if f.Err != nil {
+			cmd.SetErr(f.Err)
+			return f.Err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (f *Fault) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		hit := make([]bool, len(cmds))
+		var hits int
+		for i, cmd := range cmds {
+			if f.hits(cmd) {
+				hit[i] = true
+				hits++
+			}
+		}
+		if hits == 0 {
+			return next(ctx, cmds)
+		}
+
+		if err := f.delay(ctx); err != nil {
+			setErr(cmds, err)
+			return err
+		}
+		if f.Err == nil {
+			return next(ctx, cmds)
+		}
+
+		if len(cmds) > 0 && cmds[0].Name() == "multi" {
+			setErr(cmds, f.Err)
+			return f.Err
+		}
+		sent := make([]redis.Cmder, 0, len(cmds)-hits)
+		for i, cmd := range cmds {
+			if hit[i] {
+				cmd.SetErr(f.Err)
+			} else {
+				sent = append(sent, cmd)
+			}
+		}
+		if len(sent) > 0 {
+			_ = next(ctx, sent)
+		}
+		// like the client, return the first error of the pipeline
+		for _, cmd := range cmds {
+			if err := cmd.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func setErr(cmds []redis.Cmder, err error) {
+	for _, cmd := range cmds {
+		cmd.SetErr(err)
+	}
+}
+
+// Drop is a hook dropping the connections of a client: each write to a
+// connection closes it with Probability, failing with a connection reset
+// error. The client retries the commands not sent on another connection, see
+// Options.MaxRetries.
+type Drop struct {
+	// Probability of dropping the connection on each write, from 0 to 1.
+	Probability float64
+}
+
+var _ redis.Hook = (*Drop)(nil)
+
+func (d *Drop) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &dropConn{Conn: conn, probability: d.Probability}, nil
+	}
+}
+
+func (d *Drop) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return next
+}
+
+func (d *Drop) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+type dropConn struct {
+	net.Conn
+	probability float64
+}
+
+func (c *dropConn) Write(b []byte) (int, error) {
+	if c.probability >= 1 || rand.Float64() < c.probability {
+		_ = c.Conn.Close()
+		return 0, &net.OpError{
+			Op:     "write",
+			Net:    c.RemoteAddr().Network(),
+			Source: c.LocalAddr(),
+			Addr:   c.RemoteAddr(),
+			Err:    syscall.ECONNRESET,
+		}
+	}
+	return c.Conn.Write(b)
+}
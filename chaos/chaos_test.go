@@ -0,0 +1,99 @@
+package chaos_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/chaos"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func newClient(t *testing.T, hooks ...redis.Hook) *redis.Client {
+	srv := redistest.NewServer()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	for _, hook := range hooks {
+		client.AddHook(hook)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+		srv.Close()
+	})
+	return client
+}
+
+func TestLatency(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, chaos.Latency(20*time.Millisecond, 0))
+
+	start := time.Now()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Fatalf("got %s, want at least 20ms", d)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != context.DeadlineExceeded {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	fault := chaos.Loading(1)
+	fault.Match = chaos.Commands("GET")
+	client := newClient(t, fault)
+
+	if err := client.Set(ctx, "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Get(ctx, "key").Err()
+	if !redis.HasErrorPrefix(err, "LOADING") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestPartialPipelineFailure(t *testing.T) {
+	ctx := context.Background()
+	fault := chaos.Errors(chaos.NewError("TRYAGAIN Multiple keys request during rehashing of slot"), 1)
+	fault.Match = chaos.KeyPrefix("fail:")
+	client := newClient(t, fault)
+
+	cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "ok", "1", 0)
+		pipe.Set(ctx, "fail:1", "1", 0)
+		return nil
+	})
+	if !redis.HasErrorPrefix(err, "TRYAGAIN") {
+		t.Fatalf("got %v", err)
+	}
+	if cmds[0].Err() != nil || cmds[1].Err() == nil {
+		t.Fatalf("got %v and %v", cmds[0].Err(), cmds[1].Err())
+	}
+
+	// transactions fail as a whole
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "tx", "1", 0)
+		pipe.Set(ctx, "fail:2", "1", 0)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := client.Exists(ctx, "ok", "tx", "fail:1", "fail:2").Val(); n != 1 {
+		t.Fatalf("got %d keys, want 1", n)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	ctx := context.Background()
+	client := newClient(t, &chaos.Drop{Probability: 1})
+
+	if err := client.Ping(ctx).Err(); err == nil {
+		t.Fatal("expected an error")
+	}
+}
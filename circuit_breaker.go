@@ -0,0 +1,242 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerOptions enables a circuit breaker for each node of a client:
+// once a node failed FailureThreshold times in a row, its commands fail
+// right away with a *CircuitBreakerError instead of waiting for its timeouts,
+// until OpenTimeout elapses and some probe commands succeed. A ClusterClient
+// has a breaker for each node and a Ring for each shard.
+//
+// The failures are the errors of the network and of the connections, not the
+// Redis errors nor the cancellations of the contexts.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// breaker. Default is 5.
+	FailureThreshold int
+
+	// OpenTimeout is the duration the breaker stays open before it is
+	// half-open, letting probe commands through. Default is 5 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of probe commands which must succeed for
+	// the half-open breaker to close, and the number of probes processed at
+	// once. A failed probe opens it again. Default is 1.
+	HalfOpenProbes int
+
+	// IsFailure, if set, reports whether err is a failure of the node,
+	// instead of the default.
+	IsFailure func(err error) bool
+
+	// OnStateChange, if set, is called when the breaker of the node at addr
+	// changes state. It is called synchronously and must not block.
+	OnStateChange func(addr string, from, to CircuitState)
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets the commands through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails the commands with a *CircuitBreakerError.
+	CircuitOpen
+	// CircuitHalfOpen lets the probe commands through, and fails the others.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// ErrCircuitOpen is the error of the commands rejected by a circuit breaker:
+// errors.Is(err, ErrCircuitOpen) reports them, and errors.As to a
+// *CircuitBreakerError tells for which node.
+var ErrCircuitOpen = errors.New("redis: circuit breaker is open")
+
+// CircuitBreakerError is the error of a command rejected by the circuit
+// breaker of a node, see Options.CircuitBreaker.
+type CircuitBreakerError struct {
+	// Addr is the address of the node.
+	Addr string
+	// State is the state of the breaker, CircuitOpen or CircuitHalfOpen.
+	State CircuitState
+}
+
+func (e *CircuitBreakerError) Error() string {
+	return fmt.Sprintf("redis: circuit breaker is %s for %s", e.State, e.Addr)
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitBreakerError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// circuitBreaker is the circuit breaker of a node. Like a Limiter, each
+// allowed operation must report its result.
+type circuitBreaker struct {
+	addr             func() string
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	isFailure        func(err error) bool
+	onStateChange    func(addr string, from, to CircuitState)
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int       // the consecutive failures, when closed
+	openedAt  time.Time // when opened, or when the last probe started
+	probes    int       // the probes in progress, when half-open
+	successes int       // the successful probes, when half-open
+}
+
+func newCircuitBreaker(opt *CircuitBreakerOptions, addr func() string) *circuitBreaker {
+	if opt == nil {
+		return nil
+	}
+	cb := &circuitBreaker{
+		addr:             addr,
+		failureThreshold: opt.FailureThreshold,
+		openTimeout:      opt.OpenTimeout,
+		halfOpenProbes:   opt.HalfOpenProbes,
+		isFailure:        opt.IsFailure,
+		onStateChange:    opt.OnStateChange,
+	}
+	if cb.failureThreshold <= 0 {
+		cb.failureThreshold = 5
+	}
+	if cb.openTimeout <= 0 {
+		cb.openTimeout = 5 * time.Second
+	}
+	if cb.halfOpenProbes <= 0 {
+		cb.halfOpenProbes = 1
+	}
+	if cb.isFailure == nil {
+		cb.isFailure = isNodeFailure
+	}
+	return cb
+}
+
+// isNodeFailure is the default CircuitBreakerOptions.IsFailure.
+func isNodeFailure(err error) bool {
+	switch err {
+	case nil, Nil, ErrClosed, context.Canceled, context.DeadlineExceeded:
+		return false
+	}
+	return !isRedisError(err) && !errors.Is(err, ErrCircuitOpen)
+}
+
+// Allow returns nil if an operation is allowed, which result must then be
+// reported, or a *CircuitBreakerError.
+func (cb *circuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitClosed:
+		return nil
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.openTimeout {
+			return &CircuitBreakerError{Addr: cb.addr(), State: CircuitOpen}
+		}
+		cb.setState(CircuitHalfOpen)
+		cb.probes = 0
+		cb.successes = 0
+	}
+
+	// a probe whose result is not reported within openTimeout, e.g. as its
+	// connection was not released, does not block the others
+	if cb.probes >= cb.halfOpenProbes && time.Since(cb.openedAt) < cb.openTimeout {
+		return &CircuitBreakerError{Addr: cb.addr(), State: CircuitHalfOpen}
+	}
+	if cb.probes >= cb.halfOpenProbes {
+		cb.probes = 0
+	}
+	cb.probes++
+	cb.openedAt = time.Now()
+	return nil
+}
+
+// ReportResult reports the result of an allowed operation.
+func (cb *circuitBreaker) ReportResult(err error) {
+	failed := cb.isFailure(err)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitClosed:
+		if !failed {
+			cb.failures = 0
+			return
+		}
+		cb.failures++
+		if cb.failures >= cb.failureThreshold {
+			cb.open()
+		}
+	case CircuitHalfOpen:
+		if cb.probes > 0 {
+			cb.probes--
+		}
+		if failed {
+			cb.open()
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.halfOpenProbes {
+			cb.failures = 0
+			cb.setState(CircuitClosed)
+		}
+	}
+}
+
+// State returns the state of the breaker.
+func (cb *circuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.openTimeout {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+// open opens the breaker, with cb.mu locked.
+func (cb *circuitBreaker) open() {
+	cb.openedAt = time.Now()
+	cb.setState(CircuitOpen)
+}
+
+// setState sets the state of the breaker, with cb.mu locked.
+func (cb *circuitBreaker) setState(state CircuitState) {
+	from := cb.state
+	cb.state = state
+	if from != state && cb.onStateChange != nil {
+		cb.onStateChange(cb.addr(), from, state)
+	}
+}
+
+var _ Limiter = (*circuitBreaker)(nil)
+
+// CircuitState returns the state of the circuit breaker of the client, see
+// Options.CircuitBreaker, which is CircuitClosed without breaker.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.State()
+}
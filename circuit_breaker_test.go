@@ -0,0 +1,110 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var down int32 = 1
+	var dials int32
+	var mu sync.Mutex
+	var changes []string
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       srv.Addr(),
+		MaxRetries: -1,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			if atomic.LoadInt32(&down) == 1 {
+				return nil, errors.New("connection refused")
+			}
+			return net.Dial(network, addr)
+		},
+		CircuitBreaker: &redis.CircuitBreakerOptions{
+			FailureThreshold: 2,
+			OpenTimeout:      50 * time.Millisecond,
+			OnStateChange: func(addr string, from, to redis.CircuitState) {
+				mu.Lock()
+				changes = append(changes, from.String()+"->"+to.String())
+				mu.Unlock()
+			},
+		},
+	})
+	defer rdb.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := rdb.Ping(ctx).Err(); err == nil || errors.Is(err, redis.ErrCircuitOpen) {
+			t.Fatalf("ping %d: got %v, wanted a dial error", i, err)
+		}
+	}
+	if state := rdb.CircuitState(); state != redis.CircuitOpen {
+		t.Fatalf("got %s, wanted open", state)
+	}
+
+	n := atomic.LoadInt32(&dials)
+	err := rdb.Ping(ctx).Err()
+	var cbErr *redis.CircuitBreakerError
+	if !errors.As(err, &cbErr) || !errors.Is(err, redis.ErrCircuitOpen) {
+		t.Fatalf("got %v, wanted a CircuitBreakerError", err)
+	}
+	if cbErr.Addr != srv.Addr() || cbErr.State != redis.CircuitOpen {
+		t.Fatalf("got %+v", cbErr)
+	}
+	if got := atomic.LoadInt32(&dials); got != n {
+		t.Fatalf("dialed while the breaker is open")
+	}
+
+	// a failed probe opens the breaker again
+	time.Sleep(60 * time.Millisecond)
+	if state := rdb.CircuitState(); state != redis.CircuitHalfOpen {
+		t.Fatalf("got %s, wanted half-open", state)
+	}
+	if err := rdb.Ping(ctx).Err(); err == nil || errors.Is(err, redis.ErrCircuitOpen) {
+		t.Fatalf("got %v, wanted a dial error", err)
+	}
+	if err := rdb.Ping(ctx).Err(); !errors.Is(err, redis.ErrCircuitOpen) {
+		t.Fatalf("got %v, wanted ErrCircuitOpen", err)
+	}
+
+	// a successful probe closes it
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(60 * time.Millisecond)
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if state := rdb.CircuitState(); state != redis.CircuitClosed {
+		t.Fatalf("got %s, wanted closed", state)
+	}
+
+	// the Redis errors are not failures
+	for i := 0; i < 3; i++ {
+		_ = rdb.Get(ctx, "missing").Err()
+		_ = rdb.Do(ctx, "unknown-command").Err()
+	}
+	if state := rdb.CircuitState(); state != redis.CircuitClosed {
+		t.Fatalf("got %s, wanted closed", state)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("got changes %q, wanted %q", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("got changes %q, wanted %q", changes, want)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package redis
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// ClientCacheOptions enables client-side caching: the replies of the
+// cacheable commands are cached by the client, which asks the server to
+// track the keys read with CLIENT TRACKING during the connection handshake,
+// and invalidates the cached replies when the server sends the invalidation
+// push notifications of their keys. It requires the RESP3 protocol.
+//
+// The invalidations are received on the connections which read the keys,
+// when they are used again: while such a connection is idle, a reply can be
+// served after its key is modified by another client, for up to TTL. The
+// commands of the client invalidate the cached replies of their first key
+// right away, so the client reads its own writes of a single key.
+//
+// Only the commands processed one by one are served from the cache, not those
+// of pipelines and transactions.
+type ClientCacheOptions struct {
+	// MaxEntries is the maximum number of cached replies, the least recently
+	// used being evicted. Default is 10000.
+	MaxEntries int
+
+	// TTL is the maximum duration a reply is cached for. Default is 0, the
+	// replies are cached until they are invalidated or evicted.
+	TTL time.Duration
+
+	// Commands are the names of the cacheable commands, read-only commands
+	// whose key is the first argument. Default is "get" and "hgetall".
+	Commands []string
+}
+
+var defaultCacheableCmds = []string{"get", "hgetall"}
+
+// clientCache is the client-side cache of a client.
+type clientCache struct {
+	maxEntries int
+	ttl        time.Duration
+	cmds       map[string]struct{}
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	keys    map[string]map[*cacheEntry]struct{}
+	lru     list.List // of *cacheEntry, the most recently used first
+}
+
+type cacheEntry struct {
+	id        string // the command
+	key       string
+	connID    uint64 // the connection tracking key
+	reply     []byte
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+func newClientCache(opt *ClientCacheOptions) *clientCache {
+	c := &clientCache{
+		maxEntries: opt.MaxEntries,
+		ttl:        opt.TTL,
+		cmds:       make(map[string]struct{}),
+		entries:    make(map[string]*cacheEntry),
+		keys:       make(map[string]map[*cacheEntry]struct{}),
+	}
+	if c.maxEntries <= 0 {
+		c.maxEntries = 10000
+	}
+	cmds := opt.Commands
+	if len(cmds) == 0 {
+		cmds = defaultCacheableCmds
+	}
+	for _, name := range cmds {
+		c.cmds[internal.ToLower(name)] = struct{}{}
+	}
+	return c
+}
+
+// register handles the invalidation push notifications with c.
+func (c *clientCache) register(pushes *pushProcessor) {
+	pushes.register("invalidate", func(ctx context.Context, payload []interface{}) {
+		if len(payload) == 0 {
+			return
+		}
+		keys, ok := payload[0].([]interface{})
+		if !ok {
+			// FLUSHALL and FLUSHDB invalidate every key
+			c.flush()
+			return
+		}
+		for _, key := range keys {
+			if key, ok := key.(string); ok {
+				c.invalidate(key)
+			}
+		}
+	})
+}
+
+// watchConns makes c forget the replies read with the connections closed by
+// the pool of poolOpt, as the server stops tracking their keys.
+func (c *clientCache) watchConns(poolOpt *pool.Options) {
+	onClose := poolOpt.OnClose
+	poolOpt.OnClose = func(cn *pool.Conn, reason error) {
+		c.forgetConn(cn.ID())
+		if onClose != nil {
+			onClose(cn, reason)
+		}
+	}
+}
+
+// cacheable returns the id of cmd in the cache, if it is cacheable.
+func (c *clientCache) cacheable(cmd Cmder) (string, bool) {
+	args := cmd.Args()
+	if len(args) < 2 || cmd.readTimeout() != nil {
+		return "", false
+	}
+	if _, ok := c.cmds[cmd.Name()]; !ok {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, arg := range args {
+		s := keyArg(arg)
+		b.WriteString(strconv.Itoa(len(s)))
+		b.WriteByte(':')
+		b.WriteString(s)
+	}
+	return b.String(), true
+}
+
+// get reads the cached reply of the command id into cmd.
+func (c *clientCache) get(id string, cmd Cmder) bool {
+	c.mu.Lock()
+	entry := c.entries[id]
+	if entry != nil && c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.remove(entry)
+		entry = nil
+	}
+	if entry == nil {
+		c.mu.Unlock()
+		return false
+	}
+	c.lru.MoveToFront(entry.elem)
+	reply := entry.reply
+	c.mu.Unlock()
+
+	rd := proto.NewReaderSize(bytes.NewReader(reply), len(reply))
+	cmd.SetErr(cmd.readReply(rd))
+	return true
+}
+
+// readReply reads the reply of cmd, and caches it as the reply of the
+// command id unless it is an error.
+func (c *clientCache) readReply(
+	ctx context.Context, pushes *pushProcessor, rd *proto.Reader, cn *pool.Conn, id string, cmd Cmder,
+) error {
+	// the invalidations received before the reply are handled before the
+	// reply is cached
+	if err := pushes.process(ctx, rd); err != nil {
+		return err
+	}
+	reply, err := rd.Capture(func() error {
+		return pushes.readReply(ctx, rd, cmd)
+	})
+	if err != nil && err != Nil {
+		return err
+	}
+	c.set(id, cmd.stringArg(1), cn.ID(), reply)
+	return err
+}
+
+func (c *clientCache) set(id, key string, connID uint64, reply []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry := c.entries[id]; entry != nil {
+		c.remove(entry)
+	}
+	entry := &cacheEntry{
+		id:     id,
+		key:    key,
+		connID: connID,
+		reply:  reply,
+	}
+	if c.ttl > 0 {
+		entry.expiresAt = time.Now().Add(c.ttl)
+	}
+	entry.elem = c.lru.PushFront(entry)
+	c.entries[id] = entry
+	entries := c.keys[key]
+	if entries == nil {
+		entries = make(map[*cacheEntry]struct{})
+		c.keys[key] = entries
+	}
+	entries[entry] = struct{}{}
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back().Value.(*cacheEntry))
+	}
+}
+
+// remove removes entry, with c.mu locked.
+func (c *clientCache) remove(entry *cacheEntry) {
+	c.lru.Remove(entry.elem)
+	delete(c.entries, entry.id)
+	entries := c.keys[entry.key]
+	delete(entries, entry)
+	if len(entries) == 0 {
+		delete(c.keys, entry.key)
+	}
+}
+
+// invalidate removes the cached replies of key.
+func (c *clientCache) invalidate(key string) {
+	c.mu.Lock()
+	for entry := range c.keys[key] {
+		c.remove(entry)
+	}
+	c.mu.Unlock()
+}
+
+// invalidateCmd removes the cached replies of the first key of cmd, unless it
+// is cacheable.
+func (c *clientCache) invalidateCmd(cmd Cmder) {
+	if _, ok := c.cmds[cmd.Name()]; ok {
+		return
+	}
+	if pos := cmdFirstKeyPos(cmd); pos > 0 && pos < len(cmd.Args()) {
+		c.invalidate(cmd.stringArg(pos))
+	}
+}
+
+func (c *clientCache) forgetConn(connID uint64) {
+	c.mu.Lock()
+	for _, entry := range c.entries {
+		if entry.connID == connID {
+			c.remove(entry)
+		}
+	}
+	c.mu.Unlock()
+}
+
+func (c *clientCache) flush() {
+	c.mu.Lock()
+	c.entries = make(map[string]*cacheEntry)
+	c.keys = make(map[string]map[*cacheEntry]struct{})
+	c.lru.Init()
+	c.mu.Unlock()
+}
@@ -0,0 +1,104 @@
+package redis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestClientCache(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        srv.Addr(),
+		PoolSize:    1,
+		ClientCache: &redis.ClientCacheOptions{},
+	})
+	defer rdb.Close()
+	other := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer other.Close()
+
+	get := func(want string) {
+		t.Helper()
+		got, err := rdb.Get(ctx, "key").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got %q, wanted %q", got, want)
+		}
+	}
+
+	if err := rdb.Get(ctx, "key").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+	if err := other.Set(ctx, "key", "a", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil { // reads the invalidation
+		t.Fatal(err)
+	}
+	get("a")
+
+	if err := other.Set(ctx, "key", "b", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	get("a") // cached, the invalidation is pending on the idle connection
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	get("b")
+
+	// the writes of the client invalidate the cached replies right away
+	if err := rdb.Set(ctx, "key", "c", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	get("c")
+
+	if err := other.HSet(ctx, "hash", "field", "value").Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"field": "value"}
+	for i := 0; i < 2; i++ {
+		got, err := rdb.HGetAll(ctx, "hash").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, wanted %v", got, want)
+		}
+		got["field"] = "modified" // the cached reply is not shared
+	}
+}
+
+func TestClientCacheCommands(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        srv.Addr(),
+		PoolSize:    1,
+		ClientCache: &redis.ClientCacheOptions{Commands: []string{"strlen"}},
+	})
+	defer rdb.Close()
+	other := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer other.Close()
+
+	if err := other.Set(ctx, "key", "a", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	_ = rdb.StrLen(ctx, "key").Err()
+	_ = rdb.Get(ctx, "key").Err()
+	if err := other.Set(ctx, "key", "bb", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	// GET is not cached, and reads the invalidation of STRLEN
+	if got := rdb.Get(ctx, "key").Val(); got != "bb" {
+		t.Fatalf("got %q, wanted bb", got)
+	}
+	if got := rdb.StrLen(ctx, "key").Val(); got != 2 {
+		t.Fatalf("got %d, wanted 2", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"regexp"
 	"strconv"
@@ -41,6 +42,10 @@ type Cmder interface {
 	readTimeout() *time.Duration
 	readReply(rd *proto.Reader) error
 
+	duration() time.Duration
+	setDuration(time.Duration)
+	wireBytes() *cmdBytes
+
 	SetErr(error)
 	Err() error
 }
@@ -71,8 +76,30 @@ func writeCmds(wr *proto.Writer, cmds []Cmder) error {
 	return nil
 }
 
+// writeCmdsBatch writes the first commands of cmds, up to maxCmds commands
+// and until maxBytes bytes are written, and returns their number. A batch has
+// at least one command, and maxCmds or maxBytes of 0 are no limit.
+func writeCmdsBatch(wr *proto.Writer, cmds []Cmder, maxCmds, maxBytes int) (int, error) {
+	start := wr.Written()
+	for i, cmd := range cmds {
+		if err := writeCmd(wr, cmd); err != nil {
+			return i, err
+		}
+		if maxCmds > 0 && i+1 >= maxCmds {
+			return i + 1, nil
+		}
+		if maxBytes > 0 && wr.Written()-start >= int64(maxBytes) {
+			return i + 1, nil
+		}
+	}
+	return len(cmds), nil
+}
+
 func writeCmd(wr *proto.Writer, cmd Cmder) error {
-	return wr.WriteArgs(cmd.Args())
+	n := wr.Written()
+	err := wr.WriteArgs(cmd.Args())
+	cmd.wireBytes().out = uint32(wr.Written() - n)
+	return err
 }
 
 func cmdFirstKeyPos(cmd Cmder) int {
@@ -126,6 +153,9 @@ type baseCmd struct {
 	args   []interface{}
 	err    error
 	keyPos int8
+	pooled bool // whether the command is pooled, see StringCmd.Release
+	wire   cmdBytes
+	dur    time.Duration
 
 	_readTimeout *time.Duration
 }
@@ -159,6 +189,11 @@ func (cmd *baseCmd) Args() []interface{} {
 	return cmd.args
 }
 
+// setArgs replaces the arguments, e.g. to rewrite them in a wrapper.
+func (cmd *baseCmd) setArgs(args []interface{}) {
+	cmd.args = args
+}
+
 func (cmd *baseCmd) stringArg(pos int) string {
 	if pos < 0 || pos >= len(cmd.args) {
 		return ""
@@ -936,6 +971,225 @@ func (cmd *StringCmd) readReply(rd *proto.Reader) (err error) {
 
 //------------------------------------------------------------------------------
 
+// BytesFuncCmd reads a string reply with a callback instead of returning it,
+// e.g. to decode large values without copying them.
+type BytesFuncCmd struct {
+	baseCmd
+
+	fn    func(b []byte) error
+	fnErr error
+}
+
+var _ Cmder = (*BytesFuncCmd)(nil)
+
+// NewBytesFuncCmd returns a command calling fn with its string reply. The
+// bytes of the reply are only valid until fn returns, as they are in the read
+// buffer of the connection when they fit; fn must copy what it retains. fn is
+// not called when the command fails, e.g. with Nil.
+func NewBytesFuncCmd(ctx context.Context, fn func(b []byte) error, args ...interface{}) *BytesFuncCmd {
+	return &BytesFuncCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		fn: fn,
+	}
+}
+
+// Err returns the error of the command, or the error returned by its
+// callback.
+func (cmd *BytesFuncCmd) Err() error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	return cmd.fnErr
+}
+
+func (cmd *BytesFuncCmd) String() string {
+	return cmdString(cmd, nil)
+}
+
+func (cmd *BytesFuncCmd) readReply(rd *proto.Reader) error {
+	// the error of fn is not an error reading the reply, which does not
+	// invalidate the connection
+	return rd.ReadStringFunc(func(b []byte) {
+		cmd.fnErr = cmd.fn(b)
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// WriterCmd copies a string reply to a writer instead of returning it, e.g.
+// to stream large values. Its value is the number of bytes written.
+type WriterCmd struct {
+	baseCmd
+
+	w    io.Writer
+	val  int64
+	wErr error
+}
+
+var _ Cmder = (*WriterCmd)(nil)
+
+// NewWriterCmd returns a command copying its string reply to w, in chunks of
+// the read buffer of the connection. Nothing is written when the command
+// fails, e.g. with Nil. The read timeout applies to the whole reply.
+func NewWriterCmd(ctx context.Context, w io.Writer, args ...interface{}) *WriterCmd {
+	return &WriterCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		w: w,
+	}
+}
+
+func (cmd *WriterCmd) SetVal(val int64) {
+	cmd.val = val
+}
+
+// Val returns the number of bytes written.
+func (cmd *WriterCmd) Val() int64 {
+	return cmd.val
+}
+
+func (cmd *WriterCmd) Result() (int64, error) {
+	return cmd.val, cmd.Err()
+}
+
+// Err returns the error of the command, or the error of the writer.
+func (cmd *WriterCmd) Err() error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+	return cmd.wErr
+}
+
+func (cmd *WriterCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *WriterCmd) readReply(rd *proto.Reader) error {
+	// after an error of the writer, the rest of the reply is read and
+	// discarded so that the connection remains usable
+	return rd.ReadStringChunks(func(b []byte) {
+		if cmd.wErr != nil {
+			return
+		}
+		n, err := cmd.w.Write(b)
+		cmd.val += int64(n)
+		cmd.wErr = err
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// BytesSliceCmd decodes an array of string replies, such as the reply of
+// MGET, into buffers provided by the caller, and reports its nil elements by
+// a bitmap instead of nil interfaces.
+type BytesSliceCmd struct {
+	baseCmd
+
+	val  [][]byte
+	nils []uint64
+	// nilsBuf is the bitmap of the replies of up to 64 elements
+	nilsBuf [1]uint64
+}
+
+var _ Cmder = (*BytesSliceCmd)(nil)
+
+// NewBytesSliceCmd returns a command decoding its reply into dst: element i
+// is appended to dst[i][:0] when i is less than the capacity of dst, so that
+// reusing dst and its buffers for the next commands avoids allocations. The
+// elements of the reply must be copied to be used once dst is reused.
+func NewBytesSliceCmd(ctx context.Context, dst [][]byte, args ...interface{}) *BytesSliceCmd {
+	return &BytesSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		val: dst[:0],
+	}
+}
+
+// SetVal sets the value of the command, whose nil elements are its nil
+// slices.
+func (cmd *BytesSliceCmd) SetVal(val [][]byte) {
+	cmd.val = val
+	cmd.resetNils(len(val))
+	for i, b := range val {
+		if b == nil {
+			cmd.nils[i/64] |= 1 << (i % 64)
+		}
+	}
+}
+
+// Val returns the elements of the reply, which are empty for the nil
+// elements, see IsNil.
+func (cmd *BytesSliceCmd) Val() [][]byte {
+	return cmd.val
+}
+
+func (cmd *BytesSliceCmd) Result() ([][]byte, error) {
+	return cmd.val, cmd.err
+}
+
+// IsNil reports whether element i of the reply is nil, e.g. a missing key of
+// MGET.
+func (cmd *BytesSliceCmd) IsNil(i int) bool {
+	return i/64 < len(cmd.nils) && cmd.nils[i/64]&(1<<(i%64)) != 0
+}
+
+// Nils returns the bitmap of the nil elements of the reply: bit i%64 of word
+// i/64 is set when element i is nil.
+func (cmd *BytesSliceCmd) Nils() []uint64 {
+	return cmd.nils
+}
+
+func (cmd *BytesSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *BytesSliceCmd) resetNils(n int) {
+	words := (n + 63) / 64
+	if words <= len(cmd.nilsBuf) {
+		cmd.nilsBuf = [1]uint64{}
+		cmd.nils = cmd.nilsBuf[:words]
+	} else {
+		cmd.nils = make([]uint64, words)
+	}
+}
+
+func (cmd *BytesSliceCmd) readReply(rd *proto.Reader) error {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+
+	if n <= cap(cmd.val) {
+		cmd.val = cmd.val[:n]
+	} else {
+		cmd.val = append(cmd.val[:cap(cmd.val)], make([][]byte, n-cap(cmd.val))...)
+	}
+	cmd.resetNils(n)
+
+	for i := range cmd.val {
+		b := cmd.val[i][:0]
+		err := rd.ReadStringFunc(func(s []byte) {
+			b = append(b, s...)
+		})
+		if err == Nil {
+			cmd.nils[i/64] |= 1 << (i % 64)
+		} else if err != nil {
+			return err
+		}
+		cmd.val[i] = b
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
 type FloatCmd struct {
 	baseCmd
 
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"sync"
+)
+
+// The commands of the hot methods, such as Get and Set, are pooled: they are
+// reused once released, see StringCmd.Release. Their arguments are allocated
+// as those of the other commands.
+
+var stringCmdPool = sync.Pool{
+	New: func() interface{} {
+		return new(StringCmd)
+	},
+}
+
+func newPooledStringCmd(ctx context.Context, args ...interface{}) *StringCmd {
+	cmd := stringCmdPool.Get().(*StringCmd)
+	cmd.ctx = ctx
+	cmd.args = args
+	cmd.pooled = true
+	return cmd
+}
+
+// Release returns the command to the pool of the commands of the client
+// methods such as Get, which reuse it instead of allocating a new command.
+// Only the caller may keep the command until Release, after which it and its
+// value must not be used: hooks may keep the arguments returned by Args,
+// which are not reused, but not the command itself, e.g. to process it
+// asynchronously. Releasing commands is optional: the
+// commands which are not released are garbage collected, as are the commands
+// of the other methods.
+func (cmd *StringCmd) Release() {
+	if cmd.pooled {
+		*cmd = StringCmd{}
+		stringCmdPool.Put(cmd)
+	}
+}
+
+var statusCmdPool = sync.Pool{
+	New: func() interface{} {
+		return new(StatusCmd)
+	},
+}
+
+func newPooledStatusCmd(ctx context.Context, args ...interface{}) *StatusCmd {
+	cmd := statusCmdPool.Get().(*StatusCmd)
+	cmd.ctx = ctx
+	cmd.args = args
+	cmd.pooled = true
+	return cmd
+}
+
+// Release returns the command to the pool of the commands of the client
+// methods such as Set, see StringCmd.Release.
+func (cmd *StatusCmd) Release() {
+	if cmd.pooled {
+		*cmd = StatusCmd{}
+		statusCmdPool.Put(cmd)
+	}
+}
+
+var intCmdPool = sync.Pool{
+	New: func() interface{} {
+		return new(IntCmd)
+	},
+}
+
+func newPooledIntCmd(ctx context.Context, args ...interface{}) *IntCmd {
+	cmd := intCmdPool.Get().(*IntCmd)
+	cmd.ctx = ctx
+	cmd.args = args
+	cmd.pooled = true
+	return cmd
+}
+
+// Release returns the command to the pool of the commands of the client
+// methods such as Incr, see StringCmd.Release.
+func (cmd *IntCmd) Release() {
+	if cmd.pooled {
+		*cmd = IntCmd{}
+		intCmdPool.Put(cmd)
+	}
+}
+
+var boolCmdPool = sync.Pool{
+	New: func() interface{} {
+		return new(BoolCmd)
+	},
+}
+
+func newPooledBoolCmd(ctx context.Context, args ...interface{}) *BoolCmd {
+	cmd := boolCmdPool.Get().(*BoolCmd)
+	cmd.ctx = ctx
+	cmd.args = args
+	cmd.pooled = true
+	return cmd
+}
+
+// Release returns the command to the pool of the commands of the client
+// methods such as Expire, see StringCmd.Release.
+func (cmd *BoolCmd) Release() {
+	if cmd.pooled {
+		*cmd = BoolCmd{}
+		boolCmdPool.Put(cmd)
+	}
+}
+
+// ReleaseCmds releases the commands which can be released, e.g. the commands
+// of a pipeline after Exec, see StringCmd.Release.
+func ReleaseCmds(cmds ...Cmder) {
+	for _, cmd := range cmds {
+		if cmd, ok := cmd.(interface{ Release() }); ok {
+			cmd.Release()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type argsHook struct {
+	args [][]interface{}
+}
+
+func (h *argsHook) DialHook(next DialHook) DialHook { return next }
+
+func (h *argsHook) ProcessHook(next ProcessHook) ProcessHook {
+	return func(ctx context.Context, cmd Cmder) error {
+		h.args = append(h.args, cmd.Args())
+		return next(ctx, cmd)
+	}
+}
+
+func (h *argsHook) ProcessPipelineHook(next ProcessPipelineHook) ProcessPipelineHook {
+	return next
+}
+
+func TestHotCommandsArgs(t *testing.T) {
+	rdb := NewClientStub([]byte(":1\r\n"))
+	hook := new(argsHook)
+	rdb.Cmdable.(*Client).AddHook(hook)
+
+	key := string([]byte("key")) // not a constant
+	rdb.Get(ctx, key)
+	rdb.Set(ctx, key, "value", 90*time.Second)
+	rdb.Set(ctx, key, 1, 1500*time.Millisecond)
+	rdb.Set(ctx, key, "value", KeepTTL)
+	rdb.HGet(ctx, key, "field")
+	rdb.HSet(ctx, key, "f1", 1, "f2", 2)
+	rdb.HSet(ctx, key, map[string]interface{}{"f1": 1})
+	rdb.Expire(ctx, key, time.Hour)
+	rdb.ExpireNX(ctx, key, 300*time.Second)
+	rdb.Incr(ctx, key)
+
+	want := [][]interface{}{
+		{"get", "key"},
+		{"set", "key", "value", "ex", int64(90)},
+		{"set", "key", 1, "px", int64(1500)},
+		{"set", "key", "value", "keepttl"},
+		{"hget", "key", "field"},
+		{"hset", "key", "f1", 1, "f2", 2},
+		{"hset", "key", "f1", 1},
+		{"expire", "key", int64(3600)},
+		{"expire", "key", int64(300), "NX"},
+		{"incr", "key"},
+	}
+	if !reflect.DeepEqual(hook.args, want) {
+		t.Fatalf("got %v, wanted %v", hook.args, want)
+	}
+
+	// appending to the arguments does not modify the command
+	cmd := rdb.Get(ctx, key)
+	args := append(cmd.Args(), "extra")
+	args[1] = "other"
+	if got := cmd.Args(); len(got) != 2 || got[1] != "key" {
+		t.Fatalf("got %v", got)
+	}
+
+	// the arguments do not alias the storage of the caller
+	keys := []string{"k1", "k2"}
+	mget := rdb.MGetBytes(ctx, nil, keys...)
+	keys[0] = "other"
+	if got := mget.Args(); !reflect.DeepEqual(got, []interface{}{"mget", "k1", "k2"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestCmdRelease(t *testing.T) {
+	rdb := NewClientStub([]byte("$5\r\nhello\r\n"))
+
+	for i := 0; i < 100; i++ {
+		cmd := rdb.Get(ctx, "key")
+		if cmd.Val() != "hello" || cmd.stringArg(1) != "key" {
+			t.Fatalf("got %v", cmd)
+		}
+		cmd.Release()
+		if cmd.Val() != "" || cmd.Args() != nil || cmd.pooled {
+			t.Fatalf("got %v after Release", cmd)
+		}
+		// releasing again or the commands which are not pooled is a no-op
+		cmd.Release()
+		ReleaseCmds(rdb.GetRange(ctx, "key", 0, 1), NewCmd(ctx, "get", "key"))
+	}
+
+	cmds, err := rdb.Pipelined(ctx, func(pipe Pipeliner) error {
+		pipe.Get(ctx, "key")
+		pipe.Get(ctx, "key")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ReleaseCmds(cmds...)
+	for _, cmd := range cmds {
+		if cmd.(*StringCmd).Val() != "" {
+			t.Fatalf("got %v after ReleaseCmds", cmd)
+		}
+	}
+}
+
+func TestCmdReleaseKeptArgs(t *testing.T) {
+	rdb := NewClientStub([]byte("$5\r\nhello\r\n"))
+	hook := new(argsHook)
+	rdb.Cmdable.(*Client).AddHook(hook)
+
+	cmds := make(map[*StringCmd]bool)
+	for i := 0; i < 100; i++ {
+		cmd := rdb.Get(ctx, "key"+strconv.Itoa(i))
+		cmd.Release()
+		cmds[cmd] = true
+	}
+	// the commands are reused with a hook reading their arguments, which
+	// are not
+	if len(cmds) == 100 {
+		t.Fatal("the released commands were not reused")
+	}
+	for i, args := range hook.args {
+		if want := []interface{}{"get", "key" + strconv.Itoa(i)}; !reflect.DeepEqual(args, want) {
+			t.Fatalf("got %v, wanted %v", args, want)
+		}
+	}
+}
+
+func TestHExpireArgs(t *testing.T) {
+	rdb := NewClientStub([]byte("*1\r\n:1\r\n"))
+	hook := new(argsHook)
+	rdb.Cmdable.(*Client).AddHook(hook)
+
+	rdb.HExpire(ctx, "key", 90*time.Second, "field")
+	rdb.HExpire(ctx, "key", 1500*time.Millisecond, "field")
+	rdb.HExpire(ctx, "key", 10, "field")
+	rdb.HExpireWithArgs(ctx, "key", time.Hour, HExpireArgs{NX: true}, "field")
+
+	want := [][]interface{}{
+		{"HEXPIRE", "key", int64(90), "FIELDS", 1, "field"},
+		{"HEXPIRE", "key", int64(1), "FIELDS", 1, "field"},
+		{"HEXPIRE", "key", int64(1), "FIELDS", 1, "field"},
+		{"HEXPIRE", "key", int64(3600), "NX", "FIELDS", 1, "field"},
+	}
+	if !reflect.DeepEqual(hook.args, want) {
+		t.Fatalf("got %v, wanted %v", hook.args, want)
+	}
+}
@@ -0,0 +1,196 @@
+package redis
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+const redacted = "(redacted)"
+
+// CmdMarshaler renders the commands for logs, redacting their secrets such as
+// the passwords of AUTH and HELLO. The value of the commands is not rendered.
+type CmdMarshaler struct {
+	// MaxArgLen truncates the arguments longer than MaxArgLen bytes.
+	// Default is no truncation.
+	MaxArgLen int
+	// MaxArgs truncates the commands with more than MaxArgs arguments,
+	// including the command name. Default is no truncation.
+	MaxArgs int
+}
+
+// DefaultCmdMarshaler renders the commands for their MarshalText and
+// MarshalJSON methods.
+var DefaultCmdMarshaler = &CmdMarshaler{}
+
+// marshaledCmd is the part of Cmder rendered by CmdMarshaler.
+type marshaledCmd interface {
+	FullName() string
+	Args() []interface{}
+	Err() error
+	duration() time.Duration
+}
+
+// Args returns the arguments of cmd as rendered by the marshaler.
+func (m *CmdMarshaler) Args(cmd Cmder) []string {
+	return m.args(cmd)
+}
+
+func (m *CmdMarshaler) args(cmd marshaledCmd) []string {
+	args := cmd.Args()
+	n := len(args)
+	if m.MaxArgs > 0 && n > m.MaxArgs {
+		n = m.MaxArgs
+	}
+
+	secrets := secretArgs(args)
+	ss := make([]string, 0, n+1)
+	for i := 0; i < n; i++ {
+		if secrets[i] {
+			ss = append(ss, redacted)
+			continue
+		}
+		s := string(internal.AppendArg(nil, args[i]))
+		if m.MaxArgLen > 0 && len(s) > m.MaxArgLen {
+			s = s[:m.MaxArgLen] + "...(" + strconv.Itoa(len(s)-m.MaxArgLen) + " more bytes)"
+		}
+		ss = append(ss, s)
+	}
+	if n < len(args) {
+		ss = append(ss, "...("+strconv.Itoa(len(args)-n)+" more args)")
+	}
+	return ss
+}
+
+// Text renders cmd as its arguments, the duration of its processing and its
+// error, e.g. "set key value ex 10 (1.2ms): READONLY ...".
+func (m *CmdMarshaler) Text(cmd Cmder) []byte {
+	return m.marshalText(cmd)
+}
+
+func (m *CmdMarshaler) marshalText(cmd marshaledCmd) []byte {
+	b := []byte(strings.Join(m.args(cmd), " "))
+	if d := cmd.duration(); d > 0 {
+		b = append(b, " ("...)
+		b = append(b, d.String()...)
+		b = append(b, ')')
+	}
+	if err := cmd.Err(); err != nil {
+		b = append(b, ": "...)
+		b = append(b, err.Error()...)
+	}
+	return b
+}
+
+// JSON renders cmd as a JSON object with its name, arguments, duration and
+// error, e.g.
+//
+//	{"name":"set","args":["set","key","value"],"duration":"1.2ms","error":"READONLY ..."}
+//
+// The duration and error are omitted when they are unknown or nil.
+func (m *CmdMarshaler) JSON(cmd Cmder) ([]byte, error) {
+	return m.marshalJSON(cmd)
+}
+
+func (m *CmdMarshaler) marshalJSON(cmd marshaledCmd) ([]byte, error) {
+	v := struct {
+		Name     string   `json:"name"`
+		Args     []string `json:"args"`
+		Duration string   `json:"duration,omitempty"`
+		Error    string   `json:"error,omitempty"`
+	}{
+		Name: cmd.FullName(),
+		Args: m.args(cmd),
+	}
+	if d := cmd.duration(); d > 0 {
+		v.Duration = d.String()
+	}
+	if err := cmd.Err(); err != nil {
+		v.Error = err.Error()
+	}
+	return json.Marshal(v)
+}
+
+// MarshalText renders the command with DefaultCmdMarshaler.
+func (cmd *baseCmd) MarshalText() ([]byte, error) {
+	return DefaultCmdMarshaler.marshalText(cmd), nil
+}
+
+// MarshalJSON renders the command with DefaultCmdMarshaler.
+func (cmd *baseCmd) MarshalJSON() ([]byte, error) {
+	return DefaultCmdMarshaler.marshalJSON(cmd)
+}
+
+// duration returns the time it took to process the command, including its
+// retries, or 0 when it was not processed.
+func (cmd *baseCmd) duration() time.Duration {
+	return cmd.dur
+}
+
+func (cmd *baseCmd) setDuration(d time.Duration) {
+	cmd.dur = d
+}
+
+// secretArgs returns the positions of the secret arguments of a command.
+func secretArgs(args []interface{}) map[int]bool {
+	if len(args) < 2 {
+		return nil
+	}
+	arg := func(i int) string {
+		if i >= len(args) {
+			return ""
+		}
+		s, _ := args[i].(string)
+		return strings.ToLower(s)
+	}
+
+	secrets := make(map[int]bool)
+	switch arg(0) {
+	case "auth":
+		// AUTH [username] password
+		secrets[len(args)-1] = true
+	case "hello":
+		// HELLO protover AUTH username password
+		for i := 2; i < len(args); i++ {
+			if arg(i) == "auth" {
+				secrets[i+2] = true
+				i += 2
+			}
+		}
+	case "migrate":
+		// MIGRATE ... AUTH password | AUTH2 username password
+		for i := 6; i < len(args); i++ {
+			switch arg(i) {
+			case "auth":
+				secrets[i+1] = true
+				i++
+			case "auth2":
+				secrets[i+2] = true
+				i += 2
+			}
+		}
+	case "config":
+		// CONFIG SET requirepass password masterauth password
+		if arg(1) == "set" {
+			for i := 2; i+1 < len(args); i += 2 {
+				switch arg(i) {
+				case "requirepass", "masterauth":
+					secrets[i+1] = true
+				}
+			}
+		}
+	case "acl":
+		// ACL SETUSER username >password #hash
+		if arg(1) == "setuser" {
+			for i := 3; i < len(args); i++ {
+				if s := arg(i); strings.HasPrefix(s, ">") || strings.HasPrefix(s, "<") || strings.HasPrefix(s, "#") || strings.HasPrefix(s, "!") {
+					secrets[i] = true
+				}
+			}
+		}
+	}
+	return secrets
+}
@@ -1,6 +1,7 @@
 package redis_test
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -93,4 +94,35 @@ var _ = Describe("Cmd", func() {
 		_, err := cmd.Result()
 		Expect(err).To(Equal(e))
 	})
+
+	It("marshals commands without their secrets", func() {
+		err := client.Set(ctx, "key", "value", 0).Err()
+		Expect(err).NotTo(HaveOccurred())
+
+		get := client.Get(ctx, "missing")
+		b, err := json.Marshal(get)
+		Expect(err).NotTo(HaveOccurred())
+		var v map[string]interface{}
+		Expect(json.Unmarshal(b, &v)).NotTo(HaveOccurred())
+		Expect(v["name"]).To(Equal("get"))
+		Expect(v["args"]).To(Equal([]interface{}{"get", "missing"}))
+		Expect(v["error"]).To(Equal("redis: nil"))
+		Expect(v["duration"]).NotTo(BeEmpty())
+
+		auth := redis.NewStatusCmd(ctx, "auth", "user", "secret")
+		b, err = auth.MarshalText()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("auth user (redacted)"))
+
+		hello := redis.NewMapStringInterfaceCmd(ctx, "hello", 3, "AUTH", "user", "secret", "SETNAME", "name")
+		b, err = hello.MarshalText()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("hello 3 AUTH user (redacted) SETNAME name"))
+	})
+
+	It("truncates the marshaled arguments", func() {
+		m := &redis.CmdMarshaler{MaxArgLen: 5, MaxArgs: 3}
+		cmd := redis.NewIntCmd(ctx, "rpush", "list", "0123456789", "a", "b")
+		Expect(m.Args(cmd)).To(Equal([]string{"rpush", "list", "01234...(5 more bytes)", "...(2 more args)"}))
+	})
 })
@@ -780,6 +780,23 @@ var _ = Describe("Commands", func() {
 			Expect(pttl.Val()).To(BeNumerically("~", expiration, 100*time.Millisecond))
 		})
 
+		It("should ExpireMany", func() {
+			Expect(client.Set(ctx, "key1", "Hello", 0).Err()).NotTo(HaveOccurred())
+			Expect(client.Set(ctx, "key2", "World", 0).Err()).NotTo(HaveOccurred())
+
+			res, err := client.ExpireMany(ctx, 10*time.Second, "key1", "nonexistent", "key2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]bool{true, false, true}))
+
+			ttl := client.TTL(ctx, "key2")
+			Expect(ttl.Err()).NotTo(HaveOccurred())
+			Expect(ttl.Val()).To(Equal(10 * time.Second))
+
+			res, err = client.ExpireMany(ctx, 10*time.Second)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+
 		It("should PExpireAt", func() {
 			set := client.Set(ctx, "key", "Hello", 0)
 			Expect(set.Err()).NotTo(HaveOccurred())
@@ -2570,7 +2587,7 @@ var _ = Describe("Commands", func() {
 				Expect(sadd.Err()).NotTo(HaveOccurred())
 			}
 
-			res, err := client.HExpire(ctx, "myhash", 10, "key1", "key200").Result()
+			res, err := client.HExpire(ctx, "myhash", 10*time.Second, "key1", "key200").Result()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res).To(Equal([]int64{1, -2}))
 
@@ -2607,7 +2624,7 @@ var _ = Describe("Commands", func() {
 				Expect(sadd.Err()).NotTo(HaveOccurred())
 			}
 
-			res, err := client.HExpire(ctx, "myhash", 10, "key1", "key200").Result()
+			res, err := client.HExpire(ctx, "myhash", 10*time.Second, "key1", "key200").Result()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res).To(Equal([]int64{1, -2}))
 
@@ -2625,7 +2642,7 @@ var _ = Describe("Commands", func() {
 				Expect(sadd.Err()).NotTo(HaveOccurred())
 			}
 
-			res, err := client.HExpire(ctx, "myhash", 10, "key1", "key200").Result()
+			res, err := client.HExpire(ctx, "myhash", 10*time.Second, "key1", "key200").Result()
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res).To(Equal([]int64{1, -2}))
 
@@ -2633,6 +2650,71 @@ var _ = Describe("Commands", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res[0]).To(BeNumerically("~", 10*time.Second.Milliseconds(), 1))
 		})
+		It("should HGetDel", Label("hash-expiration", "NonRedisEnterprise"), func() {
+			err := client.HSet(ctx, "myhash", "key1", "hello1", "key2", "hello2").Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := client.HGetDel(ctx, "myhash", "key1", "key3").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"hello1", nil}))
+
+			keys, err := client.HKeys(ctx, "myhash").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keys).To(Equal([]string{"key2"}))
+		})
+
+		It("should HGetEX", Label("hash-expiration", "NonRedisEnterprise"), func() {
+			err := client.HSet(ctx, "myhash", "key1", "hello1", "key2", "hello2").Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			res, err := client.HGetEXWithArgs(ctx, "myhash", redis.HGetEXArgs{TTL: 10 * time.Second}, "key1", "key3").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"hello1", nil}))
+
+			ttl, err := client.HTTL(ctx, "myhash", "key1", "key2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal([]int64{10, -1}))
+
+			res, err = client.HGetEXWithArgs(ctx, "myhash", redis.HGetEXArgs{Persist: true}, "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"hello1"}))
+
+			ttl, err = client.HTTL(ctx, "myhash", "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal([]int64{-1}))
+		})
+
+		It("should HSetEX", Label("hash-expiration", "NonRedisEnterprise"), func() {
+			res, err := client.HSetEX(ctx, "myhash", "key1", "hello1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(int64(1)))
+
+			res, err = client.HSetEXWithArgs(ctx, "myhash", redis.HSetEXArgs{
+				Mode: "FNX",
+				TTL:  10 * time.Second,
+			}, map[string]interface{}{"key1": "hello1"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(int64(0)))
+
+			res, err = client.HSetEXWithArgs(ctx, "myhash", redis.HSetEXArgs{
+				Mode: "FXX",
+				TTL:  10 * time.Second,
+			}, "key1", "hello2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(int64(1)))
+
+			ttl, err := client.HTTL(ctx, "myhash", "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl).To(Equal([]int64{10}))
+
+			res, err = client.HSetEXWithArgs(ctx, "myhash", redis.HSetEXArgs{KeepTTL: true}, "key1", "hello3").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(int64(1)))
+
+			ttl, err = client.HTTL(ctx, "myhash", "key1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ttl[0]).To(BeNumerically("~", 10, 1))
+		})
 	})
 
 	Describe("hyperloglog", func() {
@@ -0,0 +1,135 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/redis/go-redis/v9/internal/pool"
+)
+
+// ConnEventType is the type of a ConnEvent.
+type ConnEventType int
+
+const (
+	// ConnConnected is the event of a connection dialed by the client.
+	ConnConnected ConnEventType = iota
+	// ConnHandshakeCompleted is the event of a connection ready to process
+	// the commands, once authenticated and set up, e.g. with HELLO, SELECT
+	// and Options.OnConnect.
+	ConnHandshakeCompleted
+	// ConnDisconnected is the event of a connection closed by the client.
+	ConnDisconnected
+	// ConnReconnectAttempt is the event of a dial after a failed dial.
+	ConnReconnectAttempt
+	// ConnAcquired is the event of a connection taken from the pool to
+	// process commands, see Options.OnConnectionUse. A connection dialed for
+	// the commands is acquired before its handshake.
+	ConnAcquired
+	// ConnReleased is the event of a connection given back to the pool, see
+	// Options.OnConnectionUse. It precedes ConnDisconnected when the
+	// connection is closed rather than kept idle.
+	ConnReleased
+)
+
+func (t ConnEventType) String() string {
+	switch t {
+	case ConnConnected:
+		return "connected"
+	case ConnHandshakeCompleted:
+		return "handshake completed"
+	case ConnDisconnected:
+		return "disconnected"
+	case ConnReconnectAttempt:
+		return "reconnect attempt"
+	case ConnAcquired:
+		return "acquired"
+	case ConnReleased:
+		return "released"
+	}
+	return "unknown"
+}
+
+// ConnEvent is an event of a connection, see Options.OnConnectionEvent and
+// Options.OnConnectionUse.
+type ConnEvent struct {
+	Type ConnEventType
+	// Addr is the remote address of the connection, or the address dialed
+	// for ConnReconnectAttempt.
+	Addr string
+	// ConnID is the id of the connection, unique in the process, or 0 for
+	// ConnReconnectAttempt.
+	ConnID uint64
+	// Err is, for ConnDisconnected, the reason the connection is closed: the
+	// error which broke the connection, ErrConnStale, ErrClosed when the
+	// client is closed, or nil when a healthy connection is not needed. For
+	// ConnReconnectAttempt, it is the error of the previous dial.
+	Err error
+}
+
+// ErrConnStale is the reason of the ConnDisconnected events of the
+// connections closed as they are idle or used for too long, see
+// Options.ConnMaxIdleTime and Options.ConnMaxLifetime, or found closed by
+// the server.
+var ErrConnStale = pool.ErrConnStale
+
+func newConnEvent(typ ConnEventType, opt *Options, cn *pool.Conn, err error) ConnEvent {
+	addr := opt.Addr
+	if remoteAddr := cn.RemoteAddr(); remoteAddr != nil {
+		addr = remoteAddr.String()
+	}
+	return ConnEvent{
+		Type:   typ,
+		Addr:   addr,
+		ConnID: cn.ID(),
+		Err:    err,
+	}
+}
+
+// connEvents reports the events of the connections of poolOpt to
+// opt.OnConnectionEvent.
+func (opt *Options) connEvents(poolOpt *pool.Options) {
+	onEvent := opt.OnConnectionEvent
+
+	var mu sync.Mutex
+	var dialErr error
+	dialer := poolOpt.Dialer
+	poolOpt.Dialer = func(ctx context.Context) (net.Conn, error) {
+		mu.Lock()
+		lastErr := dialErr
+		mu.Unlock()
+		if lastErr != nil {
+			onEvent(ConnEvent{
+				Type: ConnReconnectAttempt,
+				Addr: opt.Addr,
+				Err:  lastErr,
+			})
+		}
+
+		conn, err := dialer(ctx)
+
+		mu.Lock()
+		dialErr = err
+		mu.Unlock()
+		return conn, err
+	}
+
+	poolOpt.OnDial = func(cn *pool.Conn) {
+		onEvent(newConnEvent(ConnConnected, opt, cn, nil))
+	}
+	poolOpt.OnClose = func(cn *pool.Conn, reason error) {
+		onEvent(newConnEvent(ConnDisconnected, opt, cn, reason))
+	}
+}
+
+// connUseEvents reports the connections of poolOpt acquired and released to
+// opt.OnConnectionUse.
+func (opt *Options) connUseEvents(poolOpt *pool.Options) {
+	onUse := opt.OnConnectionUse
+	poolOpt.OnAcquire = func(cn *pool.Conn) {
+		onUse(newConnEvent(ConnAcquired, opt, cn, nil))
+	}
+	poolOpt.OnRelease = func(cn *pool.Conn) {
+		onUse(newConnEvent(ConnReleased, opt, cn, nil))
+	}
+}
@@ -0,0 +1,141 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+type connEvents struct {
+	mu     sync.Mutex
+	events []redis.ConnEvent
+}
+
+func (e *connEvents) add(ev redis.ConnEvent) {
+	e.mu.Lock()
+	e.events = append(e.events, ev)
+	e.mu.Unlock()
+}
+
+func (e *connEvents) types() []redis.ConnEventType {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	types := make([]redis.ConnEventType, len(e.events))
+	for i, ev := range e.events {
+		types[i] = ev.Type
+	}
+	return types
+}
+
+func TestOnConnectionEvent(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var events connEvents
+	rdb := redis.NewClient(&redis.Options{
+		Addr:              srv.Addr(),
+		OnConnectionEvent: events.add,
+	})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := events.types()
+	want := []redis.ConnEventType{redis.ConnConnected, redis.ConnHandshakeCompleted, redis.ConnDisconnected}
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, wanted %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got events %v, wanted %v", got, want)
+		}
+	}
+	for _, ev := range events.events {
+		if ev.ConnID == 0 || ev.ConnID != events.events[0].ConnID {
+			t.Fatalf("got connection %d, wanted %d", ev.ConnID, events.events[0].ConnID)
+		}
+		if ev.Addr != srv.Addr() {
+			t.Fatalf("got address %q, wanted %q", ev.Addr, srv.Addr())
+		}
+	}
+	if err := events.events[2].Err; err != redis.ErrClosed {
+		t.Fatalf("got reason %v, wanted ErrClosed", err)
+	}
+}
+
+func TestOnConnectionEventReconnectAttempt(t *testing.T) {
+	dialErr := errors.New("connection refused")
+	var events connEvents
+	rdb := redis.NewClient(&redis.Options{
+		Addr: "db:6379",
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, dialErr
+		},
+		OnConnectionEvent: events.add,
+		MaxRetries:        -1,
+	})
+	defer rdb.Close()
+
+	_ = rdb.Ping(ctx).Err()
+	if got := events.types(); len(got) != 0 {
+		t.Fatalf("got events %v after the first dial", got)
+	}
+	_ = rdb.Ping(ctx).Err()
+	if got := events.types(); len(got) != 1 || got[0] != redis.ConnReconnectAttempt {
+		t.Fatalf("got events %v, wanted a reconnect attempt", got)
+	}
+	if ev := events.events[0]; ev.Addr != "db:6379" || ev.Err != dialErr {
+		t.Fatalf("got %+v, wanted the address and error of the failed dial", ev)
+	}
+}
+
+func TestOnConnectionUse(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var events, uses connEvents
+	rdb := redis.NewClient(&redis.Options{
+		Addr:              srv.Addr(),
+		OnConnectionEvent: events.add,
+		OnConnectionUse:   uses.add,
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Ping(ctx)
+		pipe.Ping(ctx)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := uses.types()
+	want := []redis.ConnEventType{redis.ConnAcquired, redis.ConnReleased, redis.ConnAcquired, redis.ConnReleased}
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, wanted %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got events %v, wanted %v", got, want)
+		}
+	}
+	for _, ev := range uses.events {
+		if ev.ConnID == 0 || ev.ConnID != uses.events[0].ConnID {
+			t.Fatalf("got connection %d, wanted %d", ev.ConnID, uses.events[0].ConnID)
+		}
+	}
+	if got := events.types(); len(got) != 2 {
+		t.Fatalf("got events %v, wanted the dial and the handshake", got)
+	}
+}
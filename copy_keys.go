@@ -0,0 +1,340 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+type CopyKeysOptions struct {
+	// Match is the SCAN pattern of the copied keys. Default is all the keys.
+	Match string
+	// Type only copies the keys of this type, e.g. "hash". Default is all
+	// the types.
+	Type string
+	// BatchSize is the COUNT of SCAN and the number of keys copied per
+	// pipeline. Default is 100.
+	BatchSize int
+	// Replace overwrites the keys existing in the destination. By default they
+	// are skipped.
+	Replace bool
+	// Migrate copies the keys with MIGRATE COPY, sent by the source servers to
+	// the destination servers, which must be reachable from the source
+	// servers with the addresses of the destination client. By default the
+	// keys go through the client with DUMP and RESTORE. A Ring destination
+	// only supports DUMP and RESTORE.
+	Migrate bool
+	// MigrateTimeout is the timeout of MIGRATE. Default is 5 seconds.
+	MigrateTimeout time.Duration
+	// RateLimit is the maximum number of keys copied per second. Default is no
+	// limit.
+	RateLimit int
+	// OnProgress is called after each batch of keys with the progress of the
+	// copy. The batches of the servers of a cluster or ring are copied
+	// concurrently, but the calls are serialized.
+	OnProgress func(progress CopyKeysProgress)
+}
+
+// CopyKeysProgress is the progress of CopyKeys.
+type CopyKeysProgress struct {
+	// Scanned is the number of keys scanned in the source.
+	Scanned int64
+	// Copied is the number of keys copied.
+	Copied int64
+	// Skipped is the number of keys that exist in the destination, without
+	// Replace, or were deleted from the source before being copied.
+	Skipped int64
+	// Failed is the number of keys that failed to be copied.
+	Failed int64
+	// LastError is the last error copying a key.
+	LastError error
+}
+
+// CopyKeys copies the keys of src matching opt to dst, preserving their TTL.
+// src and dst can be a Client, a ClusterClient or a Ring: the keys of every
+// master of a cluster, or every shard of a ring, are scanned concurrently.
+// The keys modified during the copy may be copied before or after their
+// modification.
+//
+// It returns an error when scanning the source fails, and counts the keys it
+// failed to copy in CopyKeysProgress.Failed.
+func CopyKeys(ctx context.Context, src, dst UniversalClient, opt *CopyKeysOptions) (*CopyKeysProgress, error) {
+	c := &keyCopier{dst: dst}
+	if opt != nil {
+		c.opt = *opt
+	}
+	if c.opt.BatchSize <= 0 {
+		c.opt.BatchSize = 100
+	}
+	if c.opt.MigrateTimeout <= 0 {
+		c.opt.MigrateTimeout = 5 * time.Second
+	}
+	if c.opt.RateLimit > 0 {
+		c.pacer = newPacer(c.opt.RateLimit)
+	}
+	if c.opt.Migrate {
+		if _, ok := dst.(*Ring); ok {
+			return nil, fmt.Errorf("redis: CopyKeys can't MIGRATE to a Ring")
+		}
+	}
+
+	err := forEachScanNode(ctx, src, func(ctx context.Context, node *Client) error {
+		return scanKeys(ctx, node, c.opt.Match, c.opt.Type, c.opt.BatchSize, func(keys []string) error {
+			return c.copy(ctx, node, keys)
+		})
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	progress := c.progress
+	return &progress, err
+}
+
+type keyCopier struct {
+	dst   UniversalClient
+	opt   CopyKeysOptions
+	pacer *pacer
+
+	mu       sync.Mutex
+	progress CopyKeysProgress
+}
+
+func (c *keyCopier) copy(ctx context.Context, node *Client, keys []string) error {
+	if c.pacer != nil {
+		if err := c.pacer.wait(ctx, len(keys)); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	if c.opt.Migrate {
+		errs = c.migrate(ctx, node, keys)
+	} else {
+		errs = c.restore(ctx, node, keys)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.progress.Scanned += int64(len(keys))
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			c.progress.Copied++
+		case err == errKeySkipped:
+			c.progress.Skipped++
+		default:
+			c.progress.Failed++
+			c.progress.LastError = err
+		}
+	}
+	if c.opt.OnProgress != nil {
+		c.opt.OnProgress(c.progress)
+	}
+	return nil
+}
+
+// errKeySkipped marks the keys that were not copied on purpose.
+var errKeySkipped = fmt.Errorf("redis: key skipped")
+
+func (c *keyCopier) restore(ctx context.Context, node *Client, keys []string) []error {
+	dumps := make([]*StringCmd, len(keys))
+	ttls := make([]*DurationCmd, len(keys))
+	_, _ = node.Pipelined(ctx, func(pipe Pipeliner) error {
+		for i, key := range keys {
+			dumps[i] = pipe.Dump(ctx, key)
+			ttls[i] = pipe.PTTL(ctx, key)
+		}
+		return nil
+	})
+
+	errs := make([]error, len(keys))
+	restores := make([]*StatusCmd, len(keys))
+	_, _ = c.dst.Pipelined(ctx, func(pipe Pipeliner) error {
+		for i, key := range keys {
+			dump, err := dumps[i].Result()
+			if err == Nil {
+				errs[i] = errKeySkipped
+				continue
+			}
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			ttl, err := ttls[i].Result()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			switch {
+			case ttl == -2:
+				// expired between DUMP and PTTL
+				errs[i] = errKeySkipped
+				continue
+			case ttl < 0:
+				ttl = 0
+			}
+			if c.opt.Replace {
+				restores[i] = pipe.RestoreReplace(ctx, key, ttl, dump)
+			} else {
+				restores[i] = pipe.Restore(ctx, key, ttl, dump)
+			}
+		}
+		return nil
+	})
+
+	for i, cmd := range restores {
+		if cmd == nil {
+			continue
+		}
+		err := cmd.Err()
+		if HasErrorPrefix(err, "BUSYKEY") {
+			err = errKeySkipped
+		}
+		errs[i] = err
+	}
+	return errs
+}
+
+func (c *keyCopier) migrate(ctx context.Context, node *Client, keys []string) []error {
+	errs := make([]error, len(keys))
+	cmds := make([]*Cmd, len(keys))
+	_, _ = node.Pipelined(ctx, func(pipe Pipeliner) error {
+		for i, key := range keys {
+			target, err := c.migrateTarget(ctx, key)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			host, port, err := net.SplitHostPort(target.Addr)
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if host == "" {
+				host = "localhost"
+			}
+
+			args := []interface{}{
+				"migrate", host, port, key, target.DB,
+				c.opt.MigrateTimeout.Milliseconds(), "copy",
+			}
+			if c.opt.Replace {
+				args = append(args, "replace")
+			}
+			switch {
+			case target.Username != "":
+				args = append(args, "auth2", target.Username, target.Password)
+			case target.Password != "":
+				args = append(args, "auth", target.Password)
+			}
+			cmd := NewCmd(ctx, args...)
+			// MIGRATE blocks up to its timeout
+			cmd.setReadTimeout(c.opt.MigrateTimeout + time.Second)
+			_ = pipe.Process(ctx, cmd)
+			cmds[i] = cmd
+		}
+		return nil
+	})
+
+	for i, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		s, err := cmd.Text()
+		switch {
+		case err == nil && s == "NOKEY":
+			err = errKeySkipped
+		case HasErrorPrefix(err, "BUSYKEY"):
+			err = errKeySkipped
+		}
+		errs[i] = err
+	}
+	return errs
+}
+
+// migrateTarget returns the options of the destination server of key.
+func (c *keyCopier) migrateTarget(ctx context.Context, key string) (*Options, error) {
+	if cluster, ok := c.dst.(*ClusterClient); ok {
+		node, err := cluster.MasterForKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return node.Options(), nil
+	}
+	if client, ok := c.dst.(*Client); ok {
+		return client.Options(), nil
+	}
+	return nil, fmt.Errorf("redis: CopyKeys can't MIGRATE to %T", c.dst)
+}
+
+// forEachScanNode calls fn with the client of every server holding keys of
+// client, concurrently for clusters and rings.
+func forEachScanNode(ctx context.Context, client UniversalClient, fn func(ctx context.Context, node *Client) error) error {
+	switch client := client.(type) {
+	case *Client:
+		return fn(ctx, client)
+	case *ClusterClient:
+		return client.ForEachMaster(ctx, fn)
+	case *Ring:
+		return client.ForEachShard(ctx, fn)
+	}
+	return fmt.Errorf("redis: can't scan the keys of %T", client)
+}
+
+// scanKeys calls fn with the batches of keys of node matching match and typ.
+func scanKeys(ctx context.Context, node *Client, match, typ string, count int, fn func(keys []string) error) error {
+	var cursor uint64
+	for {
+		var keys []string
+		var err error
+		if typ != "" {
+			keys, cursor, err = node.ScanType(ctx, cursor, match, int64(count), typ).Result()
+		} else {
+			keys, cursor, err = node.Scan(ctx, cursor, match, int64(count)).Result()
+		}
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// pacer limits the rate of an operation shared by goroutines.
+type pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newPacer(perSecond int) *pacer {
+	return &pacer{interval: time.Second / time.Duration(perSecond)}
+}
+
+// wait waits for the turn of n operations.
+func (p *pacer) wait(ctx context.Context, n int) error {
+	p.mu.Lock()
+	now := time.Now()
+	if p.next.Before(now) {
+		p.next = now
+	}
+	wait := p.next.Sub(now)
+	p.next = p.next.Add(time.Duration(n) * p.interval)
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return internal.Sleep(ctx, wait)
+}
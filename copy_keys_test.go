@@ -0,0 +1,81 @@
+package redis_test
+
+import (
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("CopyKeys", func() {
+	var src, dst *redis.Client
+
+	BeforeEach(func() {
+		src = redis.NewClient(redisOptions())
+		Expect(src.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		opt := redisOptions()
+		opt.DB = 14
+		dst = redis.NewClient(opt)
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		Expect(src.Set(ctx, "user:1", "one", 0).Err()).NotTo(HaveOccurred())
+		Expect(src.Set(ctx, "user:2", "two", time.Hour).Err()).NotTo(HaveOccurred())
+		Expect(src.HSet(ctx, "user:3", "name", "three").Err()).NotTo(HaveOccurred())
+		Expect(src.Set(ctx, "other", "value", 0).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		Expect(src.Close()).NotTo(HaveOccurred())
+		Expect(dst.Close()).NotTo(HaveOccurred())
+	})
+
+	for _, migrate := range []bool{false, true} {
+		migrate := migrate
+
+		It("should copy the matching keys", func() {
+			var calls int
+			progress, err := redis.CopyKeys(ctx, src, dst, &redis.CopyKeysOptions{
+				Match:      "user:*",
+				Migrate:    migrate,
+				OnProgress: func(redis.CopyKeysProgress) { calls++ },
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress.Scanned).To(Equal(int64(3)))
+			Expect(progress.Copied).To(Equal(int64(3)))
+			Expect(calls).To(BeNumerically(">", 0))
+
+			Expect(dst.Get(ctx, "user:1").Val()).To(Equal("one"))
+			Expect(dst.TTL(ctx, "user:2").Val()).To(BeNumerically("~", time.Hour, time.Minute))
+			Expect(dst.HGet(ctx, "user:3", "name").Val()).To(Equal("three"))
+			Expect(dst.Exists(ctx, "other").Val()).To(Equal(int64(0)))
+			Expect(src.Exists(ctx, "user:1", "user:2", "user:3").Val()).To(Equal(int64(3)))
+		})
+
+		It("should skip or replace the existing keys", func() {
+			Expect(dst.Set(ctx, "user:1", "old", 0).Err()).NotTo(HaveOccurred())
+
+			progress, err := redis.CopyKeys(ctx, src, dst, &redis.CopyKeysOptions{
+				Match:   "user:*",
+				Type:    "string",
+				Migrate: migrate,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress.Copied).To(Equal(int64(1)))
+			Expect(progress.Skipped).To(Equal(int64(1)))
+			Expect(dst.Get(ctx, "user:1").Val()).To(Equal("old"))
+
+			progress, err = redis.CopyKeys(ctx, src, dst, &redis.CopyKeysOptions{
+				Match:   "user:1",
+				Replace: true,
+				Migrate: migrate,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(progress.Copied).To(Equal(int64(1)))
+			Expect(dst.Get(ctx, "user:1").Val()).To(Equal("one"))
+		})
+	}
+})
@@ -0,0 +1,164 @@
+// Package counter implements counters aggregating their increments in the
+// process and flushing them to Redis periodically, for high rate counters
+// such as metrics that would otherwise send a command per increment.
+//
+// The increments are buffered for at most Options.FlushInterval, so a crash
+// of the process loses at most the increments of the last interval. An
+// increment that failed to be flushed is retried with the next flush: when it
+// failed after it was applied by Redis, e.g. on a timeout, it is counted twice.
+package counter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type rediser interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+}
+
+type Options struct {
+	// FlushInterval is the interval between the flushes. Default is 1 second.
+	FlushInterval time.Duration
+	// Expiration of the counters, refreshed by each flush. Default is no expiration.
+	Expiration time.Duration
+	// OnError is called with the errors of the periodic flushes. Default is to
+	// ignore them.
+	OnError func(err error)
+}
+
+// Counters buffers the increments of counters and flushes them to Redis.
+type Counters struct {
+	client rediser
+	opt    Options
+
+	mu      sync.Mutex
+	pending map[string]int64
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// New returns counters flushed to client every Options.FlushInterval until
+// they are closed.
+func New(client rediser, opt *Options) *Counters {
+	c := &Counters{
+		client:  client,
+		pending: make(map[string]int64),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if opt != nil {
+		c.opt = *opt
+	}
+	if c.opt.FlushInterval <= 0 {
+		c.opt.FlushInterval = time.Second
+	}
+	go c.flusher()
+	return c
+}
+
+func (c *Counters) flusher() {
+	defer close(c.stopped)
+	ticker := time.NewTicker(c.opt.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+		if err := c.Flush(context.Background()); err != nil && c.opt.OnError != nil {
+			c.opt.OnError(err)
+		}
+	}
+}
+
+// Incr increments the counter key by 1.
+func (c *Counters) Incr(key string) {
+	c.IncrBy(key, 1)
+}
+
+// IncrBy increments the counter key by n.
+func (c *Counters) IncrBy(key string, n int64) {
+	c.mu.Lock()
+	c.pending[key] += n
+	c.mu.Unlock()
+}
+
+// Pending returns the increments of key not flushed yet.
+func (c *Counters) Pending(key string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pending[key]
+}
+
+// Get returns the value of the counter key: its value in Redis plus its
+// pending increments. It misses the increments being flushed concurrently.
+func (c *Counters) Get(ctx context.Context, key string) (int64, error) {
+	n, err := c.client.Get(ctx, key).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	return n + c.Pending(key), nil
+}
+
+// Flush sends the pending increments to Redis in a pipeline.
+func (c *Counters) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	deltas := c.pending
+	c.pending = make(map[string]int64, len(deltas))
+	c.mu.Unlock()
+
+	var keys []string
+	for key, n := range deltas {
+		if n == 0 {
+			delete(deltas, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cmds := make([]*redis.IntCmd, len(keys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.IncrBy(ctx, key, deltas[key])
+			if c.opt.Expiration > 0 {
+				pipe.Expire(ctx, key, c.opt.Expiration)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	// retry the failed increments with the next flush
+	c.mu.Lock()
+	for i, key := range keys {
+		if cmds[i] == nil || cmds[i].Err() != nil {
+			c.pending[key] += deltas[key]
+		}
+	}
+	c.mu.Unlock()
+	return err
+}
+
+// Close stops the periodic flushes and flushes the pending increments.
+func (c *Counters) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.stop)
+		<-c.stopped
+		err = c.Flush(context.Background())
+	})
+	return err
+}
@@ -0,0 +1,135 @@
+package counter_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/counter"
+)
+
+// fakeRedis applies the INCRBY commands of the pipelines to a map.
+type fakeRedis struct {
+	mu          sync.Mutex
+	vals        map[string]int64
+	flushes     int
+	err         error
+	expirations map[string]time.Duration
+}
+
+type fakePipe struct {
+	redis.Pipeliner
+	r    *fakeRedis
+	cmds []redis.Cmder
+}
+
+func (p *fakePipe) IncrBy(ctx context.Context, key string, n int64) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx, "incrby", key, n)
+	p.cmds = append(p.cmds, cmd)
+	return cmd
+}
+
+func (p *fakePipe) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx, "expire", key, expiration)
+	p.cmds = append(p.cmds, cmd)
+	return cmd
+}
+
+func (r *fakeRedis) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	pipe := &fakePipe{r: r}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushes++
+	for _, cmd := range pipe.cmds {
+		if r.err != nil {
+			cmd.SetErr(r.err)
+			continue
+		}
+		args := cmd.Args()
+		key := args[1].(string)
+		switch cmd := cmd.(type) {
+		case *redis.IntCmd:
+			r.vals[key] += args[2].(int64)
+			cmd.SetVal(r.vals[key])
+		case *redis.BoolCmd:
+			r.expirations[key] = args[2].(time.Duration)
+			cmd.SetVal(true)
+		}
+	}
+	return pipe.cmds, r.err
+}
+
+func (r *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmd := redis.NewStringCmd(ctx, "get", key)
+	n, ok := r.vals[key]
+	if !ok {
+		cmd.SetErr(redis.Nil)
+		return cmd
+	}
+	cmd.SetVal(strconv.FormatInt(n, 10))
+	return cmd
+}
+
+func TestCounters(t *testing.T) {
+	ctx := context.Background()
+	rdb := &fakeRedis{vals: make(map[string]int64), expirations: make(map[string]time.Duration)}
+	c := counter.New(rdb, &counter.Options{FlushInterval: time.Hour, Expiration: time.Minute})
+
+	for i := 0; i < 100; i++ {
+		c.Incr("hits")
+	}
+	c.IncrBy("bytes", 512)
+	if n, err := c.Get(ctx, "hits"); err != nil || n != 100 {
+		t.Fatalf("got %d, %v, want 100", n, err)
+	}
+
+	rdb.err = errors.New("connection refused")
+	if err := c.Flush(ctx); err != rdb.err {
+		t.Fatalf("got %v, want %v", err, rdb.err)
+	}
+	if n := c.Pending("hits"); n != 100 {
+		t.Fatalf("failed increments were dropped: %d pending", n)
+	}
+
+	rdb.err = nil
+	c.IncrBy("hits", 5)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rdb.vals["hits"] != 105 || rdb.vals["bytes"] != 512 {
+		t.Errorf("got %v", rdb.vals)
+	}
+	if rdb.expirations["hits"] != time.Minute {
+		t.Errorf("got expirations %v", rdb.expirations)
+	}
+	if n, err := c.Get(ctx, "hits"); err != nil || n != 105 {
+		t.Fatalf("got %d, %v, want 105", n, err)
+	}
+	if err := c.Flush(ctx); err != nil || rdb.flushes != 2 {
+		t.Errorf("empty flush sent a pipeline: %v, %d flushes", err, rdb.flushes)
+	}
+}
+
+func TestCountersPeriodicFlush(t *testing.T) {
+	rdb := &fakeRedis{vals: make(map[string]int64), expirations: make(map[string]time.Duration)}
+	c := counter.New(rdb, &counter.Options{FlushInterval: 5 * time.Millisecond})
+	defer c.Close()
+
+	c.Incr("hits")
+	deadline := time.Now().Add(time.Second)
+	for c.Pending("hits") != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if c.Pending("hits") != 0 {
+		t.Fatal("increments were not flushed")
+	}
+}
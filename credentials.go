@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9/internal/pool"
+)
+
+// StreamingCredentialsProvider provides credentials renewed over time, such as
+// short-lived authentication tokens, and notifies the clients of their
+// renewals for them to re-authenticate their connections.
+type StreamingCredentialsProvider interface {
+	// Credentials returns the current username and password.
+	Credentials(ctx context.Context) (username, password string, err error)
+	// Subscribe calls fn each time the credentials are renewed, until
+	// unsubscribe is called.
+	Subscribe(fn func()) (unsubscribe func())
+}
+
+// streamingCredentials re-authenticates the connections of a client when the
+// credentials of its provider are renewed: each connection is authenticated
+// again with AUTH the next time it is taken from the pool.
+type streamingCredentials struct {
+	provider StreamingCredentialsProvider
+
+	// generation is incremented by each renewal; the connections
+	// authenticated with the credentials of an older generation are stale.
+	generation uint32 // atomic
+
+	unsubscribe     func()
+	unsubscribeOnce sync.Once
+}
+
+func newStreamingCredentials(provider StreamingCredentialsProvider) *streamingCredentials {
+	s := &streamingCredentials{provider: provider}
+	s.unsubscribe = provider.Subscribe(func() {
+		atomic.AddUint32(&s.generation, 1)
+	})
+	return s
+}
+
+// credentials returns the current credentials, and the generation the
+// connections authenticated with them belong to.
+func (s *streamingCredentials) credentials(ctx context.Context) (username, password string, generation uint32, err error) {
+	generation = atomic.LoadUint32(&s.generation)
+	username, password, err = s.provider.Credentials(ctx)
+	return username, password, generation, err
+}
+
+func (s *streamingCredentials) stale(cn *pool.Conn) bool {
+	return cn.CredentialsGeneration != atomic.LoadUint32(&s.generation)
+}
+
+func (s *streamingCredentials) close() {
+	s.unsubscribeOnce.Do(s.unsubscribe)
+}
+
+// reauth authenticates cn again with the renewed credentials.
+func (c *baseClient) reauth(ctx context.Context, cn *pool.Conn) error {
+	username, password, generation, err := c.creds.credentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	conn := newConn(c.opt, pool.NewSingleConnPool(c.connPool, cn))
+	conn.pushes = c.pushes
+	if username != "" {
+		err = conn.AuthACL(ctx, username, password).Err()
+	} else {
+		err = conn.Auth(ctx, password).Err()
+	}
+	if err != nil {
+		return err
+	}
+	cn.CredentialsGeneration = generation
+	return nil
+}
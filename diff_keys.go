@@ -0,0 +1,474 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type DiffKeysOptions struct {
+	// Match is the SCAN pattern of the compared keys. Default is all the keys.
+	Match string
+	// BatchSize is the COUNT of SCAN and the number of keys compared per
+	// pipeline. Default is 100.
+	BatchSize int
+	// TTLTolerance is the maximum difference between the TTLs of a key, which
+	// drift by the time it took to copy the key. Supported values:
+	//   - `0` - default tolerance (1 second).
+	//   - `-1` - the TTLs are not compared.
+	TTLTolerance time.Duration
+	// SkipValues only compares the types and TTLs of the keys.
+	SkipValues bool
+	// DebugDigest compares the values with DEBUG DIGEST-VALUE, which must be
+	// enabled on both deployments, instead of fetching the values. It also
+	// supports the types of the modules.
+	DebugDigest bool
+	// Bidirectional also scans dst for the keys missing in src, reported as
+	// KeyExtra.
+	Bidirectional bool
+	// MaxDiffs is the maximum number of differences kept in the report. The
+	// differences are all counted and passed to OnDiff. Default is 1000.
+	MaxDiffs int
+	// OnDiff is called with each difference. The servers of a cluster or ring
+	// are compared concurrently, but the calls are serialized.
+	OnDiff func(diff KeyDiff)
+}
+
+// KeyDiffKind is the kind of a difference between two deployments.
+type KeyDiffKind int
+
+const (
+	// KeyMissing is a key of the source missing in the destination.
+	KeyMissing KeyDiffKind = iota + 1
+	// KeyExtra is a key of the destination missing in the source.
+	KeyExtra
+	// KeyTypeMismatch is a key of different types.
+	KeyTypeMismatch
+	// KeyTTLMismatch is a key whose TTLs differ by more than the tolerance.
+	KeyTTLMismatch
+	// KeyValueMismatch is a key of different values.
+	KeyValueMismatch
+)
+
+func (k KeyDiffKind) String() string {
+	switch k {
+	case KeyMissing:
+		return "missing"
+	case KeyExtra:
+		return "extra"
+	case KeyTypeMismatch:
+		return "type mismatch"
+	case KeyTTLMismatch:
+		return "ttl mismatch"
+	case KeyValueMismatch:
+		return "value mismatch"
+	}
+	return "unknown"
+}
+
+// KeyDiff is a difference between two deployments.
+type KeyDiff struct {
+	Key  string
+	Kind KeyDiffKind
+	// Src and Dst are the compared types, TTLs or value digests of the key in
+	// the source and destination.
+	Src, Dst string
+}
+
+// DiffKeysReport is the report of DiffKeys.
+type DiffKeysReport struct {
+	// Compared is the number of compared keys.
+	Compared int64
+	// DiffCount is the number of differences, including the differences beyond
+	// DiffKeysOptions.MaxDiffs.
+	DiffCount int64
+	// Diffs are the first differences.
+	Diffs []KeyDiff
+	// Failed is the number of keys that failed to be compared.
+	Failed int64
+	// LastError is the last error comparing a key.
+	LastError error
+}
+
+// DiffKeys compares the keys of src matching opt to the keys of dst, e.g. to
+// validate a migration done with CopyKeys. src and dst can be a Client, a
+// ClusterClient or a Ring. The keys expiring or modified during the
+// comparison may be reported as different.
+//
+// The values are compared with a digest of their content, ignoring their
+// encoding: the strings, and the ordered fields of the hashes, the elements of
+// the lists, the members of the sets, the members and scores of the sorted
+// sets and the entries of the streams. The other types are compared with
+// their DUMP payload, which depends on the version of the servers. The values
+// are fetched entirely: use SkipValues or DebugDigest for deployments with
+// large keys.
+//
+// It returns an error when scanning a deployment fails, and counts the keys it
+// failed to compare in DiffKeysReport.Failed.
+func DiffKeys(ctx context.Context, src, dst UniversalClient, opt *DiffKeysOptions) (*DiffKeysReport, error) {
+	d := &keyDiffer{src: src, dst: dst}
+	if opt != nil {
+		d.opt = *opt
+	}
+	if d.opt.BatchSize <= 0 {
+		d.opt.BatchSize = 100
+	}
+	if d.opt.TTLTolerance == 0 {
+		d.opt.TTLTolerance = time.Second
+	}
+	if d.opt.MaxDiffs <= 0 {
+		d.opt.MaxDiffs = 1000
+	}
+
+	err := forEachScanNode(ctx, src, func(ctx context.Context, node *Client) error {
+		return scanKeys(ctx, node, d.opt.Match, "", d.opt.BatchSize, func(keys []string) error {
+			d.compare(ctx, node, keys)
+			return nil
+		})
+	})
+	if err == nil && d.opt.Bidirectional {
+		err = forEachScanNode(ctx, dst, func(ctx context.Context, node *Client) error {
+			return scanKeys(ctx, node, d.opt.Match, "", d.opt.BatchSize, func(keys []string) error {
+				d.findExtra(ctx, keys)
+				return nil
+			})
+		})
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	report := d.report
+	return &report, err
+}
+
+type keyDiffer struct {
+	src, dst UniversalClient
+	opt      DiffKeysOptions
+
+	mu     sync.Mutex
+	report DiffKeysReport
+}
+
+// keyState is the state of a key in a deployment.
+type keyState struct {
+	typ    *StatusCmd
+	ttl    *DurationCmd
+	digest func() (string, error)
+}
+
+func (d *keyDiffer) compare(ctx context.Context, node *Client, keys []string) {
+	srcStates := make([]keyState, len(keys))
+	dstStates := make([]keyState, len(keys))
+	d.fetchTypes(ctx, node, keys, srcStates)
+	d.fetchTypes(ctx, d.dst, keys, dstStates)
+
+	errs := make([]error, len(keys))
+	diffs := make([]*KeyDiff, len(keys))
+	var compareValues []int
+	for i, key := range keys {
+		srcType, err := srcStates[i].typ.Result()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if srcType == "none" {
+			// deleted or expired after the scan
+			continue
+		}
+		dstType, err := dstStates[i].typ.Result()
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		switch {
+		case dstType == "none":
+			diffs[i] = &KeyDiff{Key: key, Kind: KeyMissing, Src: srcType, Dst: dstType}
+			continue
+		case srcType != dstType:
+			diffs[i] = &KeyDiff{Key: key, Kind: KeyTypeMismatch, Src: srcType, Dst: dstType}
+			continue
+		}
+
+		if d.opt.TTLTolerance >= 0 {
+			srcTTL, err := srcStates[i].ttl.Result()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			dstTTL, err := dstStates[i].ttl.Result()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if !ttlsMatch(srcTTL, dstTTL, d.opt.TTLTolerance) {
+				diffs[i] = &KeyDiff{
+					Key:  key,
+					Kind: KeyTTLMismatch,
+					Src:  formatTTL(srcTTL),
+					Dst:  formatTTL(dstTTL),
+				}
+				continue
+			}
+		}
+
+		if !d.opt.SkipValues {
+			compareValues = append(compareValues, i)
+		}
+	}
+
+	if len(compareValues) > 0 {
+		d.fetchDigests(ctx, node, keys, srcStates, compareValues)
+		d.fetchDigests(ctx, d.dst, keys, dstStates, compareValues)
+		for _, i := range compareValues {
+			srcDigest, err := srcStates[i].digest()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			dstDigest, err := dstStates[i].digest()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			if srcDigest != dstDigest {
+				diffs[i] = &KeyDiff{Key: keys[i], Kind: KeyValueMismatch, Src: srcDigest, Dst: dstDigest}
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range keys {
+		switch {
+		case errs[i] != nil:
+			d.report.Compared++
+			d.report.Failed++
+			d.report.LastError = errs[i]
+		case diffs[i] != nil:
+			d.report.Compared++
+			d.addDiff(*diffs[i])
+		case srcStates[i].typ.Val() != "none":
+			d.report.Compared++
+		}
+	}
+}
+
+func (d *keyDiffer) findExtra(ctx context.Context, keys []string) {
+	cmds := make([]*IntCmd, len(keys))
+	_, _ = d.src.Pipelined(ctx, func(pipe Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Exists(ctx, key)
+		}
+		return nil
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, cmd := range cmds {
+		n, err := cmd.Result()
+		switch {
+		case err != nil:
+			d.report.Failed++
+			d.report.LastError = err
+		case n == 0:
+			d.addDiff(KeyDiff{Key: keys[i], Kind: KeyExtra, Src: "none"})
+		}
+	}
+}
+
+func (d *keyDiffer) addDiff(diff KeyDiff) {
+	d.report.DiffCount++
+	if len(d.report.Diffs) < d.opt.MaxDiffs {
+		d.report.Diffs = append(d.report.Diffs, diff)
+	}
+	if d.opt.OnDiff != nil {
+		d.opt.OnDiff(diff)
+	}
+}
+
+func (d *keyDiffer) fetchTypes(ctx context.Context, client UniversalClient, keys []string, states []keyState) {
+	_, _ = client.Pipelined(ctx, func(pipe Pipeliner) error {
+		for i, key := range keys {
+			states[i].typ = pipe.Type(ctx, key)
+			if d.opt.TTLTolerance >= 0 {
+				states[i].ttl = pipe.PTTL(ctx, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (d *keyDiffer) fetchDigests(
+	ctx context.Context, client UniversalClient, keys []string, states []keyState, indexes []int,
+) {
+	_, _ = client.Pipelined(ctx, func(pipe Pipeliner) error {
+		for _, i := range indexes {
+			if d.opt.DebugDigest {
+				states[i].digest = debugDigest(ctx, pipe, keys[i])
+			} else {
+				states[i].digest = valueDigest(ctx, pipe, keys[i], states[i].typ.Val())
+			}
+		}
+		return nil
+	})
+}
+
+// debugDigest queues the DEBUG DIGEST-VALUE of key in pipe.
+func debugDigest(ctx context.Context, pipe Pipeliner, key string) func() (string, error) {
+	cmd := pipe.Do(ctx, "debug", "digest-value", key)
+	return func() (string, error) {
+		ss, err := cmd.StringSlice()
+		if err != nil {
+			return "", err
+		}
+		if len(ss) != 1 {
+			return "", fmt.Errorf("redis: unexpected DEBUG DIGEST-VALUE reply: %q", ss)
+		}
+		return ss[0], nil
+	}
+}
+
+// valueDigest queues the commands fetching the value of key of type typ in
+// pipe, returning the digest of the value.
+func valueDigest(ctx context.Context, pipe Pipeliner, key, typ string) func() (string, error) {
+	h := &digest{Hash: sha1.New()}
+	switch typ {
+	case "string":
+		cmd := pipe.Get(ctx, key)
+		return func() (string, error) {
+			val, err := cmd.Result()
+			if err != nil {
+				return "", err
+			}
+			h.write(val)
+			return h.sum(), nil
+		}
+	case "hash":
+		cmd := pipe.HGetAll(ctx, key)
+		return func() (string, error) {
+			val, err := cmd.Result()
+			if err != nil {
+				return "", err
+			}
+			fields := make([]string, 0, len(val))
+			for field := range val {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				h.write(field)
+				h.write(val[field])
+			}
+			return h.sum(), nil
+		}
+	case "list", "set":
+		var cmd *StringSliceCmd
+		if typ == "list" {
+			cmd = pipe.LRange(ctx, key, 0, -1)
+		} else {
+			cmd = pipe.SMembers(ctx, key)
+		}
+		return func() (string, error) {
+			val, err := cmd.Result()
+			if err != nil {
+				return "", err
+			}
+			if typ == "set" {
+				sort.Strings(val)
+			}
+			for _, s := range val {
+				h.write(s)
+			}
+			return h.sum(), nil
+		}
+	case "zset":
+		cmd := pipe.ZRangeWithScores(ctx, key, 0, -1)
+		return func() (string, error) {
+			val, err := cmd.Result()
+			if err != nil {
+				return "", err
+			}
+			for _, z := range val {
+				h.write(fmt.Sprint(z.Member))
+				h.write(strconv.FormatFloat(z.Score, 'g', -1, 64))
+			}
+			return h.sum(), nil
+		}
+	case "stream":
+		cmd := pipe.XRange(ctx, key, "-", "+")
+		return func() (string, error) {
+			val, err := cmd.Result()
+			if err != nil {
+				return "", err
+			}
+			for _, msg := range val {
+				h.write(msg.ID)
+				fields := make([]string, 0, len(msg.Values))
+				for field := range msg.Values {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				for _, field := range fields {
+					h.write(field)
+					h.write(fmt.Sprint(msg.Values[field]))
+				}
+			}
+			return h.sum(), nil
+		}
+	}
+
+	cmd := pipe.Dump(ctx, key)
+	return func() (string, error) {
+		val, err := cmd.Result()
+		if err != nil {
+			return "", err
+		}
+		h.write(val)
+		return h.sum(), nil
+	}
+}
+
+// digest hashes a sequence of strings.
+type digest struct {
+	hash.Hash
+}
+
+func (h *digest) write(s string) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], uint64(len(s)))
+	_, _ = h.Write(b[:n])
+	_, _ = h.Write([]byte(s))
+}
+
+func (h *digest) sum() string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func ttlsMatch(a, b, tolerance time.Duration) bool {
+	if a < 0 || b < 0 {
+		return a == b
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
+
+func formatTTL(ttl time.Duration) string {
+	switch ttl {
+	case -1:
+		return "persistent"
+	case -2:
+		return "none"
+	}
+	return ttl.String()
+}
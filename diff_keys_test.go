@@ -0,0 +1,68 @@
+package redis_test
+
+import (
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("DiffKeys", func() {
+	var src, dst *redis.Client
+
+	BeforeEach(func() {
+		src = redis.NewClient(redisOptions())
+		Expect(src.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		opt := redisOptions()
+		opt.DB = 14
+		dst = redis.NewClient(opt)
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		for _, client := range []*redis.Client{src, dst} {
+			Expect(client.Set(ctx, "string", "value", 0).Err()).NotTo(HaveOccurred())
+			Expect(client.HSet(ctx, "hash", "a", "1", "b", "2").Err()).NotTo(HaveOccurred())
+			Expect(client.SAdd(ctx, "set", "a", "b", "c").Err()).NotTo(HaveOccurred())
+			Expect(client.ZAdd(ctx, "zset", redis.Z{Score: 1, Member: "a"}).Err()).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		Expect(src.Close()).NotTo(HaveOccurred())
+		Expect(dst.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should report no differences", func() {
+		report, err := redis.DiffKeys(ctx, src, dst, &redis.DiffKeysOptions{Bidirectional: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Compared).To(Equal(int64(4)))
+		Expect(report.Diffs).To(BeEmpty())
+	})
+
+	It("should report the differences", func() {
+		Expect(src.Set(ctx, "missing", "value", 0).Err()).NotTo(HaveOccurred())
+		Expect(dst.Set(ctx, "extra", "value", 0).Err()).NotTo(HaveOccurred())
+		Expect(src.Expire(ctx, "string", time.Hour).Err()).NotTo(HaveOccurred())
+		Expect(dst.HSet(ctx, "hash", "b", "3").Err()).NotTo(HaveOccurred())
+		Expect(dst.Del(ctx, "set").Err()).NotTo(HaveOccurred())
+		Expect(dst.RPush(ctx, "set", "a").Err()).NotTo(HaveOccurred())
+
+		kinds := make(map[string]redis.KeyDiffKind)
+		report, err := redis.DiffKeys(ctx, src, dst, &redis.DiffKeysOptions{
+			Bidirectional: true,
+			OnDiff:        func(diff redis.KeyDiff) { kinds[diff.Key] = diff.Kind },
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.DiffCount).To(Equal(int64(5)))
+		Expect(kinds).To(Equal(map[string]redis.KeyDiffKind{
+			"missing": redis.KeyMissing,
+			"extra":   redis.KeyExtra,
+			"string":  redis.KeyTTLMismatch,
+			"hash":    redis.KeyValueMismatch,
+			"set":     redis.KeyTypeMismatch,
+		}))
+	})
+})
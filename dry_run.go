@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/hashtag"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// ErrDryRun is the error of the commands which are not sent with the DryRun
+// option: errors.Is(err, ErrDryRun) reports them, and errors.As to a
+// *DryRunError tells where they would have been sent.
+var ErrDryRun = errors.New("redis: dry run")
+
+// DryRunError is the error of a command which is not sent with the DryRun
+// option.
+type DryRunError struct {
+	// Addr is the address of the node the command would have been sent to.
+	Addr string
+	// Slot is the hash slot of the keys of the command with a ClusterClient,
+	// or -1.
+	Slot int
+	// Payload is the command marshaled as it would have been sent.
+	Payload []byte
+}
+
+func (e *DryRunError) Error() string {
+	if e.Slot >= 0 {
+		return fmt.Sprintf("redis: dry run: not sent to %s for slot %d", e.Addr, e.Slot)
+	}
+	return fmt.Sprintf("redis: dry run: not sent to %s", e.Addr)
+}
+
+// Is reports whether target is ErrDryRun.
+func (e *DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// dryRun marshals and logs cmd with logger instead of sending it to addr, and fails it
+// with a DryRunError, with its slot when cluster is true.
+func dryRun(ctx context.Context, logger Logger, cmd Cmder, addr string, cluster bool) error {
+	var buf bytes.Buffer
+	if err := writeCmd(proto.NewWriter(&buf), cmd); err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+
+	slot := -1
+	if cluster {
+		if pos := cmdFirstKeyPos(cmd); pos != 0 {
+			slot = hashtag.Slot(cmd.stringArg(pos))
+		}
+	}
+	if slot >= 0 {
+		internal.Log(ctx, logger, internal.LevelInfo, "redis: dry run",
+			"addr", addr, "slot", slot, "cmd", logCmd(cmd))
+	} else {
+		internal.Log(ctx, logger, internal.LevelInfo, "redis: dry run", "addr", addr, "cmd", logCmd(cmd))
+	}
+
+	err := &DryRunError{
+		Addr:    addr,
+		Slot:    slot,
+		Payload: buf.Bytes(),
+	}
+	cmd.SetErr(err)
+	return err
+}
+
+// dryRunCmds is dryRun for the commands of a pipeline, and returns the first
+// error.
+func dryRunCmds(ctx context.Context, logger Logger, cmds []Cmder, addr string, cluster bool) error {
+	var firstErr error
+	for _, cmd := range cmds {
+		if err := dryRun(ctx, logger, cmd, addr, cluster); firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
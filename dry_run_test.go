@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient(&Options{
+		Addr:   "db:6379",
+		DryRun: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			t.Fatal("dialed with DryRun")
+			return nil, nil
+		},
+	})
+	defer client.Close()
+
+	err := client.Get(ctx, "key").Err()
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("got %v, wanted ErrDryRun", err)
+	}
+	var dryErr *DryRunError
+	if !errors.As(err, &dryErr) {
+		t.Fatalf("got %T, wanted *DryRunError", err)
+	}
+	if dryErr.Addr != "db:6379" || dryErr.Slot != -1 {
+		t.Fatalf("got %s slot %d, wanted db:6379 slot -1", dryErr.Addr, dryErr.Slot)
+	}
+	if got, want := string(dryErr.Payload), "*2\r\n$3\r\nget\r\n$3\r\nkey\r\n"; got != want {
+		t.Fatalf("got payload %q, wanted %q", got, want)
+	}
+
+	cmds, err := client.Pipelined(ctx, func(pipe Pipeliner) error {
+		pipe.Set(ctx, "a", "1", 0)
+		pipe.Incr(ctx, "b")
+		return nil
+	})
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("got %v, wanted ErrDryRun", err)
+	}
+	for _, cmd := range cmds {
+		if !errors.As(cmd.Err(), &dryErr) {
+			t.Fatalf("%s: got %v, wanted *DryRunError", cmd.Name(), cmd.Err())
+		}
+	}
+	if got, want := string(dryErr.Payload), "*2\r\n$4\r\nincr\r\n$1\r\nb\r\n"; got != want {
+		t.Fatalf("got payload %q, wanted %q", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// OptionsFromEnv returns the Options of the environment variables named with
+// prefix, e.g. REDIS_ADDR with the prefix "REDIS":
+//   - <prefix>_URL is a URL parsed as with ParseURL, completed or overridden by
+//     the other variables
+//   - <prefix>_ADDR is the host:port address, or the path of a unix socket
+//   - <prefix>_USERNAME and <prefix>_PASSWORD are the credentials
+//   - <prefix>_TLS enables TLS with true, as with the rediss scheme
+//   - the other variables are the query parameters of ParseURL in upper case,
+//     with the same values, e.g. <prefix>_DB, <prefix>_POOL_SIZE or
+//     <prefix>_READ_TIMEOUT
+//
+// The prefix should be dedicated to the client: as the unknown query
+// parameters, any other variable named with the prefix results in an error.
+func OptionsFromEnv(prefix string) (*Options, error) {
+	u, names, err := envURL(prefix, "redis", "addr")
+	if err != nil {
+		return nil, err
+	}
+	return parseURL(u, names)
+}
+
+// ClusterOptionsFromEnv returns the ClusterOptions of the environment variables
+// named with prefix, as with OptionsFromEnv, but <prefix>_URL is parsed as with
+// ParseClusterURL and <prefix>_ADDRS is the comma-separated addresses of the
+// cluster nodes.
+func ClusterOptionsFromEnv(prefix string) (*ClusterOptions, error) {
+	u, names, err := envURL(prefix, "redis", "addrs")
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterURL(u, names)
+}
+
+// FailoverOptionsFromEnv returns the FailoverOptions of the environment
+// variables named with prefix, as with OptionsFromEnv, but <prefix>_URL is
+// parsed as with ParseFailoverURL, <prefix>_ADDRS is the comma-separated
+// addresses of the sentinels, and <prefix>_MASTER_NAME is required unless set
+// by the URL.
+func FailoverOptionsFromEnv(prefix string) (*FailoverOptions, error) {
+	u, names, err := envURL(prefix, "redis-sentinel", "addrs")
+	if err != nil {
+		return nil, err
+	}
+	return parseFailoverURL(u, names)
+}
+
+// envURL returns the URL of the environment variables of prefix, with scheme
+// when there is no URL variable, and the names of the variables of its query
+// parameters. The variable addrParam is the address of the URL, or its
+// comma-separated addresses with "addrs".
+func envURL(prefix, scheme, addrParam string) (*url.URL, map[string]string, error) {
+	prefix = strings.TrimSuffix(prefix, "_")
+	if prefix == "" {
+		return nil, nil, errors.New("redis: empty environment variables prefix")
+	}
+	prefix += "_"
+
+	vars := make(map[string]string)
+	names := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		param := strings.ToLower(name[len(prefix):])
+		vars[param] = value
+		names[param] = name
+	}
+
+	u := &url.URL{Scheme: scheme}
+	if s, ok := vars["url"]; ok {
+		var err error
+		if u, err = url.Parse(s); err != nil {
+			return nil, nil, fmt.Errorf("redis: invalid %s: %w", names["url"], err)
+		}
+	}
+
+	if s, ok := vars["tls"]; ok {
+		enabled, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("redis: invalid %s boolean: %q", names["tls"], s)
+		}
+		if strings.HasPrefix(u.Scheme, "redis") {
+			u.Scheme = strings.TrimPrefix(strings.TrimPrefix(u.Scheme, "redis"), "s")
+			if enabled {
+				u.Scheme = "rediss" + u.Scheme
+			} else {
+				u.Scheme = "redis" + u.Scheme
+			}
+		}
+	}
+
+	q := u.Query()
+	if s, ok := vars[addrParam]; ok {
+		switch {
+		case addrParam == "addrs":
+			var addrs []string
+			for _, addr := range strings.Split(s, ",") {
+				if addr = strings.TrimSpace(addr); addr != "" {
+					addrs = append(addrs, addr)
+				}
+			}
+			q.Del("addr")
+			if len(addrs) > 0 {
+				u.Host = addrs[0]
+				q["addr"] = addrs[1:]
+			}
+		case strings.HasPrefix(s, "/"):
+			u.Scheme, u.Host, u.Path = "unix", "", s
+		default:
+			u.Host = s
+		}
+	}
+
+	username, password := getUserPassword(u)
+	_, hasUsername := vars["username"]
+	_, hasPassword := vars["password"]
+	if hasUsername {
+		username = vars["username"]
+	}
+	if hasPassword {
+		password = vars["password"]
+	}
+	if hasUsername || hasPassword {
+		u.User = url.UserPassword(username, password)
+	}
+
+	for param, value := range vars {
+		switch param {
+		case "url", "tls", addrParam, "username", "password":
+			continue
+		}
+		q.Set(param, value)
+	}
+	u.RawQuery = q.Encode()
+
+	return u, names, nil
+}
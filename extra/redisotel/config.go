@@ -20,6 +20,7 @@ type config struct {
 	tracer trace.Tracer
 
 	dbStmtEnabled bool
+	pipelineCmds  PipelineCommands
 
 	// Metrics options.
 
@@ -113,6 +114,30 @@ func WithDBStatement(on bool) TracingOption {
 	})
 }
 
+// PipelineCommands is how the commands of a pipeline are traced, see
+// WithPipelineCommands.
+type PipelineCommands int
+
+const (
+	// PipelineCommandsNone traces a pipeline with a single span.
+	PipelineCommandsNone PipelineCommands = iota
+	// PipelineCommandsEvents adds an event to the span of a pipeline for each
+	// of its commands.
+	PipelineCommandsEvents
+	// PipelineCommandsSpans creates a child span of the span of a pipeline for
+	// each of its commands.
+	PipelineCommandsSpans
+)
+
+// WithPipelineCommands tells the tracing hook to trace each command of the
+// pipelines with its index and outcome, as span events or as child spans.
+// By default a pipeline is traced with a single span.
+func WithPipelineCommands(mode PipelineCommands) TracingOption {
+	return tracingOption(func(conf *config) {
+		conf.pipelineCmds = mode
+	})
+}
+
 //------------------------------------------------------------------------------
 
 type MetricsOption interface {
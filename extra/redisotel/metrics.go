@@ -2,6 +2,7 @@ package redisotel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -32,47 +34,17 @@ func InstrumentMetrics(rdb redis.UniversalClient, opts ...MetricsOption) error {
 
 	switch rdb := rdb.(type) {
 	case *redis.Client:
-		if conf.poolName == "" {
-			opt := rdb.Options()
-			conf.poolName = opt.Addr
-		}
-		conf.attrs = append(conf.attrs, attribute.String("pool.name", conf.poolName))
-
-		if err := reportPoolStats(rdb, conf); err != nil {
-			return err
-		}
-		if err := addMetricsHook(rdb, conf); err != nil {
-			return err
-		}
-		return nil
+		return instrumentClientMetrics(rdb, conf)
 	case *redis.ClusterClient:
 		rdb.OnNewNode(func(rdb *redis.Client) {
-			if conf.poolName == "" {
-				opt := rdb.Options()
-				conf.poolName = opt.Addr
-			}
-			conf.attrs = append(conf.attrs, attribute.String("pool.name", conf.poolName))
-
-			if err := reportPoolStats(rdb, conf); err != nil {
-				otel.Handle(err)
-			}
-			if err := addMetricsHook(rdb, conf); err != nil {
+			if err := instrumentClientMetrics(rdb, conf); err != nil {
 				otel.Handle(err)
 			}
 		})
 		return nil
 	case *redis.Ring:
 		rdb.OnNewNode(func(rdb *redis.Client) {
-			if conf.poolName == "" {
-				opt := rdb.Options()
-				conf.poolName = opt.Addr
-			}
-			conf.attrs = append(conf.attrs, attribute.String("pool.name", conf.poolName))
-
-			if err := reportPoolStats(rdb, conf); err != nil {
-				otel.Handle(err)
-			}
-			if err := addMetricsHook(rdb, conf); err != nil {
+			if err := instrumentClientMetrics(rdb, conf); err != nil {
 				otel.Handle(err)
 			}
 		})
@@ -82,6 +54,34 @@ func InstrumentMetrics(rdb redis.UniversalClient, opts ...MetricsOption) error {
 	}
 }
 
+// instrumentClientMetrics reports the metrics of rdb, a client or a node of
+// a ClusterClient or a Ring, labeled with its address as pool name.
+func instrumentClientMetrics(rdb *redis.Client, conf *config) error {
+	conf = conf.forClient(rdb)
+
+	if err := reportPoolStats(rdb, conf); err != nil {
+		return err
+	}
+	if rdb.Options().StatsEnabled {
+		if err := reportCommandStats(rdb, conf); err != nil {
+			return err
+		}
+	}
+	return addMetricsHook(rdb, conf)
+}
+
+// forClient returns a copy of conf with the pool name of rdb.
+func (conf *config) forClient(rdb *redis.Client) *config {
+	clientConf := *conf
+	if clientConf.poolName == "" {
+		clientConf.poolName = rdb.Options().Addr
+	}
+	clientConf.attrs = make([]attribute.KeyValue, 0, len(conf.attrs)+1)
+	clientConf.attrs = append(clientConf.attrs, conf.attrs...)
+	clientConf.attrs = append(clientConf.attrs, attribute.String("pool.name", clientConf.poolName))
+	return &clientConf
+}
+
 func reportPoolStats(rdb *redis.Client, conf *config) error {
 	labels := conf.attrs
 	idleAttrs := append(labels, attribute.String("state", "idle"))
@@ -127,6 +127,23 @@ func reportPoolStats(rdb *redis.Client, conf *config) error {
 		return err
 	}
 
+	waits, err := conf.meter.Int64ObservableCounter(
+		"db.client.connections.waits",
+		metric.WithDescription("The number of times a connection was waited for because the pool was exhausted"),
+	)
+	if err != nil {
+		return err
+	}
+
+	waitsDuration, err := conf.meter.Int64ObservableCounter(
+		"db.client.connections.waits_duration",
+		metric.WithDescription("The total time spent waiting for a connection from the pool"),
+		metric.WithUnit("ns"),
+	)
+	if err != nil {
+		return err
+	}
+
 	redisConf := rdb.Options()
 	_, err = conf.meter.RegisterCallback(
 		func(ctx context.Context, o metric.Observer) error {
@@ -140,6 +157,9 @@ func reportPoolStats(rdb *redis.Client, conf *config) error {
 			o.ObserveInt64(usage, int64(stats.TotalConns-stats.IdleConns), metric.WithAttributes(usedAttrs...))
 
 			o.ObserveInt64(timeouts, int64(stats.Timeouts), metric.WithAttributes(labels...))
+
+			o.ObserveInt64(waits, int64(stats.WaitCount), metric.WithAttributes(labels...))
+			o.ObserveInt64(waitsDuration, stats.WaitDurationNs, metric.WithAttributes(labels...))
 			return nil
 		},
 		idleMax,
@@ -147,11 +167,39 @@ func reportPoolStats(rdb *redis.Client, conf *config) error {
 		connsMax,
 		usage,
 		timeouts,
+		waits,
+		waitsDuration,
 	)
 
 	return err
 }
 
+// reportCommandStats reports the retries of the commands from the statistics
+// of rdb, which are only enabled by Options.StatsEnabled.
+func reportCommandStats(rdb *redis.Client, conf *config) error {
+	retries, err := conf.meter.Int64ObservableCounter(
+		"db.client.commands.retries",
+		metric.WithDescription("The number of times the commands were retried"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = conf.meter.RegisterCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			for name, stats := range rdb.Stats() {
+				attrs := make([]attribute.KeyValue, 0, len(conf.attrs)+1)
+				attrs = append(attrs, conf.attrs...)
+				attrs = append(attrs, semconv.DBOperation(name))
+				o.ObserveInt64(retries, int64(stats.Retries), metric.WithAttributes(attrs...))
+			}
+			return nil
+		},
+		retries,
+	)
+	return err
+}
+
 func addMetricsHook(rdb *redis.Client, conf *config) error {
 	createTime, err := conf.meter.Float64Histogram(
 		"db.client.connections.create_time",
@@ -171,18 +219,39 @@ func addMetricsHook(rdb *redis.Client, conf *config) error {
 		return err
 	}
 
+	cmdDuration, err := conf.meter.Float64Histogram(
+		"db.client.commands.duration",
+		metric.WithDescription("The duration of the commands, including the retries."),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		return err
+	}
+
+	cmdErrors, err := conf.meter.Int64Counter(
+		"db.client.commands.errors",
+		metric.WithDescription("The number of failed commands, not counting the nil replies."),
+	)
+	if err != nil {
+		return err
+	}
+
 	rdb.AddHook(&metricsHook{
-		createTime: createTime,
-		useTime:    useTime,
-		attrs:      conf.attrs,
+		createTime:  createTime,
+		useTime:     useTime,
+		cmdDuration: cmdDuration,
+		cmdErrors:   cmdErrors,
+		attrs:       conf.attrs,
 	})
 	return nil
 }
 
 type metricsHook struct {
-	createTime metric.Float64Histogram
-	useTime    metric.Float64Histogram
-	attrs      []attribute.KeyValue
+	createTime  metric.Float64Histogram
+	useTime     metric.Float64Histogram
+	cmdDuration metric.Float64Histogram
+	cmdErrors   metric.Int64Counter
+	attrs       []attribute.KeyValue
 }
 
 var _ redis.Hook = (*metricsHook)(nil)
@@ -219,6 +288,12 @@ func (mh *metricsHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 
 		mh.useTime.Record(ctx, milliseconds(dur), metric.WithAttributes(attrs...))
 
+		cmdAttrs := make([]attribute.KeyValue, 0, len(mh.attrs)+2)
+		cmdAttrs = append(cmdAttrs, mh.attrs...)
+		cmdAttrs = append(cmdAttrs, semconv.DBOperation(cmd.Name()), cmdStatusAttr(err))
+		mh.cmdDuration.Record(ctx, milliseconds(dur), metric.WithAttributes(cmdAttrs...))
+		mh.recordError(ctx, cmd)
+
 		return err
 	}
 }
@@ -240,14 +315,56 @@ func (mh *metricsHook) ProcessPipelineHook(
 
 		mh.useTime.Record(ctx, milliseconds(dur), metric.WithAttributes(attrs...))
 
+		for _, cmd := range cmds {
+			mh.recordError(ctx, cmd)
+		}
+
 		return err
 	}
 }
 
+// recordError counts the error of cmd by command name and error type.
+func (mh *metricsHook) recordError(ctx context.Context, cmd redis.Cmder) {
+	err := cmd.Err()
+	if err == nil || err == redis.Nil {
+		return
+	}
+	attrs := make([]attribute.KeyValue, 0, len(mh.attrs)+2)
+	attrs = append(attrs, mh.attrs...)
+	attrs = append(attrs, semconv.DBOperation(cmd.Name()), attribute.String("error.type", errorType(err)))
+	mh.cmdErrors.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
+
 func milliseconds(d time.Duration) float64 {
 	return float64(d) / float64(time.Millisecond)
 }
 
+// cmdStatusAttr is statusAttr not counting the nil replies as errors.
+func cmdStatusAttr(err error) attribute.KeyValue {
+	if err == redis.Nil {
+		err = nil
+	}
+	return statusAttr(err)
+}
+
+// errorType returns the type of err: "redis" for the error replies,
+// "timeout", "canceled", or "other" for the network and client errors.
+func errorType(err error) string {
+	var redisErr redis.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &redisErr):
+		return "redis"
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "other"
+	}
+}
+
 func statusAttr(err error) attribute.KeyValue {
 	if err != nil {
 		return attribute.String("status", "error")
@@ -2,12 +2,17 @@ package redisotel
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
 
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/redis/go-redis/v9"
@@ -59,3 +64,113 @@ func TestWithDBStatement(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestWithPipelineCommands(t *testing.T) {
+	for _, mode := range []PipelineCommands{PipelineCommandsEvents, PipelineCommandsSpans} {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		hook := newTracingHook("", WithTracerProvider(provider), WithPipelineCommands(mode))
+
+		ctx := context.Background()
+		cmds := []redis.Cmder{
+			redis.NewCmd(ctx, "get", "a"),
+			redis.NewCmd(ctx, "incr", "b"),
+		}
+		processHook := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+			cmds[1].SetErr(errors.New("ERR value is not an integer"))
+			return cmds[1].Err()
+		})
+		_ = processHook(ctx, cmds)
+
+		spans := recorder.Ended()
+		parent := spans[len(spans)-1]
+		if parent.Name() != "redis.pipeline get incr" {
+			t.Fatalf("got span %q, wanted the pipeline span", parent.Name())
+		}
+
+		switch mode {
+		case PipelineCommandsEvents:
+			events := parent.Events()
+			if len(events) < 2 || events[0].Name != "get" || events[1].Name != "incr" {
+				t.Fatalf("got events %v, wanted get and incr", events)
+			}
+			if !hasAttr(events[1].Attributes, "db.redis.cmd_error") {
+				t.Fatal("incr event has no error")
+			}
+		case PipelineCommandsSpans:
+			if len(spans) != 3 || spans[0].Name() != "get" || spans[1].Name() != "incr" {
+				t.Fatalf("got %d spans, wanted get, incr and the pipeline", len(spans))
+			}
+			for i, span := range spans[:2] {
+				if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+					t.Fatalf("span %q is not a child of the pipeline span", span.Name())
+				}
+				if !hasAttr(span.Attributes(), "db.redis.cmd_index") {
+					t.Fatalf("span %d has no index", i)
+				}
+			}
+			if spans[0].Status().Code != codes.Unset || spans[1].Status().Code != codes.Error {
+				t.Fatal("wanted only the incr span to fail")
+			}
+		}
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, key attribute.Key) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{redis.Nil, "redis"},
+		{redisReplyError("WRONGTYPE Operation against a key holding the wrong kind of value"), "redis"},
+		{context.DeadlineExceeded, "timeout"},
+		{&net.OpError{Op: "read", Err: timeoutError{}}, "timeout"},
+		{context.Canceled, "canceled"},
+		{errors.New("redis: client is closed"), "other"},
+	}
+	for _, test := range tests {
+		if got := errorType(test.err); got != test.want {
+			t.Errorf("errorType(%v) = %q, wanted %q", test.err, got, test.want)
+		}
+	}
+}
+
+type redisReplyError string
+
+func (e redisReplyError) Error() string { return string(e) }
+
+func (redisReplyError) RedisError() {}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestConfigForClient(t *testing.T) {
+	conf := newConfig(WithAttributes(attribute.String("service", "test")))
+	a := conf.forClient(redis.NewClient(&redis.Options{Addr: "a:6379"}))
+	b := conf.forClient(redis.NewClient(&redis.Options{Addr: "b:6379"}))
+
+	if len(conf.attrs) != 2 || conf.poolName != "" {
+		t.Fatalf("the config is modified: %v", conf.attrs)
+	}
+	for _, test := range []struct {
+		conf *config
+		addr string
+	}{{a, "a:6379"}, {b, "b:6379"}} {
+		attrs := test.conf.attrs
+		if len(attrs) != 3 || attrs[2] != attribute.String("pool.name", test.addr) {
+			t.Fatalf("got %v, wanted the pool name %s", attrs, test.addr)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -158,7 +159,10 @@ func (th *tracingHook) ProcessPipelineHook(
 		ctx, span := th.conf.tracer.Start(ctx, "redis.pipeline "+summary, opts...)
 		defer span.End()
 
-		if err := hook(ctx, cmds); err != nil {
+		start := time.Now()
+		err := hook(ctx, cmds)
+		th.traceCmds(ctx, span, cmds, start)
+		if err != nil {
 			recordError(span, err)
 			return err
 		}
@@ -166,6 +170,42 @@ func (th *tracingHook) ProcessPipelineHook(
 	}
 }
 
+// traceCmds traces the commands of the pipeline of span, which was processed
+// from start, as configured by WithPipelineCommands.
+func (th *tracingHook) traceCmds(ctx context.Context, span trace.Span, cmds []redis.Cmder, start time.Time) {
+	switch th.conf.pipelineCmds {
+	case PipelineCommandsEvents:
+		for i, cmd := range cmds {
+			attrs := []attribute.KeyValue{
+				attribute.Int("db.redis.cmd_index", i),
+			}
+			if err := cmd.Err(); err != nil && err != redis.Nil {
+				attrs = append(attrs, attribute.String("db.redis.cmd_error", err.Error()))
+			}
+			span.AddEvent(cmd.FullName(), trace.WithAttributes(attrs...))
+		}
+	case PipelineCommandsSpans:
+		end := time.Now()
+		for i, cmd := range cmds {
+			attrs := []attribute.KeyValue{
+				attribute.Int("db.redis.cmd_index", i),
+			}
+			if th.conf.dbStmtEnabled {
+				attrs = append(attrs, semconv.DBStatement(rediscmd.CmdString(cmd)))
+			}
+
+			opts := th.spanOpts
+			opts = append(opts, trace.WithAttributes(attrs...), trace.WithTimestamp(start))
+
+			_, cmdSpan := th.conf.tracer.Start(ctx, cmd.FullName(), opts...)
+			if err := cmd.Err(); err != nil {
+				recordError(cmdSpan, err)
+			}
+			cmdSpan.End(trace.WithTimestamp(end))
+		}
+	}
+}
+
 func recordError(span trace.Span, err error) {
 	if err != redis.Nil {
 		span.RecordError(err)
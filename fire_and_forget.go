@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// FireAndForget returns a pipeline which sends its commands without reading
+// their replies, e.g. for the high-volume writes that can be lost such as
+// metrics or presence pings. The commands are wrapped with CLIENT REPLY OFF
+// and CLIENT REPLY ON, and only the reply of CLIENT REPLY ON is read:
+//
+//	_, err := rdb.FireAndForget().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+//		pipe.Incr(ctx, "hits")
+//		pipe.Expire(ctx, "hits", time.Hour)
+//		return nil
+//	})
+//
+// As the server does not reply to the commands, even with an error, their
+// values and errors are not set: Exec only returns the errors of the
+// connection and of CLIENT REPLY ON. The commands are not retried once they
+// have been written. They must not be blocking commands, nor MULTI, EXEC or
+// the commands of PubSub. The connection is closed if the server does not
+// support CLIENT REPLY, which requires Redis 3.2.
+func (c *Client) FireAndForget() Pipeliner {
+	pipe := Pipeline{
+		exec: func(ctx context.Context, cmds []Cmder) error {
+			cmds = wrapClientReply(ctx, cmds)
+			return c.processPipelineHook(ctx, cmds)
+		},
+	}
+	pipe.init()
+	return &pipe
+}
+
+// clientReplyOffCmd is the CLIENT REPLY OFF command of the pipelines of
+// FireAndForget.
+type clientReplyOffCmd struct {
+	StatusCmd
+}
+
+func wrapClientReply(ctx context.Context, cmds []Cmder) []Cmder {
+	off := &clientReplyOffCmd{}
+	off.StatusCmd = *NewStatusCmd(ctx, "client", "reply", "off")
+
+	wrapped := make([]Cmder, 0, len(cmds)+2)
+	wrapped = append(wrapped, off)
+	wrapped = append(wrapped, cmds...)
+	wrapped = append(wrapped, NewStatusCmd(ctx, "client", "reply", "on"))
+	return wrapped
+}
+
+// isFireAndForget reports whether cmds is a pipeline of FireAndForget.
+func isFireAndForget(cmds []Cmder) bool {
+	if len(cmds) < 2 {
+		return false
+	}
+	_, ok := cmds[0].(*clientReplyOffCmd)
+	return ok
+}
+
+func (c *baseClient) fireAndForgetProcessCmds(
+	ctx context.Context, cn *pool.Conn, cmds []Cmder,
+) (bool, error) {
+	if err := cn.WithWriter(c.context(ctx), c.writeTimeout(), func(wr *proto.Writer) error {
+		return writeCmds(wr, cmds)
+	}); err != nil {
+		setCmdsErr(cmds, err)
+		return true, err
+	}
+
+	on := cmds[len(cmds)-1]
+	if err := cn.WithReader(c.context(ctx), c.readTimeout(), func(rd *proto.Reader) error {
+		err := c.pushes.readReply(ctx, rd, on)
+		if isRedisError(err) {
+			// the replies of the commands may follow the error of CLIENT REPLY OFF,
+			// the connection must not be reused
+			err = fmt.Errorf("redis: CLIENT REPLY is not supported: %w", err)
+		}
+		return err
+	}); err != nil {
+		setCmdsErr(cmds, err)
+		return false, err
+	}
+
+	return false, nil
+}
@@ -0,0 +1,44 @@
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestFireAndForget(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), PoolSize: 1})
+	defer rdb.Close()
+
+	var incr *redis.IntCmd
+	cmds, err := rdb.FireAndForget().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i := 0; i < 3; i++ {
+			incr = pipe.Incr(ctx, "hits")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmds) != 3 {
+		t.Fatalf("got %d commands, wanted 3", len(cmds))
+	}
+	if incr.Val() != 0 || incr.Err() != nil {
+		t.Fatalf("got %d, %v, wanted no reply", incr.Val(), incr.Err())
+	}
+
+	// the connection replies again
+	n, err := rdb.Get(ctx, "hits").Int()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatalf("got %d, wanted 3", n)
+	}
+	if stats := rdb.PoolStats(); stats.TotalConns != 1 || stats.Misses != 1 {
+		t.Fatalf("got %d connections and %d misses, wanted the connection reused", stats.TotalConns, stats.Misses)
+	}
+}
@@ -0,0 +1,194 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ForEachOptions are the options of ClusterClient.ForEachMasterWithOptions
+// and ForEachShardWithOptions.
+type ForEachOptions struct {
+	// Concurrency is the maximum number of nodes fn is called for at once.
+	// Default is 0, every node at once.
+	Concurrency int
+
+	// ContinueOnError calls fn for every node even when it failed for some,
+	// and returns a *ForEachError with the error of each failed node. By
+	// default, fn is not called for the remaining nodes once it failed, and
+	// the first error is returned.
+	ContinueOnError bool
+}
+
+// ForEachError is the error of ForEachMasterWithOptions and
+// ForEachShardWithOptions with ContinueOnError.
+type ForEachError struct {
+	// Errors are the errors of fn by node address.
+	Errors map[string]error
+	// Nodes is the number of nodes fn was called for.
+	Nodes int
+}
+
+func (e *ForEachError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "redis: %d of %d nodes failed", len(e.Errors), e.Nodes)
+	for i, addr := range e.addrs() {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "%s: %v", addr, e.Errors[addr])
+	}
+	return b.String()
+}
+
+// Is reports whether the error of a node is target, for errors.Is.
+func (e *ForEachError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of a node, by address, matching target, for
+// errors.As.
+func (e *ForEachError) As(target interface{}) bool {
+	for _, addr := range e.addrs() {
+		if errors.As(e.Errors[addr], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the errors of the nodes, as the errors of errors.Join from
+// Go 1.20.
+func (e *ForEachError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// addrs returns the sorted addresses of the failed nodes.
+func (e *ForEachError) addrs() []string {
+	addrs := make([]string, 0, len(e.Errors))
+	for addr := range e.Errors {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// ForEachMasterWithOptions calls fn on each master node of the cluster as
+// ForEachMaster, with opt.
+func (c *ClusterClient) ForEachMasterWithOptions(
+	ctx context.Context,
+	opt *ForEachOptions,
+	fn func(ctx context.Context, client *Client) error,
+) error {
+	state, err := c.state.ReloadOrGet(ctx)
+	if err != nil {
+		return err
+	}
+	return forEachNode(ctx, state.Masters, opt, fn)
+}
+
+// ForEachShardWithOptions calls fn on each known node of the cluster as
+// ForEachShard, with opt.
+func (c *ClusterClient) ForEachShardWithOptions(
+	ctx context.Context,
+	opt *ForEachOptions,
+	fn func(ctx context.Context, client *Client) error,
+) error {
+	state, err := c.state.ReloadOrGet(ctx)
+	if err != nil {
+		return err
+	}
+	nodes := make([]*clusterNode, 0, len(state.Masters)+len(state.Slaves))
+	nodes = append(nodes, state.Masters...)
+	nodes = append(nodes, state.Slaves...)
+	return forEachNode(ctx, nodes, opt, fn)
+}
+
+func forEachNode(
+	ctx context.Context,
+	nodes []*clusterNode,
+	opt *ForEachOptions,
+	fn func(ctx context.Context, client *Client) error,
+) error {
+	if opt == nil {
+		opt = &ForEachOptions{}
+	}
+
+	var sem chan struct{}
+	if opt.Concurrency > 0 {
+		sem = make(chan struct{}, opt.Concurrency)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	var firstErr error
+	setErr := func(addr string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[addr] = err
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+loop:
+	for i, node := range nodes {
+		if sem != nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				// fn is not called for the remaining nodes
+				for _, node := range nodes[i:] {
+					setErr(node.Client.opt.Addr, ctx.Err())
+				}
+				break loop
+			}
+		}
+		if !opt.ContinueOnError && failed() {
+			if sem != nil {
+				<-sem
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(node *clusterNode) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			if err := fn(ctx, node.Client); err != nil {
+				setErr(node.Client.opt.Addr, err)
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if opt.ContinueOnError {
+		return &ForEachError{Errors: errs, Nodes: len(nodes)}
+	}
+	return firstErr
+}
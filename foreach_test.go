@@ -0,0 +1,103 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestForEachWithOptions(t *testing.T) {
+	var slots []redis.ClusterSlot
+	for i := 0; i < 4; i++ {
+		srv := redistest.NewServer()
+		defer srv.Close()
+		slots = append(slots, redis.ClusterSlot{
+			Start: i * 4096, End: i*4096 + 4095,
+			Nodes: []redis.ClusterNode{{Addr: srv.Addr()}},
+		})
+	}
+	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+			return slots, nil
+		},
+	})
+	defer rdb.Close()
+	failing := slots[1].Nodes[0].Addr
+	errFailing := errors.New("failing")
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	var called []string
+	fn := func(ctx context.Context, client *redis.Client) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		called = append(called, client.Options().Addr)
+		mu.Unlock()
+		if client.Options().Addr == failing {
+			return errFailing
+		}
+		return client.Ping(ctx).Err()
+	}
+	reset := func() {
+		atomic.StoreInt32(&maxRunning, 0)
+		called = nil
+	}
+
+	err := rdb.ForEachMasterWithOptions(ctx, &redis.ForEachOptions{Concurrency: 2, ContinueOnError: true}, fn)
+	var forEachErr *redis.ForEachError
+	if !errors.As(err, &forEachErr) {
+		t.Fatalf("got %v, wanted a ForEachError", err)
+	}
+	if forEachErr.Nodes != 4 || len(forEachErr.Errors) != 1 || forEachErr.Errors[failing] != errFailing {
+		t.Fatalf("got %+v", forEachErr)
+	}
+	if !errors.Is(err, errFailing) {
+		t.Fatalf("got %v, wanted errFailing", err)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.Canceled) || errors.As(err, &netErr) {
+		t.Fatalf("got %v, matching other errors", err)
+	}
+	if want := "redis: 1 of 4 nodes failed: " + failing + ": failing"; err.Error() != want {
+		t.Fatalf("got %q, wanted %q", err, want)
+	}
+	if len(called) != 4 {
+		t.Fatalf("called for %q, wanted every node", called)
+	}
+	if max := atomic.LoadInt32(&maxRunning); max != 2 {
+		t.Fatalf("got %d calls at once, wanted 2", max)
+	}
+
+	// the nodes are not called once one failed
+	reset()
+	err = rdb.ForEachShardWithOptions(ctx, &redis.ForEachOptions{Concurrency: 1}, fn)
+	if err != errFailing {
+		t.Fatalf("got %v, wanted the error of the failing node", err)
+	}
+	if len(called) == 4 || called[len(called)-1] != failing {
+		t.Fatalf("called for %q, wanted the nodes up to the failing one", called)
+	}
+
+	reset()
+	if err := rdb.ForEachShardWithOptions(ctx, nil, func(ctx context.Context, client *redis.Client) error {
+		return client.Ping(ctx).Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -116,7 +116,7 @@ func (c cmdable) expire(
 		args = append(args, mode)
 	}
 
-	cmd := NewBoolCmd(ctx, args...)
+	cmd := newPooledBoolCmd(ctx, args...)
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -382,3 +382,45 @@ func (c cmdable) ScanType(ctx context.Context, cursor uint64, match string, coun
 	_ = c(ctx, cmd)
 	return cmd
 }
+
+//------------------------------------------------------------------------------
+
+// ExpireMany sets a millisecond-precision TTL on every key with pipelined
+// PEXPIRE commands, which is cheaper than issuing one round trip per key
+// when thousands of TTLs have to be refreshed at once.
+// The returned slice holds one boolean per key in input order that is false
+// when the key does not exist.
+func (c *Client) ExpireMany(ctx context.Context, expiration time.Duration, keys ...string) ([]bool, error) {
+	return expireMany(ctx, c.Pipeline(), expiration, keys)
+}
+
+// ExpireMany is like Client.ExpireMany, but the pipeline is split
+// by the slot owners so every node only receives its own keys.
+func (c *ClusterClient) ExpireMany(ctx context.Context, expiration time.Duration, keys ...string) ([]bool, error) {
+	return expireMany(ctx, c.Pipeline(), expiration, keys)
+}
+
+// ExpireMany is like Client.ExpireMany, but the pipeline is split by shard.
+func (c *Ring) ExpireMany(ctx context.Context, expiration time.Duration, keys ...string) ([]bool, error) {
+	return expireMany(ctx, c.Pipeline(), expiration, keys)
+}
+
+func expireMany(ctx context.Context, pipe Pipeliner, expiration time.Duration, keys []string) ([]bool, error) {
+	if len(keys) == 0 {
+		return []bool{}, nil
+	}
+
+	cmds := make([]*BoolCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.PExpire(ctx, key, expiration)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make([]bool, len(cmds))
+	for i, cmd := range cmds {
+		res[i] = cmd.Val()
+	}
+	return res, nil
+}
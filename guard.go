@@ -0,0 +1,212 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/util"
+)
+
+// DangerousCommands are commands which shared clients rarely need and which
+// can wipe, block or reconfigure a server, e.g. for GuardPolicy.DenyCommands.
+var DangerousCommands = []string{
+	"flushall", "flushdb", "keys", "debug", "config", "shutdown", "monitor",
+	"save", "bgsave", "bgrewriteaof", "replicaof", "slaveof", "failover",
+	"cluster|reset", "script|flush", "function|flush", "acl|setuser", "acl|deluser",
+}
+
+// GuardPolicy is the policy of the commands allowed by NewGuard. The commands
+// are named in lower case as in ACL rules: "config" is every CONFIG command
+// and "config|get" is only CONFIG GET. The patterns of keys are glob-style
+// patterns as those of KEYS.
+type GuardPolicy struct {
+	// AllowCommands, if not empty, are the only allowed commands.
+	AllowCommands []string
+	// DenyCommands are denied, even when they are in AllowCommands, e.g.
+	// DangerousCommands.
+	DenyCommands []string
+
+	// AllowKeys, if not empty, are the patterns of the only keys allowed.
+	AllowKeys []string
+	// DenyKeys are the patterns of the denied keys, even when they match
+	// AllowKeys.
+	DenyKeys []string
+}
+
+// GuardError is the error of the commands rejected by the hook of NewGuard,
+// or by ReadOnlyClient.
+type GuardError struct {
+	// Command is the rejected command, e.g. "flushall" or "config|set".
+	Command string
+	// Key is the rejected key when the command is rejected for a key.
+	Key string
+}
+
+func (e *GuardError) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("redis: key %q of command %q is not allowed", e.Key, e.Command)
+	}
+	return fmt.Sprintf("redis: command %q is not allowed", e.Command)
+}
+
+// NewGuard returns a hook rejecting the commands of client which policy does
+// not allow with a *GuardError, before they are sent. The commands of a
+// pipeline are all rejected with the error of the first rejected command.
+//
+// With patterns of keys, the keys are located with the key specs reported by
+// the COMMAND command, which is issued once on first use, and the commands
+// whose keys can't be located are rejected as well.
+func NewGuard(client UniversalClient, policy GuardPolicy) Hook {
+	return newGuard(policy, func(ctx context.Context) (map[string]*CommandInfo, error) {
+		return client.Command(context.WithValue(ctx, guardBypassKey{}, true)).Result()
+	})
+}
+
+// guardBypassKey marks the context of the commands of the guard itself.
+type guardBypassKey struct{}
+
+type guard struct {
+	allowCommands commandSet
+	denyCommands  commandSet
+	allowKeys     []string
+	denyKeys      []string
+
+	cmdsInfoCache *cmdsInfoCache
+}
+
+func newGuard(policy GuardPolicy, cmdsInfo func(ctx context.Context) (map[string]*CommandInfo, error)) *guard {
+	return &guard{
+		allowCommands: commandsSet(policy.AllowCommands),
+		denyCommands:  commandsSet(policy.DenyCommands),
+		allowKeys:     policy.AllowKeys,
+		denyKeys:      policy.DenyKeys,
+		cmdsInfoCache: newCmdsInfoCache(cmdsInfo),
+	}
+}
+
+// commandSet are the commands, e.g. "config", and the subcommands, e.g.
+// "config|get", of a GuardPolicy.
+type commandSet struct {
+	names map[string]struct{}
+	subs  map[string]struct{}
+}
+
+func commandsSet(cmds []string) commandSet {
+	var set commandSet
+	for _, cmd := range cmds {
+		cmd = internal.ToLower(cmd)
+		m := &set.names
+		if strings.IndexByte(cmd, '|') >= 0 {
+			m = &set.subs
+		}
+		if *m == nil {
+			*m = make(map[string]struct{})
+		}
+		(*m)[cmd] = struct{}{}
+	}
+	return set
+}
+
+func (s commandSet) empty() bool {
+	return len(s.names) == 0 && len(s.subs) == 0
+}
+
+// lookup returns the command or subcommand of cmd in the set.
+func (s commandSet) lookup(cmd Cmder) (string, bool) {
+	name := cmd.Name()
+	if _, ok := s.names[name]; ok {
+		return name, true
+	}
+	if len(s.subs) > 0 && len(cmd.Args()) > 1 {
+		sub := name + "|" + internal.ToLower(cmd.stringArg(1))
+		if _, ok := s.subs[sub]; ok {
+			return sub, true
+		}
+	}
+	return "", false
+}
+
+func (g *guard) DialHook(next DialHook) DialHook {
+	return next
+}
+
+func (g *guard) ProcessHook(next ProcessHook) ProcessHook {
+	return func(ctx context.Context, cmd Cmder) error {
+		if err := g.check(ctx, cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (g *guard) ProcessPipelineHook(next ProcessPipelineHook) ProcessPipelineHook {
+	return func(ctx context.Context, cmds []Cmder) error {
+		for _, cmd := range cmds {
+			if err := g.check(ctx, cmd); err != nil {
+				setCmdsErr(cmds, err)
+				return err
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// check returns the error of cmd if the policy does not allow it.
+func (g *guard) check(ctx context.Context, cmd Cmder) error {
+	if ctx.Value(guardBypassKey{}) != nil {
+		return nil
+	}
+
+	if denied, ok := g.denyCommands.lookup(cmd); ok {
+		return &GuardError{Command: denied}
+	}
+	if !g.allowCommands.empty() {
+		if _, ok := g.allowCommands.lookup(cmd); !ok {
+			return &GuardError{Command: cmd.Name()}
+		}
+	}
+
+	if len(g.allowKeys) == 0 && len(g.denyKeys) == 0 {
+		return nil
+	}
+	pos, err := keyPositions(ctx, g.cmdsInfoCache, cmd)
+	if err != nil {
+		return err
+	}
+	args := cmd.Args()
+	for _, i := range pos {
+		if i <= 0 || i >= len(args) {
+			continue
+		}
+		key := keyArg(args[i])
+		if matchAny(g.denyKeys, key) || len(g.allowKeys) > 0 && !matchAny(g.allowKeys, key) {
+			return &GuardError{Command: cmd.Name(), Key: key}
+		}
+	}
+	return nil
+}
+
+func keyArg(arg interface{}) string {
+	switch arg := arg.(type) {
+	case string:
+		return arg
+	case []byte:
+		return util.BytesToString(arg)
+	default:
+		return fmt.Sprint(arg)
+	}
+}
+
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if util.GlobMatch(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+var _ Hook = (*guard)(nil)
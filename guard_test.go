@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func guardCmdsInfo(ctx context.Context) (map[string]*CommandInfo, error) {
+	return map[string]*CommandInfo{
+		"get":  {Name: "get", FirstKeyPos: 1, LastKeyPos: 1, StepCount: 1},
+		"mset": {Name: "mset", FirstKeyPos: 1, LastKeyPos: -1, StepCount: 2},
+		"ping": {Name: "ping"},
+	}, nil
+}
+
+func TestGuard(t *testing.T) {
+	cases := []struct {
+		policy GuardPolicy
+		cmd    func(c Cmdable) Cmder
+		err    *GuardError // the expected error
+	}{
+		{
+			policy: GuardPolicy{DenyCommands: DangerousCommands},
+			cmd:    func(c Cmdable) Cmder { return c.FlushAll(ctx) },
+			err:    &GuardError{Command: "flushall"},
+		}, {
+			policy: GuardPolicy{DenyCommands: DangerousCommands},
+			cmd:    func(c Cmdable) Cmder { return c.ConfigGet(ctx, "maxmemory") },
+			err:    &GuardError{Command: "config"},
+		}, {
+			policy: GuardPolicy{DenyCommands: []string{"CONFIG|SET"}},
+			cmd:    func(c Cmdable) Cmder { return c.ConfigSet(ctx, "maxmemory", "1") },
+			err:    &GuardError{Command: "config|set"},
+		}, {
+			policy: GuardPolicy{DenyCommands: []string{"config|set"}},
+			cmd:    func(c Cmdable) Cmder { return c.ConfigGet(ctx, "maxmemory") },
+		}, {
+			policy: GuardPolicy{AllowCommands: []string{"get", "config|get"}},
+			cmd:    func(c Cmdable) Cmder { return c.ConfigGet(ctx, "maxmemory") },
+		}, {
+			policy: GuardPolicy{AllowCommands: []string{"get", "config|get"}},
+			cmd:    func(c Cmdable) Cmder { return c.Del(ctx, "key") },
+			err:    &GuardError{Command: "del"},
+		}, {
+			policy: GuardPolicy{AllowCommands: []string{"get"}, DenyCommands: []string{"get"}},
+			cmd:    func(c Cmdable) Cmder { return c.Get(ctx, "key") },
+			err:    &GuardError{Command: "get"},
+		}, {
+			policy: GuardPolicy{DenyKeys: []string{"secret:*"}},
+			cmd:    func(c Cmdable) Cmder { return c.MSet(ctx, "a", 1, "secret:b", 2) },
+			err:    &GuardError{Command: "mset", Key: "secret:b"},
+		}, {
+			policy: GuardPolicy{AllowKeys: []string{"app:*"}, DenyKeys: []string{"app:secret"}},
+			cmd:    func(c Cmdable) Cmder { return c.Get(ctx, "app:secret") },
+			err:    &GuardError{Command: "get", Key: "app:secret"},
+		}, {
+			policy: GuardPolicy{AllowKeys: []string{"app:*"}},
+			cmd:    func(c Cmdable) Cmder { return c.Get(ctx, "other") },
+			err:    &GuardError{Command: "get", Key: "other"},
+		}, {
+			policy: GuardPolicy{AllowKeys: []string{"app:*"}},
+			cmd:    func(c Cmdable) Cmder { return c.Get(ctx, "app:key") },
+		}, {
+			policy: GuardPolicy{AllowKeys: []string{"app:*"}},
+			cmd:    func(c Cmdable) Cmder { return c.Ping(ctx) },
+		},
+	}
+
+	for i, tc := range cases {
+		rdb := NewClientStub([]byte("+OK\r\n")).Cmdable.(*Client)
+		rdb.AddHook(newGuard(tc.policy, guardCmdsInfo))
+
+		err := tc.cmd(rdb).Err()
+		var guardErr *GuardError
+		switch {
+		case tc.err == nil:
+			if errors.As(err, &guardErr) {
+				t.Errorf("#%d: unexpected error: %s", i, err)
+			}
+		case !errors.As(err, &guardErr):
+			t.Errorf("#%d: got %v, expected %s", i, err, tc.err)
+		case *guardErr != *tc.err:
+			t.Errorf("#%d: got %+v, expected %+v", i, guardErr, tc.err)
+		}
+	}
+}
+
+func TestGuardPipeline(t *testing.T) {
+	rdb := NewClientStub([]byte("+OK\r\n")).Cmdable.(*Client)
+	rdb.AddHook(newGuard(GuardPolicy{DenyCommands: DangerousCommands}, guardCmdsInfo))
+
+	pipe := rdb.Pipeline()
+	ping := pipe.Ping(ctx)
+	pipe.FlushDB(ctx)
+	_, err := pipe.Exec(ctx)
+	if err == nil || err.Error() != `redis: command "flushdb" is not allowed` {
+		t.Fatalf("got %v", err)
+	}
+	if ping.Err() != err {
+		t.Fatalf("got %v for the allowed command", ping.Err())
+	}
+}
+
+func TestNewGuardCommandInfo(t *testing.T) {
+	rdb := NewClientStub([]byte("*0\r\n")).Cmdable.(*Client)
+	rdb.AddHook(NewGuard(rdb, GuardPolicy{AllowCommands: []string{"get"}, DenyKeys: []string{"secret"}}))
+
+	// COMMAND is not guarded, and the keys of the unknown commands can't be
+	// located
+	err := rdb.Get(ctx, "key").Err()
+	if err == nil || err.Error() != `redis: can't locate the keys of unknown command "get"` {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ type HashCmdable interface {
 	HKeys(ctx context.Context, key string) *StringSliceCmd
 	HLen(ctx context.Context, key string) *IntCmd
 	HMGet(ctx context.Context, key string, fields ...string) *SliceCmd
+	HMGetBytes(ctx context.Context, dst [][]byte, key string, fields ...string) *BytesSliceCmd
 	HSet(ctx context.Context, key string, values ...interface{}) *IntCmd
 	HMSet(ctx context.Context, key string, values ...interface{}) *BoolCmd
 	HSetNX(ctx context.Context, key, field string, value interface{}) *BoolCmd
@@ -23,6 +24,24 @@ type HashCmdable interface {
 	HVals(ctx context.Context, key string) *StringSliceCmd
 	HRandField(ctx context.Context, key string, count int) *StringSliceCmd
 	HRandFieldWithValues(ctx context.Context, key string, count int) *KeyValueSliceCmd
+	HGetDel(ctx context.Context, key string, fields ...string) *SliceCmd
+	HGetEX(ctx context.Context, key string, fields ...string) *SliceCmd
+	HGetEXWithArgs(ctx context.Context, key string, a HGetEXArgs, fields ...string) *SliceCmd
+	HSetEX(ctx context.Context, key string, values ...interface{}) *IntCmd
+	HSetEXWithArgs(ctx context.Context, key string, a HSetEXArgs, values ...interface{}) *IntCmd
+	HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *IntSliceCmd
+	HExpireWithArgs(ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string) *IntSliceCmd
+	HPExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *IntSliceCmd
+	HPExpireWithArgs(ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string) *IntSliceCmd
+	HExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *IntSliceCmd
+	HExpireAtWithArgs(ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string) *IntSliceCmd
+	HPExpireAt(ctx context.Context, key string, tm time.Time, fields ...string) *IntSliceCmd
+	HPExpireAtWithArgs(ctx context.Context, key string, tm time.Time, expirationArgs HExpireArgs, fields ...string) *IntSliceCmd
+	HPersist(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HPExpireTime(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd
+	HPTTL(ctx context.Context, key string, fields ...string) *IntSliceCmd
 }
 
 func (c cmdable) HDel(ctx context.Context, key string, fields ...string) *IntCmd {
@@ -44,7 +63,7 @@ func (c cmdable) HExists(ctx context.Context, key, field string) *BoolCmd {
 }
 
 func (c cmdable) HGet(ctx context.Context, key, field string) *StringCmd {
-	cmd := NewStringCmd(ctx, "hget", key, field)
+	cmd := newPooledStringCmd(ctx, "hget", key, field)
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -93,6 +112,21 @@ func (c cmdable) HMGet(ctx context.Context, key string, fields ...string) *Slice
 	return cmd
 }
 
+// HMGetBytes is HMGet decoding the values into dst, see NewBytesSliceCmd, so
+// that reusing dst avoids the allocations of the values. The values of the
+// missing fields are reported by BytesSliceCmd.IsNil.
+func (c cmdable) HMGetBytes(ctx context.Context, dst [][]byte, key string, fields ...string) *BytesSliceCmd {
+	args := make([]interface{}, 2+len(fields))
+	args[0] = "hmget"
+	args[1] = key
+	for i, field := range fields {
+		args[2+i] = field
+	}
+	cmd := NewBytesSliceCmd(ctx, dst, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
 // HSet accepts values in following formats:
 //
 //   - HSet("myhash", "key1", "value1", "key2", "value2")
@@ -122,7 +156,7 @@ func (c cmdable) HSet(ctx context.Context, key string, values ...interface{}) *I
 	args[0] = "hset"
 	args[1] = key
 	args = appendArgs(args, values)
-	cmd := NewIntCmd(ctx, args...)
+	cmd := newPooledIntCmd(ctx, args...)
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -191,6 +225,14 @@ func (c cmdable) HScanNoValues(ctx context.Context, key string, cursor uint64, m
 	return cmd
 }
 
+// HExpireArgs provides the conditional flags of the HEXPIRE family of commands.
+// Only the first flag that is set is sent to the server.
+//
+// The HEXPIRE family replies with one code per field:
+//   - -2 the field does not exist (or the key does not exist);
+//   - 0 the condition was not met;
+//   - 1 the expiration was set or updated;
+//   - 2 the field was deleted because the expiration is in the past.
 type HExpireArgs struct {
 	NX bool
 	XX bool
@@ -199,10 +241,11 @@ type HExpireArgs struct {
 }
 
 // HExpire - Sets the expiration time for specified fields in a hash in seconds.
+// The expiration is truncated to seconds, with a minimum of 1s.
 // The command constructs an argument list starting with "HEXPIRE", followed by the key, duration, any conditional flags, and the specified fields.
 // For more information - https://redis.io/commands/hexpire/
 func (c cmdable) HExpire(ctx context.Context, key string, expiration time.Duration, fields ...string) *IntSliceCmd {
-	args := []interface{}{"HEXPIRE", key, expiration, "FIELDS", len(fields)}
+	args := []interface{}{"HEXPIRE", key, formatSec(ctx, expiration), "FIELDS", len(fields)}
 
 	for _, field := range fields {
 		args = append(args, field)
@@ -212,12 +255,12 @@ func (c cmdable) HExpire(ctx context.Context, key string, expiration time.Durati
 	return cmd
 }
 
-// HExpire - Sets the expiration time for specified fields in a hash in seconds.
+// HExpireWithArgs - Sets the expiration time for specified fields in a hash in seconds.
 // It requires a key, an expiration duration, a struct with boolean flags for conditional expiration settings (NX, XX, GT, LT), and a list of fields.
 // The command constructs an argument list starting with "HEXPIRE", followed by the key, duration, any conditional flags, and the specified fields.
 // For more information - https://redis.io/commands/hexpire/
 func (c cmdable) HExpireWithArgs(ctx context.Context, key string, expiration time.Duration, expirationArgs HExpireArgs, fields ...string) *IntSliceCmd {
-	args := []interface{}{"HEXPIRE", key, expiration}
+	args := []interface{}{"HEXPIRE", key, formatSec(ctx, expiration)}
 
 	// only if one argument is true, we can add it to the args
 	// if more than one argument is true, it will cause an error
@@ -435,3 +478,115 @@ func (c cmdable) HPTTL(ctx context.Context, key string, fields ...string) *IntSl
 	_ = c(ctx, cmd)
 	return cmd
 }
+
+// HGetDel - Returns the values of the specified fields and deletes them from the hash.
+// The key is removed once its last field is deleted.
+// It returns an interface{} to distinguish between empty string and nil value of a missing field.
+// For more information - https://redis.io/commands/hgetdel/
+func (c cmdable) HGetDel(ctx context.Context, key string, fields ...string) *SliceCmd {
+	args := []interface{}{"HGETDEL", key, "FIELDS", len(fields)}
+
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HGetEXArgs provides arguments for the HGetEXWithArgs function.
+type HGetEXArgs struct {
+	// Zero `TTL` or `ExpireAt` leaves the expiration of the fields unchanged.
+	TTL      time.Duration
+	ExpireAt time.Time
+
+	// Persist removes the expiration of the fields.
+	Persist bool
+}
+
+// HGetEX - Returns the values of the specified fields without changing their expiration.
+// For more information - https://redis.io/commands/hgetex/
+func (c cmdable) HGetEX(ctx context.Context, key string, fields ...string) *SliceCmd {
+	return c.HGetEXWithArgs(ctx, key, HGetEXArgs{}, fields...)
+}
+
+// HGetEXWithArgs - Returns the values of the specified fields and optionally sets or removes their expiration.
+// It returns an interface{} to distinguish between empty string and nil value of a missing field.
+// For more information - https://redis.io/commands/hgetex/
+func (c cmdable) HGetEXWithArgs(ctx context.Context, key string, a HGetEXArgs, fields ...string) *SliceCmd {
+	args := []interface{}{"HGETEX", key}
+
+	switch {
+	case a.Persist:
+		args = append(args, "PERSIST")
+	case !a.ExpireAt.IsZero():
+		args = append(args, "PXAT", a.ExpireAt.UnixMilli())
+	case a.TTL > 0:
+		if usePrecise(a.TTL) {
+			args = append(args, "PX", formatMs(ctx, a.TTL))
+		} else {
+			args = append(args, "EX", formatSec(ctx, a.TTL))
+		}
+	}
+
+	args = append(args, "FIELDS", len(fields))
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	cmd := NewSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// HSetEXArgs provides arguments for the HSetEXWithArgs function.
+type HSetEXArgs struct {
+	// Mode can be `FNX` (set only if none of the fields exist),
+	// `FXX` (set only if all the fields exist) or empty.
+	Mode string
+
+	// Zero `TTL` or `ExpireAt` means that the fields have no expiration time.
+	TTL      time.Duration
+	ExpireAt time.Time
+
+	// KeepTTL retains the expiration of the fields that already have one.
+	KeepTTL bool
+}
+
+// HSetEX - Sets the specified fields without an expiration.
+// Values are accepted in the same formats as HSet.
+// For more information - https://redis.io/commands/hsetex/
+func (c cmdable) HSetEX(ctx context.Context, key string, values ...interface{}) *IntCmd {
+	return c.HSetEXWithArgs(ctx, key, HSetEXArgs{}, values...)
+}
+
+// HSetEXWithArgs - Sets the specified fields and optionally their expiration.
+// Values are accepted in the same formats as HSet.
+// It returns 1 if all the fields were set and 0 if the Mode condition was not met.
+// For more information - https://redis.io/commands/hsetex/
+func (c cmdable) HSetEXWithArgs(ctx context.Context, key string, a HSetEXArgs, values ...interface{}) *IntCmd {
+	args := []interface{}{"HSETEX", key}
+
+	if a.Mode != "" {
+		args = append(args, a.Mode)
+	}
+
+	switch {
+	case a.KeepTTL:
+		args = append(args, "KEEPTTL")
+	case !a.ExpireAt.IsZero():
+		args = append(args, "PXAT", a.ExpireAt.UnixMilli())
+	case a.TTL > 0:
+		if usePrecise(a.TTL) {
+			args = append(args, "PX", formatMs(ctx, a.TTL))
+		} else {
+			args = append(args, "EX", formatSec(ctx, a.TTL))
+		}
+	}
+
+	fieldsAndValues := appendArgs(nil, values)
+	args = append(args, "FIELDS", len(fieldsAndValues)/2)
+	args = append(args, fieldsAndValues...)
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
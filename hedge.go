@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+var defaultHedgedCmds = []string{
+	"get", "mget", "getrange", "strlen", "exists", "type", "ttl", "pttl",
+	"hget", "hmget", "hgetall", "hkeys", "hvals", "hlen", "hexists",
+	"lrange", "lindex", "llen",
+	"smembers", "sismember", "smismember", "scard",
+	"zrange", "zrangebyscore", "zrevrange", "zscore", "zmscore", "zrank", "zcard", "zcount",
+	"xrange", "xrevrange", "xlen",
+}
+
+func newHedgedCmds(opt *Options) map[string]struct{} {
+	if opt.HedgeDelay <= 0 {
+		return nil
+	}
+	names := opt.HedgeCommands
+	if len(names) == 0 {
+		names = defaultHedgedCmds
+	}
+	cmds := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		cmds[internal.ToLower(name)] = struct{}{}
+	}
+	return cmds
+}
+
+// hedged reports whether cmd is hedged, see Options.HedgeDelay.
+func (c *baseClient) hedged(cmd Cmder) bool {
+	if c.hedgedCmds == nil || cmd.readTimeout() != nil {
+		return false
+	}
+	_, ok := c.hedgedCmds[cmd.Name()]
+	return ok
+}
+
+type hedgeReply struct {
+	reply     []byte
+	out       int
+	retryable bool
+	err       error
+}
+
+// processHedged processes an attempt of cmd sent on up to two connections,
+// the second one after HedgeDelay, or right away when the first one fails
+// with a retryable error, and reads the first reply into cmd. When both fail,
+// it returns the last error, and whether it is retryable for the attempt to
+// be retried as the other commands.
+//
+// The attempts read the replies as bytes, which are read into cmd once, as
+// cmd can't be shared by the attempts; the attempt which loses completes in
+// the background for its connection to be reused. As the caller owns cmd
+// again once processHedged returns, e.g. to release it, the attempts write
+// the arguments serialized beforehand and do not use cmd.
+func (c *baseClient) processHedged(ctx context.Context, cmd Cmder, callOpt *CallOptions) (bool, error) {
+	var buf bytes.Buffer
+	if err := proto.NewWriter(&buf).WriteArgs(cmd.Args()); err != nil {
+		return false, err
+	}
+	req := buf.Bytes()
+
+	replies := make(chan hedgeReply, 2)
+	attempt := func() {
+		replies <- c.hedgeAttempt(ctx, req, callOpt)
+	}
+	go attempt()
+
+	timer := time.NewTimer(c.opt.HedgeDelay)
+	defer timer.Stop()
+
+	pending, hedged := 1, false
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				pending++
+				go attempt()
+			}
+		case r := <-replies:
+			pending--
+			if r.err == nil {
+				return false, c.readHedgeReply(cmd, r)
+			}
+			if !hedged && r.retryable {
+				hedged = true
+				pending++
+				go attempt()
+			}
+			if pending == 0 {
+				return r.retryable, r.err
+			}
+		}
+	}
+}
+
+// hedgeAttempt writes req, the serialized arguments of a hedged command, and
+// captures its reply. The commands hedged have no read timeout of their own,
+// see hedged.
+func (c *baseClient) hedgeAttempt(ctx context.Context, req []byte, callOpt *CallOptions) hedgeReply {
+	var r hedgeReply
+	r.err = c.withConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
+		writeTimeout := c.maint.timeout(callOpt.writeTimeout(c.opt.WriteTimeout))
+		if err := cn.WithWriter(c.context(ctx), writeTimeout, func(wr *proto.Writer) error {
+			_, err := wr.Write(req)
+			r.out = len(req)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		readTimeout := c.maint.timeout(callOpt.readTimeout(c.opt.ReadTimeout))
+		return cn.WithReader(c.context(ctx), readTimeout, func(rd *proto.Reader) error {
+			if err := c.pushes.process(ctx, rd); err != nil {
+				return err
+			}
+			var err error
+			r.reply, err = rd.Capture(rd.DiscardNext)
+			return err
+		})
+	})
+	r.retryable = shouldRetry(r.err, true)
+	return r
+}
+
+func (c *baseClient) readHedgeReply(cmd Cmder, r hedgeReply) error {
+	rd := proto.NewReaderSize(bytes.NewReader(r.reply), len(r.reply))
+	err := cmd.readReply(rd)
+	wire := cmd.wireBytes()
+	wire.out = uint32(r.out)
+	wire.in = uint32(len(r.reply))
+	return err
+}
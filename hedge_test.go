@@ -0,0 +1,184 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// slowReadConn delays its reads while slow is set.
+type slowReadConn struct {
+	net.Conn
+	slow *int32
+}
+
+func (cn slowReadConn) Read(b []byte) (int, error) {
+	if atomic.LoadInt32(cn.slow) == 1 {
+		time.Sleep(200 * time.Millisecond)
+	}
+	return cn.Conn.Read(b)
+}
+
+func TestHedgeDelay(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var dials, slow int32
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       srv.Addr(),
+		HedgeDelay: 10 * time.Millisecond,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			cn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			if atomic.AddInt32(&dials, 1) == 1 {
+				// only the first connection is slow
+				return slowReadConn{Conn: cn, slow: &slow}, nil
+			}
+			return cn, nil
+		},
+		StatsEnabled: true,
+	})
+	defer rdb.Close()
+
+	if err := rdb.Set(ctx, "key", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 1 {
+		t.Fatalf("got %d dials for SET, wanted 1", n)
+	}
+
+	atomic.StoreInt32(&slow, 1)
+	start := time.Now()
+	v, err := rdb.Get(ctx, "key").Result()
+	if err != nil || v != "v" {
+		t.Fatalf("got %q, %v", v, err)
+	}
+	if d := time.Since(start); d >= 150*time.Millisecond {
+		t.Fatalf("got the reply after %s, wanted the hedged reply", d)
+	}
+	if n := atomic.LoadInt32(&dials); n != 2 {
+		t.Fatalf("got %d dials, wanted a second connection", n)
+	}
+	if get := rdb.Stats()["get"]; get.BytesIn != 7 || get.BytesOut != 22 {
+		t.Fatalf("got %+v, wanted the sizes of a single GET", get)
+	}
+
+	atomic.StoreInt32(&slow, 0)
+	if err := rdb.Get(ctx, "missing").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+	if err := rdb.HGetAll(ctx, "key").Err(); err == nil {
+		t.Fatal("HGETALL of a string succeeded")
+	}
+}
+
+// getWriteConn sends its writes of GET commands to written.
+type getWriteConn struct {
+	net.Conn
+	written chan<- string
+}
+
+func (cn getWriteConn) Write(b []byte) (int, error) {
+	if strings.HasPrefix(string(b), "*2\r\n$3\r\nget\r\n") {
+		cn.written <- string(b)
+	}
+	return cn.Conn.Write(b)
+}
+
+func TestHedgeLoserAfterReturn(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var dials, slow int32
+	written := make(chan string, 1)
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       srv.Addr(),
+		HedgeDelay: 10 * time.Millisecond,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if atomic.AddInt32(&dials, 1) == 1 {
+				cn, err := net.Dial(network, addr)
+				return slowReadConn{Conn: cn, slow: &slow}, err
+			}
+			// the hedged attempt writes after the first one returned
+			time.Sleep(400 * time.Millisecond)
+			cn, err := net.Dial(network, addr)
+			return getWriteConn{Conn: cn, written: written}, err
+		},
+	})
+	defer rdb.Close()
+
+	if err := rdb.Set(ctx, "key", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreInt32(&slow, 1)
+	cmd := rdb.Get(ctx, "key")
+	if cmd.Val() != "v" {
+		t.Fatalf("got %v", cmd)
+	}
+	// the caller owns the command again
+	cmd.Args()[1] = "other"
+	cmd.Release()
+
+	select {
+	case w := <-written:
+		if want := "*2\r\n$3\r\nget\r\n$3\r\nkey\r\n"; w != want {
+			t.Fatalf("the hedged attempt wrote %q, wanted %q", w, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the hedged attempt did not write")
+	}
+}
+
+func TestHedgeRetries(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	setup := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	if err := setup.Set(ctx, "key", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	_ = setup.Close()
+
+	var dials int32
+	errDial := errors.New("dial failed")
+	newClient := func(maxRetries int) *redis.Client {
+		atomic.StoreInt32(&dials, 0)
+		return redis.NewClient(&redis.Options{
+			Addr:            srv.Addr(),
+			HedgeDelay:      10 * time.Millisecond,
+			MaxRetries:      maxRetries,
+			MinRetryBackoff: time.Millisecond,
+			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				// the connections of the hedged attempt fail
+				if atomic.AddInt32(&dials, 1) <= 2 {
+					return nil, &net.OpError{Op: "dial", Net: network, Err: errDial}
+				}
+				return net.Dial(network, addr)
+			},
+		})
+	}
+
+	rdb := newClient(1)
+	defer rdb.Close()
+	if v, err := rdb.Get(ctx, "key").Result(); err != nil || v != "v" {
+		t.Fatalf("got %q, %v, wanted the reply of the retry", v, err)
+	}
+
+	rdb = newClient(-1)
+	defer rdb.Close()
+	if err := rdb.Get(ctx, "key").Err(); !errors.Is(err, errDial) {
+		t.Fatalf("got %v, wanted the dial error", err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 2 {
+		t.Fatalf("got %d dials, wanted both hedged connections", n)
+	}
+}
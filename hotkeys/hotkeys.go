@@ -0,0 +1,432 @@
+// Package hotkeys finds the most accessed keys of a deployment, per server:
+//
+//	report, err := hotkeys.ScanFreq(ctx, client, &hotkeys.Options{Top: 20})
+//	for _, node := range report.Nodes {
+//		fmt.Println(node.Addr, node.Keys)
+//	}
+//
+// The keys are ranked with one of three sources:
+//   - ScanFreq scans the keys with their OBJECT FREQ, the access frequency
+//     counters of the servers, which requires an LFU maxmemory-policy.
+//   - Monitor counts the keys of the commands received by the servers during
+//     a window with MONITOR, which slows down the servers.
+//   - Counter is a hook counting the keys of the commands of the client.
+package hotkeys
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal/util"
+)
+
+// HotKey is the count of accesses of a key: its LFU frequency counter for
+// ScanFreq, and its number of commands for Monitor and Counter.
+type HotKey struct {
+	Key   string
+	Count int64
+}
+
+// NodeReport are the hot keys of a server, the hottest first.
+type NodeReport struct {
+	Addr string
+	Keys []HotKey
+}
+
+// Report are the hot keys of the servers of a deployment, sorted by address.
+type Report struct {
+	Nodes []NodeReport
+}
+
+type Options struct {
+	// Top is the number of hot keys reported per server. Default is 10.
+	Top int
+	// Match only ranks the keys matching the pattern. Default is all the keys.
+	Match string
+	// BatchSize is the COUNT of SCAN and the number of OBJECT FREQ per
+	// pipeline of ScanFreq. Default is 100.
+	BatchSize int
+}
+
+func (opt *Options) init() Options {
+	var o Options
+	if opt != nil {
+		o = *opt
+	}
+	if o.Top <= 0 {
+		o.Top = 10
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	return o
+}
+
+// ScanFreq ranks the keys of every master of a cluster, every shard of a
+// ring, or the server of a client, by their OBJECT FREQ. It scans the whole
+// keyspace of the servers.
+func ScanFreq(ctx context.Context, client redis.UniversalClient, opt *Options) (*Report, error) {
+	o := opt.init()
+	rep := new(report)
+	err := forEachNode(ctx, client, func(ctx context.Context, node *redis.Client) error {
+		top := newTopK(o.Top)
+		var cursor uint64
+		for {
+			keys, next, err := node.Scan(ctx, cursor, o.Match, int64(o.BatchSize)).Result()
+			if err != nil {
+				return err
+			}
+
+			cmds := make([]*redis.IntCmd, len(keys))
+			_, _ = node.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for i, key := range keys {
+					cmds[i] = pipe.ObjectFreq(ctx, key)
+				}
+				return nil
+			})
+			for i, cmd := range cmds {
+				freq, err := cmd.Result()
+				if err == redis.Nil {
+					// deleted after the scan
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("hotkeys: OBJECT FREQ of %s: %w", node.Options().Addr, err)
+				}
+				top.add(keys[i], freq)
+			}
+
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+		rep.add(node.Options().Addr, top)
+		return nil
+	})
+	return rep.report(), err
+}
+
+// Monitor ranks the keys of the commands received by every master of a
+// cluster, every shard of a ring, or the server of a client, during window.
+// Each server is monitored on a dedicated connection, closed at the end of
+// the window.
+func Monitor(ctx context.Context, client redis.UniversalClient, window time.Duration, opt *Options) (*Report, error) {
+	o := opt.init()
+	rep := new(report)
+	err := forEachNode(ctx, client, func(ctx context.Context, node *redis.Client) error {
+		counts, err := monitor(ctx, node, window, o.Match)
+		if err != nil {
+			return fmt.Errorf("hotkeys: MONITOR of %s: %w", node.Options().Addr, err)
+		}
+		top := newTopK(o.Top)
+		for key, n := range counts {
+			top.add(key, n)
+		}
+		rep.add(node.Options().Addr, top)
+		return nil
+	})
+	return rep.report(), err
+}
+
+func monitor(ctx context.Context, node *redis.Client, window time.Duration, match string) (map[string]int64, error) {
+	opt := *node.Options()
+	opt.PoolSize = 1
+	opt.MinIdleConns = 0
+	mc := redis.NewClient(&opt)
+	defer mc.Close()
+
+	lines := make(chan string, 1000)
+	cmd := mc.Monitor(ctx, lines)
+	if err := cmd.Err(); err != nil {
+		return nil, err
+	}
+	cmd.Start()
+	defer func() {
+		cmd.Stop()
+		_ = mc.Close()
+		// unblock the monitor until it sees the closed connection
+		go func() {
+			for {
+				select {
+				case <-lines:
+				case <-time.After(time.Second):
+					return
+				}
+			}
+		}()
+	}()
+
+	counts := make(map[string]int64)
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	for {
+		select {
+		case line := <-lines:
+			args, ok := parseMonitorLine(line)
+			if !ok {
+				continue
+			}
+			if key, ok := commandKey(args); ok && matchKey(match, key) {
+				counts[key]++
+			}
+		case <-timer.C:
+			return counts, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// parseMonitorLine parses the arguments of a MONITOR line such as
+//
+//	1339518083.107412 [0 127.0.0.1:60866] "set" "key" "value"
+func parseMonitorLine(line string) ([]string, bool) {
+	i := strings.Index(line, "] ")
+	if i < 0 {
+		return nil, false
+	}
+	line = line[i+2:]
+
+	var args []string
+	for len(line) > 0 {
+		if line[0] != '"' {
+			return nil, false
+		}
+		var arg []byte
+		i := 1
+		for ; i < len(line) && line[i] != '"'; i++ {
+			if line[i] != '\\' || i+1 >= len(line) {
+				arg = append(arg, line[i])
+				continue
+			}
+			i++
+			switch line[i] {
+			case 'n':
+				arg = append(arg, '\n')
+			case 'r':
+				arg = append(arg, '\r')
+			case 't':
+				arg = append(arg, '\t')
+			case 'a':
+				arg = append(arg, '\a')
+			case 'b':
+				arg = append(arg, '\b')
+			case 'x':
+				if i+2 < len(line) {
+					if b, err := strconv.ParseUint(line[i+1:i+3], 16, 8); err == nil {
+						arg = append(arg, byte(b))
+						i += 2
+						continue
+					}
+				}
+				arg = append(arg, 'x')
+			default:
+				arg = append(arg, line[i])
+			}
+		}
+		if i >= len(line) {
+			return nil, false
+		}
+		args = append(args, string(arg))
+		line = strings.TrimPrefix(line[i+1:], " ")
+	}
+	return args, len(args) > 0
+}
+
+// keylessCommands are the commands whose first argument is not a key.
+var keylessCommands = map[string]struct{}{
+	"acl": {}, "auth": {}, "bgrewriteaof": {}, "bgsave": {}, "client": {},
+	"cluster": {}, "command": {}, "config": {}, "dbsize": {}, "debug": {},
+	"echo": {}, "eval": {}, "eval_ro": {}, "evalsha": {}, "evalsha_ro": {},
+	"exec": {}, "fcall": {}, "fcall_ro": {}, "flushall": {}, "flushdb": {},
+	"function": {}, "hello": {}, "info": {}, "keys": {}, "latency": {},
+	"memory": {}, "module": {}, "monitor": {}, "multi": {}, "object": {},
+	"ping": {}, "psubscribe": {}, "publish": {}, "pubsub": {},
+	"punsubscribe": {}, "quit": {}, "readonly": {}, "readwrite": {},
+	"replicaof": {}, "reset": {}, "role": {}, "save": {}, "scan": {},
+	"script": {}, "select": {}, "slaveof": {}, "slowlog": {}, "spublish": {},
+	"ssubscribe": {}, "subscribe": {}, "sunsubscribe": {}, "swapdb": {},
+	"time": {}, "unsubscribe": {}, "unwatch": {}, "wait": {}, "xread": {},
+	"xreadgroup": {},
+}
+
+// commandKey returns the first key of the command args.
+func commandKey(args []string) (string, bool) {
+	if len(args) < 2 {
+		return "", false
+	}
+	if _, ok := keylessCommands[strings.ToLower(args[0])]; ok {
+		return "", false
+	}
+	return args[1], true
+}
+
+func matchKey(pattern, key string) bool {
+	return pattern == "" || util.GlobMatch(pattern, key)
+}
+
+// Counter is a hook counting the commands of a client per key. It counts the
+// keys of the commands sent by the client, whatever the server: for counts
+// per server, add a Counter to each node of a cluster with
+// ClusterOptions.OnNewNode.
+type Counter struct {
+	match string
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+var _ redis.Hook = (*Counter)(nil)
+
+// NewCounter returns a counter of the keys matching match, or of all the keys
+// when match is empty.
+func NewCounter(match string) *Counter {
+	return &Counter{
+		match:  match,
+		counts: make(map[string]int64),
+	}
+}
+
+// Top returns the n keys with the most commands, the hottest first.
+func (c *Counter) Top(n int) []HotKey {
+	top := newTopK(n)
+	c.mu.Lock()
+	for key, count := range c.counts {
+		top.add(key, count)
+	}
+	c.mu.Unlock()
+	return top.sorted()
+}
+
+// Reset forgets the counts, e.g. to count the keys per period.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	c.counts = make(map[string]int64)
+	c.mu.Unlock()
+}
+
+func (c *Counter) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (c *Counter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		c.count(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+func (c *Counter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			c.count(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func (c *Counter) count(cmd redis.Cmder) {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return
+	}
+	key, ok := args[1].(string)
+	if !ok {
+		return
+	}
+	if _, ok := keylessCommands[cmd.Name()]; ok || !matchKey(c.match, key) {
+		return
+	}
+	c.mu.Lock()
+	c.counts[key]++
+	c.mu.Unlock()
+}
+
+func forEachNode(ctx context.Context, client redis.UniversalClient, fn func(ctx context.Context, node *redis.Client) error) error {
+	switch client := client.(type) {
+	case *redis.Client:
+		return fn(ctx, client)
+	case *redis.ClusterClient:
+		return client.ForEachMaster(ctx, fn)
+	case *redis.Ring:
+		return client.ForEachShard(ctx, fn)
+	}
+	return fmt.Errorf("hotkeys: unsupported client %T", client)
+}
+
+// report collects the node reports concurrently.
+type report struct {
+	mu    sync.Mutex
+	nodes []NodeReport
+}
+
+func (r *report) add(addr string, top *topK) {
+	r.mu.Lock()
+	r.nodes = append(r.nodes, NodeReport{Addr: addr, Keys: top.sorted()})
+	r.mu.Unlock()
+}
+
+func (r *report) report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.Slice(r.nodes, func(i, j int) bool { return r.nodes[i].Addr < r.nodes[j].Addr })
+	return &Report{Nodes: r.nodes}
+}
+
+// topK keeps the n hottest keys in a min-heap.
+type topK struct {
+	n    int
+	keys []HotKey
+}
+
+func newTopK(n int) *topK {
+	return &topK{n: n}
+}
+
+func (t *topK) add(key string, count int64) {
+	if t.n <= 0 {
+		return
+	}
+	if len(t.keys) < t.n {
+		heap.Push(t, HotKey{Key: key, Count: count})
+		return
+	}
+	if count > t.keys[0].Count {
+		t.keys[0] = HotKey{Key: key, Count: count}
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the keys, the hottest first.
+func (t *topK) sorted() []HotKey {
+	keys := append([]HotKey(nil), t.keys...)
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Count != keys[j].Count {
+			return keys[i].Count > keys[j].Count
+		}
+		return keys[i].Key < keys[j].Key
+	})
+	return keys
+}
+
+func (t *topK) Len() int           { return len(t.keys) }
+func (t *topK) Less(i, j int) bool { return t.keys[i].Count < t.keys[j].Count }
+func (t *topK) Swap(i, j int)      { t.keys[i], t.keys[j] = t.keys[j], t.keys[i] }
+
+func (t *topK) Push(x interface{}) {
+	t.keys = append(t.keys, x.(HotKey))
+}
+
+func (t *topK) Pop() interface{} {
+	key := t.keys[len(t.keys)-1]
+	t.keys = t.keys[:len(t.keys)-1]
+	return key
+}
@@ -0,0 +1,80 @@
+package hotkeys
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+	srv := redistest.NewServer()
+	defer srv.Close()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+
+	counter := NewCounter("user:*")
+	client.AddHook(counter)
+
+	for i := 0; i < 3; i++ {
+		client.Incr(ctx, "user:1")
+	}
+	client.Get(ctx, "user:2")
+	client.Get(ctx, "other")
+	client.Ping(ctx)
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Get(ctx, "user:2")
+		pipe.Get(ctx, "user:3")
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		t.Fatal(err)
+	}
+
+	want := []HotKey{{"user:1", 3}, {"user:2", 2}}
+	if got := counter.Top(2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+
+	counter.Reset()
+	if got := counter.Top(2); len(got) != 0 {
+		t.Fatalf("got %v after reset", got)
+	}
+}
+
+func TestParseMonitorLine(t *testing.T) {
+	tests := []struct {
+		line string
+		args []string
+	}{
+		{`1339518083.107412 [0 127.0.0.1:60866] "set" "key" "value"`, []string{"set", "key", "value"}},
+		{`1339518083.107412 [0 lua] "get" "a \"b\"\\c\x01\n"`, []string{"get", "a \"b\"\\c\x01\n"}},
+		{`1339518083.107412 [0 unix:/tmp/redis.sock] "ping"`, []string{"ping"}},
+	}
+	for _, test := range tests {
+		args, ok := parseMonitorLine(test.line)
+		if !ok || !reflect.DeepEqual(args, test.args) {
+			t.Errorf("parseMonitorLine(%q) = %q, %v, wanted %q", test.line, args, ok, test.args)
+		}
+	}
+
+	for _, line := range []string{"OK", `1339518083.107412 [0 127.0.0.1:60866] "unterminated`} {
+		if _, ok := parseMonitorLine(line); ok {
+			t.Errorf("parseMonitorLine(%q) succeeded", line)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	top := newTopK(3)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		top.add(key, int64([]int{5, 1, 4, 2, 3}[i]))
+	}
+	want := []HotKey{{"a", 5}, {"c", 4}, {"e", 3}}
+	if got := top.sorted(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+}
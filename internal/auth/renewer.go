@@ -0,0 +1,156 @@
+// Package auth implements the renewal of the short-lived tokens of the
+// providers of the auth packages.
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// retryInterval is the interval between the attempts to renew a token after a
+// failure.
+const retryInterval = 10 * time.Second
+
+// Token is the username and password of a token, and the time it must be
+// renewed at.
+type Token struct {
+	Username string
+	Password string
+	RenewAt  time.Time
+}
+
+// Renewer fetches a token on first use and renews it at its RenewAt time,
+// notifying its subscribers of the renewals, until it is closed.
+type Renewer struct {
+	fetch   func(ctx context.Context) (Token, error)
+	onError func(err error)
+
+	mu      sync.Mutex
+	token   *Token
+	subs    map[int]func()
+	nextSub int
+
+	fetched   chan struct{}
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewRenewer returns a renewer of the tokens of fetch, calling onError, when
+// set, with the errors of the renewals.
+func NewRenewer(fetch func(ctx context.Context) (Token, error), onError func(err error)) *Renewer {
+	r := &Renewer{
+		fetch:   fetch,
+		onError: onError,
+		subs:    make(map[int]func()),
+		fetched: make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.renewLoop()
+	return r
+}
+
+// Credentials returns the username and password of the current token, which
+// is fetched first when there is none or it is due for renewal.
+func (r *Renewer) Credentials(ctx context.Context) (username, password string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.token == nil || !time.Now().Before(r.token.RenewAt) {
+		token, err := r.fetch(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		r.token = &token
+		select {
+		case r.fetched <- struct{}{}:
+		default:
+		}
+	}
+	return r.token.Username, r.token.Password, nil
+}
+
+// Subscribe calls fn each time the token is renewed, until unsubscribe is
+// called.
+func (r *Renewer) Subscribe(fn func()) (unsubscribe func()) {
+	r.mu.Lock()
+	id := r.nextSub
+	r.nextSub++
+	r.subs[id] = fn
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		delete(r.subs, id)
+		r.mu.Unlock()
+	}
+}
+
+// Close stops the renewals.
+func (r *Renewer) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	r.wg.Wait()
+	return nil
+}
+
+func (r *Renewer) renewLoop() {
+	defer r.wg.Done()
+
+	// the first token is fetched by Credentials
+	select {
+	case <-r.fetched:
+	case <-r.closed:
+		return
+	}
+
+	timer := time.NewTimer(r.untilRenewal())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-r.closed:
+			return
+		}
+
+		wait := retryInterval
+		if err := r.renew(); err != nil {
+			if r.onError != nil {
+				r.onError(err)
+			}
+		} else {
+			wait = r.untilRenewal()
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (r *Renewer) untilRenewal() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Until(r.token.RenewAt)
+}
+
+func (r *Renewer) renew() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	token, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.token = &token
+	subs := make([]func(), 0, len(r.subs))
+	for _, fn := range r.subs {
+		subs = append(subs, fn)
+	}
+	r.mu.Unlock()
+
+	for _, fn := range subs {
+		fn()
+	}
+	return nil
+}
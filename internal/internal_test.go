@@ -1,6 +1,12 @@
 package internal
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,3 +22,31 @@ func TestRetryBackoff(t *testing.T) {
 		Expect(backoff <= 512*time.Millisecond).To(BeTrue())
 	}
 }
+
+func TestFormatLog(t *testing.T) {
+	tests := []struct {
+		args []interface{}
+		want string
+	}{
+		{nil, "msg"},
+		{[]interface{}{"addr", "db:6379", "slot", 42}, "msg addr=db:6379 slot=42"},
+		{[]interface{}{"addr", "db:6379", "extra"}, "msg addr=db:6379 !BADKEY=extra"},
+	}
+	for _, tt := range tests {
+		if got := formatLog("msg", tt.args); got != tt.want {
+			t.Errorf("formatLog(%v) = %q, wanted %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLogCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(l Logging) { Logger = l }(Logger)
+	Logger = &logger{log: log.New(&buf, "", log.Lshortfile)}
+
+	_, _, line, _ := runtime.Caller(0)
+	Log(context.Background(), nil, LevelWarn, "msg", "key", "value")
+	if want := fmt.Sprintf("internal_test.go:%d: msg key=value", line+1); !strings.HasPrefix(buf.String(), want) {
+		t.Fatalf("got %q, wanted %q", buf.String(), want)
+	}
+}
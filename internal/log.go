@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 )
 
 type Logging interface {
@@ -24,3 +25,62 @@ func (l *logger) Printf(ctx context.Context, format string, v ...interface{}) {
 var Logger Logging = &logger{
 	log: log.New(os.Stderr, "redis: ", log.LstdFlags|log.Lshortfile),
 }
+
+// StructuredLogger is a leveled logger of messages with key-value pairs,
+// which *slog.Logger implements.
+type StructuredLogger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Log logs msg with the key-value pairs args at level with l, or with Logger
+// when l is nil, which prints them as "msg key=value ...".
+func Log(ctx context.Context, l StructuredLogger, level Level, msg string, args ...interface{}) {
+	if l == nil {
+		s := formatLog(msg, args)
+		if l, ok := Logger.(*logger); ok {
+			// the caller of Log is the caller to report
+			_ = l.log.Output(2, s)
+			return
+		}
+		Logger.Printf(ctx, "%s", s)
+		return
+	}
+
+	switch level {
+	case LevelDebug:
+		l.DebugContext(ctx, msg, args...)
+	case LevelInfo:
+		l.InfoContext(ctx, msg, args...)
+	case LevelWarn:
+		l.WarnContext(ctx, msg, args...)
+	default:
+		l.ErrorContext(ctx, msg, args...)
+	}
+}
+
+func formatLog(msg string, args []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		b.WriteByte(' ')
+		if i+1 == len(args) {
+			// a value without key, as slog reports it
+			fmt.Fprintf(&b, "!BADKEY=%v", args[i])
+			break
+		}
+		fmt.Fprintf(&b, "%v=%v", args[i], args[i+1])
+	}
+	return b.String()
+}
@@ -12,31 +12,53 @@ import (
 
 var noDeadline = time.Time{}
 
+// connID is the id of the last connection.
+var connID uint64 // atomic
+
 type Conn struct {
 	usedAt  int64 // atomic
+	id      uint64
 	netConn net.Conn
 
 	rd *proto.Reader
 	bw *bufio.Writer
 	wr *proto.Writer
 
-	Inited    bool
-	pooled    bool
-	createdAt time.Time
+	Inited     bool
+	pooled     bool
+	createdAt  time.Time
+	generation uint32
+
+	// CredentialsGeneration is the generation of the credentials the
+	// connection is authenticated with.
+	CredentialsGeneration uint32
 }
 
 func NewConn(netConn net.Conn) *Conn {
+	return NewConnSize(netConn, 0, 0)
+}
+
+// NewConnSize returns the connection of netConn with read and write buffers
+// of the given sizes, or of the default size of bufio when they are not
+// positive.
+func NewConnSize(netConn net.Conn, readBufferSize, writeBufferSize int) *Conn {
 	cn := &Conn{
+		id:        atomic.AddUint64(&connID, 1),
 		netConn:   netConn,
 		createdAt: time.Now(),
 	}
-	cn.rd = proto.NewReader(netConn)
-	cn.bw = bufio.NewWriter(netConn)
+	cn.rd = proto.NewReaderSize(netConn, readBufferSize)
+	cn.bw = bufio.NewWriterSize(netConn, writeBufferSize)
 	cn.wr = proto.NewWriter(cn.bw)
 	cn.SetUsedAt(time.Now())
 	return cn
 }
 
+// ID returns the id of the connection, unique in the process.
+func (cn *Conn) ID() uint64 {
+	return cn.id
+}
+
 func (cn *Conn) UsedAt() time.Time {
 	unix := atomic.LoadInt64(&cn.usedAt)
 	return time.Unix(unix, 0)
@@ -63,6 +85,12 @@ func (cn *Conn) RemoteAddr() net.Addr {
 	return nil
 }
 
+// HasPendingData reports whether there is unread data on the connection,
+// either already buffered or waiting on the socket.
+func (cn *Conn) HasPendingData() bool {
+	return cn.rd.Buffered() > 0 || connCheck(cn.netConn) == errUnexpectedRead
+}
+
 func (cn *Conn) WithReader(
 	ctx context.Context, timeout time.Duration, fn func(rd *proto.Reader) error,
 ) error {
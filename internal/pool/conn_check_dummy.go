@@ -2,7 +2,12 @@
 
 package pool
 
-import "net"
+import (
+	"errors"
+	"net"
+)
+
+var errUnexpectedRead = errors.New("unexpected read from socket")
 
 func connCheck(conn net.Conn) error {
 	return nil
@@ -12,3 +12,11 @@ func (cn *Conn) SetCreatedAt(tm time.Time) {
 func (cn *Conn) NetConn() net.Conn {
 	return cn.netConn
 }
+
+func (cn *Conn) BufferSizes() (int, int) {
+	return cn.rd.Size(), cn.bw.Size()
+}
+
+func (p *ConnPool) Adapt() {
+	p.adapt()
+}
@@ -21,6 +21,10 @@ var (
 
 	// ErrPoolTimeout timed out waiting to get a connection from the connection pool.
 	ErrPoolTimeout = errors.New("redis: connection pool timeout")
+
+	// ErrConnStale is the reason a connection is closed when it is idle or
+	// used for too long, retired, or found closed by the server.
+	ErrConnStale = errors.New("redis: connection is stale")
 )
 
 var timers = sync.Pool{
@@ -37,6 +41,10 @@ type Stats struct {
 	Misses   uint32 // number of times free connection was NOT found in the pool
 	Timeouts uint32 // number of times a wait timeout occurred
 
+	WaitCount      uint32 // number of times a connection was waited for
+	WaitDurationNs int64  // total time spent waiting for a connection, in nanoseconds
+
+	PoolSize   uint32 // size of the pool, which varies when it is adaptive
 	TotalConns uint32 // number of total connections in the pool
 	IdleConns  uint32 // number of idle connections in the pool
 	StaleConns uint32 // number of stale connections removed from the pool
@@ -68,6 +76,40 @@ type Options struct {
 	MaxActiveConns  int
 	ConnMaxIdleTime time.Duration
 	ConnMaxLifetime time.Duration
+
+	// ReadBufferSize and WriteBufferSize are the sizes of the buffers of
+	// the connections, the default size of bufio when they are not positive.
+	ReadBufferSize  int
+	WriteBufferSize int
+
+	// StringInterner returns the strings of the replies, see
+	// proto.Reader.SetStringInterner.
+	StringInterner func(b []byte) string
+
+	// OnDial is called with the connections dialed by the pool, and OnClose
+	// with the connections it closes and the reason, which is nil when a
+	// healthy connection is closed as it is not needed.
+	OnDial  func(cn *Conn)
+	OnClose func(cn *Conn, reason error)
+	// OnAcquire is called with the connections returned by Get, and
+	// OnRelease with the connections given back with Put or Remove.
+	OnAcquire func(cn *Conn)
+	OnRelease func(cn *Conn)
+
+	// PushNotifications keeps idle connections that have unread data,
+	// which is expected to be RESP3 push notifications the client drains
+	// before the connection is used again.
+	PushNotifications bool
+
+	// Logger logs the messages of the pool, or internal.Logger when nil.
+	Logger internal.StructuredLogger
+
+	// MaxPoolSize, when greater than MinPoolSize, makes the size of the pool
+	// adaptive: starting at PoolSize, it is adjusted every AdaptInterval
+	// between MinPoolSize and MaxPoolSize, see ConnPool.adapt.
+	MinPoolSize   int
+	MaxPoolSize   int
+	AdaptInterval time.Duration
 }
 
 type lastDialErrorWrap struct {
@@ -89,8 +131,21 @@ type ConnPool struct {
 	poolSize     int
 	idleConnsLen int
 
+	// size is the size of the pool, PoolSize unless it is adaptive. The
+	// queue of an adaptive pool has room for MaxPoolSize turns, and the held
+	// turns beyond size are kept in it.
+	size      int32  // atomic
+	held      int32  // atomic
+	peakInUse int32  // atomic, the most turns in use since the last adapt
+	lastWaits uint32 // the WaitCount of the last adapt
+	adaptStop chan struct{}
+
 	stats Stats
 
+	// generation is incremented by RetireConns; the connections dialed in a
+	// previous generation are closed instead of being reused.
+	generation uint32 // atomic
+
 	_closed uint32 // atomic
 }
 
@@ -100,9 +155,14 @@ func NewConnPool(opt *Options) *ConnPool {
 	p := &ConnPool{
 		cfg: opt,
 
-		queue:     make(chan struct{}, opt.PoolSize),
+		queue:     make(chan struct{}, opt.maxPoolSize()),
 		conns:     make([]*Conn, 0, opt.PoolSize),
 		idleConns: make([]*Conn, 0, opt.PoolSize),
+
+		size: int32(opt.PoolSize),
+	}
+	if opt.adaptive() {
+		p.initAdaptive()
 	}
 
 	p.connsMu.Lock()
@@ -116,29 +176,51 @@ func (p *ConnPool) checkMinIdleConns() {
 	if p.cfg.MinIdleConns == 0 {
 		return
 	}
-	for p.poolSize < p.cfg.PoolSize && p.idleConnsLen < p.cfg.MinIdleConns {
-		select {
-		case p.queue <- struct{}{}:
-			p.poolSize++
-			p.idleConnsLen++
-
-			go func() {
-				err := p.addIdleConn()
-				if err != nil && err != ErrClosed {
-					p.connsMu.Lock()
-					p.poolSize--
-					p.idleConnsLen--
-					p.connsMu.Unlock()
-				}
-
-				p.freeTurn()
-			}()
-		default:
+	for p.poolSize < p.Size() && p.idleConnsLen < p.cfg.MinIdleConns {
+		if !p.addIdleConnAsync() {
 			return
 		}
 	}
 }
 
+// AddIdleConns dials up to n new connections in the background and adds them
+// to the idle connections as long as the pool is not full.
+func (p *ConnPool) AddIdleConns(n int) {
+	p.connsMu.Lock()
+	defer p.connsMu.Unlock()
+
+	for i := 0; i < n && p.poolSize < p.Size(); i++ {
+		if !p.addIdleConnAsync() {
+			return
+		}
+	}
+}
+
+// addIdleConnAsync reserves a turn for a new idle connection and dials it in the background.
+// It must be called with connsMu held and reports false when no turn is available.
+func (p *ConnPool) addIdleConnAsync() bool {
+	select {
+	case p.queue <- struct{}{}:
+		p.poolSize++
+		p.idleConnsLen++
+
+		go func() {
+			err := p.addIdleConn()
+			if err != nil && err != ErrClosed {
+				p.connsMu.Lock()
+				p.poolSize--
+				p.idleConnsLen--
+				p.connsMu.Unlock()
+			}
+
+			p.freeTurn()
+		}()
+		return true
+	default:
+		return false
+	}
+}
+
 func (p *ConnPool) addIdleConn() error {
 	cn, err := p.dialConn(context.TODO(), true)
 	if err != nil {
@@ -184,14 +266,14 @@ func (p *ConnPool) newConn(ctx context.Context, pooled bool) (*Conn, error) {
 	defer p.connsMu.Unlock()
 
 	if p.cfg.MaxActiveConns > 0 && p.poolSize >= p.cfg.MaxActiveConns {
-		_ = cn.Close()
+		_ = p.closeConn(cn, ErrPoolExhausted)
 		return nil, ErrPoolExhausted
 	}
 
 	p.conns = append(p.conns, cn)
 	if pooled {
 		// If pool is full remove the cn on next Put.
-		if p.poolSize >= p.cfg.PoolSize {
+		if p.poolSize >= p.Size() {
 			cn.pooled = false
 		} else {
 			p.poolSize++
@@ -210,6 +292,8 @@ func (p *ConnPool) dialConn(ctx context.Context, pooled bool) (*Conn, error) {
 		return nil, p.getLastDialError()
 	}
 
+	// a connection dialed while the connections are retired belongs to the old generation
+	generation := atomic.LoadUint32(&p.generation)
 	netConn, err := p.cfg.Dialer(ctx)
 	if err != nil {
 		p.setLastDialError(err)
@@ -219,8 +303,13 @@ func (p *ConnPool) dialConn(ctx context.Context, pooled bool) (*Conn, error) {
 		return nil, err
 	}
 
-	cn := NewConn(netConn)
+	cn := NewConnSize(netConn, p.cfg.ReadBufferSize, p.cfg.WriteBufferSize)
+	cn.rd.SetStringInterner(p.cfg.StringInterner)
 	cn.pooled = pooled
+	cn.generation = generation
+	if p.cfg.OnDial != nil {
+		p.cfg.OnDial(cn)
+	}
 	return cn, nil
 }
 
@@ -264,6 +353,9 @@ func (p *ConnPool) Get(ctx context.Context) (*Conn, error) {
 	if err := p.waitTurn(ctx); err != nil {
 		return nil, err
 	}
+	if p.adaptStop != nil {
+		p.recordInUse()
+	}
 
 	for {
 		p.connsMu.Lock()
@@ -280,11 +372,13 @@ func (p *ConnPool) Get(ctx context.Context) (*Conn, error) {
 		}
 
 		if !p.isHealthyConn(cn) {
-			_ = p.CloseConn(cn)
+			p.removeConnWithLock(cn)
+			_ = p.closeConn(cn, ErrConnStale)
 			continue
 		}
 
 		atomic.AddUint32(&p.stats.Hits, 1)
+		p.acquired(cn)
 		return cn, nil
 	}
 
@@ -296,9 +390,22 @@ func (p *ConnPool) Get(ctx context.Context) (*Conn, error) {
 		return nil, err
 	}
 
+	p.acquired(newcn)
 	return newcn, nil
 }
 
+func (p *ConnPool) acquired(cn *Conn) {
+	if p.cfg.OnAcquire != nil {
+		p.cfg.OnAcquire(cn)
+	}
+}
+
+func (p *ConnPool) released(cn *Conn) {
+	if p.cfg.OnRelease != nil {
+		p.cfg.OnRelease(cn)
+	}
+}
+
 func (p *ConnPool) waitTurn(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -312,6 +419,12 @@ func (p *ConnPool) waitTurn(ctx context.Context) error {
 	default:
 	}
 
+	start := time.Now()
+	defer func() {
+		atomic.AddUint32(&p.stats.WaitCount, 1)
+		atomic.AddInt64(&p.stats.WaitDurationNs, int64(time.Since(start)))
+	}()
+
 	timer := timers.Get().(*time.Timer)
 	timer.Reset(p.cfg.PoolTimeout)
 
@@ -364,14 +477,20 @@ func (p *ConnPool) popIdle() (*Conn, error) {
 }
 
 func (p *ConnPool) Put(ctx context.Context, cn *Conn) {
+	p.released(cn)
+
 	if cn.rd.Buffered() > 0 {
-		internal.Logger.Printf(ctx, "Conn has unread data")
-		p.Remove(ctx, cn, BadConnError{})
+		internal.Log(ctx, p.cfg.Logger, internal.LevelWarn, "redis: conn has unread data", "conn", cn.ID())
+		p.remove(cn, BadConnError{})
 		return
 	}
 
 	if !cn.pooled {
-		p.Remove(ctx, cn, nil)
+		p.remove(cn, nil)
+		return
+	}
+	if p.retired(cn) {
+		p.remove(cn, ErrConnStale)
 		return
 	}
 
@@ -392,19 +511,24 @@ func (p *ConnPool) Put(ctx context.Context, cn *Conn) {
 	p.freeTurn()
 
 	if shouldCloseConn {
-		_ = p.closeConn(cn)
+		_ = p.closeConn(cn, nil)
 	}
 }
 
 func (p *ConnPool) Remove(_ context.Context, cn *Conn, reason error) {
+	p.released(cn)
+	p.remove(cn, reason)
+}
+
+func (p *ConnPool) remove(cn *Conn, reason error) {
 	p.removeConnWithLock(cn)
 	p.freeTurn()
-	_ = p.closeConn(cn)
+	_ = p.closeConn(cn, reason)
 }
 
 func (p *ConnPool) CloseConn(cn *Conn) error {
 	p.removeConnWithLock(cn)
-	return p.closeConn(cn)
+	return p.closeConn(cn, nil)
 }
 
 func (p *ConnPool) removeConnWithLock(cn *Conn) {
@@ -427,7 +551,10 @@ func (p *ConnPool) removeConn(cn *Conn) {
 	atomic.AddUint32(&p.stats.StaleConns, 1)
 }
 
-func (p *ConnPool) closeConn(cn *Conn) error {
+func (p *ConnPool) closeConn(cn *Conn, reason error) error {
+	if p.cfg.OnClose != nil {
+		p.cfg.OnClose(cn, reason)
+	}
 	return cn.Close()
 }
 
@@ -453,6 +580,10 @@ func (p *ConnPool) Stats() *Stats {
 		Misses:   atomic.LoadUint32(&p.stats.Misses),
 		Timeouts: atomic.LoadUint32(&p.stats.Timeouts),
 
+		WaitCount:      atomic.LoadUint32(&p.stats.WaitCount),
+		WaitDurationNs: atomic.LoadInt64(&p.stats.WaitDurationNs),
+
+		PoolSize:   uint32(p.Size()),
 		TotalConns: uint32(p.Len()),
 		IdleConns:  uint32(p.IdleLen()),
 		StaleConns: atomic.LoadUint32(&p.stats.StaleConns),
@@ -463,6 +594,41 @@ func (p *ConnPool) closed() bool {
 	return atomic.LoadUint32(&p._closed) == 1
 }
 
+// RetireConns retires the current connections: the idle ones are closed right
+// away and the ones in use are closed when they are put back, so the pool is
+// drained as the commands complete and only new connections are used.
+func (p *ConnPool) RetireConns() {
+	atomic.AddUint32(&p.generation, 1)
+
+	p.connsMu.Lock()
+	var retired []*Conn
+	idleConns := p.idleConns[:0]
+	for _, cn := range p.idleConns {
+		if p.retired(cn) {
+			retired = append(retired, cn)
+		} else {
+			idleConns = append(idleConns, cn)
+		}
+	}
+	for i := len(idleConns); i < len(p.idleConns); i++ {
+		p.idleConns[i] = nil
+	}
+	p.idleConns = idleConns
+	p.idleConnsLen -= len(retired)
+	for _, cn := range retired {
+		p.removeConn(cn)
+	}
+	p.connsMu.Unlock()
+
+	for _, cn := range retired {
+		_ = p.closeConn(cn, ErrConnStale)
+	}
+}
+
+func (p *ConnPool) retired(cn *Conn) bool {
+	return cn.generation != atomic.LoadUint32(&p.generation)
+}
+
 func (p *ConnPool) Filter(fn func(*Conn) bool) error {
 	p.connsMu.Lock()
 	defer p.connsMu.Unlock()
@@ -470,7 +636,7 @@ func (p *ConnPool) Filter(fn func(*Conn) bool) error {
 	var firstErr error
 	for _, cn := range p.conns {
 		if fn(cn) {
-			if err := p.closeConn(cn); err != nil && firstErr == nil {
+			if err := p.closeConn(cn, nil); err != nil && firstErr == nil {
 				firstErr = err
 			}
 		}
@@ -482,16 +648,20 @@ func (p *ConnPool) Close() error {
 	if !atomic.CompareAndSwapUint32(&p._closed, 0, 1) {
 		return ErrClosed
 	}
+	if p.adaptStop != nil {
+		close(p.adaptStop)
+	}
 
 	var firstErr error
 	p.connsMu.Lock()
 	for _, cn := range p.conns {
-		if err := p.closeConn(cn); err != nil && firstErr == nil {
+		if err := p.closeConn(cn, ErrClosed); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
 	p.conns = nil
 	p.poolSize = 0
+	atomic.StoreInt32(&p.size, 0)
 	p.idleConns = nil
 	p.idleConnsLen = 0
 	p.connsMu.Unlock()
@@ -502,6 +672,10 @@ func (p *ConnPool) Close() error {
 func (p *ConnPool) isHealthyConn(cn *Conn) bool {
 	now := time.Now()
 
+	if p.retired(cn) {
+		return false
+	}
+
 	if p.cfg.ConnMaxLifetime > 0 && now.Sub(cn.createdAt) >= p.cfg.ConnMaxLifetime {
 		return false
 	}
@@ -509,8 +683,10 @@ func (p *ConnPool) isHealthyConn(cn *Conn) bool {
 		return false
 	}
 
-	if connCheck(cn.netConn) != nil {
-		return false
+	if err := connCheck(cn.netConn); err != nil {
+		if err != errUnexpectedRead || !p.cfg.PushNotifications {
+			return false
+		}
 	}
 
 	cn.SetUsedAt(now)
@@ -0,0 +1,148 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+func (opt *Options) adaptive() bool {
+	return opt.MaxPoolSize > opt.MinPoolSize && opt.MaxPoolSize > 0
+}
+
+// maxPoolSize returns the number of turns of the queue of the pool.
+func (opt *Options) maxPoolSize() int {
+	if opt.adaptive() {
+		return opt.MaxPoolSize
+	}
+	return opt.PoolSize
+}
+
+// Size returns the size of the pool, the number of connections used at once.
+func (p *ConnPool) Size() int {
+	return int(atomic.LoadInt32(&p.size))
+}
+
+// initAdaptive holds the turns beyond PoolSize, bounded by MinPoolSize and
+// MaxPoolSize, and starts adapting the size of the pool.
+func (p *ConnPool) initAdaptive() {
+	if p.cfg.MinPoolSize < 1 {
+		p.cfg.MinPoolSize = 1
+	}
+	if p.cfg.AdaptInterval <= 0 {
+		p.cfg.AdaptInterval = time.Second
+	}
+
+	size := p.cfg.PoolSize
+	if size < p.cfg.MinPoolSize {
+		size = p.cfg.MinPoolSize
+	}
+	if size > p.cfg.MaxPoolSize {
+		size = p.cfg.MaxPoolSize
+	}
+	p.size = int32(size)
+	p.held = int32(p.cfg.MaxPoolSize - size)
+	for i := int32(0); i < p.held; i++ {
+		p.queue <- struct{}{}
+	}
+
+	p.adaptStop = make(chan struct{})
+	go p.adaptLoop()
+}
+
+func (p *ConnPool) adaptLoop() {
+	ticker := time.NewTicker(p.cfg.AdaptInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.adapt()
+		case <-p.adaptStop:
+			return
+		}
+	}
+}
+
+// recordInUse records the number of turns in use after a turn is taken.
+func (p *ConnPool) recordInUse() {
+	inUse := int32(len(p.queue)) - atomic.LoadInt32(&p.held)
+	for {
+		peak := atomic.LoadInt32(&p.peakInUse)
+		if inUse <= peak || atomic.CompareAndSwapInt32(&p.peakInUse, peak, inUse) {
+			return
+		}
+	}
+}
+
+// adapt grows the pool by a quarter when Get waited for a turn since the
+// last adapt, and shrinks it by a quarter when at most half of its turns were
+// used at once, closing the idle connections in excess.
+func (p *ConnPool) adapt() {
+	waits := atomic.LoadUint32(&p.stats.WaitCount)
+	waited := waits != p.lastWaits
+	p.lastWaits = waits
+	peak := int(atomic.SwapInt32(&p.peakInUse, 0))
+
+	size := p.Size()
+	step := size / 4
+	if step < 1 {
+		step = 1
+	}
+
+	switch {
+	case waited && size < p.cfg.MaxPoolSize:
+		if n := p.cfg.MaxPoolSize - size; step > n {
+			step = n
+		}
+		p.grow(step)
+	case !waited && peak <= size/2 && size > p.cfg.MinPoolSize:
+		if n := size - p.cfg.MinPoolSize; step > n {
+			step = n
+		}
+		p.shrink(step)
+	}
+}
+
+// holdTurn holds a free turn, if any, reducing the size of the pool.
+func (p *ConnPool) holdTurn() bool {
+	select {
+	case p.queue <- struct{}{}:
+		atomic.AddInt32(&p.held, 1)
+		atomic.AddInt32(&p.size, -1)
+		return true
+	default:
+		return false
+	}
+}
+
+// grow frees n held turns.
+func (p *ConnPool) grow(n int) {
+	atomic.AddInt32(&p.size, int32(n))
+	for i := 0; i < n; i++ {
+		atomic.AddInt32(&p.held, -1)
+		<-p.queue
+	}
+}
+
+// shrink holds up to n free turns and closes the idle connections in excess.
+func (p *ConnPool) shrink(n int) {
+	for i := 0; i < n && p.holdTurn(); i++ {
+	}
+
+	p.connsMu.Lock()
+	var excess []*Conn
+	for p.poolSize > p.Size() && len(p.idleConns) > 0 {
+		// the least recently used connection
+		cn := p.idleConns[0]
+		copy(p.idleConns, p.idleConns[1:])
+		p.idleConns = p.idleConns[:len(p.idleConns)-1]
+		p.idleConnsLen--
+		p.removeConn(cn)
+		excess = append(excess, cn)
+	}
+	p.connsMu.Unlock()
+
+	for _, cn := range excess {
+		_ = p.closeConn(cn, nil)
+	}
+}
@@ -111,10 +111,62 @@ var _ = Describe("ConnPool", func() {
 			Fail("Get is not unblocked")
 		}
 
+		stats := connPool.Stats()
+		Expect(stats.WaitCount).To(Equal(uint32(1)))
+		Expect(stats.WaitDurationNs).To(BeNumerically(">=", int64(time.Millisecond)))
+
 		for _, cn := range cns {
 			connPool.Put(ctx, cn)
 		}
 	})
+
+	It("should drain retired conns", func() {
+		idle, err := connPool.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		used, err := connPool.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		connPool.Put(ctx, idle)
+		Expect(connPool.Len()).To(Equal(2))
+		Expect(connPool.IdleLen()).To(Equal(1))
+
+		connPool.RetireConns()
+		Expect(connPool.Len()).To(Equal(1))
+		Expect(connPool.IdleLen()).To(Equal(0))
+
+		connPool.Put(ctx, used)
+		Expect(connPool.Len()).To(Equal(0))
+
+		cn, err := connPool.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cn).NotTo(BeIdenticalTo(idle))
+		Expect(cn).NotTo(BeIdenticalTo(used))
+		connPool.Put(ctx, cn)
+		Expect(connPool.IdleLen()).To(Equal(1))
+	})
+
+	It("should size the buffers of conns", func() {
+		cn, err := connPool.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		rd, wr := cn.BufferSizes()
+		Expect(rd).To(Equal(4096))
+		Expect(wr).To(Equal(4096))
+		connPool.Put(ctx, cn)
+
+		p := pool.NewConnPool(&pool.Options{
+			Dialer:          dummyDialer,
+			PoolSize:        1,
+			PoolTimeout:     time.Hour,
+			ReadBufferSize:  64 * 1024,
+			WriteBufferSize: 512,
+		})
+		defer p.Close()
+		cn, err = p.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		rd, wr = cn.BufferSizes()
+		Expect(rd).To(Equal(64 * 1024))
+		Expect(wr).To(Equal(512))
+		p.Put(ctx, cn)
+	})
 })
 
 var _ = Describe("MinIdleConns", func() {
@@ -354,3 +406,91 @@ var _ = Describe("race", func() {
 		Expect(stats.TotalConns).To(Equal(uint32(opt.PoolSize)))
 	})
 })
+
+var _ = Describe("adaptive pool", func() {
+	ctx := context.Background()
+	var p *pool.ConnPool
+
+	BeforeEach(func() {
+		p = pool.NewConnPool(&pool.Options{
+			Dialer:        dummyDialer,
+			PoolSize:      4,
+			PoolTimeout:   time.Hour,
+			MinPoolSize:   2,
+			MaxPoolSize:   8,
+			AdaptInterval: time.Hour, // adapted by the test
+		})
+	})
+
+	AfterEach(func() {
+		Expect(p.Close()).NotTo(HaveOccurred())
+	})
+
+	It("grows when Get waits for a turn", func() {
+		Expect(p.Size()).To(Equal(4))
+
+		var cns []*pool.Conn
+		for i := 0; i < 4; i++ {
+			cn, err := p.Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			cns = append(cns, cn)
+		}
+
+		got := make(chan *pool.Conn)
+		go func() {
+			defer GinkgoRecover()
+			cn, err := p.Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			got <- cn
+		}()
+		Consistently(got, 50*time.Millisecond).ShouldNot(Receive())
+
+		p.Put(ctx, cns[0])
+		cns[0] = <-got
+		Expect(p.Stats().WaitCount).To(Equal(uint32(1)))
+
+		p.Adapt()
+		Expect(p.Size()).To(Equal(5))
+		Expect(p.Stats().PoolSize).To(Equal(uint32(5)))
+
+		cn, err := p.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		cns = append(cns, cn)
+		Expect(p.Len()).To(Equal(5))
+		for _, cn := range cns {
+			p.Put(ctx, cn)
+		}
+
+		// it does not grow without waits nor beyond MaxPoolSize
+		p.Adapt()
+		Expect(p.Size()).To(Equal(5))
+	})
+
+	It("shrinks when its connections are underused", func() {
+		var cns []*pool.Conn
+		for i := 0; i < 4; i++ {
+			cn, err := p.Get(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			cns = append(cns, cn)
+		}
+		for _, cn := range cns {
+			p.Put(ctx, cn)
+		}
+		p.Adapt() // all the turns were used
+		Expect(p.Size()).To(Equal(4))
+
+		cn, err := p.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		p.Put(ctx, cn)
+
+		p.Adapt()
+		Expect(p.Size()).To(Equal(3))
+		Expect(p.Len()).To(Equal(3))
+		Expect(p.IdleLen()).To(Equal(3))
+
+		p.Adapt()
+		p.Adapt()
+		Expect(p.Size()).To(Equal(2))
+		Expect(p.Len()).To(Equal(2))
+	})
+})
@@ -2,6 +2,7 @@ package proto
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -40,6 +41,8 @@ const (
 
 const Nil = RedisError("redis: nil") // nolint:errname
 
+var crlf = []byte("\r\n")
+
 type RedisError string
 
 func (e RedisError) Error() string { return string(e) }
@@ -53,25 +56,98 @@ func ParseErrorReply(line []byte) error {
 //------------------------------------------------------------------------------
 
 type Reader struct {
-	rd *bufio.Reader
+	rd  *bufio.Reader
+	src countingReader
+
+	// intern returns the strings of the string and status replies, if set.
+	intern func(b []byte) string
 }
 
 func NewReader(rd io.Reader) *Reader {
-	return &Reader{
-		rd: bufio.NewReader(rd),
+	r := &Reader{
+		src: countingReader{rd: rd},
+	}
+	r.rd = bufio.NewReader(&r.src)
+	return r
+}
+
+// NewReaderSize returns a Reader with a buffer of at least size bytes, or of
+// the default size of bufio when size is not positive.
+func NewReaderSize(rd io.Reader, size int) *Reader {
+	if size <= 0 {
+		return NewReader(rd)
+	}
+	r := &Reader{
+		src: countingReader{rd: rd},
+	}
+	r.rd = bufio.NewReaderSize(&r.src, size)
+	return r
+}
+
+// countingReader counts the bytes read from rd, and copies them to capture
+// while capturing.
+type countingReader struct {
+	rd io.Reader
+	n  int64
+
+	capturing bool
+	capture   []byte
+}
+
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.rd.Read(b)
+	r.n += int64(n)
+	if r.capturing {
+		r.capture = append(r.capture, b[:n]...)
 	}
+	return n, err
+}
+
+// Consumed returns the number of bytes of the replies read so far, not
+// counting the buffered bytes.
+func (r *Reader) Consumed() int64 {
+	return r.src.n - int64(r.rd.Buffered())
+}
+
+// Capture returns a copy of the bytes of the replies read by fn, e.g. to read
+// them again later with a Reader of the bytes.
+func (r *Reader) Capture(fn func() error) ([]byte, error) {
+	buffered, _ := r.rd.Peek(r.rd.Buffered())
+	start := r.Consumed()
+	r.src.capture = append([]byte(nil), buffered...)
+	r.src.capturing = true
+
+	err := fn()
+
+	b := r.src.capture[:r.Consumed()-start]
+	r.src.capture = nil
+	r.src.capturing = false
+	return b, err
+}
+
+// SetStringInterner sets the function returning the strings of the string and
+// status replies, e.g. previously returned strings, instead of new strings.
+// The function must not retain b.
+func (r *Reader) SetStringInterner(fn func(b []byte) string) {
+	r.intern = fn
 }
 
 func (r *Reader) Buffered() int {
 	return r.rd.Buffered()
 }
 
+// Size returns the size of the buffer of the reader.
+func (r *Reader) Size() int {
+	return r.rd.Size()
+}
+
 func (r *Reader) Peek(n int) ([]byte, error) {
 	return r.rd.Peek(n)
 }
 
 func (r *Reader) Reset(rd io.Reader) {
-	r.rd.Reset(rd)
+	r.src.rd = rd
+	r.rd.Reset(&r.src)
 }
 
 // PeekReplyType returns the data type of the next response without advancing the Reader,
@@ -90,6 +166,69 @@ func (r *Reader) PeekReplyType() (byte, error) {
 	return b[0], nil
 }
 
+// PeekPushNotificationName returns the name of the push notification at the head
+// of the buffer without advancing the Reader, e.g. "invalidate" or "message".
+func (r *Reader) PeekPushNotificationName() (string, error) {
+	n := 1
+	for {
+		buf, err := r.rd.Peek(n)
+		if err != nil {
+			return "", err
+		}
+
+		name, ok, err := parsePushNotificationName(buf)
+		if err != nil || ok {
+			return name, err
+		}
+
+		// Wait for at least one more byte unless more are already buffered.
+		if n = r.rd.Buffered(); n <= len(buf) {
+			n = len(buf) + 1
+		}
+	}
+}
+
+// parsePushNotificationName parses the name from the beginning of a push frame
+// and reports false if buf does not hold the whole name yet.
+func parsePushNotificationName(buf []byte) (string, bool, error) {
+	if buf[0] != RespPush {
+		return "", false, fmt.Errorf("redis: can't parse push notification: %.100q", buf)
+	}
+
+	i := bytes.Index(buf, crlf)
+	if i == -1 {
+		return "", false, nil
+	}
+	buf = buf[i+2:]
+	if len(buf) == 0 {
+		return "", false, nil
+	}
+
+	switch buf[0] {
+	case RespStatus:
+		i := bytes.Index(buf, crlf)
+		if i == -1 {
+			return "", false, nil
+		}
+		return string(buf[1:i]), true, nil
+	case RespString:
+		i := bytes.Index(buf, crlf)
+		if i == -1 {
+			return "", false, nil
+		}
+		n, err := replyLen(buf[:i])
+		if err != nil {
+			return "", false, err
+		}
+		buf = buf[i+2:]
+		if len(buf) < n {
+			return "", false, nil
+		}
+		return string(buf[:n]), true, nil
+	}
+	return "", false, fmt.Errorf("redis: can't parse push notification name: %.100q", buf)
+}
+
 // ReadLine Return a valid reply, it will check the protocol or redis error,
 // and discard the attribute type.
 func (r *Reader) ReadLine() ([]byte, error) {
@@ -159,7 +298,7 @@ func (r *Reader) ReadReply() (interface{}, error) {
 
 	switch line[0] {
 	case RespStatus:
-		return string(line[1:]), nil
+		return r.readStatus(line), nil
 	case RespInt:
 		return util.ParseInt(line[1:], 10, 64)
 	case RespFloat:
@@ -213,12 +352,29 @@ func (r *Reader) readBigInt(line []byte) (*big.Int, error) {
 	return nil, fmt.Errorf("redis: can't parse bigInt reply: %q", line)
 }
 
+func (r *Reader) readStatus(line []byte) string {
+	if r.intern != nil {
+		return r.intern(line[1:])
+	}
+	return string(line[1:])
+}
+
 func (r *Reader) readStringReply(line []byte) (string, error) {
 	n, err := replyLen(line)
 	if err != nil {
 		return "", err
 	}
 
+	if r.intern != nil && n+2 <= r.rd.Size() {
+		b, err := r.rd.Peek(n + 2)
+		if err != nil {
+			return "", err
+		}
+		s := r.intern(b[:n])
+		_, err = r.rd.Discard(n + 2)
+		return s, err
+	}
+
 	b := make([]byte, n+2)
 	_, err = io.ReadFull(r.rd, b)
 	if err != nil {
@@ -375,10 +531,19 @@ func (r *Reader) ReadString() (string, error) {
 	}
 
 	switch line[0] {
-	case RespStatus, RespInt, RespFloat:
+	case RespStatus:
+		return r.readStatus(line), nil
+	case RespInt, RespFloat:
 		return string(line[1:]), nil
 	case RespString:
 		return r.readStringReply(line)
+	}
+	return r.readStringLine(line)
+}
+
+// readStringLine returns the string of the other replies of ReadString.
+func (r *Reader) readStringLine(line []byte) (string, error) {
+	switch line[0] {
 	case RespBool:
 		b, err := r.readBool(line)
 		return strconv.FormatBool(b), err
@@ -394,6 +559,118 @@ func (r *Reader) ReadString() (string, error) {
 	return "", fmt.Errorf("redis: can't parse reply=%.100q reading string", line)
 }
 
+// ReadStringFunc reads a string reply as ReadString, but calls fn with the
+// string instead of returning it: the string is in the buffer of the reader
+// when it fits, and must not be used after fn returns.
+func (r *Reader) ReadStringFunc(fn func(b []byte)) error {
+	line, err := r.ReadLine()
+	if err != nil {
+		return err
+	}
+
+	switch line[0] {
+	case RespStatus, RespInt, RespFloat:
+		fn(line[1:])
+		return nil
+	case RespString, RespVerbatim:
+		n, err := replyLen(line)
+		if err != nil {
+			return err
+		}
+		verbatim := line[0] == RespVerbatim
+
+		var b []byte
+		if n+2 <= r.rd.Size() {
+			if b, err = r.rd.Peek(n + 2); err != nil {
+				return err
+			}
+			defer r.rd.Discard(n + 2) //nolint:errcheck // the bytes are buffered
+		} else {
+			b = make([]byte, n+2)
+			if _, err = io.ReadFull(r.rd, b); err != nil {
+				return err
+			}
+		}
+		b = b[:n]
+
+		if verbatim {
+			if len(b) < 4 || b[3] != ':' {
+				return fmt.Errorf("redis: can't parse verbatim string reply: %q", b)
+			}
+			b = b[4:]
+		}
+		fn(b)
+		return nil
+	}
+
+	s, err := r.readStringLine(line)
+	if err != nil {
+		return err
+	}
+	fn([]byte(s))
+	return nil
+}
+
+// ReadStringChunks reads a string reply as ReadStringFunc, but calls fn with
+// the successive chunks of the string in the buffer of the reader, so that a
+// large string is never entirely in memory. The chunks must not be used after
+// fn returns.
+func (r *Reader) ReadStringChunks(fn func(b []byte)) error {
+	line, err := r.ReadLine()
+	if err != nil {
+		return err
+	}
+
+	switch line[0] {
+	case RespStatus, RespInt, RespFloat:
+		fn(line[1:])
+		return nil
+	case RespString, RespVerbatim:
+		n, err := replyLen(line)
+		if err != nil {
+			return err
+		}
+
+		if line[0] == RespVerbatim {
+			if n < 4 {
+				return fmt.Errorf("redis: can't parse verbatim string reply: %q", line)
+			}
+			b, err := r.rd.Peek(4)
+			if err != nil {
+				return err
+			}
+			if b[3] != ':' {
+				return fmt.Errorf("redis: can't parse verbatim string reply: %q", b)
+			}
+			_, _ = r.rd.Discard(4)
+			n -= 4
+		}
+
+		for n > 0 {
+			size := n
+			if size > r.rd.Size() {
+				size = r.rd.Size()
+			}
+			b, err := r.rd.Peek(size)
+			if err != nil {
+				return err
+			}
+			fn(b)
+			_, _ = r.rd.Discard(size)
+			n -= size
+		}
+		_, err = r.rd.Discard(2)
+		return err
+	}
+
+	s, err := r.readStringLine(line)
+	if err != nil {
+		return err
+	}
+	fn([]byte(s))
+	return nil
+}
+
 func (r *Reader) ReadBool() (bool, error) {
 	s, err := r.ReadString()
 	if err != nil {
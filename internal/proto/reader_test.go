@@ -3,6 +3,7 @@ package proto_test
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/redis/go-redis/v9/internal/proto"
@@ -98,3 +99,172 @@ func benchmarkParseReply(b *testing.B, reply string, wanterr bool) {
 		}
 	}
 }
+
+func TestReader_PeekPushNotificationName(t *testing.T) {
+	for _, tc := range []struct {
+		reply string
+		name  string
+	}{
+		{">2\r\n$10\r\ninvalidate\r\n*1\r\n$3\r\nkey\r\n", "invalidate"},
+		{">4\r\n+MOVING\r\n:1\r\n:15\r\n$14\r\n127.0.0.1:6379\r\n", "MOVING"},
+	} {
+		r := proto.NewReader(bytes.NewReader([]byte(tc.reply)))
+		name, err := r.PeekPushNotificationName()
+		if err != nil {
+			t.Fatalf("%q: %v", tc.reply, err)
+		}
+		if name != tc.name {
+			t.Fatalf("%q: got %q, wanted %q", tc.reply, name, tc.name)
+		}
+
+		// The frame must still be readable in full.
+		reply, err := r.ReadReply()
+		if err != nil {
+			t.Fatalf("%q: %v", tc.reply, err)
+		}
+		if got := reply.([]interface{})[0]; got != tc.name {
+			t.Fatalf("%q: got %q, wanted %q", tc.reply, got, tc.name)
+		}
+	}
+
+	r := proto.NewReader(bytes.NewReader([]byte("*1\r\n+OK\r\n")))
+	if _, err := r.PeekPushNotificationName(); err == nil {
+		t.Fatal("expected an error for a non-push reply")
+	}
+}
+
+func TestReader_ReadStringFunc(t *testing.T) {
+	large := strings.Repeat("a", 10000) // larger than the read buffer
+	for _, tc := range []struct {
+		reply string
+		want  string
+	}{
+		{"$5\r\nhello\r\n", "hello"},
+		{"$10000\r\n" + large + "\r\n", large},
+		{"=9\r\ntxt:hello\r\n", "hello"},
+		{"+OK\r\n", "OK"},
+		{":42\r\n", "42"},
+		{"#t\r\n", "true"},
+	} {
+		// the reply is followed by another one, left unread
+		r := proto.NewReader(strings.NewReader(tc.reply + "+next\r\n"))
+		var got string
+		if err := r.ReadStringFunc(func(b []byte) { got = string(b) }); err != nil {
+			t.Fatalf("%.20q: %v", tc.reply, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%.20q: got %.20q, wanted %.20q", tc.reply, got, tc.want)
+		}
+		if next, err := r.ReadString(); err != nil || next != "next" {
+			t.Fatalf("%.20q: got %q, %v after the reply", tc.reply, next, err)
+		}
+	}
+
+	for _, reply := range []string{"$-1\r\n", "_\r\n", "-ERR failed\r\n", "*1\r\n+OK\r\n"} {
+		r := proto.NewReader(strings.NewReader(reply))
+		if err := r.ReadStringFunc(func(b []byte) {
+			t.Fatalf("%q: fn called with %q", reply, b)
+		}); err == nil {
+			t.Fatalf("%q: got nil error", reply)
+		}
+	}
+}
+
+func TestReader_SetStringInterner(t *testing.T) {
+	large := strings.Repeat("a", 10000) // larger than the read buffer
+	var interned []string
+	r := proto.NewReader(strings.NewReader("$5\r\nfield\r\n+OK\r\n*2\r\n$5\r\nfield\r\n:1\r\n$10000\r\n" + large + "\r\n"))
+	r.SetStringInterner(func(b []byte) string {
+		interned = append(interned, string(b))
+		return "interned"
+	})
+
+	if s, err := r.ReadString(); err != nil || s != "interned" {
+		t.Fatalf("got %q, %v", s, err)
+	}
+	if s, err := r.ReadString(); err != nil || s != "interned" {
+		t.Fatalf("got %q, %v", s, err)
+	}
+	v, err := r.ReadReply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals := v.([]interface{}); vals[0] != "interned" || vals[1] != int64(1) {
+		t.Fatalf("got %q", vals)
+	}
+	// the strings larger than the buffer are not interned
+	if s, err := r.ReadString(); err != nil || s != large {
+		t.Fatalf("got %.20q, %v", s, err)
+	}
+	if len(interned) != 3 || interned[0] != "field" || interned[1] != "OK" || interned[2] != "field" {
+		t.Fatalf("interned %q", interned)
+	}
+}
+
+func TestReader_ReadStringChunks(t *testing.T) {
+	large := strings.Repeat("abcdefgh", 1000) // larger than the read buffer
+	for _, tc := range []struct {
+		reply string
+		want  string
+	}{
+		{"$5\r\nhello\r\n", "hello"},
+		{"$0\r\n\r\n", ""},
+		{"$8000\r\n" + large + "\r\n", large},
+		{"=8004\r\ntxt:" + large + "\r\n", large},
+		{"+OK\r\n", "OK"},
+		{"#f\r\n", "false"},
+	} {
+		r := proto.NewReaderSize(strings.NewReader(tc.reply+"+next\r\n"), 64)
+		var got []byte
+		var chunks int
+		if err := r.ReadStringChunks(func(b []byte) {
+			got = append(got, b...)
+			chunks++
+		}); err != nil {
+			t.Fatalf("%.20q: %v", tc.reply, err)
+		}
+		if string(got) != tc.want {
+			t.Fatalf("%.20q: got %.20q, wanted %.20q", tc.reply, got, tc.want)
+		}
+		if len(tc.want) > 64 && chunks < len(tc.want)/64 {
+			t.Fatalf("%.20q: got %d chunks", tc.reply, chunks)
+		}
+		if next, err := r.ReadString(); err != nil || next != "next" {
+			t.Fatalf("%.20q: got %q, %v after the reply", tc.reply, next, err)
+		}
+	}
+
+	for _, reply := range []string{"$-1\r\n", "-ERR failed\r\n", "=3\r\ntxt\r\n"} {
+		r := proto.NewReader(strings.NewReader(reply))
+		if err := r.ReadStringChunks(func(b []byte) {
+			t.Fatalf("%q: fn called with %q", reply, b)
+		}); err == nil {
+			t.Fatalf("%q: got nil error", reply)
+		}
+	}
+}
+
+func TestReader_Capture(t *testing.T) {
+	replies := "$5\r\nhello\r\n%1\r\n+a\r\n:1\r\n+OK\r\n"
+	// a small buffer, for the replies to be read from the source while captured
+	r := proto.NewReaderSize(strings.NewReader(replies), 16)
+	if _, err := r.ReadString(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := r.Capture(func() error {
+		_, err := r.ReadReply()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "%1\r\n+a\r\n:1\r\n"; got != want {
+		t.Fatalf("got %q, wanted %q", got, want)
+	}
+
+	status, err := r.ReadString()
+	if err != nil || status != "OK" {
+		t.Fatalf("got %q, %v after the capture, wanted OK", status, err)
+	}
+}
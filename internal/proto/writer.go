@@ -2,6 +2,7 @@ package proto
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -20,6 +21,7 @@ type writer interface {
 
 type Writer struct {
 	writer
+	n int64
 
 	lenBuf []byte
 	numBuf []byte
@@ -34,6 +36,31 @@ func NewWriter(wr writer) *Writer {
 	}
 }
 
+func (w *Writer) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *Writer) WriteByte(c byte) error {
+	err := w.writer.WriteByte(c)
+	if err == nil {
+		w.n++
+	}
+	return err
+}
+
+func (w *Writer) WriteString(s string) (int, error) {
+	n, err := w.writer.WriteString(s)
+	w.n += int64(n)
+	return n, err
+}
+
+// Written returns the number of bytes written so far.
+func (w *Writer) Written() int64 {
+	return w.n
+}
+
 func (w *Writer) WriteArgs(args []interface{}) error {
 	if err := w.WriteByte(RespArray); err != nil {
 		return err
@@ -140,6 +167,8 @@ func (w *Writer) WriteArg(v interface{}) error {
 		return w.bytes(b)
 	case net.IP:
 		return w.bytes(v)
+	case *ReaderArg:
+		return w.reader(v)
 	default:
 		return fmt.Errorf(
 			"redis: can't marshal %T (implement encoding.BinaryMarshaler)", v)
@@ -162,6 +191,40 @@ func (w *Writer) bytes(b []byte) error {
 	return w.crlf()
 }
 
+func (w *Writer) reader(arg *ReaderArg) error {
+	if err := arg.rewind(); err != nil {
+		return err
+	}
+
+	if err := w.WriteByte(RespString); err != nil {
+		return err
+	}
+
+	if err := w.writeLen(int(arg.Size)); err != nil {
+		return err
+	}
+
+	// the buffered writer reads directly into its buffer
+	src := io.LimitReader(arg.R, arg.Size)
+	var n int64
+	var err error
+	if rf, ok := w.writer.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+		w.n += n
+	} else {
+		n, err = io.Copy(w, src)
+	}
+	if err != nil {
+		return err
+	}
+	if n < arg.Size {
+		// not io.EOF, which would retry the command
+		return fmt.Errorf("redis: reader ended after %d of %d bytes", n, arg.Size)
+	}
+
+	return w.crlf()
+}
+
 func (w *Writer) string(s string) error {
 	return w.bytes(util.StringToBytes(s))
 }
@@ -187,3 +250,40 @@ func (w *Writer) crlf() error {
 	}
 	return w.WriteByte('\n')
 }
+
+// ReaderArg is an argument of Size bytes copied from R when the command is
+// written, instead of being in memory.
+type ReaderArg struct {
+	R    io.Reader
+	Size int64
+
+	written  bool
+	seekable bool
+	offset   int64
+}
+
+// rewind seeks R back to its offset of the first write when the command is
+// written again, e.g. retried, or fails when R is not an io.Seeker.
+func (arg *ReaderArg) rewind() error {
+	seeker, ok := arg.R.(io.Seeker)
+	if !arg.written {
+		arg.written = true
+		if ok {
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				arg.seekable = true
+				arg.offset = offset
+			}
+		}
+		return nil
+	}
+
+	if !arg.seekable {
+		return errors.New("redis: can't write the reader argument again as it is not an io.Seeker")
+	}
+	_, err := seeker.Seek(arg.offset, io.SeekStart)
+	return err
+}
+
+func (arg *ReaderArg) String() string {
+	return fmt.Sprintf("<%d bytes>", arg.Size)
+}
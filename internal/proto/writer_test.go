@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding"
 	"fmt"
+	"io"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +76,30 @@ var _ = Describe("WriteBuffer", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(buf.String()).To(Equal(fmt.Sprintf("*1\r\n$16\r\n%s\r\n", bytes.NewBuffer(ip))))
 	})
+
+	It("should copy reader args", func() {
+		rd := strings.NewReader("xxhello world")
+		_, _ = rd.Seek(2, io.SeekStart)
+		arg := &proto.ReaderArg{R: rd, Size: 5}
+		Expect(wr.WriteArgs([]interface{}{"set", arg})).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("*2\r\n$3\r\nset\r\n$5\r\nhello\r\n"))
+
+		// the reader is seeked back to write the argument again
+		buf.Reset()
+		Expect(wr.WriteArg(arg)).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal("$5\r\nhello\r\n"))
+	})
+
+	It("should not write reader args again without seeking", func() {
+		arg := &proto.ReaderArg{R: io.LimitReader(strings.NewReader("hello"), 5), Size: 5}
+		Expect(wr.WriteArg(arg)).NotTo(HaveOccurred())
+		Expect(wr.WriteArg(arg)).To(MatchError("redis: can't write the reader argument again as it is not an io.Seeker"))
+	})
+
+	It("should fail with short reader args", func() {
+		arg := &proto.ReaderArg{R: strings.NewReader("hello"), Size: 10}
+		Expect(wr.WriteArg(arg)).To(MatchError("redis: reader ended after 5 of 10 bytes"))
+	})
 })
 
 type discard struct{}
@@ -0,0 +1,23 @@
+package util
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		match        bool
+	}{
+		{"user:*", "user:1", true},
+		{"user:*", "session:1", false},
+		{"user:?", "user:12", false},
+		{"user:[0-9]", "user:5", true},
+		{"user:[^0-9]", "user:5", false},
+		{"*:1", "user:1", true},
+		{`a\*`, "a*", true},
+	}
+	for _, test := range tests {
+		if got := GlobMatch(test.pattern, test.key); got != test.match {
+			t.Errorf("GlobMatch(%q, %q) = %v", test.pattern, test.key, got)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package redis
 import (
 	"context"
 	"fmt"
+	"math"
+	"net"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -352,3 +355,204 @@ var _ = Describe("withConn", func() {
 		Expect(client.connPool.Len()).To(Equal(1))
 	})
 })
+
+func TestParseMaintNotification(t *testing.T) {
+	tests := []struct {
+		typ     MaintNotificationType
+		payload []interface{}
+		want    *MaintNotification
+	}{{
+		typ:     MaintMoving,
+		payload: []interface{}{int64(1), int64(15), "10.0.0.2:6379"},
+		want:    &MaintNotification{Type: MaintMoving, SeqID: 1, Time: 15 * time.Second, Endpoint: "10.0.0.2:6379"},
+	}, {
+		typ:     MaintMoving,
+		payload: []interface{}{int64(2), int64(15), "null"},
+		want:    &MaintNotification{Type: MaintMoving, SeqID: 2, Time: 15 * time.Second},
+	}, {
+		typ:     MaintMigrating,
+		payload: []interface{}{int64(3), int64(5), "1,2"},
+		want:    &MaintNotification{Type: MaintMigrating, SeqID: 3, Time: 5 * time.Second, ShardIDs: []string{"1", "2"}},
+	}, {
+		typ:     MaintFailedOver,
+		payload: []interface{}{int64(4), []interface{}{"3"}},
+		want:    &MaintNotification{Type: MaintFailedOver, SeqID: 4, ShardIDs: []string{"3"}},
+	}, {
+		typ:     MaintMoving,
+		payload: []interface{}{"bad"},
+	}}
+
+	for _, test := range tests {
+		got, ok := parseMaintNotification(test.typ, test.payload)
+		if test.want == nil {
+			if ok {
+				t.Errorf("parseMaintNotification(%s, %v) = %v, want failure", test.typ, test.payload, got)
+			}
+			continue
+		}
+		if !ok || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseMaintNotification(%s, %v) = %v, want %v", test.typ, test.payload, got, test.want)
+		}
+	}
+}
+
+func TestPushProcessorReadReply(t *testing.T) {
+	var got []interface{}
+	pushes := newPushProcessor()
+	pushes.register("MOVING", func(ctx context.Context, payload []interface{}) {
+		got = payload
+	})
+
+	rd := proto.NewReader(strings.NewReader(
+		">4\r\n$6\r\nMOVING\r\n:1\r\n:15\r\n$13\r\n10.0.0.2:6379\r\n" +
+			">2\r\n$7\r\nUNKNOWN\r\n:1\r\n" +
+			"+OK\r\n"))
+
+	cmd := NewStatusCmd(context.Background(), "ping")
+	if err := pushes.readReply(context.Background(), rd, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.Val() != "OK" {
+		t.Errorf("got %q, want OK", cmd.Val())
+	}
+	if want := []interface{}{int64(1), int64(15), "10.0.0.2:6379"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPushProcessorOnPush(t *testing.T) {
+	var handled int
+	var msgs []PushMessage
+	pushes := newPushProcessor()
+	pushes.register("MOVING", func(ctx context.Context, payload []interface{}) {
+		handled++
+	})
+	pushes.onPush = func(ctx context.Context, msg PushMessage) {
+		msgs = append(msgs, msg)
+	}
+
+	rd := proto.NewReader(strings.NewReader(
+		">4\r\n$6\r\nMOVING\r\n:1\r\n:15\r\n$13\r\n10.0.0.2:6379\r\n" +
+			">2\r\n$6\r\ncustom\r\n$5\r\nhello\r\n" +
+			"+OK\r\n"))
+
+	cmd := NewStatusCmd(context.Background(), "ping")
+	if err := pushes.readReply(context.Background(), rd, cmd); err != nil {
+		t.Fatal(err)
+	}
+	if handled != 1 {
+		t.Errorf("MOVING handled %d times, want 1", handled)
+	}
+	want := []PushMessage{
+		{Name: "MOVING", Payload: []interface{}{int64(1), int64(15), "10.0.0.2:6379"}},
+		{Name: "custom", Payload: []interface{}{"hello"}},
+	}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Errorf("got %v, want %v", msgs, want)
+	}
+}
+
+func TestMaintNotificationsRelaxedTimeout(t *testing.T) {
+	m := newMaintNotifications(&MaintNotificationsOptions{})
+	if got := m.timeout(time.Second); got != time.Second {
+		t.Fatalf("got %s before the maintenance, want 1s", got)
+	}
+
+	ctx := context.Background()
+	m.notify(ctx, nil, &MaintNotification{Type: MaintMigrating, SeqID: 1, Time: 5 * time.Second})
+	if got := m.timeout(time.Second); got != 10*time.Second {
+		t.Errorf("got %s during the maintenance, want 10s", got)
+	}
+	if got := m.timeout(time.Minute); got != time.Minute {
+		t.Errorf("got %s for a longer timeout, want 1m", got)
+	}
+	if got := m.timeout(-1); got != -1 {
+		t.Errorf("got %s without timeout, want -1", got)
+	}
+
+	m.notify(ctx, nil, &MaintNotification{Type: MaintMigrated, SeqID: 2})
+	if got := m.timeout(time.Second); got != time.Second {
+		t.Errorf("got %s after the maintenance, want 1s", got)
+	}
+
+	m = newMaintNotifications(&MaintNotificationsOptions{RelaxedTimeout: -1})
+	m.notify(ctx, nil, &MaintNotification{Type: MaintFailingOver, SeqID: 1, Time: 5 * time.Second})
+	if got := m.timeout(time.Second); got != time.Second {
+		t.Errorf("got %s with the relaxed timeout disabled, want 1s", got)
+	}
+}
+
+func TestMaintNotificationsMoving(t *testing.T) {
+	client := NewClient(&Options{
+		Addr:               "10.0.0.1:6379",
+		Protocol:           3,
+		MaintNotifications: &MaintNotificationsOptions{Preconnect: true},
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, fmt.Errorf("dial %s", addr)
+		},
+	})
+	defer client.Close()
+
+	client.maint.notify(context.Background(), client.baseClient, &MaintNotification{
+		Type:     MaintMoving,
+		SeqID:    1,
+		Time:     15 * time.Second,
+		Endpoint: "10.0.0.2:6379",
+	})
+	if got := client.maint.addr(client.opt.Addr); got != "10.0.0.2:6379" {
+		t.Errorf("got %s, want the announced endpoint", got)
+	}
+	err := client.Ping(context.Background()).Err()
+	if err == nil || !strings.Contains(err.Error(), "dial 10.0.0.2:6379") {
+		t.Errorf("got %v, want a dial to the announced endpoint", err)
+	}
+}
+
+func TestRegisterReadOnlyCommands(t *testing.T) {
+	client := NewClusterClient(&ClusterOptions{Addrs: []string{":0"}, ReadOnly: true})
+	defer client.Close()
+	ctx := context.Background()
+
+	for _, name := range []string{"json.get", "ft.search", "ts.range", "bf.exists"} {
+		if !client.cmdIsReadOnly(ctx, name) {
+			t.Errorf("%s is not read-only", name)
+		}
+	}
+
+	RegisterReadOnlyCommands("MYMOD.GET")
+	if !client.cmdIsReadOnly(ctx, NewCmd(ctx, "MYMOD.GET", "key").Name()) {
+		t.Error("MYMOD.GET is not read-only")
+	}
+	UnregisterReadOnlyCommands("mymod.get")
+	if isRegisteredReadOnly("mymod.get") {
+		t.Error("mymod.get is still registered")
+	}
+}
+
+func TestTSAutoBucket(t *testing.T) {
+	const day = 86400000
+	for _, tc := range []struct {
+		span      int64
+		maxPoints int
+		minBucket int
+		want      int64
+	}{
+		{span: 999, maxPoints: 1000, want: 0},
+		{span: 2999, maxPoints: 1000, want: 5},
+		{span: 999, maxPoints: 1000, minBucket: 10, want: 10},
+		{span: 10 * day, maxPoints: 1, want: 30 * day},
+		{span: 40 * day, maxPoints: 1, want: 60 * day},
+	} {
+		if tc.span > math.MaxInt {
+			continue
+		}
+		want := tc.want
+		if want > math.MaxInt {
+			// the 30 days bucket overflows the int of 32-bit platforms
+			want = tc.span + 1
+		}
+		if got := tsAutoBucket(int(tc.span), tc.maxPoints, tc.minBucket); int64(got) != want {
+			t.Errorf("got %d for %+v", got, tc)
+		}
+	}
+}
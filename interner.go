@@ -0,0 +1,61 @@
+package redis
+
+import "sync"
+
+// StringInterner returns the strings of replies, see Options.StringInterner.
+// It must be safe for concurrent use.
+type StringInterner interface {
+	// Intern returns the string of b, e.g. a string it returned before.
+	// It must not retain b, which is in the read buffer of a connection.
+	Intern(b []byte) string
+}
+
+// NewStringInterner returns a StringInterner of the replies of at most maxLen
+// bytes, which are the likely repeated ones, such as the field names of
+// hashes or enum-like values. It keeps at most maxStrings strings and forgets
+// them all when it is full, so that the strings which are not repeated, such
+// as small values, do not keep its memory. The default maxLen is 32 and the
+// default maxStrings is 4096.
+func NewStringInterner(maxLen, maxStrings int) StringInterner {
+	if maxLen <= 0 {
+		maxLen = 32
+	}
+	if maxStrings <= 0 {
+		maxStrings = 4096
+	}
+	return &stringInterner{
+		maxLen:     maxLen,
+		maxStrings: maxStrings,
+		strs:       make(map[string]string),
+	}
+}
+
+type stringInterner struct {
+	maxLen     int
+	maxStrings int
+
+	mu   sync.RWMutex
+	strs map[string]string
+}
+
+func (in *stringInterner) Intern(b []byte) string {
+	if len(b) > in.maxLen {
+		return string(b)
+	}
+
+	in.mu.RLock()
+	s, ok := in.strs[string(b)]
+	in.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	s = string(b)
+	in.mu.Lock()
+	if len(in.strs) >= in.maxStrings {
+		in.strs = make(map[string]string)
+	}
+	in.strs[s] = s
+	in.mu.Unlock()
+	return s
+}
@@ -0,0 +1,66 @@
+package redis_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestStringInterner(t *testing.T) {
+	in := redis.NewStringInterner(8, 2)
+	field := []byte("field")
+	if s := in.Intern(field); s != "field" {
+		t.Fatalf("got %q", s)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { in.Intern(field) }); allocs != 0 {
+		t.Fatalf("got %v allocs interning a known string", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { in.Intern([]byte("too long to intern")) }); allocs != 1 {
+		t.Fatalf("got %v allocs for a long string", allocs)
+	}
+
+	// the interner forgets its strings when it is full
+	a, b := []byte("a"), []byte("b")
+	if allocs := testing.AllocsPerRun(100, func() {
+		in.Intern(a)
+		in.Intern(b)
+		in.Intern(field)
+	}); allocs < 3 {
+		t.Fatalf("got %v allocs interning more strings than maxStrings", allocs)
+	}
+}
+
+type recordingInterner struct {
+	mu   sync.Mutex
+	strs []string
+}
+
+func (in *recordingInterner) Intern(b []byte) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.strs = append(in.strs, string(b))
+	return string(b)
+}
+
+func TestOptionsStringInterner(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	in := new(recordingInterner)
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), StringInterner: in})
+	defer rdb.Close()
+
+	if err := rdb.Set(ctx, "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := rdb.Get(ctx, "key").Result(); err != nil || s != "value" {
+		t.Fatalf("got %q, %v", s, err)
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if n := len(in.strs); n == 0 || in.strs[n-1] != "value" {
+		t.Fatalf("interned %q", in.strs)
+	}
+}
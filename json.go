@@ -1,10 +1,15 @@
 package redis
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 
+	"github.com/redis/go-redis/v9/internal/hashtag"
 	"github.com/redis/go-redis/v9/internal/proto"
 	"github.com/redis/go-redis/v9/internal/util"
 )
@@ -26,7 +31,9 @@ type JSONCmdable interface {
 	JSONForget(ctx context.Context, key, path string) *IntCmd
 	JSONGet(ctx context.Context, key string, paths ...string) *JSONCmd
 	JSONGetWithArgs(ctx context.Context, key string, options *JSONGetArgs, paths ...string) *JSONCmd
+	JSONGetScan(ctx context.Context, key, path string, dst interface{}) error
 	JSONMerge(ctx context.Context, key, path string, value string) *StatusCmd
+	JSONMergeWithArgs(ctx context.Context, args *JSONMergeArgs) *StatusCmd
 	JSONMSetArgs(ctx context.Context, docs []JSONSetArgs) *StatusCmd
 	JSONMSet(ctx context.Context, params ...interface{}) *StatusCmd
 	JSONMGet(ctx context.Context, path string, keys ...string) *JSONSliceCmd
@@ -47,6 +54,14 @@ type JSONSetArgs struct {
 	Value interface{}
 }
 
+// JSONMergeArgs is the argument of JSONMergeWithArgs. Value is marshaled
+// like the value of JSONSet.
+type JSONMergeArgs struct {
+	Key   string
+	Path  string
+	Value interface{}
+}
+
 type JSONArrIndexArgs struct {
 	Start int
 	Stop  *int
@@ -111,6 +126,84 @@ func (cmd JSONCmd) Expanded() (interface{}, error) {
 	return cmd.expanded, nil
 }
 
+// Scan decodes the reply of JSON.GET into dst using encoding/json.
+//
+// JSONPath queries (paths starting with "$") reply with an array of matches,
+// which Scan unwraps: a single match is decoded into dst, unless dst is a slice
+// and the match is not a JSON array, in which case the whole array of matches is
+// decoded into dst, as are several matches. No match is reported as Nil.
+// Replies to legacy paths and to several paths are decoded as is.
+func (cmd *JSONCmd) Scan(dst interface{}) error {
+	return cmd.scan(dst, json.Unmarshal)
+}
+
+func (cmd *JSONCmd) scan(dst interface{}, unmarshal func([]byte, interface{}) error) error {
+	if err := cmd.Err(); err != nil {
+		return err
+	}
+
+	val := cmd.Val()
+	if val == "" {
+		return Nil
+	}
+
+	if paths := cmd.paths(); len(paths) != 1 || !strings.HasPrefix(paths[0], "$") {
+		return unmarshal([]byte(val), dst)
+	}
+
+	var matches []json.RawMessage
+	if err := json.Unmarshal([]byte(val), &matches); err != nil {
+		return err
+	}
+
+	switch len(matches) {
+	case 0:
+		return Nil
+	case 1:
+		if !isSlicePtr(dst) || isJSONArray(matches[0]) {
+			return unmarshal(matches[0], dst)
+		}
+	default:
+		if !isSlicePtr(dst) {
+			return fmt.Errorf("redis: JSON path matched %d values, but %T is not a slice", len(matches), dst)
+		}
+	}
+	return unmarshal([]byte(val), dst)
+}
+
+// paths returns the paths passed to JSON.GET.
+func (cmd *JSONCmd) paths() []string {
+	var paths []string
+	for i := 2; i < len(cmd.args); i++ {
+		switch s := cmd.stringArg(i); s {
+		case "INDENT", "NEWLINE", "SPACE":
+			i++
+		default:
+			paths = append(paths, s)
+		}
+	}
+	return paths
+}
+
+func isSlicePtr(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return false
+	}
+	switch rv.Elem().Kind() {
+	case reflect.Slice:
+		return rv.Elem().Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return true
+	}
+	return false
+}
+
+func isJSONArray(b json.RawMessage) bool {
+	b = bytes.TrimLeft(b, " \t\r\n")
+	return len(b) > 0 && b[0] == '['
+}
+
 func (cmd *JSONCmd) readReply(rd *proto.Reader) error {
 	// nil response from JSON.(M)GET (cmd.baseCmd.err will be "redis: nil")
 	if cmd.baseCmd.Err() == Nil {
@@ -448,6 +541,12 @@ func (c cmdable) JSONGetWithArgs(ctx context.Context, key string, options *JSONG
 	return cmd
 }
 
+// JSONGetScan gets the value at path and decodes it into dst,
+// unwrapping the array of matches of JSONPath queries. See JSONCmd.Scan.
+func (c cmdable) JSONGetScan(ctx context.Context, key, path string, dst interface{}) error {
+	return c.JSONGet(ctx, key, path).Scan(dst)
+}
+
 // JSONMerge merges a given JSON value into matching paths.
 // For more information, see https://redis.io/commands/json.merge
 func (c cmdable) JSONMerge(ctx context.Context, key, path string, value string) *StatusCmd {
@@ -457,19 +556,116 @@ func (c cmdable) JSONMerge(ctx context.Context, key, path string, value string)
 	return cmd
 }
 
+// JSONMergeWithArgs merges the JSON value of args into the matching paths.
+// The value must be something that can be marshaled to JSON (using encoding/JSON) unless
+// the argument is a string or []byte when we assume that it can be passed directly as JSON.
+// For more information, see https://redis.io/commands/json.merge
+func (c cmdable) JSONMergeWithArgs(ctx context.Context, args *JSONMergeArgs) *StatusCmd {
+	value, err := jsonValue(args.Value)
+	cmd := NewStatusCmd(ctx, "JSON.MERGE", args.Key, args.Path, value)
+	if err != nil {
+		cmd.SetErr(err)
+	} else {
+		_ = c(ctx, cmd)
+	}
+	return cmd
+}
+
 // JSONMGet returns the values at the specified path from multiple key arguments.
 // Note - the arguments are reversed when compared with `JSON.MGET` as we want
 // to follow the pattern of having the last argument be variable.
 // For more information, see https://redis.io/commands/json.mget
 func (c cmdable) JSONMGet(ctx context.Context, path string, keys ...string) *JSONSliceCmd {
-	args := make([]interface{}, len(keys)+1)
+	cmd := NewJSONSliceCmd(ctx, jsonMGetArgs(path, keys)...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func jsonMGetArgs(path string, keys []string) []interface{} {
+	args := make([]interface{}, len(keys)+1, len(keys)+2)
 	args[0] = "JSON.MGET"
 	for n, key := range keys {
 		args[n+1] = key
 	}
-	args = append(args, path)
-	cmd := NewJSONSliceCmd(ctx, args...)
-	_ = c(ctx, cmd)
+	return append(args, path)
+}
+
+// JSONMGetSharded is like JSONMGet. It is provided so the same code works
+// with Client, ClusterClient and Ring.
+func (c *Client) JSONMGetSharded(ctx context.Context, path string, keys ...string) *JSONSliceCmd {
+	return c.JSONMGet(ctx, path, keys...)
+}
+
+// JSONMGetSharded is like JSONMGet, but splits the keys by hash slot and
+// pipelines one JSON.MGET per slot, so the keys may live on different nodes.
+// The values are returned in the order of keys.
+func (c *ClusterClient) JSONMGetSharded(ctx context.Context, path string, keys ...string) *JSONSliceCmd {
+	return jsonMGetSharded(ctx, c.Pipeline(), path, keys, func(key string) (string, error) {
+		return strconv.Itoa(hashtag.Slot(key)), nil
+	})
+}
+
+// JSONMGetSharded is like JSONMGet, but splits the keys by shard and
+// pipelines one JSON.MGET per shard. The values are returned in the order of keys.
+func (c *Ring) JSONMGetSharded(ctx context.Context, path string, keys ...string) *JSONSliceCmd {
+	return jsonMGetSharded(ctx, c.Pipeline(), path, keys, func(key string) (string, error) {
+		shard, err := c.sharding.GetByKey(key)
+		if err != nil {
+			return "", err
+		}
+		return shard.addr, nil
+	})
+}
+
+func jsonMGetSharded(
+	ctx context.Context, pipe Pipeliner, path string, keys []string, shardOf func(key string) (string, error),
+) *JSONSliceCmd {
+	cmd := NewJSONSliceCmd(ctx, jsonMGetArgs(path, keys)...)
+
+	// positions of the keys in each shard, in the order the shards were seen
+	var shards []string
+	positions := make(map[string][]int)
+	for i, key := range keys {
+		shard, err := shardOf(key)
+		if err != nil {
+			cmd.SetErr(err)
+			return cmd
+		}
+		if _, ok := positions[shard]; !ok {
+			shards = append(shards, shard)
+		}
+		positions[shard] = append(positions[shard], i)
+	}
+
+	cmds := make([]*JSONSliceCmd, len(shards))
+	for i, shard := range shards {
+		pos := positions[shard]
+		shardKeys := make([]string, len(pos))
+		for j, n := range pos {
+			shardKeys[j] = keys[n]
+		}
+		cmds[i] = pipe.JSONMGet(ctx, path, shardKeys...)
+	}
+	if len(cmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			cmd.SetErr(err)
+			return cmd
+		}
+	}
+
+	val := make([]interface{}, len(keys))
+	for i, shard := range shards {
+		pos := positions[shard]
+		vals := cmds[i].Val()
+		if len(vals) != len(pos) {
+			cmd.SetErr(fmt.Errorf("redis: JSON.MGET replied %d values for %d keys", len(vals), len(pos)))
+			return cmd
+		}
+		for j, n := range pos {
+			val[n] = vals[j]
+		}
+	}
+	cmd.SetVal(val)
 	return cmd
 }
 
@@ -533,17 +729,8 @@ func (c cmdable) JSONSet(ctx context.Context, key, path string, value interface{
 // the argument is a string or []byte when we assume that it can be passed directly as JSON.
 // For more information, see https://redis.io/commands/json.set
 func (c cmdable) JSONSetMode(ctx context.Context, key, path string, value interface{}, mode string) *StatusCmd {
-	var bytes []byte
-	var err error
-	switch v := value.(type) {
-	case string:
-		bytes = []byte(v)
-	case []byte:
-		bytes = v
-	default:
-		bytes, err = json.Marshal(v)
-	}
-	args := []interface{}{"JSON.SET", key, path, util.BytesToString(bytes)}
+	val, err := jsonValue(value)
+	args := []interface{}{"JSON.SET", key, path, val}
 	if mode != "" {
 		switch strings.ToUpper(mode) {
 		case "XX", "NX":
@@ -562,6 +749,19 @@ func (c cmdable) JSONSetMode(ctx context.Context, key, path string, value interf
 	return cmd
 }
 
+// jsonValue returns value serialized as JSON. Strings and []byte are assumed to be JSON already.
+func jsonValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return util.BytesToString(v), nil
+	default:
+		b, err := json.Marshal(v)
+		return util.BytesToString(b), err
+	}
+}
+
 // JSONStrAppend appends the JSON-string values to the string at the specified path.
 // For more information, see https://redis.io/commands/json.strappend
 func (c cmdable) JSONStrAppend(ctx context.Context, key, path, value string) *IntPointerSliceCmd {
@@ -597,3 +797,101 @@ func (c cmdable) JSONType(ctx context.Context, key, path string) *JSONSliceCmd {
 	_ = c(ctx, cmd)
 	return cmd
 }
+
+//------------------------------------------------------------------------------
+
+// JSONCodec marshals and unmarshals the Go values stored with JSONSetStruct
+// and loaded with JSONGetStruct, e.g. to plug in a faster JSON library.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// JSONSetStruct marshals v with the client JSONCodec and sets it at path,
+// the root of the document when path is empty.
+func (c *Client) JSONSetStruct(ctx context.Context, key, path string, v interface{}) error {
+	_, err := jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, "")
+	return err
+}
+
+// JSONSetStructMode is like JSONSetStruct, but with the "NX" or "XX" mode of JSON.SET.
+// It reports false when the value was not set because of the mode.
+func (c *Client) JSONSetStructMode(ctx context.Context, key, path string, v interface{}, mode string) (bool, error) {
+	return jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, mode)
+}
+
+// JSONGetStruct gets the value at path, the root of the document when path is empty,
+// and unmarshals it into dst with the client JSONCodec. The array of matches of
+// JSONPath queries is unwrapped like by JSONCmd.Scan. A missing key or path is
+// reported as Nil.
+func (c *Client) JSONGetStruct(ctx context.Context, key, path string, dst interface{}) error {
+	return jsonGetStruct(ctx, c, c.opt.JSONCodec, key, path, dst)
+}
+
+func (c *ClusterClient) JSONSetStruct(ctx context.Context, key, path string, v interface{}) error {
+	_, err := jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, "")
+	return err
+}
+
+func (c *ClusterClient) JSONSetStructMode(ctx context.Context, key, path string, v interface{}, mode string) (bool, error) {
+	return jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, mode)
+}
+
+func (c *ClusterClient) JSONGetStruct(ctx context.Context, key, path string, dst interface{}) error {
+	return jsonGetStruct(ctx, c, c.opt.JSONCodec, key, path, dst)
+}
+
+func (c *Ring) JSONSetStruct(ctx context.Context, key, path string, v interface{}) error {
+	_, err := jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, "")
+	return err
+}
+
+func (c *Ring) JSONSetStructMode(ctx context.Context, key, path string, v interface{}, mode string) (bool, error) {
+	return jsonSetStruct(ctx, c, c.opt.JSONCodec, key, path, v, mode)
+}
+
+func (c *Ring) JSONGetStruct(ctx context.Context, key, path string, dst interface{}) error {
+	return jsonGetStruct(ctx, c, c.opt.JSONCodec, key, path, dst)
+}
+
+func jsonSetStruct(
+	ctx context.Context, c JSONCmdable, codec JSONCodec, key, path string, v interface{}, mode string,
+) (bool, error) {
+	if codec == nil {
+		codec = stdJSONCodec{}
+	}
+	if path == "" {
+		path = "$"
+	}
+
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	err = c.JSONSetMode(ctx, key, path, b, mode).Err()
+	if err == Nil {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func jsonGetStruct(ctx context.Context, c JSONCmdable, codec JSONCodec, key, path string, dst interface{}) error {
+	if codec == nil {
+		codec = stdJSONCodec{}
+	}
+	if path == "" {
+		path = "$"
+	}
+	return c.JSONGet(ctx, key, path).scan(dst, codec.Unmarshal)
+}
@@ -0,0 +1,476 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOp is an operation of a JSON Patch document (RFC 6902).
+type JSONPatchOp struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" and "test".
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	// From is the JSON Pointer of the value moved or copied.
+	From string `json:"from,omitempty"`
+	// Value is the JSON value added, replaced or tested.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ParseJSONPatch parses a JSON Patch document.
+func ParseJSONPatch(data []byte) ([]JSONPatchOp, error) {
+	var patch []JSONPatchOp
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("redis: invalid JSON patch: %w", err)
+	}
+	return patch, nil
+}
+
+// JSONApplyPatch applies the JSON Patch to the document stored at key.
+//
+// The document is watched and checked against the whole patch first, so a
+// failing operation, including a failing "test", leaves it untouched. The patch
+// is then written with the equivalent JSON.SET, JSON.DEL, JSON.ARRINSERT and
+// JSON.ARRAPPEND commands in a MULTI/EXEC transaction. TxFailedErr is returned
+// when the document was modified concurrently.
+func (c *Client) JSONApplyPatch(ctx context.Context, key string, patch []JSONPatchOp) error {
+	return jsonApplyPatch(ctx, c.Watch, key, patch)
+}
+
+func (c *ClusterClient) JSONApplyPatch(ctx context.Context, key string, patch []JSONPatchOp) error {
+	return jsonApplyPatch(ctx, c.Watch, key, patch)
+}
+
+func (c *Ring) JSONApplyPatch(ctx context.Context, key string, patch []JSONPatchOp) error {
+	return jsonApplyPatch(ctx, c.Watch, key, patch)
+}
+
+func jsonApplyPatch(
+	ctx context.Context, watch func(context.Context, func(*Tx) error, ...string) error, key string, patch []JSONPatchOp,
+) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	return watch(ctx, func(tx *Tx) error {
+		cmd := tx.JSONGet(ctx, key)
+		if err := cmd.Err(); err != nil && err != Nil {
+			return err
+		}
+
+		p := &jsonPatcher{exists: cmd.Err() == nil}
+		if p.exists {
+			doc, err := decodeJSONPatchValue([]byte(cmd.Val()))
+			if err != nil {
+				return err
+			}
+			p.doc = doc
+		}
+		for i := range patch {
+			if err := p.apply(&patch[i]); err != nil {
+				return fmt.Errorf("redis: JSON patch operation %d: %w", i, err)
+			}
+		}
+
+		_, err := tx.TxPipelined(ctx, func(pipe Pipeliner) error {
+			for _, args := range p.cmds {
+				pipe.Do(ctx, append([]interface{}{args[0], key}, args[1:]...)...)
+			}
+			return nil
+		})
+		return err
+	}, key)
+}
+
+// jsonPatcher applies a patch to a local copy of the document, validating
+// the operations and recording the commands applying them in Redis.
+type jsonPatcher struct {
+	doc    interface{}
+	exists bool
+	// cmds are the commands without their key.
+	cmds [][]interface{}
+}
+
+func (p *jsonPatcher) apply(op *JSONPatchOp) error {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return fmt.Errorf("%q requires a value", op.Op)
+		}
+		value, err := decodeJSONPatchValue(op.Value)
+		if err != nil {
+			return err
+		}
+		switch op.Op {
+		case "add":
+			return p.add(path, value, compactJSON(op.Value))
+		case "replace":
+			return p.replace(path, value, compactJSON(op.Value))
+		default:
+			cur, err := p.get(path)
+			if err != nil {
+				return err
+			}
+			if !jsonEqual(cur, value) {
+				return fmt.Errorf("test failed at %q", op.Path)
+			}
+			return nil
+		}
+	case "remove":
+		return p.remove(path)
+	case "move", "copy":
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return err
+		}
+		value, err := p.get(from)
+		if err != nil {
+			return err
+		}
+		if op.Op == "move" {
+			if isJSONPointerPrefix(from, path) {
+				return fmt.Errorf("cannot move %q into itself", op.From)
+			}
+			if err := p.remove(from); err != nil {
+				return err
+			}
+		} else {
+			value = copyJSONValue(value)
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return p.add(path, value, string(b))
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+func (p *jsonPatcher) get(path []string) (interface{}, error) {
+	if !p.exists {
+		return nil, fmt.Errorf("the document does not exist")
+	}
+	node := p.doc
+	for _, token := range path {
+		child, err := jsonChild(node, token)
+		if err != nil {
+			return nil, err
+		}
+		node = child
+	}
+	return node, nil
+}
+
+func (p *jsonPatcher) add(path []string, value interface{}, raw string) error {
+	if len(path) == 0 {
+		p.doc, p.exists = value, true
+		p.cmds = append(p.cmds, []interface{}{"JSON.SET", "$", raw})
+		return nil
+	}
+
+	parentPath, token := path[:len(path)-1], path[len(path)-1]
+	jsonPath, parent, err := p.jsonPath(parentPath)
+	if err != nil {
+		return err
+	}
+
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		parent[token] = value
+		p.cmds = append(p.cmds, []interface{}{"JSON.SET", jsonPath + jsonPathMember(token), raw})
+		return nil
+	case []interface{}:
+		i, err := jsonArrayIndex(token, len(parent), true)
+		if err != nil {
+			return err
+		}
+		arr := append(parent, nil)
+		copy(arr[i+1:], arr[i:])
+		arr[i] = value
+		if token == "-" {
+			p.cmds = append(p.cmds, []interface{}{"JSON.ARRAPPEND", jsonPath, raw})
+		} else {
+			p.cmds = append(p.cmds, []interface{}{"JSON.ARRINSERT", jsonPath, i, raw})
+		}
+		return p.set(parentPath, arr)
+	default:
+		return fmt.Errorf("cannot add a member to a JSON %s", jsonTypeName(parent))
+	}
+}
+
+func (p *jsonPatcher) replace(path []string, value interface{}, raw string) error {
+	if _, err := p.get(path); err != nil {
+		return err
+	}
+	jsonPath, _, err := p.jsonPath(path)
+	if err != nil {
+		return err
+	}
+	p.cmds = append(p.cmds, []interface{}{"JSON.SET", jsonPath, raw})
+	return p.set(path, value)
+}
+
+func (p *jsonPatcher) remove(path []string) error {
+	if _, err := p.get(path); err != nil {
+		return err
+	}
+	jsonPath, _, err := p.jsonPath(path)
+	if err != nil {
+		return err
+	}
+	p.cmds = append(p.cmds, []interface{}{"JSON.DEL", jsonPath})
+
+	if len(path) == 0 {
+		p.doc, p.exists = nil, false
+		return nil
+	}
+	parentPath, token := path[:len(path)-1], path[len(path)-1]
+	parent, _ := p.get(parentPath)
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		delete(parent, token)
+		return nil
+	default:
+		arr := parent.([]interface{})
+		i, _ := jsonArrayIndex(token, len(arr), false)
+		return p.set(parentPath, append(arr[:i:i], arr[i+1:]...))
+	}
+}
+
+// set replaces the existing value at path.
+func (p *jsonPatcher) set(path []string, value interface{}) error {
+	if len(path) == 0 {
+		p.doc = value
+		return nil
+	}
+	parent, err := p.get(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	token := path[len(path)-1]
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		parent[token] = value
+	case []interface{}:
+		i, err := jsonArrayIndex(token, len(parent), false)
+		if err != nil {
+			return err
+		}
+		parent[i] = value
+	}
+	return nil
+}
+
+// jsonPath returns the JSONPath of the existing value at path, and the value.
+func (p *jsonPatcher) jsonPath(path []string) (string, interface{}, error) {
+	if !p.exists {
+		return "", nil, fmt.Errorf("the document does not exist")
+	}
+	var b strings.Builder
+	b.WriteString("$")
+	node := p.doc
+	for _, token := range path {
+		if _, ok := node.([]interface{}); ok {
+			b.WriteString("[" + token + "]")
+		} else {
+			b.WriteString(jsonPathMember(token))
+		}
+		child, err := jsonChild(node, token)
+		if err != nil {
+			return "", nil, err
+		}
+		node = child
+	}
+	return b.String(), node, nil
+}
+
+func jsonChild(node interface{}, token string) (interface{}, error) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q does not exist", token)
+		}
+		return child, nil
+	case []interface{}:
+		i, err := jsonArrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		return node[i], nil
+	default:
+		return nil, fmt.Errorf("cannot get member %q of a JSON %s", token, jsonTypeName(node))
+	}
+}
+
+// jsonArrayIndex parses the array index token. "-", the index past the last
+// element, is only allowed when end is set.
+func jsonArrayIndex(token string, n int, end bool) (int, error) {
+	if token == "-" && end {
+		return n, nil
+	}
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > n || i == n && !end {
+		return 0, fmt.Errorf("array index %d is out of range", i)
+	}
+	return i, nil
+}
+
+// parseJSONPointer parses a JSON Pointer (RFC 6901) into its reference tokens.
+func parseJSONPointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q", s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isJSONPointerPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonPathMember returns the JSONPath selecting the member name of an object.
+func jsonPathMember(name string) string {
+	if isJSONPathIdent(name) {
+		return "." + name
+	}
+	b, _ := json.Marshal(name)
+	return "[" + string(b) + "]"
+}
+
+func isJSONPathIdent(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isParamNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeJSONPatchValue(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func compactJSON(b []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return string(b)
+	}
+	return buf.String()
+}
+
+func copyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyJSONValue(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			a[i] = copyJSONValue(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// jsonEqual compares JSON values as required by the "test" operation:
+// numbers are compared by value.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			w, ok := b[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, err1 := a.Float64()
+		y, err2 := b.Float64()
+		if err1 != nil || err2 != nil {
+			return a == b
+		}
+		return x == y
+	default:
+		return a == b
+	}
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -270,6 +270,89 @@ var _ = Describe("JSON Commands", Label("json"), func() {
 			Expect(res).To(Equal(`[{"a":1,"b":3,"c":4}]`))
 		})
 
+		It("should JSONMergeWithArgs", Label("json.merge", "json"), func() {
+			res, err := client.JSONSet(ctx, "merge2", "$", map[string]int{"a": 1, "b": 2}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("OK"))
+
+			res, err = client.JSONMergeWithArgs(ctx, &redis.JSONMergeArgs{
+				Key:   "merge2",
+				Path:  "$",
+				Value: map[string]interface{}{"b": nil, "c": 4},
+			}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("OK"))
+
+			res, err = client.JSONGet(ctx, "merge2", "$").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(`[{"a":1,"c":4}]`))
+		})
+
+		It("should JSONGetScan", Label("json.get", "json"), func() {
+			type book struct {
+				Title string   `json:"title"`
+				Price float64  `json:"price"`
+				Tags  []string `json:"tags"`
+			}
+			type store struct {
+				Books []book `json:"books"`
+			}
+
+			err := client.JSONSet(ctx, "scan1", "$", store{Books: []book{
+				{Title: "a", Price: 5, Tags: []string{"x", "y"}},
+				{Title: "b", Price: 15},
+			}}).Err()
+			Expect(err).NotTo(HaveOccurred())
+
+			var s store
+			Expect(client.JSONGetScan(ctx, "scan1", "$", &s)).NotTo(HaveOccurred())
+			Expect(s.Books).To(HaveLen(2))
+
+			var b book
+			Expect(client.JSONGetScan(ctx, "scan1", "$.books[0]", &b)).NotTo(HaveOccurred())
+			Expect(b.Title).To(Equal("a"))
+
+			var tags []string
+			Expect(client.JSONGetScan(ctx, "scan1", "$.books[0].tags", &tags)).NotTo(HaveOccurred())
+			Expect(tags).To(Equal([]string{"x", "y"}))
+
+			var prices []float64
+			Expect(client.JSONGetScan(ctx, "scan1", "$..price", &prices)).NotTo(HaveOccurred())
+			Expect(prices).To(Equal([]float64{5, 15}))
+
+			var title string
+			Expect(client.JSONGetScan(ctx, "scan1", ".books[1].title", &title)).NotTo(HaveOccurred())
+			Expect(title).To(Equal("b"))
+
+			Expect(client.JSONGetScan(ctx, "scan1", "$.missing", &title)).To(Equal(redis.Nil))
+			Expect(client.JSONGetScan(ctx, "missing", "$", &s)).To(Equal(redis.Nil))
+		})
+
+		It("should JSONSetStruct and JSONGetStruct", Label("json.set", "json.get", "json"), func() {
+			Expect(client.JSONSetStruct(ctx, "struct1", "", JSONGetTestStruct{Hello: "world"})).NotTo(HaveOccurred())
+
+			var v JSONGetTestStruct
+			Expect(client.JSONGetStruct(ctx, "struct1", "", &v)).NotTo(HaveOccurred())
+			Expect(v.Hello).To(Equal("world"))
+
+			var hello string
+			Expect(client.JSONGetStruct(ctx, "struct1", "$.hello", &hello)).NotTo(HaveOccurred())
+			Expect(hello).To(Equal("world"))
+
+			ok, err := client.JSONSetStructMode(ctx, "struct1", "$", JSONGetTestStruct{Hello: "again"}, "NX")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+
+			ok, err = client.JSONSetStructMode(ctx, "struct1", "$.hello", "again", "XX")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(client.JSONGetStruct(ctx, "struct1", "$.hello", &hello)).NotTo(HaveOccurred())
+			Expect(hello).To(Equal("again"))
+
+			Expect(client.JSONGetStruct(ctx, "struct1", "$.missing", &hello)).To(Equal(redis.Nil))
+			Expect(client.JSONGetStruct(ctx, "missing", "", &v)).To(Equal(redis.Nil))
+		})
+
 		It("should JSONMSet", Label("json.mset", "json", "NonRedisEnterprise"), func() {
 			doc1 := redis.JSONSetArgs{Key: "mset1", Path: "$", Value: `{"a": 1}`}
 			doc2 := redis.JSONSetArgs{Key: "mset2", Path: "$", Value: 2}
@@ -327,6 +410,46 @@ var _ = Describe("JSON Commands", Label("json"), func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(iRes).To(Equal([]interface{}{nil, nil}))
 		})
+
+		It("should JSONApplyPatch", Label("json.set", "json"), func() {
+			Expect(client.JSONSet(ctx, "patch1", "$", `{"name": "a", "tags": ["x", "z"], "stats": {"n": 1}}`).Err()).NotTo(HaveOccurred())
+
+			patch, err := redis.ParseJSONPatch([]byte(`[
+				{"op": "test", "path": "/name", "value": "a"},
+				{"op": "replace", "path": "/name", "value": "b"},
+				{"op": "add", "path": "/tags/1", "value": "y"},
+				{"op": "add", "path": "/tags/-", "value": "w"},
+				{"op": "copy", "from": "/stats", "path": "/prev"},
+				{"op": "move", "from": "/stats/n", "path": "/count"},
+				{"op": "remove", "path": "/stats"}
+			]`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.JSONApplyPatch(ctx, "patch1", patch)).NotTo(HaveOccurred())
+
+			res, err := client.JSONGet(ctx, "patch1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(MatchJSON(`{"name": "b", "tags": ["x", "y", "z", "w"], "prev": {"n": 1}, "count": 1}`))
+
+			// a failing test leaves the document untouched
+			err = client.JSONApplyPatch(ctx, "patch1", []redis.JSONPatchOp{
+				{Op: "remove", Path: "/prev"},
+				{Op: "test", Path: "/count", Value: []byte(`2`)},
+			})
+			Expect(err).To(HaveOccurred())
+			res, err = client.JSONGet(ctx, "patch1", "$.prev.n").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal("[1]"))
+		})
+
+		It("should JSONMGetSharded", Label("json.mget", "json", "NonRedisEnterprise"), func() {
+			Expect(client.JSONSet(ctx, "{a}sharded1", "$", `{"a": 1}`).Err()).NotTo(HaveOccurred())
+			Expect(client.JSONSet(ctx, "{b}sharded2", "$", `{"a": 2}`).Err()).NotTo(HaveOccurred())
+			Expect(client.JSONSet(ctx, "{a}sharded3", "$", `{"a": 3}`).Err()).NotTo(HaveOccurred())
+
+			res, err := client.JSONMGetSharded(ctx, "$.a", "{a}sharded1", "{b}sharded2", "missing", "{a}sharded3").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"[1]", "[2]", nil, "[3]"}))
+		})
 	})
 
 	Describe("Misc", Label("misc"), func() {
@@ -0,0 +1,385 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// WithKeyPrefix returns a Cmdable that prepends prefix to the keys of every
+// command sent through client, so several tenants can share one database
+// without seeing each other's keys.
+//
+// The keys are located with the key specs reported by the COMMAND command,
+// which is issued once on first use, and a few well-known commands with
+// variable key positions such as EVAL, ZUNIONSTORE, XREAD and SORT.
+// Commands whose keys can't be located fail instead of being sent unprefixed.
+//
+// Patterns of KEYS and SCAN are prefixed too, with the glob-style special
+// characters of the prefix escaped, and a SCAN without MATCH matches the keys
+// of the prefix. The prefix is stripped from the key names returned by KEYS,
+// SCAN, BLPOP, BRPOP, BZPOPMIN, BZPOPMAX, LMPOP, ZMPOP and XREAD. RANDOMKEY
+// fails, as it may return the keys of other tenants. Script bodies and PubSub
+// channels are left untouched.
+func WithKeyPrefix(client UniversalClient, prefix string) Cmdable {
+	c := &keyPrefixClient{
+		client:        client,
+		prefix:        prefix,
+		patternPrefix: escapeGlob(prefix),
+	}
+	c.cmdsInfoCache = newCmdsInfoCache(func(ctx context.Context) (map[string]*CommandInfo, error) {
+		return client.Command(ctx).Result()
+	})
+	c.cmdable = c.Process
+	return c
+}
+
+type keyPrefixClient struct {
+	cmdable
+
+	client        UniversalClient
+	prefix        string
+	patternPrefix string // prefix of the patterns of KEYS and SCAN
+	cmdsInfoCache *cmdsInfoCache
+}
+
+// escapeGlob escapes the special characters of the glob-style patterns of
+// KEYS and SCAN in s.
+func escapeGlob(s string) string {
+	if !strings.ContainsAny(s, `*?[]\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Do creates a Cmd from the args, prefixes its keys and processes the cmd.
+func (c *keyPrefixClient) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(ctx, args...)
+	_ = c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process prefixes the keys of cmd and processes it with the wrapped client.
+// The args of cmd are restored once the command has been processed.
+func (c *keyPrefixClient) Process(ctx context.Context, cmd Cmder) error {
+	restore, err := c.prefixCmd(ctx, cmd)
+	if err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+
+	err = c.client.Process(ctx, cmd)
+	restore()
+	c.unprefixReply(cmd)
+	return err
+}
+
+func (c *keyPrefixClient) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.Pipeline().Pipelined(ctx, fn)
+}
+
+func (c *keyPrefixClient) Pipeline() Pipeliner {
+	return c.pipeline(c.client.Pipeline)
+}
+
+func (c *keyPrefixClient) TxPipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.TxPipeline().Pipelined(ctx, fn)
+}
+
+func (c *keyPrefixClient) TxPipeline() Pipeliner {
+	return c.pipeline(c.client.TxPipeline)
+}
+
+func (c *keyPrefixClient) pipeline(newPipeline func() Pipeliner) Pipeliner {
+	pipe := Pipeline{
+		exec: func(ctx context.Context, cmds []Cmder) error {
+			restores := make([]func(), 0, len(cmds))
+			defer func() {
+				for _, restore := range restores {
+					restore()
+				}
+			}()
+
+			inner := newPipeline()
+			for _, cmd := range cmds {
+				restore, err := c.prefixCmd(ctx, cmd)
+				if err != nil {
+					setCmdsErr(cmds, err)
+					return err
+				}
+				restores = append(restores, restore)
+				_ = inner.Process(ctx, cmd)
+			}
+
+			_, err := inner.Exec(ctx)
+			for _, cmd := range cmds {
+				c.unprefixReply(cmd)
+			}
+			return err
+		},
+	}
+	pipe.init()
+	return &pipe
+}
+
+// prefixCmd rewrites the keys of cmd in place and returns a func restoring the args.
+func (c *keyPrefixClient) prefixCmd(ctx context.Context, cmd Cmder) (func(), error) {
+	args := cmd.Args()
+
+	var pos, patterns []int
+	switch cmd.Name() {
+	case "keys":
+		patterns = []int{1}
+	case "scan":
+		patterns = matchPatternPos(cmd)
+		if patterns == nil {
+			// Without MATCH the scan would return the keys of other tenants.
+			cmd, ok := cmd.(interface{ setArgs([]interface{}) })
+			if !ok || len(args) < 2 {
+				return nil, errors.New("redis: can't restrict SCAN to the keys of the prefix")
+			}
+			cmd.setArgs(append(args[:len(args):len(args)], "match", c.patternPrefix+"*"))
+			return func() { cmd.setArgs(args) }, nil
+		}
+	case "randomkey":
+		return nil, errors.New("redis: RANDOMKEY may return the keys of other prefixes")
+	default:
+		var err error
+		if pos, err = keyPositions(ctx, c.cmdsInfoCache, cmd); err != nil {
+			return nil, err
+		}
+	}
+
+	saved := make([]interface{}, len(args))
+	copy(saved, args)
+	for _, i := range pos {
+		if i > 0 && i < len(args) {
+			args[i] = prefixArg(c.prefix, args[i])
+		}
+	}
+	for _, i := range patterns {
+		if i > 0 && i < len(args) {
+			args[i] = prefixArg(c.patternPrefix, args[i])
+		}
+	}
+	return func() { copy(args, saved) }, nil
+}
+
+func prefixArg(prefix string, arg interface{}) string {
+	switch arg := arg.(type) {
+	case string:
+		return prefix + arg
+	case []byte:
+		return prefix + string(arg)
+	default:
+		return prefix + fmt.Sprint(arg)
+	}
+}
+
+func matchPatternPos(cmd Cmder) []int {
+	for i := 2; i < len(cmd.Args())-1; i++ {
+		if internal.ToLower(cmd.stringArg(i)) == "match" {
+			return []int{i + 1}
+		}
+	}
+	return nil
+}
+
+// keyPositions returns the indexes of the keys in the args of cmd, located
+// with the key specs of cmdsInfoCache for the commands with fixed positions.
+func keyPositions(ctx context.Context, cmdsInfoCache *cmdsInfoCache, cmd Cmder) ([]int, error) {
+	args := cmd.Args()
+	name := cmd.Name()
+
+	switch name {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro", "blmpop", "bzmpop":
+		return numKeysPositions(cmd, 2), nil
+	case "zunion", "zinter", "zdiff", "zintercard", "sintercard", "lmpop", "zmpop":
+		return numKeysPositions(cmd, 1), nil
+	case "zunionstore", "zinterstore", "zdiffstore":
+		return append([]int{1}, numKeysPositions(cmd, 2)...), nil
+	case "xread", "xreadgroup":
+		for i := 1; i < len(args); i++ {
+			if internal.ToLower(cmd.stringArg(i)) == "streams" {
+				n := (len(args) - i - 1) / 2
+				return keyRange(i+1, i+n), nil
+			}
+		}
+		return nil, nil
+	case "sort", "sort_ro":
+		pos := []int{1}
+		for i := 2; i < len(args)-1; i++ {
+			switch internal.ToLower(cmd.stringArg(i)) {
+			case "store":
+				pos = append(pos, i+1)
+			case "by":
+				if internal.ToLower(cmd.stringArg(i+1)) != "nosort" {
+					pos = append(pos, i+1)
+				}
+			case "get":
+				if cmd.stringArg(i+1) != "#" {
+					pos = append(pos, i+1)
+				}
+			case "limit":
+				i++
+			default:
+				continue
+			}
+			i++
+		}
+		return pos, nil
+	case "georadius", "georadiusbymember":
+		pos := []int{1}
+		for i := 2; i < len(args)-1; i++ {
+			switch internal.ToLower(cmd.stringArg(i)) {
+			case "store", "storedist":
+				pos = append(pos, i+1)
+				i++
+			}
+		}
+		return pos, nil
+	case "migrate":
+		if cmd.stringArg(3) != "" {
+			return []int{3}, nil
+		}
+		for i := 6; i < len(args); i++ {
+			if internal.ToLower(cmd.stringArg(i)) == "keys" {
+				return keyRange(i+1, len(args)-1), nil
+			}
+		}
+		return nil, nil
+	case "object", "xinfo", "xgroup":
+		if len(args) > 2 && internal.ToLower(cmd.stringArg(1)) != "help" {
+			return []int{2}, nil
+		}
+		return nil, nil
+	case "memory":
+		if internal.ToLower(cmd.stringArg(1)) == "usage" {
+			return []int{2}, nil
+		}
+		return nil, nil
+	}
+
+	cmdsInfo, err := cmdsInfoCache.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := cmdsInfo[name]
+	if info == nil {
+		return nil, fmt.Errorf("redis: can't locate the keys of unknown command %q", name)
+	}
+	if info.FirstKeyPos <= 0 {
+		for _, flag := range info.Flags {
+			if flag == "movablekeys" {
+				return nil, fmt.Errorf("redis: can't locate the keys of %q", name)
+			}
+		}
+		return nil, nil
+	}
+
+	last := int(info.LastKeyPos)
+	if last < 0 {
+		last += len(args)
+	}
+	step := int(info.StepCount)
+	if step < 1 {
+		step = 1
+	}
+
+	var pos []int
+	for i := int(info.FirstKeyPos); i <= last && i < len(args); i += step {
+		pos = append(pos, i)
+	}
+	return pos, nil
+}
+
+// numKeysPositions returns the positions of the keys following the numkeys argument at pos.
+func numKeysPositions(cmd Cmder, pos int) []int {
+	n, err := strconv.Atoi(cmd.stringArg(pos))
+	if err != nil {
+		return nil
+	}
+	return keyRange(pos+1, pos+n)
+}
+
+func keyRange(first, last int) []int {
+	if last < first {
+		return nil
+	}
+	pos := make([]int, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		pos = append(pos, i)
+	}
+	return pos
+}
+
+// unprefixReply strips the prefix from the key names returned by cmd.
+func (c *keyPrefixClient) unprefixReply(cmd Cmder) {
+	switch cmd := cmd.(type) {
+	case *StringSliceCmd:
+		switch cmd.Name() {
+		case "keys":
+			for i := range cmd.val {
+				cmd.val[i] = c.unprefix(cmd.val[i])
+			}
+		case "blpop", "brpop":
+			if len(cmd.val) > 0 {
+				cmd.val[0] = c.unprefix(cmd.val[0])
+			}
+		}
+	case *ScanCmd:
+		if cmd.Name() == "scan" {
+			for i := range cmd.page {
+				cmd.page[i] = c.unprefix(cmd.page[i])
+			}
+		}
+	case *Cmd:
+		// the commands of Do
+		switch cmd.Name() {
+		case "keys":
+			c.unprefixKeys(cmd.val)
+		case "scan":
+			if page, ok := cmd.val.([]interface{}); ok && len(page) == 2 {
+				c.unprefixKeys(page[1])
+			}
+		}
+	case *ZWithKeyCmd:
+		if cmd.val != nil {
+			cmd.val.Key = c.unprefix(cmd.val.Key)
+		}
+	case *KeyValuesCmd:
+		cmd.key = c.unprefix(cmd.key)
+	case *ZSliceWithKeyCmd:
+		cmd.key = c.unprefix(cmd.key)
+	case *XStreamSliceCmd:
+		for i := range cmd.val {
+			cmd.val[i].Stream = c.unprefix(cmd.val[i].Stream)
+		}
+	}
+}
+
+func (c *keyPrefixClient) unprefixKeys(val interface{}) {
+	keys, _ := val.([]interface{})
+	for i, key := range keys {
+		if key, ok := key.(string); ok {
+			keys[i] = c.unprefix(key)
+		}
+	}
+}
+
+func (c *keyPrefixClient) unprefix(key string) string {
+	return strings.TrimPrefix(key, c.prefix)
+}
@@ -0,0 +1,167 @@
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+var _ = Describe("WithKeyPrefix", func() {
+	var client *redis.Client
+	var tenant redis.Cmdable
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		tenant = redis.WithKeyPrefix(client, "tenant:42:")
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should prefix keys", func() {
+		Expect(tenant.Set(ctx, "key", "hello", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "tenant:42:key").Val()).To(Equal("hello"))
+		Expect(tenant.Get(ctx, "key").Val()).To(Equal("hello"))
+
+		Expect(tenant.MSet(ctx, "k1", "v1", "k2", "v2").Err()).NotTo(HaveOccurred())
+		Expect(client.MGet(ctx, "tenant:42:k1", "tenant:42:k2").Val()).To(Equal([]interface{}{"v1", "v2"}))
+
+		get := tenant.Get(ctx, "key")
+		Expect(get.Args()).To(Equal([]interface{}{"get", "key"}))
+	})
+
+	It("should prefix keys of commands with numkeys", func() {
+		Expect(tenant.ZAdd(ctx, "z1", redis.Z{Score: 1, Member: "one"}).Err()).NotTo(HaveOccurred())
+		Expect(tenant.ZAdd(ctx, "z2", redis.Z{Score: 2, Member: "two"}).Err()).NotTo(HaveOccurred())
+		Expect(tenant.ZUnionStore(ctx, "out", &redis.ZStore{Keys: []string{"z1", "z2"}}).Val()).To(Equal(int64(2)))
+		Expect(client.ZCard(ctx, "tenant:42:out").Val()).To(Equal(int64(2)))
+
+		val, err := tenant.Eval(ctx, "return redis.call('get', KEYS[1])", []string{"key"}).Result()
+		Expect(err).To(Equal(redis.Nil))
+		Expect(val).To(BeNil())
+		Expect(client.Set(ctx, "tenant:42:key", "hello", 0).Err()).NotTo(HaveOccurred())
+		Expect(tenant.Eval(ctx, "return redis.call('get', KEYS[1])", []string{"key"}).Val()).To(Equal("hello"))
+	})
+
+	It("should strip the prefix from KEYS and SCAN", func() {
+		Expect(client.Set(ctx, "other", "x", 0).Err()).NotTo(HaveOccurred())
+		for i := 0; i < 20; i++ {
+			Expect(tenant.Set(ctx, fmt.Sprintf("k%02d", i), "x", 0).Err()).NotTo(HaveOccurred())
+		}
+
+		keys := tenant.Keys(ctx, "k0*").Val()
+		Expect(keys).To(HaveLen(10))
+		Expect(keys).To(ContainElement("k05"))
+
+		var scanned []string
+		iter := tenant.Scan(ctx, 0, "", 5).Iterator()
+		for iter.Next(ctx) {
+			scanned = append(scanned, iter.Val())
+		}
+		Expect(iter.Err()).NotTo(HaveOccurred())
+		Expect(scanned).To(HaveLen(20))
+		Expect(scanned).NotTo(ContainElement("other"))
+
+		Expect(tenant.RandomKey(ctx).Err()).To(HaveOccurred())
+	})
+
+	It("should strip the prefix from blocking pops and streams", func() {
+		Expect(tenant.RPush(ctx, "list", "a").Err()).NotTo(HaveOccurred())
+		Expect(tenant.BLPop(ctx, 0, "list").Val()).To(Equal([]string{"list", "a"}))
+
+		Expect(tenant.XAdd(ctx, &redis.XAddArgs{Stream: "stream", ID: "1-0", Values: []string{"f", "v"}}).Err()).NotTo(HaveOccurred())
+		streams, err := tenant.XRead(ctx, &redis.XReadArgs{Streams: []string{"stream", "0"}}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(streams).To(HaveLen(1))
+		Expect(streams[0].Stream).To(Equal("stream"))
+	})
+
+	It("should prefix keys in pipelines", func() {
+		cmds, err := tenant.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "key", "hello", 0)
+			pipe.Incr(ctx, "counter")
+			pipe.Keys(ctx, "*")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds).To(HaveLen(3))
+		Expect(cmds[2].(*redis.StringSliceCmd).Val()).To(ConsistOf("key", "counter"))
+		Expect(client.Get(ctx, "tenant:42:counter").Val()).To(Equal("1"))
+
+		_, err = tenant.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Incr(ctx, "counter")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "tenant:42:counter").Val()).To(Equal("2"))
+	})
+})
+
+func TestKeyPrefixPatterns(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+
+	// the keys of the other tenant match the prefix as a pattern
+	for _, key := range []string{"t[1]*:a", "t[1]*:b", "t1x:c", "other"} {
+		if err := client.Set(ctx, key, "x", 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tenant := redis.WithKeyPrefix(client, "t[1]*:")
+	want := []string{"a", "b"}
+
+	keys, err := tenant.Keys(ctx, "*").Result()
+	if sort.Strings(keys); err != nil || !reflect.DeepEqual(keys, want) {
+		t.Fatalf("got %q, %v from KEYS, wanted %q", keys, err, want)
+	}
+
+	var scanned []string
+	iter := tenant.Scan(ctx, 0, "", 10).Iterator()
+	for iter.Next(ctx) {
+		scanned = append(scanned, iter.Val())
+	}
+	if sort.Strings(scanned); iter.Err() != nil || !reflect.DeepEqual(scanned, want) {
+		t.Fatalf("got %q, %v from SCAN, wanted %q", scanned, iter.Err(), want)
+	}
+
+	// a SCAN without MATCH of Do
+	do := tenant.(interface {
+		Do(ctx context.Context, args ...interface{}) *redis.Cmd
+	}).Do
+	scanned = nil
+	for cursor := int64(0); ; {
+		page, err := do(ctx, "scan", cursor).Slice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range page[1].([]interface{}) {
+			scanned = append(scanned, key.(string))
+		}
+		if cursor, _ = strconv.ParseInt(page[0].(string), 10, 64); cursor == 0 {
+			break
+		}
+	}
+	if sort.Strings(scanned); !reflect.DeepEqual(scanned, want) {
+		t.Fatalf("got %q from the SCAN of Do, wanted %q", scanned, want)
+	}
+
+	if err := do(ctx, "scan").Err(); err == nil {
+		t.Fatal("SCAN without cursor succeeded")
+	}
+	if err := tenant.RandomKey(ctx).Err(); err == nil {
+		t.Fatal("RANDOMKEY succeeded")
+	}
+}
@@ -0,0 +1,370 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// keysArchiveMagic starts the archives of ExportKeys, followed by the version
+// of the format.
+const keysArchiveMagic = "GOREDIS-KEYS"
+
+const keysArchiveVersion = 1
+
+type ExportKeysOptions struct {
+	// Match is the SCAN pattern of the exported keys. Default is all the keys.
+	Match string
+	// Type only exports the keys of this type, e.g. "hash". Default is all
+	// the types.
+	Type string
+	// BatchSize is the COUNT of SCAN and the number of keys dumped per
+	// pipeline. Default is 100.
+	BatchSize int
+}
+
+type ImportKeysOptions struct {
+	// Replace overwrites the existing keys. By default they are skipped.
+	Replace bool
+	// BatchSize is the number of keys restored per pipeline. Default is 100.
+	BatchSize int
+}
+
+// KeysArchiveStats are the statistics of ExportKeys and ImportKeys.
+type KeysArchiveStats struct {
+	// Keys is the number of exported or imported keys.
+	Keys int64
+	// Skipped is the number of keys deleted before being exported, or existing
+	// or expired when imported.
+	Skipped int64
+	// Failed is the number of keys that failed to be exported or imported.
+	Failed int64
+	// LastError is the last error exporting or importing a key.
+	LastError error
+}
+
+// KeyRecord is a key of an archive.
+type KeyRecord struct {
+	Key  string
+	Type string
+	// ExpireAt is the expiration time of the key, or the zero time.
+	ExpireAt time.Time
+	// Payload is the DUMP of the key.
+	Payload []byte
+}
+
+// ExportKeys writes the keys of client matching opt to w, with their type,
+// expiration time and DUMP payload, for ImportKeys. client can be a Client, a
+// ClusterClient or a Ring: the keys of every master of a cluster, or every
+// shard of a ring, are exported concurrently. The keys modified during the
+// export may be exported before or after their modification.
+//
+// The DUMP payloads can only be restored by servers of the same or a newer
+// version.
+//
+// It returns an error when scanning the keys or writing w fails, and counts
+// the keys it failed to dump in KeysArchiveStats.Failed.
+func ExportKeys(ctx context.Context, client UniversalClient, w io.Writer, opt *ExportKeysOptions) (*KeysArchiveStats, error) {
+	var o ExportKeysOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+
+	aw, err := NewKeyArchiveWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	stats := new(KeysArchiveStats)
+	var mu sync.Mutex
+
+	err = forEachScanNode(ctx, client, func(ctx context.Context, node *Client) error {
+		return scanKeys(ctx, node, o.Match, o.Type, o.BatchSize, func(keys []string) error {
+			types := make([]*StatusCmd, len(keys))
+			ttls := make([]*DurationCmd, len(keys))
+			dumps := make([]*StringCmd, len(keys))
+			_, _ = node.Pipelined(ctx, func(pipe Pipeliner) error {
+				for i, key := range keys {
+					types[i] = pipe.Type(ctx, key)
+					ttls[i] = pipe.PTTL(ctx, key)
+					dumps[i] = pipe.Dump(ctx, key)
+				}
+				return nil
+			})
+			now := time.Now()
+
+			mu.Lock()
+			defer mu.Unlock()
+			for i, key := range keys {
+				rec, err := dumpedRecord(key, types[i], ttls[i], dumps[i], now)
+				if err == Nil {
+					stats.Skipped++
+					continue
+				}
+				if err != nil {
+					stats.Failed++
+					stats.LastError = err
+					continue
+				}
+				if err := aw.Write(rec); err != nil {
+					return err
+				}
+				stats.Keys++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return stats, err
+	}
+	return stats, aw.Flush()
+}
+
+func dumpedRecord(key string, typ *StatusCmd, ttl *DurationCmd, dump *StringCmd, now time.Time) (*KeyRecord, error) {
+	payload, err := dump.Result()
+	if err != nil {
+		return nil, err
+	}
+	t, err := typ.Result()
+	if err != nil {
+		return nil, err
+	}
+	rec := &KeyRecord{
+		Key:     key,
+		Type:    t,
+		Payload: []byte(payload),
+	}
+	d, err := ttl.Result()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case d == -2:
+		// expired between DUMP and PTTL
+		return nil, Nil
+	case d >= 0:
+		rec.ExpireAt = now.Add(d)
+	}
+	return rec, nil
+}
+
+// ImportKeys restores the keys of the archive r written by ExportKeys into
+// client, which can be a Client, a ClusterClient or a Ring. The expired keys
+// are skipped.
+//
+// It returns an error when reading r fails, and counts the keys it failed to
+// restore in KeysArchiveStats.Failed.
+func ImportKeys(ctx context.Context, client UniversalClient, r io.Reader, opt *ImportKeysOptions) (*KeysArchiveStats, error) {
+	var o ImportKeysOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+
+	ar, err := NewKeyArchiveReader(r)
+	if err != nil {
+		return nil, err
+	}
+	stats := new(KeysArchiveStats)
+	batch := make([]*KeyRecord, 0, o.BatchSize)
+	for {
+		rec, err := ar.Next()
+		if err != nil && err != io.EOF {
+			return stats, err
+		}
+		if rec != nil {
+			batch = append(batch, rec)
+		}
+		if len(batch) == o.BatchSize || (err == io.EOF && len(batch) > 0) {
+			restoreRecords(ctx, client, batch, o.Replace, stats)
+			batch = batch[:0]
+		}
+		if err == io.EOF {
+			return stats, nil
+		}
+	}
+}
+
+func restoreRecords(ctx context.Context, client UniversalClient, recs []*KeyRecord, replace bool, stats *KeysArchiveStats) {
+	cmds := make([]*StatusCmd, len(recs))
+	_, _ = client.Pipelined(ctx, func(pipe Pipeliner) error {
+		now := time.Now()
+		for i, rec := range recs {
+			var ttl time.Duration
+			if !rec.ExpireAt.IsZero() {
+				ttl = rec.ExpireAt.Sub(now)
+				if ttl <= 0 {
+					continue
+				}
+			}
+			if replace {
+				cmds[i] = pipe.RestoreReplace(ctx, rec.Key, ttl, string(rec.Payload))
+			} else {
+				cmds[i] = pipe.Restore(ctx, rec.Key, ttl, string(rec.Payload))
+			}
+		}
+		return nil
+	})
+
+	for _, cmd := range cmds {
+		if cmd == nil {
+			stats.Skipped++
+			continue
+		}
+		err := cmd.Err()
+		switch {
+		case err == nil:
+			stats.Keys++
+		case HasErrorPrefix(err, "BUSYKEY"):
+			stats.Skipped++
+		default:
+			stats.Failed++
+			stats.LastError = err
+		}
+	}
+}
+
+// KeyArchiveWriter writes the archives of ExportKeys. The archive starts with
+// a header, followed by the records: the key, type, expiration time in Unix
+// milliseconds, or 0, and DUMP payload, each prefixed with its length or
+// encoded as varints.
+type KeyArchiveWriter struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+// NewKeyArchiveWriter writes the header of an archive to w and returns its
+// writer.
+func NewKeyArchiveWriter(w io.Writer) (*KeyArchiveWriter, error) {
+	aw := &KeyArchiveWriter{w: bufio.NewWriter(w)}
+	if _, err := aw.w.WriteString(keysArchiveMagic); err != nil {
+		return nil, err
+	}
+	if err := aw.w.WriteByte(keysArchiveVersion); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write writes rec to the archive.
+func (aw *KeyArchiveWriter) Write(rec *KeyRecord) error {
+	var expireAt int64
+	if !rec.ExpireAt.IsZero() {
+		expireAt = rec.ExpireAt.UnixMilli()
+	}
+
+	b := aw.buf[:0]
+	b = appendBytes(b, []byte(rec.Key))
+	b = appendBytes(b, []byte(rec.Type))
+	b = appendVarint(b, expireAt)
+	b = appendBytes(b, rec.Payload)
+	aw.buf = b
+
+	_, err := aw.w.Write(b)
+	return err
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (aw *KeyArchiveWriter) Flush() error {
+	return aw.w.Flush()
+}
+
+func appendBytes(b, s []byte) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(s)))
+	b = append(b, buf[:n]...)
+	return append(b, s...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// KeyArchiveReader reads the archives of ExportKeys.
+type KeyArchiveReader struct {
+	r *bufio.Reader
+}
+
+// NewKeyArchiveReader reads the header of the archive r and returns its
+// reader.
+func NewKeyArchiveReader(r io.Reader) (*KeyArchiveReader, error) {
+	ar := &KeyArchiveReader{r: bufio.NewReader(r)}
+	header := make([]byte, len(keysArchiveMagic)+1)
+	if _, err := io.ReadFull(ar.r, header); err != nil {
+		return nil, fmt.Errorf("redis: invalid keys archive: %w", err)
+	}
+	if string(header[:len(keysArchiveMagic)]) != keysArchiveMagic {
+		return nil, errors.New("redis: invalid keys archive")
+	}
+	if v := header[len(keysArchiveMagic)]; v != keysArchiveVersion {
+		return nil, fmt.Errorf("redis: unsupported keys archive version %d", v)
+	}
+	return ar, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the archive.
+func (ar *KeyArchiveReader) Next() (*KeyRecord, error) {
+	if _, err := ar.r.Peek(1); err == io.EOF {
+		return nil, io.EOF
+	}
+
+	key, err := ar.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	typ, err := ar.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	expireAt, err := binary.ReadVarint(ar.r)
+	if err != nil {
+		return nil, ar.unexpected(err)
+	}
+	payload, err := ar.readBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &KeyRecord{
+		Key:     string(key),
+		Type:    string(typ),
+		Payload: payload,
+	}
+	if expireAt != 0 {
+		rec.ExpireAt = time.UnixMilli(expireAt)
+	}
+	return rec, nil
+}
+
+func (ar *KeyArchiveReader) readBytes() ([]byte, error) {
+	n, err := binary.ReadUvarint(ar.r)
+	if err != nil {
+		return nil, ar.unexpected(err)
+	}
+	if n > 512<<20 {
+		return nil, fmt.Errorf("redis: invalid keys archive record of %d bytes", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(ar.r, b); err != nil {
+		return nil, ar.unexpected(err)
+	}
+	return b, nil
+}
+
+func (ar *KeyArchiveReader) unexpected(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("redis: invalid keys archive: %w", err)
+}
@@ -0,0 +1,74 @@
+package redis_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("ExportKeys and ImportKeys", func() {
+	var src, dst *redis.Client
+
+	BeforeEach(func() {
+		src = redis.NewClient(redisOptions())
+		Expect(src.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		opt := redisOptions()
+		opt.DB = 14
+		dst = redis.NewClient(opt)
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		Expect(src.Set(ctx, "user:1", "one", 0).Err()).NotTo(HaveOccurred())
+		Expect(src.Set(ctx, "user:2", "two", time.Hour).Err()).NotTo(HaveOccurred())
+		Expect(src.HSet(ctx, "user:3", "name", "three").Err()).NotTo(HaveOccurred())
+		Expect(src.Set(ctx, "other", "value", 0).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(dst.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+		Expect(src.Close()).NotTo(HaveOccurred())
+		Expect(dst.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should export and import the keys", func() {
+		var buf bytes.Buffer
+		stats, err := redis.ExportKeys(ctx, src, &buf, &redis.ExportKeysOptions{Match: "user:*"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Keys).To(Equal(int64(3)))
+
+		rd, err := redis.NewKeyArchiveReader(bytes.NewReader(buf.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		types := make(map[string]string)
+		for {
+			rec, err := rd.Next()
+			if err != nil {
+				break
+			}
+			types[rec.Key] = rec.Type
+		}
+		Expect(types).To(Equal(map[string]string{"user:1": "string", "user:2": "string", "user:3": "hash"}))
+
+		Expect(dst.Set(ctx, "user:1", "old", 0).Err()).NotTo(HaveOccurred())
+		stats, err = redis.ImportKeys(ctx, dst, bytes.NewReader(buf.Bytes()), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Keys).To(Equal(int64(2)))
+		Expect(stats.Skipped).To(Equal(int64(1)))
+		Expect(dst.Get(ctx, "user:1").Val()).To(Equal("old"))
+		Expect(dst.TTL(ctx, "user:2").Val()).To(BeNumerically("~", time.Hour, time.Minute))
+		Expect(dst.HGet(ctx, "user:3", "name").Val()).To(Equal("three"))
+
+		stats, err = redis.ImportKeys(ctx, dst, bytes.NewReader(buf.Bytes()), &redis.ImportKeysOptions{Replace: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.Keys).To(Equal(int64(3)))
+		Expect(dst.Get(ctx, "user:1").Val()).To(Equal("one"))
+	})
+
+	It("should reject invalid archives", func() {
+		_, err := redis.ImportKeys(ctx, dst, bytes.NewReader([]byte("not an archive")), nil)
+		Expect(err).To(MatchError("redis: invalid keys archive"))
+	})
+})
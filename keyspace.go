@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeyEvent is a keyspace notification: the operation Op, e.g. "set", "del" or
+// "expired", on the key Key of the database DB.
+type KeyEvent struct {
+	Key string
+	Op  string
+	DB  int
+}
+
+// KeyEventsOptions are the options of Client.SubscribeKeyEvents.
+type KeyEventsOptions struct {
+	// Events, if set, are the notify-keyspace-events flags set with CONFIG
+	// SET before subscribing, e.g. "KEA" for every event or "Kx" for the
+	// expired keys. "K" is added when missing. Default is to keep the
+	// configuration of the server, where the notifications are disabled by
+	// default.
+	Events string
+	// Pattern is the glob-style pattern of the keys. Default is "*".
+	Pattern string
+	// AllDBs subscribes to the events of all the databases instead of the
+	// database of the client.
+	AllDBs bool
+	// ChannelSize is the size of the channel of the events. Default is 100.
+	ChannelSize int
+}
+
+// KeyEvents are the keyspace notifications subscribed with
+// Client.SubscribeKeyEvents.
+type KeyEvents struct {
+	pubsub *PubSub
+	ch     chan KeyEvent
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// SubscribeKeyEvents subscribes to the keyspace notifications of the keys of
+// opt.Pattern, and delivers them as KeyEvents. The subscription is restored
+// when its connection is lost, but the events sent in the meantime are lost.
+//
+// The notifications are sent by each server for its own keys: with a cluster,
+// each master must be subscribed to, e.g. with ClusterClient.ForEachMaster.
+func (c *Client) SubscribeKeyEvents(ctx context.Context, opt *KeyEventsOptions) (*KeyEvents, error) {
+	if opt == nil {
+		opt = &KeyEventsOptions{}
+	}
+	if opt.Events != "" {
+		if err := c.ConfigSet(ctx, "notify-keyspace-events", keyspaceEventsFlags(opt.Events)).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	pubsub := c.PSubscribe(ctx)
+	if err := pubsub.PSubscribe(ctx, keyspaceChannel(c.opt.DB, opt)); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	size := opt.ChannelSize
+	if size <= 0 {
+		size = 100
+	}
+	e := &KeyEvents{
+		pubsub: pubsub,
+		ch:     make(chan KeyEvent, size),
+		done:   make(chan struct{}),
+	}
+	go e.run(size)
+	return e, nil
+}
+
+func (e *KeyEvents) run(size int) {
+	defer close(e.ch)
+	for msg := range e.pubsub.Channel(WithChannelSize(size)) {
+		ev, ok := parseKeyEvent(msg.Channel, msg.Payload)
+		if !ok {
+			continue
+		}
+		select {
+		case e.ch <- ev:
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// Channel returns the channel of the events, closed by Close.
+func (e *KeyEvents) Channel() <-chan KeyEvent {
+	return e.ch
+}
+
+// Close unsubscribes from the notifications and closes the channel of the
+// events.
+func (e *KeyEvents) Close() error {
+	e.closeOnce.Do(func() { close(e.done) })
+	return e.pubsub.Close()
+}
+
+// keyspaceEventsFlags returns the notify-keyspace-events flags events, with
+// the flag of the keyspace notifications.
+func keyspaceEventsFlags(events string) string {
+	if !strings.Contains(events, "K") {
+		events = "K" + events
+	}
+	return events
+}
+
+func keyspaceChannel(db int, opt *KeyEventsOptions) string {
+	pattern := opt.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	dbs := "*"
+	if !opt.AllDBs {
+		dbs = strconv.Itoa(db)
+	}
+	return "__keyspace@" + dbs + "__:" + pattern
+}
+
+// parseKeyEvent parses the keyspace notification of channel
+// "__keyspace@<db>__:<key>" with the operation as payload.
+func parseKeyEvent(channel, payload string) (KeyEvent, bool) {
+	const prefix = "__keyspace@"
+	if !strings.HasPrefix(channel, prefix) {
+		return KeyEvent{}, false
+	}
+	s := channel[len(prefix):]
+	i := strings.Index(s, "__:")
+	if i < 0 {
+		return KeyEvent{}, false
+	}
+	db, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return KeyEvent{}, false
+	}
+	return KeyEvent{
+		Key: s[i+len("__:"):],
+		Op:  payload,
+		DB:  db,
+	}, true
+}
@@ -0,0 +1,39 @@
+package redis
+
+import "testing"
+
+func TestParseKeyEvent(t *testing.T) {
+	tests := []struct {
+		channel, payload string
+		want             KeyEvent
+		ok               bool
+	}{
+		{"__keyspace@0__:user:1", "set", KeyEvent{Key: "user:1", Op: "set", DB: 0}, true},
+		{"__keyspace@12__:a__:b", "expired", KeyEvent{Key: "a__:b", Op: "expired", DB: 12}, true},
+		{"__keyspace@0__:", "del", KeyEvent{Key: "", Op: "del", DB: 0}, true},
+		{"__keyevent@0__:set", "user:1", KeyEvent{}, false},
+		{"__keyspace@x__:key", "set", KeyEvent{}, false},
+		{"news", "hello", KeyEvent{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseKeyEvent(test.channel, test.payload)
+		if ok != test.ok || got != test.want {
+			t.Errorf("parseKeyEvent(%q, %q) = %+v, %t, wanted %+v, %t", test.channel, test.payload, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestKeyspaceChannel(t *testing.T) {
+	if ch := keyspaceChannel(3, &KeyEventsOptions{}); ch != "__keyspace@3__:*" {
+		t.Fatalf("got %q", ch)
+	}
+	if ch := keyspaceChannel(3, &KeyEventsOptions{Pattern: "user:*", AllDBs: true}); ch != "__keyspace@*__:user:*" {
+		t.Fatalf("got %q", ch)
+	}
+	if flags := keyspaceEventsFlags("Ex"); flags != "KEx" {
+		t.Fatalf("got flags %q", flags)
+	}
+	if flags := keyspaceEventsFlags("KEA"); flags != "KEA" {
+		t.Fatalf("got flags %q", flags)
+	}
+}
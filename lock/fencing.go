@@ -0,0 +1,142 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrStaleFence is returned by Fence.Validate when a larger fencing token was
+// already validated.
+var ErrStaleFence = errors.New("lock: stale fencing token")
+
+var (
+	// fenceObtainScript obtains the owner key and returns the next fencing
+	// token, or the current one when the lock is already held with the token.
+	//
+	// KEYS: owner, fencing counter.
+	// ARGV: token, TTL in milliseconds.
+	fenceObtainScript = redis.NewScript(`
+if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("incr", KEYS[2])
+end
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+	return tonumber(redis.call("get", KEYS[2]))
+end
+return 0
+`)
+	// fenceValidateScript accepts a fencing token no smaller than the largest
+	// one accepted before.
+	//
+	// KEYS: largest accepted token.
+	// ARGV: fencing token.
+	fenceValidateScript = redis.NewScript(`
+local fence = tonumber(ARGV[1])
+if fence < tonumber(redis.call("get", KEYS[1]) or "0") then
+	return 0
+end
+redis.call("set", KEYS[1], fence)
+return 1
+`)
+)
+
+// Fence is a lock issuing fencing tokens: each holder gets a token larger
+// than the tokens of the previous holders. A holder passes its token along
+// with its side effects, which are rejected by Validate once a later holder
+// used a larger token, so a holder that lost the lock without noticing, e.g.
+// after a long GC pause, cannot overwrite the work of its successor.
+//
+// The counter of the tokens never expires.
+type Fence struct {
+	client *Client
+	name   string
+
+	ownerKey   string
+	counterKey string
+	highKey    string
+}
+
+// NewFence returns the fencing lock name of client.
+func NewFence(client redis.Scripter, name string) *Fence {
+	prefix := hashTagged(name)
+	return &Fence{
+		client:     New(client),
+		name:       name,
+		ownerKey:   prefix + ":owner",
+		counterKey: prefix + ":fence",
+		highKey:    prefix + ":fence:accepted",
+	}
+}
+
+// FencedLock is a lock obtained with a fencing token.
+type FencedLock struct {
+	*Lock
+	// Fence is the fencing token of the holder.
+	Fence int64
+}
+
+// Obtain obtains the lock for ttl with a new fencing token, see Client.Obtain.
+func (f *Fence) Obtain(ctx context.Context, ttl time.Duration, opt *Options) (*FencedLock, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	token := opt.Token
+	if token == "" {
+		var err error
+		if token, err = newToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	lk := &FencedLock{
+		Lock: &Lock{
+			client:  f.client,
+			key:     f.name,
+			keys:    []string{f.ownerKey},
+			scripts: keyScripts,
+			token:   token,
+			ttl:     ttl,
+		},
+	}
+	client := f.client.clients[0]
+	keys := []string{f.ownerKey, f.counterKey}
+	err := retryObtain(ctx, opt.Retry, nil, func(ctx context.Context) (bool, error) {
+		start := time.Now()
+		fence, err := fenceObtainScript.Run(ctx, client, keys, token, ttl.Milliseconds()).Int64()
+		if err != nil {
+			return false, err
+		}
+		if fence == 0 {
+			return false, nil
+		}
+		lk.Fence = fence
+		lk.setValidity(start, ttl)
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.AutoExtend {
+		lk.watch()
+	}
+	return lk, nil
+}
+
+// Validate accepts fence unless a larger fencing token was accepted before,
+// in which case it returns ErrStaleFence. The side effects protected by the
+// lock are performed only once their fencing token was accepted.
+func (f *Fence) Validate(ctx context.Context, fence int64) error {
+	ok, err := runBool(ctx, fenceValidateScript, f.client.clients[0], []string{f.highKey}, strconv.FormatInt(fence, 10))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrStaleFence
+	}
+	return nil
+}
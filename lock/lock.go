@@ -0,0 +1,465 @@
+// Package lock implements distributed locks.
+//
+// A lock is obtained on a single Redis instance, or on several independent
+// instances with the Redlock algorithm, by setting its key to a random token
+// with a TTL. It is released or extended only by its holder, whose token is
+// checked atomically by Lua scripts:
+//
+//	lk, err := lock.Obtain(ctx, rdb, "my-lock", 10*time.Second, &lock.Options{
+//		Retry:      lock.LimitRetry(lock.LinearBackoff(100*time.Millisecond), 10),
+//		AutoExtend: true,
+//	})
+//	if err == lock.ErrNotObtained {
+//		// someone else holds the lock
+//	}
+//	defer lk.Release(ctx)
+//
+// RWMutex is a reader/writer lock, Semaphore a counting semaphore and Fence a
+// lock issuing fencing tokens, on a single instance.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal"
+)
+
+var (
+	// ErrNotObtained is returned by Obtain when the lock is held by someone else.
+	ErrNotObtained = errors.New("lock: not obtained")
+	// ErrNotHeld is returned when the lock expired or was obtained by someone else.
+	ErrNotHeld = errors.New("lock: not held")
+)
+
+var (
+	obtainScript = redis.NewScript(`
+if redis.call("set", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+	return 2
+end
+return 0
+`)
+	releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+	extendScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+	pttlScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pttl", KEYS[1])
+end
+return -3
+`)
+)
+
+// lockScripts are the scripts releasing, extending and returning the TTL of
+// a lock. They take the keys of the lock and its token, followed by the TTL
+// in milliseconds for extendScript.
+type lockScripts struct {
+	release, extend, pttl *redis.Script
+}
+
+var keyScripts = &lockScripts{release: releaseScript, extend: extendScript, pttl: pttlScript}
+
+// RetryStrategy is the backoff between the attempts of Obtain.
+type RetryStrategy interface {
+	// NextBackoff returns the delay before the retry-th retry, starting at 1,
+	// or a negative duration to stop retrying.
+	NextBackoff(retry int) time.Duration
+}
+
+type retryFunc func(retry int) time.Duration
+
+func (f retryFunc) NextBackoff(retry int) time.Duration { return f(retry) }
+
+// NoRetry never retries.
+func NoRetry() RetryStrategy {
+	return retryFunc(func(int) time.Duration { return -1 })
+}
+
+// LinearBackoff retries every backoff.
+func LinearBackoff(backoff time.Duration) RetryStrategy {
+	return retryFunc(func(int) time.Duration { return backoff })
+}
+
+// ExponentialBackoff retries with a jittered backoff doubling from minBackoff
+// up to maxBackoff.
+func ExponentialBackoff(minBackoff, maxBackoff time.Duration) RetryStrategy {
+	return retryFunc(func(retry int) time.Duration {
+		return internal.RetryBackoff(retry-1, minBackoff, maxBackoff)
+	})
+}
+
+// LimitRetry limits strategy to maxRetries retries.
+func LimitRetry(strategy RetryStrategy, maxRetries int) RetryStrategy {
+	return retryFunc(func(retry int) time.Duration {
+		if retry > maxRetries {
+			return -1
+		}
+		return strategy.NextBackoff(retry)
+	})
+}
+
+type Options struct {
+	// Token identifies the holder of the lock. Default is a random token.
+	// Obtaining a lock already held with the same token extends it.
+	Token string
+	// Retry is the strategy retrying Obtain while the lock is held by someone
+	// else. Default is NoRetry. Obtain also stops retrying when ctx is done.
+	Retry RetryStrategy
+	// AutoExtend extends the lock to its TTL every third of its TTL until it
+	// is released, see Lock.Lost.
+	AutoExtend bool
+}
+
+// Client obtains the locks on one or several independent Redis instances.
+type Client struct {
+	clients []redis.Scripter
+	quorum  int
+}
+
+// New returns a client obtaining the locks on clients. With several clients,
+// the Redlock algorithm is used: a lock is obtained when it was set on a
+// majority of the clients, within its TTL. The errors of a minority of the
+// clients are tolerated: Obtain fails with an error only when too many
+// clients failed for the lock to be obtained.
+func New(clients ...redis.Scripter) *Client {
+	if len(clients) == 0 {
+		panic("lock: New() requires at least one client")
+	}
+	return &Client{
+		clients: clients,
+		quorum:  len(clients)/2 + 1,
+	}
+}
+
+// Obtain obtains the lock key of client for ttl, see Client.Obtain.
+func Obtain(ctx context.Context, client redis.Scripter, key string, ttl time.Duration, opt *Options) (*Lock, error) {
+	return New(client).Obtain(ctx, key, ttl, opt)
+}
+
+// Obtain obtains the lock key for ttl. It returns ErrNotObtained when the lock
+// is held by someone else after the retries of Options.Retry.
+func (c *Client) Obtain(ctx context.Context, key string, ttl time.Duration, opt *Options) (*Lock, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	token := opt.Token
+	if token == "" {
+		var err error
+		if token, err = newToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	lk := &Lock{
+		client:  c,
+		key:     key,
+		keys:    []string{key},
+		scripts: keyScripts,
+		token:   token,
+		ttl:     ttl,
+	}
+	if err := retryObtain(ctx, opt.Retry, nil, lk.obtain); err != nil {
+		return nil, err
+	}
+	if opt.AutoExtend {
+		lk.watch()
+	}
+	return lk, nil
+}
+
+// retryObtain calls obtain until it succeeds, following the retry strategy.
+// A message received on wake retries immediately.
+func retryObtain(
+	ctx context.Context, retry RetryStrategy, wake <-chan *redis.Message, obtain func(ctx context.Context) (bool, error),
+) error {
+	if retry == nil {
+		retry = NoRetry()
+	}
+	for attempt := 1; ; attempt++ {
+		ok, err := obtain(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		backoff := retry.NextBackoff(attempt)
+		if backoff < 0 {
+			return ErrNotObtained
+		}
+		if wake == nil {
+			if err := internal.Sleep(ctx, backoff); err != nil {
+				return err
+			}
+			continue
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-wake:
+			t.Stop()
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Lock is an obtained lock.
+type Lock struct {
+	client  *Client
+	key     string
+	keys    []string
+	scripts *lockScripts
+	token   string
+	ttl     time.Duration
+
+	mu         sync.Mutex
+	validUntil time.Time
+
+	stop     chan struct{}
+	lost     chan struct{}
+	lostOnce sync.Once
+	watching sync.WaitGroup
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Token returns the token of the holder of the lock.
+func (l *Lock) Token() string {
+	return l.token
+}
+
+// ValidUntil returns the time until which the lock is held, unless it is
+// extended.
+func (l *Lock) ValidUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.validUntil
+}
+
+// TTL returns the remaining TTL of the lock, or ErrNotHeld. With several
+// clients, it is the TTL on the first client holding the lock.
+func (l *Lock) TTL(ctx context.Context) (time.Duration, error) {
+	var firstErr error
+	for _, client := range l.client.clients {
+		ms, err := l.scripts.pttl.Run(ctx, client, l.keys, l.token).Int64()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if ms >= 0 {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return 0, ErrNotHeld
+}
+
+// Extend extends the lock to ttl. It returns ErrNotHeld when the lock expired
+// or was obtained by someone else.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	start := time.Now()
+	n, _, err := l.client.run(ctx, func(client redis.Scripter) (bool, error) {
+		return runBool(ctx, l.scripts.extend, client, l.keys, l.token, ttl.Milliseconds())
+	})
+	if n >= l.client.quorum {
+		l.setValidity(start, ttl)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrNotHeld
+}
+
+// Release releases the lock, stopping its automatic extension. It returns
+// ErrNotHeld when the lock expired or was obtained by someone else.
+func (l *Lock) Release(ctx context.Context) error {
+	if l.stop != nil {
+		select {
+		case <-l.stop:
+		default:
+			close(l.stop)
+		}
+		l.watching.Wait()
+	}
+
+	n, _, err := l.client.run(ctx, func(client redis.Scripter) (bool, error) {
+		return runBool(ctx, l.scripts.release, client, l.keys, l.token)
+	})
+	l.mu.Lock()
+	l.validUntil = time.Time{}
+	l.mu.Unlock()
+	if n >= l.client.quorum {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return ErrNotHeld
+}
+
+// Lost returns a channel closed when the lock, automatically extended, was
+// lost: it could not be extended before it expired. It is nil unless
+// Options.AutoExtend is set.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *Lock) obtain(ctx context.Context) (bool, error) {
+	start := time.Now()
+	var (
+		mu       sync.Mutex
+		acquired []redis.Scripter
+	)
+	n, failed, err := l.client.run(ctx, func(client redis.Scripter) (bool, error) {
+		// 1 when the lock was set, 2 when it was already held with the token
+		n, err := obtainScript.Run(ctx, client, l.keys, l.token, l.ttl.Milliseconds()).Int64()
+		if n == 1 {
+			mu.Lock()
+			acquired = append(acquired, client)
+			mu.Unlock()
+		}
+		return n == 1 || n == 2, err
+	})
+	if n >= l.client.quorum && l.setValidity(start, l.ttl) {
+		return true, nil
+	}
+	// do not keep a minority of the instances locked until the TTL expires,
+	// but only release the instances locked by this attempt: the lock may
+	// already be held with the token on the others
+	for _, client := range acquired {
+		_, _ = runBool(context.Background(), releaseScript, client, l.keys, l.token)
+	}
+	if len(l.client.clients)-failed >= l.client.quorum {
+		// the lock can still be obtained on the instances which did not fail:
+		// it is held by someone else, and Obtain retries
+		return false, nil
+	}
+	return false, err
+}
+
+// setValidity records the validity of the lock set with ttl at start, less the
+// clock drift of the instances. It reports whether the lock is still valid.
+func (l *Lock) setValidity(start time.Time, ttl time.Duration) bool {
+	validUntil := start.Add(ttl)
+	if len(l.client.clients) > 1 {
+		drift := ttl/100 + 2*time.Millisecond
+		validUntil = validUntil.Add(-drift)
+	}
+	l.mu.Lock()
+	l.validUntil = validUntil
+	l.mu.Unlock()
+	return time.Now().Before(validUntil)
+}
+
+func (l *Lock) watch() {
+	l.stop = make(chan struct{})
+	l.lost = make(chan struct{})
+	interval := l.ttl / 3
+
+	l.watching.Add(1)
+	go func() {
+		defer l.watching.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := l.Extend(ctx, l.ttl)
+			cancel()
+			if err == ErrNotHeld || (err != nil && !time.Now().Before(l.ValidUntil())) {
+				l.lostOnce.Do(func() { close(l.lost) })
+				return
+			}
+		}
+	}()
+}
+
+// run calls fn on the clients concurrently and returns the number of clients
+// fn succeeded on, the number of clients fn failed on with an error, and the
+// first error.
+func (c *Client) run(ctx context.Context, fn func(client redis.Scripter) (bool, error)) (int, int, error) {
+	if len(c.clients) == 1 {
+		ok, err := fn(c.clients[0])
+		if ok {
+			return 1, 0, nil
+		}
+		if err != nil {
+			return 0, 1, err
+		}
+		return 0, 0, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		n        int
+		failed   int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for _, client := range c.clients {
+		wg.Add(1)
+		go func(client redis.Scripter) {
+			defer wg.Done()
+			ok, err := fn(client)
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				n++
+			} else if err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}(client)
+	}
+	wg.Wait()
+	return n, failed, firstErr
+}
+
+func runBool(ctx context.Context, script *redis.Script, client redis.Scripter, keys []string, args ...interface{}) (bool, error) {
+	n, err := script.Run(ctx, client, keys, args...).Int64()
+	return n == 1, err
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
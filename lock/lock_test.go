@@ -0,0 +1,368 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeScripter runs the lock scripts on an in-memory map.
+type fakeScripter struct {
+	redis.Scripter
+
+	mu   sync.Mutex
+	vals map[string]string
+	exp  map[string]time.Time
+	err  error
+	// keys are the keys of the unknown scripts.
+	keys [][]string
+}
+
+func newFakeScripter() *fakeScripter {
+	return &fakeScripter{vals: make(map[string]string), exp: make(map[string]time.Time)}
+}
+
+func (s *fakeScripter) get(key string) (string, bool) {
+	if exp, ok := s.exp[key]; ok && !time.Now().Before(exp) {
+		delete(s.vals, key)
+		delete(s.exp, key)
+	}
+	v, ok := s.vals[key]
+	return v, ok
+}
+
+func (s *fakeScripter) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.vals, key)
+	delete(s.exp, key)
+}
+
+func (s *fakeScripter) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cmd := redis.NewCmd(ctx)
+	if s.err != nil {
+		cmd.SetErr(s.err)
+		return cmd
+	}
+	key, token := keys[0], args[0].(string)
+	v, ok := s.get(key)
+	held := ok && v == token
+	var n int64
+	switch sha1 {
+	case obtainScript.Hash():
+		if !ok || held {
+			s.vals[key] = token
+			s.exp[key] = time.Now().Add(time.Duration(args[1].(int64)) * time.Millisecond)
+			n = 1
+			if held {
+				n = 2
+			}
+		}
+	case releaseScript.Hash():
+		if held {
+			delete(s.vals, key)
+			delete(s.exp, key)
+			n = 1
+		}
+	case extendScript.Hash():
+		if held {
+			s.exp[key] = time.Now().Add(time.Duration(args[1].(int64)) * time.Millisecond)
+			n = 1
+		}
+	case pttlScript.Hash():
+		n = -3
+		if held {
+			n = time.Until(s.exp[key]).Milliseconds()
+		}
+	case fenceObtainScript.Hash():
+		counter, _ := strconv.ParseInt(s.vals[keys[1]], 10, 64)
+		if !ok {
+			counter++
+			s.vals[key] = token
+			s.vals[keys[1]] = strconv.FormatInt(counter, 10)
+		}
+		if !ok || held {
+			n = counter
+		}
+	case fenceValidateScript.Hash():
+		fence, _ := strconv.ParseInt(token, 10, 64)
+		high, _ := strconv.ParseInt(v, 10, 64)
+		if fence >= high {
+			s.vals[key] = token
+			n = 1
+		}
+	default:
+		s.keys = append(s.keys, keys)
+	}
+	cmd.SetVal(n)
+	return cmd
+}
+
+func TestObtainRelease(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeScripter()
+
+	lk, err := Obtain(ctx, client, "lock", time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Obtain(ctx, client, "lock", time.Minute, nil); err != ErrNotObtained {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if ttl, err := lk.TTL(ctx); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("got TTL %v, %v", ttl, err)
+	}
+	if err := lk.Extend(ctx, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := lk.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := lk.Release(ctx); err != ErrNotHeld {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+	if err := lk.Extend(ctx, time.Hour); err != ErrNotHeld {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+}
+
+func TestObtainRetry(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeScripter()
+
+	if _, err := Obtain(ctx, client, "lock", 50*time.Millisecond, nil); err != nil {
+		t.Fatal(err)
+	}
+	lk, err := Obtain(ctx, client, "lock", time.Minute, &Options{
+		Retry: LimitRetry(LinearBackoff(10*time.Millisecond), 20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lk.Release(ctx)
+
+	ctx2, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = Obtain(ctx2, client, "lock", time.Minute, &Options{Retry: LinearBackoff(5 * time.Millisecond)})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRedlockQuorum(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newFakeScripter(), newFakeScripter(), newFakeScripter()
+	locker := New(a, b, c)
+
+	c.err = errors.New("connection refused")
+	lk, err := locker.Obtain(ctx, "lock", time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(lk.ValidUntil()) >= time.Minute {
+		t.Fatal("validity does not account for clock drift")
+	}
+
+	// a majority of the instances is required
+	if _, err := New(a, b, c).Obtain(ctx, "other", time.Minute, &Options{Token: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	b.err = c.err
+	if _, err := locker.Obtain(ctx, "third", time.Minute, nil); err != c.err {
+		t.Fatalf("got %v, want %v", err, c.err)
+	}
+	if _, ok := a.get("third"); ok {
+		t.Fatal("minority lock was not released")
+	}
+	b.err = nil
+	if err := lk.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedlockQuorumReusedToken(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newFakeScripter(), newFakeScripter(), newFakeScripter()
+	if _, err := New(a).Obtain(ctx, "lock", time.Minute, &Options{Token: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(b).Obtain(ctx, "lock", time.Minute, &Options{Token: "y"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// obtaining again with the token fails without a quorum, and the lock
+	// already held with the token is kept
+	c.err = errors.New("connection refused")
+	if _, err := New(a, b, c).Obtain(ctx, "lock", time.Minute, &Options{Token: "x"}); err != ErrNotObtained {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if v, _ := a.get("lock"); v != "x" {
+		t.Fatalf("got %q, the lock held with the token was released", v)
+	}
+	if v, _ := b.get("lock"); v != "y" {
+		t.Fatalf("got %q", v)
+	}
+}
+
+func TestRedlockMinorityFailure(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newFakeScripter(), newFakeScripter(), newFakeScripter()
+	if _, err := New(a, b).Obtain(ctx, "lock", time.Minute, &Options{Token: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the lock is held on a majority, the error of c is not reported
+	c.err = errors.New("connection refused")
+	if _, err := New(a, b, c).Obtain(ctx, "lock", time.Minute, nil); err != ErrNotObtained {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+
+	// and Obtain retries until the lock is released
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		a.release("lock")
+		b.release("lock")
+	}()
+	opt := &Options{Retry: LimitRetry(LinearBackoff(10*time.Millisecond), 20)}
+	if _, err := New(a, b, c).Obtain(ctx, "lock", time.Minute, opt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoExtend(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeScripter()
+
+	lk, err := Obtain(ctx, client, "lock", 30*time.Millisecond, &Options{AutoExtend: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := lk.TTL(ctx); err != nil {
+		t.Fatalf("lock was not extended: %v", err)
+	}
+
+	client.mu.Lock()
+	client.vals["lock"] = "stolen"
+	client.mu.Unlock()
+	select {
+	case <-lk.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lost lock was not reported")
+	}
+	if err := lk.Release(ctx); err != ErrNotHeld {
+		t.Fatalf("got %v, want ErrNotHeld", err)
+	}
+}
+
+func TestRetryStrategies(t *testing.T) {
+	if d := NoRetry().NextBackoff(1); d >= 0 {
+		t.Errorf("NoRetry: got %v", d)
+	}
+	limited := LimitRetry(LinearBackoff(time.Second), 2)
+	if d := limited.NextBackoff(2); d != time.Second {
+		t.Errorf("LimitRetry: got %v, want 1s", d)
+	}
+	if d := limited.NextBackoff(3); d >= 0 {
+		t.Errorf("LimitRetry: got %v after the limit", d)
+	}
+	exp := ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond)
+	for retry := 1; retry < 10; retry++ {
+		if d := exp.NextBackoff(retry); d < 10*time.Millisecond || d > 100*time.Millisecond {
+			t.Errorf("ExponentialBackoff(%d): got %v", retry, d)
+		}
+	}
+}
+
+func TestRWMutexKeys(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name, prefix string
+	}{
+		{"doc", "{doc}"},
+		{"{user:1}:doc", "{user:1}:doc"},
+	}
+	for _, test := range tests {
+		client := newFakeScripter()
+		_, err := NewRWMutex(client, test.name).RLock(ctx, time.Minute, nil)
+		if err != ErrNotObtained {
+			t.Fatalf("got %v, want ErrNotObtained", err)
+		}
+
+		p := test.prefix
+		want := [][]string{
+			{p + ":writer", p + ":readers", p + ":queue", p + ":queue:exp", p + ":queue:seq"},
+			{p + ":queue", p + ":queue:exp"},
+		}
+		if !reflect.DeepEqual(client.keys, want) {
+			t.Errorf("%s: got keys %q, want %q", test.name, client.keys, want)
+		}
+	}
+}
+
+func TestSemaphoreAcquire(t *testing.T) {
+	client := newFakeScripter()
+	sem := NewSemaphore(client, "jobs", 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err := sem.Acquire(ctx, time.Minute, &Options{Retry: LinearBackoff(10 * time.Millisecond)})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	n := len(client.keys)
+	if n < 3 {
+		t.Fatalf("got %d script calls, want at least 3", n)
+	}
+	want := []string{"{jobs}:holders", "{jobs}:queue", "{jobs}:queue:exp", "{jobs}:queue:seq"}
+	if !reflect.DeepEqual(client.keys[0], want) {
+		t.Errorf("got keys %q, want %q", client.keys[0], want)
+	}
+	if !reflect.DeepEqual(client.keys[n-1], want[1:3]) {
+		t.Errorf("waiter was not removed from the queue: got keys %q", client.keys[n-1])
+	}
+}
+
+func TestFence(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeScripter()
+	fence := NewFence(client, "report")
+
+	first, err := fence.Obtain(ctx, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fence.Obtain(ctx, time.Minute, nil); err != ErrNotObtained {
+		t.Fatalf("got %v, want ErrNotObtained", err)
+	}
+	if err := fence.Validate(ctx, first.Fence); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := fence.Obtain(ctx, time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Fence <= first.Fence {
+		t.Fatalf("got fencing token %d after %d", second.Fence, first.Fence)
+	}
+	if err := fence.Validate(ctx, second.Fence); err != nil {
+		t.Fatal(err)
+	}
+	if err := fence.Validate(ctx, first.Fence); err != ErrStaleFence {
+		t.Fatalf("got %v, want ErrStaleFence", err)
+	}
+}
@@ -0,0 +1,215 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal/hashtag"
+)
+
+var (
+	// rwObtainScript obtains the read ("r") or write ("w") lock or queues the
+	// waiter. Queued waiters expire after the TTL of the lock, so a crashed
+	// waiter does not block the others for longer.
+	//
+	// KEYS: writer, readers, queue, queue expirations, queue sequence.
+	// ARGV: token, mode, TTL in milliseconds.
+	rwObtainScript = redis.NewScript(`
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local ttl = tonumber(ARGV[3])
+
+redis.call("zremrangebyscore", KEYS[2], "-inf", now)
+local expired = redis.call("zrangebyscore", KEYS[4], "-inf", now)
+for _, member in ipairs(expired) do
+	redis.call("zrem", KEYS[3], member)
+end
+redis.call("zremrangebyscore", KEYS[4], "-inf", now)
+
+local member = ARGV[2] .. ":" .. ARGV[1]
+local ticket = redis.call("zscore", KEYS[3], member)
+if not ticket then
+	ticket = redis.call("incr", KEYS[5])
+	redis.call("zadd", KEYS[3], ticket, member)
+end
+
+local writer = redis.call("get", KEYS[1])
+local ok = false
+if ARGV[2] == "w" then
+	if writer == ARGV[1] then
+		ok = true
+	elseif not writer and redis.call("zcard", KEYS[2]) == 0 then
+		ok = #redis.call("zrangebyscore", KEYS[3], "-inf", "(" .. ticket, "limit", 0, 1) == 0
+	end
+elseif not writer then
+	ok = true
+	for _, waiter in ipairs(redis.call("zrangebyscore", KEYS[3], "-inf", "(" .. ticket)) do
+		if string.sub(waiter, 1, 2) == "w:" then
+			ok = false
+			break
+		end
+	end
+end
+
+local function expire(key, ms)
+	if redis.call("pttl", key) < ms then
+		redis.call("pexpire", key, ms)
+	end
+end
+
+if ok then
+	redis.call("zrem", KEYS[3], member)
+	redis.call("zrem", KEYS[4], member)
+	if ARGV[2] == "w" then
+		redis.call("set", KEYS[1], ARGV[1], "PX", ttl)
+	else
+		redis.call("zadd", KEYS[2], now + ttl, ARGV[1])
+		expire(KEYS[2], ttl)
+	end
+	return 1
+end
+
+redis.call("zadd", KEYS[4], now + ttl, member)
+expire(KEYS[3], ttl)
+expire(KEYS[4], ttl)
+expire(KEYS[5], ttl)
+return 0
+`)
+	// rwCancelScript removes a waiter from the queue.
+	//
+	// KEYS: queue, queue expirations.
+	// ARGV: token, mode.
+	rwCancelScript = redis.NewScript(`
+local member = ARGV[2] .. ":" .. ARGV[1]
+redis.call("zrem", KEYS[2], member)
+return redis.call("zrem", KEYS[1], member)
+`)
+
+	readReleaseScript = redis.NewScript(`
+return redis.call("zrem", KEYS[1], ARGV[1])
+`)
+	readExtendScript = redis.NewScript(`
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local ttl = tonumber(ARGV[2])
+local score = redis.call("zscore", KEYS[1], ARGV[1])
+if not score or tonumber(score) <= now then
+	return 0
+end
+redis.call("zadd", KEYS[1], "XX", now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[1]) < ttl then
+	redis.call("pexpire", KEYS[1], ttl)
+end
+return 1
+`)
+	readPTTLScript = redis.NewScript(`
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local score = redis.call("zscore", KEYS[1], ARGV[1])
+if not score or tonumber(score) <= now then
+	return -3
+end
+return tonumber(score) - now
+`)
+)
+
+var readScripts = &lockScripts{release: readReleaseScript, extend: readExtendScript, pttl: readPTTLScript}
+
+// RWMutex is a reader/writer lock held by any number of readers or by a single
+// writer. Waiters are served in order: a reader does not obtain the lock while
+// a writer is waiting before it, so writers are not starved by readers.
+//
+// The keys of the lock share the hash tag of its name, or the name itself, so
+// they are in the same slot of a cluster.
+type RWMutex struct {
+	client *Client
+	name   string
+
+	writerKey   string
+	readersKey  string
+	queueKey    string
+	queueExpKey string
+	seqKey      string
+}
+
+// NewRWMutex returns the reader/writer lock name of client.
+func NewRWMutex(client redis.Scripter, name string) *RWMutex {
+	prefix := hashTagged(name)
+	return &RWMutex{
+		client:      New(client),
+		name:        name,
+		writerKey:   prefix + ":writer",
+		readersKey:  prefix + ":readers",
+		queueKey:    prefix + ":queue",
+		queueExpKey: prefix + ":queue:exp",
+		seqKey:      prefix + ":queue:seq",
+	}
+}
+
+// Lock obtains the lock for writing for ttl. It returns ErrNotObtained when
+// the lock is held, or is waited for by someone else before, after the retries
+// of Options.Retry. The returned lock is released or extended like any other.
+func (m *RWMutex) Lock(ctx context.Context, ttl time.Duration, opt *Options) (*Lock, error) {
+	return m.obtain(ctx, "w", ttl, opt)
+}
+
+// RLock obtains the lock for reading for ttl, see Lock.
+func (m *RWMutex) RLock(ctx context.Context, ttl time.Duration, opt *Options) (*Lock, error) {
+	return m.obtain(ctx, "r", ttl, opt)
+}
+
+func (m *RWMutex) obtain(ctx context.Context, mode string, ttl time.Duration, opt *Options) (*Lock, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	token := opt.Token
+	if token == "" {
+		var err error
+		if token, err = newToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	lk := &Lock{
+		client:  m.client,
+		key:     m.name,
+		keys:    []string{m.writerKey},
+		scripts: keyScripts,
+		token:   token,
+		ttl:     ttl,
+	}
+	if mode == "r" {
+		lk.keys = []string{m.readersKey}
+		lk.scripts = readScripts
+	}
+
+	keys := []string{m.writerKey, m.readersKey, m.queueKey, m.queueExpKey, m.seqKey}
+	client := m.client.clients[0]
+	err := retryObtain(ctx, opt.Retry, nil, func(ctx context.Context) (bool, error) {
+		start := time.Now()
+		ok, err := runBool(ctx, rwObtainScript, client, keys, token, mode, ttl.Milliseconds())
+		if ok {
+			lk.setValidity(start, ttl)
+		}
+		return ok, err
+	})
+	if err != nil {
+		// leave the queue so the waiters after us are not blocked until we expire
+		_ = rwCancelScript.Run(context.Background(), client, keys[2:4], token, mode).Err()
+		return nil, err
+	}
+
+	if opt.AutoExtend {
+		lk.watch()
+	}
+	return lk, nil
+}
+
+// hashTagged returns name, wrapped in braces unless it has a hash tag.
+func hashTagged(name string) string {
+	if hashtag.Key(name) == name {
+		return "{" + name + "}"
+	}
+	return name
+}
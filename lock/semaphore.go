@@ -0,0 +1,183 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// semAcquireScript acquires the semaphore or queues the waiter. The first
+	// waiters are served as long as there are free slots, in order.
+	//
+	// KEYS: holders, queue, queue expirations, queue sequence.
+	// ARGV: token, size, TTL in milliseconds.
+	semAcquireScript = redis.NewScript(`
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local size = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local function expire(key, ms)
+	if redis.call("pttl", key) < ms then
+		redis.call("pexpire", key, ms)
+	end
+end
+
+redis.call("zremrangebyscore", KEYS[1], "-inf", now)
+local expired = redis.call("zrangebyscore", KEYS[3], "-inf", now)
+for _, member in ipairs(expired) do
+	redis.call("zrem", KEYS[2], member)
+end
+redis.call("zremrangebyscore", KEYS[3], "-inf", now)
+
+if redis.call("zscore", KEYS[1], ARGV[1]) then
+	redis.call("zadd", KEYS[1], "XX", now + ttl, ARGV[1])
+	expire(KEYS[1], ttl)
+	return 1
+end
+
+if not redis.call("zscore", KEYS[2], ARGV[1]) then
+	redis.call("zadd", KEYS[2], redis.call("incr", KEYS[4]), ARGV[1])
+end
+
+local free = size - redis.call("zcard", KEYS[1])
+if redis.call("zrank", KEYS[2], ARGV[1]) < free then
+	redis.call("zrem", KEYS[2], ARGV[1])
+	redis.call("zrem", KEYS[3], ARGV[1])
+	redis.call("zadd", KEYS[1], now + ttl, ARGV[1])
+	expire(KEYS[1], ttl)
+	return 1
+end
+
+redis.call("zadd", KEYS[3], now + ttl, ARGV[1])
+expire(KEYS[2], ttl)
+expire(KEYS[3], ttl)
+expire(KEYS[4], ttl)
+return 0
+`)
+	// semCancelScript removes a waiter from the queue.
+	//
+	// KEYS: queue, queue expirations.
+	// ARGV: token.
+	semCancelScript = redis.NewScript(`
+redis.call("zrem", KEYS[2], ARGV[1])
+return redis.call("zrem", KEYS[1], ARGV[1])
+`)
+	// semReleaseScript releases the semaphore and wakes up the waiters.
+	semReleaseScript = redis.NewScript(`
+local n = redis.call("zrem", KEYS[1], ARGV[1])
+if n == 1 then
+	redis.call("publish", KEYS[1] .. ":released", ARGV[1])
+end
+return n
+`)
+)
+
+var semaphoreScripts = &lockScripts{release: semReleaseScript, extend: readExtendScript, pttl: readPTTLScript}
+
+// Semaphore is a counting semaphore held by at most Size() holders at a time.
+// Each holder expires after its TTL, so a crashed holder releases its slot.
+// Waiters are served in the order they started waiting.
+//
+// Waiters are woken up by the releases, published on the channel with the name
+// of the holders key followed by ":released" when the client can subscribe,
+// and otherwise poll. No keyspace notification needs to be configured.
+//
+// The keys of the semaphore share the hash tag of its name, or the name
+// itself, so they are in the same slot of a cluster.
+type Semaphore struct {
+	client *Client
+	name   string
+	size   int
+
+	holdersKey  string
+	queueKey    string
+	queueExpKey string
+	seqKey      string
+}
+
+// NewSemaphore returns the semaphore name of client with size slots.
+func NewSemaphore(client redis.Scripter, name string, size int) *Semaphore {
+	if size <= 0 {
+		panic("lock: NewSemaphore() requires a positive size")
+	}
+	prefix := hashTagged(name)
+	return &Semaphore{
+		client:      New(client),
+		name:        name,
+		size:        size,
+		holdersKey:  prefix + ":holders",
+		queueKey:    prefix + ":queue",
+		queueExpKey: prefix + ":queue:exp",
+		seqKey:      prefix + ":queue:seq",
+	}
+}
+
+// Size returns the number of slots of the semaphore.
+func (s *Semaphore) Size() int {
+	return s.size
+}
+
+// Acquire acquires a slot of the semaphore for ttl, blocking until a slot is
+// free or ctx is done. Options.Retry is the polling interval, 100 milliseconds
+// by default; ErrNotObtained is returned when it stops retrying. The returned
+// lock is released or extended like any other.
+func (s *Semaphore) Acquire(ctx context.Context, ttl time.Duration, opt *Options) (*Lock, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	token := opt.Token
+	if token == "" {
+		var err error
+		if token, err = newToken(); err != nil {
+			return nil, err
+		}
+	}
+	retry := opt.Retry
+	if retry == nil {
+		retry = LinearBackoff(100 * time.Millisecond)
+	}
+
+	lk := &Lock{
+		client:  s.client,
+		key:     s.name,
+		keys:    []string{s.holdersKey},
+		scripts: semaphoreScripts,
+		token:   token,
+		ttl:     ttl,
+	}
+
+	client := s.client.clients[0]
+	var wake <-chan *redis.Message
+	if sub, ok := client.(subscriber); ok && retry.NextBackoff(1) >= 0 {
+		pubsub := sub.Subscribe(ctx, s.holdersKey+":released")
+		defer pubsub.Close()
+		wake = pubsub.Channel()
+	}
+
+	keys := []string{s.holdersKey, s.queueKey, s.queueExpKey, s.seqKey}
+	err := retryObtain(ctx, retry, wake, func(ctx context.Context) (bool, error) {
+		start := time.Now()
+		ok, err := runBool(ctx, semAcquireScript, client, keys, token, s.size, ttl.Milliseconds())
+		if ok {
+			lk.setValidity(start, ttl)
+		}
+		return ok, err
+	})
+	if err != nil {
+		// leave the queue so the waiters after us are not blocked until we expire
+		_ = semCancelScript.Run(context.Background(), client, keys[1:3], token).Err()
+		return nil, err
+	}
+
+	if opt.AutoExtend {
+		lk.watch()
+	}
+	return lk, nil
+}
+
+type subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// Logger is the leveled structured logger of a client, see Options.Logger.
+// The messages are logged with key-value pairs as args, e.g. "error", err,
+// and *slog.Logger implements it:
+//
+//	rdb := redis.NewClient(&redis.Options{
+//		Addr:   "localhost:6379",
+//		Logger: slog.Default().With("client", "sessions"),
+//	})
+//
+// The logged commands are rendered with DefaultCmdMarshaler, which redacts
+// their secrets such as the passwords of AUTH and HELLO.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+var _ internal.StructuredLogger = Logger(nil)
+
+// clientLogger returns the Logger of the options of client, or nil for the
+// clients without options such as the pipelines.
+func clientLogger(client interface{}) Logger {
+	switch client := client.(type) {
+	case *Client:
+		return client.opt.Logger
+	case *ClusterClient:
+		return client.opt.Logger
+	case *Ring:
+		return client.opt.Logger
+	}
+	return nil
+}
+
+// logCmd renders cmd for the logs.
+func logCmd(cmd Cmder) string {
+	return string(DefaultCmdMarshaler.Text(cmd))
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+// testLogger records the messages logged as a Logger.
+type testLogger struct {
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, logRecord{level: level, msg: msg, args: args})
+}
+
+func (l *testLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("debug", msg, args)
+}
+
+func (l *testLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("info", msg, args)
+}
+
+func (l *testLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("warn", msg, args)
+}
+
+func (l *testLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	l.log("error", msg, args)
+}
+
+func TestOptionsLogger(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(logger Logger) *Client {
+		return NewClient(&Options{
+			Addr:   "db:6379",
+			DryRun: true,
+			Logger: logger,
+			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				t.Fatal("dialed with DryRun")
+				return nil, nil
+			},
+		})
+	}
+	var logger1, logger2 testLogger
+	client1, client2 := newClient(&logger1), newClient(&logger2)
+	defer client1.Close()
+	defer client2.Close()
+
+	_ = client1.Do(ctx, "auth", "user", "secret").Err()
+	_ = client2.Get(ctx, "key").Err()
+
+	if len(logger1.records) != 1 || len(logger2.records) != 1 {
+		t.Fatalf("got %d and %d records, wanted 1 each", len(logger1.records), len(logger2.records))
+	}
+	rec := logger1.records[0]
+	if rec.level != "info" || rec.msg != "redis: dry run" {
+		t.Fatalf("got %s %q", rec.level, rec.msg)
+	}
+	if len(rec.args) != 4 || rec.args[0] != "addr" || rec.args[1] != "db:6379" || rec.args[2] != "cmd" {
+		t.Fatalf("got args %v", rec.args)
+	}
+	if cmd := rec.args[3].(string); strings.Contains(cmd, "secret") || cmd != "auth user (redacted)" {
+		t.Fatalf("got cmd %q", cmd)
+	}
+	if cmd := logger2.records[0].args[3]; cmd != "get key" {
+		t.Fatalf("got cmd %q", cmd)
+	}
+}
+
+func TestOptionsLoggerBackground(t *testing.T) {
+	var logger testLogger
+	client := NewClient(&Options{
+		Addr:     "db:6379",
+		Logger:   &logger,
+		TLSFiles: &TLSFiles{CertFile: "missing.pem", KeyFile: "missing-key.pem"},
+	})
+	defer client.Close()
+
+	b := NewTSBuffer(client, &TSBufferOptions{FlushInterval: -1})
+	b.onError(errors.New("flush failed"))
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	var msgs []string
+	for _, rec := range logger.records {
+		msgs = append(msgs, rec.msg)
+	}
+	want := []string{"redis: loading TLS files failed", "redis: TSBuffer flush failed"}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Fatalf("got %q, wanted %q", msgs, want)
+	}
+}
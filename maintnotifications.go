@@ -0,0 +1,280 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+)
+
+// MaintNotificationType is the kind of a maintenance push notification.
+type MaintNotificationType string
+
+const (
+	// MaintMoving announces that the endpoint is about to be rebound to another address.
+	MaintMoving MaintNotificationType = "MOVING"
+	// MaintMigrating announces that a shard is about to be migrated.
+	MaintMigrating MaintNotificationType = "MIGRATING"
+	// MaintMigrated announces that a shard migration has completed.
+	MaintMigrated MaintNotificationType = "MIGRATED"
+	// MaintFailingOver announces that a shard is about to fail over.
+	MaintFailingOver MaintNotificationType = "FAILING_OVER"
+	// MaintFailedOver announces that a shard failover has completed.
+	MaintFailedOver MaintNotificationType = "FAILED_OVER"
+)
+
+var maintNotificationTypes = []MaintNotificationType{
+	MaintMoving, MaintMigrating, MaintMigrated, MaintFailingOver, MaintFailedOver,
+}
+
+// MaintNotification is a maintenance push notification sent by Redis 8 and
+// Redis Enterprise to RESP3 connections that enabled maintenance notifications.
+type MaintNotification struct {
+	Type  MaintNotificationType
+	SeqID int64
+
+	// Time is the time left until the announced event,
+	// e.g. until the old endpoint stops serving connections after MOVING.
+	Time time.Duration
+
+	// Endpoint is the "host:port" announced by MOVING.
+	// It is empty when the server asks to reconnect to the current endpoint.
+	Endpoint string
+
+	// ShardIDs lists the shards affected by the migration or failover.
+	ShardIDs []string
+}
+
+func parseMaintNotification(typ MaintNotificationType, payload []interface{}) (*MaintNotification, bool) {
+	n := &MaintNotification{Type: typ}
+
+	var ok bool
+	if len(payload) == 0 {
+		return nil, false
+	}
+	if n.SeqID, ok = payload[0].(int64); !ok {
+		return nil, false
+	}
+	payload = payload[1:]
+
+	switch typ {
+	case MaintMoving, MaintMigrating, MaintFailingOver:
+		if len(payload) == 0 {
+			return nil, false
+		}
+		sec, ok := payload[0].(int64)
+		if !ok {
+			return nil, false
+		}
+		n.Time = time.Duration(sec) * time.Second
+		payload = payload[1:]
+	}
+
+	if typ == MaintMoving {
+		if len(payload) > 0 {
+			if endpoint, ok := payload[0].(string); ok && endpoint != "null" {
+				n.Endpoint = endpoint
+			}
+		}
+		return n, true
+	}
+
+	for _, v := range payload {
+		switch v := v.(type) {
+		case string:
+			n.ShardIDs = append(n.ShardIDs, strings.Split(v, ",")...)
+		case []interface{}:
+			for _, id := range v {
+				if id, ok := id.(string); ok {
+					n.ShardIDs = append(n.ShardIDs, id)
+				}
+			}
+		}
+	}
+	return n, true
+}
+
+// MaintNotificationsOptions enables maintenance push notifications, which the
+// client asks for with CLIENT MAINT_NOTIFICATIONS during the connection handshake.
+// It requires the RESP3 protocol.
+type MaintNotificationsOptions struct {
+	// EndpointType selects the kind of address announced by MOVING notifications:
+	// "internal-ip", "external-ip", "internal-fqdn", "external-fqdn" or "none".
+	// Default lets the server decide.
+	EndpointType string
+
+	// OnNotification is called for every maintenance notification received on
+	// any connection of the client, so the same event is usually reported once
+	// per connection. It is called on the goroutine reading a reply and must not block.
+	OnNotification func(ctx context.Context, n *MaintNotification)
+
+	// Preconnect makes the client follow MOVING notifications: new connections
+	// are dialed to the announced endpoint, and max(MinIdleConns, 1) of them are
+	// established right away so the cut-over does not pay for the dials. The
+	// connections to the old endpoint are drained: the idle ones are closed and
+	// the ones in use are closed once their command completes. When MOVING
+	// announces no endpoint, the connections are drained halfway through the
+	// announced time and reconnect to the current endpoint.
+	Preconnect bool
+
+	// RelaxedTimeout replaces ReadTimeout and WriteTimeout when they are shorter
+	// during the maintenance windows, which slow down the commands: from
+	// MOVING, MIGRATING and FAILING_OVER until MIGRATED, FAILED_OVER or the
+	// announced time elapses. Supported values:
+	//   - `0` - default relaxed timeout (10 seconds).
+	//   - `-1` - the timeouts are not relaxed.
+	RelaxedTimeout time.Duration
+}
+
+// maintNotifications is the per-client state of maintenance notifications.
+type maintNotifications struct {
+	opt *MaintNotificationsOptions
+
+	mu           sync.RWMutex
+	endpoint     string    // overrides Options.Addr after MOVING
+	movingSeqID  int64     // SeqID of the last followed MOVING without endpoint
+	relaxedUntil time.Time // end of the maintenance window
+}
+
+func newMaintNotifications(opt *MaintNotificationsOptions) *maintNotifications {
+	if opt.RelaxedTimeout == 0 {
+		opt.RelaxedTimeout = 10 * time.Second
+	}
+	return &maintNotifications{
+		opt:         opt,
+		movingSeqID: -1,
+	}
+}
+
+func (m *maintNotifications) register(c *baseClient, pushes *pushProcessor) {
+	for _, typ := range maintNotificationTypes {
+		typ := typ
+		pushes.register(string(typ), func(ctx context.Context, payload []interface{}) {
+			n, ok := parseMaintNotification(typ, payload)
+			if !ok {
+				internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "redis: can't parse notification",
+					"type", typ, "payload", payload)
+				return
+			}
+			m.notify(ctx, c, n)
+		})
+	}
+}
+
+func (m *maintNotifications) notify(ctx context.Context, c *baseClient, n *MaintNotification) {
+	if m.opt.OnNotification != nil {
+		m.opt.OnNotification(ctx, n)
+	}
+
+	switch n.Type {
+	case MaintMoving, MaintMigrating, MaintFailingOver:
+		m.relax(n.Time)
+	case MaintMigrated, MaintFailedOver:
+		m.unrelax()
+	}
+
+	if n.Type == MaintMoving && m.opt.Preconnect {
+		if n.Endpoint != "" {
+			m.moving(c, n.Endpoint)
+		} else {
+			m.reconnect(c, n)
+		}
+	}
+}
+
+// moving redirects new connections to endpoint, pre-connects to it and drains
+// the connections to the old endpoint.
+func (m *maintNotifications) moving(c *baseClient, endpoint string) {
+	m.mu.Lock()
+	if m.endpoint == endpoint {
+		m.mu.Unlock()
+		return
+	}
+	m.endpoint = endpoint
+	m.mu.Unlock()
+
+	if p, ok := c.connPool.(*pool.ConnPool); ok {
+		p.RetireConns()
+		n := c.opt.MinIdleConns
+		if n < 1 {
+			n = 1
+		}
+		p.AddIdleConns(n)
+	}
+}
+
+// reconnect drains the connections halfway through the MOVING n announcing
+// no endpoint, so they reconnect to the current endpoint before it stops
+// serving them.
+func (m *maintNotifications) reconnect(c *baseClient, n *MaintNotification) {
+	m.mu.Lock()
+	if m.movingSeqID == n.SeqID {
+		m.mu.Unlock()
+		return
+	}
+	m.movingSeqID = n.SeqID
+	m.mu.Unlock()
+
+	if p, ok := c.connPool.(*pool.ConnPool); ok {
+		time.AfterFunc(n.Time/2, p.RetireConns)
+	}
+}
+
+// relax relaxes the timeouts for d, the announced duration of the maintenance,
+// plus the relaxed timeout of the commands sent at its end.
+func (m *maintNotifications) relax(d time.Duration) {
+	if m.opt.RelaxedTimeout < 0 {
+		return
+	}
+	until := time.Now().Add(d + m.opt.RelaxedTimeout)
+
+	m.mu.Lock()
+	if until.After(m.relaxedUntil) {
+		m.relaxedUntil = until
+	}
+	m.mu.Unlock()
+}
+
+func (m *maintNotifications) unrelax() {
+	m.mu.Lock()
+	m.relaxedUntil = time.Time{}
+	m.mu.Unlock()
+}
+
+// timeout returns the relaxed timeout during the maintenance windows, or
+// timeout. It is a no-op for nil notifications.
+func (m *maintNotifications) timeout(timeout time.Duration) time.Duration {
+	if m == nil || timeout <= 0 {
+		return timeout
+	}
+
+	m.mu.RLock()
+	relaxed := time.Now().Before(m.relaxedUntil)
+	m.mu.RUnlock()
+
+	if relaxed && m.opt.RelaxedTimeout > timeout {
+		return m.opt.RelaxedTimeout
+	}
+	return timeout
+}
+
+// addr returns the address new connections are dialed to.
+func (m *maintNotifications) addr(addr string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.endpoint != "" {
+		return m.endpoint
+	}
+	return addr
+}
+
+func (m *maintNotifications) enableArgs() []interface{} {
+	args := []interface{}{"client", "maint_notifications", "on"}
+	if m.opt.EndpointType != "" {
+		args = append(args, "moving-endpoint-type", m.opt.EndpointType)
+	}
+	return args
+}
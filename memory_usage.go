@@ -0,0 +1,176 @@
+package redis
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/redis/go-redis/v9/internal/util"
+)
+
+type MemoryUsageOptions struct {
+	// Match is the SCAN pattern of the scanned keys. Default is all the keys.
+	Match string
+	// BatchSize is the COUNT of SCAN and the number of MEMORY USAGE per
+	// pipeline. Default is 100.
+	BatchSize int
+	// SampleRate is the fraction of the scanned keys whose MEMORY USAGE is
+	// sampled, from 0 to 1. Default is 1, all the keys.
+	SampleRate float64
+	// Samples is the number of elements sampled by MEMORY USAGE to estimate
+	// the size of the hashes, lists, sets and sorted sets. Supported values:
+	//   - `0` - default Redis samples (5).
+	//   - `-1` - all the elements.
+	Samples int
+}
+
+// MemoryUsageReport is the memory used by the keys of a deployment, grouped by
+// pattern.
+type MemoryUsageReport struct {
+	// Scanned is the number of scanned keys.
+	Scanned int64
+	// Sampled is the number of keys whose memory usage was sampled.
+	Sampled int64
+	// Bytes is the memory used by the sampled keys.
+	Bytes int64
+	// Groups are the memory usages of the keys matching the patterns, in the
+	// order of the patterns, followed by the keys matching no pattern.
+	Groups []MemoryUsageGroup
+	// Failed is the number of keys whose memory usage failed to be sampled.
+	Failed int64
+	// LastError is the last error sampling a key.
+	LastError error
+}
+
+// MemoryUsageGroup is the memory used by the keys matching a pattern.
+type MemoryUsageGroup struct {
+	// Pattern is the pattern of the keys, or "" for the keys matching no
+	// pattern.
+	Pattern string
+	// Keys is the number of scanned keys matching the pattern.
+	Keys int64
+	// Sampled is the number of keys whose memory usage was sampled.
+	Sampled int64
+	// Bytes is the memory used by the sampled keys.
+	Bytes int64
+	// MaxKey is the sampled key using the most memory, and MaxBytes its memory
+	// usage.
+	MaxKey   string
+	MaxBytes int64
+}
+
+// EstimatedBytes is the memory used by all the keys of the group, extrapolated
+// from the sampled keys.
+func (g *MemoryUsageGroup) EstimatedBytes() int64 {
+	if g.Sampled == 0 {
+		return 0
+	}
+	return int64(float64(g.Bytes) / float64(g.Sampled) * float64(g.Keys))
+}
+
+// AverageBytes is the average memory used by the sampled keys of the group.
+func (g *MemoryUsageGroup) AverageBytes() int64 {
+	if g.Sampled == 0 {
+		return 0
+	}
+	return g.Bytes / g.Sampled
+}
+
+// SampleMemoryUsage scans the keys of client, samples their MEMORY USAGE and
+// groups them by the first of patterns they match, e.g. "user:*" and
+// "session:*". client can be a Client, a ClusterClient or a Ring: the keys of
+// every master of a cluster, or every shard of a ring, are scanned
+// concurrently.
+//
+// It returns an error when scanning the keys fails, and counts the keys it
+// failed to sample in MemoryUsageReport.Failed.
+func SampleMemoryUsage(
+	ctx context.Context, client UniversalClient, patterns []string, opt *MemoryUsageOptions,
+) (*MemoryUsageReport, error) {
+	var o MemoryUsageOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if o.SampleRate <= 0 || o.SampleRate > 1 {
+		o.SampleRate = 1
+	}
+
+	report := &MemoryUsageReport{
+		Groups: make([]MemoryUsageGroup, len(patterns)+1),
+	}
+	for i, pattern := range patterns {
+		report.Groups[i].Pattern = pattern
+	}
+	var mu sync.Mutex
+
+	err := forEachScanNode(ctx, client, func(ctx context.Context, node *Client) error {
+		return scanKeys(ctx, node, o.Match, "", o.BatchSize, func(keys []string) error {
+			groups := make([]int, len(keys))
+			cmds := make([]*IntCmd, len(keys))
+			_, _ = node.Pipelined(ctx, func(pipe Pipeliner) error {
+				for i, key := range keys {
+					groups[i] = matchGroup(patterns, key)
+					if o.SampleRate < 1 && rand.Float64() >= o.SampleRate {
+						continue
+					}
+					switch {
+					case o.Samples < 0:
+						cmds[i] = pipe.MemoryUsage(ctx, key, 0)
+					case o.Samples > 0:
+						cmds[i] = pipe.MemoryUsage(ctx, key, o.Samples)
+					default:
+						cmds[i] = pipe.MemoryUsage(ctx, key)
+					}
+				}
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Scanned += int64(len(keys))
+			for i, key := range keys {
+				g := &report.Groups[groups[i]]
+				g.Keys++
+				if cmds[i] == nil {
+					continue
+				}
+				n, err := cmds[i].Result()
+				if err == Nil {
+					// deleted after the scan
+					g.Keys--
+					report.Scanned--
+					continue
+				}
+				if err != nil {
+					report.Failed++
+					report.LastError = err
+					continue
+				}
+				report.Sampled++
+				report.Bytes += n
+				g.Sampled++
+				g.Bytes += n
+				if n > g.MaxBytes {
+					g.MaxKey = key
+					g.MaxBytes = n
+				}
+			}
+			return nil
+		})
+	})
+	return report, err
+}
+
+// matchGroup returns the index of the first of patterns matching key, or
+// len(patterns) when none matches.
+func matchGroup(patterns []string, key string) int {
+	for i, pattern := range patterns {
+		if util.GlobMatch(pattern, key) {
+			return i
+		}
+	}
+	return len(patterns)
+}
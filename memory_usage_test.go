@@ -0,0 +1,46 @@
+package redis_test
+
+import (
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("SampleMemoryUsage", func() {
+	var client *redis.Client
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Set(ctx, "user:1", "one", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "user:2", "two", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.RPush(ctx, "session:1", "a", "b", "c").Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "other", "value", 0).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should group the memory usage by pattern", func() {
+		report, err := redis.SampleMemoryUsage(ctx, client, []string{"user:*", "session:*"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Scanned).To(Equal(int64(4)))
+		Expect(report.Sampled).To(Equal(int64(4)))
+		Expect(report.Groups).To(HaveLen(3))
+
+		users := report.Groups[0]
+		Expect(users.Pattern).To(Equal("user:*"))
+		Expect(users.Keys).To(Equal(int64(2)))
+		Expect(users.Bytes).To(BeNumerically(">", 0))
+		Expect(users.EstimatedBytes()).To(Equal(users.Bytes))
+
+		Expect(report.Groups[1].Keys).To(Equal(int64(1)))
+		Expect(report.Groups[1].MaxKey).To(Equal("session:1"))
+		Expect(report.Groups[2].Pattern).To(Equal(""))
+		Expect(report.Groups[2].MaxKey).To(Equal("other"))
+		Expect(report.Bytes).To(Equal(users.Bytes + report.Groups[1].Bytes + report.Groups[2].Bytes))
+	})
+})
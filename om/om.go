@@ -0,0 +1,351 @@
+// Package om maps Go structs to Redis hashes or RedisJSON documents.
+//
+// A Repository is created for a struct type, whose tagged fields are stored
+// under keys built from a prefix and the value of the field tagged `om:"id"`:
+//
+//	type User struct {
+//		ID      string `redis:"id" json:"id" om:"id"`
+//		Name    string `redis:"name" json:"name" search:"text,sortable"`
+//		Age     int    `redis:"age" json:"age" search:"numeric"`
+//		Version int64  `redis:"version" json:"version" om:"version"`
+//	}
+//
+//	users, err := om.NewRepository(rdb, &User{}, &om.Options{Prefix: "user:"})
+//
+// Hash fields are named after the `redis` tags, JSON documents are encoded with
+// encoding/json. Fields tagged with `search` describe the search index created
+// by EnsureIndex, see redis.SearchSchemaFromStruct.
+//
+// An integer field tagged `om:"version"` enables optimistic locking: Save
+// fails with ErrVersionConflict unless the version of the value is the stored
+// one, and increments it.
+package om
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrVersionConflict is returned by Save when the value was modified since it was read.
+var ErrVersionConflict = errors.New("om: version conflict")
+
+// Storage is the Redis type values are stored as.
+type Storage int
+
+const (
+	// HashStorage stores values as hashes.
+	HashStorage Storage = iota
+	// JSONStorage stores values as RedisJSON documents.
+	JSONStorage
+)
+
+type Options struct {
+	// Prefix of the keys. Default is the lower-cased name of the struct followed by ":".
+	Prefix string
+	// Storage of the values. Default is HashStorage.
+	Storage Storage
+	// Index is the name of the search index. Default is Prefix followed by "idx".
+	Index string
+}
+
+// Repository saves, loads, deletes and searches the values of a struct type.
+type Repository struct {
+	client redis.UniversalClient
+	typ    reflect.Type
+	opt    Options
+
+	id      int
+	version int
+	// versionName is the hash field or JSONPath of the version.
+	versionName string
+}
+
+// NewRepository returns the repository of the struct type of v.
+func NewRepository(client redis.UniversalClient, v interface{}, opt *Options) (*Repository, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("om: NewRepository(non-struct %T)", v)
+	}
+
+	r := &Repository{
+		client:  client,
+		typ:     t,
+		id:      -1,
+		version: -1,
+	}
+	if opt != nil {
+		r.opt = *opt
+	}
+	if r.opt.Prefix == "" {
+		r.opt.Prefix = strings.ToLower(t.Name()) + ":"
+	}
+	if r.opt.Index == "" {
+		r.opt.Index = r.opt.Prefix + "idx"
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch f.Tag.Get("om") {
+		case "":
+			continue
+		case "id":
+			if f.Type.Kind() != reflect.String {
+				return nil, fmt.Errorf("om: id field %s.%s must be a string", t, f.Name)
+			}
+			r.id = i
+		case "version":
+			switch f.Type.Kind() {
+			case reflect.Int, reflect.Int32, reflect.Int64:
+			default:
+				return nil, fmt.Errorf("om: version field %s.%s must be an integer", t, f.Name)
+			}
+			r.version = i
+			if r.versionName = tagName(f, r.tagKey()); r.versionName == "" {
+				return nil, fmt.Errorf("om: version field %s.%s requires a %s tag", t, f.Name, r.tagKey())
+			}
+		default:
+			return nil, fmt.Errorf("om: unknown tag %q of field %s.%s", f.Tag.Get("om"), t, f.Name)
+		}
+	}
+	if r.id < 0 {
+		return nil, fmt.Errorf("om: %s has no field tagged `om:\"id\"`", t)
+	}
+	return r, nil
+}
+
+func (r *Repository) tagKey() string {
+	if r.opt.Storage == JSONStorage {
+		return "json"
+	}
+	return "redis"
+}
+
+func tagName(f reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// Key returns the key of the value with the id.
+func (r *Repository) Key(id string) string {
+	return r.opt.Prefix + id
+}
+
+// Index returns the search index of the values, generated from their `search` tags.
+func (r *Repository) Index() (*redis.SearchIndex, error) {
+	options := &redis.FTCreateOptions{
+		OnHash: r.opt.Storage == HashStorage,
+		OnJSON: r.opt.Storage == JSONStorage,
+		Prefix: []interface{}{r.opt.Prefix},
+	}
+	return redis.NewSearchIndex(r.client, r.opt.Index, options, reflect.New(r.typ).Interface())
+}
+
+// EnsureIndex creates the search index unless it already exists, see redis.SearchIndex.EnsureIndex.
+func (r *Repository) EnsureIndex(ctx context.Context) error {
+	idx, err := r.Index()
+	if err != nil {
+		return err
+	}
+	return idx.EnsureIndex(ctx)
+}
+
+func (r *Repository) structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != r.typ {
+		return reflect.Value{}, fmt.Errorf("om: got %T, want *%s", v, r.typ)
+	}
+	return rv.Elem(), nil
+}
+
+// Save stores the value pointed to by v, replacing the stored one. A random id
+// is generated when the id field is empty.
+func (r *Repository) Save(ctx context.Context, v interface{}) error {
+	rv, err := r.structValue(v)
+	if err != nil {
+		return err
+	}
+
+	idField := rv.Field(r.id)
+	if idField.String() == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		idField.SetString(id)
+	}
+	key := r.Key(idField.String())
+
+	if r.version < 0 {
+		_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return r.write(ctx, pipe, key, v)
+		})
+		return err
+	}
+
+	versionField := rv.Field(r.version)
+	version := versionField.Int()
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := r.storedVersion(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		if stored != version {
+			return ErrVersionConflict
+		}
+
+		versionField.SetInt(version + 1)
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return r.write(ctx, pipe, key, v)
+		})
+		return err
+	}, key)
+	if err != nil {
+		versionField.SetInt(version)
+		if err == redis.TxFailedErr {
+			return ErrVersionConflict
+		}
+	}
+	return err
+}
+
+func (r *Repository) write(ctx context.Context, pipe redis.Pipeliner, key string, v interface{}) error {
+	if r.opt.Storage == JSONStorage {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		pipe.JSONSet(ctx, key, "$", b)
+		return nil
+	}
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, v)
+	return nil
+}
+
+// storedVersion returns the version of the stored value, 0 when there is none.
+func (r *Repository) storedVersion(ctx context.Context, tx *redis.Tx, key string) (int64, error) {
+	if r.opt.Storage == JSONStorage {
+		res, err := tx.JSONGet(ctx, key, "$."+r.versionName).Result()
+		if err != nil && err != redis.Nil {
+			return 0, err
+		}
+		var versions []int64
+		if res != "" {
+			if err := json.Unmarshal([]byte(res), &versions); err != nil {
+				return 0, err
+			}
+		}
+		if len(versions) == 0 {
+			return 0, nil
+		}
+		return versions[0], nil
+	}
+
+	s, err := tx.HGet(ctx, key, r.versionName).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// Get loads the value with the id into the struct pointed to by dst.
+// It returns redis.Nil when there is no such value.
+func (r *Repository) Get(ctx context.Context, id string, dst interface{}) error {
+	rv, err := r.structValue(dst)
+	if err != nil {
+		return err
+	}
+	key := r.Key(id)
+
+	if r.opt.Storage == JSONStorage {
+		if err := r.client.JSONGetStruct(ctx, key, "$", dst); err != nil {
+			return err
+		}
+	} else {
+		cmd := r.client.HGetAll(ctx, key)
+		if err := cmd.Err(); err != nil {
+			return err
+		}
+		if len(cmd.Val()) == 0 {
+			return redis.Nil
+		}
+		if err := cmd.Scan(dst); err != nil {
+			return err
+		}
+	}
+	rv.Field(r.id).SetString(id)
+	return nil
+}
+
+// Delete deletes the value with the id. It reports whether the value existed.
+func (r *Repository) Delete(ctx context.Context, id string) (bool, error) {
+	n, err := r.client.Del(ctx, r.Key(id)).Result()
+	return n > 0, err
+}
+
+// Search runs the query on the search index and loads the documents into the
+// slice pointed to by dst, a *[]T or *[]*T. It returns the total number of
+// matching documents, which may exceed the returned ones, see FTSearchOptions.Limit.
+func (r *Repository) Search(ctx context.Context, query string, options *redis.FTSearchOptions, dst interface{}) (int, error) {
+	slice := reflect.ValueOf(dst)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return 0, fmt.Errorf("om: Search(non-slice pointer %T)", dst)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType != r.typ {
+		return 0, fmt.Errorf("om: got %T, want *[]%s or *[]*%s", dst, r.typ, r.typ)
+	}
+
+	res, err := r.client.FTSearchWithArgs(ctx, r.opt.Index, query, options).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	out := reflect.MakeSlice(slice.Type(), 0, len(res.Docs))
+	for i := range res.Docs {
+		doc := &res.Docs[i]
+		v := reflect.New(r.typ)
+		if err := doc.Scan(v.Interface()); err != nil {
+			return 0, err
+		}
+		v.Elem().Field(r.id).SetString(strings.TrimPrefix(doc.ID, r.opt.Prefix))
+		if isPtr {
+			out = reflect.Append(out, v)
+		} else {
+			out = reflect.Append(out, v.Elem())
+		}
+	}
+	slice.Set(out)
+	return res.Total, nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,68 @@
+package om_test
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/om"
+)
+
+type user struct {
+	ID      string `redis:"id" json:"id" om:"id"`
+	Name    string `redis:"name" json:"name" search:"text,sortable"`
+	Age     int    `redis:"age" json:"age" search:"numeric"`
+	Version int64  `redis:"version" json:"version" om:"version"`
+}
+
+func TestNewRepository(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: ":6379"})
+	defer client.Close()
+
+	users, err := om.NewRepository(client, &user{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := users.Key("42"); key != "user:42" {
+		t.Errorf("got key %q, want user:42", key)
+	}
+
+	idx, err := users.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Name() != "user:idx" {
+		t.Errorf("got index %q, want user:idx", idx.Name())
+	}
+	if n := len(idx.Schema()); n != 2 {
+		t.Errorf("got %d indexed fields, want 2", n)
+	}
+
+	type noID struct{ Name string }
+	type intID struct {
+		ID int `om:"id"`
+	}
+	type stringVersion struct {
+		ID      string `om:"id"`
+		Version string `om:"version"`
+	}
+	type untaggedVersion struct {
+		ID      string `om:"id"`
+		Version int64  `om:"version"`
+	}
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{noID{}, "om: om_test.noID has no field tagged `om:\"id\"`"},
+		{&intID{}, "om: id field om_test.intID.ID must be a string"},
+		{&stringVersion{}, "om: version field om_test.stringVersion.Version must be an integer"},
+		{&untaggedVersion{}, "om: version field om_test.untaggedVersion.Version requires a redis tag"},
+		{42, "om: NewRepository(non-struct int)"},
+	}
+	for _, test := range tests {
+		_, err := om.NewRepository(client, test.v, &om.Options{Prefix: "x:"})
+		if err == nil || err.Error() != test.want {
+			t.Errorf("NewRepository(%T) = %v, want %q", test.v, err, test.want)
+		}
+	}
+}
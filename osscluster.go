@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"math"
 	"net"
@@ -41,6 +42,7 @@ type ClusterOptions struct {
 	MaxRedirects int
 
 	// Enables read-only commands on slave nodes.
+	// Module commands registered with RegisterReadOnlyCommands are read-only too.
 	ReadOnly bool
 	// Allows routing read-only commands to the closest master or slave node.
 	// It automatically enables ReadOnly.
@@ -48,6 +50,13 @@ type ClusterOptions struct {
 	// Allows routing read-only commands to the random master or slave node.
 	// It automatically enables ReadOnly.
 	RouteRandomly bool
+	// Optional function choosing the node of the slot which a read-only
+	// command is routed to, e.g. the closest one by availability zone with the
+	// labels of the nodes. nodes starts with the master of the slot, and the
+	// command is routed to the master when the returned node is not one of
+	// them. It takes precedence over RouteByLatency and RouteRandomly, and
+	// automatically enables ReadOnly.
+	RouteReadsFunc func(nodes []NodeInfo) NodeInfo
 
 	// Optional function that returns cluster slots information.
 	// It is useful to manually create cluster of standalone Redis servers
@@ -62,12 +71,30 @@ type ClusterOptions struct {
 
 	OnConnect func(ctx context.Context, cn *Conn) error
 
+	// OnConnectionEvent is called with the events of the connections, see
+	// Options.OnConnectionEvent.
+	OnConnectionEvent func(ev ConnEvent)
+	// OnConnectionUse is called with the events of the connections taken from
+	// the pools and given back to them, see Options.OnConnectionUse.
+	OnConnectionUse func(ev ConnEvent)
+	// Logger logs the messages of the clients, see Options.Logger.
+	Logger Logger
+	// CircuitBreaker enables a circuit breaker for each node, see
+	// Options.CircuitBreaker.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// OnPush is called with the push notifications received on the
+	// connections of each node, see Options.OnPush.
+	OnPush func(ctx context.Context, msg PushMessage)
+
 	Protocol                   int
 	Username                   string
 	Password                   string
 	CredentialsProvider        func() (username string, password string)
 	CredentialsProviderContext func(ctx context.Context) (username string, password string, err error)
 
+	StreamingCredentialsProvider StreamingCredentialsProvider
+
 	MaxRetries      int
 	MinRetryBackoff time.Duration
 	MaxRetryBackoff time.Duration
@@ -77,6 +104,32 @@ type ClusterOptions struct {
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
 
+	// StatsEnabled enables the statistics of the commands returned by Stats.
+	StatsEnabled bool
+
+	// AutoPipeline batches the commands issued concurrently into pipelines,
+	// see Options.AutoPipeline.
+	AutoPipeline bool
+	// AutoPipelineMaxSize is the maximum number of commands of the pipelines
+	// of AutoPipeline. Default is 100 commands.
+	AutoPipelineMaxSize int
+
+	// PipelineMaxCmds and PipelineMaxBytes split the pipelines in batches,
+	// see Options.PipelineMaxCmds.
+	PipelineMaxCmds  int
+	PipelineMaxBytes int
+
+	// HedgeDelay enables the hedging of the read commands on another
+	// connection of their node, see Options.HedgeDelay.
+	HedgeDelay time.Duration
+	// HedgeCommands are the names of the commands hedged with HedgeDelay.
+	HedgeCommands []string
+
+	// DryRun marshals and logs the commands with their node and hash slot
+	// instead of sending them, see Options.DryRun. The cluster topology is
+	// still loaded to route the commands.
+	DryRun bool
+
 	PoolFIFO        bool
 	PoolSize        int // applies per cluster node and not for the whole cluster
 	PoolTimeout     time.Duration
@@ -85,11 +138,28 @@ type ClusterOptions struct {
 	MaxActiveConns  int // applies per cluster node and not for the whole cluster
 	ConnMaxIdleTime time.Duration
 	ConnMaxLifetime time.Duration
+	ReadBufferSize  int
+	WriteBufferSize int
+	StringInterner  StringInterner
+
+	// AdaptivePoolSize adjusts the size of the pool of each node to its load,
+	// see Options.AdaptivePoolSize.
+	AdaptivePoolSize bool
 
-	TLSConfig        *tls.Config
-	DisableIndentity bool // Disable set-lib on connect. Default is false.
+	TLSConfig           *tls.Config
+	TLSFiles            *TLSFiles
+	TLSVerifyConnection func(cs tls.ConnectionState) error
+	TLSServerSPIFFEIDs  []string
+	DisableIndentity    bool // Disable set-lib on connect. Default is false.
 
 	IdentitySuffix string // Add suffix to client name. Default is empty.
+
+	JSONCodec JSONCodec
+}
+
+// measureLatency reports whether the latencies of the nodes are measured.
+func (opt *ClusterOptions) measureLatency() bool {
+	return opt.RouteByLatency || opt.RouteReadsFunc != nil
 }
 
 func (opt *ClusterOptions) init() {
@@ -99,7 +169,7 @@ func (opt *ClusterOptions) init() {
 		opt.MaxRedirects = 3
 	}
 
-	if opt.RouteByLatency || opt.RouteRandomly {
+	if opt.RouteByLatency || opt.RouteRandomly || opt.RouteReadsFunc != nil {
 		opt.ReadOnly = true
 	}
 
@@ -148,6 +218,9 @@ func (opt *ClusterOptions) init() {
 //	or
 //	rediss://<user>:<password>@<host>:<port>
 //
+// The redis-cluster and rediss-cluster schemes are accepted as well, e.g. to tell
+// cluster URLs from the URLs of ParseURL.
+//
 // To add additional addresses, specify the query parameter, "addr" one or more times. e.g:
 //
 //	redis://<user>:<password>@<host>:<port>?addr=<host2>:<port2>&addr=<host3>:<port3>
@@ -165,6 +238,8 @@ func (opt *ClusterOptions) init() {
 //   - fields "network", "addr", "username" and "password" can only be set using other
 //     URL attributes (scheme, host, userinfo, resp.), query parameters using these
 //     names will be treated as unknown parameters
+//   - DisableIndentity is set with disable_identity
+//   - skip_verify=true disables the verification of the server certificates of rediss URLs
 //   - unknown parameter names will result in an error
 //
 // Example:
@@ -177,12 +252,17 @@ func (opt *ClusterOptions) init() {
 //		ReadTimeout: 6 * time.Second,
 //	}
 func ParseClusterURL(redisURL string) (*ClusterOptions, error) {
-	o := &ClusterOptions{}
-
 	u, err := url.Parse(redisURL)
 	if err != nil {
 		return nil, err
 	}
+	return parseClusterURL(u, nil)
+}
+
+// parseClusterURL parses u into ClusterOptions; names are the names of the
+// query parameters in the errors, see queryOptions.
+func parseClusterURL(u *url.URL, names map[string]string) (*ClusterOptions, error) {
+	o := &ClusterOptions{}
 
 	// add base URL to the array of addresses
 	// more addresses may be added through the URL params
@@ -190,7 +270,7 @@ func ParseClusterURL(redisURL string) (*ClusterOptions, error) {
 	o.Addrs = append(o.Addrs, net.JoinHostPort(h, p))
 
 	// setup username, password, and other configurations
-	o, err = setupClusterConn(u, h, o)
+	o, err := setupClusterConn(u, h, o, names)
 	if err != nil {
 		return nil, err
 	}
@@ -199,19 +279,19 @@ func ParseClusterURL(redisURL string) (*ClusterOptions, error) {
 }
 
 // setupClusterConn gets the username and password from the URL and the query parameters.
-func setupClusterConn(u *url.URL, host string, o *ClusterOptions) (*ClusterOptions, error) {
+func setupClusterConn(u *url.URL, host string, o *ClusterOptions, names map[string]string) (*ClusterOptions, error) {
 	switch u.Scheme {
-	case "rediss":
+	case "rediss", "rediss-cluster":
 		o.TLSConfig = &tls.Config{ServerName: host}
 		fallthrough
-	case "redis":
+	case "redis", "redis-cluster":
 		o.Username, o.Password = getUserPassword(u)
 	default:
 		return nil, fmt.Errorf("redis: invalid URL scheme: %s", u.Scheme)
 	}
 
 	// retrieve the configuration from the query parameters
-	o, err := setupClusterQueryParams(u, o)
+	o, err := setupClusterQueryParams(u, o, names)
 	if err != nil {
 		return nil, err
 	}
@@ -220,8 +300,8 @@ func setupClusterConn(u *url.URL, host string, o *ClusterOptions) (*ClusterOptio
 }
 
 // setupClusterQueryParams converts query parameters in u to option value in o.
-func setupClusterQueryParams(u *url.URL, o *ClusterOptions) (*ClusterOptions, error) {
-	q := queryOptions{q: u.Query()}
+func setupClusterQueryParams(u *url.URL, o *ClusterOptions, names map[string]string) (*ClusterOptions, error) {
+	q := queryOptions{q: u.Query(), names: names}
 
 	o.Protocol = q.int("protocol")
 	o.ClientName = q.string("client_name")
@@ -237,12 +317,26 @@ func setupClusterQueryParams(u *url.URL, o *ClusterOptions) (*ClusterOptions, er
 	o.WriteTimeout = q.duration("write_timeout")
 	o.PoolFIFO = q.bool("pool_fifo")
 	o.PoolSize = q.int("pool_size")
+	o.AdaptivePoolSize = q.bool("adaptive_pool_size")
 	o.MinIdleConns = q.int("min_idle_conns")
 	o.MaxIdleConns = q.int("max_idle_conns")
 	o.MaxActiveConns = q.int("max_active_conns")
+	o.ReadBufferSize = q.int("read_buffer_size")
+	o.WriteBufferSize = q.int("write_buffer_size")
 	o.PoolTimeout = q.duration("pool_timeout")
 	o.ConnMaxLifetime = q.duration("conn_max_lifetime")
 	o.ConnMaxIdleTime = q.duration("conn_max_idle_time")
+	o.ContextTimeoutEnabled = q.bool("context_timeout_enabled")
+	o.DisableIndentity = q.disableIdentity()
+	o.IdentitySuffix = q.string("identity_suffix")
+	o.StatsEnabled = q.bool("stats_enabled")
+	o.AutoPipeline = q.bool("auto_pipeline")
+	o.AutoPipelineMaxSize = q.int("auto_pipeline_max_size")
+	o.PipelineMaxCmds = q.int("pipeline_max_cmds")
+	o.PipelineMaxBytes = q.int("pipeline_max_bytes")
+	o.HedgeDelay = q.duration("hedge_delay")
+	o.DryRun = q.bool("dry_run")
+	q.tls(o.TLSConfig)
 
 	if q.err != nil {
 		return nil, q.err
@@ -269,9 +363,14 @@ func setupClusterQueryParams(u *url.URL, o *ClusterOptions) (*ClusterOptions, er
 
 func (opt *ClusterOptions) clientOptions() *Options {
 	return &Options{
-		ClientName: opt.ClientName,
-		Dialer:     opt.Dialer,
-		OnConnect:  opt.OnConnect,
+		ClientName:        opt.ClientName,
+		Dialer:            opt.Dialer,
+		OnConnect:         opt.OnConnect,
+		OnConnectionEvent: opt.OnConnectionEvent,
+		OnConnectionUse:   opt.OnConnectionUse,
+		Logger:            opt.Logger,
+		CircuitBreaker:    opt.CircuitBreaker,
+		OnPush:            opt.OnPush,
 
 		Protocol:                   opt.Protocol,
 		Username:                   opt.Username,
@@ -279,6 +378,8 @@ func (opt *ClusterOptions) clientOptions() *Options {
 		CredentialsProvider:        opt.CredentialsProvider,
 		CredentialsProviderContext: opt.CredentialsProviderContext,
 
+		StreamingCredentialsProvider: opt.StreamingCredentialsProvider,
+
 		MaxRetries:      opt.MaxRetries,
 		MinRetryBackoff: opt.MinRetryBackoff,
 		MaxRetryBackoff: opt.MaxRetryBackoff,
@@ -287,18 +388,33 @@ func (opt *ClusterOptions) clientOptions() *Options {
 		ReadTimeout:           opt.ReadTimeout,
 		WriteTimeout:          opt.WriteTimeout,
 		ContextTimeoutEnabled: opt.ContextTimeoutEnabled,
-
-		PoolFIFO:         opt.PoolFIFO,
-		PoolSize:         opt.PoolSize,
-		PoolTimeout:      opt.PoolTimeout,
-		MinIdleConns:     opt.MinIdleConns,
-		MaxIdleConns:     opt.MaxIdleConns,
-		MaxActiveConns:   opt.MaxActiveConns,
-		ConnMaxIdleTime:  opt.ConnMaxIdleTime,
-		ConnMaxLifetime:  opt.ConnMaxLifetime,
-		DisableIndentity: opt.DisableIndentity,
-		IdentitySuffix:   opt.IdentitySuffix,
-		TLSConfig:        opt.TLSConfig,
+		StatsEnabled:          opt.StatsEnabled,
+		AutoPipeline:          opt.AutoPipeline,
+		AutoPipelineMaxSize:   opt.AutoPipelineMaxSize,
+		PipelineMaxCmds:       opt.PipelineMaxCmds,
+		PipelineMaxBytes:      opt.PipelineMaxBytes,
+		HedgeDelay:            opt.HedgeDelay,
+		HedgeCommands:         opt.HedgeCommands,
+
+		PoolFIFO:            opt.PoolFIFO,
+		PoolSize:            opt.PoolSize,
+		PoolTimeout:         opt.PoolTimeout,
+		MinIdleConns:        opt.MinIdleConns,
+		MaxIdleConns:        opt.MaxIdleConns,
+		MaxActiveConns:      opt.MaxActiveConns,
+		ConnMaxIdleTime:     opt.ConnMaxIdleTime,
+		ConnMaxLifetime:     opt.ConnMaxLifetime,
+		ReadBufferSize:      opt.ReadBufferSize,
+		WriteBufferSize:     opt.WriteBufferSize,
+		StringInterner:      opt.StringInterner,
+		AdaptivePoolSize:    opt.AdaptivePoolSize,
+		DisableIndentity:    opt.DisableIndentity,
+		IdentitySuffix:      opt.IdentitySuffix,
+		JSONCodec:           opt.JSONCodec,
+		TLSConfig:           opt.TLSConfig,
+		TLSFiles:            opt.TLSFiles,
+		TLSVerifyConnection: opt.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  opt.TLSServerSPIFFEIDs,
 		// If ClusterSlots is populated, then we probably have an artificial
 		// cluster whose nodes are not in clustering mode (otherwise there isn't
 		// much use for ClusterSlots config).  This means we cannot execute the
@@ -310,12 +426,27 @@ func (opt *ClusterOptions) clientOptions() *Options {
 
 //------------------------------------------------------------------------------
 
+// NodeInfo describes a node of a slot for ClusterOptions.RouteReadsFunc.
+type NodeInfo struct {
+	Addr   string
+	Master bool
+	// Latency is the average latency of the PINGs to the node, measured
+	// periodically with RouteByLatency or RouteReadsFunc.
+	Latency time.Duration
+	// Failing reports whether a command failed on the node recently.
+	Failing bool
+	// Labels are the ClusterNode.NetworkingMetadata of the node, e.g. as
+	// returned by ClusterOptions.ClusterSlots.
+	Labels map[string]string
+}
+
 type clusterNode struct {
 	Client *Client
 
 	latency    uint32 // atomic
 	generation uint32 // atomic
 	failing    uint32 // atomic
+	labels     atomic.Value
 }
 
 func newClusterNode(clOpt *ClusterOptions, addr string) *clusterNode {
@@ -326,7 +457,7 @@ func newClusterNode(clOpt *ClusterOptions, addr string) *clusterNode {
 	}
 
 	node.latency = math.MaxUint32
-	if clOpt.RouteByLatency {
+	if clOpt.measureLatency() {
 		go node.updateLatency()
 	}
 
@@ -391,6 +522,15 @@ func (n *clusterNode) Failing() bool {
 	return false
 }
 
+func (n *clusterNode) Labels() map[string]string {
+	labels, _ := n.labels.Load().(map[string]string)
+	return labels
+}
+
+func (n *clusterNode) SetLabels(labels map[string]string) {
+	n.labels.Store(labels)
+}
+
 func (n *clusterNode) Generation() uint32 {
 	return atomic.LoadUint32(&n.generation)
 }
@@ -494,7 +634,7 @@ func (c *clusterNodes) GC(generation uint32) {
 	for addr, node := range c.nodes {
 		if node.Generation() >= generation {
 			c.activeAddrs = append(c.activeAddrs, addr)
-			if c.opt.RouteByLatency {
+			if c.opt.measureLatency() {
 				go node.updateLatency()
 			}
 			continue
@@ -642,6 +782,7 @@ func newClusterState(
 			}
 
 			node.SetGeneration(c.generation)
+			node.SetLabels(slotNode.NetworkingMetadata)
 			nodes = append(nodes, node)
 
 			if i == 0 {
@@ -769,6 +910,31 @@ func (c *clusterState) slotRandomNode(slot int) (*clusterNode, error) {
 	return nodes[randomNodes[0]], nil
 }
 
+func (c *clusterState) slotRoutedNode(slot int, route func([]NodeInfo) NodeInfo) (*clusterNode, error) {
+	nodes := c.slotNodes(slot)
+	if len(nodes) == 0 {
+		return c.nodes.Random()
+	}
+
+	infos := make([]NodeInfo, len(nodes))
+	for i, node := range nodes {
+		infos[i] = NodeInfo{
+			Addr:    node.Client.opt.Addr,
+			Master:  i == 0,
+			Latency: node.Latency(),
+			Failing: node.Failing(),
+			Labels:  node.Labels(),
+		}
+	}
+	addr := route(infos).Addr
+	for _, node := range nodes {
+		if node.Client.opt.Addr == addr {
+			return node, nil
+		}
+	}
+	return nodes[0], nil
+}
+
 func (c *clusterState) slotNodes(slot int) []*clusterNode {
 	i := sort.Search(len(c.slots), func(i int) bool {
 		return c.slots[i].end >= slot
@@ -933,6 +1099,13 @@ func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
 			}
 		}
 
+		if c.opt.DryRun {
+			return dryRun(ctx, node.Client.opt.Logger, cmd, node.Client.getAddr(), true)
+		}
+		if attempt > 0 && node.Client.stats != nil {
+			node.Client.stats.recordRetry(cmd)
+		}
+
 		if ask {
 			ask = false
 
@@ -956,8 +1129,8 @@ func (c *ClusterClient) process(ctx context.Context, cmd Cmder) error {
 			continue
 		}
 
-		// If slave is loading - pick another node.
-		if c.opt.ReadOnly && isLoadingError(lastErr) {
+		// If slave is loading or its circuit breaker is open - pick another node.
+		if c.opt.ReadOnly && (isLoadingError(lastErr) || errors.Is(lastErr, ErrCircuitOpen)) {
 			node.MarkAsFailing()
 			node = nil
 			continue
@@ -1132,7 +1305,10 @@ func (c *ClusterClient) PoolStats() *PoolStats {
 		acc.Hits += s.Hits
 		acc.Misses += s.Misses
 		acc.Timeouts += s.Timeouts
+		acc.WaitCount += s.WaitCount
+		acc.WaitDurationNs += s.WaitDurationNs
 
+		acc.PoolSize += s.PoolSize
 		acc.TotalConns += s.TotalConns
 		acc.IdleConns += s.IdleConns
 		acc.StaleConns += s.StaleConns
@@ -1143,7 +1319,10 @@ func (c *ClusterClient) PoolStats() *PoolStats {
 		acc.Hits += s.Hits
 		acc.Misses += s.Misses
 		acc.Timeouts += s.Timeouts
+		acc.WaitCount += s.WaitCount
+		acc.WaitDurationNs += s.WaitDurationNs
 
+		acc.PoolSize += s.PoolSize
 		acc.TotalConns += s.TotalConns
 		acc.IdleConns += s.IdleConns
 		acc.StaleConns += s.StaleConns
@@ -1283,18 +1462,30 @@ func (c *ClusterClient) mapCmdsByNode(ctx context.Context, cmdsMap *cmdsMap, cmd
 
 func (c *ClusterClient) cmdsAreReadOnly(ctx context.Context, cmds []Cmder) bool {
 	for _, cmd := range cmds {
-		cmdInfo := c.cmdInfo(ctx, cmd.Name())
-		if cmdInfo == nil || !cmdInfo.ReadOnly {
+		if !c.cmdIsReadOnly(ctx, cmd.Name()) {
 			return false
 		}
 	}
 	return true
 }
 
+// cmdIsReadOnly reports whether the command may be sent to a replica.
+func (c *ClusterClient) cmdIsReadOnly(ctx context.Context, name string) bool {
+	if isRegisteredReadOnly(name) {
+		return true
+	}
+	cmdInfo := c.cmdInfo(ctx, name)
+	return cmdInfo != nil && cmdInfo.ReadOnly
+}
+
 func (c *ClusterClient) processPipelineNode(
 	ctx context.Context, node *clusterNode, cmds []Cmder, failedCmds *cmdsMap,
 ) {
 	_ = node.Client.withProcessPipelineHook(ctx, cmds, func(ctx context.Context, cmds []Cmder) error {
+		if c.opt.DryRun {
+			return dryRunCmds(ctx, node.Client.opt.Logger, cmds, node.Client.getAddr(), true)
+		}
+
 		cn, err := node.Client.getConn(ctx)
 		if err != nil {
 			node.MarkAsFailing()
@@ -1316,22 +1507,39 @@ func (c *ClusterClient) processPipelineNode(
 func (c *ClusterClient) processPipelineNodeConn(
 	ctx context.Context, node *clusterNode, cn *pool.Conn, cmds []Cmder, failedCmds *cmdsMap,
 ) error {
-	if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
-		return writeCmds(wr, cmds)
-	}); err != nil {
-		if isBadConn(err, false, node.Client.getAddr()) {
-			node.MarkAsFailing()
+	for len(cmds) > 0 {
+		var n int
+		if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
+			var err error
+			n, err = writeCmdsBatch(wr, cmds, c.opt.PipelineMaxCmds, c.opt.PipelineMaxBytes)
+			return err
+		}); err != nil {
+			if isBadConn(err, false, node.Client.getAddr()) {
+				node.MarkAsFailing()
+			}
+			if shouldRetry(err, true) {
+				_ = c.mapCmdsByNode(ctx, failedCmds, cmds)
+			}
+			setCmdsErr(cmds, err)
+			return err
 		}
-		if shouldRetry(err, true) {
-			_ = c.mapCmdsByNode(ctx, failedCmds, cmds)
+
+		// the commands of the previous batches are not retried
+		batch, rest := cmds[:n], cmds[n:]
+		if err := cn.WithReader(c.context(ctx), c.opt.ReadTimeout, func(rd *proto.Reader) error {
+			return c.pipelineReadCmds(ctx, node, rd, batch, failedCmds)
+		}); err != nil {
+			if len(rest) > 0 {
+				if shouldRetry(err, true) {
+					_ = c.mapCmdsByNode(ctx, failedCmds, rest)
+				}
+				setCmdsErr(rest, err)
+			}
+			return err
 		}
-		setCmdsErr(cmds, err)
-		return err
+		cmds = rest
 	}
-
-	return cn.WithReader(c.context(ctx), c.opt.ReadTimeout, func(rd *proto.Reader) error {
-		return c.pipelineReadCmds(ctx, node, rd, cmds, failedCmds)
-	})
+	return nil
 }
 
 func (c *ClusterClient) pipelineReadCmds(
@@ -1342,7 +1550,7 @@ func (c *ClusterClient) pipelineReadCmds(
 	failedCmds *cmdsMap,
 ) error {
 	for i, cmd := range cmds {
-		err := cmd.readReply(rd)
+		err := node.Client.pushes.readReply(ctx, rd, cmd)
 		cmd.SetErr(err)
 
 		if err == nil {
@@ -1480,6 +1688,10 @@ func (c *ClusterClient) processTxPipelineNode(
 ) {
 	cmds = wrapMultiExec(ctx, cmds)
 	_ = node.Client.withProcessPipelineHook(ctx, cmds, func(ctx context.Context, cmds []Cmder) error {
+		if c.opt.DryRun {
+			return dryRunCmds(ctx, node.Client.opt.Logger, cmds, node.Client.getAddr(), true)
+		}
+
 		cn, err := node.Client.getConn(ctx)
 		if err != nil {
 			_ = c.mapCmdsByNode(ctx, failedCmds, cmds)
@@ -1516,7 +1728,7 @@ func (c *ClusterClient) processTxPipelineNodeConn(
 		trimmedCmds := cmds[1 : len(cmds)-1]
 
 		if err := c.txPipelineReadQueued(
-			ctx, rd, statusCmd, trimmedCmds, failedCmds,
+			ctx, node.Client.pushes, rd, statusCmd, trimmedCmds, failedCmds,
 		); err != nil {
 			setCmdsErr(cmds, err)
 
@@ -1528,24 +1740,25 @@ func (c *ClusterClient) processTxPipelineNodeConn(
 			return err
 		}
 
-		return pipelineReadCmds(rd, trimmedCmds)
+		return pipelineReadCmds(ctx, node.Client.pushes, rd, trimmedCmds)
 	})
 }
 
 func (c *ClusterClient) txPipelineReadQueued(
 	ctx context.Context,
+	pushes *pushProcessor,
 	rd *proto.Reader,
 	statusCmd *StatusCmd,
 	cmds []Cmder,
 	failedCmds *cmdsMap,
 ) error {
 	// Parse queued replies.
-	if err := statusCmd.readReply(rd); err != nil {
+	if err := pushes.readReply(ctx, rd, statusCmd); err != nil {
 		return err
 	}
 
 	for _, cmd := range cmds {
-		err := statusCmd.readReply(rd)
+		err := pushes.readReply(ctx, rd, statusCmd)
 		if err == nil || c.checkMovedErr(ctx, cmd, err, failedCmds) || isRedisError(err) {
 			continue
 		}
@@ -1553,6 +1766,9 @@ func (c *ClusterClient) txPipelineReadQueued(
 	}
 
 	// Parse number of replies.
+	if err := pushes.process(ctx, rd); err != nil {
+		return err
+	}
 	line, err := rd.ReadLine()
 	if err != nil {
 		if err == Nil {
@@ -1776,13 +1992,13 @@ func (c *ClusterClient) cmdsInfo(ctx context.Context) (map[string]*CommandInfo,
 func (c *ClusterClient) cmdInfo(ctx context.Context, name string) *CommandInfo {
 	cmdsInfo, err := c.cmdsInfoCache.Get(ctx)
 	if err != nil {
-		internal.Logger.Printf(context.TODO(), "getting command info: %s", err)
+		internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "redis: getting command info", "error", err)
 		return nil
 	}
 
 	info := cmdsInfo[name]
 	if info == nil {
-		internal.Logger.Printf(context.TODO(), "info for cmd=%s not found", name)
+		internal.Log(ctx, c.opt.Logger, internal.LevelDebug, "redis: command info not found", "cmd", name)
 	}
 	return info
 }
@@ -1814,16 +2030,16 @@ func (c *ClusterClient) cmdNode(
 		return nil, err
 	}
 
-	if c.opt.ReadOnly {
-		cmdInfo := c.cmdInfo(ctx, cmdName)
-		if cmdInfo != nil && cmdInfo.ReadOnly {
-			return c.slotReadOnlyNode(state, slot)
-		}
+	if c.opt.ReadOnly && c.cmdIsReadOnly(ctx, cmdName) {
+		return c.slotReadOnlyNode(state, slot)
 	}
 	return state.slotMasterNode(slot)
 }
 
 func (c *ClusterClient) slotReadOnlyNode(state *clusterState, slot int) (*clusterNode, error) {
+	if c.opt.RouteReadsFunc != nil {
+		return state.slotRoutedNode(slot, c.opt.RouteReadsFunc)
+	}
 	if c.opt.RouteByLatency {
 		return state.slotClosestNode(slot)
 	}
@@ -2,8 +2,11 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
+
+	"github.com/redis/go-redis/v9/internal/hashtag"
 )
 
 func (c *ClusterClient) DBSize(ctx context.Context) *IntCmd {
@@ -107,3 +110,138 @@ func (c *ClusterClient) ScriptExists(ctx context.Context, hashes ...string) *Boo
 	})
 	return cmd
 }
+
+// slotKeys groups the positions of keys by hash slot, in the order of their
+// first key.
+func slotKeys(keys []string) [][]int {
+	var groups [][]int
+	bySlot := make(map[int]int)
+	for i, key := range keys {
+		slot := hashtag.Slot(key)
+		g, ok := bySlot[slot]
+		if !ok {
+			g = len(groups)
+			bySlot[slot] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// MGetAll is MGET for keys of any hash slots: the keys are split by slot,
+// their MGET are pipelined to the nodes of the slots concurrently, and the
+// values are returned in the order of keys. Unlike MGET it is not atomic.
+func (c *ClusterClient) MGetAll(ctx context.Context, keys ...string) *SliceCmd {
+	args := make([]interface{}, 1+len(keys))
+	args[0] = "mget"
+	for i, key := range keys {
+		args[1+i] = key
+	}
+	cmd := NewSliceCmd(ctx, args...)
+
+	_ = c.withProcessHook(ctx, cmd, func(ctx context.Context, _ Cmder) error {
+		groups := slotKeys(keys)
+		cmds := make([]*SliceCmd, len(groups))
+		_, err := c.Pipelined(ctx, func(pipe Pipeliner) error {
+			for g, group := range groups {
+				groupKeys := make([]string, len(group))
+				for i, pos := range group {
+					groupKeys[i] = keys[pos]
+				}
+				cmds[g] = pipe.MGet(ctx, groupKeys...)
+			}
+			return nil
+		})
+
+		vals := make([]interface{}, len(keys))
+		for g, group := range groups {
+			for i, val := range cmds[g].Val() {
+				vals[group[i]] = val
+			}
+		}
+		cmd.val = vals
+		cmd.SetErr(err)
+		return nil
+	})
+	return cmd
+}
+
+var errMSetAllArgs = errors.New("redis: MSetAll requires pairs of keys and values")
+
+// MSetAll is MSET for keys of any hash slots, with the values of MSet: the
+// pairs are split by the slot of their key, and their MSET are pipelined to
+// the nodes of the slots concurrently. Unlike MSET it is not atomic, some
+// pairs may be set when it fails.
+func (c *ClusterClient) MSetAll(ctx context.Context, values ...interface{}) *StatusCmd {
+	args := make([]interface{}, 1, 1+len(values))
+	args[0] = "mset"
+	args = appendArgs(args, values)
+	cmd := NewStatusCmd(ctx, args...)
+
+	_ = c.withProcessHook(ctx, cmd, func(ctx context.Context, _ Cmder) error {
+		pairs := args[1:]
+		if len(pairs)%2 != 0 {
+			cmd.SetErr(errMSetAllArgs)
+			return nil
+		}
+		keys := make([]string, len(pairs)/2)
+		for i := range keys {
+			keys[i] = cmd.stringArg(1 + 2*i)
+		}
+
+		_, err := c.Pipelined(ctx, func(pipe Pipeliner) error {
+			for _, group := range slotKeys(keys) {
+				groupPairs := make([]interface{}, 0, 2*len(group))
+				for _, pos := range group {
+					groupPairs = append(groupPairs, pairs[2*pos], pairs[2*pos+1])
+				}
+				pipe.MSet(ctx, groupPairs...)
+			}
+			return nil
+		})
+		if err != nil {
+			cmd.SetErr(err)
+		} else {
+			cmd.val = "OK"
+		}
+		return nil
+	})
+	return cmd
+}
+
+// DelAll is DEL for keys of any hash slots: the keys are split by slot,
+// their DEL are pipelined to the nodes of the slots concurrently, and it
+// returns the number of keys deleted. Unlike DEL it is not atomic.
+func (c *ClusterClient) DelAll(ctx context.Context, keys ...string) *IntCmd {
+	args := make([]interface{}, 1+len(keys))
+	args[0] = "del"
+	for i, key := range keys {
+		args[1+i] = key
+	}
+	cmd := NewIntCmd(ctx, args...)
+
+	_ = c.withProcessHook(ctx, cmd, func(ctx context.Context, _ Cmder) error {
+		groups := slotKeys(keys)
+		cmds := make([]*IntCmd, len(groups))
+		_, err := c.Pipelined(ctx, func(pipe Pipeliner) error {
+			for g, group := range groups {
+				groupKeys := make([]string, len(group))
+				for i, pos := range group {
+					groupKeys[i] = keys[pos]
+				}
+				cmds[g] = pipe.Del(ctx, groupKeys...)
+			}
+			return nil
+		})
+
+		var n int64
+		for _, delCmd := range cmds {
+			n += delCmd.Val()
+		}
+		cmd.val = n
+		cmd.SetErr(err)
+		return nil
+	})
+	return cmd
+}
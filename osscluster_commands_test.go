@@ -0,0 +1,120 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/internal/hashtag"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// slotsHook fails the commands whose keys are in different hash slots, as
+// the redistest servers accept them.
+type slotsHook struct{}
+
+func (slotsHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (slotsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (slotsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			args := cmd.Args()
+			step := 1
+			if cmd.Name() == "mset" {
+				step = 2
+			}
+			for i := 1 + step; i < len(args); i += step {
+				if hashtag.Slot(args[i].(string)) != hashtag.Slot(args[1].(string)) {
+					err := errors.New("CROSSSLOT Keys in request don't hash to the same slot")
+					for _, cmd := range cmds {
+						cmd.SetErr(err)
+					}
+					return err
+				}
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func TestClusterMultiKey(t *testing.T) {
+	srv1, srv2 := redistest.NewServer(), redistest.NewServer()
+	defer srv1.Close()
+	defer srv2.Close()
+
+	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{
+				{Start: 0, End: 8191, Nodes: []redis.ClusterNode{{Addr: srv1.Addr()}}},
+				{Start: 8192, End: 16383, Nodes: []redis.ClusterNode{{Addr: srv2.Addr()}}},
+			}, nil
+		},
+	})
+	defer rdb.Close()
+	rdb.OnNewNode(func(node *redis.Client) {
+		node.AddHook(slotsHook{})
+	})
+
+	var keys []string
+	var values []interface{}
+	for i := 0; i < 20; i++ {
+		key := "key" + strconv.Itoa(i)
+		keys = append(keys, key)
+		values = append(values, key, "value"+strconv.Itoa(i))
+	}
+	keys = append(keys, "{key1}.other")
+	values = append(values, "{key1}.other", "other")
+
+	if _, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.MGet(ctx, keys...)
+		return nil
+	}); err == nil {
+		t.Fatal("MGET of keys of different slots succeeded")
+	}
+
+	if err := rdb.MSetAll(ctx, values...).Err(); err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys {
+		srv := srv1
+		if hashtag.Slot(key) > 8191 {
+			srv = srv2
+		}
+		if val, ok := srv.Get(key); !ok || val != values[2*i+1] {
+			t.Fatalf("got %q for %s, wanted %q", val, key, values[2*i+1])
+		}
+	}
+
+	vals, err := rdb.MGetAll(ctx, append(keys, "missing")...).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]interface{}, 0, len(keys)+1)
+	for i := range keys {
+		want = append(want, values[2*i+1])
+	}
+	want = append(want, nil)
+	if !reflect.DeepEqual(vals, want) {
+		t.Fatalf("got %v, wanted %v", vals, want)
+	}
+
+	n, err := rdb.DelAll(ctx, append(keys, "missing")...).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(keys)) {
+		t.Fatalf("deleted %d keys, wanted %d", n, len(keys))
+	}
+	if len(srv1.Keys())+len(srv2.Keys()) != 0 {
+		t.Fatalf("got keys %q and %q", srv1.Keys(), srv2.Keys())
+	}
+
+	if err := rdb.MSetAll(ctx, "key").Err(); err == nil {
+		t.Fatal("MSetAll succeeded without value")
+	}
+}
@@ -0,0 +1,115 @@
+package redis_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// writesConn counts the writes of a connection.
+type writesConn struct {
+	net.Conn
+	writes *int32
+}
+
+func (cn writesConn) Write(b []byte) (int, error) {
+	atomic.AddInt32(cn.writes, 1)
+	return cn.Conn.Write(b)
+}
+
+func TestPipelineBatches(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	newClient := func(maxCmds, maxBytes int) (*redis.Client, *int32) {
+		var writes int32
+		rdb := redis.NewClient(&redis.Options{
+			Addr:             srv.Addr(),
+			PoolSize:         1,
+			PipelineMaxCmds:  maxCmds,
+			PipelineMaxBytes: maxBytes,
+			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				cn, err := net.Dial(network, addr)
+				if err != nil {
+					return nil, err
+				}
+				return writesConn{Conn: cn, writes: &writes}, nil
+			},
+		})
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			t.Fatal(err)
+		}
+		return rdb, &writes
+	}
+
+	exec := func(rdb *redis.Client, n int, value string) []redis.Cmder {
+		t.Helper()
+		cmds, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i := 0; i < n; i++ {
+				pipe.Set(ctx, "key"+strconv.Itoa(i), value, 0)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cmds) != n {
+			t.Fatalf("got %d commands, wanted %d", len(cmds), n)
+		}
+		return cmds
+	}
+
+	for _, tc := range []struct {
+		name              string
+		maxCmds, maxBytes int
+		value             string
+		writes            int32
+	}{
+		{name: "unlimited", value: "v", writes: 1},
+		{name: "commands", maxCmds: 10, value: "v", writes: 10},
+		{name: "bytes", maxBytes: 1000, value: strings.Repeat("v", 100), writes: 12},
+		{name: "both", maxCmds: 50, maxBytes: 10000, value: "v", writes: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, writes := newClient(tc.maxCmds, tc.maxBytes)
+			defer rdb.Close()
+
+			atomic.StoreInt32(writes, 0)
+			exec(rdb, 95, tc.value)
+			if got := atomic.LoadInt32(writes); got != tc.writes {
+				t.Fatalf("got %d writes, wanted %d", got, tc.writes)
+			}
+			if v, ok := srv.Get("key94"); !ok || v != tc.value {
+				t.Fatalf("got %q, wanted %q", v, tc.value)
+			}
+		})
+	}
+
+	// the Redis errors of the batches do not stop the next ones
+	rdb, _ := newClient(1, 0)
+	defer rdb.Close()
+	if err := rdb.Set(ctx, "str", "a", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	cmds, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "x", "1", 0)
+		pipe.Incr(ctx, "str")
+		pipe.Get(ctx, "x")
+		return nil
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "ERR ") {
+		t.Fatalf("got %v, wanted the error of INCR", err)
+	}
+	if cmds[1].Err() == nil {
+		t.Fatal("INCR succeeded")
+	}
+	if got := cmds[2].(*redis.StringCmd).Val(); got != "1" {
+		t.Fatalf("got %q, wanted 1", got)
+	}
+}
@@ -2,7 +2,9 @@ package redis
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/redis/go-redis/v9/internal/proto"
 )
@@ -722,6 +724,227 @@ func (c cmdable) CFMExists(ctx context.Context, key string, elements ...interfac
 	return cmd
 }
 
+// -------------------------------------------
+// Bulk insert helpers
+
+const defaultBulkInsertChunkSize = 1000
+
+// BFInsertBulk adds a large number of items to a Bloom filter with pipelined
+// commands of at most chunkSize items each (1000 by default), so the replies
+// and the requests stay bounded. The items are sent with BF.MADD, or with
+// BF.INSERT when options are given, which creates the filter with them unless
+// it already exists.
+// The returned slice holds one boolean per item in input order that is true
+// when the item was added.
+func (c *Client) BFInsertBulk(ctx context.Context, key string, chunkSize int, options *BFInsertOptions, items ...interface{}) ([]bool, error) {
+	return bfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+// BFInsertBulk is like Client.BFInsertBulk, but the pipeline is sent to the node owning the key.
+func (c *ClusterClient) BFInsertBulk(ctx context.Context, key string, chunkSize int, options *BFInsertOptions, items ...interface{}) ([]bool, error) {
+	return bfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+// BFInsertBulk is like Client.BFInsertBulk, but the pipeline is sent to the shard owning the key.
+func (c *Ring) BFInsertBulk(ctx context.Context, key string, chunkSize int, options *BFInsertOptions, items ...interface{}) ([]bool, error) {
+	return bfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+func bfInsertBulk(ctx context.Context, pipe Pipeliner, key string, chunkSize int, options *BFInsertOptions, items []interface{}) ([]bool, error) {
+	return insertBulk(ctx, pipe, chunkSize, items, func(chunk []interface{}) *BoolSliceCmd {
+		if options == nil {
+			return pipe.BFMAdd(ctx, key, chunk...)
+		}
+		return pipe.BFInsert(ctx, key, options, chunk...)
+	})
+}
+
+// CFInsertBulk adds a large number of items to a Cuckoo filter with pipelined
+// CF.INSERT commands of at most chunkSize items each (1000 by default).
+// The filter is created with options unless it already exists.
+// The returned slice holds one boolean per item in input order that is true
+// when the item was added.
+func (c *Client) CFInsertBulk(ctx context.Context, key string, chunkSize int, options *CFInsertOptions, items ...interface{}) ([]bool, error) {
+	return cfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+// CFInsertBulk is like Client.CFInsertBulk, but the pipeline is sent to the node owning the key.
+func (c *ClusterClient) CFInsertBulk(ctx context.Context, key string, chunkSize int, options *CFInsertOptions, items ...interface{}) ([]bool, error) {
+	return cfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+// CFInsertBulk is like Client.CFInsertBulk, but the pipeline is sent to the shard owning the key.
+func (c *Ring) CFInsertBulk(ctx context.Context, key string, chunkSize int, options *CFInsertOptions, items ...interface{}) ([]bool, error) {
+	return cfInsertBulk(ctx, c.Pipeline(), key, chunkSize, options, items)
+}
+
+func cfInsertBulk(ctx context.Context, pipe Pipeliner, key string, chunkSize int, options *CFInsertOptions, items []interface{}) ([]bool, error) {
+	return insertBulk(ctx, pipe, chunkSize, items, func(chunk []interface{}) *BoolSliceCmd {
+		return pipe.CFInsert(ctx, key, options, chunk...)
+	})
+}
+
+func insertBulk(ctx context.Context, pipe Pipeliner, chunkSize int, items []interface{}, insert func(chunk []interface{}) *BoolSliceCmd) ([]bool, error) {
+	if len(items) == 0 {
+		return []bool{}, nil
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultBulkInsertChunkSize
+	}
+
+	cmds := make([]*BoolSliceCmd, 0, (len(items)+chunkSize-1)/chunkSize)
+	for i := 0; i < len(items); i += chunkSize {
+		end := i + chunkSize
+		if end > len(items) {
+			end = len(items)
+		}
+		cmds = append(cmds, insert(items[i:end]))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make([]bool, 0, len(items))
+	for _, cmd := range cmds {
+		res = append(res, cmd.Val()...)
+	}
+	return res, nil
+}
+
+// -------------------------------------------
+// Backup helpers
+
+// BFBackup streams the Bloom filter at key to w, chunk by chunk with
+// BF.SCANDUMP, so the filter is never held in memory. The filter should not
+// be modified during the backup. It is restored with BFRestore.
+func (c *Client) BFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.BFScanDump, key, w)
+}
+
+// BFRestore restores the Bloom filter saved by BFBackup from r at key,
+// which must not exist, chunk by chunk with BF.LOADCHUNK.
+func (c *Client) BFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.BFLoadChunk, key, r)
+}
+
+// CFBackup is like BFBackup for Cuckoo filters.
+func (c *Client) CFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.CFScanDump, key, w)
+}
+
+// CFRestore is like BFRestore for Cuckoo filters.
+func (c *Client) CFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.CFLoadChunk, key, r)
+}
+
+func (c *ClusterClient) BFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.BFScanDump, key, w)
+}
+
+func (c *ClusterClient) BFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.BFLoadChunk, key, r)
+}
+
+func (c *ClusterClient) CFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.CFScanDump, key, w)
+}
+
+func (c *ClusterClient) CFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.CFLoadChunk, key, r)
+}
+
+func (c *Ring) BFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.BFScanDump, key, w)
+}
+
+func (c *Ring) BFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.BFLoadChunk, key, r)
+}
+
+func (c *Ring) CFBackup(ctx context.Context, key string, w io.Writer) error {
+	return scanDumpBackup(ctx, c.CFScanDump, key, w)
+}
+
+func (c *Ring) CFRestore(ctx context.Context, key string, r io.Reader) error {
+	return scanDumpRestore(ctx, c.CFLoadChunk, key, r)
+}
+
+// scanDumpMagic starts the streams written by the backup helpers. The chunks
+// follow, each as its big-endian 8 bytes iterator, 4 bytes length and data,
+// and the stream ends with a zero iterator.
+const scanDumpMagic = "REDISSCANDUMP1"
+
+func scanDumpBackup(
+	ctx context.Context, scanDump func(ctx context.Context, key string, iterator int64) *ScanDumpCmd, key string, w io.Writer,
+) error {
+	if _, err := io.WriteString(w, scanDumpMagic); err != nil {
+		return err
+	}
+
+	var header [12]byte
+	var iter int64
+	for {
+		chunk, err := scanDump(ctx, key, iter).Result()
+		if err != nil {
+			return err
+		}
+		if chunk.Iter == 0 {
+			break
+		}
+
+		binary.BigEndian.PutUint64(header[:8], uint64(chunk.Iter))
+		binary.BigEndian.PutUint32(header[8:], uint32(len(chunk.Data)))
+		if _, err := w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, chunk.Data); err != nil {
+			return err
+		}
+		iter = chunk.Iter
+	}
+
+	binary.BigEndian.PutUint64(header[:8], 0)
+	binary.BigEndian.PutUint32(header[8:], 0)
+	_, err := w.Write(header[:])
+	return err
+}
+
+func scanDumpRestore(
+	ctx context.Context, loadChunk func(ctx context.Context, key string, iterator int64, data interface{}) *StatusCmd, key string, r io.Reader,
+) error {
+	magic := make([]byte, len(scanDumpMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return fmt.Errorf("redis: reading filter backup: %w", err)
+	}
+	if string(magic) != scanDumpMagic {
+		return fmt.Errorf("redis: not a filter backup")
+	}
+
+	var header [12]byte
+	var data []byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return fmt.Errorf("redis: reading filter backup: %w", err)
+		}
+		iter := int64(binary.BigEndian.Uint64(header[:8]))
+		if iter == 0 {
+			return nil
+		}
+
+		n := int(binary.BigEndian.Uint32(header[8:]))
+		if cap(data) < n {
+			data = make([]byte, n)
+		}
+		data = data[:n]
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("redis: reading filter backup: %w", err)
+		}
+		if err := loadChunk(ctx, key, iter, data).Err(); err != nil {
+			return err
+		}
+	}
+}
+
 // -------------------------------------------
 // CMS commands
 //-------------------------------------------
@@ -893,6 +1116,113 @@ func (c cmdable) CMSQuery(ctx context.Context, key string, elements ...interface
 	return cmd
 }
 
+// CMSInitOptions provision a Count-Min Sketch on first use, see CMSIncrByMap.
+// The sketch is created with CMS.INITBYDIM when Width and Depth are set,
+// or with CMS.INITBYPROB when ErrorRate and Probability are set.
+type CMSInitOptions struct {
+	Width       int64
+	Depth       int64
+	ErrorRate   float64
+	Probability float64
+}
+
+// CMSIncrByMap increments the counts of the items of increments in the sketch
+// key and returns their updated counts. When init is given, the sketch is
+// created in the same pipeline unless it already exists.
+func (c *Client) CMSIncrByMap(ctx context.Context, key string, increments map[string]int64, init *CMSInitOptions) (map[string]int64, error) {
+	return cmsIncrByMap(ctx, c.Pipeline(), key, increments, init)
+}
+
+// CMSIncrByMap is like Client.CMSIncrByMap.
+func (c *ClusterClient) CMSIncrByMap(ctx context.Context, key string, increments map[string]int64, init *CMSInitOptions) (map[string]int64, error) {
+	return cmsIncrByMap(ctx, c.Pipeline(), key, increments, init)
+}
+
+// CMSIncrByMap is like Client.CMSIncrByMap.
+func (c *Ring) CMSIncrByMap(ctx context.Context, key string, increments map[string]int64, init *CMSInitOptions) (map[string]int64, error) {
+	return cmsIncrByMap(ctx, c.Pipeline(), key, increments, init)
+}
+
+func cmsIncrByMap(ctx context.Context, pipe Pipeliner, key string, increments map[string]int64, init *CMSInitOptions) (map[string]int64, error) {
+	if len(increments) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	var initCmd *StatusCmd
+	if init != nil {
+		switch {
+		case init.Width > 0 && init.Depth > 0:
+			initCmd = pipe.CMSInitByDim(ctx, key, init.Width, init.Depth)
+		case init.ErrorRate > 0 && init.Probability > 0:
+			initCmd = pipe.CMSInitByProb(ctx, key, init.ErrorRate, init.Probability)
+		default:
+			return nil, fmt.Errorf("redis: CMSInitOptions require Width and Depth or ErrorRate and Probability")
+		}
+	}
+
+	items := make([]string, 0, len(increments))
+	args := make([]interface{}, 0, 2*len(increments))
+	for item, incr := range increments {
+		items = append(items, item)
+		args = append(args, item, incr)
+	}
+	incrCmd := pipe.CMSIncrBy(ctx, key, args...)
+
+	_, _ = pipe.Exec(ctx)
+	if initCmd != nil {
+		if err := initCmd.Err(); err != nil && !isKeyExistsError(err) {
+			return nil, err
+		}
+	}
+	counts, err := incrCmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	return cmsCountsMap(items, counts)
+}
+
+// CMSQueryMany returns the counts of items in the sketch key by item.
+func (c *Client) CMSQueryMany(ctx context.Context, key string, items ...string) (map[string]int64, error) {
+	return cmsQueryMany(ctx, c, key, items)
+}
+
+// CMSQueryMany is like Client.CMSQueryMany.
+func (c *ClusterClient) CMSQueryMany(ctx context.Context, key string, items ...string) (map[string]int64, error) {
+	return cmsQueryMany(ctx, c, key, items)
+}
+
+// CMSQueryMany is like Client.CMSQueryMany.
+func (c *Ring) CMSQueryMany(ctx context.Context, key string, items ...string) (map[string]int64, error) {
+	return cmsQueryMany(ctx, c, key, items)
+}
+
+func cmsQueryMany(ctx context.Context, c ProbabilisticCmdable, key string, items []string) (map[string]int64, error) {
+	if len(items) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	args := make([]interface{}, len(items))
+	for i, item := range items {
+		args[i] = item
+	}
+	counts, err := c.CMSQuery(ctx, key, args...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return cmsCountsMap(items, counts)
+}
+
+func cmsCountsMap(items []string, counts []int64) (map[string]int64, error) {
+	if len(counts) != len(items) {
+		return nil, fmt.Errorf("redis: got %d counts for %d items", len(counts), len(items))
+	}
+	m := make(map[string]int64, len(items))
+	for i, item := range items {
+		m[item] = counts[i]
+	}
+	return m, nil
+}
+
 // -------------------------------------------
 // TopK commands
 //--------------------------------------------
@@ -1427,3 +1757,104 @@ func (c cmdable) TDigestTrimmedMean(ctx context.Context, key string, lowCutQuant
 	_ = c(ctx, cmd)
 	return cmd
 }
+
+// TDigestSummary is a summary of a t-Digest fetched in one round trip, see TDigestSummarize.
+type TDigestSummary struct {
+	Min         float64
+	Max         float64
+	TrimmedMean float64
+	// Quantiles are the estimated values at the quantiles of TDigestSummaryOptions.Quantiles.
+	Quantiles []float64
+	// CDF are the estimated fractions of observations at or below the values of TDigestSummaryOptions.CDF.
+	CDF []float64
+}
+
+type TDigestSummaryOptions struct {
+	Quantiles []float64
+	CDF       []float64
+	// TrimLow and TrimHigh are the cut quantiles of the trimmed mean.
+	// Default is 0 and 1, which is the mean of all the observations.
+	TrimLow  float64
+	TrimHigh float64
+}
+
+// TDigestSummarize returns the min, the max, the trimmed mean and the
+// requested quantiles and CDF points of the t-Digest key with pipelined
+// commands. All the values are NaN when the t-Digest is empty.
+func (c *Client) TDigestSummarize(ctx context.Context, key string, options *TDigestSummaryOptions) (*TDigestSummary, error) {
+	return tdigestSummarize(ctx, c.Pipeline(), key, options, nil)
+}
+
+// TDigestSummarize is like Client.TDigestSummarize.
+func (c *ClusterClient) TDigestSummarize(ctx context.Context, key string, options *TDigestSummaryOptions) (*TDigestSummary, error) {
+	return tdigestSummarize(ctx, c.Pipeline(), key, options, nil)
+}
+
+// TDigestSummarize is like Client.TDigestSummarize.
+func (c *Ring) TDigestSummarize(ctx context.Context, key string, options *TDigestSummaryOptions) (*TDigestSummary, error) {
+	return tdigestSummarize(ctx, c.Pipeline(), key, options, nil)
+}
+
+// TDigestMergeSummarize merges sourceKeys into destKey with TDIGEST.MERGE and
+// summarizes destKey in the same pipeline, see Client.TDigestSummarize.
+func (c *Client) TDigestMergeSummarize(ctx context.Context, destKey string, mergeOptions *TDigestMergeOptions, options *TDigestSummaryOptions, sourceKeys ...string) (*TDigestSummary, error) {
+	return tdigestMergeSummarize(ctx, c.Pipeline(), destKey, mergeOptions, options, sourceKeys)
+}
+
+// TDigestMergeSummarize is like Client.TDigestMergeSummarize.
+// The source keys must hash to the slot of destKey.
+func (c *ClusterClient) TDigestMergeSummarize(ctx context.Context, destKey string, mergeOptions *TDigestMergeOptions, options *TDigestSummaryOptions, sourceKeys ...string) (*TDigestSummary, error) {
+	return tdigestMergeSummarize(ctx, c.Pipeline(), destKey, mergeOptions, options, sourceKeys)
+}
+
+// TDigestMergeSummarize is like Client.TDigestMergeSummarize.
+// The source keys must belong to the shard of destKey.
+func (c *Ring) TDigestMergeSummarize(ctx context.Context, destKey string, mergeOptions *TDigestMergeOptions, options *TDigestSummaryOptions, sourceKeys ...string) (*TDigestSummary, error) {
+	return tdigestMergeSummarize(ctx, c.Pipeline(), destKey, mergeOptions, options, sourceKeys)
+}
+
+func tdigestMergeSummarize(ctx context.Context, pipe Pipeliner, destKey string, mergeOptions *TDigestMergeOptions, options *TDigestSummaryOptions, sourceKeys []string) (*TDigestSummary, error) {
+	return tdigestSummarize(ctx, pipe, destKey, options, func() {
+		pipe.TDigestMerge(ctx, destKey, mergeOptions, sourceKeys...)
+	})
+}
+
+func tdigestSummarize(ctx context.Context, pipe Pipeliner, key string, options *TDigestSummaryOptions, before func()) (*TDigestSummary, error) {
+	if options == nil {
+		options = &TDigestSummaryOptions{}
+	}
+	trimHigh := options.TrimHigh
+	if trimHigh == 0 {
+		trimHigh = 1
+	}
+
+	if before != nil {
+		before()
+	}
+	min := pipe.TDigestMin(ctx, key)
+	max := pipe.TDigestMax(ctx, key)
+	mean := pipe.TDigestTrimmedMean(ctx, key, options.TrimLow, trimHigh)
+	var quantiles, cdf *FloatSliceCmd
+	if len(options.Quantiles) > 0 {
+		quantiles = pipe.TDigestQuantile(ctx, key, options.Quantiles...)
+	}
+	if len(options.CDF) > 0 {
+		cdf = pipe.TDigestCDF(ctx, key, options.CDF...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	summary := &TDigestSummary{
+		Min:         min.Val(),
+		Max:         max.Val(),
+		TrimmedMean: mean.Val(),
+	}
+	if quantiles != nil {
+		summary.Quantiles = quantiles.Val()
+	}
+	if cdf != nil {
+		summary.CDF = cdf.Val()
+	}
+	return summary, nil
+}
@@ -1,9 +1,11 @@
 package redis_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
+	"strings"
 
 	. "github.com/bsm/ginkgo/v2"
 	. "github.com/bsm/gomega"
@@ -108,6 +110,27 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(result.ExpansionRate).To(BeEquivalentTo(int64(3)))
 		})
 
+		It("should BFInsertBulk", Label("bloom", "bfinsert", "bfinsertbulk"), func() {
+			items := make([]interface{}, 2500)
+			for i := range items {
+				items[i] = i
+			}
+
+			result, err := client.BFInsertBulk(ctx, "testbf1", 1000, nil, items...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(2500))
+
+			result, err = client.BFInsertBulk(ctx, "testbf2", 0, &redis.BFInsertOptions{Capacity: 5000, Error: 0.001}, items...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(2500))
+			Expect(result).NotTo(ContainElement(false))
+
+			info, err := client.BFInfo(ctx, "testbf2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Capacity).To(BeEquivalentTo(5000))
+			Expect(info.ItemsInserted).To(BeEquivalentTo(2500))
+		})
+
 		It("should BFInsert", Label("bloom", "bfinsert"), func() {
 			options := &redis.BFInsertOptions{
 				Capacity:   2000,
@@ -227,6 +250,31 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(infBefore).To(BeEquivalentTo(infAfter))
 		})
 
+		It("should BFBackup and BFRestore", Label("bloom", "bfscandump", "bfloadchunk"), func() {
+			err := client.BFReserve(ctx, "testbfbk1", 0.001, 3000).Err()
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < 1000; i++ {
+				client.BFAdd(ctx, "testbfbk1", i)
+			}
+
+			var buf bytes.Buffer
+			Expect(client.BFBackup(ctx, "testbfbk1", &buf)).NotTo(HaveOccurred())
+			Expect(client.BFRestore(ctx, "testbfbk2", &buf)).NotTo(HaveOccurred())
+
+			infBefore, err := client.BFInfo(ctx, "testbfbk1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			infAfter, err := client.BFInfo(ctx, "testbfbk2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infAfter).To(BeEquivalentTo(infBefore))
+
+			exists, err := client.BFExists(ctx, "testbfbk2", 10).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+
+			err = client.BFRestore(ctx, "testbfbk3", strings.NewReader("garbage"))
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should BFReserveWithArgs", Label("bloom", "bfreserveargs"), func() {
 			options := &redis.BFReserveOptions{
 				Capacity:   2000,
@@ -352,6 +400,24 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(infBefore).To(BeEquivalentTo(infAfter))
 		})
 
+		It("should CFBackup and CFRestore", Label("cuckoo", "cfscandump", "cfloadchunk"), func() {
+			err := client.CFReserve(ctx, "testcfbk1", 1000).Err()
+			Expect(err).NotTo(HaveOccurred())
+			for i := 0; i < 1000; i++ {
+				client.CFAdd(ctx, "testcfbk1", fmt.Sprintf("item%d", i))
+			}
+
+			var buf bytes.Buffer
+			Expect(client.CFBackup(ctx, "testcfbk1", &buf)).NotTo(HaveOccurred())
+			Expect(client.CFRestore(ctx, "testcfbk2", &buf)).NotTo(HaveOccurred())
+
+			infBefore, err := client.CFInfo(ctx, "testcfbk1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			infAfter, err := client.CFInfo(ctx, "testcfbk2").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(infAfter).To(BeEquivalentTo(infBefore))
+		})
+
 		It("should CFInfo and CFReserveWithArgs", Label("cuckoo", "cfinfo", "cfreserveargs"), func() {
 			args := &redis.CFReserveOptions{
 				Capacity:      2048,
@@ -390,6 +456,25 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(len(result)).To(BeEquivalentTo(3))
 		})
 
+		It("should CFInsertBulk", Label("cuckoo", "cfinsert", "cfinsertbulk"), func() {
+			items := make([]interface{}, 2500)
+			for i := range items {
+				items[i] = fmt.Sprintf("item%d", i)
+			}
+
+			result, err := client.CFInsertBulk(ctx, "testcf1", 1000, &redis.CFInsertOptions{Capacity: 10000}, items...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(HaveLen(2500))
+			Expect(result).NotTo(ContainElement(false))
+
+			info, err := client.CFInfo(ctx, "testcf1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.NumItemsInserted).To(BeEquivalentTo(2500))
+
+			_, err = client.CFInsertBulk(ctx, "testcf2", 1000, &redis.CFInsertOptions{NoCreate: true}, items...)
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should CFInsertNX", Label("cuckoo", "cfinsertnx"), func() {
 			args := &redis.CFInsertOptions{
 				Capacity: 3000,
@@ -511,6 +596,31 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(result[1]).To(BeEquivalentTo(int64(6)))
 			Expect(result[2]).To(BeEquivalentTo(int64(6)))
 		})
+
+		It("should CMSIncrByMap and CMSQueryMany", Label("cms", "cmsincrbymap", "cmsquerymany"), func() {
+			_, err := client.CMSIncrByMap(ctx, "cms1", map[string]int64{"a": 1}, nil)
+			Expect(err).To(HaveOccurred())
+
+			init := &redis.CMSInitOptions{Width: 2000, Depth: 5}
+			counts, err := client.CMSIncrByMap(ctx, "cms1", map[string]int64{"a": 1, "b": 2}, init)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int64{"a": 1, "b": 2}))
+
+			counts, err = client.CMSIncrByMap(ctx, "cms1", map[string]int64{"b": 3}, init)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int64{"b": 5}))
+
+			info, err := client.CMSInfo(ctx, "cms1").Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Width).To(BeEquivalentTo(2000))
+
+			_, err = client.CMSIncrByMap(ctx, "cms2", map[string]int64{"a": 1}, &redis.CMSInitOptions{ErrorRate: 0.001, Probability: 0.01})
+			Expect(err).NotTo(HaveOccurred())
+
+			counts, err = client.CMSQueryMany(ctx, "cms1", "a", "b", "c")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(Equal(map[string]int64{"a": 1, "b": 5, "c": 0}))
+		})
 	})
 
 	Describe("TopK", Label("topk"), func() {
@@ -729,5 +839,33 @@ var _ = Describe("Probabilistic commands", Label("probabilistic"), func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(max).To(BeEquivalentTo(float64(140)))
 		})
+
+		It("should TDigestSummarize and TDigestMergeSummarize", Label("tdigest", "tdigestsummarize", "NonRedisEnterprise"), func() {
+			Expect(client.TDigestCreate(ctx, "tdigest1").Err()).NotTo(HaveOccurred())
+			Expect(client.TDigestAdd(ctx, "tdigest1", 1, 2, 3, 4, 5, 6, 7, 8, 9, 10).Err()).NotTo(HaveOccurred())
+			Expect(client.TDigestCreate(ctx, "tdigest2").Err()).NotTo(HaveOccurred())
+			Expect(client.TDigestAdd(ctx, "tdigest2", 11, 12, 13, 14, 15, 16, 17, 18, 19, 20).Err()).NotTo(HaveOccurred())
+
+			summary, err := client.TDigestSummarize(ctx, "tdigest1", &redis.TDigestSummaryOptions{
+				Quantiles: []float64{0, 0.5, 1},
+				CDF:       []float64{0, 10},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.Min).To(BeEquivalentTo(1))
+			Expect(summary.Max).To(BeEquivalentTo(10))
+			Expect(summary.TrimmedMean).To(BeNumerically("~", 5.5, 0.01))
+			Expect(summary.Quantiles).To(HaveLen(3))
+			Expect(summary.Quantiles[0]).To(BeEquivalentTo(1))
+			Expect(summary.Quantiles[2]).To(BeEquivalentTo(10))
+			Expect(summary.CDF).To(Equal([]float64{0, 1}))
+
+			summary, err = client.TDigestMergeSummarize(ctx, "tdigest3", nil, &redis.TDigestSummaryOptions{TrimLow: 0.1, TrimHigh: 0.9}, "tdigest1", "tdigest2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(summary.Min).To(BeEquivalentTo(1))
+			Expect(summary.Max).To(BeEquivalentTo(20))
+			Expect(summary.TrimmedMean).To(BeNumerically("~", 10.5, 0.01))
+			Expect(summary.Quantiles).To(BeNil())
+			Expect(client.TDigestInfo(ctx, "tdigest3").Val().Observations).To(BeEquivalentTo(20))
+		})
 	})
 })
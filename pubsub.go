@@ -165,7 +165,8 @@ func (c *PubSub) closeTheCn(reason error) error {
 		return nil
 	}
 	if !c.closed {
-		internal.Logger.Printf(c.getContext(), "redis: discarding bad PubSub connection: %s", reason)
+		internal.Log(c.getContext(), c.opt.Logger, internal.LevelWarn, "redis: discarding bad PubSub connection",
+			"error", reason)
 	}
 	err := c.closeConn(c.cn)
 	c.cn = nil
@@ -663,12 +664,12 @@ func (c *channel) initMsgChan() {
 						<-timer.C
 					}
 				case <-timer.C:
-					internal.Logger.Printf(
-						ctx, "redis: %s channel is full for %s (message is dropped)",
-						c, c.chanSendTimeout)
+					internal.Log(ctx, c.pubSub.opt.Logger, internal.LevelWarn, "redis: channel is full (message is dropped)",
+						"pubsub", c.pubSub, "timeout", c.chanSendTimeout)
 				}
 			default:
-				internal.Logger.Printf(ctx, "redis: unknown message type: %T", msg)
+				internal.Log(ctx, c.pubSub.opt.Logger, internal.LevelWarn, "redis: unknown message type",
+					"type", fmt.Sprintf("%T", msg))
 			}
 		}
 	}()
@@ -717,12 +718,12 @@ func (c *channel) initAllChan() {
 						<-timer.C
 					}
 				case <-timer.C:
-					internal.Logger.Printf(
-						ctx, "redis: %s channel is full for %s (message is dropped)",
-						c, c.chanSendTimeout)
+					internal.Log(ctx, c.pubSub.opt.Logger, internal.LevelWarn, "redis: channel is full (message is dropped)",
+						"pubsub", c.pubSub, "timeout", c.chanSendTimeout)
 				}
 			default:
-				internal.Logger.Printf(ctx, "redis: unknown message type: %T", msg)
+				internal.Log(ctx, c.pubSub.opt.Logger, internal.LevelWarn, "redis: unknown message type",
+					"type", fmt.Sprintf("%T", msg))
 			}
 		}
 	}()
@@ -0,0 +1,156 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+type pushHandler func(ctx context.Context, payload []interface{})
+
+// PushMessage is a RESP3 push notification received on a regular connection
+// of a client, outside of PubSub, see Options.OnPush.
+type PushMessage struct {
+	// Name is the name of the notification, e.g. "invalidate" or "MOVING".
+	Name string
+	// Payload is the rest of the notification.
+	Payload []interface{}
+}
+
+// pushProcessor dispatches the RESP3 push notifications received on regular
+// (non-PubSub) connections to the handlers registered by notification name.
+// Notifications without a handler are discarded so they never get mistaken
+// for a command reply.
+type pushProcessor struct {
+	mu       sync.RWMutex
+	handlers map[string]pushHandler
+
+	// onPush is Options.OnPush, called with every notification.
+	onPush func(ctx context.Context, msg PushMessage)
+}
+
+func newPushProcessor() *pushProcessor {
+	return &pushProcessor{
+		handlers: make(map[string]pushHandler),
+	}
+}
+
+func (p *pushProcessor) register(name string, handler pushHandler) {
+	p.mu.Lock()
+	p.handlers[name] = handler
+	p.mu.Unlock()
+}
+
+func (p *pushProcessor) handle(ctx context.Context, name string, payload []interface{}) {
+	p.mu.RLock()
+	handler := p.handlers[name]
+	p.mu.RUnlock()
+
+	if handler != nil {
+		handler(ctx, payload)
+	}
+	if p.onPush != nil {
+		p.onPush(ctx, PushMessage{Name: name, Payload: payload})
+	}
+}
+
+// isPubSubPush reports whether the push notification belongs to the PubSub
+// protocol and must be left to the caller, e.g. the reply of SUBSCRIBE.
+func isPubSubPush(name string) bool {
+	switch name {
+	case "subscribe", "unsubscribe", "psubscribe", "punsubscribe",
+		"ssubscribe", "sunsubscribe", "message", "pmessage", "smessage", "pong":
+		return true
+	}
+	return false
+}
+
+// process reads the push notifications preceding the next reply on rd.
+// It is a no-op for a nil processor.
+func (p *pushProcessor) process(ctx context.Context, rd *proto.Reader) error {
+	if p == nil {
+		return nil
+	}
+
+	for {
+		typ, err := rd.PeekReplyType()
+		if err != nil {
+			return err
+		}
+		if typ != proto.RespPush {
+			return nil
+		}
+
+		name, err := rd.PeekPushNotificationName()
+		if err != nil {
+			return err
+		}
+		if isPubSubPush(name) {
+			return nil
+		}
+
+		if err := p.read(ctx, name, rd); err != nil {
+			return err
+		}
+	}
+}
+
+// readReply reads the push notifications preceding the reply of cmd and then the reply.
+func (p *pushProcessor) readReply(ctx context.Context, rd *proto.Reader, cmd Cmder) error {
+	if err := p.process(ctx, rd); err != nil {
+		return err
+	}
+	n := rd.Consumed()
+	err := cmd.readReply(rd)
+	cmd.wireBytes().in = uint32(rd.Consumed() - n)
+	return err
+}
+
+func (p *pushProcessor) read(ctx context.Context, name string, rd *proto.Reader) error {
+	reply, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	payload, _ := reply.([]interface{})
+	if len(payload) > 0 {
+		payload = payload[1:]
+	}
+	p.handle(ctx, name, payload)
+	return nil
+}
+
+// processPending drains the push notifications received while cn was idle.
+// Anything else pending on the connection is reported as an error.
+func (p *pushProcessor) processPending(ctx context.Context, cn *pool.Conn, timeout time.Duration) error {
+	if p == nil {
+		return nil
+	}
+
+	for cn.HasPendingData() {
+		if err := cn.WithReader(ctx, timeout, func(rd *proto.Reader) error {
+			typ, err := rd.PeekReplyType()
+			if err != nil {
+				return err
+			}
+			if typ != proto.RespPush {
+				return fmt.Errorf("redis: unexpected pending reply type %q", typ)
+			}
+
+			name, err := rd.PeekPushNotificationName()
+			if err != nil {
+				return err
+			}
+			if isPubSubPush(name) {
+				return fmt.Errorf("redis: unexpected pending %q push notification", name)
+			}
+			return p.read(ctx, name, rd)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
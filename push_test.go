@@ -0,0 +1,45 @@
+package redis_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestOnPush(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var msgs []redis.PushMessage
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     srv.Addr(),
+		PoolSize: 1,
+		OnPush: func(ctx context.Context, msg redis.PushMessage) {
+			msgs = append(msgs, msg)
+		},
+	})
+	defer rdb.Close()
+	other := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer other.Close()
+
+	if err := rdb.Do(ctx, "client", "tracking", "on").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Get(ctx, "key").Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+	if err := other.Set(ctx, "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil { // reads the invalidation
+		t.Fatal(err)
+	}
+
+	want := []redis.PushMessage{{Name: "invalidate", Payload: []interface{}{[]interface{}{"key"}}}}
+	if !reflect.DeepEqual(msgs, want) {
+		t.Fatalf("got %v, wanted %v", msgs, want)
+	}
+}
@@ -0,0 +1,459 @@
+// Package queue implements a reliable job queue on Redis streams.
+//
+// Jobs are added to a stream read by a consumer group of workers, or to a
+// sorted set of scheduled jobs when they are delayed, from which the workers
+// promote the due jobs to the stream. A job is acknowledged once handled;
+// failed jobs are scheduled again with a backoff until they run out of
+// attempts and are moved to the dead letter stream. Jobs of crashed workers
+// are claimed by the other workers once they have been idle for long enough.
+//
+//	q := queue.New(rdb, "emails", nil)
+//	id, err := q.Enqueue(ctx, []byte(`{"to":"alice@example.com"}`), &queue.EnqueueOptions{Delay: time.Minute})
+//
+//	err = q.Work(ctx, func(ctx context.Context, job *queue.Job) error {
+//		return send(job.Payload)
+//	}, nil)
+//
+// All the keys of a queue share a hash tag, so it can be used with cluster clients.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Status is the status of a job.
+type Status string
+
+const (
+	// StatusScheduled jobs wait for their time, or for their retry.
+	StatusScheduled Status = "scheduled"
+	// StatusPending jobs wait for a worker.
+	StatusPending Status = "pending"
+	// StatusActive jobs are being handled by a worker.
+	StatusActive Status = "active"
+	// StatusCompleted jobs were handled successfully.
+	StatusCompleted Status = "completed"
+	// StatusDead jobs failed all their attempts.
+	StatusDead Status = "dead"
+)
+
+// ErrJobNotFound is returned by Job when there is no such job, or it expired.
+var ErrJobNotFound = errors.New("queue: job not found")
+
+const group = "workers"
+
+// promoteScript moves the due jobs from the scheduled set to the stream.
+//
+// KEYS: scheduled jobs, stream.
+// ARGV: prefix of the job keys, maximum number of jobs.
+var promoteScript = redis.NewScript(`
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local ids = redis.call("zrangebyscore", KEYS[1], "-inf", now, "limit", 0, tonumber(ARGV[2]))
+for _, id in ipairs(ids) do
+	redis.call("zrem", KEYS[1], id)
+	redis.call("xadd", KEYS[2], "*", "id", id)
+	redis.call("hset", ARGV[1] .. id, "status", "pending")
+end
+return #ids
+`)
+
+type Options struct {
+	// MaxAttempts is the number of times a job is handled before it is dead.
+	// Default is 3.
+	MaxAttempts int
+	// RetryDelay returns the delay before the attempt-th attempt of a failed
+	// job. Default is 1 second doubling up to 1 hour.
+	RetryDelay func(attempt int) time.Duration
+	// Retention is how long completed and dead jobs can be queried. Default is 24 hours.
+	Retention time.Duration
+}
+
+// Queue is a job queue.
+type Queue struct {
+	client redis.UniversalClient
+	name   string
+	opt    Options
+
+	streamKey    string
+	scheduledKey string
+	deadKey      string
+	jobPrefix    string
+}
+
+// New returns the queue name of client.
+func New(client redis.UniversalClient, name string, opt *Options) *Queue {
+	q := &Queue{client: client, name: name}
+	if opt != nil {
+		q.opt = *opt
+	}
+	if q.opt.MaxAttempts <= 0 {
+		q.opt.MaxAttempts = 3
+	}
+	if q.opt.RetryDelay == nil {
+		q.opt.RetryDelay = defaultRetryDelay
+	}
+	if q.opt.Retention <= 0 {
+		q.opt.Retention = 24 * time.Hour
+	}
+
+	prefix := "{queue:" + name + "}"
+	q.streamKey = prefix + ":stream"
+	q.scheduledKey = prefix + ":scheduled"
+	q.deadKey = prefix + ":dead"
+	q.jobPrefix = prefix + ":job:"
+	return q
+}
+
+func defaultRetryDelay(attempt int) time.Duration {
+	d := time.Second
+	for i := 2; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	if d > time.Hour {
+		d = time.Hour
+	}
+	return d
+}
+
+// Name returns the name of the queue.
+func (q *Queue) Name() string {
+	return q.name
+}
+
+// DeadLetterStream returns the stream the dead jobs are added to, with their
+// "id" and last "error".
+func (q *Queue) DeadLetterStream() string {
+	return q.deadKey
+}
+
+// Job is a job of a queue.
+type Job struct {
+	ID      string
+	Payload []byte
+	Status  Status
+	// Attempts is the number of times the job was handled, including the
+	// current attempt.
+	Attempts int
+	// Error is the error of the last failed attempt.
+	Error      string
+	EnqueuedAt time.Time
+}
+
+type EnqueueOptions struct {
+	// ID of the job. Default is a random id. Enqueuing a job with the ID of
+	// an existing job replaces it.
+	ID string
+	// Delay of the job. Default is to handle it as soon as possible.
+	Delay time.Duration
+}
+
+// Enqueue adds a job with payload and returns its id.
+func (q *Queue) Enqueue(ctx context.Context, payload []byte, opt *EnqueueOptions) (string, error) {
+	if opt == nil {
+		opt = &EnqueueOptions{}
+	}
+	id := opt.ID
+	if id == "" {
+		var err error
+		if id, err = newID(); err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
+	status := StatusPending
+	if opt.Delay > 0 {
+		status = StatusScheduled
+	}
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		key := q.jobPrefix + id
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key,
+			"payload", payload,
+			"status", string(status),
+			"attempts", 0,
+			"enqueued_at", now.UnixMilli(),
+		)
+		if opt.Delay > 0 {
+			pipe.ZAdd(ctx, q.scheduledKey, redis.Z{
+				Score:  float64(now.Add(opt.Delay).UnixMilli()),
+				Member: id,
+			})
+		} else {
+			pipe.XAdd(ctx, &redis.XAddArgs{Stream: q.streamKey, Values: []interface{}{"id", id}})
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Job returns the job with the id, or ErrJobNotFound.
+func (q *Queue) Job(ctx context.Context, id string) (*Job, error) {
+	m, err := q.client.HGetAll(ctx, q.jobPrefix+id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(m) == 0 {
+		return nil, ErrJobNotFound
+	}
+	return parseJob(id, m)
+}
+
+func parseJob(id string, m map[string]string) (*Job, error) {
+	job := &Job{
+		ID:      id,
+		Payload: []byte(m["payload"]),
+		Status:  Status(m["status"]),
+		Error:   m["error"],
+	}
+	var err error
+	if s := m["attempts"]; s != "" {
+		if job.Attempts, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("queue: job %s has invalid attempts %q", id, s)
+		}
+	}
+	if s := m["enqueued_at"]; s != "" {
+		ms, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queue: job %s has invalid enqueued_at %q", id, s)
+		}
+		job.EnqueuedAt = time.UnixMilli(ms)
+	}
+	return job, nil
+}
+
+// PromoteDue moves up to count due scheduled jobs to the stream and returns
+// their number. Workers call it before reading the stream.
+func (q *Queue) PromoteDue(ctx context.Context, count int) (int, error) {
+	return promoteScript.Run(ctx, q.client, []string{q.scheduledKey, q.streamKey}, q.jobPrefix, count).Int()
+}
+
+// Handler handles a job. A job is retried when its handler returns an error.
+type Handler func(ctx context.Context, job *Job) error
+
+type WorkerOptions struct {
+	// Consumer is the name of the worker in the consumer group. Default is the
+	// host name and the process id.
+	Consumer string
+	// Concurrency is the number of jobs handled concurrently. Default is 1.
+	Concurrency int
+	// Block is the time waiting for jobs before promoting the due scheduled
+	// jobs again. Default is 1 second.
+	Block time.Duration
+	// ClaimMinIdle is the time after which the jobs of a worker that stopped
+	// are claimed by the other workers. It must exceed the time taken by the
+	// handler. Default is 5 minutes.
+	ClaimMinIdle time.Duration
+	// OnError is called with the errors of Redis, which are retried after
+	// Block. Default is to ignore them.
+	OnError func(err error)
+}
+
+// Work handles the jobs of the queue with handler until ctx is done, and
+// returns ctx.Err().
+func (q *Queue) Work(ctx context.Context, handler Handler, opt *WorkerOptions) error {
+	var o WorkerOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.Consumer == "" {
+		host, _ := os.Hostname()
+		o.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.Block <= 0 {
+		o.Block = time.Second
+	}
+	if o.ClaimMinIdle <= 0 {
+		o.ClaimMinIdle = 5 * time.Minute
+	}
+
+	err := q.client.XGroupCreateMkStream(ctx, q.streamKey, group, "0").Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return err
+	}
+
+	done := make(chan struct{}, o.Concurrency)
+	for i := 0; i < o.Concurrency; i++ {
+		consumer := o.Consumer
+		if o.Concurrency > 1 {
+			consumer += "-" + strconv.Itoa(i)
+		}
+		go func() {
+			q.work(ctx, handler, &o, consumer)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < o.Concurrency; i++ {
+		<-done
+	}
+	return ctx.Err()
+}
+
+func (q *Queue) work(ctx context.Context, handler Handler, opt *WorkerOptions, consumer string) {
+	for ctx.Err() == nil {
+		msgs, err := q.next(ctx, opt, consumer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if opt.OnError != nil {
+				opt.OnError(err)
+			}
+			select {
+			case <-time.After(opt.Block):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		for _, msg := range msgs {
+			if err := q.handle(ctx, handler, msg); err != nil && opt.OnError != nil && ctx.Err() == nil {
+				opt.OnError(err)
+			}
+		}
+	}
+}
+
+// next returns the next message: a message claimed from a stopped worker, or
+// a new one.
+func (q *Queue) next(ctx context.Context, opt *WorkerOptions, consumer string) ([]redis.XMessage, error) {
+	if _, err := q.PromoteDue(ctx, 100); err != nil {
+		return nil, err
+	}
+
+	claimed, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.streamKey,
+		Group:    group,
+		MinIdle:  opt.ClaimMinIdle,
+		Start:    "0-0",
+		Count:    1,
+		Consumer: consumer,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(claimed) > 0 {
+		return claimed, nil
+	}
+
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{q.streamKey, ">"},
+		Count:    1,
+		Block:    opt.Block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var msgs []redis.XMessage
+	for _, stream := range streams {
+		msgs = append(msgs, stream.Messages...)
+	}
+	return msgs, nil
+}
+
+func (q *Queue) handle(ctx context.Context, handler Handler, msg redis.XMessage) error {
+	id, _ := msg.Values["id"].(string)
+	key := q.jobPrefix + id
+
+	m, err := q.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if len(m) == 0 {
+		// the job expired or was deleted
+		return q.ack(ctx, msg.ID, nil)
+	}
+	job, err := parseJob(id, m)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := q.client.HIncrBy(ctx, key, "attempts", 1).Result()
+	if err != nil {
+		return err
+	}
+	job.Attempts = int(attempts)
+	job.Status = StatusActive
+	if err := q.client.HSet(ctx, key, "status", string(StatusActive)).Err(); err != nil {
+		return err
+	}
+
+	herr := callHandler(ctx, handler, job)
+	if ctx.Err() != nil && herr != nil {
+		// the worker is stopping: the job is claimed by another worker
+		return nil
+	}
+
+	return q.ack(ctx, msg.ID, func(pipe redis.Pipeliner) {
+		switch {
+		case herr == nil:
+			pipe.HSet(ctx, key, "status", string(StatusCompleted))
+			pipe.Expire(ctx, key, q.opt.Retention)
+		case job.Attempts < q.opt.MaxAttempts:
+			delay := q.opt.RetryDelay(job.Attempts + 1)
+			pipe.HSet(ctx, key, "status", string(StatusScheduled), "error", herr.Error())
+			pipe.ZAdd(ctx, q.scheduledKey, redis.Z{
+				Score:  float64(time.Now().Add(delay).UnixMilli()),
+				Member: id,
+			})
+		default:
+			pipe.HSet(ctx, key, "status", string(StatusDead), "error", herr.Error())
+			pipe.Expire(ctx, key, q.opt.Retention)
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: q.deadKey,
+				Values: []interface{}{"id", id, "error", herr.Error()},
+			})
+		}
+	})
+}
+
+// ack acknowledges and deletes the message, in a transaction with the
+// commands of fn.
+func (q *Queue) ack(ctx context.Context, msgID string, fn func(pipe redis.Pipeliner)) error {
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, q.streamKey, group, msgID)
+		pipe.XDel(ctx, q.streamKey, msgID)
+		if fn != nil {
+			fn(pipe)
+		}
+		return nil
+	})
+	return err
+}
+
+func callHandler(ctx context.Context, handler Handler, job *Job) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("queue: handler panicked: %v", v)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNew(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: ":6379"})
+	defer client.Close()
+
+	q := New(client, "emails", nil)
+	if q.DeadLetterStream() != "{queue:emails}:dead" {
+		t.Errorf("got dead letter stream %q", q.DeadLetterStream())
+	}
+	if q.opt.MaxAttempts != 3 || q.opt.Retention != 24*time.Hour {
+		t.Errorf("got options %+v", q.opt)
+	}
+}
+
+func TestDefaultRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{5, 8 * time.Second},
+		{20, time.Hour},
+		{100, time.Hour},
+	}
+	for _, test := range tests {
+		if got := defaultRetryDelay(test.attempt); got != test.want {
+			t.Errorf("defaultRetryDelay(%d) = %v, want %v", test.attempt, got, test.want)
+		}
+	}
+}
+
+func TestParseJob(t *testing.T) {
+	job, err := parseJob("42", map[string]string{
+		"payload":     "hello",
+		"status":      "scheduled",
+		"attempts":    "2",
+		"error":       "timeout",
+		"enqueued_at": "1700000000000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "42" || string(job.Payload) != "hello" || job.Status != StatusScheduled ||
+		job.Attempts != 2 || job.Error != "timeout" || job.EnqueuedAt.UnixMilli() != 1700000000000 {
+		t.Errorf("got %+v", job)
+	}
+
+	if _, err := parseJob("42", map[string]string{"attempts": "x"}); err == nil {
+		t.Error("invalid attempts were accepted")
+	}
+}
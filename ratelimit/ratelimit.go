@@ -0,0 +1,263 @@
+// Package ratelimit implements rate limiters storing their state in Redis.
+//
+// Limiter implements the generic cell rate algorithm (GCRA), which spaces the
+// requests evenly and allows bursts, in a single string per key.
+// SlidingWindowLimiter keeps the log of the requests of the last period in a
+// sorted set per key, which is exact but grows with the rate.
+//
+// Both run a Lua script on a single key per call, so they can be used with
+// cluster clients.
+//
+//	limiter := ratelimit.NewLimiter(rdb)
+//	res, err := limiter.Allow(ctx, "user:42", ratelimit.PerSecond(10))
+//	if err != nil {
+//		return err
+//	}
+//	if res.Allowed == 0 {
+//		// retry after res.RetryAfter
+//	}
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "rate:"
+
+type rediser interface {
+	redis.Scripter
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+// Limit is a rate of Rate requests per Period, allowing bursts of Burst
+// requests.
+type Limit struct {
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d req/%s (burst %d)", l.Rate, fmtDur(l.Period), l.Burst)
+}
+
+// IsZero reports whether l is the zero Limit.
+func (l Limit) IsZero() bool {
+	return l == Limit{}
+}
+
+func fmtDur(d time.Duration) string {
+	switch d {
+	case time.Second:
+		return "s"
+	case time.Minute:
+		return "m"
+	case time.Hour:
+		return "h"
+	}
+	return d.String()
+}
+
+// PerSecond is a limit of rate requests per second, with bursts of rate requests.
+func PerSecond(rate int) Limit {
+	return Limit{Rate: rate, Burst: rate, Period: time.Second}
+}
+
+// PerMinute is a limit of rate requests per minute, with bursts of rate requests.
+func PerMinute(rate int) Limit {
+	return Limit{Rate: rate, Burst: rate, Period: time.Minute}
+}
+
+// PerHour is a limit of rate requests per hour, with bursts of rate requests.
+func PerHour(rate int) Limit {
+	return Limit{Rate: rate, Burst: rate, Period: time.Hour}
+}
+
+// Result is the result of Allow, AllowN and Reserve.
+type Result struct {
+	// Limit is the limit that was used.
+	Limit Limit
+	// Allowed is the number of requests allowed, 0 when none are.
+	Allowed int
+	// Remaining is the number of requests allowed right after this one.
+	Remaining int
+	// RetryAfter is the time until the requests are allowed, -1 when they
+	// are allowed now. For reservations, it is the time the caller must wait
+	// before proceeding, -1 when the reservation can never be satisfied.
+	RetryAfter time.Duration
+	// ResetAfter is the time until the limiter gets back to its initial state.
+	ResetAfter time.Duration
+}
+
+// gcraScript implements GCRA, with the theoretical arrival time of the next
+// request stored in KEYS[1], in seconds since 2020 to keep the precision of
+// Lua numbers.
+//
+// ARGV: burst, rate, period in seconds, cost, "1" to reserve.
+var gcraScript = redis.NewScript(`
+local key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+local reserve = ARGV[5] == "1"
+
+local emission_interval = period / rate
+local increment = emission_interval * cost
+local burst_offset = emission_interval * burst
+
+local t = redis.call("TIME")
+local now = (tonumber(t[1]) - 1577836800) + tonumber(t[2]) / 1000000
+
+local tat = tonumber(redis.call("GET", key) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + increment
+local diff = now - (new_tat - burst_offset)
+local remaining = diff / emission_interval
+
+if remaining < 0 and (not reserve or cost > burst) then
+	local retry_after = -diff
+	if cost > burst then
+		retry_after = -1
+	end
+	return {0, 0, tostring(retry_after), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+if reset_after > 0 then
+	redis.call("SET", key, new_tat, "EX", math.ceil(reset_after))
+end
+
+local retry_after = -1
+if reserve then
+	retry_after = math.max(-diff, 0)
+end
+return {cost, math.max(math.floor(remaining), 0), tostring(retry_after), tostring(reset_after)}
+`)
+
+// Limiter is a GCRA rate limiter.
+type Limiter struct {
+	client rediser
+}
+
+// NewLimiter returns a GCRA rate limiter storing its state in client.
+func NewLimiter(client rediser) *Limiter {
+	return &Limiter{client: client}
+}
+
+// Allow is a shortcut for AllowN(ctx, key, limit, 1).
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN reports whether n requests may happen now under the limit of key.
+// The requests are only counted when they are allowed.
+func (l *Limiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	return l.run(ctx, key, limit, n, false)
+}
+
+// Reserve counts n requests under the limit of key and returns the time the
+// caller must wait, Result.RetryAfter, before proceeding with them. Unlike
+// AllowN, the requests are counted even when they are not allowed now.
+func (l *Limiter) Reserve(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	return l.run(ctx, key, limit, n, true)
+}
+
+// Reset resets the limit of key.
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, keyPrefix+key).Err()
+}
+
+func (l *Limiter) run(ctx context.Context, key string, limit Limit, n int, reserve bool) (*Result, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	if limit.Burst <= 0 {
+		return nil, fmt.Errorf("ratelimit: invalid limit %s", limit)
+	}
+	args := []interface{}{limit.Burst, limit.Rate, limit.Period.Seconds(), n, boolArg(reserve)}
+	v, err := gcraScript.Run(ctx, l.client, []string{keyPrefix + key}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(limit, v, time.Second)
+}
+
+func (l Limit) validate() error {
+	if l.Rate <= 0 || l.Period <= 0 {
+		return fmt.Errorf("ratelimit: invalid limit %s", l)
+	}
+	return nil
+}
+
+func boolArg(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// parseResult parses the {allowed, remaining, retry after, reset after} reply
+// of the scripts, whose durations are in unit.
+func parseResult(limit Limit, v []interface{}, unit time.Duration) (*Result, error) {
+	if len(v) != 4 {
+		return nil, fmt.Errorf("ratelimit: got %d elements in the script reply, wanted 4", len(v))
+	}
+	allowed, ok1 := v[0].(int64)
+	remaining, ok2 := v[1].(int64)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("ratelimit: unexpected script reply %v", v)
+	}
+	retryAfter, err := parseDuration(v[2], unit)
+	if err != nil {
+		return nil, err
+	}
+	resetAfter, err := parseDuration(v[3], unit)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Limit:      limit,
+		Allowed:    int(allowed),
+		Remaining:  int(remaining),
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+func parseDuration(v interface{}, unit time.Duration) (time.Duration, error) {
+	var f float64
+	switch v := v.(type) {
+	case int64:
+		f = float64(v)
+	case string:
+		var err error
+		if f, err = strconv.ParseFloat(v, 64); err != nil {
+			return 0, fmt.Errorf("ratelimit: unexpected duration %q in the script reply", v)
+		}
+	default:
+		return 0, fmt.Errorf("ratelimit: unexpected duration %v (%T) in the script reply", v, v)
+	}
+	if f == -1 {
+		return -1, nil
+	}
+	return time.Duration(f * float64(unit)), nil
+}
+
+func newMemberID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeClient replies to the scripts with reply.
+type fakeClient struct {
+	rediser
+
+	reply []interface{}
+	keys  []string
+	args  []interface{}
+}
+
+func (c *fakeClient) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	c.keys, c.args = keys, args
+	cmd := redis.NewCmd(ctx)
+	cmd.SetVal(c.reply)
+	return cmd
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{reply: []interface{}{int64(0), int64(0), "0.25", "1.5"}}
+
+	res, err := NewLimiter(client).AllowN(ctx, "user:42", PerSecond(10), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Result{
+		Limit:      PerSecond(10),
+		RetryAfter: 250 * time.Millisecond,
+		ResetAfter: 1500 * time.Millisecond,
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+	if !reflect.DeepEqual(client.keys, []string{"rate:user:42"}) {
+		t.Errorf("got keys %q", client.keys)
+	}
+	if !reflect.DeepEqual(client.args, []interface{}{10, 10, 1.0, 3, "0"}) {
+		t.Errorf("got args %v", client.args)
+	}
+
+	client.reply = []interface{}{int64(1), int64(9), "-1", "0.1"}
+	res, err = NewLimiter(client).Reserve(ctx, "user:42", PerMinute(600), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed != 1 || res.Remaining != 9 || res.RetryAfter != -1 {
+		t.Errorf("got %+v", res)
+	}
+	if client.args[4] != "1" {
+		t.Errorf("got reserve arg %v", client.args[4])
+	}
+
+	if _, err := NewLimiter(client).Allow(ctx, "user:42", Limit{Rate: 10, Period: time.Second}); err == nil {
+		t.Error("limit without burst was accepted")
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{reply: []interface{}{int64(2), int64(8), int64(-1), int64(60000)}}
+
+	res, err := NewSlidingWindowLimiter(client).AllowN(ctx, "ip", PerMinute(10), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed != 2 || res.Remaining != 8 || res.RetryAfter != -1 || res.ResetAfter != time.Minute {
+		t.Errorf("got %+v", res)
+	}
+	if !reflect.DeepEqual(client.keys, []string{"rate:log:ip"}) {
+		t.Errorf("got keys %q", client.keys)
+	}
+	if !reflect.DeepEqual(client.args[:4], []interface{}{10, int64(60000), 2, "0"}) {
+		t.Errorf("got args %v", client.args)
+	}
+
+	if _, err := NewSlidingWindowLimiter(client).Allow(ctx, "ip", Limit{}); err == nil {
+		t.Error("zero limit was accepted")
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	if s := PerHour(5).String(); s != "5 req/h (burst 5)" {
+		t.Errorf("got %q", s)
+	}
+	if s := (Limit{Rate: 1, Burst: 2, Period: 10 * time.Second}).String(); s != "1 req/10s (burst 2)" {
+		t.Errorf("got %q", s)
+	}
+}
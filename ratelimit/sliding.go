@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const slidingKeyPrefix = keyPrefix + "log:"
+
+// slidingScript logs the requests of the last window in the sorted set
+// KEYS[1], scored by their time in milliseconds. Reserved requests are logged
+// at the time they are allowed.
+//
+// ARGV: limit, window in milliseconds, cost, "1" to reserve, unique id of the call.
+var slidingScript = redis.NewScript(`
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local reserve = ARGV[4] == "1"
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+
+local function reset_after()
+	local last = redis.call("ZRANGE", key, -1, -1, "WITHSCORES")
+	if #last == 0 then
+		return 0
+	end
+	return tonumber(last[2]) + window - now
+end
+
+if cost > limit then
+	return {0, math.max(limit - count, 0), -1, reset_after()}
+end
+
+local at = now
+if count + cost > limit then
+	-- the requests are allowed once enough logged requests leave the window
+	local i = count + cost - limit - 1
+	local oldest = redis.call("ZRANGE", key, i, i, "WITHSCORES")
+	at = tonumber(oldest[2]) + window
+	if not reserve then
+		return {0, math.max(limit - count, 0), at - now, reset_after()}
+	end
+end
+
+for i = 1, cost do
+	redis.call("ZADD", key, at, ARGV[5] .. ":" .. i)
+end
+redis.call("PEXPIRE", key, reset_after())
+
+local retry_after = -1
+if reserve then
+	retry_after = at - now
+end
+return {cost, math.max(limit - count - cost, 0), retry_after, reset_after()}
+`)
+
+// SlidingWindowLimiter is a rate limiter allowing at most Limit.Rate requests
+// in any window of Limit.Period. Limit.Burst is ignored.
+type SlidingWindowLimiter struct {
+	client rediser
+}
+
+// NewSlidingWindowLimiter returns a sliding window log rate limiter storing
+// its state in client.
+func NewSlidingWindowLimiter(client rediser) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{client: client}
+}
+
+// Allow is a shortcut for AllowN(ctx, key, limit, 1).
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	return l.AllowN(ctx, key, limit, 1)
+}
+
+// AllowN reports whether n requests may happen now under the limit of key.
+// The requests are only logged when they are allowed.
+func (l *SlidingWindowLimiter) AllowN(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	return l.run(ctx, key, limit, n, false)
+}
+
+// Reserve logs n requests under the limit of key and returns the time the
+// caller must wait, Result.RetryAfter, before proceeding with them.
+func (l *SlidingWindowLimiter) Reserve(ctx context.Context, key string, limit Limit, n int) (*Result, error) {
+	return l.run(ctx, key, limit, n, true)
+}
+
+// Reset resets the limit of key.
+func (l *SlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, slidingKeyPrefix+key).Err()
+}
+
+func (l *SlidingWindowLimiter) run(ctx context.Context, key string, limit Limit, n int, reserve bool) (*Result, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	id, err := newMemberID()
+	if err != nil {
+		return nil, err
+	}
+	args := []interface{}{limit.Rate, limit.Period.Milliseconds(), n, boolArg(reserve), id}
+	v, err := slidingScript.Run(ctx, l.client, []string{slidingKeyPrefix + key}, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+	return parseResult(limit, v, time.Millisecond)
+}
@@ -0,0 +1,305 @@
+package redis
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+type ReadWriteSplitOptions struct {
+	// Primary are the options of the client of the primary.
+	Primary *Options
+	// ReplicaAddrs are the addresses of the replicas of the primary, whose
+	// clients use the options of the primary.
+	ReplicaAddrs []string
+
+	// HealthCheckInterval is the interval between the health checks of the
+	// replicas, which must be up and connected to the primary to serve the
+	// reads. Default is 1 second.
+	HealthCheckInterval time.Duration
+	// MaxReplicationLag is the maximum number of bytes of the replication
+	// stream a replica can lag behind the primary to serve the reads. Default
+	// is no maximum.
+	MaxReplicationLag int64
+}
+
+func (opt *ReadWriteSplitOptions) init() {
+	if opt.HealthCheckInterval <= 0 {
+		opt.HealthCheckInterval = time.Second
+	}
+}
+
+// ReadWriteSplitClient sends the read-only commands to the replicas of a
+// standalone primary, without Sentinel nor Cluster, and the other commands to
+// the primary. The read-only commands are the commands flagged as such by the
+// COMMAND command, and the commands registered with RegisterReadOnlyCommands.
+//
+// The replicas are health-checked with INFO replication: the reads are sent to
+// a random healthy replica, or to the primary when none is healthy, or when a
+// replica fails with a network error. The replication is asynchronous: a read
+// sent to a replica may not see a write completed just before, use Primary for
+// such reads.
+//
+// The pipelines are sent to a replica when all their commands are read-only,
+// and the transactions to the primary. Stateful commands such as WATCH and
+// the PubSub are not routed: use Primary or Replicas to run them.
+type ReadWriteSplitClient struct {
+	cmdable
+
+	opt      *ReadWriteSplitOptions
+	primary  *Client
+	replicas []*splitReplica
+
+	cmdsInfoCache *cmdsInfoCache
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+type splitReplica struct {
+	client *Client
+
+	mu      sync.RWMutex
+	healthy bool
+}
+
+func (r *splitReplica) isHealthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.healthy
+}
+
+func (r *splitReplica) setHealthy(healthy bool) {
+	r.mu.Lock()
+	r.healthy = healthy
+	r.mu.Unlock()
+}
+
+// NewReadWriteSplitClient returns a client of the primary and replicas of opt,
+// health-checking the replicas until the client is closed. The reads are sent
+// to the primary until the first health check of the replicas.
+func NewReadWriteSplitClient(opt *ReadWriteSplitOptions) *ReadWriteSplitClient {
+	opt.init()
+	c := &ReadWriteSplitClient{
+		opt:     opt,
+		primary: NewClient(opt.Primary),
+		closed:  make(chan struct{}),
+	}
+	for _, addr := range opt.ReplicaAddrs {
+		replicaOpt := opt.Primary.clone()
+		replicaOpt.Addr = addr
+		c.replicas = append(c.replicas, &splitReplica{client: NewClient(replicaOpt)})
+	}
+	c.cmdsInfoCache = newCmdsInfoCache(func(ctx context.Context) (map[string]*CommandInfo, error) {
+		return c.primary.Command(ctx).Result()
+	})
+	c.cmdable = c.Process
+
+	c.wg.Add(1)
+	go c.healthCheck()
+	return c
+}
+
+// Primary returns the client of the primary.
+func (c *ReadWriteSplitClient) Primary() *Client {
+	return c.primary
+}
+
+// Replicas returns the clients of the replicas.
+func (c *ReadWriteSplitClient) Replicas() []*Client {
+	clients := make([]*Client, len(c.replicas))
+	for i, r := range c.replicas {
+		clients[i] = r.client
+	}
+	return clients
+}
+
+// Do creates a Cmd from the args and processes the cmd.
+func (c *ReadWriteSplitClient) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(ctx, args...)
+	_ = c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process processes cmd with a replica when it is read-only, or with the
+// primary.
+func (c *ReadWriteSplitClient) Process(ctx context.Context, cmd Cmder) error {
+	if !c.cmdIsReadOnly(ctx, cmd.Name()) {
+		return c.primary.Process(ctx, cmd)
+	}
+	replica := c.pickReplica()
+	if replica == nil {
+		return c.primary.Process(ctx, cmd)
+	}
+
+	err := replica.client.Process(ctx, cmd)
+	if isServerFailure(err) {
+		replica.setHealthy(false)
+		return c.primary.Process(ctx, cmd)
+	}
+	return err
+}
+
+func (c *ReadWriteSplitClient) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.Pipeline().Pipelined(ctx, fn)
+}
+
+func (c *ReadWriteSplitClient) Pipeline() Pipeliner {
+	pipe := Pipeline{
+		exec: func(ctx context.Context, cmds []Cmder) error {
+			var replica *splitReplica
+			if c.cmdsAreReadOnly(ctx, cmds) {
+				replica = c.pickReplica()
+			}
+			if replica == nil {
+				return c.execPipeline(ctx, c.primary, cmds)
+			}
+
+			err := c.execPipeline(ctx, replica.client, cmds)
+			if isServerFailure(err) {
+				replica.setHealthy(false)
+				return c.execPipeline(ctx, c.primary, cmds)
+			}
+			return err
+		},
+	}
+	pipe.init()
+	return &pipe
+}
+
+func (c *ReadWriteSplitClient) execPipeline(ctx context.Context, client *Client, cmds []Cmder) error {
+	inner := client.Pipeline()
+	for _, cmd := range cmds {
+		_ = inner.Process(ctx, cmd)
+	}
+	_, err := inner.Exec(ctx)
+	return err
+}
+
+func (c *ReadWriteSplitClient) TxPipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.primary.TxPipelined(ctx, fn)
+}
+
+func (c *ReadWriteSplitClient) TxPipeline() Pipeliner {
+	return c.primary.TxPipeline()
+}
+
+// Close stops the health checks and closes the clients of the primary and
+// replicas.
+func (c *ReadWriteSplitClient) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	c.wg.Wait()
+
+	err := c.primary.Close()
+	for _, r := range c.replicas {
+		if err2 := r.client.Close(); err == nil {
+			err = err2
+		}
+	}
+	return err
+}
+
+func (c *ReadWriteSplitClient) cmdIsReadOnly(ctx context.Context, name string) bool {
+	if isRegisteredReadOnly(name) {
+		return true
+	}
+	cmdsInfo, err := c.cmdsInfoCache.Get(ctx)
+	if err != nil {
+		internal.Log(ctx, c.primary.opt.Logger, internal.LevelWarn, "redis: getting command info", "error", err)
+		return false
+	}
+	info := cmdsInfo[name]
+	return info != nil && info.ReadOnly
+}
+
+func (c *ReadWriteSplitClient) cmdsAreReadOnly(ctx context.Context, cmds []Cmder) bool {
+	for _, cmd := range cmds {
+		if !c.cmdIsReadOnly(ctx, cmd.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickReplica returns a random healthy replica, or nil.
+func (c *ReadWriteSplitClient) pickReplica() *splitReplica {
+	var healthy []*splitReplica
+	for _, r := range c.replicas {
+		if r.isHealthy() {
+			healthy = append(healthy, r)
+		}
+	}
+	switch len(healthy) {
+	case 0:
+		return nil
+	case 1:
+		return healthy[0]
+	}
+	return healthy[rand.Intn(len(healthy))]
+}
+
+func (c *ReadWriteSplitClient) healthCheck() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.opt.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		c.checkReplicas()
+
+		select {
+		case <-ticker.C:
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+func (c *ReadWriteSplitClient) checkReplicas() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opt.HealthCheckInterval)
+	defer cancel()
+
+	var primaryOffset int64 = -1
+	if c.opt.MaxReplicationLag > 0 {
+		info, err := c.primary.InfoMap(ctx, "replication").Result()
+		if err == nil {
+			primaryOffset, _ = strconv.ParseInt(info["Replication"]["master_repl_offset"], 10, 64)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range c.replicas {
+		wg.Add(1)
+		go func(r *splitReplica) {
+			defer wg.Done()
+			r.setHealthy(c.replicaIsHealthy(ctx, r.client, primaryOffset))
+		}(r)
+	}
+	wg.Wait()
+}
+
+// replicaIsHealthy reports whether the replica is connected to the primary
+// and, when MaxReplicationLag is set, lags less than MaxReplicationLag bytes
+// behind primaryOffset, the offset of the primary or -1 if it is unknown.
+func (c *ReadWriteSplitClient) replicaIsHealthy(ctx context.Context, replica *Client, primaryOffset int64) bool {
+	info, err := replica.InfoMap(ctx, "replication").Result()
+	if err != nil {
+		return false
+	}
+	repl := info["Replication"]
+	if repl["role"] != "slave" || repl["master_link_status"] != "up" {
+		return false
+	}
+	if c.opt.MaxReplicationLag <= 0 {
+		return true
+	}
+	if primaryOffset < 0 {
+		return false
+	}
+	offset, err := strconv.ParseInt(repl["slave_repl_offset"], 10, 64)
+	return err == nil && primaryOffset-offset <= c.opt.MaxReplicationLag
+}
@@ -0,0 +1,55 @@
+package redis_test
+
+import (
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+var _ = Describe("ReadWriteSplitClient", func() {
+	var client *redis.ReadWriteSplitClient
+	var rec *redistest.Recorder
+
+	newClient := func(replicaAddrs ...string) {
+		client = redis.NewReadWriteSplitClient(&redis.ReadWriteSplitOptions{
+			Primary:             redisOptions(),
+			ReplicaAddrs:        replicaAddrs,
+			HealthCheckInterval: 10 * time.Millisecond,
+		})
+		rec = redistest.NewRecorder()
+		client.Primary().AddHook(rec)
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	}
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should send the reads to the primary when the replicas are not replicating", func() {
+		// the primary itself is not a replica of the primary
+		newClient(redisAddr)
+		time.Sleep(50 * time.Millisecond)
+
+		Expect(client.Set(ctx, "key", "value", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Get(ctx, "key").Val()).To(Equal("value"))
+		Expect(rec.ContainsSequence("set key value", "get key")).To(BeTrue())
+	})
+
+	It("should send the reads to the primary when the replicas are down", func() {
+		newClient("127.0.0.1:1")
+		time.Sleep(50 * time.Millisecond)
+
+		Expect(client.Set(ctx, "key", "value", 0).Err()).NotTo(HaveOccurred())
+		cmds, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Get(ctx, "key")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmds[0].(*redis.StringCmd).Val()).To(Equal("value"))
+		Expect(rec.Contains("get key")).To(BeTrue())
+	})
+})
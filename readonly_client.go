@@ -0,0 +1,149 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// readOnlySafeCmds are the commands, and the subcommands of the container
+// commands, that ReadOnlyClient allows although COMMAND does not flag them
+// as read-only.
+var readOnlySafeCmds = map[string]struct{}{
+	"ping":     {},
+	"echo":     {},
+	"info":     {},
+	"time":     {},
+	"lastsave": {},
+	"role":     {},
+	"command":  {},
+
+	"client|list":     {},
+	"client|info":     {},
+	"client|getname":  {},
+	"client|id":       {},
+	"config|get":      {},
+	"slowlog|get":     {},
+	"slowlog|len":     {},
+	"latency|latest":  {},
+	"latency|history": {},
+	"memory|usage":    {},
+	"memory|stats":    {},
+	"object|encoding": {},
+	"object|freq":     {},
+	"object|idletime": {},
+	"object|refcount": {},
+	"xinfo|stream":    {},
+	"xinfo|groups":    {},
+	"xinfo|consumers": {},
+	"cluster|info":    {},
+	"cluster|nodes":   {},
+	"cluster|shards":  {},
+	"cluster|slots":   {},
+}
+
+// ReadOnlyClient returns a Cmdable that processes the read-only commands with
+// client, and rejects the other commands with a *GuardError before they are
+// sent, e.g. for the components that must not modify the data, such as
+// dashboards.
+//
+// The read-only commands are the commands flagged as such by the COMMAND
+// command, which is issued once on first use, the commands registered with
+// RegisterReadOnlyCommands, and a few inspection commands such as PING,
+// INFO, CONFIG GET or CLIENT LIST. The commands are rejected when COMMAND
+// fails. The pipelines and transactions are rejected when one of their
+// commands is not read-only.
+func ReadOnlyClient(client UniversalClient) Cmdable {
+	c := &readOnlyClient{
+		client: client,
+	}
+	c.cmdsInfoCache = newCmdsInfoCache(func(ctx context.Context) (map[string]*CommandInfo, error) {
+		return client.Command(ctx).Result()
+	})
+	c.cmdable = c.Process
+	return c
+}
+
+type readOnlyClient struct {
+	cmdable
+
+	client        UniversalClient
+	cmdsInfoCache *cmdsInfoCache
+}
+
+// Do creates a Cmd from the args and processes the cmd when it is read-only.
+func (c *readOnlyClient) Do(ctx context.Context, args ...interface{}) *Cmd {
+	cmd := NewCmd(ctx, args...)
+	_ = c.Process(ctx, cmd)
+	return cmd
+}
+
+// Process processes cmd with the wrapped client when it is read-only.
+func (c *readOnlyClient) Process(ctx context.Context, cmd Cmder) error {
+	if err := c.check(ctx, cmd); err != nil {
+		cmd.SetErr(err)
+		return err
+	}
+	return c.client.Process(ctx, cmd)
+}
+
+func (c *readOnlyClient) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.Pipeline().Pipelined(ctx, fn)
+}
+
+func (c *readOnlyClient) Pipeline() Pipeliner {
+	return c.pipeline(c.client.Pipeline)
+}
+
+func (c *readOnlyClient) TxPipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return c.TxPipeline().Pipelined(ctx, fn)
+}
+
+func (c *readOnlyClient) TxPipeline() Pipeliner {
+	return c.pipeline(c.client.TxPipeline)
+}
+
+func (c *readOnlyClient) pipeline(newPipeline func() Pipeliner) Pipeliner {
+	pipe := Pipeline{
+		exec: func(ctx context.Context, cmds []Cmder) error {
+			for _, cmd := range cmds {
+				if err := c.check(ctx, cmd); err != nil {
+					setCmdsErr(cmds, err)
+					return err
+				}
+			}
+
+			inner := newPipeline()
+			for _, cmd := range cmds {
+				_ = inner.Process(ctx, cmd)
+			}
+			_, err := inner.Exec(ctx)
+			return err
+		},
+	}
+	pipe.init()
+	return &pipe
+}
+
+// check returns the error of cmd when it is not read-only.
+func (c *readOnlyClient) check(ctx context.Context, cmd Cmder) error {
+	name := cmd.Name()
+	if _, ok := readOnlySafeCmds[name]; ok || isRegisteredReadOnly(name) {
+		return nil
+	}
+	if len(cmd.Args()) > 1 {
+		sub := name + "|" + internal.ToLower(cmd.stringArg(1))
+		if _, ok := readOnlySafeCmds[sub]; ok {
+			return nil
+		}
+	}
+
+	cmdsInfo, err := c.cmdsInfoCache.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if info := cmdsInfo[name]; info != nil && info.ReadOnly {
+		return nil
+	}
+	return &GuardError{Command: name}
+}
@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	rdb := NewClientStub([]byte("+OK\r\n")).Cmdable.(*Client)
+	ro := ReadOnlyClient(rdb)
+	ro.(*readOnlyClient).cmdsInfoCache = newCmdsInfoCache(func(ctx context.Context) (map[string]*CommandInfo, error) {
+		return map[string]*CommandInfo{
+			"get": {Name: "get", ReadOnly: true},
+			"set": {Name: "set"},
+		}, nil
+	})
+
+	for _, cmd := range []Cmder{
+		ro.Get(ctx, "key"),
+		ro.Ping(ctx),
+		ro.ConfigGet(ctx, "maxmemory"),
+		ro.JSONGet(ctx, "key"),
+	} {
+		var guardErr *GuardError
+		if errors.As(cmd.Err(), &guardErr) {
+			t.Errorf("%s: unexpected error: %s", cmd.Name(), cmd.Err())
+		}
+	}
+
+	for _, cmd := range []Cmder{
+		ro.Set(ctx, "key", "value", 0),
+		ro.ConfigSet(ctx, "maxmemory", "1"),
+		ro.FlushAll(ctx),
+		ro.(*readOnlyClient).Do(ctx, "unknown"),
+	} {
+		var guardErr *GuardError
+		if !errors.As(cmd.Err(), &guardErr) || guardErr.Command != cmd.Name() {
+			t.Errorf("%s: got %v", cmd.Name(), cmd.Err())
+		}
+	}
+
+	get := NewStringCmd(ctx, "get", "key")
+	_, err := ro.TxPipelined(ctx, func(pipe Pipeliner) error {
+		_ = pipe.Process(ctx, get)
+		pipe.Incr(ctx, "counter")
+		return nil
+	})
+	if err == nil || err.Error() != `redis: command "incr" is not allowed` || get.Err() != err {
+		t.Fatalf("got %v, %v", err, get.Err())
+	}
+}
+
+func TestReadOnlyClientCommandError(t *testing.T) {
+	rdb := NewClientStub([]byte("-ERR unknown command\r\n")).Cmdable.(*Client)
+	ro := ReadOnlyClient(rdb)
+	if err := ro.Get(ctx, "key").Err(); err == nil || err.Error() != "ERR unknown command" {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"strings"
+	"sync"
+)
+
+// readOnlyCmds are the module commands ClusterClient may send to replicas
+// when ReadOnly is enabled, whether or not COMMAND reports them as read-only.
+// FT.AGGREGATE and FT.CURSOR are left out because a cursor must be read
+// from the node that created it.
+var readOnlyCmds = struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+}{
+	names: make(map[string]struct{}),
+}
+
+func init() {
+	RegisterReadOnlyCommands(
+		// RedisJSON
+		"JSON.GET", "JSON.MGET", "JSON.TYPE", "JSON.STRLEN", "JSON.OBJLEN",
+		"JSON.OBJKEYS", "JSON.ARRLEN", "JSON.ARRINDEX", "JSON.RESP",
+		// RediSearch
+		"FT.SEARCH", "FT.PROFILE", "FT.INFO", "FT.EXPLAIN", "FT.EXPLAINCLI",
+		"FT.SPELLCHECK", "FT.TAGVALS", "FT.SUGGET", "FT.SUGLEN", "FT.DICTDUMP",
+		"FT.SYNDUMP", "FT._LIST",
+		// RedisTimeSeries
+		"TS.GET", "TS.MGET", "TS.RANGE", "TS.REVRANGE", "TS.MRANGE",
+		"TS.MREVRANGE", "TS.INFO", "TS.QUERYINDEX",
+		// RedisBloom
+		"BF.EXISTS", "BF.MEXISTS", "BF.INFO", "BF.CARD", "BF.SCANDUMP",
+		"CF.EXISTS", "CF.MEXISTS", "CF.COUNT", "CF.INFO", "CF.SCANDUMP",
+		"CMS.QUERY", "CMS.INFO",
+		"TOPK.QUERY", "TOPK.COUNT", "TOPK.LIST", "TOPK.INFO",
+		"TDIGEST.INFO", "TDIGEST.MIN", "TDIGEST.MAX", "TDIGEST.QUANTILE",
+		"TDIGEST.CDF", "TDIGEST.RANK", "TDIGEST.REVRANK", "TDIGEST.BYRANK",
+		"TDIGEST.BYREVRANK", "TDIGEST.TRIMMED_MEAN",
+		// Vector sets
+		"VCARD", "VDIM", "VEMB", "VGETATTR", "VINFO", "VLINKS", "VRANDMEMBER", "VSIM",
+	)
+}
+
+// RegisterReadOnlyCommands registers names as read-only commands, typically
+// commands of a module the COMMAND command does not flag as read-only.
+// With ClusterOptions.ReadOnly they are routed to replicas like the
+// built-in read-only commands.
+func RegisterReadOnlyCommands(names ...string) {
+	readOnlyCmds.mu.Lock()
+	defer readOnlyCmds.mu.Unlock()
+	for _, name := range names {
+		readOnlyCmds.names[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+// UnregisterReadOnlyCommands removes names registered with RegisterReadOnlyCommands,
+// e.g. to keep reads that must see their own writes on the masters.
+func UnregisterReadOnlyCommands(names ...string) {
+	readOnlyCmds.mu.Lock()
+	defer readOnlyCmds.mu.Unlock()
+	for _, name := range names {
+		delete(readOnlyCmds.names, strings.ToLower(name))
+	}
+}
+
+// isRegisteredReadOnly reports whether the command name, as returned by
+// Cmder.Name, was registered with RegisterReadOnlyCommands.
+func isRegisteredReadOnly(name string) bool {
+	readOnlyCmds.mu.RLock()
+	_, ok := readOnlyCmds.names[name]
+	readOnlyCmds.mu.RUnlock()
+	return ok
+}
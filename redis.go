@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,7 +22,10 @@ type Scanner = hscan.Scanner
 // Nil reply returned by Redis when key does not exist.
 const Nil = proto.Nil
 
-// SetLogger set custom log
+// SetLogger sets the global logger, which logs the messages of the clients
+// without Options.Logger, and those not related to a client.
+//
+// Deprecated: use Options.Logger, which can be set for each client.
 func SetLogger(logger internal.Logging) {
 	internal.Logger = logger
 }
@@ -41,17 +45,29 @@ type (
 )
 
 type hooksMixin struct {
+	// hooksMu serializes the changes of the hooks, which are published in
+	// current for the commands to load them without locking.
 	hooksMu *sync.Mutex
 
 	slice   []Hook
 	initial hooks
-	current hooks
+	current *atomic.Value // *chainedHooks
+}
+
+// chainedHooks are the hooks of hooksMixin.slice chained, never modified
+// once published.
+type chainedHooks struct {
+	hooks
+	slice []Hook
 }
 
 func (hs *hooksMixin) initHooks(hooks hooks) {
 	hs.hooksMu = new(sync.Mutex)
+	hs.current = new(atomic.Value)
 	hs.initial = hooks
+	hs.hooksMu.Lock()
 	hs.chain()
+	hs.hooksMu.Unlock()
 }
 
 type hooks struct {
@@ -112,36 +128,85 @@ func (h *hooks) setDefaults() {
 //
 // Please note: "next(ctx, cmd)" is very important, it will call the next hook,
 // if "next(ctx, cmd)" is not executed, the redis command will not be executed.
+//
+// AddHook can be called while the commands are processed, which use the
+// hook once they start after it is added.
 func (hs *hooksMixin) AddHook(hook Hook) {
-	hs.slice = append(hs.slice, hook)
+	hs.hooksMu.Lock()
+	defer hs.hooksMu.Unlock()
+
+	// the slice is copied, as the commands being processed may still use it
+	l := len(hs.slice)
+	hs.slice = append(hs.slice[:l:l], hook)
 	hs.chain()
 }
 
-func (hs *hooksMixin) chain() {
-	hs.initial.setDefaults()
+// RemoveHook removes the hook most recently added with AddHook that is equal
+// to hook, if any. The commands already being processed keep using the hooks
+// they started with, and the next ones are processed without hook.
+//
+// The hooks are compared with ==, so removing a hook requires the value it
+// was added with, e.g. the same pointer.
+func (hs *hooksMixin) RemoveHook(hook Hook) {
+	hs.hooksMu.Lock()
+	defer hs.hooksMu.Unlock()
+
+	for i := len(hs.slice) - 1; i >= 0; i-- {
+		if !sameHook(hs.slice[i], hook) {
+			continue
+		}
+		slice := make([]Hook, 0, len(hs.slice)-1)
+		slice = append(slice, hs.slice[:i]...)
+		hs.slice = append(slice, hs.slice[i+1:]...)
+		hs.chain()
+		return
+	}
+}
 
+// Hooks returns a copy of the hooks added with AddHook, in the order they
+// were added.
+func (hs *hooksMixin) Hooks() []Hook {
 	hs.hooksMu.Lock()
 	defer hs.hooksMu.Unlock()
 
-	hs.current.dial = hs.initial.dial
-	hs.current.process = hs.initial.process
-	hs.current.pipeline = hs.initial.pipeline
-	hs.current.txPipeline = hs.initial.txPipeline
+	hooks := make([]Hook, len(hs.slice))
+	copy(hooks, hs.slice)
+	return hooks
+}
 
+// sameHook reports whether a and b are equal, without panicking on the hooks
+// whose type is not comparable.
+func sameHook(a, b Hook) bool {
+	typ := reflect.TypeOf(a)
+	if typ == nil || typ != reflect.TypeOf(b) || !typ.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// chain chains the hooks and publishes them, with hs.hooksMu locked.
+func (hs *hooksMixin) chain() {
+	hs.initial.setDefaults()
+
+	current := &chainedHooks{
+		hooks: hs.initial,
+		slice: hs.slice,
+	}
 	for i := len(hs.slice) - 1; i >= 0; i-- {
-		if wrapped := hs.slice[i].DialHook(hs.current.dial); wrapped != nil {
-			hs.current.dial = wrapped
+		if wrapped := hs.slice[i].DialHook(current.dial); wrapped != nil {
+			current.dial = wrapped
 		}
-		if wrapped := hs.slice[i].ProcessHook(hs.current.process); wrapped != nil {
-			hs.current.process = wrapped
+		if wrapped := hs.slice[i].ProcessHook(current.process); wrapped != nil {
+			current.process = wrapped
 		}
-		if wrapped := hs.slice[i].ProcessPipelineHook(hs.current.pipeline); wrapped != nil {
-			hs.current.pipeline = wrapped
+		if wrapped := hs.slice[i].ProcessPipelineHook(current.pipeline); wrapped != nil {
+			current.pipeline = wrapped
 		}
-		if wrapped := hs.slice[i].ProcessPipelineHook(hs.current.txPipeline); wrapped != nil {
-			hs.current.txPipeline = wrapped
+		if wrapped := hs.slice[i].ProcessPipelineHook(current.txPipeline); wrapped != nil {
+			current.txPipeline = wrapped
 		}
 	}
+	hs.current.Store(current)
 }
 
 func (hs *hooksMixin) clone() hooksMixin {
@@ -152,12 +217,15 @@ func (hs *hooksMixin) clone() hooksMixin {
 	l := len(clone.slice)
 	clone.slice = clone.slice[:l:l]
 	clone.hooksMu = new(sync.Mutex)
+	clone.current = new(atomic.Value)
+	clone.current.Store(hs.current.Load())
 	return clone
 }
 
 func (hs *hooksMixin) withProcessHook(ctx context.Context, cmd Cmder, hook ProcessHook) error {
-	for i := len(hs.slice) - 1; i >= 0; i-- {
-		if wrapped := hs.slice[i].ProcessHook(hook); wrapped != nil {
+	slice := hs.hookSlice()
+	for i := len(slice) - 1; i >= 0; i-- {
+		if wrapped := slice[i].ProcessHook(hook); wrapped != nil {
 			hook = wrapped
 		}
 	}
@@ -167,30 +235,39 @@ func (hs *hooksMixin) withProcessHook(ctx context.Context, cmd Cmder, hook Proce
 func (hs *hooksMixin) withProcessPipelineHook(
 	ctx context.Context, cmds []Cmder, hook ProcessPipelineHook,
 ) error {
-	for i := len(hs.slice) - 1; i >= 0; i-- {
-		if wrapped := hs.slice[i].ProcessPipelineHook(hook); wrapped != nil {
+	slice := hs.hookSlice()
+	for i := len(slice) - 1; i >= 0; i-- {
+		if wrapped := slice[i].ProcessPipelineHook(hook); wrapped != nil {
 			hook = wrapped
 		}
 	}
 	return hook(ctx, cmds)
 }
 
+// hookSlice returns the hooks, whose slice is replaced rather than modified.
+func (hs *hooksMixin) hookSlice() []Hook {
+	return hs.chained().slice
+}
+
+// chained returns the chained hooks, without locking hs.hooksMu.
+func (hs *hooksMixin) chained() *chainedHooks {
+	return hs.current.Load().(*chainedHooks)
+}
+
 func (hs *hooksMixin) dialHook(ctx context.Context, network, addr string) (net.Conn, error) {
-	hs.hooksMu.Lock()
-	defer hs.hooksMu.Unlock()
-	return hs.current.dial(ctx, network, addr)
+	return hs.chained().dial(ctx, network, addr)
 }
 
 func (hs *hooksMixin) processHook(ctx context.Context, cmd Cmder) error {
-	return hs.current.process(ctx, cmd)
+	return hs.chained().process(ctx, cmd)
 }
 
 func (hs *hooksMixin) processPipelineHook(ctx context.Context, cmds []Cmder) error {
-	return hs.current.pipeline(ctx, cmds)
+	return hs.chained().pipeline(ctx, cmds)
 }
 
 func (hs *hooksMixin) processTxPipelineHook(ctx context.Context, cmds []Cmder) error {
-	return hs.current.txPipeline(ctx, cmds)
+	return hs.chained().txPipeline(ctx, cmds)
 }
 
 //------------------------------------------------------------------------------
@@ -199,6 +276,26 @@ type baseClient struct {
 	opt      *Options
 	connPool pool.Pooler
 
+	// pushes and maint are nil unless push notifications are enabled.
+	pushes *pushProcessor
+	maint  *maintNotifications
+	// creds is nil unless Options.StreamingCredentialsProvider is set.
+	creds *streamingCredentials
+	// unwatchTLSFiles is nil unless Options.TLSFiles is set.
+	unwatchTLSFiles func()
+	// unwatchDNS is nil unless Options.ResolveInterval is set.
+	unwatchDNS func()
+	// stats is nil unless Options.StatsEnabled is set.
+	stats *cmdsStats
+	// cache is nil unless Options.ClientCache is set.
+	cache *clientCache
+	// auto is nil unless Options.AutoPipeline is set.
+	auto *autoPipeliner
+	// hedgedCmds is nil unless Options.HedgeDelay is set.
+	hedgedCmds map[string]struct{}
+	// breaker is nil unless Options.CircuitBreaker is set.
+	breaker *circuitBreaker
+
 	onClose func() error // hook called when client is closed
 }
 
@@ -214,6 +311,9 @@ func (c *baseClient) withTimeout(timeout time.Duration) *baseClient {
 
 	clone := c.clone()
 	clone.opt = opt
+	if c.auto != nil {
+		clone.auto = newAutoPipeliner(clone)
+	}
 
 	return clone
 }
@@ -244,18 +344,34 @@ func (c *baseClient) getConn(ctx context.Context) (*pool.Conn, error) {
 			return nil, err
 		}
 	}
+	if c.breaker != nil {
+		if err := c.breaker.Allow(); err != nil {
+			if c.opt.Limiter != nil {
+				c.opt.Limiter.ReportResult(err)
+			}
+			return nil, err
+		}
+	}
 
 	cn, err := c._getConn(ctx)
 	if err != nil {
-		if c.opt.Limiter != nil {
-			c.opt.Limiter.ReportResult(err)
-		}
+		c.reportResult(err)
 		return nil, err
 	}
 
 	return cn, nil
 }
 
+// reportResult reports the result of an operation allowed by getConn.
+func (c *baseClient) reportResult(err error) {
+	if c.opt.Limiter != nil {
+		c.opt.Limiter.ReportResult(err)
+	}
+	if c.breaker != nil {
+		c.breaker.ReportResult(err)
+	}
+}
+
 func (c *baseClient) _getConn(ctx context.Context) (*pool.Conn, error) {
 	cn, err := c.connPool.Get(ctx)
 	if err != nil {
@@ -263,6 +379,16 @@ func (c *baseClient) _getConn(ctx context.Context) (*pool.Conn, error) {
 	}
 
 	if cn.Inited {
+		if err := c.pushes.processPending(ctx, cn, c.opt.ReadTimeout); err != nil {
+			c.connPool.Remove(ctx, cn, err)
+			return nil, err
+		}
+		if c.creds != nil && c.creds.stale(cn) {
+			if err := c.reauth(ctx, cn); err != nil {
+				c.connPool.Remove(ctx, cn, err)
+				return nil, err
+			}
+		}
 		return cn, nil
 	}
 
@@ -285,7 +411,11 @@ func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 
 	var err error
 	username, password := c.opt.Username, c.opt.Password
-	if c.opt.CredentialsProviderContext != nil {
+	if c.creds != nil {
+		if username, password, cn.CredentialsGeneration, err = c.creds.credentials(ctx); err != nil {
+			return err
+		}
+	} else if c.opt.CredentialsProviderContext != nil {
 		if username, password, err = c.opt.CredentialsProviderContext(ctx); err != nil {
 			return err
 		}
@@ -295,6 +425,7 @@ func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 
 	connPool := pool.NewSingleConnPool(c.connPool, cn)
 	conn := newConn(c.opt, connPool)
+	conn.pushes = c.pushes
 
 	var auth bool
 	protocol := c.opt.Protocol
@@ -318,6 +449,10 @@ func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 		return err
 	}
 
+	if c.cache != nil && !auth {
+		return errors.New("redis: client-side caching requires RESP3, which the server does not support")
+	}
+
 	_, err = conn.Pipelined(ctx, func(pipe Pipeliner) error {
 		if !auth && password != "" {
 			if username != "" {
@@ -339,6 +474,10 @@ func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 			pipe.ClientSetName(ctx, c.opt.ClientName)
 		}
 
+		if c.cache != nil {
+			_ = pipe.Process(ctx, NewStatusCmd(ctx, "client", "tracking", "on"))
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -357,16 +496,29 @@ func (c *baseClient) initConn(ctx context.Context, cn *pool.Conn) error {
 		_, _ = p.Exec(ctx)
 	}
 
+	if c.maint != nil && auth && protocol == 3 {
+		cmd := NewStatusCmd(ctx, c.maint.enableArgs()...)
+		_ = conn.Process(ctx, cmd)
+		if err := cmd.Err(); err != nil {
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "redis: can't enable maintenance notifications",
+				"error", err)
+		}
+	}
+
 	if c.opt.OnConnect != nil {
-		return c.opt.OnConnect(ctx, conn)
+		if err := c.opt.OnConnect(ctx, conn); err != nil {
+			return err
+		}
+	}
+
+	if c.opt.OnConnectionEvent != nil {
+		c.opt.OnConnectionEvent(newConnEvent(ConnHandshakeCompleted, c.opt, cn, nil))
 	}
 	return nil
 }
 
 func (c *baseClient) releaseConn(ctx context.Context, cn *pool.Conn, err error) {
-	if c.opt.Limiter != nil {
-		c.opt.Limiter.ReportResult(err)
-	}
+	c.reportResult(err)
 
 	if isBadConn(err, false, c.opt.Addr) {
 		c.connPool.Remove(ctx, cn, err)
@@ -397,12 +549,55 @@ func (c *baseClient) dial(ctx context.Context, network, addr string) (net.Conn,
 	return c.opt.Dialer(ctx, network, addr)
 }
 
-func (c *baseClient) process(ctx context.Context, cmd Cmder) error {
+func (c *baseClient) process(ctx context.Context, cmd Cmder) (err error) {
+	start := time.Now()
+	defer func() {
+		// set before the hooks return, for them to log the command
+		cmd.SetErr(err)
+		cmd.setDuration(time.Since(start))
+		if c.stats != nil {
+			c.stats.record(cmd)
+		}
+	}()
+
+	if c.opt.DryRun {
+		return dryRun(ctx, c.opt.Logger, cmd, c.getAddr(), false)
+	}
+
+	var cacheID string
+	if c.cache != nil {
+		var cacheable bool
+		if cacheID, cacheable = c.cache.cacheable(cmd); cacheable {
+			if c.cache.get(cacheID, cmd) {
+				return cmd.Err()
+			}
+		} else {
+			c.cache.invalidateCmd(cmd)
+		}
+	}
+
+	callOpt := callOptions(ctx)
+	hedged := cacheID == "" && c.hedged(cmd)
+	if c.auto != nil && !hedged && cacheID == "" && callOpt == nil && cmd.readTimeout() == nil {
+		return c.auto.process(ctx, cmd)
+	}
+
+	if callOpt != nil && callOpt.RetryPolicy != nil {
+		return c.processRetryPolicy(ctx, cmd, callOpt, cacheID, hedged)
+	}
+
 	var lastErr error
-	for attempt := 0; attempt <= c.opt.MaxRetries; attempt++ {
-		attempt := attempt
+	for attempt := 0; attempt <= callOpt.maxRetries(c.opt.MaxRetries); attempt++ {
+		if attempt > 0 {
+			if err := internal.Sleep(ctx, c.retryBackoff(attempt)); err != nil {
+				return err
+			}
+			if c.stats != nil {
+				c.stats.recordRetry(cmd)
+			}
+		}
 
-		retry, err := c._process(ctx, cmd, attempt)
+		retry, err := c.processAttempt(ctx, cmd, callOpt, cacheID, hedged)
 		if err == nil || !retry {
 			return err
 		}
@@ -412,23 +607,60 @@ func (c *baseClient) process(ctx context.Context, cmd Cmder) error {
 	return lastErr
 }
 
-func (c *baseClient) _process(ctx context.Context, cmd Cmder, attempt int) (bool, error) {
-	if attempt > 0 {
-		if err := internal.Sleep(ctx, c.retryBackoff(attempt)); err != nil {
-			return false, err
+// processRetryPolicy processes cmd retried as decided by
+// CallOptions.RetryPolicy.
+func (c *baseClient) processRetryPolicy(
+	ctx context.Context, cmd Cmder, callOpt *CallOptions, cacheID string, hedged bool,
+) error {
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.processAttempt(ctx, cmd, callOpt, cacheID, hedged)
+		if err == nil {
+			return nil
+		}
+
+		retry, backoff := callOpt.RetryPolicy.Retry(cmd, attempt, err, retryable)
+		if !retry {
+			return err
+		}
+		if err := internal.Sleep(ctx, backoff); err != nil {
+			return err
+		}
+		if c.stats != nil {
+			c.stats.recordRetry(cmd)
 		}
 	}
+}
 
+// processAttempt processes an attempt of cmd, hedged when hedged is set, and
+// reports whether the error is retryable.
+func (c *baseClient) processAttempt(
+	ctx context.Context, cmd Cmder, callOpt *CallOptions, cacheID string, hedged bool,
+) (bool, error) {
+	if hedged {
+		return c.processHedged(ctx, cmd, callOpt)
+	}
+	return c._process(ctx, cmd, callOpt, cacheID)
+}
+
+func (c *baseClient) _process(
+	ctx context.Context, cmd Cmder, callOpt *CallOptions, cacheID string,
+) (bool, error) {
 	retryTimeout := uint32(0)
 	if err := c.withConn(ctx, func(ctx context.Context, cn *pool.Conn) error {
-		if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
+		writeTimeout := c.maint.timeout(callOpt.writeTimeout(c.opt.WriteTimeout))
+		if err := cn.WithWriter(c.context(ctx), writeTimeout, func(wr *proto.Writer) error {
 			return writeCmd(wr, cmd)
 		}); err != nil {
 			atomic.StoreUint32(&retryTimeout, 1)
 			return err
 		}
 
-		if err := cn.WithReader(c.context(ctx), c.cmdTimeout(cmd), cmd.readReply); err != nil {
+		if err := cn.WithReader(c.context(ctx), c.cmdTimeout(cmd, callOpt), func(rd *proto.Reader) error {
+			if cacheID != "" {
+				return c.cache.readReply(ctx, c.pushes, rd, cn, cacheID, cmd)
+			}
+			return c.pushes.readReply(ctx, rd, cmd)
+		}); err != nil {
 			if cmd.readTimeout() == nil {
 				atomic.StoreUint32(&retryTimeout, 1)
 			} else {
@@ -450,7 +682,7 @@ func (c *baseClient) retryBackoff(attempt int) time.Duration {
 	return internal.RetryBackoff(attempt, c.opt.MinRetryBackoff, c.opt.MaxRetryBackoff)
 }
 
-func (c *baseClient) cmdTimeout(cmd Cmder) time.Duration {
+func (c *baseClient) cmdTimeout(cmd Cmder, callOpt *CallOptions) time.Duration {
 	if timeout := cmd.readTimeout(); timeout != nil {
 		t := *timeout
 		if t == 0 {
@@ -458,7 +690,17 @@ func (c *baseClient) cmdTimeout(cmd Cmder) time.Duration {
 		}
 		return t + 10*time.Second
 	}
-	return c.opt.ReadTimeout
+	return c.maint.timeout(callOpt.readTimeout(c.opt.ReadTimeout))
+}
+
+// readTimeout returns ReadTimeout, relaxed during the maintenance windows.
+func (c *baseClient) readTimeout() time.Duration {
+	return c.maint.timeout(c.opt.ReadTimeout)
+}
+
+// writeTimeout returns WriteTimeout, relaxed during the maintenance windows.
+func (c *baseClient) writeTimeout() time.Duration {
+	return c.maint.timeout(c.opt.WriteTimeout)
 }
 
 // Close closes the client, releasing any open resources.
@@ -467,6 +709,15 @@ func (c *baseClient) cmdTimeout(cmd Cmder) time.Duration {
 // long-lived and shared between many goroutines.
 func (c *baseClient) Close() error {
 	var firstErr error
+	if c.creds != nil {
+		c.creds.close()
+	}
+	if c.unwatchTLSFiles != nil {
+		c.unwatchTLSFiles()
+	}
+	if c.unwatchDNS != nil {
+		c.unwatchDNS()
+	}
 	if c.onClose != nil {
 		if err := c.onClose(); err != nil {
 			firstErr = err
@@ -483,7 +734,11 @@ func (c *baseClient) getAddr() string {
 }
 
 func (c *baseClient) processPipeline(ctx context.Context, cmds []Cmder) error {
-	if err := c.generalProcessPipeline(ctx, cmds, c.pipelineProcessCmds); err != nil {
+	p := c.pipelineProcessCmds
+	if isFireAndForget(cmds) {
+		p = c.fireAndForgetProcessCmds
+	}
+	if err := c.generalProcessPipeline(ctx, cmds, p); err != nil {
 		return err
 	}
 	return cmdsFirstErr(cmds)
@@ -501,6 +756,33 @@ type pipelineProcessor func(context.Context, *pool.Conn, []Cmder) (bool, error)
 func (c *baseClient) generalProcessPipeline(
 	ctx context.Context, cmds []Cmder, p pipelineProcessor,
 ) error {
+	start := time.Now()
+	defer func() {
+		d := time.Since(start)
+		for _, cmd := range cmds {
+			cmd.setDuration(d)
+		}
+		if c.stats != nil {
+			c.stats.recordCmds(cmds)
+		}
+	}()
+
+	if c.opt.DryRun {
+		return dryRunCmds(ctx, c.opt.Logger, cmds, c.getAddr(), false)
+	}
+
+	if c.cache != nil {
+		for _, cmd := range cmds {
+			c.cache.invalidateCmd(cmd)
+		}
+	}
+
+	return c.pipelineWithRetries(ctx, cmds, p)
+}
+
+// pipelineWithRetries processes cmds with p, retrying the pipeline on the
+// retryable errors.
+func (c *baseClient) pipelineWithRetries(ctx context.Context, cmds []Cmder, p pipelineProcessor) error {
 	var lastErr error
 	for attempt := 0; attempt <= c.opt.MaxRetries; attempt++ {
 		if attempt > 0 {
@@ -508,6 +790,9 @@ func (c *baseClient) generalProcessPipeline(
 				setCmdsErr(cmds, err)
 				return err
 			}
+			if c.stats != nil {
+				c.stats.recordRetry(cmds...)
+			}
 		}
 
 		// Enable retries by default to retry dial errors returned by withConn.
@@ -527,25 +812,42 @@ func (c *baseClient) generalProcessPipeline(
 func (c *baseClient) pipelineProcessCmds(
 	ctx context.Context, cn *pool.Conn, cmds []Cmder,
 ) (bool, error) {
-	if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
-		return writeCmds(wr, cmds)
-	}); err != nil {
-		setCmdsErr(cmds, err)
-		return true, err
-	}
+	// the pipeline is only retried while its first batch is processed
+	for first := true; len(cmds) > 0; first = false {
+		var n int
+		if err := cn.WithWriter(c.context(ctx), c.writeTimeout(), func(wr *proto.Writer) error {
+			var err error
+			n, err = writeCmdsBatch(wr, cmds, c.opt.PipelineMaxCmds, c.opt.PipelineMaxBytes)
+			return err
+		}); err != nil {
+			setCmdsErr(cmds, err)
+			return first, err
+		}
 
-	if err := cn.WithReader(c.context(ctx), c.opt.ReadTimeout, func(rd *proto.Reader) error {
-		return pipelineReadCmds(rd, cmds)
-	}); err != nil {
-		return true, err
+		batch, rest := cmds[:n], cmds[n:]
+		err := cn.WithReader(c.context(ctx), c.readTimeout(), func(rd *proto.Reader) error {
+			return pipelineReadCmds(ctx, c.pushes, rd, batch)
+		})
+		switch {
+		case err == nil:
+		case len(rest) == 0:
+			return first, err
+		case isRedisError(err) && !(first && shouldRetry(err, true)):
+			// the error of the first command of the batch, the next batches
+			// are processed
+		default:
+			setCmdsErr(rest, err)
+			return first, err
+		}
+		cmds = rest
 	}
 
 	return false, nil
 }
 
-func pipelineReadCmds(rd *proto.Reader, cmds []Cmder) error {
+func pipelineReadCmds(ctx context.Context, pushes *pushProcessor, rd *proto.Reader, cmds []Cmder) error {
 	for i, cmd := range cmds {
-		err := cmd.readReply(rd)
+		err := pushes.readReply(ctx, rd, cmd)
 		cmd.SetErr(err)
 		if err != nil && !isRedisError(err) {
 			setCmdsErr(cmds[i+1:], err)
@@ -559,24 +861,24 @@ func pipelineReadCmds(rd *proto.Reader, cmds []Cmder) error {
 func (c *baseClient) txPipelineProcessCmds(
 	ctx context.Context, cn *pool.Conn, cmds []Cmder,
 ) (bool, error) {
-	if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
+	if err := cn.WithWriter(c.context(ctx), c.writeTimeout(), func(wr *proto.Writer) error {
 		return writeCmds(wr, cmds)
 	}); err != nil {
 		setCmdsErr(cmds, err)
 		return true, err
 	}
 
-	if err := cn.WithReader(c.context(ctx), c.opt.ReadTimeout, func(rd *proto.Reader) error {
+	if err := cn.WithReader(c.context(ctx), c.readTimeout(), func(rd *proto.Reader) error {
 		statusCmd := cmds[0].(*StatusCmd)
 		// Trim multi and exec.
 		trimmedCmds := cmds[1 : len(cmds)-1]
 
-		if err := txPipelineReadQueued(rd, statusCmd, trimmedCmds); err != nil {
+		if err := txPipelineReadQueued(ctx, c.pushes, rd, statusCmd, trimmedCmds); err != nil {
 			setCmdsErr(cmds, err)
 			return err
 		}
 
-		return pipelineReadCmds(rd, trimmedCmds)
+		return pipelineReadCmds(ctx, c.pushes, rd, trimmedCmds)
 	}); err != nil {
 		return false, err
 	}
@@ -584,20 +886,25 @@ func (c *baseClient) txPipelineProcessCmds(
 	return false, nil
 }
 
-func txPipelineReadQueued(rd *proto.Reader, statusCmd *StatusCmd, cmds []Cmder) error {
+func txPipelineReadQueued(
+	ctx context.Context, pushes *pushProcessor, rd *proto.Reader, statusCmd *StatusCmd, cmds []Cmder,
+) error {
 	// Parse +OK.
-	if err := statusCmd.readReply(rd); err != nil {
+	if err := pushes.readReply(ctx, rd, statusCmd); err != nil {
 		return err
 	}
 
 	// Parse +QUEUED.
 	for range cmds {
-		if err := statusCmd.readReply(rd); err != nil && !isRedisError(err) {
+		if err := pushes.readReply(ctx, rd, statusCmd); err != nil && !isRedisError(err) {
 			return err
 		}
 	}
 
 	// Parse number of replies.
+	if err := pushes.process(ctx, rd); err != nil {
+		return err
+	}
 	line, err := rd.ReadLine()
 	if err != nil {
 		if err == Nil {
@@ -635,6 +942,11 @@ type Client struct {
 
 // NewClient returns a client to the Redis Server specified by Options.
 func NewClient(opt *Options) *Client {
+	if opt.StrictValidation {
+		if err := opt.Validate(); err != nil {
+			panic(err)
+		}
+	}
 	opt.init()
 
 	c := Client{
@@ -643,7 +955,44 @@ func NewClient(opt *Options) *Client {
 		},
 	}
 	c.init()
-	c.connPool = newConnPool(opt, c.dialHook)
+
+	if opt.StreamingCredentialsProvider != nil {
+		c.creds = newStreamingCredentials(opt.StreamingCredentialsProvider)
+	}
+	if opt.StatsEnabled {
+		c.stats = newCmdsStats()
+	}
+	if opt.AutoPipeline {
+		c.auto = newAutoPipeliner(c.baseClient)
+	}
+	c.hedgedCmds = newHedgedCmds(opt)
+	c.breaker = newCircuitBreaker(opt.CircuitBreaker, c.getAddr)
+
+	dialer := c.dialHook
+	if opt.pushNotifications() {
+		c.pushes = newPushProcessor()
+		c.pushes.onPush = opt.OnPush
+		if opt.MaintNotifications != nil {
+			c.maint = newMaintNotifications(opt.MaintNotifications)
+			c.maint.register(c.baseClient, c.pushes)
+			dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return c.dialHook(ctx, network, c.maint.addr(addr))
+			}
+		}
+	}
+	poolOpt := newPoolOptions(opt, dialer)
+	if opt.ClientCache != nil && c.pushes != nil {
+		c.cache = newClientCache(opt.ClientCache)
+		c.cache.register(c.pushes)
+		c.cache.watchConns(poolOpt)
+	}
+	c.connPool = pool.NewConnPool(poolOpt)
+	if opt.TLSFiles != nil {
+		c.watchTLSFiles()
+	}
+	if opt.ResolveInterval > 0 {
+		c.watchDNS()
+	}
 
 	return &c
 }
@@ -666,7 +1015,10 @@ func (c *Client) WithTimeout(timeout time.Duration) *Client {
 }
 
 func (c *Client) Conn() *Conn {
-	return newConn(c.opt, pool.NewStickyConnPool(c.connPool))
+	conn := newConn(c.opt, pool.NewStickyConnPool(c.connPool))
+	conn.pushes = c.pushes
+	conn.stats = c.stats
+	return conn
 }
 
 // Do create a Cmd from the args and processes the cmd.
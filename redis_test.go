@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	. "github.com/bsm/gomega"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
 )
 
 type redisHookError struct{}
@@ -50,6 +54,313 @@ func TestHookError(t *testing.T) {
 	}
 }
 
+func TestRemoveHook(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	var calls []string
+	newHook := func(name string) *hook {
+		return &hook{
+			processHook: func(next redis.ProcessHook) redis.ProcessHook {
+				return func(ctx context.Context, cmd redis.Cmder) error {
+					calls = append(calls, name)
+					return next(ctx, cmd)
+				}
+			},
+		}
+	}
+	h1, h2 := newHook("h1"), newHook("h2")
+	rdb.AddHook(h1)
+	rdb.AddHook(h2)
+	rdb.AddHook(redisHookError{})
+	rdb.RemoveHook(redisHookError{}) // an equal value
+
+	if hooks := rdb.Hooks(); len(hooks) != 2 || hooks[0] != h1 || hooks[1] != h2 {
+		t.Fatalf("got hooks %v", hooks)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rdb.RemoveHook(h1)
+	rdb.RemoveHook(newHook("h3")) // not added
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(calls, " "); got != "h1 h2 h2" {
+		t.Fatalf("got calls %q", got)
+	}
+	if hooks := rdb.Hooks(); len(hooks) != 1 || hooks[0] != h2 {
+		t.Fatalf("got hooks %v", hooks)
+	}
+}
+
+func TestAddHookConcurrently(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = rdb.Ping(ctx).Err()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				h := &hook{}
+				rdb.AddHook(h)
+				rdb.RemoveHook(h)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hooks := rdb.Hooks(); len(hooks) != 0 {
+		t.Fatalf("got %d hooks", len(hooks))
+	}
+}
+
+// renewedCredentials is a StreamingCredentialsProvider whose password is
+// renewed by renew.
+type renewedCredentials struct {
+	mu       sync.Mutex
+	password string
+	calls    int
+	subs     []func()
+}
+
+func (p *renewedCredentials) Credentials(ctx context.Context) (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	return "", p.password, nil
+}
+
+func (p *renewedCredentials) Subscribe(fn func()) func() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subs = append(p.subs, fn)
+	return func() {}
+}
+
+func (p *renewedCredentials) renew(password string) {
+	p.mu.Lock()
+	p.password = password
+	subs := p.subs
+	p.mu.Unlock()
+	for _, fn := range subs {
+		fn()
+	}
+}
+
+func TestGetInto(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), PoolSize: 1})
+	defer rdb.Close()
+
+	large := strings.Repeat("a", 100000) // larger than the read buffer
+	for _, value := range []string{"hello", large} {
+		if err := rdb.Set(ctx, "key", value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if err := rdb.GetInto(ctx, "key", func(b []byte) error {
+			got = string(b)
+			return nil
+		}).Err(); err != nil {
+			t.Fatal(err)
+		}
+		if got != value {
+			t.Fatalf("got %.20q, wanted %.20q", got, value)
+		}
+	}
+
+	err := rdb.GetInto(ctx, "missing", func(b []byte) error {
+		t.Fatalf("fn called with %q", b)
+		return nil
+	}).Err()
+	if err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+
+	// the error of fn is the error of the command, and the connection is reused
+	errDecode := errors.New("decode error")
+	if err := rdb.GetInto(ctx, "key", func(b []byte) error { return errDecode }).Err(); err != errDecode {
+		t.Fatalf("got %v, wanted %v", err, errDecode)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := rdb.PoolStats().TotalConns; n != 1 {
+		t.Fatalf("got %d connections", n)
+	}
+}
+
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		n := w.n
+		w.n = 0
+		return n, io.ErrShortWrite
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func TestGetTo(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), PoolSize: 1})
+	defer rdb.Close()
+
+	large := strings.Repeat("abcdefgh", 100000) // many read buffers
+	if err := rdb.Set(ctx, "key", large, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := rdb.GetTo(ctx, "key", &buf).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(large)) || buf.String() != large {
+		t.Fatalf("got %d bytes, wanted %d", n, len(large))
+	}
+
+	if err := rdb.GetTo(ctx, "missing", &buf).Err(); err != redis.Nil {
+		t.Fatalf("got %v, wanted redis.Nil", err)
+	}
+
+	// the error of the writer is the error of the command, and the connection
+	// is reused
+	n, err = rdb.GetTo(ctx, "key", &limitedWriter{n: 10000}).Result()
+	if err != io.ErrShortWrite || n != 10000 {
+		t.Fatalf("got %d, %v", n, err)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := rdb.PoolStats().TotalConns; n != 1 {
+		t.Fatalf("got %d connections", n)
+	}
+}
+
+func TestSetFrom(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), PoolSize: 1})
+	defer rdb.Close()
+
+	large := strings.Repeat("abcdefgh", 100000) // many write buffers
+	if err := rdb.SetFrom(ctx, "key", strings.NewReader(large), int64(len(large)), 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := rdb.Get(ctx, "key").Result(); err != nil || got != large {
+		t.Fatalf("got %d bytes, %v", len(got), err)
+	}
+
+	// the command of a short reader fails without breaking the protocol
+	err := rdb.SetFrom(ctx, "key", strings.NewReader("short"), 10, 0).Err()
+	if err == nil || err.Error() != "redis: reader ended after 5 of 10 bytes" {
+		t.Fatalf("got %v", err)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMGetBytes(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	if err := rdb.MSet(ctx, "k1", "v1", "k3", "value3").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.HSet(ctx, "hash", "f2", "v2").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := make([][]byte, 0, 2)
+	cmd := rdb.MGetBytes(ctx, dst, "k1", "k2", "k3")
+	vals, err := cmd.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vals) != 3 || string(vals[0]) != "v1" || len(vals[1]) != 0 || string(vals[2]) != "value3" {
+		t.Fatalf("got %q", vals)
+	}
+	if cmd.IsNil(0) || !cmd.IsNil(1) || cmd.IsNil(2) || cmd.IsNil(3) {
+		t.Fatalf("got nils %b", cmd.Nils())
+	}
+
+	// the buffers of the values are reused
+	buf := &vals[0][0]
+	cmd = rdb.HMGetBytes(ctx, vals, "hash", "f2", "f1")
+	if err := cmd.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cmd.Val(); len(got) != 2 || string(got[0]) != "v2" || &got[0][0] != buf {
+		t.Fatalf("got %q", got)
+	}
+	if cmd.IsNil(0) || !cmd.IsNil(1) {
+		t.Fatalf("got nils %b", cmd.Nils())
+	}
+}
+
+func TestStreamingCredentialsProvider(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	srv.SetPassword("first")
+
+	creds := &renewedCredentials{password: "first"}
+	rdb := redis.NewClient(&redis.Options{
+		Addr:                         srv.Addr(),
+		StreamingCredentialsProvider: creds,
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if creds.calls != 1 {
+		t.Fatalf("got %d calls of Credentials, wanted 1", creds.calls)
+	}
+
+	// the pooled connection is authenticated again with the new password
+	srv.SetPassword("second")
+	creds.renew("second")
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if creds.calls != 2 {
+		t.Fatalf("got %d calls of Credentials, wanted 2", creds.calls)
+	}
+	if n := rdb.PoolStats().TotalConns; n != 1 {
+		t.Fatalf("got %d conns, wanted 1", n)
+	}
+
+	creds.renew("wrong")
+	if err := rdb.Ping(ctx).Err(); err == nil || !strings.HasPrefix(err.Error(), "WRONGPASS") {
+		t.Fatalf("got %v, wanted WRONGPASS", err)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 var _ = Describe("Client", func() {
@@ -0,0 +1,796 @@
+package redistest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	errWrongType = "WRONGTYPE Operation against a key holding the wrong kind of value"
+	errSyntax    = "ERR syntax error"
+	errNotInt    = "ERR value is not an integer or out of range"
+	errNotFloat  = "ERR value is not a valid float"
+	errNoSuchKey = "ERR no such key"
+)
+
+type command struct {
+	fn func(c *conn, args []string)
+	// arity is the number of arguments including the name of the command,
+	// or minus its minimum.
+	arity int
+	// tx commands run immediately inside MULTI instead of being queued.
+	tx bool
+	// keyless commands have no key, e.g. to be tracked.
+	keyless bool
+}
+
+func (cmd command) arityOK(n int) bool {
+	if cmd.arity < 0 {
+		return n >= -cmd.arity
+	}
+	return n == cmd.arity
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		// connection and server
+		"ping":     {fn: cmdPing, arity: -1, keyless: true},
+		"echo":     {fn: cmdEcho, arity: 2, keyless: true},
+		"hello":    {fn: cmdHello, arity: -1, tx: true, keyless: true},
+		"auth":     {fn: cmdAuth, arity: -2, keyless: true},
+		"select":   {fn: cmdSelect, arity: 2, keyless: true},
+		"client":   {fn: cmdClient, arity: -2, keyless: true},
+		"command":  {fn: cmdCommand, arity: -1, keyless: true},
+		"info":     {fn: cmdInfo, arity: -1, keyless: true},
+		"time":     {fn: cmdTime, arity: 1, keyless: true},
+		"dbsize":   {fn: cmdDBSize, arity: 1, keyless: true},
+		"flushdb":  {fn: cmdFlushDB, arity: -1, keyless: true},
+		"flushall": {fn: cmdFlushAll, arity: -1, keyless: true},
+
+		// transactions
+		"multi":   {fn: cmdMulti, arity: 1, tx: true},
+		"exec":    {fn: cmdExec, arity: 1, tx: true},
+		"discard": {fn: cmdDiscard, arity: 1, tx: true},
+		"watch":   {fn: cmdWatch, arity: -2, tx: true},
+		"unwatch": {fn: cmdUnwatch, arity: 1, tx: true},
+
+		// keys
+		"del":       {fn: cmdDel, arity: -2},
+		"unlink":    {fn: cmdDel, arity: -2},
+		"exists":    {fn: cmdExists, arity: -2},
+		"expire":    {fn: cmdExpire(time.Second, false), arity: -3},
+		"pexpire":   {fn: cmdExpire(time.Millisecond, false), arity: -3},
+		"expireat":  {fn: cmdExpire(time.Second, true), arity: -3},
+		"pexpireat": {fn: cmdExpire(time.Millisecond, true), arity: -3},
+		"ttl":       {fn: cmdTTL(time.Second), arity: 2},
+		"pttl":      {fn: cmdTTL(time.Millisecond), arity: 2},
+		"persist":   {fn: cmdPersist, arity: 2},
+		"type":      {fn: cmdType, arity: 2},
+		"keys":      {fn: cmdKeys, arity: 2, keyless: true},
+		"scan":      {fn: cmdScan, arity: -2, keyless: true},
+		"rename":    {fn: cmdRename(false), arity: 3},
+		"renamenx":  {fn: cmdRename(true), arity: 3},
+
+		// strings
+		"get":         {fn: cmdGet, arity: 2},
+		"set":         {fn: cmdSet, arity: -3},
+		"setnx":       {fn: cmdSetNX, arity: 3},
+		"setex":       {fn: cmdSetEX(time.Second), arity: 4},
+		"psetex":      {fn: cmdSetEX(time.Millisecond), arity: 4},
+		"getset":      {fn: cmdGetSet, arity: 3},
+		"getdel":      {fn: cmdGetDel, arity: 2},
+		"getex":       {fn: cmdGetEX, arity: -2},
+		"mget":        {fn: cmdMGet, arity: -2},
+		"mset":        {fn: cmdMSet(false), arity: -3},
+		"msetnx":      {fn: cmdMSet(true), arity: -3},
+		"incr":        {fn: cmdIncrBy(1), arity: 2},
+		"decr":        {fn: cmdIncrBy(-1), arity: 2},
+		"incrby":      {fn: cmdIncrBy(1), arity: 3},
+		"decrby":      {fn: cmdIncrBy(-1), arity: 3},
+		"incrbyfloat": {fn: cmdIncrByFloat, arity: 3},
+		"append":      {fn: cmdAppend, arity: 3},
+		"strlen":      {fn: cmdStrLen, arity: 2},
+		"getrange":    {fn: cmdGetRange, arity: 4},
+
+		// hashes
+		"hset":         {fn: cmdHSet, arity: -4},
+		"hmset":        {fn: cmdHSet, arity: -4},
+		"hsetnx":       {fn: cmdHSetNX, arity: 4},
+		"hget":         {fn: cmdHGet, arity: 3},
+		"hmget":        {fn: cmdHMGet, arity: -3},
+		"hgetall":      {fn: cmdHGetAll, arity: 2},
+		"hdel":         {fn: cmdHDel, arity: -3},
+		"hexists":      {fn: cmdHExists, arity: 3},
+		"hlen":         {fn: cmdHLen, arity: 2},
+		"hkeys":        {fn: cmdHKeys, arity: 2},
+		"hvals":        {fn: cmdHVals, arity: 2},
+		"hstrlen":      {fn: cmdHStrLen, arity: 3},
+		"hincrby":      {fn: cmdHIncrBy, arity: 4},
+		"hincrbyfloat": {fn: cmdHIncrByFloat, arity: 4},
+
+		// lists
+		"lpush":     {fn: cmdPush(true, false), arity: -3},
+		"rpush":     {fn: cmdPush(false, false), arity: -3},
+		"lpushx":    {fn: cmdPush(true, true), arity: -3},
+		"rpushx":    {fn: cmdPush(false, true), arity: -3},
+		"lpop":      {fn: cmdPop(true), arity: -2},
+		"rpop":      {fn: cmdPop(false), arity: -2},
+		"llen":      {fn: cmdLLen, arity: 2},
+		"lrange":    {fn: cmdLRange, arity: 4},
+		"lindex":    {fn: cmdLIndex, arity: 3},
+		"lset":      {fn: cmdLSet, arity: 4},
+		"lrem":      {fn: cmdLRem, arity: 4},
+		"ltrim":     {fn: cmdLTrim, arity: 4},
+		"lmove":     {fn: cmdLMove, arity: 5},
+		"rpoplpush": {fn: cmdRPopLPush, arity: 3},
+
+		// sets
+		"sadd":        {fn: cmdSAdd, arity: -3},
+		"srem":        {fn: cmdSRem, arity: -3},
+		"smembers":    {fn: cmdSMembers, arity: 2},
+		"sismember":   {fn: cmdSIsMember, arity: 3},
+		"smismember":  {fn: cmdSMIsMember, arity: -3},
+		"scard":       {fn: cmdSCard, arity: 2},
+		"spop":        {fn: cmdSPop, arity: -2},
+		"srandmember": {fn: cmdSRandMember, arity: -2},
+		"sinter":      {fn: cmdSetOp(setInter), arity: -2},
+		"sunion":      {fn: cmdSetOp(setUnion), arity: -2},
+		"sdiff":       {fn: cmdSetOp(setDiff), arity: -2},
+
+		// sorted sets
+		"zadd":             {fn: cmdZAdd, arity: -4},
+		"zincrby":          {fn: cmdZIncrBy, arity: 4},
+		"zrem":             {fn: cmdZRem, arity: -3},
+		"zscore":           {fn: cmdZScore, arity: 3},
+		"zmscore":          {fn: cmdZMScore, arity: -3},
+		"zcard":            {fn: cmdZCard, arity: 2},
+		"zcount":           {fn: cmdZCount, arity: 4},
+		"zrank":            {fn: cmdZRank(false), arity: 3},
+		"zrevrank":         {fn: cmdZRank(true), arity: 3},
+		"zrange":           {fn: cmdZRange, arity: -4},
+		"zrevrange":        {fn: cmdZRangeCompat(false, true), arity: -4},
+		"zrangebyscore":    {fn: cmdZRangeCompat(true, false), arity: -4},
+		"zrevrangebyscore": {fn: cmdZRangeCompat(true, true), arity: -4},
+		"zremrangebyscore": {fn: cmdZRemRangeByScore, arity: 4},
+		"zremrangebyrank":  {fn: cmdZRemRangeByRank, arity: 4},
+		"zpopmin":          {fn: cmdZPop(false), arity: -2},
+		"zpopmax":          {fn: cmdZPop(true), arity: -2},
+	}
+}
+
+func (c *conn) db() *db {
+	return c.srv.db(c.dbIndex)
+}
+
+// lookup returns the entry of key, or nil when it does not exist. It replies
+// with an error and returns false when the key holds another type than kind.
+func (c *conn) lookup(key, kind string) (*entry, bool) {
+	e := c.db().get(c.srv, key)
+	if e == nil {
+		return nil, true
+	}
+	if typeName(e.value) != kind {
+		c.w.error(errWrongType)
+		return nil, false
+	}
+	return e, true
+}
+
+// lookupOrCreate is like lookup but creates the key with newValue when it
+// does not exist.
+func (c *conn) lookupOrCreate(key, kind string, newValue func() interface{}) (*entry, bool) {
+	e, ok := c.lookup(key, kind)
+	if !ok {
+		return nil, false
+	}
+	if e == nil {
+		e = &entry{value: newValue()}
+		c.db().keys[key] = e
+	}
+	return e, true
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case hash:
+		return "hash"
+	case list:
+		return "list"
+	case set:
+		return "set"
+	case *zset:
+		return "zset"
+	}
+	return "none"
+}
+
+func parseInt(s string) (int64, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+func parseFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+//------------------------------------------------------------------------------
+
+func cmdPing(c *conn, args []string) {
+	switch len(args) {
+	case 1:
+		c.w.simple("PONG")
+	case 2:
+		c.w.bulk(args[1])
+	default:
+		c.w.error("ERR wrong number of arguments for 'ping' command")
+	}
+}
+
+func cmdEcho(c *conn, args []string) {
+	c.w.bulk(args[1])
+}
+
+func cmdHello(c *conn, args []string) {
+	proto := c.w.proto
+	if len(args) > 1 {
+		n, ok := parseInt(args[1])
+		if !ok {
+			c.w.error("ERR Protocol version is not an integer or out of range")
+			return
+		}
+		if n != 2 && n != 3 {
+			c.w.error("NOPROTO unsupported protocol version")
+			return
+		}
+		proto = int(n)
+	}
+
+	var name string
+	var hasName bool
+	for i := 2; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "auth":
+			if i+2 >= len(args) {
+				c.w.error(errSyntax)
+				return
+			}
+			if !c.auth(args[i+1], args[i+2]) {
+				return
+			}
+			i += 2
+		case "setname":
+			if i+1 >= len(args) {
+				c.w.error(errSyntax)
+				return
+			}
+			name, hasName = args[i+1], true
+			i++
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+	if c.srv.password != "" && !c.authed {
+		c.w.error("NOAUTH HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+		return
+	}
+	if hasName {
+		c.name = name
+	}
+
+	c.w.proto = proto
+	c.w.mapLen(7)
+	c.w.bulk("server")
+	c.w.bulk("redis")
+	c.w.bulk("version")
+	c.w.bulk(version)
+	c.w.bulk("proto")
+	c.w.int(int64(proto))
+	c.w.bulk("id")
+	c.w.int(c.id)
+	c.w.bulk("mode")
+	c.w.bulk("standalone")
+	c.w.bulk("role")
+	c.w.bulk("master")
+	c.w.bulk("modules")
+	c.w.array(0)
+}
+
+// version is the version of Redis reported by the server.
+const version = "7.4.0"
+
+// auth authenticates the connection, replying with an error when it fails.
+func (c *conn) auth(username, password string) bool {
+	if c.srv.password == "" {
+		c.w.error("ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+		return false
+	}
+	if username != "default" || password != c.srv.password {
+		c.w.error("WRONGPASS invalid username-password pair or user is disabled.")
+		return false
+	}
+	c.authed = true
+	return true
+}
+
+func cmdAuth(c *conn, args []string) {
+	var ok bool
+	switch len(args) {
+	case 2:
+		ok = c.auth("default", args[1])
+	case 3:
+		ok = c.auth(args[1], args[2])
+	default:
+		c.w.error(errSyntax)
+		return
+	}
+	if ok {
+		c.w.ok()
+	}
+}
+
+func cmdSelect(c *conn, args []string) {
+	n, ok := parseInt(args[1])
+	if !ok {
+		c.w.error(errNotInt)
+		return
+	}
+	if n < 0 || n >= 16 {
+		c.w.error("ERR DB index is out of range")
+		return
+	}
+	c.dbIndex = int(n)
+	c.w.ok()
+}
+
+func cmdClient(c *conn, args []string) {
+	switch strings.ToLower(args[1]) {
+	case "setname":
+		if len(args) != 3 {
+			c.w.error(errSyntax)
+			return
+		}
+		c.name = args[2]
+		c.w.ok()
+	case "getname":
+		if c.name == "" {
+			c.w.null()
+			return
+		}
+		c.w.bulk(c.name)
+	case "id":
+		c.w.int(c.id)
+	case "setinfo":
+		if len(args) != 4 {
+			c.w.error(errSyntax)
+			return
+		}
+		c.w.ok()
+	case "info":
+		c.w.bulk(fmt.Sprintf("id=%d addr=%s name=%s db=%d resp=%d\n",
+			c.id, c.netConn.RemoteAddr(), c.name, c.dbIndex, c.w.proto))
+	case "tracking":
+		if len(args) < 3 {
+			c.w.error(errSyntax)
+			return
+		}
+		switch strings.ToLower(args[2]) {
+		case "on":
+			bcast, prefixes, ok := parseTracking(args[3:])
+			if !ok {
+				c.w.error(errSyntax)
+				return
+			}
+			if c.tracking && bcast != c.bcast {
+				c.w.error("ERR You can't switch BCAST mode on/off before disabling tracking for this client, and then re-enabling it with a different mode.")
+				return
+			}
+			c.tracking = true
+			c.bcast = bcast
+			c.prefixes = append(c.prefixes, prefixes...)
+		case "off":
+			if len(args) != 3 {
+				c.w.error(errSyntax)
+				return
+			}
+			c.tracking = false
+			c.bcast = false
+			c.prefixes = nil
+			c.srv.untrack(c)
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+		c.w.ok()
+	case "reply":
+		if len(args) != 3 {
+			c.w.error(errSyntax)
+			return
+		}
+		switch strings.ToLower(args[2]) {
+		case "on":
+			c.replyOff = false
+			c.w.off = false
+			c.w.ok()
+		case "off":
+			c.replyOff = true
+		case "skip":
+			c.replySkip = true
+		default:
+			c.w.error(errSyntax)
+		}
+	default:
+		c.w.error(fmt.Sprintf("ERR unknown subcommand '%s'. Try CLIENT HELP.", args[1]))
+	}
+}
+
+// parseTracking parses the BCAST and PREFIX options of CLIENT TRACKING ON.
+func parseTracking(args []string) (bcast bool, prefixes []string, ok bool) {
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "bcast":
+			bcast = true
+		case "prefix":
+			if i+1 == len(args) {
+				return false, nil, false
+			}
+			i++
+			prefixes = append(prefixes, args[i])
+		default:
+			return false, nil, false
+		}
+	}
+	if len(prefixes) > 0 && !bcast {
+		return false, nil, false
+	}
+	return bcast, prefixes, true
+}
+
+func cmdCommand(c *conn, args []string) {
+	c.w.array(0)
+}
+
+func cmdInfo(c *conn, args []string) {
+	var b strings.Builder
+	b.WriteString("# Server\r\n")
+	b.WriteString("redis_version:" + version + "\r\n")
+	b.WriteString("redis_mode:standalone\r\n")
+	b.WriteString("\r\n# Keyspace\r\n")
+	for i := 0; i < 16; i++ {
+		d, ok := c.srv.dbs[i]
+		if !ok {
+			continue
+		}
+		keys := d.sortedKeys(c.srv)
+		if len(keys) == 0 {
+			continue
+		}
+		var expires int
+		for _, key := range keys {
+			if !d.keys[key].expireAt.IsZero() {
+				expires++
+			}
+		}
+		fmt.Fprintf(&b, "db%d:keys=%d,expires=%d,avg_ttl=0\r\n", i, len(keys), expires)
+	}
+	c.w.bulk(b.String())
+}
+
+func cmdTime(c *conn, args []string) {
+	now := c.srv.now()
+	c.w.array(2)
+	c.w.bulk(strconv.FormatInt(now.Unix(), 10))
+	c.w.bulk(strconv.Itoa(now.Nanosecond() / 1000))
+}
+
+func cmdDBSize(c *conn, args []string) {
+	c.w.int(int64(len(c.db().sortedKeys(c.srv))))
+}
+
+func cmdFlushDB(c *conn, args []string) {
+	c.srv.flush(c.db())
+	c.w.ok()
+}
+
+func cmdFlushAll(c *conn, args []string) {
+	for _, d := range c.srv.dbs {
+		c.srv.flush(d)
+	}
+	c.w.ok()
+}
+
+//------------------------------------------------------------------------------
+
+func cmdMulti(c *conn, args []string) {
+	if c.inMulti {
+		c.w.error("ERR MULTI calls can not be nested")
+		return
+	}
+	c.inMulti, c.multiOK, c.queued = true, true, nil
+	c.w.ok()
+}
+
+func cmdExec(c *conn, args []string) {
+	if !c.inMulti {
+		c.w.error("ERR EXEC without MULTI")
+		return
+	}
+	queued, ok := c.queued, c.multiOK
+	c.inMulti, c.queued = false, nil
+	defer c.unwatch()
+
+	if !ok {
+		c.w.error("EXECABORT Transaction discarded because of previous errors.")
+		return
+	}
+	for wk, v := range c.watched {
+		d := c.srv.db(wk.db)
+		d.get(c.srv, wk.key) // expires the key
+		if d.versions[wk.key] != v {
+			c.w.nullArray()
+			return
+		}
+	}
+
+	c.w.array(len(queued))
+	for _, args := range queued {
+		commands[strings.ToLower(args[0])].fn(c, args)
+	}
+}
+
+func cmdDiscard(c *conn, args []string) {
+	if !c.inMulti {
+		c.w.error("ERR DISCARD without MULTI")
+		return
+	}
+	c.inMulti, c.queued = false, nil
+	c.unwatch()
+	c.w.ok()
+}
+
+func cmdWatch(c *conn, args []string) {
+	if c.inMulti {
+		c.w.error("ERR WATCH inside MULTI is not allowed")
+		return
+	}
+	if c.watched == nil {
+		c.watched = make(map[watchKey]uint64)
+	}
+	d := c.db()
+	for _, key := range args[1:] {
+		d.get(c.srv, key) // expires the key
+		c.watched[watchKey{db: c.dbIndex, key: key}] = d.versions[key]
+	}
+	c.w.ok()
+}
+
+func cmdUnwatch(c *conn, args []string) {
+	c.unwatch()
+	c.w.ok()
+}
+
+func (c *conn) unwatch() {
+	c.watched = nil
+}
+
+//------------------------------------------------------------------------------
+
+func cmdDel(c *conn, args []string) {
+	var n int64
+	d := c.db()
+	for _, key := range args[1:] {
+		if d.get(c.srv, key) != nil && d.del(c.srv, key) {
+			n++
+		}
+	}
+	c.w.int(n)
+}
+
+func cmdExists(c *conn, args []string) {
+	var n int64
+	d := c.db()
+	for _, key := range args[1:] {
+		if d.get(c.srv, key) != nil {
+			n++
+		}
+	}
+	c.w.int(n)
+}
+
+func cmdExpire(unit time.Duration, at bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		n, ok := parseInt(args[2])
+		if !ok {
+			c.w.error(errNotInt)
+			return
+		}
+		var nx, xx, gt, lt bool
+		for _, arg := range args[3:] {
+			switch strings.ToLower(arg) {
+			case "nx":
+				nx = true
+			case "xx":
+				xx = true
+			case "gt":
+				gt = true
+			case "lt":
+				lt = true
+			default:
+				c.w.error("ERR Unsupported option " + arg)
+				return
+			}
+		}
+		if nx && (xx || gt || lt) || gt && lt {
+			c.w.error("ERR NX and XX, GT or LT options at the same time are not compatible")
+			return
+		}
+
+		d := c.db()
+		e := d.get(c.srv, args[1])
+		if e == nil {
+			c.w.int(0)
+			return
+		}
+
+		var expireAt time.Time
+		if at {
+			expireAt = time.Unix(0, 0).Add(time.Duration(n) * unit)
+		} else {
+			expireAt = c.srv.now().Add(time.Duration(n) * unit)
+		}
+		switch {
+		case nx && !e.expireAt.IsZero(),
+			xx && e.expireAt.IsZero(),
+			gt && (e.expireAt.IsZero() || !expireAt.After(e.expireAt)),
+			lt && !e.expireAt.IsZero() && !expireAt.Before(e.expireAt):
+			c.w.int(0)
+			return
+		}
+
+		if !expireAt.After(c.srv.now()) {
+			d.del(c.srv, args[1])
+		} else {
+			e.expireAt = expireAt
+			d.touch(c.srv, args[1])
+		}
+		c.w.int(1)
+	}
+}
+
+func cmdTTL(unit time.Duration) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		e := c.db().get(c.srv, args[1])
+		switch {
+		case e == nil:
+			c.w.int(-2)
+		case e.expireAt.IsZero():
+			c.w.int(-1)
+		default:
+			ttl := e.expireAt.Sub(c.srv.now())
+			c.w.int(int64((ttl + unit/2) / unit))
+		}
+	}
+}
+
+func cmdPersist(c *conn, args []string) {
+	d := c.db()
+	e := d.get(c.srv, args[1])
+	if e == nil || e.expireAt.IsZero() {
+		c.w.int(0)
+		return
+	}
+	e.expireAt = time.Time{}
+	d.touch(c.srv, args[1])
+	c.w.int(1)
+}
+
+func cmdType(c *conn, args []string) {
+	e := c.db().get(c.srv, args[1])
+	if e == nil {
+		c.w.simple("none")
+		return
+	}
+	c.w.simple(typeName(e.value))
+}
+
+func cmdKeys(c *conn, args []string) {
+	var keys []string
+	for _, key := range c.db().sortedKeys(c.srv) {
+		if match(args[1], key) {
+			keys = append(keys, key)
+		}
+	}
+	c.w.bulks(keys)
+}
+
+// cmdScan iterates the sorted keys, its cursor being the index of the next key.
+func cmdScan(c *conn, args []string) {
+	cursor, ok := parseInt(args[1])
+	if !ok || cursor < 0 {
+		c.w.error("ERR invalid cursor")
+		return
+	}
+	pattern, count, kind := "*", int64(10), ""
+	for i := 2; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			c.w.error(errSyntax)
+			return
+		}
+		switch strings.ToLower(args[i]) {
+		case "match":
+			pattern = args[i+1]
+		case "count":
+			if count, ok = parseInt(args[i+1]); !ok {
+				c.w.error(errNotInt)
+				return
+			}
+			if count < 1 {
+				c.w.error(errSyntax)
+				return
+			}
+		case "type":
+			kind = strings.ToLower(args[i+1])
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+
+	d := c.db()
+	all := d.sortedKeys(c.srv)
+	var keys []string
+	i := cursor
+	for ; i < int64(len(all)) && i < cursor+count; i++ {
+		key := all[i]
+		if !match(pattern, key) {
+			continue
+		}
+		if kind != "" && typeName(d.keys[key].value) != kind {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if i >= int64(len(all)) {
+		i = 0
+	}
+
+	c.w.array(2)
+	c.w.bulk(strconv.FormatInt(i, 10))
+	c.w.bulks(keys)
+}
+
+func cmdRename(nx bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		d := c.db()
+		e := d.get(c.srv, args[1])
+		if e == nil {
+			c.w.error(errNoSuchKey)
+			return
+		}
+		if nx && d.get(c.srv, args[2]) != nil {
+			c.w.int(0)
+			return
+		}
+		if args[1] != args[2] {
+			d.del(c.srv, args[1])
+			d.keys[args[2]] = e
+			d.touch(c.srv, args[2])
+		}
+		if nx {
+			c.w.int(1)
+		} else {
+			c.w.ok()
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package redistest
+
+import (
+	"sort"
+	"time"
+)
+
+// entry is a key of a database. Its value is a string, hash, list, set
+// or *zset.
+type entry struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+type (
+	hash map[string]string
+	list []string
+	set  map[string]struct{}
+)
+
+type zset struct {
+	scores map[string]float64
+}
+
+type zmember struct {
+	member string
+	score  float64
+}
+
+// sorted returns the members sorted by score, then lexicographically.
+func (z *zset) sorted() []zmember {
+	members := make([]zmember, 0, len(z.scores))
+	for m, score := range z.scores {
+		members = append(members, zmember{member: m, score: score})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].score != members[j].score {
+			return members[i].score < members[j].score
+		}
+		return members[i].member < members[j].member
+	})
+	return members
+}
+
+type db struct {
+	keys map[string]*entry
+	// versions are the versions of the modified keys, checked by WATCH.
+	versions map[string]uint64
+}
+
+func newDB() *db {
+	return &db{
+		keys:     make(map[string]*entry),
+		versions: make(map[string]uint64),
+	}
+}
+
+// get returns the entry of key, or nil when it does not exist or expired.
+func (d *db) get(s *Server, key string) *entry {
+	e, ok := d.keys[key]
+	if !ok {
+		return nil
+	}
+	if !e.expireAt.IsZero() && !s.now().Before(e.expireAt) {
+		d.del(s, key)
+		return nil
+	}
+	return e
+}
+
+// set replaces the value of key, removing its expiration.
+func (d *db) set(s *Server, key string, value interface{}) *entry {
+	e := &entry{value: value}
+	d.keys[key] = e
+	d.touch(s, key)
+	return e
+}
+
+func (d *db) del(s *Server, key string) bool {
+	if _, ok := d.keys[key]; !ok {
+		return false
+	}
+	delete(d.keys, key)
+	d.touch(s, key)
+	return true
+}
+
+// touch marks key as modified, failing the transactions watching it.
+func (d *db) touch(s *Server, key string) {
+	s.version++
+	d.versions[key] = s.version
+	s.invalidate(key)
+}
+
+// removeIfEmpty deletes key when its collection is empty, as Redis does.
+func (d *db) removeIfEmpty(s *Server, key string) {
+	e, ok := d.keys[key]
+	if !ok {
+		return
+	}
+	var n int
+	switch v := e.value.(type) {
+	case hash:
+		n = len(v)
+	case list:
+		n = len(v)
+	case set:
+		n = len(v)
+	case *zset:
+		n = len(v.scores)
+	default:
+		return
+	}
+	if n == 0 {
+		d.del(s, key)
+	}
+}
+
+func (d *db) sortedKeys(s *Server) []string {
+	keys := make([]string, 0, len(d.keys))
+	for key := range d.keys {
+		if d.get(s, key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) flush(d *db) {
+	for key := range d.keys {
+		d.del(s, key)
+	}
+}
+
+// match reports whether key matches the glob-style pattern of KEYS and SCAN.
+func match(pattern, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if match(pattern[1:], key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(key) == 0 {
+				return false
+			}
+			key = key[1:]
+			pattern = pattern[1:]
+		case '[':
+			if len(key) == 0 {
+				return false
+			}
+			end := 1
+			for end < len(pattern) && pattern[end] != ']' {
+				if pattern[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(pattern) {
+				// unterminated class, match '[' literally
+				if key[0] != '[' {
+					return false
+				}
+				key = key[1:]
+				pattern = pattern[1:]
+				continue
+			}
+			if !matchClass(pattern[1:end], key[0]) {
+				return false
+			}
+			key = key[1:]
+			pattern = pattern[end+1:]
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(key) == 0 || key[0] != pattern[0] {
+				return false
+			}
+			key = key[1:]
+			pattern = pattern[1:]
+		}
+	}
+	return len(key) == 0
+}
+
+func matchClass(class string, c byte) bool {
+	negate := len(class) > 0 && class[0] == '^'
+	if negate {
+		class = class[1:]
+	}
+	matched := false
+	for i := 0; i < len(class); i++ {
+		switch {
+		case class[i] == '\\' && i+1 < len(class):
+			i++
+			matched = matched || class[i] == c
+		case i+2 < len(class) && class[i+1] == '-':
+			lo, hi := class[i], class[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			matched = matched || (c >= lo && c <= hi)
+			i += 2
+		default:
+			matched = matched || class[i] == c
+		}
+	}
+	return matched != negate
+}
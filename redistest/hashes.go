@@ -0,0 +1,230 @@
+package redistest
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func newHash() interface{} { return hash{} }
+
+func (c *conn) getHash(key string) (hash, bool) {
+	e, ok := c.lookup(key, "hash")
+	if !ok || e == nil {
+		return nil, ok
+	}
+	return e.value.(hash), true
+}
+
+func (h hash) sortedFields() []string {
+	fields := make([]string, 0, len(h))
+	for field := range h {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// cmdHSet implements HSET and HMSET, which only differ by their replies.
+func cmdHSet(c *conn, args []string) {
+	if len(args)%2 != 0 {
+		c.w.error("ERR wrong number of arguments for '" + strings.ToLower(args[0]) + "' command")
+		return
+	}
+	e, ok := c.lookupOrCreate(args[1], "hash", newHash)
+	if !ok {
+		return
+	}
+	h := e.value.(hash)
+	var n int64
+	for i := 2; i < len(args); i += 2 {
+		if _, ok := h[args[i]]; !ok {
+			n++
+		}
+		h[args[i]] = args[i+1]
+	}
+	c.db().touch(c.srv, args[1])
+	if strings.EqualFold(args[0], "hmset") {
+		c.w.ok()
+		return
+	}
+	c.w.int(n)
+}
+
+func cmdHSetNX(c *conn, args []string) {
+	e, ok := c.lookupOrCreate(args[1], "hash", newHash)
+	if !ok {
+		return
+	}
+	h := e.value.(hash)
+	if _, ok := h[args[2]]; ok {
+		c.w.int(0)
+		return
+	}
+	h[args[2]] = args[3]
+	c.db().touch(c.srv, args[1])
+	c.w.int(1)
+}
+
+func cmdHGet(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	v, ok := h[args[2]]
+	if !ok {
+		c.w.null()
+		return
+	}
+	c.w.bulk(v)
+}
+
+func cmdHMGet(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	c.w.array(len(args) - 2)
+	for _, field := range args[2:] {
+		if v, ok := h[field]; ok {
+			c.w.bulk(v)
+		} else {
+			c.w.null()
+		}
+	}
+}
+
+func cmdHGetAll(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	c.w.mapLen(len(h))
+	for _, field := range h.sortedFields() {
+		c.w.bulk(field)
+		c.w.bulk(h[field])
+	}
+}
+
+func cmdHDel(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	for _, field := range args[2:] {
+		if _, ok := h[field]; ok {
+			delete(h, field)
+			n++
+		}
+	}
+	if n > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+	c.w.int(n)
+}
+
+func cmdHExists(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	_, exists := h[args[2]]
+	c.w.bool(exists)
+}
+
+func cmdHLen(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	c.w.int(int64(len(h)))
+}
+
+func cmdHKeys(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	c.w.bulks(h.sortedFields())
+}
+
+func cmdHVals(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	fields := h.sortedFields()
+	c.w.array(len(fields))
+	for _, field := range fields {
+		c.w.bulk(h[field])
+	}
+}
+
+func cmdHStrLen(c *conn, args []string) {
+	h, ok := c.getHash(args[1])
+	if !ok {
+		return
+	}
+	c.w.int(int64(len(h[args[2]])))
+}
+
+func cmdHIncrBy(c *conn, args []string) {
+	delta, ok := parseInt(args[3])
+	if !ok {
+		c.w.error(errNotInt)
+		return
+	}
+	e, ok := c.lookupOrCreate(args[1], "hash", newHash)
+	if !ok {
+		return
+	}
+	h := e.value.(hash)
+	var n int64
+	if v, exists := h[args[2]]; exists {
+		if n, ok = parseInt(v); !ok {
+			c.w.error("ERR hash value is not an integer")
+			return
+		}
+	}
+	if delta > 0 && n > math.MaxInt64-delta || delta < 0 && n < math.MinInt64-delta {
+		c.w.error("ERR increment or decrement would overflow")
+		return
+	}
+	n += delta
+	h[args[2]] = strconv.FormatInt(n, 10)
+	c.db().touch(c.srv, args[1])
+	c.w.int(n)
+}
+
+func cmdHIncrByFloat(c *conn, args []string) {
+	delta, ok := parseFloat(args[3])
+	if !ok {
+		c.w.error(errNotFloat)
+		return
+	}
+	e, ok := c.lookupOrCreate(args[1], "hash", newHash)
+	if !ok {
+		return
+	}
+	h := e.value.(hash)
+	var f float64
+	if v, exists := h[args[2]]; exists {
+		if f, ok = parseFloat(v); !ok {
+			c.w.error("ERR hash value is not a float")
+			return
+		}
+	}
+	f += delta
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		c.w.error("ERR increment would produce NaN or Infinity")
+		return
+	}
+	s := formatFloat(f)
+	h[args[2]] = s
+	c.db().touch(c.srv, args[1])
+	c.w.bulk(s)
+}
@@ -0,0 +1,298 @@
+package redistest
+
+import "strings"
+
+func newList() interface{} { return list(nil) }
+
+func (c *conn) getList(key string) (*entry, list, bool) {
+	e, ok := c.lookup(key, "list")
+	if !ok || e == nil {
+		return nil, nil, ok
+	}
+	return e, e.value.(list), true
+}
+
+func cmdPush(left, exists bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		var e *entry
+		var ok bool
+		if exists {
+			if e, _, ok = c.getList(args[1]); !ok {
+				return
+			}
+			if e == nil {
+				c.w.int(0)
+				return
+			}
+		} else if e, ok = c.lookupOrCreate(args[1], "list", newList); !ok {
+			return
+		}
+
+		l := e.value.(list)
+		for _, v := range args[2:] {
+			if left {
+				l = append(list{v}, l...)
+			} else {
+				l = append(l, v)
+			}
+		}
+		e.value = l
+		c.db().touch(c.srv, args[1])
+		c.w.int(int64(len(l)))
+	}
+}
+
+func cmdPop(left bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		count, withCount := int64(1), len(args) > 2
+		if withCount {
+			if len(args) > 3 {
+				c.w.error(errSyntax)
+				return
+			}
+			n, ok := parseInt(args[2])
+			if !ok || n < 0 {
+				c.w.error("ERR value is out of range, must be positive")
+				return
+			}
+			count = n
+		}
+
+		e, l, ok := c.getList(args[1])
+		if !ok {
+			return
+		}
+		if e == nil {
+			if withCount {
+				c.w.nullArray()
+			} else {
+				c.w.null()
+			}
+			return
+		}
+
+		if count > int64(len(l)) {
+			count = int64(len(l))
+		}
+		var popped []string
+		if left {
+			popped = append(popped, l[:count]...)
+			l = l[count:]
+		} else {
+			for i := int64(0); i < count; i++ {
+				popped = append(popped, l[len(l)-1])
+				l = l[:len(l)-1]
+			}
+		}
+		c.setList(args[1], e, l)
+
+		if withCount {
+			c.w.bulks(popped)
+		} else {
+			c.w.bulk(popped[0])
+		}
+	}
+}
+
+// setList replaces the elements of the list key, deleting it when it is empty.
+func (c *conn) setList(key string, e *entry, l list) {
+	e.value = l
+	d := c.db()
+	d.touch(c.srv, key)
+	d.removeIfEmpty(c.srv, key)
+}
+
+func cmdLLen(c *conn, args []string) {
+	_, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	c.w.int(int64(len(l)))
+}
+
+func cmdLRange(c *conn, args []string) {
+	start, ok1 := parseInt(args[2])
+	stop, ok2 := parseInt(args[3])
+	if !ok1 || !ok2 {
+		c.w.error(errNotInt)
+		return
+	}
+	_, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	lo, hi, ok := rangeIndexes(start, stop, len(l))
+	if !ok {
+		c.w.array(0)
+		return
+	}
+	c.w.bulks(l[lo:hi])
+}
+
+func cmdLIndex(c *conn, args []string) {
+	i, ok := parseInt(args[2])
+	if !ok {
+		c.w.error(errNotInt)
+		return
+	}
+	_, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	if i < 0 {
+		i += int64(len(l))
+	}
+	if i < 0 || i >= int64(len(l)) {
+		c.w.null()
+		return
+	}
+	c.w.bulk(l[i])
+}
+
+func cmdLSet(c *conn, args []string) {
+	i, ok := parseInt(args[2])
+	if !ok {
+		c.w.error(errNotInt)
+		return
+	}
+	e, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	if e == nil {
+		c.w.error(errNoSuchKey)
+		return
+	}
+	if i < 0 {
+		i += int64(len(l))
+	}
+	if i < 0 || i >= int64(len(l)) {
+		c.w.error("ERR index out of range")
+		return
+	}
+	l[i] = args[3]
+	c.db().touch(c.srv, args[1])
+	c.w.ok()
+}
+
+func cmdLRem(c *conn, args []string) {
+	count, ok := parseInt(args[2])
+	if !ok {
+		c.w.error(errNotInt)
+		return
+	}
+	e, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	if e == nil {
+		c.w.int(0)
+		return
+	}
+
+	removed := make([]bool, len(l))
+	var n int64
+	if count >= 0 {
+		for i := 0; i < len(l) && (count == 0 || n < count); i++ {
+			if l[i] == args[3] {
+				removed[i] = true
+				n++
+			}
+		}
+	} else {
+		for i := len(l) - 1; i >= 0 && n < -count; i-- {
+			if l[i] == args[3] {
+				removed[i] = true
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		c.w.int(0)
+		return
+	}
+
+	kept := make(list, 0, len(l)-int(n))
+	for i, v := range l {
+		if !removed[i] {
+			kept = append(kept, v)
+		}
+	}
+	c.setList(args[1], e, kept)
+	c.w.int(n)
+}
+
+func cmdLTrim(c *conn, args []string) {
+	start, ok1 := parseInt(args[2])
+	stop, ok2 := parseInt(args[3])
+	if !ok1 || !ok2 {
+		c.w.error(errNotInt)
+		return
+	}
+	e, l, ok := c.getList(args[1])
+	if !ok {
+		return
+	}
+	if e != nil {
+		lo, hi, ok := rangeIndexes(start, stop, len(l))
+		if !ok {
+			lo, hi = 0, 0
+		}
+		c.setList(args[1], e, append(list(nil), l[lo:hi]...))
+	}
+	c.w.ok()
+}
+
+func cmdLMove(c *conn, args []string) {
+	var fromLeft, toLeft bool
+	for i, arg := range args[3:] {
+		switch strings.ToLower(arg) {
+		case "left":
+			if i == 0 {
+				fromLeft = true
+			} else {
+				toLeft = true
+			}
+		case "right":
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+	c.move(args[1], args[2], fromLeft, toLeft)
+}
+
+func cmdRPopLPush(c *conn, args []string) {
+	c.move(args[1], args[2], false, true)
+}
+
+func (c *conn) move(src, dst string, fromLeft, toLeft bool) {
+	e, l, ok := c.getList(src)
+	if !ok {
+		return
+	}
+	if e == nil {
+		c.w.null()
+		return
+	}
+	if _, _, ok := c.getList(dst); !ok {
+		return
+	}
+
+	var v string
+	if fromLeft {
+		v, l = l[0], l[1:]
+	} else {
+		v, l = l[len(l)-1], l[:len(l)-1]
+	}
+	c.setList(src, e, l)
+
+	de, _ := c.lookupOrCreate(dst, "list", newList)
+	if toLeft {
+		de.value = append(list{v}, de.value.(list)...)
+	} else {
+		de.value = append(de.value.(list), v)
+	}
+	c.db().touch(c.srv, dst)
+	c.w.bulk(v)
+}
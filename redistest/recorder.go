@@ -0,0 +1,264 @@
+package redistest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Recorder is a hook recording the commands processed by a client. It also
+// replies to the commands matching its stubs instead of sending them to
+// Redis:
+//
+//	rec := redistest.NewRecorder()
+//	client.AddHook(rec)
+//
+//	rec.On("get", "key").Return("value")
+//	rec.On("set").Error(errors.New("READONLY You can't write against a read only replica."))
+//
+//	...
+//
+//	if !rec.ContainsSequence("get key", "set key value") {
+//		t.Fatalf("got %q", rec.Commands())
+//	}
+//
+// A client whose commands are all stubbed needs no server.
+type Recorder struct {
+	mu    sync.Mutex
+	cmds  [][]interface{}
+	stubs []*Stub
+}
+
+var _ redis.Hook = (*Recorder)(nil)
+
+// NewRecorder returns a recorder without stubs.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (r *Recorder) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if r.record(cmd) {
+			return cmd.Err()
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (r *Recorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		var firstErr error
+		sent := make([]redis.Cmder, 0, len(cmds))
+		for _, cmd := range cmds {
+			if !r.record(cmd) {
+				sent = append(sent, cmd)
+			} else if err := cmd.Err(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		if len(sent) > 0 {
+			if err := next(ctx, sent); err != nil {
+				return err
+			}
+		}
+		return firstErr
+	}
+}
+
+// record records cmd and reports whether it was stubbed.
+func (r *Recorder) record(cmd redis.Cmder) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cmds = append(r.cmds, cmd.Args())
+	for _, stub := range r.stubs {
+		if stub.use(cmd) {
+			stub.apply(cmd)
+			return true
+		}
+	}
+	return false
+}
+
+// On stubs the commands named name whose first arguments are args, compared
+// after formatting them with fmt.Sprint. The stubs are matched in the order
+// they were added. A stub without reply nor error replies with a nil value.
+func (r *Recorder) On(name string, args ...interface{}) *Stub {
+	stub := &Stub{
+		rec:  r,
+		name: strings.ToLower(name),
+		args: formatArgs(args),
+	}
+	r.mu.Lock()
+	r.stubs = append(r.stubs, stub)
+	r.mu.Unlock()
+	return stub
+}
+
+// Commands returns the recorded commands, formatted as their arguments
+// separated by spaces, e.g. "set key value ex 10".
+func (r *Recorder) Commands() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmds := make([]string, len(r.cmds))
+	for i, args := range r.cmds {
+		cmds[i] = strings.Join(formatArgs(args), " ")
+	}
+	return cmds
+}
+
+// Args returns the arguments of the recorded commands.
+func (r *Recorder) Args() [][]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]interface{}(nil), r.cmds...)
+}
+
+// Count returns the number of recorded commands named name.
+func (r *Recorder) Count(name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int
+	for _, args := range r.cmds {
+		if len(args) > 0 && strings.EqualFold(fmt.Sprint(args[0]), name) {
+			n++
+		}
+	}
+	return n
+}
+
+// Contains reports whether the command cmd, formatted as by Commands, was
+// recorded.
+func (r *Recorder) Contains(cmd string) bool {
+	return r.ContainsSequence(cmd)
+}
+
+// ContainsSequence reports whether the commands cmds, formatted as by
+// Commands, were recorded in this order, possibly with other commands
+// in between.
+func (r *Recorder) ContainsSequence(cmds ...string) bool {
+	i := 0
+	for _, cmd := range r.Commands() {
+		if i < len(cmds) && cmd == cmds[i] {
+			i++
+		}
+	}
+	return i == len(cmds)
+}
+
+// Reset forgets the recorded commands, keeping the stubs.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.cmds = nil
+	r.mu.Unlock()
+}
+
+// ResetStubs removes the stubs.
+func (r *Recorder) ResetStubs() {
+	r.mu.Lock()
+	r.stubs = nil
+	r.mu.Unlock()
+}
+
+// Stub is the reply to the commands matching a Recorder.On stub.
+type Stub struct {
+	rec  *Recorder
+	name string
+	args []string
+
+	val   interface{}
+	err   error
+	times int
+	used  int
+}
+
+// Return replies with val, set with the SetVal method of the commands: val
+// must have the type of their values, or be convertible to it, e.g. a string
+// for the StringCmd of GET or the StatusCmd of SET.
+func (s *Stub) Return(val interface{}) *Stub {
+	s.rec.mu.Lock()
+	s.val = val
+	s.rec.mu.Unlock()
+	return s
+}
+
+// Error replies with err, e.g. redis.Nil for a missing key.
+func (s *Stub) Error(err error) *Stub {
+	s.rec.mu.Lock()
+	s.err = err
+	s.rec.mu.Unlock()
+	return s
+}
+
+// Times limits the stub to its n next matching commands. Default is no limit.
+func (s *Stub) Times(n int) *Stub {
+	s.rec.mu.Lock()
+	s.times = n
+	s.rec.mu.Unlock()
+	return s
+}
+
+// use reports whether the stub matches cmd and is not exhausted, counting the
+// use.
+func (s *Stub) use(cmd redis.Cmder) bool {
+	if s.times > 0 && s.used >= s.times {
+		return false
+	}
+	args := formatArgs(cmd.Args())
+	if len(args) == 0 || strings.ToLower(args[0]) != s.name || len(args)-1 < len(s.args) {
+		return false
+	}
+	for i, arg := range s.args {
+		if args[i+1] != arg {
+			return false
+		}
+	}
+	s.used++
+	return true
+}
+
+func (s *Stub) apply(cmd redis.Cmder) {
+	if s.err != nil {
+		cmd.SetErr(s.err)
+		return
+	}
+	if s.val == nil {
+		return
+	}
+
+	setVal := reflect.ValueOf(cmd).MethodByName("SetVal")
+	if !setVal.IsValid() || setVal.Type().NumIn() != 1 {
+		cmd.SetErr(fmt.Errorf("redistest: %T has no SetVal method", cmd))
+		return
+	}
+	typ := setVal.Type().In(0)
+	val := reflect.ValueOf(s.val)
+	switch {
+	case val.Type().AssignableTo(typ):
+	case val.Type().ConvertibleTo(typ) && (typ.Kind() != reflect.String || val.Kind() == reflect.String):
+		val = val.Convert(typ)
+	default:
+		cmd.SetErr(fmt.Errorf("redistest: cannot reply %T to %T", s.val, cmd))
+		return
+	}
+	setVal.Call([]reflect.Value{val})
+}
+
+func formatArgs(args []interface{}) []string {
+	ss := make([]string, len(args))
+	for i, arg := range args {
+		ss[i] = fmt.Sprint(arg)
+	}
+	return ss
+}
@@ -0,0 +1,80 @@
+package redistest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newClient(t, 3)
+	rec := redistest.NewRecorder()
+	client.AddHook(rec)
+
+	rec.On("get", "stubbed").Return("value")
+	rec.On("incr").Return(42).Times(1)
+	errReadOnly := errors.New("READONLY You can't write against a read only replica.")
+	rec.On("set", "readonly").Error(errReadOnly)
+
+	v, err := client.Get(ctx, "stubbed").Result()
+	noErr(t, err)
+	check(t, v, "value")
+
+	n, err := client.Incr(ctx, "counter").Result()
+	noErr(t, err)
+	check(t, n, int64(42))
+	n, err = client.Incr(ctx, "counter").Result()
+	noErr(t, err)
+	check(t, n, int64(1))
+
+	err = client.Set(ctx, "readonly", "x", 0).Err()
+	check(t, err, errReadOnly)
+
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "key", "x", 0)
+		pipe.Get(ctx, "stubbed")
+		return nil
+	})
+	noErr(t, err)
+
+	check(t, rec.Commands(), []string{
+		"get stubbed",
+		"incr counter",
+		"incr counter",
+		"set readonly x",
+		"set key x",
+		"get stubbed",
+	})
+	check(t, rec.ContainsSequence("incr counter", "set key x"), true)
+	check(t, rec.ContainsSequence("set key x", "incr counter"), false)
+	check(t, rec.Count("INCR"), 2)
+	check(t, srv.Keys(), []string{"counter", "key"})
+
+	rec.Reset()
+	check(t, rec.Commands(), []string{})
+}
+
+func TestRecorderWithoutServer(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+	defer client.Close()
+	rec := redistest.NewRecorder()
+	client.AddHook(rec)
+
+	rec.On("get").Error(redis.Nil)
+	rec.On("hgetall").Return(map[string]string{"a": "1"})
+	rec.On("zscore").Return("1")
+
+	check(t, client.Get(ctx, "key").Err(), redis.Nil)
+	m, err := client.HGetAll(ctx, "hash").Result()
+	noErr(t, err)
+	check(t, m, map[string]string{"a": "1"})
+
+	if err := client.ZScore(ctx, "z", "m").Err(); err == nil {
+		t.Fatal("expected an error replying a string to a FloatCmd")
+	}
+}
@@ -0,0 +1,293 @@
+package redistest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func newClient(t *testing.T, protocol int) (*redistest.Server, *redis.Client) {
+	t.Helper()
+	srv := redistest.NewServer()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), Protocol: protocol})
+	t.Cleanup(func() {
+		_ = client.Close()
+		srv.Close()
+	})
+	return srv, client
+}
+
+func forEachProtocol(t *testing.T, fn func(t *testing.T, srv *redistest.Server, client *redis.Client)) {
+	for _, protocol := range []int{2, 3} {
+		protocol := protocol
+		t.Run(map[int]string{2: "RESP2", 3: "RESP3"}[protocol], func(t *testing.T) {
+			srv, client := newClient(t, protocol)
+			fn(t, srv, client)
+		})
+	}
+}
+
+func check(t *testing.T, got, want interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func noErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		noErr(t, client.Set(ctx, "key", "value", 0).Err())
+		v, err := client.Get(ctx, "key").Result()
+		noErr(t, err)
+		check(t, v, "value")
+
+		_, err = client.Get(ctx, "missing").Result()
+		check(t, err, redis.Nil)
+
+		ok, err := client.SetNX(ctx, "key", "other", 0).Result()
+		noErr(t, err)
+		check(t, ok, false)
+
+		n, err := client.IncrBy(ctx, "counter", 5).Result()
+		noErr(t, err)
+		check(t, n, int64(5))
+		f, err := client.IncrByFloat(ctx, "counter", 0.5).Result()
+		noErr(t, err)
+		check(t, f, 5.5)
+
+		vals, err := client.MGet(ctx, "key", "missing").Result()
+		noErr(t, err)
+		check(t, vals, []interface{}{"value", nil})
+
+		err = client.Incr(ctx, "key").Err()
+		if err == nil || err.Error() != "ERR value is not an integer or out of range" {
+			t.Fatalf("got %v", err)
+		}
+
+		v, ok = srv.Get("key")
+		check(t, v, "value")
+		check(t, ok, true)
+	})
+}
+
+func TestExpiration(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		noErr(t, client.Set(ctx, "key", "value", time.Minute).Err())
+		ttl, err := client.TTL(ctx, "key").Result()
+		noErr(t, err)
+		check(t, ttl, time.Minute)
+
+		srv.FastForward(time.Minute)
+		_, err = client.Get(ctx, "key").Result()
+		check(t, err, redis.Nil)
+
+		ttl, err = client.TTL(ctx, "key").Result()
+		noErr(t, err)
+		check(t, ttl, time.Duration(-2))
+	})
+}
+
+func TestHashes(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		n, err := client.HSet(ctx, "hash", "a", "1", "b", "2").Result()
+		noErr(t, err)
+		check(t, n, int64(2))
+
+		all, err := client.HGetAll(ctx, "hash").Result()
+		noErr(t, err)
+		check(t, all, map[string]string{"a": "1", "b": "2"})
+
+		ok, err := client.HExists(ctx, "hash", "a").Result()
+		noErr(t, err)
+		check(t, ok, true)
+
+		n, err = client.HIncrBy(ctx, "hash", "a", 10).Result()
+		noErr(t, err)
+		check(t, n, int64(11))
+
+		noErr(t, client.HDel(ctx, "hash", "a", "b").Err())
+		check(t, srv.Keys(), []string{})
+
+		err = client.Set(ctx, "key", "value", 0).Err()
+		noErr(t, err)
+		err = client.HGet(ctx, "key", "a").Err()
+		var rerr redis.Error
+		if !errors.As(err, &rerr) || !redis.HasErrorPrefix(err, "WRONGTYPE") {
+			t.Fatalf("got %v", err)
+		}
+	})
+}
+
+func TestLists(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		noErr(t, client.RPush(ctx, "list", "a", "b", "c").Err())
+		noErr(t, client.LPush(ctx, "list", "z").Err())
+
+		vals, err := client.LRange(ctx, "list", 0, -1).Result()
+		noErr(t, err)
+		check(t, vals, []string{"z", "a", "b", "c"})
+
+		v, err := client.RPop(ctx, "list").Result()
+		noErr(t, err)
+		check(t, v, "c")
+
+		vals, err = client.LPopCount(ctx, "list", 2).Result()
+		noErr(t, err)
+		check(t, vals, []string{"z", "a"})
+
+		v, err = client.LMove(ctx, "list", "other", "LEFT", "RIGHT").Result()
+		noErr(t, err)
+		check(t, v, "b")
+		check(t, srv.Keys(), []string{"other"})
+	})
+}
+
+func TestSets(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		noErr(t, client.SAdd(ctx, "s1", "a", "b", "c").Err())
+		noErr(t, client.SAdd(ctx, "s2", "b", "c", "d").Err())
+
+		members, err := client.SMembers(ctx, "s1").Result()
+		noErr(t, err)
+		check(t, members, []string{"a", "b", "c"})
+
+		members, err = client.SInter(ctx, "s1", "s2").Result()
+		noErr(t, err)
+		check(t, members, []string{"b", "c"})
+
+		ok, err := client.SIsMember(ctx, "s1", "d").Result()
+		noErr(t, err)
+		check(t, ok, false)
+	})
+}
+
+func TestSortedSets(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		noErr(t, client.ZAdd(ctx, "z", redis.Z{Score: 2, Member: "b"}, redis.Z{Score: 1, Member: "a"}, redis.Z{Score: 3, Member: "c"}).Err())
+
+		score, err := client.ZScore(ctx, "z", "b").Result()
+		noErr(t, err)
+		check(t, score, 2.0)
+
+		zs, err := client.ZRangeWithScores(ctx, "z", 0, 1).Result()
+		noErr(t, err)
+		check(t, zs, []redis.Z{{Score: 1, Member: "a"}, {Score: 2, Member: "b"}})
+
+		members, err := client.ZRangeByScore(ctx, "z", &redis.ZRangeBy{Min: "(1", Max: "+inf"}).Result()
+		noErr(t, err)
+		check(t, members, []string{"b", "c"})
+
+		members, err = client.ZRevRange(ctx, "z", 0, 0).Result()
+		noErr(t, err)
+		check(t, members, []string{"c"})
+
+		zs, err = client.ZPopMin(ctx, "z").Result()
+		noErr(t, err)
+		check(t, zs, []redis.Z{{Score: 1, Member: "a"}})
+
+		rank, err := client.ZRank(ctx, "z", "c").Result()
+		noErr(t, err)
+		check(t, rank, int64(1))
+	})
+}
+
+func TestTransactions(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		cmds, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "key", "1", 0)
+			pipe.Incr(ctx, "key")
+			return nil
+		})
+		noErr(t, err)
+		check(t, cmds[1].(*redis.IntCmd).Val(), int64(2))
+
+		err = client.Watch(ctx, func(tx *redis.Tx) error {
+			if err := tx.Get(ctx, "key").Err(); err != nil {
+				return err
+			}
+			// a concurrent modification fails the transaction
+			if err := client.Set(ctx, "key", "3", 0).Err(); err != nil {
+				return err
+			}
+			_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, "key", "4", 0)
+				return nil
+			})
+			return err
+		}, "key")
+		check(t, err, redis.TxFailedErr)
+
+		v, _ := srv.Get("key")
+		check(t, v, "3")
+	})
+}
+
+func TestScan(t *testing.T) {
+	forEachProtocol(t, func(t *testing.T, srv *redistest.Server, client *redis.Client) {
+		ctx := context.Background()
+
+		for _, key := range []string{"user:1", "user:2", "order:1", "user:3"} {
+			noErr(t, client.Set(ctx, key, "x", 0).Err())
+		}
+		var keys []string
+		iter := client.Scan(ctx, 0, "user:*", 2).Iterator()
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		noErr(t, iter.Err())
+		check(t, keys, []string{"user:1", "user:2", "user:3"})
+	})
+}
+
+func TestAuth(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	srv.SetPassword("secret")
+	ctx := context.Background()
+
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), Password: "wrong"})
+	defer client.Close()
+	if err := client.Ping(ctx).Err(); !redis.HasErrorPrefix(err, "WRONGPASS") {
+		t.Fatalf("got %v", err)
+	}
+
+	for _, protocol := range []int{2, 3} {
+		client := redis.NewClient(&redis.Options{Addr: srv.Addr(), Password: "secret", Protocol: protocol})
+		noErr(t, client.Ping(ctx).Err())
+		_ = client.Close()
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, client := newClient(t, 3)
+	err := client.Do(context.Background(), "NOPE", "arg").Err()
+	if err == nil || err.Error() != "ERR unknown command 'NOPE', with args beginning with: 'arg' " {
+		t.Fatalf("got %v", err)
+	}
+}
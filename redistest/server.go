@@ -0,0 +1,461 @@
+// Package redistest implements an in-memory Redis server for tests.
+//
+// The server speaks RESP2 and RESP3 and implements the commands of strings,
+// keys, hashes, lists, sets and sorted sets, as well as transactions:
+//
+//	srv := redistest.NewServer()
+//	defer srv.Close()
+//
+//	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+//
+// Unknown commands reply with an error. Expirations follow the clock of the
+// server, which FastForward advances.
+//
+// Recorder is a hook recording the commands of a client and stubbing their
+// replies, with or without a server.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is an in-memory Redis server listening on a local address.
+type Server struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	dbs      map[int]*db
+	version  uint64
+	offset   time.Duration
+	password string
+	nextID   int64
+	conns    map[*conn]struct{}
+	tracked  tracking
+	closed   bool
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server listening on a random port of 127.0.0.1.
+// It panics when it cannot listen.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redistest: failed to listen: %v", err))
+	}
+	s := &Server{
+		ln:    ln,
+		dbs:   make(map[int]*db),
+		conns: make(map[*conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Addr returns the address of the server, for redis.Options.Addr.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	_ = s.ln.Close()
+	for c := range s.conns {
+		_ = c.netConn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// SetPassword requires the clients to authenticate with password, or not when
+// password is empty.
+func (s *Server) SetPassword(password string) {
+	s.mu.Lock()
+	s.password = password
+	s.mu.Unlock()
+}
+
+// FlushAll deletes the keys of all the databases.
+func (s *Server) FlushAll() {
+	s.mu.Lock()
+	for _, d := range s.dbs {
+		s.flush(d)
+	}
+	s.mu.Unlock()
+}
+
+// FastForward advances the clock of the server by d, expiring the keys whose
+// TTL elapsed.
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	s.offset += d
+	s.mu.Unlock()
+}
+
+// Get returns the string value of key in database 0, for assertions.
+func (s *Server) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.db(0).get(s, key)
+	if e == nil {
+		return "", false
+	}
+	v, ok := e.value.(string)
+	return v, ok
+}
+
+// Keys returns the sorted keys of database 0, for assertions.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db(0).sortedKeys(s)
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) db(i int) *db {
+	d, ok := s.dbs[i]
+	if !ok {
+		d = newDB()
+		s.dbs[i] = d
+	}
+	return d
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = nc.Close()
+			return
+		}
+		s.nextID++
+		c := &conn{
+			srv:     s,
+			netConn: nc,
+			rd:      bufio.NewReader(nc),
+			w:       &writer{bw: bufio.NewWriter(nc), proto: 2},
+			id:      s.nextID,
+		}
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.serve()
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.untrack(c)
+			s.mu.Unlock()
+			_ = nc.Close()
+		}()
+	}
+}
+
+type conn struct {
+	srv     *Server
+	netConn net.Conn
+	rd      *bufio.Reader
+	w       *writer
+
+	id      int64
+	dbIndex int
+	name    string
+	authed  bool
+
+	// replyOff and replySkip are set by CLIENT REPLY.
+	replyOff  bool
+	replySkip bool
+
+	// tracking is set by CLIENT TRACKING, and invalidated are the keys to
+	// push the invalidation of.
+	tracking    bool
+	invalidated []string
+	// bcast is set by CLIENT TRACKING ON BCAST, with the tracked prefixes:
+	// the invalidations of the keys matching them are pushed right away.
+	bcast    bool
+	prefixes []string
+
+	inMulti bool
+	multiOK bool
+	queued  [][]string
+	watched map[watchKey]uint64
+}
+
+type watchKey struct {
+	db  int
+	key string
+}
+
+func (c *conn) serve() {
+	for {
+		args, err := readCommand(c.rd)
+		if err != nil {
+			if err != io.EOF {
+				c.w.error("ERR Protocol error: " + err.Error())
+				_ = c.flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		c.w.off = c.replyOff || c.replySkip
+		c.replySkip = false
+		quit := c.dispatch(args)
+		if err := c.flush(); err != nil || quit {
+			return
+		}
+	}
+}
+
+// flush writes the replies of c, with the server locked as the invalidations
+// of the broadcast tracking are pushed by the other connections.
+func (c *conn) flush() error {
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+	return c.w.flush()
+}
+
+// dispatch runs a command and reports whether the connection must be closed.
+func (c *conn) dispatch(args []string) bool {
+	name := strings.ToLower(args[0])
+
+	s := c.srv
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.pushInvalidated()
+	if name == "quit" {
+		c.w.simple("OK")
+		return true
+	}
+	if s.password != "" && !c.authed && name != "auth" && name != "hello" {
+		c.w.error("NOAUTH Authentication required.")
+		return false
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		c.w.error(fmt.Sprintf("ERR unknown command '%s', with args beginning with: %s", args[0], quoteArgs(args[1:])))
+		if c.inMulti {
+			c.multiOK = false
+		}
+		return false
+	}
+	if !cmd.arityOK(len(args)) {
+		c.w.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+		if c.inMulti {
+			c.multiOK = false
+		}
+		return false
+	}
+
+	if c.inMulti && !cmd.tx {
+		c.queued = append(c.queued, args)
+		c.w.simple("QUEUED")
+		return false
+	}
+	version := s.version
+	cmd.fn(c, args)
+	if len(args) > 1 && s.version == version && !cmd.keyless && !cmd.tx {
+		// the key of a command which did not modify it was read
+		s.track(c, args[1])
+	}
+	return false
+}
+
+func quoteArgs(args []string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		b.WriteString("'")
+		b.WriteString(arg)
+		b.WriteString("' ")
+	}
+	return b.String()
+}
+
+// readCommand reads a command sent as an array of bulk strings or inline.
+func readCommand(rd *bufio.Reader) ([]string, error) {
+	line, err := readLine(rd)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > 1024*1024 {
+		return nil, errors.New("invalid multibulk length")
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := readLine(rd)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("expected '$', got '%s'", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > 512*1024*1024 {
+			return nil, errors.New("invalid bulk length")
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(rd, b); err != nil {
+			return nil, err
+		}
+		args = append(args, string(b[:size]))
+	}
+	return args, nil
+}
+
+func readLine(rd *bufio.Reader) (string, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// writer writes the replies in the protocol version of the connection.
+type writer struct {
+	bw    *bufio.Writer
+	proto int
+	off   bool // the replies are discarded
+}
+
+func (w *writer) flush() error {
+	return w.bw.Flush()
+}
+
+func (w *writer) line(prefix byte, s string) {
+	if w.off {
+		return
+	}
+	_ = w.bw.WriteByte(prefix)
+	_, _ = w.bw.WriteString(s)
+	_, _ = w.bw.WriteString("\r\n")
+}
+
+func (w *writer) simple(s string) { w.line('+', s) }
+
+func (w *writer) ok() { w.simple("OK") }
+
+func (w *writer) error(msg string) { w.line('-', msg) }
+
+func (w *writer) int(n int64) { w.line(':', strconv.FormatInt(n, 10)) }
+
+func (w *writer) bool(b bool) {
+	if w.proto == 3 {
+		if b {
+			w.line('#', "t")
+		} else {
+			w.line('#', "f")
+		}
+		return
+	}
+	if b {
+		w.int(1)
+	} else {
+		w.int(0)
+	}
+}
+
+func (w *writer) bulk(s string) {
+	w.line('$', strconv.Itoa(len(s)))
+	if w.off {
+		return
+	}
+	_, _ = w.bw.WriteString(s)
+	_, _ = w.bw.WriteString("\r\n")
+}
+
+func (w *writer) null() {
+	if w.proto == 3 {
+		w.line('_', "")
+	} else {
+		w.line('$', "-1")
+	}
+}
+
+func (w *writer) nullArray() {
+	if w.proto == 3 {
+		w.line('_', "")
+	} else {
+		w.line('*', "-1")
+	}
+}
+
+func (w *writer) array(n int) { w.line('*', strconv.Itoa(n)) }
+
+func (w *writer) mapLen(n int) {
+	if w.proto == 3 {
+		w.line('%', strconv.Itoa(n))
+	} else {
+		w.array(2 * n)
+	}
+}
+
+func (w *writer) setLen(n int) {
+	if w.proto == 3 {
+		w.line('~', strconv.Itoa(n))
+	} else {
+		w.array(n)
+	}
+}
+
+func (w *writer) double(f float64) {
+	if w.proto == 3 {
+		w.line(',', formatFloat(f))
+	} else {
+		w.bulk(formatFloat(f))
+	}
+}
+
+func (w *writer) bulks(ss []string) {
+	w.array(len(ss))
+	for _, s := range ss {
+		w.bulk(s)
+	}
+}
+
+func formatFloat(f float64) string {
+	if f == float64(int64(f)) && f < 1e15 && f > -1e15 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
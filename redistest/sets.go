@@ -0,0 +1,253 @@
+package redistest
+
+import (
+	"math/rand"
+	"sort"
+)
+
+func newSet() interface{} { return set{} }
+
+func (c *conn) getSet(key string) (set, bool) {
+	e, ok := c.lookup(key, "set")
+	if !ok || e == nil {
+		return nil, ok
+	}
+	return e.value.(set), true
+}
+
+func (s set) sortedMembers() []string {
+	members := make([]string, 0, len(s))
+	for m := range s {
+		members = append(members, m)
+	}
+	sort.Strings(members)
+	return members
+}
+
+func (c *conn) writeSet(members []string) {
+	c.w.setLen(len(members))
+	for _, m := range members {
+		c.w.bulk(m)
+	}
+}
+
+func cmdSAdd(c *conn, args []string) {
+	e, ok := c.lookupOrCreate(args[1], "set", newSet)
+	if !ok {
+		return
+	}
+	s := e.value.(set)
+	var n int64
+	for _, m := range args[2:] {
+		if _, ok := s[m]; !ok {
+			s[m] = struct{}{}
+			n++
+		}
+	}
+	c.db().touch(c.srv, args[1])
+	c.w.int(n)
+}
+
+func cmdSRem(c *conn, args []string) {
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	for _, m := range args[2:] {
+		if _, ok := s[m]; ok {
+			delete(s, m)
+			n++
+		}
+	}
+	if n > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+	c.w.int(n)
+}
+
+func cmdSMembers(c *conn, args []string) {
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	c.writeSet(s.sortedMembers())
+}
+
+func cmdSIsMember(c *conn, args []string) {
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	if _, ok := s[args[2]]; ok {
+		c.w.int(1)
+	} else {
+		c.w.int(0)
+	}
+}
+
+func cmdSMIsMember(c *conn, args []string) {
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	c.w.array(len(args) - 2)
+	for _, m := range args[2:] {
+		if _, ok := s[m]; ok {
+			c.w.int(1)
+		} else {
+			c.w.int(0)
+		}
+	}
+}
+
+func cmdSCard(c *conn, args []string) {
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	c.w.int(int64(len(s)))
+}
+
+func cmdSPop(c *conn, args []string) {
+	count, withCount := int64(1), len(args) > 2
+	if withCount {
+		n, ok := parseInt(args[2])
+		if !ok || n < 0 || len(args) > 3 {
+			c.w.error("ERR value is out of range, must be positive")
+			return
+		}
+		count = n
+	}
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	if s == nil && !withCount {
+		c.w.null()
+		return
+	}
+
+	members := s.sortedMembers()
+	rand.Shuffle(len(members), func(i, j int) {
+		members[i], members[j] = members[j], members[i]
+	})
+	if count < int64(len(members)) {
+		members = members[:count]
+	}
+	for _, m := range members {
+		delete(s, m)
+	}
+	if len(members) > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+
+	if withCount {
+		c.writeSet(members)
+	} else {
+		c.w.bulk(members[0])
+	}
+}
+
+func cmdSRandMember(c *conn, args []string) {
+	count, withCount := int64(1), len(args) > 2
+	if withCount {
+		n, ok := parseInt(args[2])
+		if !ok || len(args) > 3 {
+			c.w.error(errNotInt)
+			return
+		}
+		count = n
+	}
+	s, ok := c.getSet(args[1])
+	if !ok {
+		return
+	}
+	members := s.sortedMembers()
+	if !withCount {
+		if len(members) == 0 {
+			c.w.null()
+			return
+		}
+		c.w.bulk(members[rand.Intn(len(members))])
+		return
+	}
+
+	var picked []string
+	if count < 0 && len(members) > 0 {
+		// a negative count allows picking the same member several times
+		for i := int64(0); i < -count; i++ {
+			picked = append(picked, members[rand.Intn(len(members))])
+		}
+	} else if count > 0 {
+		rand.Shuffle(len(members), func(i, j int) {
+			members[i], members[j] = members[j], members[i]
+		})
+		if count < int64(len(members)) {
+			members = members[:count]
+		}
+		picked = members
+	}
+	c.w.bulks(picked)
+}
+
+func setInter(sets []set) set {
+	res := set{}
+	if len(sets) == 0 {
+		return res
+	}
+outer:
+	for m := range sets[0] {
+		for _, s := range sets[1:] {
+			if _, ok := s[m]; !ok {
+				continue outer
+			}
+		}
+		res[m] = struct{}{}
+	}
+	return res
+}
+
+func setUnion(sets []set) set {
+	res := set{}
+	for _, s := range sets {
+		for m := range s {
+			res[m] = struct{}{}
+		}
+	}
+	return res
+}
+
+func setDiff(sets []set) set {
+	res := set{}
+	if len(sets) == 0 {
+		return res
+	}
+	for m := range sets[0] {
+		res[m] = struct{}{}
+	}
+	for _, s := range sets[1:] {
+		for m := range s {
+			delete(res, m)
+		}
+	}
+	return res
+}
+
+func cmdSetOp(op func(sets []set) set) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		sets := make([]set, 0, len(args)-1)
+		for _, key := range args[1:] {
+			s, ok := c.getSet(key)
+			if !ok {
+				return
+			}
+			sets = append(sets, s)
+		}
+		c.writeSet(op(sets).sortedMembers())
+	}
+}
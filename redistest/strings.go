@@ -0,0 +1,403 @@
+package redistest
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *conn) getString(key string) (string, bool, bool) {
+	e, ok := c.lookup(key, "string")
+	if !ok || e == nil {
+		return "", false, ok
+	}
+	return e.value.(string), true, true
+}
+
+func cmdGet(c *conn, args []string) {
+	v, exists, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	if !exists {
+		c.w.null()
+		return
+	}
+	c.w.bulk(v)
+}
+
+func cmdSet(c *conn, args []string) {
+	var nx, xx, get, keepTTL bool
+	var expireAt time.Time
+	now := c.srv.now()
+	for i := 3; i < len(args); i++ {
+		switch opt := strings.ToLower(args[i]); opt {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "get":
+			get = true
+		case "keepttl":
+			keepTTL = true
+		case "ex", "px", "exat", "pxat":
+			if i+1 >= len(args) || !expireAt.IsZero() {
+				c.w.error(errSyntax)
+				return
+			}
+			n, ok := parseInt(args[i+1])
+			if !ok {
+				c.w.error(errNotInt)
+				return
+			}
+			if n <= 0 {
+				c.w.error("ERR invalid expire time in 'set' command")
+				return
+			}
+			switch opt {
+			case "ex":
+				expireAt = now.Add(time.Duration(n) * time.Second)
+			case "px":
+				expireAt = now.Add(time.Duration(n) * time.Millisecond)
+			case "exat":
+				expireAt = time.Unix(n, 0)
+			case "pxat":
+				expireAt = time.UnixMilli(n)
+			}
+			i++
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+	if nx && xx || keepTTL && !expireAt.IsZero() {
+		c.w.error(errSyntax)
+		return
+	}
+
+	d := c.db()
+	old := d.get(c.srv, args[1])
+	if get && old != nil {
+		if _, ok := old.value.(string); !ok {
+			c.w.error(errWrongType)
+			return
+		}
+	}
+	reply := func() {
+		if get && old != nil {
+			c.w.bulk(old.value.(string))
+		} else {
+			c.w.null()
+		}
+	}
+	if nx && old != nil || xx && old == nil {
+		reply()
+		return
+	}
+
+	e := d.set(c.srv, args[1], args[2])
+	switch {
+	case keepTTL && old != nil:
+		e.expireAt = old.expireAt
+	case !expireAt.IsZero():
+		e.expireAt = expireAt
+	}
+	if get {
+		reply()
+	} else {
+		c.w.ok()
+	}
+}
+
+func cmdSetNX(c *conn, args []string) {
+	d := c.db()
+	if d.get(c.srv, args[1]) != nil {
+		c.w.int(0)
+		return
+	}
+	d.set(c.srv, args[1], args[2])
+	c.w.int(1)
+}
+
+func cmdSetEX(unit time.Duration) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		n, ok := parseInt(args[2])
+		if !ok {
+			c.w.error(errNotInt)
+			return
+		}
+		if n <= 0 {
+			c.w.error("ERR invalid expire time in '" + strings.ToLower(args[0]) + "' command")
+			return
+		}
+		e := c.db().set(c.srv, args[1], args[3])
+		e.expireAt = c.srv.now().Add(time.Duration(n) * unit)
+		c.w.ok()
+	}
+}
+
+func cmdGetSet(c *conn, args []string) {
+	v, exists, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	c.db().set(c.srv, args[1], args[2])
+	if !exists {
+		c.w.null()
+		return
+	}
+	c.w.bulk(v)
+}
+
+func cmdGetDel(c *conn, args []string) {
+	v, exists, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	if !exists {
+		c.w.null()
+		return
+	}
+	c.db().del(c.srv, args[1])
+	c.w.bulk(v)
+}
+
+func cmdGetEX(c *conn, args []string) {
+	var expireAt time.Time
+	var persist bool
+	now := c.srv.now()
+	for i := 2; i < len(args); i++ {
+		switch opt := strings.ToLower(args[i]); opt {
+		case "persist":
+			persist = true
+		case "ex", "px", "exat", "pxat":
+			if i+1 >= len(args) {
+				c.w.error(errSyntax)
+				return
+			}
+			n, ok := parseInt(args[i+1])
+			if !ok {
+				c.w.error(errNotInt)
+				return
+			}
+			if n <= 0 {
+				c.w.error("ERR invalid expire time in 'getex' command")
+				return
+			}
+			switch opt {
+			case "ex":
+				expireAt = now.Add(time.Duration(n) * time.Second)
+			case "px":
+				expireAt = now.Add(time.Duration(n) * time.Millisecond)
+			case "exat":
+				expireAt = time.Unix(n, 0)
+			case "pxat":
+				expireAt = time.UnixMilli(n)
+			}
+			i++
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+
+	e, ok := c.lookup(args[1], "string")
+	if !ok {
+		return
+	}
+	if e == nil {
+		c.w.null()
+		return
+	}
+	switch {
+	case persist:
+		e.expireAt = time.Time{}
+		c.db().touch(c.srv, args[1])
+	case !expireAt.IsZero():
+		e.expireAt = expireAt
+		c.db().touch(c.srv, args[1])
+	}
+	c.w.bulk(e.value.(string))
+}
+
+func cmdMGet(c *conn, args []string) {
+	d := c.db()
+	c.w.array(len(args) - 1)
+	for _, key := range args[1:] {
+		e := d.get(c.srv, key)
+		if e == nil {
+			c.w.null()
+			continue
+		}
+		v, ok := e.value.(string)
+		if !ok {
+			c.w.null()
+			continue
+		}
+		c.w.bulk(v)
+	}
+}
+
+func cmdMSet(nx bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		if len(args)%2 == 0 {
+			c.w.error("ERR wrong number of arguments for '" + strings.ToLower(args[0]) + "' command")
+			return
+		}
+		d := c.db()
+		if nx {
+			for i := 1; i < len(args); i += 2 {
+				if d.get(c.srv, args[i]) != nil {
+					c.w.int(0)
+					return
+				}
+			}
+		}
+		for i := 1; i < len(args); i += 2 {
+			d.set(c.srv, args[i], args[i+1])
+		}
+		if nx {
+			c.w.int(1)
+		} else {
+			c.w.ok()
+		}
+	}
+}
+
+func cmdIncrBy(sign int64) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		delta := sign
+		if len(args) == 3 {
+			n, ok := parseInt(args[2])
+			if !ok {
+				c.w.error(errNotInt)
+				return
+			}
+			if sign < 0 {
+				if n == math.MinInt64 {
+					c.w.error("ERR decrement would overflow")
+					return
+				}
+				n = -n
+			}
+			delta = n
+		}
+
+		v, exists, ok := c.getString(args[1])
+		if !ok {
+			return
+		}
+		var n int64
+		if exists {
+			if n, ok = parseInt(v); !ok {
+				c.w.error(errNotInt)
+				return
+			}
+		}
+		if delta > 0 && n > math.MaxInt64-delta || delta < 0 && n < math.MinInt64-delta {
+			c.w.error("ERR increment or decrement would overflow")
+			return
+		}
+		n += delta
+		c.setString(args[1], strconv.FormatInt(n, 10))
+		c.w.int(n)
+	}
+}
+
+func cmdIncrByFloat(c *conn, args []string) {
+	delta, ok := parseFloat(args[2])
+	if !ok {
+		c.w.error(errNotFloat)
+		return
+	}
+	v, exists, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	var f float64
+	if exists {
+		if f, ok = parseFloat(v); !ok {
+			c.w.error(errNotFloat)
+			return
+		}
+	}
+	f += delta
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		c.w.error("ERR increment would produce NaN or Infinity")
+		return
+	}
+	s := formatFloat(f)
+	c.setString(args[1], s)
+	c.w.bulk(s)
+}
+
+// setString sets the value of the string key, keeping its expiration.
+func (c *conn) setString(key, value string) {
+	d := c.db()
+	if e := d.get(c.srv, key); e != nil {
+		e.value = value
+		d.touch(c.srv, key)
+		return
+	}
+	d.set(c.srv, key, value)
+}
+
+func cmdAppend(c *conn, args []string) {
+	v, _, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	v += args[2]
+	c.setString(args[1], v)
+	c.w.int(int64(len(v)))
+}
+
+func cmdStrLen(c *conn, args []string) {
+	v, _, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	c.w.int(int64(len(v)))
+}
+
+func cmdGetRange(c *conn, args []string) {
+	start, ok1 := parseInt(args[2])
+	end, ok2 := parseInt(args[3])
+	if !ok1 || !ok2 {
+		c.w.error(errNotInt)
+		return
+	}
+	v, _, ok := c.getString(args[1])
+	if !ok {
+		return
+	}
+	lo, hi, ok := rangeIndexes(start, end, len(v))
+	if !ok {
+		c.w.bulk("")
+		return
+	}
+	c.w.bulk(v[lo:hi])
+}
+
+// rangeIndexes converts the inclusive start and end indexes of Redis, which
+// count from the end when negative, to a slice range of a length n sequence.
+func rangeIndexes(start, end int64, n int) (int, int, bool) {
+	size := int64(n)
+	if start < 0 {
+		start += size
+	}
+	if end < 0 {
+		end += size
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end >= size {
+		end = size - 1
+	}
+	if start > end || start >= size {
+		return 0, 0, false
+	}
+	return int(start), int(end) + 1, true
+}
@@ -0,0 +1,89 @@
+package redistest
+
+import "strings"
+
+// tracking are the keys tracked by CLIENT TRACKING: the invalidations of the
+// keys read by a tracking connection are pushed to it before its next reply,
+// and those of the keys matching the prefixes of a broadcast tracking
+// connection right away.
+type tracking struct {
+	conns map[string]map[*conn]struct{} // by key
+}
+
+// track tracks key for c, when c tracks the keys it reads.
+func (s *Server) track(c *conn, key string) {
+	if !c.tracking || c.bcast {
+		return
+	}
+	if s.tracked.conns == nil {
+		s.tracked.conns = make(map[string]map[*conn]struct{})
+	}
+	conns := s.tracked.conns[key]
+	if conns == nil {
+		conns = make(map[*conn]struct{})
+		s.tracked.conns[key] = conns
+	}
+	conns[c] = struct{}{}
+}
+
+// invalidate queues the invalidation of key for the connections tracking it,
+// which stop tracking it, as in Redis.
+func (s *Server) invalidate(key string) {
+	for c := range s.tracked.conns[key] {
+		c.invalidated = append(c.invalidated, key)
+	}
+	delete(s.tracked.conns, key)
+
+	for c := range s.conns {
+		if c.bcast && c.matchPrefix(key) {
+			c.pushNow([]string{key})
+		}
+	}
+}
+
+// matchPrefix reports whether key matches a broadcast tracking prefix of c.
+func (c *conn) matchPrefix(key string) bool {
+	if len(c.prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range c.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// pushNow writes the invalidation push notification of keys to c, with the
+// server locked.
+func (c *conn) pushNow(keys []string) {
+	off := c.w.off
+	c.w.off = false
+	c.w.line('>', "2")
+	c.w.bulk("invalidate")
+	c.w.bulks(keys)
+	c.w.off = off
+	_ = c.w.flush()
+}
+
+// untrack stops tracking the keys of c.
+func (s *Server) untrack(c *conn) {
+	for key, conns := range s.tracked.conns {
+		delete(conns, c)
+		if len(conns) == 0 {
+			delete(s.tracked.conns, key)
+		}
+	}
+	c.invalidated = nil
+}
+
+// pushInvalidated writes the invalidation push notifications queued for c.
+func (c *conn) pushInvalidated() {
+	if len(c.invalidated) == 0 {
+		return
+	}
+	c.w.line('>', "2")
+	c.w.bulk("invalidate")
+	c.w.bulks(c.invalidated)
+	c.invalidated = nil
+}
@@ -0,0 +1,524 @@
+package redistest
+
+import (
+	"math"
+	"strings"
+)
+
+func newZSet() interface{} { return &zset{scores: make(map[string]float64)} }
+
+func (c *conn) getZSet(key string) (*zset, bool) {
+	e, ok := c.lookup(key, "zset")
+	if !ok || e == nil {
+		return nil, ok
+	}
+	return e.value.(*zset), true
+}
+
+// scoreBound is a bound of a score range such as "(1.5" or "-inf".
+type scoreBound struct {
+	score     float64
+	exclusive bool
+}
+
+func parseScoreBound(s string) (scoreBound, bool) {
+	var b scoreBound
+	if strings.HasPrefix(s, "(") {
+		b.exclusive = true
+		s = s[1:]
+	}
+	f, ok := parseFloat(s)
+	b.score = f
+	return b, ok
+}
+
+func (b scoreBound) above(score float64) bool {
+	return score > b.score || !b.exclusive && score == b.score
+}
+
+func (b scoreBound) below(score float64) bool {
+	return score < b.score || !b.exclusive && score == b.score
+}
+
+func (c *conn) writeZMembers(members []zmember, withScores bool) {
+	switch {
+	case !withScores:
+		c.w.array(len(members))
+		for _, m := range members {
+			c.w.bulk(m.member)
+		}
+	case c.w.proto == 3:
+		c.w.array(len(members))
+		for _, m := range members {
+			c.w.array(2)
+			c.w.bulk(m.member)
+			c.w.double(m.score)
+		}
+	default:
+		c.w.array(2 * len(members))
+		for _, m := range members {
+			c.w.bulk(m.member)
+			c.w.double(m.score)
+		}
+	}
+}
+
+func cmdZAdd(c *conn, args []string) {
+	var nx, xx, gt, lt, ch, incr bool
+	i := 2
+flags:
+	for ; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "gt":
+			gt = true
+		case "lt":
+			lt = true
+		case "ch":
+			ch = true
+		case "incr":
+			incr = true
+		default:
+			break flags
+		}
+	}
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		c.w.error(errSyntax)
+		return
+	}
+	if nx && xx {
+		c.w.error("ERR XX and NX options at the same time are not compatible")
+		return
+	}
+	if gt && lt || nx && (gt || lt) {
+		c.w.error("ERR GT, LT, and/or NX options at the same time are not compatible")
+		return
+	}
+	if incr && len(pairs) != 2 {
+		c.w.error("ERR INCR option supports a single increment-element pair")
+		return
+	}
+	scores := make([]float64, len(pairs)/2)
+	for j := range scores {
+		f, ok := parseFloat(pairs[2*j])
+		if !ok {
+			c.w.error(errNotFloat)
+			return
+		}
+		scores[j] = f
+	}
+
+	e, ok := c.lookupOrCreate(args[1], "zset", newZSet)
+	if !ok {
+		return
+	}
+	z := e.value.(*zset)
+	var added, changed int64
+	var last float64
+	var updated bool
+	for j, score := range scores {
+		member := pairs[2*j+1]
+		old, exists := z.scores[member]
+		if incr && exists {
+			score += old
+		}
+		switch {
+		case nx && exists, xx && !exists,
+			exists && gt && score <= old,
+			exists && lt && score >= old:
+			continue
+		}
+		z.scores[member] = score
+		last, updated = score, true
+		if !exists {
+			added++
+		} else if score != old {
+			changed++
+		}
+	}
+	d := c.db()
+	if updated {
+		d.touch(c.srv, args[1])
+	}
+	d.removeIfEmpty(c.srv, args[1])
+
+	switch {
+	case incr && !updated:
+		c.w.null()
+	case incr:
+		c.w.double(last)
+	case ch:
+		c.w.int(added + changed)
+	default:
+		c.w.int(added)
+	}
+}
+
+func cmdZIncrBy(c *conn, args []string) {
+	delta, ok := parseFloat(args[2])
+	if !ok {
+		c.w.error(errNotFloat)
+		return
+	}
+	e, ok := c.lookupOrCreate(args[1], "zset", newZSet)
+	if !ok {
+		return
+	}
+	z := e.value.(*zset)
+	score := z.scores[args[3]] + delta
+	if math.IsNaN(score) {
+		c.w.error("ERR resulting score is not a number (NaN)")
+		return
+	}
+	z.scores[args[3]] = score
+	c.db().touch(c.srv, args[1])
+	c.w.double(score)
+}
+
+func cmdZRem(c *conn, args []string) {
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	if z != nil {
+		for _, m := range args[2:] {
+			if _, ok := z.scores[m]; ok {
+				delete(z.scores, m)
+				n++
+			}
+		}
+	}
+	if n > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+	c.w.int(n)
+}
+
+func cmdZScore(c *conn, args []string) {
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	if z == nil {
+		c.w.null()
+		return
+	}
+	score, ok := z.scores[args[2]]
+	if !ok {
+		c.w.null()
+		return
+	}
+	c.w.double(score)
+}
+
+func cmdZMScore(c *conn, args []string) {
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	c.w.array(len(args) - 2)
+	for _, m := range args[2:] {
+		if z == nil {
+			c.w.null()
+			continue
+		}
+		if score, ok := z.scores[m]; ok {
+			c.w.double(score)
+		} else {
+			c.w.null()
+		}
+	}
+}
+
+func cmdZCard(c *conn, args []string) {
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	if z == nil {
+		c.w.int(0)
+		return
+	}
+	c.w.int(int64(len(z.scores)))
+}
+
+func cmdZCount(c *conn, args []string) {
+	min, ok1 := parseScoreBound(args[2])
+	max, ok2 := parseScoreBound(args[3])
+	if !ok1 || !ok2 {
+		c.w.error("ERR min or max is not a float")
+		return
+	}
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	if z != nil {
+		for _, score := range z.scores {
+			if min.above(score) && max.below(score) {
+				n++
+			}
+		}
+	}
+	c.w.int(n)
+}
+
+func cmdZRank(rev bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		z, ok := c.getZSet(args[1])
+		if !ok {
+			return
+		}
+		if z == nil {
+			c.w.null()
+			return
+		}
+		members := z.sorted()
+		for i, m := range members {
+			if m.member == args[2] {
+				if rev {
+					i = len(members) - 1 - i
+				}
+				c.w.int(int64(i))
+				return
+			}
+		}
+		c.w.null()
+	}
+}
+
+// cmdZRange implements ZRANGE key start stop [BYSCORE] [REV] [LIMIT offset count]
+// [WITHSCORES].
+func cmdZRange(c *conn, args []string) {
+	var byScore, rev, withScores, limit bool
+	var offset, count int64
+	for i := 4; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "byscore":
+			byScore = true
+		case "rev":
+			rev = true
+		case "withscores":
+			withScores = true
+		case "limit":
+			if i+2 >= len(args) {
+				c.w.error(errSyntax)
+				return
+			}
+			var ok1, ok2 bool
+			offset, ok1 = parseInt(args[i+1])
+			count, ok2 = parseInt(args[i+2])
+			if !ok1 || !ok2 {
+				c.w.error(errNotInt)
+				return
+			}
+			limit = true
+			i += 2
+		default:
+			c.w.error(errSyntax)
+			return
+		}
+	}
+	if limit && !byScore {
+		c.w.error("ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+		return
+	}
+
+	var members []zmember
+	var z *zset
+	var ok bool
+	if byScore {
+		minArg, maxArg := args[2], args[3]
+		if rev {
+			minArg, maxArg = maxArg, minArg
+		}
+		min, ok1 := parseScoreBound(minArg)
+		max, ok2 := parseScoreBound(maxArg)
+		if !ok1 || !ok2 {
+			c.w.error("ERR min or max is not a float")
+			return
+		}
+		if z, ok = c.getZSet(args[1]); !ok {
+			return
+		}
+		if z != nil {
+			for _, m := range z.sorted() {
+				if min.above(m.score) && max.below(m.score) {
+					members = append(members, m)
+				}
+			}
+		}
+		if rev {
+			reverse(members)
+		}
+		if limit {
+			members = limitMembers(members, offset, count)
+		}
+	} else {
+		start, ok1 := parseInt(args[2])
+		stop, ok2 := parseInt(args[3])
+		if !ok1 || !ok2 {
+			c.w.error(errNotInt)
+			return
+		}
+		if z, ok = c.getZSet(args[1]); !ok {
+			return
+		}
+		if z != nil {
+			all := z.sorted()
+			if rev {
+				reverse(all)
+			}
+			if lo, hi, ok := rangeIndexes(start, stop, len(all)); ok {
+				members = all[lo:hi]
+			}
+		}
+	}
+	c.writeZMembers(members, withScores)
+}
+
+// cmdZRangeCompat implements ZREVRANGE, ZRANGEBYSCORE and ZREVRANGEBYSCORE with
+// the equivalent ZRANGE.
+func cmdZRangeCompat(byScore, rev bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		zargs := append([]string{"zrange"}, args[1:4]...)
+		if byScore {
+			zargs = append(zargs, "byscore")
+		}
+		if rev {
+			zargs = append(zargs, "rev")
+		}
+		cmdZRange(c, append(zargs, args[4:]...))
+	}
+}
+
+func reverse(members []zmember) {
+	for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+		members[i], members[j] = members[j], members[i]
+	}
+}
+
+func limitMembers(members []zmember, offset, count int64) []zmember {
+	if offset < 0 || offset >= int64(len(members)) {
+		return nil
+	}
+	members = members[offset:]
+	if count >= 0 && count < int64(len(members)) {
+		members = members[:count]
+	}
+	return members
+}
+
+func cmdZRemRangeByScore(c *conn, args []string) {
+	min, ok1 := parseScoreBound(args[2])
+	max, ok2 := parseScoreBound(args[3])
+	if !ok1 || !ok2 {
+		c.w.error("ERR min or max is not a float")
+		return
+	}
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	if z != nil {
+		for m, score := range z.scores {
+			if min.above(score) && max.below(score) {
+				delete(z.scores, m)
+				n++
+			}
+		}
+	}
+	if n > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+	c.w.int(n)
+}
+
+func cmdZRemRangeByRank(c *conn, args []string) {
+	start, ok1 := parseInt(args[2])
+	stop, ok2 := parseInt(args[3])
+	if !ok1 || !ok2 {
+		c.w.error(errNotInt)
+		return
+	}
+	z, ok := c.getZSet(args[1])
+	if !ok {
+		return
+	}
+	var n int64
+	if z != nil {
+		all := z.sorted()
+		if lo, hi, ok := rangeIndexes(start, stop, len(all)); ok {
+			for _, m := range all[lo:hi] {
+				delete(z.scores, m.member)
+				n++
+			}
+		}
+	}
+	if n > 0 {
+		d := c.db()
+		d.touch(c.srv, args[1])
+		d.removeIfEmpty(c.srv, args[1])
+	}
+	c.w.int(n)
+}
+
+func cmdZPop(max bool) func(c *conn, args []string) {
+	return func(c *conn, args []string) {
+		count, withCount := int64(1), len(args) > 2
+		if withCount {
+			n, ok := parseInt(args[2])
+			if !ok || n < 0 || len(args) > 3 {
+				c.w.error("ERR value is out of range, must be positive")
+				return
+			}
+			count = n
+		}
+		z, ok := c.getZSet(args[1])
+		if !ok {
+			return
+		}
+
+		var popped []zmember
+		if z != nil {
+			all := z.sorted()
+			if max {
+				reverse(all)
+			}
+			if count < int64(len(all)) {
+				all = all[:count]
+			}
+			for _, m := range all {
+				delete(z.scores, m.member)
+			}
+			popped = all
+		}
+		if len(popped) > 0 {
+			d := c.db()
+			d.touch(c.srv, args[1])
+			d.removeIfEmpty(c.srv, args[1])
+		}
+
+		if withCount {
+			c.writeZMembers(popped, true)
+			return
+		}
+		// without count, RESP3 replies with a flat member and score
+		c.w.array(2 * len(popped))
+		for _, m := range popped {
+			c.w.bulk(m.member)
+			c.w.double(m.score)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+)
+
+// lookupSRV and lookupHost are the DNS lookups of Options.SRV and
+// Options.ResolveInterval, replaced by the tests.
+var (
+	lookupSRV  = net.DefaultResolver.LookupSRV
+	lookupHost = net.DefaultResolver.LookupHost
+)
+
+// errNoSRVRecords is returned when the domain of Options.SRV has no records.
+var errNoSRVRecords = errors.New("redis: no SRV records")
+
+// lookupSRVAddrs returns the host:port addresses of the targets of the SRV
+// records of opt.Addr, in the order of their priorities and weights.
+func (opt *Options) lookupSRVAddrs(ctx context.Context) ([]string, error) {
+	_, srvs, err := lookupSRV(ctx, "redis", "tcp", opt.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(srvs) == 0 {
+		return nil, fmt.Errorf("%w for %s", errNoSRVRecords, opt.Addr)
+	}
+	addrs := make([]string, len(srvs))
+	for i, srv := range srvs {
+		target := strings.TrimSuffix(srv.Target, ".")
+		addrs[i] = net.JoinHostPort(target, strconv.Itoa(int(srv.Port)))
+	}
+	return addrs, nil
+}
+
+// dialSRV dials the targets of the SRV records of opt.Addr in turn, until one
+// is connected.
+func dialSRV(
+	ctx context.Context,
+	opt *Options,
+	dialer func(ctx context.Context, network, addr string) (net.Conn, error),
+) (net.Conn, error) {
+	addrs, err := opt.lookupSRVAddrs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		var conn net.Conn
+		conn, err = dialer(ctx, opt.Network, addr)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+// resolvable reports whether opt.Addr has addresses to resolve, unlike an IP
+// address or a unix socket.
+func (opt *Options) resolvable() bool {
+	if opt.Network == "unix" {
+		return false
+	}
+	return opt.SRV || net.ParseIP(opt.host()) == nil
+}
+
+// host returns the host of opt.Addr.
+func (opt *Options) host() string {
+	host, _, err := net.SplitHostPort(opt.Addr)
+	if err != nil {
+		return opt.Addr
+	}
+	return host
+}
+
+// resolveAddr returns the sorted addresses of opt.Addr, the targets of its
+// SRV records or its IP addresses.
+func (opt *Options) resolveAddr(ctx context.Context) ([]string, error) {
+	var addrs []string
+	var err error
+	if opt.SRV {
+		addrs, err = opt.lookupSRVAddrs(ctx)
+	} else {
+		addrs, err = lookupHost(ctx, opt.host())
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(addrs)
+	return addrs, nil
+}
+
+// watchDNS resolves Options.Addr every Options.ResolveInterval and retires the
+// connections of the pool when its addresses changed.
+func (c *baseClient) watchDNS() {
+	if !c.opt.resolvable() {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	var once sync.Once
+	c.unwatchDNS = func() {
+		once.Do(func() { close(stop) })
+		<-done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(c.opt.ResolveInterval)
+		defer ticker.Stop()
+
+		last, _ := c.resolveAddr()
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			addrs, err := c.resolveAddr()
+			if err != nil {
+				internal.Log(context.Background(), c.opt.Logger, internal.LevelWarn,
+					"redis: resolving addr failed", "addr", c.opt.Addr, "error", err)
+				continue
+			}
+			if last != nil && !equalStrings(last, addrs) {
+				internal.Log(context.Background(), c.opt.Logger, internal.LevelInfo,
+					"redis: addr resolved to new addresses, retiring connections",
+					"addr", c.opt.Addr, "addrs", strings.Join(addrs, ","))
+				if p, ok := c.connPool.(*pool.ConnPool); ok {
+					p.RetireConns()
+				}
+			}
+			last = addrs
+		}
+	}()
+}
+
+// resolveAddr resolves Options.Addr within Options.DialTimeout.
+func (c *baseClient) resolveAddr() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opt.DialTimeout)
+	defer cancel()
+	return c.opt.resolveAddr(ctx)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
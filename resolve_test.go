@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDialSRV(t *testing.T) {
+	ctx := context.Background()
+	defer func(fn func(context.Context, string, string, string) (string, []*net.SRV, error)) {
+		lookupSRV = fn
+	}(lookupSRV)
+
+	var records []*net.SRV
+	lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		if service != "redis" || proto != "tcp" || name != "redis.example.com" {
+			t.Errorf("got lookup of %s %s %s", service, proto, name)
+		}
+		return "_redis._tcp." + name + ".", records, nil
+	}
+
+	var dialed []string
+	client := NewClient(&Options{
+		Addr:       "redis.example.com",
+		SRV:        true,
+		MaxRetries: -1,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dialed = append(dialed, addr)
+			return nil, errors.New("refused " + addr)
+		},
+	})
+	defer client.Close()
+
+	records = []*net.SRV{
+		{Target: "a.example.com.", Port: 6380},
+		{Target: "b.example.com.", Port: 6381},
+	}
+	err := client.Ping(ctx).Err()
+	if err == nil || err.Error() != "refused b.example.com:6381" {
+		t.Fatalf("got %v, wanted the error of the last target", err)
+	}
+	if len(dialed) != 2 || dialed[0] != "a.example.com:6380" || dialed[1] != "b.example.com:6381" {
+		t.Fatalf("dialed %q", dialed)
+	}
+
+	records = nil
+	if err := client.Ping(ctx).Err(); !errors.Is(err, errNoSRVRecords) {
+		t.Fatalf("got %v, wanted errNoSRVRecords", err)
+	}
+}
+
+func TestResolveInterval(t *testing.T) {
+	defer func(fn func(context.Context, string) ([]string, error)) {
+		lookupHost = fn
+	}(lookupHost)
+
+	var mu sync.Mutex
+	addrs := []string{"10.0.0.2", "10.0.0.1"}
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if host != "db.example.com" {
+			t.Errorf("got lookup of %s", host)
+		}
+		return append([]string(nil), addrs...), nil
+	}
+
+	var logger testLogger
+	client := NewClient(&Options{
+		Addr:            "db.example.com:6379",
+		ResolveInterval: 10 * time.Millisecond,
+		Logger:          &logger,
+	})
+	defer client.Close()
+
+	retired := func() int {
+		logger.mu.Lock()
+		defer logger.mu.Unlock()
+		var n int
+		for _, rec := range logger.records {
+			if rec.msg == "redis: addr resolved to new addresses, retiring connections" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// the same addresses in another order are not a change
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	addrs = []string{"10.0.0.1", "10.0.0.2"}
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	if n := retired(); n != 0 {
+		t.Fatalf("retired the connections %d times, wanted 0", n)
+	}
+
+	mu.Lock()
+	addrs = []string{"10.0.0.3"}
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	if n := retired(); n != 1 {
+		t.Fatalf("retired the connections %d times, wanted 1", n)
+	}
+
+	ipClient := NewClient(&Options{Addr: "127.0.0.1:6379", ResolveInterval: time.Millisecond})
+	defer ipClient.Close()
+	if ipClient.unwatchDNS != nil {
+		t.Fatal("resolving an IP address")
+	}
+}
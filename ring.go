@@ -70,6 +70,22 @@ type RingOptions struct {
 	Dialer    func(ctx context.Context, network, addr string) (net.Conn, error)
 	OnConnect func(ctx context.Context, cn *Conn) error
 
+	// OnConnectionEvent is called with the events of the connections, see
+	// Options.OnConnectionEvent.
+	OnConnectionEvent func(ev ConnEvent)
+	// OnConnectionUse is called with the events of the connections taken from
+	// the pools and given back to them, see Options.OnConnectionUse.
+	OnConnectionUse func(ev ConnEvent)
+	// Logger logs the messages of the clients, see Options.Logger.
+	Logger Logger
+	// CircuitBreaker enables a circuit breaker for each node, see
+	// Options.CircuitBreaker.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// OnPush is called with the push notifications received on the
+	// connections of each shard, see Options.OnPush.
+	OnPush func(ctx context.Context, msg PushMessage)
+
 	Protocol int
 	Username string
 	Password string
@@ -84,6 +100,31 @@ type RingOptions struct {
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
 
+	// StatsEnabled enables the statistics of the commands returned by Stats.
+	StatsEnabled bool
+
+	// AutoPipeline batches the commands issued concurrently into pipelines,
+	// see Options.AutoPipeline.
+	AutoPipeline bool
+	// AutoPipelineMaxSize is the maximum number of commands of the pipelines
+	// of AutoPipeline. Default is 100 commands.
+	AutoPipelineMaxSize int
+
+	// PipelineMaxCmds and PipelineMaxBytes split the pipelines in batches,
+	// see Options.PipelineMaxCmds.
+	PipelineMaxCmds  int
+	PipelineMaxBytes int
+
+	// HedgeDelay enables the hedging of the read commands on another
+	// connection of their node, see Options.HedgeDelay.
+	HedgeDelay time.Duration
+	// HedgeCommands are the names of the commands hedged with HedgeDelay.
+	HedgeCommands []string
+
+	// DryRun marshals and logs the commands with their shard instead of
+	// sending them, see Options.DryRun.
+	DryRun bool
+
 	// PoolFIFO uses FIFO mode for each node connection pool GET/PUT (default LIFO).
 	PoolFIFO bool
 
@@ -94,12 +135,24 @@ type RingOptions struct {
 	MaxActiveConns  int
 	ConnMaxIdleTime time.Duration
 	ConnMaxLifetime time.Duration
+	ReadBufferSize  int
+	WriteBufferSize int
+	StringInterner  StringInterner
+
+	// AdaptivePoolSize adjusts the size of the pools to their load, see
+	// Options.AdaptivePoolSize.
+	AdaptivePoolSize bool
 
-	TLSConfig *tls.Config
-	Limiter   Limiter
+	TLSConfig           *tls.Config
+	TLSFiles            *TLSFiles
+	TLSVerifyConnection func(cs tls.ConnectionState) error
+	TLSServerSPIFFEIDs  []string
+	Limiter             Limiter
 
 	DisableIndentity bool
 	IdentitySuffix   string
+
+	JSONCodec JSONCodec
 }
 
 func (opt *RingOptions) init() {
@@ -138,9 +191,14 @@ func (opt *RingOptions) init() {
 
 func (opt *RingOptions) clientOptions() *Options {
 	return &Options{
-		ClientName: opt.ClientName,
-		Dialer:     opt.Dialer,
-		OnConnect:  opt.OnConnect,
+		ClientName:        opt.ClientName,
+		Dialer:            opt.Dialer,
+		OnConnect:         opt.OnConnect,
+		OnConnectionEvent: opt.OnConnectionEvent,
+		OnConnectionUse:   opt.OnConnectionUse,
+		Logger:            opt.Logger,
+		CircuitBreaker:    opt.CircuitBreaker,
+		OnPush:            opt.OnPush,
 
 		Protocol: opt.Protocol,
 		Username: opt.Username,
@@ -153,6 +211,14 @@ func (opt *RingOptions) clientOptions() *Options {
 		ReadTimeout:           opt.ReadTimeout,
 		WriteTimeout:          opt.WriteTimeout,
 		ContextTimeoutEnabled: opt.ContextTimeoutEnabled,
+		StatsEnabled:          opt.StatsEnabled,
+		AutoPipeline:          opt.AutoPipeline,
+		AutoPipelineMaxSize:   opt.AutoPipelineMaxSize,
+		PipelineMaxCmds:       opt.PipelineMaxCmds,
+		PipelineMaxBytes:      opt.PipelineMaxBytes,
+		HedgeDelay:            opt.HedgeDelay,
+		HedgeCommands:         opt.HedgeCommands,
+		DryRun:                opt.DryRun,
 
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
@@ -162,12 +228,22 @@ func (opt *RingOptions) clientOptions() *Options {
 		MaxActiveConns:  opt.MaxActiveConns,
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
+		ReadBufferSize:  opt.ReadBufferSize,
+		WriteBufferSize: opt.WriteBufferSize,
+		StringInterner:  opt.StringInterner,
 
-		TLSConfig: opt.TLSConfig,
-		Limiter:   opt.Limiter,
+		AdaptivePoolSize: opt.AdaptivePoolSize,
+
+		TLSConfig:           opt.TLSConfig,
+		TLSFiles:            opt.TLSFiles,
+		TLSVerifyConnection: opt.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  opt.TLSServerSPIFFEIDs,
+		Limiter:             opt.Limiter,
 
 		DisableIndentity: opt.DisableIndentity,
 		IdentitySuffix:   opt.IdentitySuffix,
+
+		JSONCodec: opt.JSONCodec,
 	}
 }
 
@@ -271,7 +347,8 @@ func (c *ringSharding) SetAddrs(addrs map[string]string) {
 	cleanup := func(shards map[string]*ringShard) {
 		for addr, shard := range shards {
 			if err := shard.Client.Close(); err != nil {
-				internal.Logger.Printf(context.Background(), "shard.Close %s failed: %s", addr, err)
+				internal.Log(context.Background(), c.opt.Logger, internal.LevelWarn, "redis: closing ring shard failed",
+					"addr", addr, "error", err)
 			}
 		}
 	}
@@ -411,7 +488,7 @@ func (c *ringSharding) Heartbeat(ctx context.Context, frequency time.Duration) {
 				err := shard.Client.Ping(ctx).Err()
 				isUp := err == nil || err == pool.ErrPoolTimeout
 				if shard.Vote(isUp) {
-					internal.Logger.Printf(ctx, "ring shard state changed: %s", shard)
+					internal.Log(ctx, c.opt.Logger, internal.LevelInfo, "redis: ring shard state changed", "shard", shard)
 					rebalance = true
 				}
 			}
@@ -570,6 +647,9 @@ func (c *Ring) PoolStats() *PoolStats {
 		acc.Hits += s.Hits
 		acc.Misses += s.Misses
 		acc.Timeouts += s.Timeouts
+		acc.WaitCount += s.WaitCount
+		acc.WaitDurationNs += s.WaitDurationNs
+		acc.PoolSize += s.PoolSize
 		acc.TotalConns += s.TotalConns
 		acc.IdleConns += s.IdleConns
 	}
@@ -0,0 +1,78 @@
+package redis_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestRouteReadsFunc(t *testing.T) {
+	// the redistest servers do not support COMMAND
+	redis.RegisterReadOnlyCommands("get")
+	defer redis.UnregisterReadOnlyCommands("get")
+
+	var nodes []redis.ClusterNode
+	for _, az := range []string{"a", "b", "c"} {
+		srv := redistest.NewServer()
+		defer srv.Close()
+
+		// each node has a different value, as the servers do not replicate
+		rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+		if err := rdb.Set(ctx, "key", az, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+		_ = rdb.Close()
+
+		nodes = append(nodes, redis.ClusterNode{
+			Addr:               srv.Addr(),
+			NetworkingMetadata: map[string]string{"az": az},
+		})
+	}
+
+	var mu sync.Mutex
+	var got []redis.NodeInfo
+	az := "c"
+	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{{Start: 0, End: 16383, Nodes: nodes}}, nil
+		},
+		RouteReadsFunc: func(nodes []redis.NodeInfo) redis.NodeInfo {
+			mu.Lock()
+			defer mu.Unlock()
+			got = nodes
+			for _, node := range nodes {
+				if node.Labels["az"] == az {
+					return node
+				}
+			}
+			return redis.NodeInfo{}
+		},
+	})
+	defer rdb.Close()
+
+	if val, err := rdb.Get(ctx, "key").Result(); err != nil || val != "c" {
+		t.Fatalf("got %q, %v, wanted the value of the node of the az c", val, err)
+	}
+	if len(got) != 3 || !got[0].Master || got[1].Master || got[1].Addr != nodes[1].Addr || got[2].Labels["az"] != "c" {
+		t.Fatalf("got nodes %+v", got)
+	}
+
+	// the writes are not routed
+	if err := rdb.Set(ctx, "key", "x", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if val := rdb.Get(ctx, "key").Val(); val != "c" {
+		t.Fatalf("got %q, wanted c", val)
+	}
+
+	// the commands are routed to the master without node
+	mu.Lock()
+	az = "d"
+	mu.Unlock()
+	if val := rdb.Get(ctx, "key").Val(); val != "x" {
+		t.Fatalf("got %q, wanted the value of the master", val)
+	}
+}
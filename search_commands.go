@@ -2,10 +2,14 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/hscan"
 	"github.com/redis/go-redis/v9/internal/proto"
 )
 
@@ -13,6 +17,7 @@ type SearchCmdable interface {
 	FT_List(ctx context.Context) *StringSliceCmd
 	FTAggregate(ctx context.Context, index string, query string) *MapStringInterfaceCmd
 	FTAggregateWithArgs(ctx context.Context, index string, query string, options *FTAggregateOptions) *AggregateCmd
+	FTAggregateWithParams(ctx context.Context, index string, query string, params map[string]interface{}, options *FTAggregateOptions) *AggregateCmd
 	FTAliasAdd(ctx context.Context, index string, alias string) *StatusCmd
 	FTAliasDel(ctx context.Context, alias string) *StatusCmd
 	FTAliasUpdate(ctx context.Context, index string, alias string) *StatusCmd
@@ -30,14 +35,23 @@ type SearchCmdable interface {
 	FTExplain(ctx context.Context, index string, query string) *StringCmd
 	FTExplainWithArgs(ctx context.Context, index string, query string, options *FTExplainOptions) *StringCmd
 	FTInfo(ctx context.Context, index string) *FTInfoCmd
+	FTProfile(ctx context.Context, index string, limited bool, query interface{}) *FTProfileCmd
 	FTSpellCheck(ctx context.Context, index string, query string) *FTSpellCheckCmd
 	FTSpellCheckWithArgs(ctx context.Context, index string, query string, options *FTSpellCheckOptions) *FTSpellCheckCmd
 	FTSearch(ctx context.Context, index string, query string) *FTSearchCmd
 	FTSearchWithArgs(ctx context.Context, index string, query string, options *FTSearchOptions) *FTSearchCmd
+	FTSearchWithParams(ctx context.Context, index string, query string, params map[string]interface{}, options *FTSearchOptions) *FTSearchCmd
+	FTSugAdd(ctx context.Context, key string, str string, score float64) *IntCmd
+	FTSugAddWithArgs(ctx context.Context, key string, str string, score float64, options *FTSugAddOptions) *IntCmd
+	FTSugDel(ctx context.Context, key string, str string) *BoolCmd
+	FTSugGet(ctx context.Context, key string, prefix string) *StringSliceCmd
+	FTSugGetWithArgs(ctx context.Context, key string, prefix string, options *FTSugGetOptions) *FTSuggestionSliceCmd
+	FTSugLen(ctx context.Context, key string) *IntCmd
 	FTSynDump(ctx context.Context, index string) *FTSynDumpCmd
 	FTSynUpdate(ctx context.Context, index string, synGroupId interface{}, terms []interface{}) *StatusCmd
 	FTSynUpdateWithArgs(ctx context.Context, index string, synGroupId interface{}, options *FTSynUpdateOptions, terms []interface{}) *StatusCmd
 	FTTagVals(ctx context.Context, index string, field string) *StringSliceCmd
+	FTVectorSearch(ctx context.Context, index string, q *FTVectorQuery) *FTVectorSearchCmd
 }
 
 type FTCreateOptions struct {
@@ -280,6 +294,29 @@ type FTSearchSortBy struct {
 	Desc      bool
 }
 
+// FTSearchSummarize returns fragments of the text fields around the matches
+// instead of their whole values, parsed into Document.Fragments.
+type FTSearchSummarize struct {
+	// Fields are the summarized fields. Default is all the returned text fields.
+	Fields []string
+	// Frags is the number of fragments returned per field. Default is 3.
+	Frags int
+	// Len is the number of words of the fragments. Default is 20.
+	Len int
+	// Separator separates the fragments. Default is "... ".
+	Separator string
+}
+
+// FTSearchHighlight wraps the matches in the text fields with tags. Their
+// offsets are parsed into Document.Fragments.
+type FTSearchHighlight struct {
+	// Fields are the highlighted fields. Default is all the returned text fields.
+	Fields []string
+	// OpenTag and CloseTag wrap the matches. Default is "<b>" and "</b>".
+	OpenTag  string
+	CloseTag string
+}
+
 type FTSearchOptions struct {
 	NoContent       bool
 	Verbatim        bool
@@ -292,6 +329,8 @@ type FTSearchOptions struct {
 	InKeys          []interface{}
 	InFields        []interface{}
 	Return          []FTSearchReturn
+	Summarize       *FTSearchSummarize
+	Highlight       *FTSearchHighlight
 	Slop            int
 	Timeout         int
 	InOrder         bool
@@ -321,6 +360,10 @@ type FTSynDumpCmd struct {
 type FTAggregateResult struct {
 	Total int
 	Rows  []AggregateRow
+
+	// CursorID is the cursor to read the next rows from when WithCursor was requested.
+	// It is 0 once all the rows have been read.
+	CursorID int
 }
 
 type AggregateRow struct {
@@ -330,6 +373,8 @@ type AggregateRow struct {
 type AggregateCmd struct {
 	baseCmd
 	val *FTAggregateResult
+
+	process cmdable
 }
 
 type FTInfoResult struct {
@@ -451,6 +496,47 @@ type Document struct {
 	Payload *string
 	SortKey *string
 	Fields  map[string]string
+	// Fragments are the summarized or highlighted fields, parsed
+	// from Fields when FTSearchOptions.Summarize or Highlight is set.
+	Fragments map[string][]FTFragment
+}
+
+// Scan copies the document into the struct pointed to by dst.
+//
+// The fields of HASH documents are matched with the `redis` struct tags.
+// The "$" field holding a whole JSON document is unmarshaled with encoding/json,
+// so the `json` tags apply, and the other fields, e.g. those selected with RETURN,
+// are matched with the `redis` tags. The document key and score are scanned into
+// the fields tagged `redis:"__key"` and `redis:"__score"`.
+func (d *Document) Scan(dst interface{}) error {
+	if doc, ok := d.Fields["$"]; ok {
+		if err := json.Unmarshal([]byte(doc), dst); err != nil {
+			return err
+		}
+	}
+
+	strct, err := hscan.Struct(dst)
+	if err != nil {
+		return err
+	}
+
+	if err := strct.Scan("__key", d.ID); err != nil {
+		return err
+	}
+	if d.Score != nil {
+		if err := strct.Scan("__score", strconv.FormatFloat(*d.Score, 'f', -1, 64)); err != nil {
+			return err
+		}
+	}
+	for k, v := range d.Fields {
+		if k == "$" {
+			continue
+		}
+		if err := strct.Scan(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type AggregateQuery []interface{}
@@ -646,9 +732,138 @@ func (cmd *AggregateCmd) readReply(rd *proto.Reader) (err error) {
 		cmd.err = err
 		return nil
 	}
+
+	// Replies with a cursor are [result, cursor id].
+	cursorID := int64(-1)
+	if len(data) == 2 {
+		if page, ok := data[0].([]interface{}); ok {
+			if cursorID, ok = data[1].(int64); !ok {
+				cmd.err = fmt.Errorf("invalid cursor id format")
+				return nil
+			}
+			data = page
+		}
+	}
+
 	cmd.val, err = ProcessAggregateResult(data)
 	if err != nil {
 		cmd.err = err
+		return nil
+	}
+	if cursorID > 0 {
+		cmd.val.CursorID = int(cursorID)
+	}
+	return nil
+}
+
+// Iterator returns an iterator over the rows of the FT.AGGREGATE result,
+// which transparently reads the next rows with FT.CURSOR READ when the
+// command was issued by FTAggregateWithArgs with WithCursor.
+func (cmd *AggregateCmd) Iterator() *FTAggregateIterator {
+	it := &FTAggregateIterator{
+		process: cmd.process,
+		index:   cmd.stringArg(1),
+		err:     cmd.err,
+	}
+	if cmd.val != nil {
+		it.rows = cmd.val.Rows
+		it.cursorID = cmd.val.CursorID
+	}
+	if it.process == nil && it.err == nil && it.cursorID != 0 {
+		it.err = fmt.Errorf("redis: AggregateCmd.Iterator requires a command issued by FTAggregateWithArgs")
+	}
+	return it
+}
+
+// FTAggregateIterator is used to iterate over the rows of FT.AGGREGATE,
+// following its cursor until the rows are exhausted.
+type FTAggregateIterator struct {
+	process  cmdable
+	index    string
+	cursorID int
+
+	rows []AggregateRow
+	pos  int
+	err  error
+}
+
+// Err returns the last iterator error, if any.
+func (it *FTAggregateIterator) Err() error {
+	return it.err
+}
+
+// Next advances to the next row, reading the next batch of rows
+// from the cursor when needed, and returns true if a row can be read.
+func (it *FTAggregateIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.pos < len(it.rows) {
+		it.pos++
+		return true
+	}
+
+	for it.cursorID != 0 {
+		cmd := NewAggregateCmd(ctx, "FT.CURSOR", "READ", it.index, it.cursorID)
+		if err := it.process(ctx, cmd); err != nil {
+			it.err = err
+			return false
+		}
+
+		it.rows = cmd.val.Rows
+		it.cursorID = cmd.val.CursorID
+		it.pos = 1
+
+		// The cursor may return an empty batch.
+		if len(it.rows) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Val returns the current row.
+func (it *FTAggregateIterator) Val() AggregateRow {
+	if it.err == nil && it.pos > 0 && it.pos <= len(it.rows) {
+		return it.rows[it.pos-1]
+	}
+	return AggregateRow{}
+}
+
+// Close ends the iteration and deletes the cursor with FT.CURSOR DEL unless all
+// the rows have been read. It must be called when the iteration is stopped early,
+// so the cursor does not linger on the server until its MAXIDLE timeout.
+func (it *FTAggregateIterator) Close(ctx context.Context) error {
+	if it.cursorID == 0 || it.process == nil {
+		return nil
+	}
+	cursorID := it.cursorID
+	it.cursorID = 0
+	it.rows = nil
+	return it.process.FTCursorDel(ctx, it.index, cursorID).Err()
+}
+
+// Scan copies the fields of the row into the struct pointed to by dst,
+// matching them with the `redis` struct tags.
+func (r AggregateRow) Scan(dst interface{}) error {
+	strct, err := hscan.Struct(dst)
+	if err != nil {
+		return err
+	}
+	for k, v := range r.Fields {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case nil:
+			continue
+		default:
+			s = fmt.Sprint(v)
+		}
+		if err := strct.Scan(k, s); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -761,6 +976,7 @@ func (c cmdable) FTAggregateWithArgs(ctx context.Context, index string, query st
 	}
 
 	cmd := NewAggregateCmd(ctx, args...)
+	cmd.process = c
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -906,106 +1122,112 @@ func (c cmdable) FTCreate(ctx context.Context, index string, options *FTCreateOp
 	}
 	args = append(args, "SCHEMA")
 	for _, schema := range schema {
-		if schema.FieldName == "" || schema.FieldType == SearchFieldTypeInvalid {
-			panic("FT.CREATE: SCHEMA FieldName and FieldType are required")
-		}
-		args = append(args, schema.FieldName)
-		if schema.As != "" {
-			args = append(args, "AS", schema.As)
-		}
-		args = append(args, schema.FieldType.String())
-		if schema.VectorArgs != nil {
-			if schema.FieldType != SearchFieldTypeVector {
-				panic("FT.CREATE: SCHEMA FieldType VECTOR is required for VectorArgs")
+		args = appendFieldSchemaArgs(args, schema)
+	}
+	cmd := NewStatusCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// appendFieldSchemaArgs appends the FT.CREATE and FT.ALTER arguments of the field.
+func appendFieldSchemaArgs(args []interface{}, schema *FieldSchema) []interface{} {
+	if schema.FieldName == "" || schema.FieldType == SearchFieldTypeInvalid {
+		panic("FT.CREATE: SCHEMA FieldName and FieldType are required")
+	}
+	args = append(args, schema.FieldName)
+	if schema.As != "" {
+		args = append(args, "AS", schema.As)
+	}
+	args = append(args, schema.FieldType.String())
+	if schema.VectorArgs != nil {
+		if schema.FieldType != SearchFieldTypeVector {
+			panic("FT.CREATE: SCHEMA FieldType VECTOR is required for VectorArgs")
+		}
+		if schema.VectorArgs.FlatOptions != nil && schema.VectorArgs.HNSWOptions != nil {
+			panic("FT.CREATE: SCHEMA VectorArgs FlatOptions and HNSWOptions are mutually exclusive")
+		}
+		if schema.VectorArgs.FlatOptions != nil {
+			args = append(args, "FLAT")
+			if schema.VectorArgs.FlatOptions.Type == "" || schema.VectorArgs.FlatOptions.Dim == 0 || schema.VectorArgs.FlatOptions.DistanceMetric == "" {
+				panic("FT.CREATE: Type, Dim and DistanceMetric are required for VECTOR FLAT")
 			}
-			if schema.VectorArgs.FlatOptions != nil && schema.VectorArgs.HNSWOptions != nil {
-				panic("FT.CREATE: SCHEMA VectorArgs FlatOptions and HNSWOptions are mutually exclusive")
+			flatArgs := []interface{}{
+				"TYPE", schema.VectorArgs.FlatOptions.Type,
+				"DIM", schema.VectorArgs.FlatOptions.Dim,
+				"DISTANCE_METRIC", schema.VectorArgs.FlatOptions.DistanceMetric,
 			}
-			if schema.VectorArgs.FlatOptions != nil {
-				args = append(args, "FLAT")
-				if schema.VectorArgs.FlatOptions.Type == "" || schema.VectorArgs.FlatOptions.Dim == 0 || schema.VectorArgs.FlatOptions.DistanceMetric == "" {
-					panic("FT.CREATE: Type, Dim and DistanceMetric are required for VECTOR FLAT")
-				}
-				flatArgs := []interface{}{
-					"TYPE", schema.VectorArgs.FlatOptions.Type,
-					"DIM", schema.VectorArgs.FlatOptions.Dim,
-					"DISTANCE_METRIC", schema.VectorArgs.FlatOptions.DistanceMetric,
-				}
-				if schema.VectorArgs.FlatOptions.InitialCapacity > 0 {
-					flatArgs = append(flatArgs, "INITIAL_CAP", schema.VectorArgs.FlatOptions.InitialCapacity)
-				}
-				if schema.VectorArgs.FlatOptions.BlockSize > 0 {
-					flatArgs = append(flatArgs, "BLOCK_SIZE", schema.VectorArgs.FlatOptions.BlockSize)
-				}
-				args = append(args, len(flatArgs))
-				args = append(args, flatArgs...)
+			if schema.VectorArgs.FlatOptions.InitialCapacity > 0 {
+				flatArgs = append(flatArgs, "INITIAL_CAP", schema.VectorArgs.FlatOptions.InitialCapacity)
 			}
-			if schema.VectorArgs.HNSWOptions != nil {
-				args = append(args, "HNSW")
-				if schema.VectorArgs.HNSWOptions.Type == "" || schema.VectorArgs.HNSWOptions.Dim == 0 || schema.VectorArgs.HNSWOptions.DistanceMetric == "" {
-					panic("FT.CREATE: Type, Dim and DistanceMetric are required for VECTOR HNSW")
-				}
-				hnswArgs := []interface{}{
-					"TYPE", schema.VectorArgs.HNSWOptions.Type,
-					"DIM", schema.VectorArgs.HNSWOptions.Dim,
-					"DISTANCE_METRIC", schema.VectorArgs.HNSWOptions.DistanceMetric,
-				}
-				if schema.VectorArgs.HNSWOptions.InitialCapacity > 0 {
-					hnswArgs = append(hnswArgs, "INITIAL_CAP", schema.VectorArgs.HNSWOptions.InitialCapacity)
-				}
-				if schema.VectorArgs.HNSWOptions.MaxEdgesPerNode > 0 {
-					hnswArgs = append(hnswArgs, "M", schema.VectorArgs.HNSWOptions.MaxEdgesPerNode)
-				}
-				if schema.VectorArgs.HNSWOptions.MaxAllowedEdgesPerNode > 0 {
-					hnswArgs = append(hnswArgs, "EF_CONSTRUCTION", schema.VectorArgs.HNSWOptions.MaxAllowedEdgesPerNode)
-				}
-				if schema.VectorArgs.HNSWOptions.EFRunTime > 0 {
-					hnswArgs = append(hnswArgs, "EF_RUNTIME", schema.VectorArgs.HNSWOptions.EFRunTime)
-				}
-				if schema.VectorArgs.HNSWOptions.Epsilon > 0 {
-					hnswArgs = append(hnswArgs, "EPSILON", schema.VectorArgs.HNSWOptions.Epsilon)
-				}
-				args = append(args, len(hnswArgs))
-				args = append(args, hnswArgs...)
+			if schema.VectorArgs.FlatOptions.BlockSize > 0 {
+				flatArgs = append(flatArgs, "BLOCK_SIZE", schema.VectorArgs.FlatOptions.BlockSize)
 			}
+			args = append(args, len(flatArgs))
+			args = append(args, flatArgs...)
 		}
-		if schema.GeoShapeFieldType != "" {
-			if schema.FieldType != SearchFieldTypeGeoShape {
-				panic("FT.CREATE: SCHEMA FieldType GEOSHAPE is required for GeoShapeFieldType")
+		if schema.VectorArgs.HNSWOptions != nil {
+			args = append(args, "HNSW")
+			if schema.VectorArgs.HNSWOptions.Type == "" || schema.VectorArgs.HNSWOptions.Dim == 0 || schema.VectorArgs.HNSWOptions.DistanceMetric == "" {
+				panic("FT.CREATE: Type, Dim and DistanceMetric are required for VECTOR HNSW")
 			}
-			args = append(args, schema.GeoShapeFieldType)
-		}
-		if schema.NoStem {
-			args = append(args, "NOSTEM")
-		}
-		if schema.Sortable {
-			args = append(args, "SORTABLE")
-		}
-		if schema.UNF {
-			args = append(args, "UNF")
-		}
-		if schema.NoIndex {
-			args = append(args, "NOINDEX")
-		}
-		if schema.PhoneticMatcher != "" {
-			args = append(args, "PHONETIC", schema.PhoneticMatcher)
-		}
-		if schema.Weight > 0 {
-			args = append(args, "WEIGHT", schema.Weight)
-		}
-		if schema.Seperator != "" {
-			args = append(args, "SEPERATOR", schema.Seperator)
-		}
-		if schema.CaseSensitive {
-			args = append(args, "CASESENSITIVE")
+			hnswArgs := []interface{}{
+				"TYPE", schema.VectorArgs.HNSWOptions.Type,
+				"DIM", schema.VectorArgs.HNSWOptions.Dim,
+				"DISTANCE_METRIC", schema.VectorArgs.HNSWOptions.DistanceMetric,
+			}
+			if schema.VectorArgs.HNSWOptions.InitialCapacity > 0 {
+				hnswArgs = append(hnswArgs, "INITIAL_CAP", schema.VectorArgs.HNSWOptions.InitialCapacity)
+			}
+			if schema.VectorArgs.HNSWOptions.MaxEdgesPerNode > 0 {
+				hnswArgs = append(hnswArgs, "M", schema.VectorArgs.HNSWOptions.MaxEdgesPerNode)
+			}
+			if schema.VectorArgs.HNSWOptions.MaxAllowedEdgesPerNode > 0 {
+				hnswArgs = append(hnswArgs, "EF_CONSTRUCTION", schema.VectorArgs.HNSWOptions.MaxAllowedEdgesPerNode)
+			}
+			if schema.VectorArgs.HNSWOptions.EFRunTime > 0 {
+				hnswArgs = append(hnswArgs, "EF_RUNTIME", schema.VectorArgs.HNSWOptions.EFRunTime)
+			}
+			if schema.VectorArgs.HNSWOptions.Epsilon > 0 {
+				hnswArgs = append(hnswArgs, "EPSILON", schema.VectorArgs.HNSWOptions.Epsilon)
+			}
+			args = append(args, len(hnswArgs))
+			args = append(args, hnswArgs...)
 		}
-		if schema.WithSuffixtrie {
-			args = append(args, "WITHSUFFIXTRIE")
+	}
+	if schema.GeoShapeFieldType != "" {
+		if schema.FieldType != SearchFieldTypeGeoShape {
+			panic("FT.CREATE: SCHEMA FieldType GEOSHAPE is required for GeoShapeFieldType")
 		}
+		args = append(args, schema.GeoShapeFieldType)
 	}
-	cmd := NewStatusCmd(ctx, args...)
-	_ = c(ctx, cmd)
-	return cmd
+	if schema.NoStem {
+		args = append(args, "NOSTEM")
+	}
+	if schema.Sortable {
+		args = append(args, "SORTABLE")
+	}
+	if schema.UNF {
+		args = append(args, "UNF")
+	}
+	if schema.NoIndex {
+		args = append(args, "NOINDEX")
+	}
+	if schema.PhoneticMatcher != "" {
+		args = append(args, "PHONETIC", schema.PhoneticMatcher)
+	}
+	if schema.Weight > 0 {
+		args = append(args, "WEIGHT", schema.Weight)
+	}
+	if schema.Seperator != "" {
+		args = append(args, "SEPERATOR", schema.Seperator)
+	}
+	if schema.CaseSensitive {
+		args = append(args, "CASESENSITIVE")
+	}
+	if schema.WithSuffixtrie {
+		args = append(args, "WITHSUFFIXTRIE")
+	}
+	return args
 }
 
 // FTCursorDel - Deletes a cursor from an existing index.
@@ -1506,6 +1728,112 @@ func parseFTSpellCheck(data []interface{}) ([]SpellCheckResult, error) {
 	return results, nil
 }
 
+// FTFragment is a fragment of a summarized or highlighted field.
+type FTFragment struct {
+	// Text of the fragment without the highlighting tags.
+	Text string
+	// Highlights are the [start, end) byte offsets of the matches in Text.
+	Highlights [][2]int
+}
+
+func (hl *FTSearchHighlight) tags() (open, close string) {
+	open, close = "<b>", "</b>"
+	if hl.OpenTag != "" {
+		open = hl.OpenTag
+	}
+	if hl.CloseTag != "" {
+		close = hl.CloseTag
+	}
+	return open, close
+}
+
+// searchFragments parses the summarized or highlighted fields of a document.
+// Fields are parsed when they are listed in the options or, when all the
+// fields are summarized or highlighted, when they hold fragments.
+func searchFragments(fields map[string]string, options *FTSearchOptions) map[string][]FTFragment {
+	sum, hl := options.Summarize, options.Highlight
+	sep := "... "
+	if sum != nil && sum.Separator != "" {
+		sep = sum.Separator
+	}
+	var open, close string
+	if hl != nil {
+		open, close = hl.tags()
+	}
+
+	all := (sum != nil && len(sum.Fields) == 0) || (hl != nil && len(hl.Fields) == 0)
+	listed := make(map[string]bool)
+	if sum != nil {
+		for _, field := range sum.Fields {
+			listed[field] = true
+		}
+	}
+	if hl != nil {
+		for _, field := range hl.Fields {
+			listed[field] = true
+		}
+	}
+
+	var frags map[string][]FTFragment
+	for name, value := range fields {
+		if !listed[name] {
+			if !all || name == "$" {
+				continue
+			}
+			if !(sum != nil && strings.Contains(value, sep)) && !(hl != nil && strings.Contains(value, open)) {
+				continue
+			}
+		}
+
+		texts := []string{value}
+		if sum != nil {
+			texts = texts[:0]
+			for _, text := range strings.Split(value, sep) {
+				if strings.TrimSpace(text) != "" {
+					texts = append(texts, text)
+				}
+			}
+		}
+		fieldFrags := make([]FTFragment, 0, len(texts))
+		for _, text := range texts {
+			frag := FTFragment{Text: text}
+			if hl != nil {
+				frag = parseHighlights(text, open, close)
+			}
+			fieldFrags = append(fieldFrags, frag)
+		}
+		if frags == nil {
+			frags = make(map[string][]FTFragment)
+		}
+		frags[name] = fieldFrags
+	}
+	return frags
+}
+
+// parseHighlights strips the highlighting tags of text, recording the offsets
+// of the matches they wrapped.
+func parseHighlights(text, open, close string) FTFragment {
+	var b strings.Builder
+	var highlights [][2]int
+	for {
+		i := strings.Index(text, open)
+		if i < 0 {
+			break
+		}
+		j := strings.Index(text[i+len(open):], close)
+		if j < 0 {
+			break
+		}
+		b.WriteString(text[:i])
+		start := b.Len()
+		b.WriteString(text[i+len(open) : i+len(open)+j])
+		highlights = append(highlights, [2]int{start, b.Len()})
+		text = text[i+len(open)+j+len(close):]
+	}
+	b.WriteString(text)
+	return FTFragment{Text: b.String(), Highlights: highlights}
+}
+
 func parseFTSearch(data []interface{}, noContent, withScores, withPayloads, withSortKeys bool) (FTSearchResult, error) {
 	if len(data) < 1 {
 		return FTSearchResult{}, fmt.Errorf("unexpected search result format")
@@ -1619,6 +1947,44 @@ func (cmd *FTSearchCmd) Val() FTSearchResult {
 	return cmd.val
 }
 
+// Scan copies the returned documents into the slice pointed to by dst,
+// whose elements are structs or pointers to structs. See Document.Scan.
+func (cmd *FTSearchCmd) Scan(dst interface{}) error {
+	if cmd.err != nil {
+		return cmd.err
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("redis: FTSearchCmd.Scan(non-slice-pointer %T)", dst)
+	}
+	v = v.Elem()
+
+	elemType := v.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("redis: FTSearchCmd.Scan(non-struct slice %T)", dst)
+	}
+
+	slice := reflect.MakeSlice(v.Type(), len(cmd.val.Docs), len(cmd.val.Docs))
+	for i := range cmd.val.Docs {
+		elem := slice.Index(i)
+		if isPtr {
+			elem.Set(reflect.New(elemType))
+		} else {
+			elem = elem.Addr()
+		}
+		if err := cmd.val.Docs[i].Scan(elem.Interface()); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
 func (cmd *FTSearchCmd) readReply(rd *proto.Reader) (err error) {
 	data, err := rd.ReadSlice()
 	if err != nil {
@@ -1628,6 +1994,12 @@ func (cmd *FTSearchCmd) readReply(rd *proto.Reader) (err error) {
 	cmd.val, err = parseFTSearch(data, cmd.options.NoContent, cmd.options.WithScores, cmd.options.WithPayloads, cmd.options.WithSortKeys)
 	if err != nil {
 		cmd.err = err
+		return nil
+	}
+	if cmd.options.Summarize != nil || cmd.options.Highlight != nil {
+		for i := range cmd.val.Docs {
+			cmd.val.Docs[i].Fragments = searchFragments(cmd.val.Docs[i].Fields, cmd.options)
+		}
 	}
 	return nil
 }
@@ -1643,6 +2015,41 @@ func (c cmdable) FTSearch(ctx context.Context, index string, query string) *FTSe
 	return cmd
 }
 
+func appendSummarizeHighlightArgs(args []interface{}, options *FTSearchOptions) []interface{} {
+	if sum := options.Summarize; sum != nil {
+		args = append(args, "SUMMARIZE")
+		if len(sum.Fields) > 0 {
+			args = append(args, "FIELDS", len(sum.Fields))
+			for _, field := range sum.Fields {
+				args = append(args, field)
+			}
+		}
+		if sum.Frags > 0 {
+			args = append(args, "FRAGS", sum.Frags)
+		}
+		if sum.Len > 0 {
+			args = append(args, "LEN", sum.Len)
+		}
+		if sum.Separator != "" {
+			args = append(args, "SEPARATOR", sum.Separator)
+		}
+	}
+	if hl := options.Highlight; hl != nil {
+		args = append(args, "HIGHLIGHT")
+		if len(hl.Fields) > 0 {
+			args = append(args, "FIELDS", len(hl.Fields))
+			for _, field := range hl.Fields {
+				args = append(args, field)
+			}
+		}
+		if hl.OpenTag != "" || hl.CloseTag != "" {
+			open, close := hl.tags()
+			args = append(args, "TAGS", open, close)
+		}
+	}
+	return args
+}
+
 type SearchQuery []interface{}
 
 func FTSearchQuery(query string, options *FTSearchOptions) SearchQuery {
@@ -1696,6 +2103,7 @@ func FTSearchQuery(query string, options *FTSearchOptions) SearchQuery {
 			queryArgs = append(queryArgs, len(queryArgsReturn))
 			queryArgs = append(queryArgs, queryArgsReturn...)
 		}
+		queryArgs = appendSummarizeHighlightArgs(queryArgs, options)
 		if options.Slop > 0 {
 			queryArgs = append(queryArgs, "SLOP", options.Slop)
 		}
@@ -1810,6 +2218,7 @@ func (c cmdable) FTSearchWithArgs(ctx context.Context, index string, query strin
 			args = append(args, len(argsReturn))
 			args = append(args, argsReturn...)
 		}
+		args = appendSummarizeHighlightArgs(args, options)
 		if options.Slop > 0 {
 			args = append(args, "SLOP", options.Slop)
 		}
@@ -1979,214 +2388,493 @@ func (c cmdable) FTTagVals(ctx context.Context, index string, field string) *Str
 	return cmd
 }
 
-// type FTProfileResult struct {
-// 	Results []interface{}
-// 	Profile ProfileDetails
-// }
-
-// type ProfileDetails struct {
-// 	TotalProfileTime        string
-// 	ParsingTime             string
-// 	PipelineCreationTime    string
-// 	Warning                 string
-// 	IteratorsProfile        []IteratorProfile
-// 	ResultProcessorsProfile []ResultProcessorProfile
-// }
-
-// type IteratorProfile struct {
-// 	Type           string
-// 	QueryType      string
-// 	Time           interface{}
-// 	Counter        int
-// 	Term           string
-// 	Size           int
-// 	ChildIterators []IteratorProfile
-// }
-
-// type ResultProcessorProfile struct {
-// 	Type    string
-// 	Time    interface{}
-// 	Counter int
-// }
-
-// func parseFTProfileResult(data []interface{}) (FTProfileResult, error) {
-// 	var result FTProfileResult
-// 	if len(data) < 2 {
-// 		return result, fmt.Errorf("unexpected data length")
-// 	}
-
-// 	// Parse results
-// 	result.Results = data[0].([]interface{})
-
-// 	// Parse profile details
-// 	profileData := data[1].([]interface{})
-// 	profileDetails := ProfileDetails{}
-// 	for i := 0; i < len(profileData); i += 2 {
-// 		switch profileData[i].(string) {
-// 		case "Total profile time":
-// 			profileDetails.TotalProfileTime = profileData[i+1].(string)
-// 		case "Parsing time":
-// 			profileDetails.ParsingTime = profileData[i+1].(string)
-// 		case "Pipeline creation time":
-// 			profileDetails.PipelineCreationTime = profileData[i+1].(string)
-// 		case "Warning":
-// 			profileDetails.Warning = profileData[i+1].(string)
-// 		case "Iterators profile":
-// 			profileDetails.IteratorsProfile = parseIteratorsProfile(profileData[i+1].([]interface{}))
-// 		case "Result processors profile":
-// 			profileDetails.ResultProcessorsProfile = parseResultProcessorsProfile(profileData[i+1].([]interface{}))
-// 		}
-// 	}
-
-// 	result.Profile = profileDetails
-// 	return result, nil
-// }
-
-// func parseIteratorsProfile(data []interface{}) []IteratorProfile {
-// 	var iterators []IteratorProfile
-// 	for _, item := range data {
-// 		profile := item.([]interface{})
-// 		iterator := IteratorProfile{}
-// 		for i := 0; i < len(profile); i += 2 {
-// 			switch profile[i].(string) {
-// 			case "Type":
-// 				iterator.Type = profile[i+1].(string)
-// 			case "Query type":
-// 				iterator.QueryType = profile[i+1].(string)
-// 			case "Time":
-// 				iterator.Time = profile[i+1]
-// 			case "Counter":
-// 				iterator.Counter = int(profile[i+1].(int64))
-// 			case "Term":
-// 				iterator.Term = profile[i+1].(string)
-// 			case "Size":
-// 				iterator.Size = int(profile[i+1].(int64))
-// 			case "Child iterators":
-// 				iterator.ChildIterators = parseChildIteratorsProfile(profile[i+1].([]interface{}))
-// 			}
-// 		}
-// 		iterators = append(iterators, iterator)
-// 	}
-// 	return iterators
-// }
-
-// func parseChildIteratorsProfile(data []interface{}) []IteratorProfile {
-// 	var iterators []IteratorProfile
-// 	for _, item := range data {
-// 		profile := item.([]interface{})
-// 		iterator := IteratorProfile{}
-// 		for i := 0; i < len(profile); i += 2 {
-// 			switch profile[i].(string) {
-// 			case "Type":
-// 				iterator.Type = profile[i+1].(string)
-// 			case "Query type":
-// 				iterator.QueryType = profile[i+1].(string)
-// 			case "Time":
-// 				iterator.Time = profile[i+1]
-// 			case "Counter":
-// 				iterator.Counter = int(profile[i+1].(int64))
-// 			case "Term":
-// 				iterator.Term = profile[i+1].(string)
-// 			case "Size":
-// 				iterator.Size = int(profile[i+1].(int64))
-// 			}
-// 		}
-// 		iterators = append(iterators, iterator)
-// 	}
-// 	return iterators
-// }
-
-// func parseResultProcessorsProfile(data []interface{}) []ResultProcessorProfile {
-// 	var processors []ResultProcessorProfile
-// 	for _, item := range data {
-// 		profile := item.([]interface{})
-// 		processor := ResultProcessorProfile{}
-// 		for i := 0; i < len(profile); i += 2 {
-// 			switch profile[i].(string) {
-// 			case "Type":
-// 				processor.Type = profile[i+1].(string)
-// 			case "Time":
-// 				processor.Time = profile[i+1]
-// 			case "Counter":
-// 				processor.Counter = int(profile[i+1].(int64))
-// 			}
-// 		}
-// 		processors = append(processors, processor)
-// 	}
-// 	return processors
-// }
-
-// func NewFTProfileCmd(ctx context.Context, args ...interface{}) *FTProfileCmd {
-// 	return &FTProfileCmd{
-// 		baseCmd: baseCmd{
-// 			ctx:  ctx,
-// 			args: args,
-// 		},
-// 	}
-// }
-
-// type FTProfileCmd struct {
-// 	baseCmd
-// 	val FTProfileResult
-// }
-
-// func (cmd *FTProfileCmd) String() string {
-// 	return cmdString(cmd, cmd.val)
-// }
-
-// func (cmd *FTProfileCmd) SetVal(val FTProfileResult) {
-// 	cmd.val = val
-// }
-
-// func (cmd *FTProfileCmd) Result() (FTProfileResult, error) {
-// 	return cmd.val, cmd.err
-// }
-
-// func (cmd *FTProfileCmd) Val() FTProfileResult {
-// 	return cmd.val
-// }
-
-// func (cmd *FTProfileCmd) readReply(rd *proto.Reader) (err error) {
-// 	data, err := rd.ReadSlice()
-// 	if err != nil {
-// 		return err
-// 	}
-// 	cmd.val, err = parseFTProfileResult(data)
-// 	if err != nil {
-// 		cmd.err = err
-// 	}
-// 	return nil
-// }
-
-// // FTProfile - Executes a search query and returns a profile of how the query was processed.
-// // The 'index' parameter specifies the index to search, the 'limited' parameter specifies whether to limit the results,
-// // and the 'query' parameter specifies the search / aggreagte query. Please notice that you must either pass a SearchQuery or an AggregateQuery.
-// // For more information, please refer to the Redis documentation:
-// // [FT.PROFILE]: (https://redis.io/commands/ft.profile/)
-// func (c cmdable) FTProfile(ctx context.Context, index string, limited bool, query interface{}) *FTProfileCmd {
-// 	queryType := ""
-// 	var argsQuery []interface{}
-
-// 	switch v := query.(type) {
-// 	case AggregateQuery:
-// 		queryType = "AGGREGATE"
-// 		argsQuery = v
-// 	case SearchQuery:
-// 		queryType = "SEARCH"
-// 		argsQuery = v
-// 	default:
-// 		panic("FT.PROFILE: query must be either AggregateQuery or SearchQuery")
-// 	}
-
-// 	args := []interface{}{"FT.PROFILE", index, queryType}
-
-// 	if limited {
-// 		args = append(args, "LIMITED")
-// 	}
-// 	args = append(args, "QUERY")
-// 	args = append(args, argsQuery...)
-
-// 	cmd := NewFTProfileCmd(ctx, args...)
-// 	_ = c(ctx, cmd)
-// 	return cmd
-// }
+type FTSugAddOptions struct {
+	Incr    bool
+	Payload string
+}
+
+type FTSugGetOptions struct {
+	Fuzzy        bool
+	Max          int
+	WithScores   bool
+	WithPayloads bool
+}
+
+// FTSugAdd - Adds a suggestion string to an auto-complete suggestion dictionary.
+// The 'key' parameter specifies the suggestion dictionary key, the 'str' parameter specifies the suggestion, and the 'score' parameter specifies its weight.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGADD]: (https://redis.io/commands/ft.sugadd/)
+func (c cmdable) FTSugAdd(ctx context.Context, key string, str string, score float64) *IntCmd {
+	cmd := NewIntCmd(ctx, "FT.SUGADD", key, str, score)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugAddWithArgs - Adds a suggestion string to an auto-complete suggestion dictionary with additional options.
+// The 'options' parameter allows incrementing the score of an existing suggestion (Incr) and attaching a payload to it.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGADD]: (https://redis.io/commands/ft.sugadd/)
+func (c cmdable) FTSugAddWithArgs(ctx context.Context, key string, str string, score float64, options *FTSugAddOptions) *IntCmd {
+	args := []interface{}{"FT.SUGADD", key, str, score}
+	if options != nil {
+		if options.Incr {
+			args = append(args, "INCR")
+		}
+		if options.Payload != "" {
+			args = append(args, "PAYLOAD", options.Payload)
+		}
+	}
+	cmd := NewIntCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugDel - Deletes a suggestion string from an auto-complete suggestion dictionary.
+// Returns true if the suggestion was found and deleted.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGDEL]: (https://redis.io/commands/ft.sugdel/)
+func (c cmdable) FTSugDel(ctx context.Context, key string, str string) *BoolCmd {
+	cmd := NewBoolCmd(ctx, "FT.SUGDEL", key, str)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugGet - Gets completion suggestions for a prefix.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGGET]: (https://redis.io/commands/ft.sugget/)
+func (c cmdable) FTSugGet(ctx context.Context, key string, prefix string) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "FT.SUGGET", key, prefix)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugGetWithArgs - Gets completion suggestions for a prefix with additional options.
+// The 'options' parameter allows fuzzy prefix matching, limiting the number of suggestions (Max)
+// and returning the scores and the payloads of the suggestions.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGGET]: (https://redis.io/commands/ft.sugget/)
+func (c cmdable) FTSugGetWithArgs(ctx context.Context, key string, prefix string, options *FTSugGetOptions) *FTSuggestionSliceCmd {
+	args := []interface{}{"FT.SUGGET", key, prefix}
+	if options == nil {
+		options = &FTSugGetOptions{}
+	}
+	if options.Fuzzy {
+		args = append(args, "FUZZY")
+	}
+	if options.WithScores {
+		args = append(args, "WITHSCORES")
+	}
+	if options.WithPayloads {
+		args = append(args, "WITHPAYLOADS")
+	}
+	if options.Max > 0 {
+		args = append(args, "MAX", options.Max)
+	}
+	cmd := newFTSuggestionSliceCmd(ctx, options, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// FTSugLen - Gets the size of an auto-complete suggestion dictionary.
+// For more information, please refer to the Redis documentation:
+// [FT.SUGLEN]: (https://redis.io/commands/ft.suglen/)
+func (c cmdable) FTSugLen(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd(ctx, "FT.SUGLEN", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+type FTSuggestion struct {
+	Term    string
+	Score   float64
+	Payload string
+}
+
+type FTSuggestionSliceCmd struct {
+	baseCmd
+	val     []FTSuggestion
+	options *FTSugGetOptions
+}
+
+func newFTSuggestionSliceCmd(ctx context.Context, options *FTSugGetOptions, args ...interface{}) *FTSuggestionSliceCmd {
+	return &FTSuggestionSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+		options: options,
+	}
+}
+
+func (cmd *FTSuggestionSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTSuggestionSliceCmd) SetVal(val []FTSuggestion) {
+	cmd.val = val
+}
+
+func (cmd *FTSuggestionSliceCmd) Result() ([]FTSuggestion, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTSuggestionSliceCmd) Val() []FTSuggestion {
+	return cmd.val
+}
+
+func (cmd *FTSuggestionSliceCmd) readReply(rd *proto.Reader) (err error) {
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+
+	step := 1
+	if cmd.options.WithScores {
+		step++
+	}
+	if cmd.options.WithPayloads {
+		step++
+	}
+	if n%step != 0 {
+		return fmt.Errorf("redis: got %d elements in the suggestions reply, want a multiple of %d", n, step)
+	}
+
+	cmd.val = make([]FTSuggestion, n/step)
+	for i := range cmd.val {
+		if cmd.val[i].Term, err = rd.ReadString(); err != nil {
+			return err
+		}
+		if cmd.options.WithScores {
+			if cmd.val[i].Score, err = rd.ReadFloat(); err != nil {
+				return err
+			}
+		}
+		if cmd.options.WithPayloads {
+			if cmd.val[i].Payload, err = rd.ReadString(); err != nil && err != Nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type FTProfileResult struct {
+	// Results is the raw reply of the profiled FT.SEARCH or FT.AGGREGATE query.
+	Results interface{}
+	// Profile is the profile of the query, the one of the first shard on clusters.
+	Profile ProfileDetails
+	// Shards are the profiles of every shard.
+	Shards []ProfileDetails
+}
+
+// ProfileDetails is the profile of a query on a shard. The times are in milliseconds.
+type ProfileDetails struct {
+	TotalProfileTime        float64
+	ParsingTime             float64
+	PipelineCreationTime    float64
+	Warning                 string
+	IteratorsProfile        []IteratorProfile
+	ResultProcessorsProfile []ResultProcessorProfile
+}
+
+// IteratorProfile is a node of the tree of iterators executing the query.
+type IteratorProfile struct {
+	Type           string
+	QueryType      string
+	Time           float64
+	Counter        int64
+	Term           string
+	Size           int64
+	ChildIterators []IteratorProfile
+}
+
+// ResultProcessorProfile is a step of the pipeline processing the results.
+type ResultProcessorProfile struct {
+	Type    string
+	Time    float64
+	Counter int64
+}
+
+func parseFTProfileResult(reply interface{}) (FTProfileResult, error) {
+	var result FTProfileResult
+
+	var profile interface{}
+	switch reply := reply.(type) {
+	case []interface{}:
+		if len(reply) < 2 {
+			return result, fmt.Errorf("redis: unexpected FT.PROFILE reply length %d", len(reply))
+		}
+		result.Results, profile = reply[0], reply[1]
+	case map[interface{}]interface{}:
+		for k, v := range reply {
+			switch k, _ := k.(string); internal.ToLower(k) {
+			case "results":
+				result.Results = v
+			case "profile":
+				profile = v
+			}
+		}
+		if profile == nil {
+			return result, fmt.Errorf("redis: FT.PROFILE reply has no profile")
+		}
+	default:
+		return result, fmt.Errorf("redis: unexpected FT.PROFILE reply %T", reply)
+	}
+
+	fields := profileFields(profile)
+	if shards, ok := fields["shards"]; ok {
+		for _, shard := range profileList(shards) {
+			result.Shards = append(result.Shards, parseProfileDetails(profileFields(shard)))
+		}
+	} else {
+		result.Shards = []ProfileDetails{parseProfileDetails(fields)}
+	}
+	if len(result.Shards) > 0 {
+		result.Profile = result.Shards[0]
+	}
+	return result, nil
+}
+
+func parseProfileDetails(fields map[string]interface{}) ProfileDetails {
+	var details ProfileDetails
+	for k, v := range fields {
+		switch k {
+		case "total profile time":
+			details.TotalProfileTime = profileFloat(v)
+		case "parsing time":
+			details.ParsingTime = profileFloat(v)
+		case "pipeline creation time":
+			details.PipelineCreationTime = profileFloat(v)
+		case "warning":
+			details.Warning = profileString(v)
+		case "iterators profile":
+			details.IteratorsProfile = parseIteratorsProfile(v)
+		case "result processors profile":
+			for _, item := range profileList(v) {
+				fields := profileFields(item)
+				details.ResultProcessorsProfile = append(details.ResultProcessorsProfile, ResultProcessorProfile{
+					Type:    profileString(fields["type"]),
+					Time:    profileFloat(fields["time"]),
+					Counter: profileInt(fields["counter"]),
+				})
+			}
+		}
+	}
+	return details
+}
+
+// parseIteratorsProfile parses either a single iterator or a list of iterators.
+func parseIteratorsProfile(v interface{}) []IteratorProfile {
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		if _, ok := v.(map[interface{}]interface{}); ok {
+			return []IteratorProfile{parseIteratorProfile(profileFields(v))}
+		}
+		return nil
+	}
+
+	switch list[0].(type) {
+	case []interface{}, map[interface{}]interface{}:
+		iterators := make([]IteratorProfile, 0, len(list))
+		for _, item := range list {
+			iterators = append(iterators, parseIteratorProfile(profileFields(item)))
+		}
+		return iterators
+	default:
+		return []IteratorProfile{parseIteratorProfile(profileFields(v))}
+	}
+}
+
+func parseIteratorProfile(fields map[string]interface{}) IteratorProfile {
+	iterator := IteratorProfile{
+		Type:      profileString(fields["type"]),
+		QueryType: profileString(fields["query type"]),
+		Time:      profileFloat(fields["time"]),
+		Counter:   profileInt(fields["counter"]),
+		Term:      profileString(fields["term"]),
+		Size:      profileInt(fields["size"]),
+	}
+	if children, ok := fields["child iterators"]; ok {
+		iterator.ChildIterators = parseIteratorsProfile(children)
+	}
+	return iterator
+}
+
+// profileFields returns the fields of a profile node by lower-cased name.
+// Depending on the protocol and the module version, a node is a map, a flat
+// list of names and values or a list of [name, value...] lists. Child
+// iterators may follow their name as several consecutive values.
+func profileFields(v interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		for k, val := range v {
+			if k, ok := k.(string); ok {
+				fields[internal.ToLower(k)] = val
+			}
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			if _, ok := v[0].([]interface{}); ok {
+				for _, item := range v {
+					pair, ok := item.([]interface{})
+					if !ok || len(pair) < 2 {
+						continue
+					}
+					if k, ok := pair[0].(string); ok {
+						if len(pair) == 2 {
+							fields[internal.ToLower(k)] = pair[1]
+						} else {
+							fields[internal.ToLower(k)] = pair[1:]
+						}
+					}
+				}
+				return fields
+			}
+		}
+		for i := 0; i < len(v)-1; i++ {
+			k, ok := v[i].(string)
+			if !ok {
+				continue
+			}
+			k = internal.ToLower(k)
+			if k != "child iterators" {
+				fields[k] = v[i+1]
+				i++
+				continue
+			}
+			// The children of RESP2 replies are listed one after the other.
+			j := i + 1
+			for j < len(v) {
+				if _, ok := v[j].([]interface{}); !ok {
+					break
+				}
+				j++
+			}
+			if j-i-1 == 1 {
+				fields[k] = v[i+1]
+			} else {
+				fields[k] = v[i+1 : j]
+			}
+			i = j - 1
+		}
+	}
+	return fields
+}
+
+func profileList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func profileString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func profileFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+func profileInt(v interface{}) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
+
+func NewFTProfileCmd(ctx context.Context, args ...interface{}) *FTProfileCmd {
+	return &FTProfileCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+type FTProfileCmd struct {
+	baseCmd
+	val FTProfileResult
+}
+
+func (cmd *FTProfileCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *FTProfileCmd) SetVal(val FTProfileResult) {
+	cmd.val = val
+}
+
+func (cmd *FTProfileCmd) Result() (FTProfileResult, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *FTProfileCmd) Val() FTProfileResult {
+	return cmd.val
+}
+
+func (cmd *FTProfileCmd) readReply(rd *proto.Reader) (err error) {
+	data, err := rd.ReadReply()
+	if err != nil {
+		return err
+	}
+	cmd.val, err = parseFTProfileResult(data)
+	if err != nil {
+		cmd.err = err
+	}
+	return nil
+}
+
+// FTProfile - Executes a search query and returns a profile of how the query was processed.
+// The 'index' parameter specifies the index to search, the 'limited' parameter specifies whether to limit the results,
+// and the 'query' parameter specifies the search / aggregate query. Please notice that you must either pass a SearchQuery or an AggregateQuery.
+// For more information, please refer to the Redis documentation:
+// [FT.PROFILE]: (https://redis.io/commands/ft.profile/)
+func (c cmdable) FTProfile(ctx context.Context, index string, limited bool, query interface{}) *FTProfileCmd {
+	queryType := ""
+	var argsQuery []interface{}
+
+	switch v := query.(type) {
+	case AggregateQuery:
+		queryType = "AGGREGATE"
+		argsQuery = v
+	case SearchQuery:
+		queryType = "SEARCH"
+		argsQuery = v
+	default:
+		panic("FT.PROFILE: query must be either AggregateQuery or SearchQuery")
+	}
+
+	args := []interface{}{"FT.PROFILE", index, queryType}
+
+	if limited {
+		args = append(args, "LIMITED")
+	}
+	args = append(args, "QUERY")
+	args = append(args, argsQuery...)
+
+	cmd := NewFTProfileCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
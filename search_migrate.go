@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// FTWaitForIndexing polls FT.INFO every interval, 100 milliseconds when
+// interval is zero, until index has finished indexing its documents.
+func FTWaitForIndexing(ctx context.Context, client SearchCmdable, index string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+	for {
+		info, err := client.FTInfo(ctx, index).Result()
+		if err != nil {
+			return err
+		}
+		if info.Indexing == 0 {
+			return nil
+		}
+		if err := internal.Sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// FTAliasTarget returns the index alias points to, or "" when the alias does not exist.
+func FTAliasTarget(ctx context.Context, client SearchCmdable, alias string) (string, error) {
+	info, err := client.FTInfo(ctx, alias).Result()
+	if err != nil {
+		if isUnknownIndexError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return info.IndexName, nil
+}
+
+// FTAliasSwapSafe points alias to index, adding the alias when it does not
+// exist, once index exists and has finished indexing, so queries through the
+// alias never see a partially built index. It returns the index the alias
+// pointed to before, "" when it did not exist.
+func FTAliasSwapSafe(ctx context.Context, client SearchCmdable, alias, index string) (string, error) {
+	if err := FTWaitForIndexing(ctx, client, index, 0); err != nil {
+		return "", err
+	}
+	old, err := FTAliasTarget(ctx, client, alias)
+	if err != nil {
+		return "", err
+	}
+	if old == index {
+		return old, nil
+	}
+	if err := client.FTAliasUpdate(ctx, index, alias).Err(); err != nil {
+		return "", err
+	}
+	return old, nil
+}
+
+type FTMigrateOptions struct {
+	// PollInterval is the interval between the FT.INFO polls waiting for the
+	// new index to be built. Default is 100 milliseconds.
+	PollInterval time.Duration
+	// KeepOld keeps the index the alias pointed to instead of dropping it.
+	// The documents of a dropped index are never deleted.
+	KeepOld bool
+}
+
+// FTMigrateIndex migrates the index behind alias to a new schema, blue/green
+// style: it creates index with options and schema, waits for it to index the
+// existing documents, points alias to it and drops the index alias pointed to
+// before, which it returns. When the migration fails before the alias is
+// swapped, the alias is left untouched and index is dropped.
+func FTMigrateIndex(
+	ctx context.Context, client SearchCmdable, alias, index string,
+	options *FTCreateOptions, schema []*FieldSchema, opt *FTMigrateOptions,
+) (string, error) {
+	if opt == nil {
+		opt = &FTMigrateOptions{}
+	}
+
+	old, err := FTAliasTarget(ctx, client, alias)
+	if err != nil {
+		return "", err
+	}
+	if old == index {
+		return "", fmt.Errorf("redis: alias %q already points to index %q", alias, index)
+	}
+
+	if err := client.FTCreate(ctx, index, options, schema...).Err(); err != nil {
+		return "", err
+	}
+	if err := FTWaitForIndexing(ctx, client, index, opt.PollInterval); err != nil {
+		_ = client.FTDropIndex(context.Background(), index).Err()
+		return "", err
+	}
+	if err := client.FTAliasUpdate(ctx, index, alias).Err(); err != nil {
+		_ = client.FTDropIndex(context.Background(), index).Err()
+		return "", err
+	}
+
+	if old != "" && !opt.KeepOld {
+		if err := client.FTDropIndex(ctx, old).Err(); err != nil {
+			return old, err
+		}
+	}
+	return old, nil
+}
@@ -0,0 +1,169 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// minParamsDialect is the first dialect supporting query parameters.
+const minParamsDialect = 2
+
+// FTBindParams validates the named parameters of query and returns them
+// ready to be sent as PARAMS. Values are bound as data, never as query syntax,
+// so user input should be passed as a parameter rather than concatenated into
+// the query string.
+//
+// Supported values are strings, []byte, integers, finite floats, and
+// []float32 or []float64 vectors, which are encoded as FLOAT32 and FLOAT64
+// blobs. Every $name the query references must be bound.
+func FTBindParams(query string, params map[string]interface{}) (map[string]interface{}, error) {
+	bound := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		if !isParamName(name) {
+			return nil, fmt.Errorf("redis: invalid search parameter name %q", name)
+		}
+		v, err := ftParamValue(name, value)
+		if err != nil {
+			return nil, err
+		}
+		bound[name] = v
+	}
+	for _, name := range queryParamRefs(query) {
+		if _, ok := bound[name]; !ok {
+			return nil, fmt.Errorf("redis: search query references undefined parameter $%s", name)
+		}
+	}
+	return bound, nil
+}
+
+func ftParamValue(name string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string, []byte,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return v, nil
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil, fmt.Errorf("redis: search parameter %q is not a finite number", name)
+		}
+		return v, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("redis: search parameter %q is not a finite number", name)
+		}
+		return v, nil
+	case []float32:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("redis: search parameter %q is an empty vector", name)
+		}
+		return EncodeFloat32Vector(v), nil
+	case []float64:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("redis: search parameter %q is an empty vector", name)
+		}
+		return EncodeFloat64Vector(v), nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported type %T of search parameter %q", value, name)
+	}
+}
+
+func isParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isParamNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isParamNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// queryParamRefs returns the names of the parameters referenced by query.
+// Escaped dollars and query attributes, such as $YIELD_DISTANCE_AS: dist,
+// are not references.
+func queryParamRefs(query string) []string {
+	var refs []string
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+			continue
+		case '$':
+		default:
+			continue
+		}
+
+		j := i + 1
+		for j < len(query) && isParamNameChar(query[j]) {
+			j++
+		}
+		if j == i+1 {
+			continue
+		}
+		name := query[i+1 : j]
+
+		k := j
+		for k < len(query) && query[k] == ' ' {
+			k++
+		}
+		if k < len(query) && query[k] == ':' {
+			i = j - 1
+			continue
+		}
+
+		refs = append(refs, name)
+		i = j - 1
+	}
+	return refs
+}
+
+func paramsDialect(dialect int) int {
+	if dialect < minParamsDialect {
+		return minParamsDialect
+	}
+	return dialect
+}
+
+// FTSearchWithParams is like FTSearchWithArgs, but binds params with
+// FTBindParams and selects dialect 2 or higher, as parameters require.
+// Params set in options are ignored.
+func (c cmdable) FTSearchWithParams(ctx context.Context, index string, query string, params map[string]interface{}, options *FTSearchOptions) *FTSearchCmd {
+	var opt FTSearchOptions
+	if options != nil {
+		opt = *options
+	}
+	bound, err := FTBindParams(query, params)
+	if err != nil {
+		cmd := newFTSearchCmd(ctx, &opt, "FT.SEARCH", index, query)
+		cmd.SetErr(err)
+		return cmd
+	}
+	opt.Params = bound
+	opt.DialectVersion = paramsDialect(opt.DialectVersion)
+	return c.FTSearchWithArgs(ctx, index, query, &opt)
+}
+
+// FTAggregateWithParams is like FTAggregateWithArgs, but binds params with
+// FTBindParams and selects dialect 2 or higher, as parameters require.
+// Params set in options are ignored.
+func (c cmdable) FTAggregateWithParams(ctx context.Context, index string, query string, params map[string]interface{}, options *FTAggregateOptions) *AggregateCmd {
+	var opt FTAggregateOptions
+	if options != nil {
+		opt = *options
+	}
+	bound, err := FTBindParams(query, params)
+	if err != nil {
+		cmd := NewAggregateCmd(ctx, "FT.AGGREGATE", index, query)
+		cmd.SetErr(err)
+		return cmd
+	}
+	opt.Params = bound
+	opt.DialectVersion = paramsDialect(opt.DialectVersion)
+	return c.FTAggregateWithArgs(ctx, index, query, &opt)
+}
@@ -0,0 +1,431 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// SearchSchemaFromStruct generates the FT.CREATE schema of the struct type of v
+// from the `search` tags of its fields. The first item of the tag is the field
+// type followed by comma separated options:
+//
+//	Title  string    `redis:"title" search:"text,sortable,weight=2"`
+//	Tags   []string  `redis:"tags" search:"tag,separator=;"`
+//	Price  float64   `redis:"price" search:"numeric,sortable,noindex"`
+//	Vector []float32 `redis:"vec" search:"vector,algorithm=hnsw,dim=128,distance=cosine,type=float32"`
+//
+// The types are text, tag, numeric, geo, geoshape and vector. The flags are
+// sortable, unf, nostem, noindex, casesensitive and withsuffixtrie; the valued
+// options are as, weight, separator, phonetic and, for geoshape, coord. Vectors
+// require dim and default to the FLAT algorithm with FLOAT32 elements and the
+// COSINE distance; they accept initial_cap, block_size, m, ef_construction,
+// ef_runtime and epsilon.
+//
+// Fields of HASH indexes are named after their `redis` tag. Fields of JSON
+// indexes (onJSON) are JSONPaths built from the `json` tags, nested structs
+// included, and are aliased to the `json` names joined with "_", so they can be
+// queried with the same names as HASH fields. Tagged slices of a JSON index are
+// indexed element-wise, except vectors.
+func SearchSchemaFromStruct(v interface{}, onJSON bool) ([]*FieldSchema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("redis: SearchSchemaFromStruct(non-struct %T)", v)
+	}
+
+	var schema []*FieldSchema
+	if err := appendStructSchema(&schema, t, onJSON, "$", ""); err != nil {
+		return nil, err
+	}
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("redis: %s has no fields with a search tag", t)
+	}
+	return schema, nil
+}
+
+func appendStructSchema(schema *[]*FieldSchema, t reflect.Type, onJSON bool, path, alias string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := fieldTagName(f, "redis")
+		if onJSON {
+			name = fieldTagName(f, "json")
+		}
+		if name == "-" {
+			continue
+		}
+
+		fieldPath, fieldAlias := path+"."+name, name
+		if alias != "" {
+			fieldAlias = alias + "_" + name
+		}
+
+		tag, ok := f.Tag.Lookup("search")
+		if !ok {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if onJSON && ft.Kind() == reflect.Struct {
+				if err := appendStructSchema(schema, ft, onJSON, fieldPath, fieldAlias); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if tag == "-" {
+			continue
+		}
+
+		field, err := parseSearchTag(tag)
+		if err != nil {
+			return fmt.Errorf("redis: field %s.%s: %w", t, f.Name, err)
+		}
+
+		if !onJSON {
+			field.FieldName = name
+			*schema = append(*schema, field)
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Slice && field.FieldType != SearchFieldTypeVector {
+			fieldPath += "[*]"
+		}
+		field.FieldName = fieldPath
+		if field.As == "" {
+			field.As = fieldAlias
+		}
+		*schema = append(*schema, field)
+	}
+	return nil
+}
+
+func fieldTagName(f reflect.StructField, key string) string {
+	if tag := strings.Split(f.Tag.Get(key), ",")[0]; tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+func parseSearchTag(tag string) (*FieldSchema, error) {
+	items := strings.Split(tag, ",")
+	field := &FieldSchema{}
+
+	switch typ := strings.ToLower(strings.TrimSpace(items[0])); typ {
+	case "text":
+		field.FieldType = SearchFieldTypeText
+	case "tag":
+		field.FieldType = SearchFieldTypeTag
+	case "numeric":
+		field.FieldType = SearchFieldTypeNumeric
+	case "geo":
+		field.FieldType = SearchFieldTypeGeo
+	case "geoshape":
+		field.FieldType = SearchFieldTypeGeoShape
+	case "vector":
+		field.FieldType = SearchFieldTypeVector
+	default:
+		return nil, fmt.Errorf("unknown search field type %q", typ)
+	}
+
+	vector := map[string]string{}
+	for _, item := range items[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		key = strings.ToLower(key)
+
+		var err error
+		switch key {
+		case "":
+		case "sortable":
+			field.Sortable = true
+		case "unf":
+			field.UNF = true
+		case "nostem":
+			field.NoStem = true
+		case "noindex":
+			field.NoIndex = true
+		case "casesensitive":
+			field.CaseSensitive = true
+		case "withsuffixtrie":
+			field.WithSuffixtrie = true
+		case "as":
+			field.As = val
+		case "weight":
+			field.Weight, err = strconv.ParseFloat(val, 64)
+		case "separator":
+			field.Seperator = val
+		case "phonetic":
+			field.PhoneticMatcher = val
+		case "coord":
+			field.GeoShapeFieldType = strings.ToUpper(val)
+		case "algorithm", "type", "dim", "distance", "initial_cap", "block_size",
+			"m", "ef_construction", "ef_runtime", "epsilon":
+			vector[key] = val
+		default:
+			return nil, fmt.Errorf("unknown search option %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid search option %q: %w", item, err)
+		}
+	}
+
+	if field.FieldType == SearchFieldTypeVector {
+		args, err := parseVectorArgs(vector)
+		if err != nil {
+			return nil, err
+		}
+		field.VectorArgs = args
+	} else if len(vector) > 0 {
+		return nil, fmt.Errorf("vector options on a %s field", field.FieldType)
+	}
+
+	return field, nil
+}
+
+func parseVectorArgs(opts map[string]string) (*FTVectorArgs, error) {
+	ints := make(map[string]int, len(opts))
+	for _, key := range []string{"dim", "initial_cap", "block_size", "m", "ef_construction", "ef_runtime"} {
+		if s, ok := opts[key]; ok {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vector option %s=%s: %w", key, s, err)
+			}
+			ints[key] = n
+		}
+	}
+	var epsilon float64
+	if s, ok := opts["epsilon"]; ok {
+		var err error
+		if epsilon, err = strconv.ParseFloat(s, 64); err != nil {
+			return nil, fmt.Errorf("invalid vector option epsilon=%s: %w", s, err)
+		}
+	}
+
+	if ints["dim"] <= 0 {
+		return nil, fmt.Errorf("vector fields require a dim option")
+	}
+	typ := strings.ToUpper(opts["type"])
+	if typ == "" {
+		typ = "FLOAT32"
+	}
+	distance := strings.ToUpper(opts["distance"])
+	if distance == "" {
+		distance = "COSINE"
+	}
+
+	switch algorithm := strings.ToUpper(opts["algorithm"]); algorithm {
+	case "", "FLAT":
+		return &FTVectorArgs{FlatOptions: &FTFlatOptions{
+			Type:            typ,
+			Dim:             ints["dim"],
+			DistanceMetric:  distance,
+			InitialCapacity: ints["initial_cap"],
+			BlockSize:       ints["block_size"],
+		}}, nil
+	case "HNSW":
+		return &FTVectorArgs{HNSWOptions: &FTHNSWOptions{
+			Type:                   typ,
+			Dim:                    ints["dim"],
+			DistanceMetric:         distance,
+			InitialCapacity:        ints["initial_cap"],
+			MaxEdgesPerNode:        ints["m"],
+			MaxAllowedEdgesPerNode: ints["ef_construction"],
+			EFRunTime:              ints["ef_runtime"],
+			Epsilon:                epsilon,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unknown vector algorithm %q", algorithm)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// SearchIndex is a search index whose schema is generated from a struct,
+// see SearchSchemaFromStruct.
+type SearchIndex struct {
+	client  SearchCmdable
+	name    string
+	options *FTCreateOptions
+	schema  []*FieldSchema
+}
+
+// NewSearchIndex returns the index name of the documents of the struct type of v.
+// The index is built on JSON documents when options.OnJSON is set, on hashes otherwise.
+func NewSearchIndex(client SearchCmdable, name string, options *FTCreateOptions, v interface{}) (*SearchIndex, error) {
+	if options == nil {
+		options = &FTCreateOptions{}
+	}
+	schema, err := SearchSchemaFromStruct(v, options.OnJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchIndex{
+		client:  client,
+		name:    name,
+		options: options,
+		schema:  schema,
+	}, nil
+}
+
+// Name returns the name of the index.
+func (idx *SearchIndex) Name() string {
+	return idx.name
+}
+
+// Schema returns the schema generated for the index.
+func (idx *SearchIndex) Schema() []*FieldSchema {
+	return idx.schema
+}
+
+// EnsureIndex creates the index unless it already exists. An existing index is
+// checked against the schema, and any difference in the key type, the fields
+// or their types is reported as an *FTSchemaMismatchError; the index is never dropped.
+func (idx *SearchIndex) EnsureIndex(ctx context.Context) error {
+	return FTEnsureIndex(ctx, idx.client, idx.Definition())
+}
+
+// Definition returns the definition of the index, e.g. to ensure it with
+// FTEnsureIndex and AddFields.
+func (idx *SearchIndex) Definition() *FTIndexDefinition {
+	return &FTIndexDefinition{
+		Name:    idx.name,
+		Options: idx.options,
+		Schema:  idx.schema,
+	}
+}
+
+// FTIndexDefinition is the desired definition of a search index, see FTEnsureIndex.
+type FTIndexDefinition struct {
+	Name    string
+	Options *FTCreateOptions
+	Schema  []*FieldSchema
+
+	// AddFields adds the fields missing from an existing index with FT.ALTER
+	// instead of reporting them. The other differences are still reported.
+	AddFields bool
+	// SkipInitialScan does not index the existing documents on the added fields.
+	SkipInitialScan bool
+}
+
+// FTSchemaMismatchError is returned by FTEnsureIndex when an existing index
+// does not match its definition.
+type FTSchemaMismatchError struct {
+	Index string
+	Diffs []string
+}
+
+func (e *FTSchemaMismatchError) Error() string {
+	return fmt.Sprintf("redis: index %q does not match the schema: %s", e.Index, strings.Join(e.Diffs, "; "))
+}
+
+// FTEnsureIndex creates the index of def unless it already exists. It is
+// idempotent: an existing index is compared with def using FT.INFO, and the
+// differences in the key type, the fields or their options are reported as an
+// *FTSchemaMismatchError. With AddFields, the missing fields are added with
+// FT.ALTER first. The index is never dropped.
+func FTEnsureIndex(ctx context.Context, client SearchCmdable, def *FTIndexDefinition) error {
+	options := def.Options
+	if options == nil {
+		options = &FTCreateOptions{}
+	}
+
+	info, err := client.FTInfo(ctx, def.Name).Result()
+	if err != nil {
+		if !isUnknownIndexError(err) {
+			return err
+		}
+		return client.FTCreate(ctx, def.Name, options, def.Schema...).Err()
+	}
+
+	diffs, missing := diffIndex(options, def.Schema, &info)
+	if len(missing) > 0 {
+		if def.AddFields {
+			var args []interface{}
+			for _, field := range missing {
+				args = appendFieldSchemaArgs(args, field)
+			}
+			if err := client.FTAlter(ctx, def.Name, def.SkipInitialScan, args).Err(); err != nil {
+				return err
+			}
+		} else {
+			for _, field := range missing {
+				diffs = append(diffs, fmt.Sprintf("field %s is missing", field.FieldName))
+			}
+		}
+	}
+	if len(diffs) > 0 {
+		return &FTSchemaMismatchError{Index: def.Name, Diffs: diffs}
+	}
+	return nil
+}
+
+// diffIndex returns the differences between the index and the schema, except
+// the fields missing from the index, which are returned separately.
+func diffIndex(options *FTCreateOptions, schema []*FieldSchema, info *FTInfoResult) ([]string, []*FieldSchema) {
+	var diffs []string
+	var missing []*FieldSchema
+
+	keyType := "HASH"
+	if options.OnJSON {
+		keyType = "JSON"
+	}
+	if info.IndexDefinition.KeyType != "" && info.IndexDefinition.KeyType != keyType {
+		diffs = append(diffs, fmt.Sprintf("key type is %s, want %s", info.IndexDefinition.KeyType, keyType))
+	}
+
+	attrs := make(map[string]FTAttribute, len(info.Attributes))
+	for _, attr := range info.Attributes {
+		attrs[attr.Identifier] = attr
+	}
+
+	for _, field := range schema {
+		attr, ok := attrs[field.FieldName]
+		if !ok {
+			missing = append(missing, field)
+			continue
+		}
+		delete(attrs, field.FieldName)
+
+		if typ := field.FieldType.String(); !strings.EqualFold(attr.Type, typ) {
+			diffs = append(diffs, fmt.Sprintf("field %s is %s, want %s", field.FieldName, attr.Type, typ))
+		}
+		if field.As != "" && attr.Attribute != field.As {
+			diffs = append(diffs, fmt.Sprintf("field %s is aliased to %s, want %s", field.FieldName, attr.Attribute, field.As))
+		}
+		if attr.Sortable != field.Sortable {
+			diffs = append(diffs, fmt.Sprintf("field %s has sortable=%t, want %t", field.FieldName, attr.Sortable, field.Sortable))
+		}
+		if attr.NoIndex != field.NoIndex {
+			diffs = append(diffs, fmt.Sprintf("field %s has noindex=%t, want %t", field.FieldName, attr.NoIndex, field.NoIndex))
+		}
+	}
+
+	extra := make([]string, 0, len(attrs))
+	for name := range attrs {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		diffs = append(diffs, fmt.Sprintf("field %s is not in the schema", name))
+	}
+	return diffs, missing
+}
+
+func isUnknownIndexError(err error) bool {
+	if !isRedisError(err) {
+		return false
+	}
+	s := internal.ToLower(err.Error())
+	return strings.Contains(s, "unknown index name") || strings.Contains(s, "no such index")
+}
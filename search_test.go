@@ -2,6 +2,8 @@ package redis_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	. "github.com/bsm/ginkgo/v2"
@@ -404,6 +406,39 @@ var _ = Describe("RediSearch commands", Label("search"), func() {
 		Expect(*res.Docs[0].Score).To(BeEquivalentTo(float64(0)))
 	})
 
+	It("should FTSearch Summarize and Highlight", Label("search", "ftsearch"), func() {
+		text1 := &redis.FieldSchema{FieldName: "title", FieldType: redis.SearchFieldTypeText}
+		text2 := &redis.FieldSchema{FieldName: "body", FieldType: redis.SearchFieldTypeText}
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, text1, text2).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "doc1", "title", "Foxes", "body", "The quick brown fox jumps over the lazy dog")
+
+		res, err := client.FTSearchWithArgs(ctx, "idx1", "fox", &redis.FTSearchOptions{
+			Highlight: &redis.FTSearchHighlight{Fields: []string{"body"}, OpenTag: "[", CloseTag: "]"},
+		}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Docs).To(HaveLen(1))
+		Expect(res.Docs[0].Fields["body"]).To(Equal("The quick brown [fox] jumps over the lazy dog"))
+		Expect(res.Docs[0].Fragments).To(Equal(map[string][]redis.FTFragment{
+			"body": {{Text: "The quick brown fox jumps over the lazy dog", Highlights: [][2]int{{16, 19}}}},
+		}))
+
+		res, err = client.FTSearchWithArgs(ctx, "idx1", "fox", &redis.FTSearchOptions{
+			Summarize: &redis.FTSearchSummarize{Fields: []string{"body"}, Frags: 1, Len: 3, Separator: "|"},
+			Highlight: &redis.FTSearchHighlight{Fields: []string{"body"}},
+		}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		frags := res.Docs[0].Fragments["body"]
+		Expect(frags).To(HaveLen(1))
+		Expect(frags[0].Text).To(ContainSubstring("fox"))
+		Expect(frags[0].Highlights).To(HaveLen(1))
+		start, end := frags[0].Highlights[0][0], frags[0].Highlights[0][1]
+		Expect(frags[0].Text[start:end]).To(Equal("fox"))
+	})
+
 	It("should FTConfigSet and FTConfigGet ", Label("search", "ftconfigget", "ftconfigset", "NonRedisEnterprise"), func() {
 		val, err := client.FTConfigSet(ctx, "TIMEOUT", "100").Result()
 		Expect(err).NotTo(HaveOccurred())
@@ -686,6 +721,203 @@ var _ = Describe("RediSearch commands", Label("search"), func() {
 		Expect(res.Docs[0].Fields["just_a_number"]).To(BeEquivalentTo("25"))
 	})
 
+	It("should FTSearch and Scan HASH documents", Label("search", "ftsearch"), func() {
+		text1 := &redis.FieldSchema{FieldName: "name", FieldType: redis.SearchFieldTypeText}
+		num1 := &redis.FieldSchema{FieldName: "age", FieldType: redis.SearchFieldTypeNumeric}
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, text1, num1).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "user:1", "name", "Jon", "age", 25)
+		client.HSet(ctx, "user:2", "name", "Jon", "age", 30)
+
+		type user struct {
+			Key   string  `redis:"__key"`
+			Score float64 `redis:"__score"`
+			Name  string  `redis:"name"`
+			Age   int     `redis:"age"`
+		}
+
+		var users []user
+		err = client.FTSearchWithArgs(ctx, "idx1", "Jon", &redis.FTSearchOptions{
+			WithScores: true,
+			SortBy:     []redis.FTSearchSortBy{{FieldName: "age", Asc: true}},
+		}).Scan(&users)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(2))
+		Expect(users[0].Key).To(Equal("user:1"))
+		Expect(users[0].Name).To(Equal("Jon"))
+		Expect(users[0].Age).To(Equal(25))
+		Expect(users[0].Score).To(BeNumerically(">", 0))
+		Expect(users[1].Age).To(Equal(30))
+	})
+
+	It("should FTSearch and Scan JSON documents", Label("search", "ftsearch"), func() {
+		text1 := &redis.FieldSchema{FieldName: "$.name", FieldType: redis.SearchFieldTypeText, As: "name"}
+		num1 := &redis.FieldSchema{FieldName: "$.age", FieldType: redis.SearchFieldTypeNumeric, As: "age"}
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{OnJSON: true}, text1, num1).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.JSONSet(ctx, "doc:1", "$", `{"name": "Jon", "age": 25}`)
+
+		type person struct {
+			Key  string `redis:"__key"`
+			Name string `json:"name" redis:"name"`
+			Age  int    `json:"age" redis:"age"`
+		}
+
+		var docs []*person
+		Expect(client.FTSearch(ctx, "idx1", "Jon").Scan(&docs)).NotTo(HaveOccurred())
+		Expect(docs).To(Equal([]*person{{Key: "doc:1", Name: "Jon", Age: 25}}))
+
+		var returned []person
+		err = client.FTSearchWithArgs(ctx, "idx1", "Jon", &redis.FTSearchOptions{
+			Return: []redis.FTSearchReturn{{FieldName: "name"}, {FieldName: "age"}},
+		}).Scan(&returned)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(returned).To(Equal([]person{{Key: "doc:1", Name: "Jon", Age: 25}}))
+	})
+
+	It("should iterate FTAggregate rows with a cursor", Label("search", "ftaggregate"), func() {
+		num1 := &redis.FieldSchema{FieldName: "n", FieldType: redis.SearchFieldTypeNumeric}
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, num1).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		for i := 0; i < 10; i++ {
+			client.HSet(ctx, fmt.Sprintf("doc%d", i), "n", i)
+		}
+
+		options := &redis.FTAggregateOptions{
+			Load:              []redis.FTAggregateLoad{{Field: "n"}},
+			WithCursor:        true,
+			WithCursorOptions: &redis.FTAggregateWithCursor{Count: 3},
+		}
+		cmd := client.FTAggregateWithArgs(ctx, "idx1", "*", options)
+		Expect(cmd.Err()).NotTo(HaveOccurred())
+		Expect(cmd.Val().Rows).To(HaveLen(3))
+		Expect(cmd.Val().CursorID).NotTo(BeZero())
+
+		type row struct {
+			N int `redis:"n"`
+		}
+
+		var ns []int
+		iter := cmd.Iterator()
+		for iter.Next(ctx) {
+			var r row
+			Expect(iter.Val().Scan(&r)).NotTo(HaveOccurred())
+			ns = append(ns, r.N)
+		}
+		Expect(iter.Err()).NotTo(HaveOccurred())
+		Expect(ns).To(ConsistOf(0, 1, 2, 3, 4, 5, 6, 7, 8, 9))
+		Expect(iter.Close(ctx)).NotTo(HaveOccurred())
+
+		iter = client.FTAggregateWithArgs(ctx, "idx1", "*", options).Iterator()
+		Expect(iter.Next(ctx)).To(BeTrue())
+		Expect(iter.Close(ctx)).NotTo(HaveOccurred())
+		Expect(iter.Next(ctx)).To(BeFalse())
+		Expect(iter.Err()).NotTo(HaveOccurred())
+	})
+
+	It("should generate a schema from struct tags", Label("search", "ftcreate"), func() {
+		type address struct {
+			City string `json:"city" search:"tag"`
+		}
+		type product struct {
+			ID      string    `redis:"-" json:"-"`
+			Title   string    `redis:"title" json:"title" search:"text,sortable,weight=2"`
+			Tags    []string  `redis:"tags" json:"tags" search:"tag,separator=;"`
+			Price   float64   `redis:"price" json:"price" search:"numeric,noindex"`
+			Vector  []float32 `redis:"vec" json:"vec" search:"vector,algorithm=hnsw,dim=4,m=16"`
+			Address address   `redis:"-" json:"address"`
+			Ignored string    `redis:"ignored" json:"ignored"`
+		}
+
+		schema, err := redis.SearchSchemaFromStruct(product{}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schema).To(Equal([]*redis.FieldSchema{
+			{FieldName: "title", FieldType: redis.SearchFieldTypeText, Sortable: true, Weight: 2},
+			{FieldName: "tags", FieldType: redis.SearchFieldTypeTag, Seperator: ";"},
+			{FieldName: "price", FieldType: redis.SearchFieldTypeNumeric, NoIndex: true},
+			{FieldName: "vec", FieldType: redis.SearchFieldTypeVector, VectorArgs: &redis.FTVectorArgs{
+				HNSWOptions: &redis.FTHNSWOptions{Type: "FLOAT32", Dim: 4, DistanceMetric: "COSINE", MaxEdgesPerNode: 16},
+			}},
+		}))
+
+		schema, err = redis.SearchSchemaFromStruct(&product{}, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schema).To(HaveLen(5))
+		Expect(schema[1].FieldName).To(Equal("$.tags[*]"))
+		Expect(schema[1].As).To(Equal("tags"))
+		Expect(schema[3].FieldName).To(Equal("$.vec"))
+		Expect(schema[4].FieldName).To(Equal("$.address.city"))
+		Expect(schema[4].As).To(Equal("address_city"))
+
+		_, err = redis.SearchSchemaFromStruct(struct {
+			V []float32 `search:"vector"`
+		}{}, false)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should EnsureIndex", Label("search", "ftcreate", "ftinfo"), func() {
+		type product struct {
+			Title string  `redis:"title" search:"text,sortable"`
+			Price float64 `redis:"price" search:"numeric"`
+		}
+
+		idx, err := redis.NewSearchIndex(client, "idx1", &redis.FTCreateOptions{Prefix: []interface{}{"product:"}}, product{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idx.EnsureIndex(ctx)).NotTo(HaveOccurred())
+		WaitForIndexing(client, "idx1")
+		Expect(idx.EnsureIndex(ctx)).NotTo(HaveOccurred())
+
+		client.HSet(ctx, "product:1", "title", "hello", "price", 10)
+		res, err := client.FTSearch(ctx, "idx1", "hello").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Total).To(BeEquivalentTo(1))
+
+		type other struct {
+			Title string `redis:"title" search:"tag"`
+		}
+		idx, err = redis.NewSearchIndex(client, "idx1", nil, other{})
+		Expect(err).NotTo(HaveOccurred())
+		err = idx.EnsureIndex(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("field title is TEXT, want TAG"))
+		Expect(err.Error()).To(ContainSubstring("field price is not in the schema"))
+	})
+
+	It("should FTEnsureIndex and add the missing fields", Label("search", "ftcreate", "ftalter", "ftinfo"), func() {
+		title := &redis.FieldSchema{FieldName: "title", FieldType: redis.SearchFieldTypeText}
+		price := &redis.FieldSchema{FieldName: "price", FieldType: redis.SearchFieldTypeNumeric}
+
+		def := &redis.FTIndexDefinition{Name: "idx1", Schema: []*redis.FieldSchema{title}}
+		Expect(redis.FTEnsureIndex(ctx, client, def)).NotTo(HaveOccurred())
+		WaitForIndexing(client, "idx1")
+
+		def.Schema = append(def.Schema, price)
+		err := redis.FTEnsureIndex(ctx, client, def)
+		var mismatch *redis.FTSchemaMismatchError
+		Expect(errors.As(err, &mismatch)).To(BeTrue())
+		Expect(mismatch.Diffs).To(Equal([]string{"field price is missing"}))
+
+		def.AddFields = true
+		Expect(redis.FTEnsureIndex(ctx, client, def)).NotTo(HaveOccurred())
+		WaitForIndexing(client, "idx1")
+		def.AddFields = false
+		Expect(redis.FTEnsureIndex(ctx, client, def)).NotTo(HaveOccurred())
+
+		client.HSet(ctx, "doc1", "title", "hello", "price", 10)
+		res, err := client.FTSearch(ctx, "idx1", "@price:[5 15]").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Total).To(BeEquivalentTo(1))
+	})
+
 	It("should FTCreate CaseSensitive", Label("search", "ftcreate"), func() {
 
 		tag1 := &redis.FieldSchema{FieldName: "t", FieldType: redis.SearchFieldTypeTag, CaseSensitive: false}
@@ -905,6 +1137,89 @@ var _ = Describe("RediSearch commands", Label("search"), func() {
 		Expect(res.Docs[0].Fields["__v_score"]).To(BeEquivalentTo("0"))
 	})
 
+	It("should FTVectorSearch KNN and range queries", Label("search", "ftsearch"), func() {
+		flatOptions := &redis.FTFlatOptions{Type: "FLOAT32", Dim: 2, DistanceMetric: "L2"}
+		val, err := client.FTCreate(ctx, "idx1",
+			&redis.FTCreateOptions{},
+			&redis.FieldSchema{FieldName: "v", FieldType: redis.SearchFieldTypeVector, VectorArgs: &redis.FTVectorArgs{FlatOptions: flatOptions}},
+			&redis.FieldSchema{FieldName: "color", FieldType: redis.SearchFieldTypeTag}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "a", "v", redis.EncodeFloat32Vector([]float32{0, 0}), "color", "red")
+		client.HSet(ctx, "b", "v", redis.EncodeFloat32Vector([]float32{1, 0}), "color", "blue")
+		client.HSet(ctx, "c", "v", redis.EncodeFloat32Vector([]float32{3, 0}), "color", "red")
+
+		res, err := client.FTVectorSearch(ctx, "idx1", &redis.FTVectorQuery{
+			Field:  "v",
+			Vector: []float32{0, 0},
+			K:      2,
+		}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Docs).To(HaveLen(2))
+		Expect(res.Docs[0].ID).To(BeEquivalentTo("a"))
+		Expect(res.Docs[0].Distance).To(BeEquivalentTo(0))
+		Expect(res.Docs[1].ID).To(BeEquivalentTo("b"))
+		Expect(res.Docs[1].Distance).To(BeEquivalentTo(1))
+
+		res, err = client.FTVectorSearch(ctx, "idx1", &redis.FTVectorQuery{
+			Field:         "v",
+			Vector:        redis.EncodeFloat32Vector([]float32{0, 0}),
+			K:             2,
+			Filter:        "@color:{red}",
+			DistanceField: "dist",
+			Return:        []redis.FTSearchReturn{{FieldName: "color"}},
+		}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Docs).To(HaveLen(2))
+		Expect(res.Docs[0].ID).To(BeEquivalentTo("a"))
+		Expect(res.Docs[1].ID).To(BeEquivalentTo("c"))
+		Expect(res.Docs[1].Distance).To(BeEquivalentTo(9))
+		Expect(res.Docs[1].Fields["color"]).To(BeEquivalentTo("red"))
+
+		res, err = client.FTVectorSearch(ctx, "idx1", &redis.FTVectorQuery{
+			Field:  "v",
+			Vector: []float32{0, 0},
+			Radius: 2,
+		}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Total).To(BeEquivalentTo(2))
+		Expect(res.Docs[0].ID).To(BeEquivalentTo("a"))
+		Expect(res.Docs[1].ID).To(BeEquivalentTo("b"))
+
+		_, err = client.FTVectorSearch(ctx, "idx1", &redis.FTVectorQuery{Field: "v", Vector: "0,0", K: 2}).Result()
+		Expect(err).To(MatchError("redis: unsupported vector type string"))
+	})
+
+	It("should FTSugAdd, FTSugGet, FTSugDel and FTSugLen", Label("search", "ftsug"), func() {
+		n, err := client.FTSugAdd(ctx, "sug", "hello world", 1).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(1))
+		n, err = client.FTSugAddWithArgs(ctx, "sug", "hello redis", 2, &redis.FTSugAddOptions{Payload: "p1"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(BeEquivalentTo(2))
+		Expect(client.FTSugAddWithArgs(ctx, "sug", "help", 1, &redis.FTSugAddOptions{Incr: true}).Err()).NotTo(HaveOccurred())
+		Expect(client.FTSugLen(ctx, "sug").Val()).To(BeEquivalentTo(3))
+
+		Expect(client.FTSugGet(ctx, "sug", "hel").Val()).To(ConsistOf("hello world", "hello redis", "help"))
+
+		sugs, err := client.FTSugGetWithArgs(ctx, "sug", "hello", &redis.FTSugGetOptions{WithScores: true, WithPayloads: true, Max: 1}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sugs).To(HaveLen(1))
+		Expect(sugs[0].Term).To(Equal("hello redis"))
+		Expect(sugs[0].Score).To(BeNumerically(">", 0))
+		Expect(sugs[0].Payload).To(Equal("p1"))
+
+		sugs, err = client.FTSugGetWithArgs(ctx, "sug", "hellp", &redis.FTSugGetOptions{Fuzzy: true}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sugs).NotTo(BeEmpty())
+
+		Expect(client.FTSugDel(ctx, "sug", "help").Val()).To(BeTrue())
+		Expect(client.FTSugDel(ctx, "sug", "help").Val()).To(BeFalse())
+		Expect(client.FTSugLen(ctx, "sug").Val()).To(BeEquivalentTo(2))
+	})
+
 	It("should FTCreate and FTSearch text params", Label("search", "ftcreate", "ftsearch"), func() {
 		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, &redis.FieldSchema{FieldName: "name", FieldType: redis.SearchFieldTypeText}).Result()
 		Expect(err).NotTo(HaveOccurred())
@@ -923,6 +1238,75 @@ var _ = Describe("RediSearch commands", Label("search"), func() {
 
 	})
 
+	It("should FTSearchWithParams and FTAggregateWithParams", Label("search", "ftsearch", "ftaggregate"), func() {
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{},
+			&redis.FieldSchema{FieldName: "name", FieldType: redis.SearchFieldTypeText},
+			&redis.FieldSchema{FieldName: "numval", FieldType: redis.SearchFieldTypeNumeric}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "doc1", "name", "Alice", "numval", 101)
+		client.HSet(ctx, "doc2", "name", "Bob", "numval", 102)
+		client.HSet(ctx, "doc3", "name", "Carol", "numval", 103)
+
+		// the dialect is raised to 2, which parameters require
+		res1, err := client.FTSearchWithParams(ctx, "idx1", "@name:$name", map[string]interface{}{"name": "Alice"}, nil).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res1.Total).To(BeEquivalentTo(int64(1)))
+		Expect(res1.Docs[0].ID).To(BeEquivalentTo("doc1"))
+
+		res1, err = client.FTSearchWithParams(ctx, "idx1", "@numval:[$min $max]", map[string]interface{}{"min": 102, "max": 103},
+			&redis.FTSearchOptions{SortBy: []redis.FTSearchSortBy{{FieldName: "numval", Asc: true}}}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res1.Total).To(BeEquivalentTo(int64(2)))
+		Expect(res1.Docs[0].ID).To(BeEquivalentTo("doc2"))
+
+		res2, err := client.FTAggregateWithParams(ctx, "idx1", "@numval:[$min +inf]", map[string]interface{}{"min": 103}, nil).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res2.Total).To(BeEquivalentTo(1))
+
+		_, err = client.FTSearchWithParams(ctx, "idx1", "@numval:[$min $max]", map[string]interface{}{"min": 101}, nil).Result()
+		Expect(err).To(MatchError("redis: search query references undefined parameter $max"))
+
+		_, err = client.FTSearchWithParams(ctx, "idx1", "@name:$name", map[string]interface{}{"name": struct{}{}}, nil).Result()
+		Expect(err).To(MatchError(`redis: unsupported type struct {} of search parameter "name"`))
+	})
+
+	It("should FTMigrateIndex and FTAliasSwapSafe", Label("search", "ftcreate", "ftaliasupdate"), func() {
+		client.HSet(ctx, "doc1", "name", "Alice", "age", 30)
+		client.HSet(ctx, "doc2", "name", "Bob", "age", 40)
+
+		text := &redis.FieldSchema{FieldName: "name", FieldType: redis.SearchFieldTypeText}
+		old, err := redis.FTMigrateIndex(ctx, client, "people", "people_v1", &redis.FTCreateOptions{}, []*redis.FieldSchema{text}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(old).To(BeEmpty())
+
+		num := &redis.FieldSchema{FieldName: "age", FieldType: redis.SearchFieldTypeNumeric}
+		old, err = redis.FTMigrateIndex(ctx, client, "people", "people_v2", &redis.FTCreateOptions{}, []*redis.FieldSchema{text, num}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(old).To(BeEquivalentTo("people_v1"))
+
+		target, err := redis.FTAliasTarget(ctx, client, "people")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(BeEquivalentTo("people_v2"))
+		_, err = client.FTInfo(ctx, "people_v1").Result()
+		Expect(err).To(HaveOccurred())
+
+		res, err := client.FTSearch(ctx, "people", "@age:[35 50]").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Total).To(BeEquivalentTo(1))
+		Expect(res.Docs[0].ID).To(BeEquivalentTo("doc2"))
+
+		Expect(client.FTCreate(ctx, "people_v3", &redis.FTCreateOptions{}, text).Err()).NotTo(HaveOccurred())
+		old, err = redis.FTAliasSwapSafe(ctx, client, "people", "people_v3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(old).To(BeEquivalentTo("people_v2"))
+		target, err = redis.FTAliasTarget(ctx, client, "people")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(target).To(BeEquivalentTo("people_v3"))
+	})
+
 	It("should FTCreate and FTSearch numeric params", Label("search", "ftcreate", "ftsearch"), func() {
 		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, &redis.FieldSchema{FieldName: "numval", FieldType: redis.SearchFieldTypeNumeric}).Result()
 		Expect(err).NotTo(HaveOccurred())
@@ -1043,94 +1427,68 @@ var _ = Describe("RediSearch commands", Label("search"), func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(res2.Total).To(BeEquivalentTo(int64(2)))
 	})
-})
 
-// It("should FTProfile Search and Aggregate", Label("search", "ftprofile"), func() {
-// 	val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, &redis.FieldSchema{FieldName: "t", FieldType: redis.SearchFieldTypeText}).Result()
-// 	Expect(err).NotTo(HaveOccurred())
-// 	Expect(val).To(BeEquivalentTo("OK"))
-// 	WaitForIndexing(client, "idx1")
-
-// 	client.HSet(ctx, "1", "t", "hello")
-// 	client.HSet(ctx, "2", "t", "world")
-
-// 	// FTProfile Search
-// 	query := redis.FTSearchQuery("hello|world", &redis.FTSearchOptions{NoContent: true})
-// 	res1, err := client.FTProfile(ctx, "idx1", false, query).Result()
-// 	Expect(err).NotTo(HaveOccurred())
-// 	panic(res1)
-// Expect(len(res1["results"].([]interface{}))).To(BeEquivalentTo(3))
-// resProfile := res1["profile"].(map[interface{}]interface{})
-// Expect(resProfile["Parsing time"].(float64) < 0.5).To(BeTrue())
-// iterProfile0 := resProfile["Iterators profile"].([]interface{})[0].(map[interface{}]interface{})
-// Expect(iterProfile0["Counter"]).To(BeEquivalentTo(2.0))
-// Expect(iterProfile0["Type"]).To(BeEquivalentTo("UNION"))
-
-// // FTProfile Aggregate
-// aggQuery := redis.FTAggregateQuery("*", &redis.FTAggregateOptions{
-// 	Load:  []redis.FTAggregateLoad{{Field: "t"}},
-// 	Apply: []redis.FTAggregateApply{{Field: "startswith(@t, 'hel')", As: "prefix"}}})
-// res2, err := client.FTProfile(ctx, "idx1", false, aggQuery).Result()
-// Expect(err).NotTo(HaveOccurred())
-// Expect(len(res2["results"].([]interface{}))).To(BeEquivalentTo(2))
-// resProfile = res2["profile"].(map[interface{}]interface{})
-// iterProfile0 = resProfile["Iterators profile"].([]interface{})[0].(map[interface{}]interface{})
-// Expect(iterProfile0["Counter"]).To(BeEquivalentTo(2))
-// Expect(iterProfile0["Type"]).To(BeEquivalentTo("WILDCARD"))
-// })
-
-// 	It("should FTProfile Search Limited", Label("search", "ftprofile"), func() {
-// 		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, &redis.FieldSchema{FieldName: "t", FieldType: redis.SearchFieldTypeText}).Result()
-// 		Expect(err).NotTo(HaveOccurred())
-// 		Expect(val).To(BeEquivalentTo("OK"))
-// 		WaitForIndexing(client, "idx1")
-
-// 		client.HSet(ctx, "1", "t", "hello")
-// 		client.HSet(ctx, "2", "t", "hell")
-// 		client.HSet(ctx, "3", "t", "help")
-// 		client.HSet(ctx, "4", "t", "helowa")
-
-// 		// FTProfile Search
-// 		query := redis.FTSearchQuery("%hell% hel*", &redis.FTSearchOptions{})
-// 		res1, err := client.FTProfile(ctx, "idx1", true, query).Result()
-// 		Expect(err).NotTo(HaveOccurred())
-// 		resProfile := res1["profile"].(map[interface{}]interface{})
-// 		iterProfile0 := resProfile["Iterators profile"].([]interface{})[0].(map[interface{}]interface{})
-// 		Expect(iterProfile0["Type"]).To(BeEquivalentTo("INTERSECT"))
-// 		Expect(len(res1["results"].([]interface{}))).To(BeEquivalentTo(3))
-// 		Expect(iterProfile0["Child iterators"].([]interface{})[0].(map[interface{}]interface{})["Child iterators"]).To(BeEquivalentTo("The number of iterators in the union is 3"))
-// 		Expect(iterProfile0["Child iterators"].([]interface{})[1].(map[interface{}]interface{})["Child iterators"]).To(BeEquivalentTo("The number of iterators in the union is 4"))
-// 	})
-
-// 	It("should FTProfile Search query params", Label("search", "ftprofile"), func() {
-// 		hnswOptions := &redis.FTHNSWOptions{Type: "FLOAT32", Dim: 2, DistanceMetric: "L2"}
-// 		val, err := client.FTCreate(ctx, "idx1",
-// 			&redis.FTCreateOptions{},
-// 			&redis.FieldSchema{FieldName: "v", FieldType: redis.SearchFieldTypeVector, VectorArgs: &redis.FTVectorArgs{HNSWOptions: hnswOptions}}).Result()
-// 		Expect(err).NotTo(HaveOccurred())
-// 		Expect(val).To(BeEquivalentTo("OK"))
-// 		WaitForIndexing(client, "idx1")
-
-// 		client.HSet(ctx, "a", "v", "aaaaaaaa")
-// 		client.HSet(ctx, "b", "v", "aaaabaaa")
-// 		client.HSet(ctx, "c", "v", "aaaaabaa")
-
-// 		// FTProfile Search
-// 		searchOptions := &redis.FTSearchOptions{
-// 			Return:         []redis.FTSearchReturn{{FieldName: "__v_score"}},
-// 			SortBy:         []redis.FTSearchSortBy{{FieldName: "__v_score", Asc: true}},
-// 			DialectVersion: 2,
-// 			Params:         map[string]interface{}{"vec": "aaaaaaaa"},
-// 		}
-// 		query := redis.FTSearchQuery("*=>[KNN 2 @v $vec]", searchOptions)
-// 		res1, err := client.FTProfile(ctx, "idx1", false, query).Result()
-// 		Expect(err).NotTo(HaveOccurred())
-// 		resProfile := res1["profile"].(map[interface{}]interface{})
-// 		iterProfile0 := resProfile["Iterators profile"].([]interface{})[0].(map[interface{}]interface{})
-// 		Expect(iterProfile0["Counter"]).To(BeEquivalentTo(2))
-// 		Expect(iterProfile0["Type"]).To(BeEquivalentTo(redis.SearchFieldTypeVector.String()))
-// 		Expect(res1["total_results"]).To(BeEquivalentTo(2))
-// 		results0 := res1["results"].([]interface{})[0].(map[interface{}]interface{})
-// 		Expect(results0["id"]).To(BeEquivalentTo("a"))
-// 		Expect(results0["extra_attributes"].(map[interface{}]interface{})["__v_score"]).To(BeEquivalentTo("0"))
-// 	})
+	It("should FTProfile Search and Aggregate", Label("search", "ftprofile"), func() {
+		val, err := client.FTCreate(ctx, "idx1", &redis.FTCreateOptions{}, &redis.FieldSchema{FieldName: "t", FieldType: redis.SearchFieldTypeText}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "1", "t", "hello")
+		client.HSet(ctx, "2", "t", "world")
+
+		// FTProfile Search
+		query := redis.FTSearchQuery("hello|world", &redis.FTSearchOptions{NoContent: true})
+		res1, err := client.FTProfile(ctx, "idx1", false, query).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res1.Results).To(HaveLen(3))
+		Expect(res1.Shards).NotTo(BeEmpty())
+		Expect(res1.Profile.ParsingTime).To(BeNumerically("<", 0.5))
+		Expect(res1.Profile.IteratorsProfile).To(HaveLen(1))
+		iterProfile0 := res1.Profile.IteratorsProfile[0]
+		Expect(iterProfile0.Type).To(BeEquivalentTo("UNION"))
+		Expect(iterProfile0.Counter).To(BeEquivalentTo(2))
+		Expect(iterProfile0.ChildIterators).To(HaveLen(2))
+		Expect(iterProfile0.ChildIterators[0].Type).To(BeEquivalentTo("TEXT"))
+		Expect(res1.Profile.ResultProcessorsProfile).NotTo(BeEmpty())
+		Expect(res1.Profile.ResultProcessorsProfile[0].Type).To(BeEquivalentTo("Index"))
+
+		// FTProfile Aggregate
+		aggQuery := redis.FTAggregateQuery("*", &redis.FTAggregateOptions{
+			Load:  []redis.FTAggregateLoad{{Field: "t"}},
+			Apply: []redis.FTAggregateApply{{Field: "startswith(@t, 'hel')", As: "prefix"}}})
+		res2, err := client.FTProfile(ctx, "idx1", false, aggQuery).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res2.Profile.IteratorsProfile).To(HaveLen(1))
+		Expect(res2.Profile.IteratorsProfile[0].Type).To(BeEquivalentTo("WILDCARD"))
+		Expect(res2.Profile.IteratorsProfile[0].Counter).To(BeEquivalentTo(2))
+	})
+
+	It("should FTProfile Search query params", Label("search", "ftprofile"), func() {
+		hnswOptions := &redis.FTHNSWOptions{Type: "FLOAT32", Dim: 2, DistanceMetric: "L2"}
+		val, err := client.FTCreate(ctx, "idx1",
+			&redis.FTCreateOptions{},
+			&redis.FieldSchema{FieldName: "v", FieldType: redis.SearchFieldTypeVector, VectorArgs: &redis.FTVectorArgs{HNSWOptions: hnswOptions}}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(val).To(BeEquivalentTo("OK"))
+		WaitForIndexing(client, "idx1")
+
+		client.HSet(ctx, "a", "v", "aaaaaaaa")
+		client.HSet(ctx, "b", "v", "aaaabaaa")
+		client.HSet(ctx, "c", "v", "aaaaabaa")
+
+		// FTProfile Search
+		searchOptions := &redis.FTSearchOptions{
+			Return:         []redis.FTSearchReturn{{FieldName: "__v_score"}},
+			SortBy:         []redis.FTSearchSortBy{{FieldName: "__v_score", Asc: true}},
+			DialectVersion: 2,
+			Params:         map[string]interface{}{"vec": "aaaaaaaa"},
+		}
+		query := redis.FTSearchQuery("*=>[KNN 2 @v $vec]", searchOptions)
+		res1, err := client.FTProfile(ctx, "idx1", false, query).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res1.Profile.IteratorsProfile).To(HaveLen(1))
+		Expect(res1.Profile.IteratorsProfile[0].Type).To(BeEquivalentTo(redis.SearchFieldTypeVector.String()))
+		Expect(res1.Profile.IteratorsProfile[0].Counter).To(BeEquivalentTo(2))
+	})
+})
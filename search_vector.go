@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// EncodeFloat32Vector encodes v as the little-endian blob expected by FLOAT32 vector fields.
+func EncodeFloat32Vector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b
+}
+
+// EncodeFloat64Vector encodes v as the little-endian blob expected by FLOAT64 vector fields.
+func EncodeFloat64Vector(v []float64) []byte {
+	b := make([]byte, 8*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint64(b[8*i:], math.Float64bits(f))
+	}
+	return b
+}
+
+// DecodeFloat32Vector decodes a FLOAT32 vector blob, e.g. read from a hash field.
+func DecodeFloat32Vector(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("redis: FLOAT32 vector blob has invalid length %d", len(b))
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v, nil
+}
+
+// DecodeFloat64Vector decodes a FLOAT64 vector blob, e.g. read from a hash field.
+func DecodeFloat64Vector(b []byte) ([]float64, error) {
+	if len(b)%8 != 0 {
+		return nil, fmt.Errorf("redis: FLOAT64 vector blob has invalid length %d", len(b))
+	}
+	v := make([]float64, len(b)/8)
+	for i := range v {
+		v[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return v, nil
+}
+
+//------------------------------------------------------------------------------
+
+// FTVectorQuery is a vector similarity query run by FTVectorSearch.
+// It is a KNN query returning the K nearest neighbours of Vector,
+// or a range query returning the vectors within Radius when Radius is set.
+type FTVectorQuery struct {
+	// Field is the name or the alias of the vector field.
+	Field string
+	// Vector is the query vector: a []float32, a []float64 or an encoded []byte blob.
+	Vector interface{}
+
+	K      int
+	Radius float64
+
+	// Filter restricts the documents compared to Vector. Default is all documents.
+	Filter string
+	// Params are the parameters referenced by Filter.
+	Params map[string]interface{}
+
+	// DistanceField is the name the distance to Vector is returned as.
+	// Default is "__<Field>_score".
+	DistanceField string
+
+	// EFRuntime overrides the EF_RUNTIME of HNSW fields for KNN queries.
+	EFRuntime int
+	// Epsilon overrides the EPSILON of range queries.
+	Epsilon float64
+
+	// Return restricts the returned fields. The distance is always returned.
+	Return []FTSearchReturn
+	// Limit is the number of documents returned by range queries. Default is 10.
+	Limit int
+	// DialectVersion of the query. Default is 2, the minimum for vector queries.
+	DialectVersion int
+}
+
+func (q *FTVectorQuery) distanceField() string {
+	if q.DistanceField != "" {
+		return q.DistanceField
+	}
+	return "__" + q.Field + "_score"
+}
+
+func (q *FTVectorQuery) blob() ([]byte, error) {
+	switch v := q.Vector.(type) {
+	case []float32:
+		return EncodeFloat32Vector(v), nil
+	case []float64:
+		return EncodeFloat64Vector(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("redis: unsupported vector type %T", q.Vector)
+	}
+}
+
+// build returns the query string and the options of FT.SEARCH.
+func (q *FTVectorQuery) build() (string, *FTSearchOptions, error) {
+	if q.Field == "" {
+		return "", nil, fmt.Errorf("redis: vector query requires a Field")
+	}
+	blob, err := q.blob()
+	if err != nil {
+		return "", nil, err
+	}
+
+	dist := q.distanceField()
+	options := &FTSearchOptions{
+		SortBy:         []FTSearchSortBy{{FieldName: dist, Asc: true}},
+		DialectVersion: paramsDialect(q.DialectVersion),
+		Params:         make(map[string]interface{}, len(q.Params)+4),
+	}
+	for k, v := range q.Params {
+		options.Params[k] = v
+	}
+	options.Params["vec_blob"] = blob
+	if q.Return != nil {
+		options.Return = append(append([]FTSearchReturn{}, q.Return...), FTSearchReturn{FieldName: dist})
+	}
+
+	filter := q.Filter
+	if filter == "" {
+		filter = "*"
+	}
+
+	var query string
+	if q.Radius > 0 {
+		options.Params["vec_radius"] = q.Radius
+		attrs := "$YIELD_DISTANCE_AS: " + dist
+		if q.Epsilon > 0 {
+			attrs += "; $EPSILON: " + strconv.FormatFloat(q.Epsilon, 'f', -1, 64)
+		}
+		query = "@" + q.Field + ":[VECTOR_RANGE $vec_radius $vec_blob]=>{" + attrs + "}"
+		if filter != "*" {
+			query = "(" + filter + ") " + query
+		}
+		options.Limit = q.Limit
+		if options.Limit == 0 {
+			options.Limit = 10
+		}
+	} else {
+		if q.K <= 0 {
+			return "", nil, fmt.Errorf("redis: vector query requires K or Radius")
+		}
+		options.Params["vec_k"] = q.K
+		knn := "KNN $vec_k @" + q.Field + " $vec_blob"
+		if q.EFRuntime > 0 {
+			options.Params["vec_ef"] = q.EFRuntime
+			knn += " EF_RUNTIME $vec_ef"
+		}
+		if filter != "*" {
+			filter = "(" + filter + ")"
+		}
+		query = filter + "=>[" + knn + " AS " + dist + "]"
+		options.Limit = q.K
+	}
+
+	if options.Params, err = FTBindParams(query, options.Params); err != nil {
+		return "", nil, err
+	}
+	return query, options, nil
+}
+
+// FTVectorSearch runs the vector similarity query q on index. The vector is
+// encoded and bound as a PARAMS blob, and the documents are sorted by distance.
+// For more information, please refer to the Redis documentation:
+// [Vector search]: (https://redis.io/docs/interact/search-and-query/advanced-concepts/vectors/)
+func (c cmdable) FTVectorSearch(ctx context.Context, index string, q *FTVectorQuery) *FTVectorSearchCmd {
+	query, options, err := q.build()
+	if err != nil {
+		cmd := newFTVectorSearchCmd(ctx, &FTSearchOptions{}, "", "FT.SEARCH", index)
+		cmd.SetErr(err)
+		return cmd
+	}
+
+	args := append([]interface{}{"FT.SEARCH", index}, FTSearchQuery(query, options)...)
+	cmd := newFTVectorSearchCmd(ctx, options, q.distanceField(), args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+type FTVectorSearchResult struct {
+	Total int
+	Docs  []VectorDocument
+}
+
+// VectorDocument is a document returned by a vector query with its distance to the query vector.
+type VectorDocument struct {
+	Document
+	Distance float64
+}
+
+type FTVectorSearchCmd struct {
+	FTSearchCmd
+
+	distanceField string
+	vectorVal     FTVectorSearchResult
+}
+
+var _ Cmder = (*FTVectorSearchCmd)(nil)
+
+func newFTVectorSearchCmd(ctx context.Context, options *FTSearchOptions, distanceField string, args ...interface{}) *FTVectorSearchCmd {
+	return &FTVectorSearchCmd{
+		FTSearchCmd:   *newFTSearchCmd(ctx, options, args...),
+		distanceField: distanceField,
+	}
+}
+
+func (cmd *FTVectorSearchCmd) SetVal(val FTVectorSearchResult) {
+	cmd.vectorVal = val
+}
+
+func (cmd *FTVectorSearchCmd) Val() FTVectorSearchResult {
+	return cmd.vectorVal
+}
+
+func (cmd *FTVectorSearchCmd) Result() (FTVectorSearchResult, error) {
+	return cmd.vectorVal, cmd.err
+}
+
+func (cmd *FTVectorSearchCmd) readReply(rd *proto.Reader) error {
+	if err := cmd.FTSearchCmd.readReply(rd); err != nil || cmd.err != nil {
+		return err
+	}
+
+	res := FTVectorSearchResult{
+		Total: cmd.FTSearchCmd.val.Total,
+		Docs:  make([]VectorDocument, len(cmd.FTSearchCmd.val.Docs)),
+	}
+	for i, doc := range cmd.FTSearchCmd.val.Docs {
+		res.Docs[i].Document = doc
+		if s, ok := doc.Fields[cmd.distanceField]; ok {
+			dist, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				cmd.err = fmt.Errorf("invalid vector distance format")
+				return nil
+			}
+			res.Docs[i].Distance = dist
+		}
+	}
+	cmd.vectorVal = res
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
 	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -41,6 +44,10 @@ type FailoverOptions struct {
 	// Allows routing read-only commands to the random master or replica node.
 	// This option only works with NewFailoverClusterClient.
 	RouteRandomly bool
+	// Optional function choosing the master or replica node which read-only
+	// commands are routed to, see ClusterOptions.RouteReadsFunc.
+	// This option only works with NewFailoverClusterClient.
+	RouteReadsFunc func(nodes []NodeInfo) NodeInfo
 
 	// Route all commands to replica read-only nodes.
 	ReplicaOnly bool
@@ -54,6 +61,22 @@ type FailoverOptions struct {
 	Dialer    func(ctx context.Context, network, addr string) (net.Conn, error)
 	OnConnect func(ctx context.Context, cn *Conn) error
 
+	// OnConnectionEvent is called with the events of the connections, see
+	// Options.OnConnectionEvent.
+	OnConnectionEvent func(ev ConnEvent)
+	// OnConnectionUse is called with the events of the connections taken from
+	// the pools and given back to them, see Options.OnConnectionUse.
+	OnConnectionUse func(ev ConnEvent)
+	// Logger logs the messages of the clients, see Options.Logger.
+	Logger Logger
+	// CircuitBreaker enables a circuit breaker for each node, see
+	// Options.CircuitBreaker.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// OnPush is called with the push notifications received on the
+	// connections of each node, see Options.OnPush.
+	OnPush func(ctx context.Context, msg PushMessage)
+
 	Protocol int
 	Username string
 	Password string
@@ -68,6 +91,31 @@ type FailoverOptions struct {
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
 
+	// StatsEnabled enables the statistics of the commands returned by Stats.
+	StatsEnabled bool
+
+	// AutoPipeline batches the commands issued concurrently into pipelines,
+	// see Options.AutoPipeline.
+	AutoPipeline bool
+	// AutoPipelineMaxSize is the maximum number of commands of the pipelines
+	// of AutoPipeline. Default is 100 commands.
+	AutoPipelineMaxSize int
+
+	// PipelineMaxCmds and PipelineMaxBytes split the pipelines in batches,
+	// see Options.PipelineMaxCmds.
+	PipelineMaxCmds  int
+	PipelineMaxBytes int
+
+	// HedgeDelay enables the hedging of the read commands on another
+	// connection of their node, see Options.HedgeDelay.
+	HedgeDelay time.Duration
+	// HedgeCommands are the names of the commands hedged with HedgeDelay.
+	HedgeCommands []string
+
+	// DryRun marshals and logs the commands instead of sending them to the
+	// master or replicas, see Options.DryRun.
+	DryRun bool
+
 	PoolFIFO bool
 
 	PoolSize        int
@@ -77,11 +125,180 @@ type FailoverOptions struct {
 	MaxActiveConns  int
 	ConnMaxIdleTime time.Duration
 	ConnMaxLifetime time.Duration
+	ReadBufferSize  int
+	WriteBufferSize int
+	StringInterner  StringInterner
 
-	TLSConfig *tls.Config
+	// AdaptivePoolSize adjusts the size of the pools to their load, see
+	// Options.AdaptivePoolSize.
+	AdaptivePoolSize bool
+
+	TLSConfig           *tls.Config
+	TLSFiles            *TLSFiles
+	TLSVerifyConnection func(cs tls.ConnectionState) error
+	TLSServerSPIFFEIDs  []string
 
 	DisableIndentity bool
 	IdentitySuffix   string
+
+	JSONCodec JSONCodec
+}
+
+// ParseFailoverURL parses a URL into FailoverOptions that can be used to connect
+// to the master of Redis Sentinel. The URL must be in the form:
+//
+//	redis-sentinel://<user>:<password>@<host>:<port>/<master_name>/<db_number>
+//	or
+//	rediss-sentinel://<user>:<password>@<host>:<port>/<master_name>/<db_number>
+//
+// The redis+sentinel and rediss+sentinel schemes are the same.
+//
+// The host is the address of a sentinel, 26379 by default; more sentinels are
+// added with the query parameter "addr", and the user and the password are
+// those of the master and replicas. The sentinels use the query parameters
+// "sentinel_username" and "sentinel_password".
+//
+// The other fields of FailoverOptions are set using query parameters, as with
+// ParseURL, e.g. master_name, replica_only or route_by_latency.
+//
+// Example:
+//
+//	redis-sentinel://:password@localhost:26379/mymaster/1?addr=localhost:26380&replica_only=true
+//	is equivalent to:
+//	&FailoverOptions{
+//		MasterName:    "mymaster",
+//		SentinelAddrs: ["localhost:26379", "localhost:26380"],
+//		Password:      "password",
+//		DB:            1,
+//		ReplicaOnly:   true,
+//	}
+func ParseFailoverURL(redisURL string) (*FailoverOptions, error) {
+	u, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return parseFailoverURL(u, nil)
+}
+
+// parseFailoverURL parses u into FailoverOptions; names are the names of the
+// query parameters in the errors, see queryOptions.
+func parseFailoverURL(u *url.URL, names map[string]string) (*FailoverOptions, error) {
+	o := &FailoverOptions{}
+	switch u.Scheme {
+	case "rediss-sentinel", "rediss+sentinel":
+		o.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	case "redis-sentinel", "redis+sentinel":
+	default:
+		return nil, fmt.Errorf("redis: invalid URL scheme: %s", u.Scheme)
+	}
+	o.Username, o.Password = getUserPassword(u)
+
+	if u.Host != "" {
+		h, p, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			h = u.Host
+		}
+		if p == "" {
+			p = "26379"
+		}
+		o.SentinelAddrs = append(o.SentinelAddrs, net.JoinHostPort(h, p))
+	}
+
+	f := strings.FieldsFunc(u.Path, func(r rune) bool {
+		return r == '/'
+	})
+	switch len(f) {
+	case 0:
+	case 2:
+		var err error
+		if o.DB, err = strconv.Atoi(f[1]); err != nil {
+			return nil, fmt.Errorf("redis: invalid database number: %q", f[1])
+		}
+		fallthrough
+	case 1:
+		o.MasterName = f[0]
+	default:
+		return nil, fmt.Errorf("redis: invalid URL path: %s", u.Path)
+	}
+
+	return setupFailoverQueryParams(u, o, names)
+}
+
+// setupFailoverQueryParams converts query parameters in u to option value in o.
+func setupFailoverQueryParams(u *url.URL, o *FailoverOptions, names map[string]string) (*FailoverOptions, error) {
+	q := queryOptions{q: u.Query(), names: names}
+
+	if tmp := q.string("db"); tmp != "" {
+		db, err := strconv.Atoi(tmp)
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid database number: %w", err)
+		}
+		o.DB = db
+	}
+	if q.has("master_name") {
+		o.MasterName = q.string("master_name")
+	}
+	o.SentinelUsername = q.string("sentinel_username")
+	o.SentinelPassword = q.string("sentinel_password")
+	o.ClientName = q.string("client_name")
+	o.RouteByLatency = q.bool("route_by_latency")
+	o.RouteRandomly = q.bool("route_randomly")
+	o.ReplicaOnly = q.bool("replica_only")
+	o.UseDisconnectedReplicas = q.bool("use_disconnected_replicas")
+	o.Protocol = q.int("protocol")
+	o.MaxRetries = q.int("max_retries")
+	o.MinRetryBackoff = q.duration("min_retry_backoff")
+	o.MaxRetryBackoff = q.duration("max_retry_backoff")
+	o.DialTimeout = q.duration("dial_timeout")
+	o.ReadTimeout = q.duration("read_timeout")
+	o.WriteTimeout = q.duration("write_timeout")
+	o.ContextTimeoutEnabled = q.bool("context_timeout_enabled")
+	o.PoolFIFO = q.bool("pool_fifo")
+	o.PoolSize = q.int("pool_size")
+	o.AdaptivePoolSize = q.bool("adaptive_pool_size")
+	o.PoolTimeout = q.duration("pool_timeout")
+	o.MinIdleConns = q.int("min_idle_conns")
+	o.MaxIdleConns = q.int("max_idle_conns")
+	o.MaxActiveConns = q.int("max_active_conns")
+	o.ReadBufferSize = q.int("read_buffer_size")
+	o.WriteBufferSize = q.int("write_buffer_size")
+	o.ConnMaxIdleTime = q.duration("conn_max_idle_time")
+	o.ConnMaxLifetime = q.duration("conn_max_lifetime")
+	o.DisableIndentity = q.disableIdentity()
+	o.IdentitySuffix = q.string("identity_suffix")
+	o.StatsEnabled = q.bool("stats_enabled")
+	o.AutoPipeline = q.bool("auto_pipeline")
+	o.AutoPipelineMaxSize = q.int("auto_pipeline_max_size")
+	o.PipelineMaxCmds = q.int("pipeline_max_cmds")
+	o.PipelineMaxBytes = q.int("pipeline_max_bytes")
+	o.HedgeDelay = q.duration("hedge_delay")
+	o.DryRun = q.bool("dry_run")
+	q.tls(o.TLSConfig)
+
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	for _, addr := range q.strings("addr") {
+		h, p, err := net.SplitHostPort(addr)
+		if err != nil || h == "" || p == "" {
+			return nil, fmt.Errorf("redis: unable to parse addr param: %s", addr)
+		}
+		o.SentinelAddrs = append(o.SentinelAddrs, net.JoinHostPort(h, p))
+	}
+	if len(o.SentinelAddrs) == 0 {
+		return nil, errors.New("redis: no sentinel address")
+	}
+	if o.MasterName == "" {
+		return nil, errors.New("redis: no master name")
+	}
+
+	// any parameters left?
+	if r := q.remaining(); len(r) > 0 {
+		return nil, fmt.Errorf("redis: unexpected option: %s", strings.Join(r, ", "))
+	}
+
+	return o, nil
 }
 
 func (opt *FailoverOptions) clientOptions() *Options {
@@ -89,8 +306,13 @@ func (opt *FailoverOptions) clientOptions() *Options {
 		Addr:       "FailoverClient",
 		ClientName: opt.ClientName,
 
-		Dialer:    opt.Dialer,
-		OnConnect: opt.OnConnect,
+		Dialer:            opt.Dialer,
+		OnConnect:         opt.OnConnect,
+		OnConnectionEvent: opt.OnConnectionEvent,
+		OnConnectionUse:   opt.OnConnectionUse,
+		Logger:            opt.Logger,
+		CircuitBreaker:    opt.CircuitBreaker,
+		OnPush:            opt.OnPush,
 
 		DB:       opt.DB,
 		Protocol: opt.Protocol,
@@ -105,6 +327,14 @@ func (opt *FailoverOptions) clientOptions() *Options {
 		ReadTimeout:           opt.ReadTimeout,
 		WriteTimeout:          opt.WriteTimeout,
 		ContextTimeoutEnabled: opt.ContextTimeoutEnabled,
+		StatsEnabled:          opt.StatsEnabled,
+		AutoPipeline:          opt.AutoPipeline,
+		AutoPipelineMaxSize:   opt.AutoPipelineMaxSize,
+		PipelineMaxCmds:       opt.PipelineMaxCmds,
+		PipelineMaxBytes:      opt.PipelineMaxBytes,
+		HedgeDelay:            opt.HedgeDelay,
+		HedgeCommands:         opt.HedgeCommands,
+		DryRun:                opt.DryRun,
 
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
@@ -114,11 +344,21 @@ func (opt *FailoverOptions) clientOptions() *Options {
 		MaxActiveConns:  opt.MaxActiveConns,
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
+		ReadBufferSize:  opt.ReadBufferSize,
+		WriteBufferSize: opt.WriteBufferSize,
+		StringInterner:  opt.StringInterner,
+
+		AdaptivePoolSize: opt.AdaptivePoolSize,
 
-		TLSConfig: opt.TLSConfig,
+		TLSConfig:           opt.TLSConfig,
+		TLSFiles:            opt.TLSFiles,
+		TLSVerifyConnection: opt.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  opt.TLSServerSPIFFEIDs,
 
 		DisableIndentity: opt.DisableIndentity,
 		IdentitySuffix:   opt.IdentitySuffix,
+
+		JSONCodec: opt.JSONCodec,
 	}
 }
 
@@ -127,8 +367,11 @@ func (opt *FailoverOptions) sentinelOptions(addr string) *Options {
 		Addr:       addr,
 		ClientName: opt.ClientName,
 
-		Dialer:    opt.Dialer,
-		OnConnect: opt.OnConnect,
+		Dialer:            opt.Dialer,
+		OnConnect:         opt.OnConnect,
+		OnConnectionEvent: opt.OnConnectionEvent,
+		OnConnectionUse:   opt.OnConnectionUse,
+		Logger:            opt.Logger,
 
 		DB:       0,
 		Username: opt.SentinelUsername,
@@ -151,8 +394,16 @@ func (opt *FailoverOptions) sentinelOptions(addr string) *Options {
 		MaxActiveConns:  opt.MaxActiveConns,
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
+		ReadBufferSize:  opt.ReadBufferSize,
+		WriteBufferSize: opt.WriteBufferSize,
+		StringInterner:  opt.StringInterner,
+
+		AdaptivePoolSize: opt.AdaptivePoolSize,
 
-		TLSConfig: opt.TLSConfig,
+		TLSConfig:           opt.TLSConfig,
+		TLSFiles:            opt.TLSFiles,
+		TLSVerifyConnection: opt.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  opt.TLSServerSPIFFEIDs,
 
 		DisableIndentity: opt.DisableIndentity,
 		IdentitySuffix:   opt.IdentitySuffix,
@@ -163,8 +414,13 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 	return &ClusterOptions{
 		ClientName: opt.ClientName,
 
-		Dialer:    opt.Dialer,
-		OnConnect: opt.OnConnect,
+		Dialer:            opt.Dialer,
+		OnConnect:         opt.OnConnect,
+		OnConnectionEvent: opt.OnConnectionEvent,
+		OnConnectionUse:   opt.OnConnectionUse,
+		Logger:            opt.Logger,
+		CircuitBreaker:    opt.CircuitBreaker,
+		OnPush:            opt.OnPush,
 
 		Protocol: opt.Protocol,
 		Username: opt.Username,
@@ -174,6 +430,7 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 
 		RouteByLatency: opt.RouteByLatency,
 		RouteRandomly:  opt.RouteRandomly,
+		RouteReadsFunc: opt.RouteReadsFunc,
 
 		MinRetryBackoff: opt.MinRetryBackoff,
 		MaxRetryBackoff: opt.MaxRetryBackoff,
@@ -182,6 +439,14 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 		ReadTimeout:           opt.ReadTimeout,
 		WriteTimeout:          opt.WriteTimeout,
 		ContextTimeoutEnabled: opt.ContextTimeoutEnabled,
+		StatsEnabled:          opt.StatsEnabled,
+		AutoPipeline:          opt.AutoPipeline,
+		AutoPipelineMaxSize:   opt.AutoPipelineMaxSize,
+		PipelineMaxCmds:       opt.PipelineMaxCmds,
+		PipelineMaxBytes:      opt.PipelineMaxBytes,
+		HedgeDelay:            opt.HedgeDelay,
+		HedgeCommands:         opt.HedgeCommands,
+		DryRun:                opt.DryRun,
 
 		PoolFIFO:        opt.PoolFIFO,
 		PoolSize:        opt.PoolSize,
@@ -191,8 +456,16 @@ func (opt *FailoverOptions) clusterOptions() *ClusterOptions {
 		MaxActiveConns:  opt.MaxActiveConns,
 		ConnMaxIdleTime: opt.ConnMaxIdleTime,
 		ConnMaxLifetime: opt.ConnMaxLifetime,
+		ReadBufferSize:  opt.ReadBufferSize,
+		WriteBufferSize: opt.WriteBufferSize,
+		StringInterner:  opt.StringInterner,
+
+		AdaptivePoolSize: opt.AdaptivePoolSize,
 
-		TLSConfig: opt.TLSConfig,
+		TLSConfig:           opt.TLSConfig,
+		TLSFiles:            opt.TLSFiles,
+		TLSVerifyConnection: opt.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  opt.TLSServerSPIFFEIDs,
 
 		DisableIndentity: opt.DisableIndentity,
 		IdentitySuffix:   opt.IdentitySuffix,
@@ -209,6 +482,9 @@ func NewFailoverClient(failoverOpt *FailoverOptions) *Client {
 	if failoverOpt.RouteRandomly {
 		panic("to route commands randomly, use NewFailoverClusterClient")
 	}
+	if failoverOpt.RouteReadsFunc != nil {
+		panic("to route commands with RouteReadsFunc, use NewFailoverClusterClient")
+	}
 
 	sentinelAddrs := make([]string, len(failoverOpt.SentinelAddrs))
 	copy(sentinelAddrs, failoverOpt.SentinelAddrs)
@@ -235,9 +511,24 @@ func NewFailoverClient(failoverOpt *FailoverOptions) *Client {
 	}
 	rdb.init()
 
+	if opt.StatsEnabled {
+		rdb.stats = newCmdsStats()
+	}
+	if opt.AutoPipeline {
+		rdb.auto = newAutoPipeliner(rdb.baseClient)
+	}
+	rdb.hedgedCmds = newHedgedCmds(opt)
+	rdb.breaker = newCircuitBreaker(opt.CircuitBreaker, rdb.getAddr)
+	if opt.pushNotifications() {
+		rdb.pushes = newPushProcessor()
+		rdb.pushes.onPush = opt.OnPush
+	}
 	connPool = newConnPool(opt, rdb.dialHook)
 	rdb.connPool = connPool
 	rdb.onClose = failover.Close
+	if opt.TLSFiles != nil {
+		rdb.watchTLSFiles()
+	}
 
 	failover.mu.Lock()
 	failover.onFailover = func(ctx context.Context, addr string) {
@@ -253,6 +544,13 @@ func NewFailoverClient(failoverOpt *FailoverOptions) *Client {
 func masterReplicaDialer(
 	failover *sentinelFailover,
 ) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	tlsConfig := newTLSConfig(tlsOptions{
+		config:           failover.opt.TLSConfig,
+		files:            failover.opt.TLSFiles,
+		verifyConnection: failover.opt.TLSVerifyConnection,
+		spiffeIDs:        failover.opt.TLSServerSPIFFEIDs,
+		logger:           failover.opt.Logger,
+	})
 	return func(ctx context.Context, network, _ string) (net.Conn, error) {
 		var addr string
 		var err error
@@ -276,10 +574,10 @@ func masterReplicaDialer(
 			Timeout:   failover.opt.DialTimeout,
 			KeepAlive: 5 * time.Minute,
 		}
-		if failover.opt.TLSConfig == nil {
+		if tlsConfig == nil {
 			return netDialer.DialContext(ctx, network, addr)
 		}
-		return tls.DialWithDialer(netDialer, network, addr, failover.opt.TLSConfig)
+		return tls.DialWithDialer(netDialer, network, addr, tlsConfig)
 	}
 }
 
@@ -521,8 +819,8 @@ func (c *sentinelFailover) MasterAddr(ctx context.Context) (string, error) {
 				return "", err
 			}
 			// Continue on other errors
-			internal.Logger.Printf(ctx, "sentinel: GetMasterAddrByName name=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: GetMasterAddrByName failed",
+				"master", c.opt.MasterName, "error", err)
 		} else {
 			return addr, nil
 		}
@@ -539,8 +837,8 @@ func (c *sentinelFailover) MasterAddr(ctx context.Context) (string, error) {
 				return "", err
 			}
 			// Continue on other errors
-			internal.Logger.Printf(ctx, "sentinel: GetMasterAddrByName name=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: GetMasterAddrByName failed",
+				"master", c.opt.MasterName, "error", err)
 		} else {
 			return addr, nil
 		}
@@ -555,8 +853,8 @@ func (c *sentinelFailover) MasterAddr(ctx context.Context) (string, error) {
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return "", err
 			}
-			internal.Logger.Printf(ctx, "sentinel: GetMasterAddrByName master=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: GetMasterAddrByName failed",
+				"master", c.opt.MasterName, "error", err)
 			continue
 		}
 
@@ -583,8 +881,8 @@ func (c *sentinelFailover) replicaAddrs(ctx context.Context, useDisconnected boo
 				return nil, err
 			}
 			// Continue on other errors
-			internal.Logger.Printf(ctx, "sentinel: Replicas name=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: Replicas failed",
+				"master", c.opt.MasterName, "error", err)
 		} else if len(addrs) > 0 {
 			return addrs, nil
 		}
@@ -601,8 +899,8 @@ func (c *sentinelFailover) replicaAddrs(ctx context.Context, useDisconnected boo
 				return nil, err
 			}
 			// Continue on other errors
-			internal.Logger.Printf(ctx, "sentinel: Replicas name=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: Replicas failed",
+				"master", c.opt.MasterName, "error", err)
 		} else if len(addrs) > 0 {
 			return addrs, nil
 		} else {
@@ -622,8 +920,8 @@ func (c *sentinelFailover) replicaAddrs(ctx context.Context, useDisconnected boo
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return nil, err
 			}
-			internal.Logger.Printf(ctx, "sentinel: Replicas master=%q failed: %s",
-				c.opt.MasterName, err)
+			internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: Replicas failed",
+				"master", c.opt.MasterName, "error", err)
 			continue
 		}
 		sentinelReachable = true
@@ -655,8 +953,8 @@ func (c *sentinelFailover) getMasterAddr(ctx context.Context, sentinel *Sentinel
 func (c *sentinelFailover) getReplicaAddrs(ctx context.Context, sentinel *SentinelClient) ([]string, error) {
 	addrs, err := sentinel.Replicas(ctx, c.opt.MasterName).Result()
 	if err != nil {
-		internal.Logger.Printf(ctx, "sentinel: Replicas name=%q failed: %s",
-			c.opt.MasterName, err)
+		internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: Replicas failed",
+			"master", c.opt.MasterName, "error", err)
 		return nil, err
 	}
 	return parseReplicaAddrs(addrs, false), nil
@@ -703,8 +1001,8 @@ func (c *sentinelFailover) trySwitchMaster(ctx context.Context, addr string) {
 	}
 	c._masterAddr = addr
 
-	internal.Logger.Printf(ctx, "sentinel: new master=%q addr=%q",
-		c.opt.MasterName, addr)
+	internal.Log(ctx, c.opt.Logger, internal.LevelInfo, "sentinel: new master",
+		"master", c.opt.MasterName, "addr", addr)
 	if c.onFailover != nil {
 		c.onFailover(ctx, addr)
 	}
@@ -724,7 +1022,8 @@ func (c *sentinelFailover) setSentinel(ctx context.Context, sentinel *SentinelCl
 func (c *sentinelFailover) discoverSentinels(ctx context.Context) {
 	sentinels, err := c.sentinel.Sentinels(ctx, c.opt.MasterName).Result()
 	if err != nil {
-		internal.Logger.Printf(ctx, "sentinel: Sentinels master=%q failed: %s", c.opt.MasterName, err)
+		internal.Log(ctx, c.opt.Logger, internal.LevelWarn, "sentinel: Sentinels failed",
+			"master", c.opt.MasterName, "error", err)
 		return
 	}
 	for _, sentinel := range sentinels {
@@ -739,8 +1038,8 @@ func (c *sentinelFailover) discoverSentinels(ctx context.Context) {
 		if ip != "" && port != "" {
 			sentinelAddr := net.JoinHostPort(ip, port)
 			if !contains(c.sentinelAddrs, sentinelAddr) {
-				internal.Logger.Printf(ctx, "sentinel: discovered new sentinel=%q for master=%q",
-					sentinelAddr, c.opt.MasterName)
+				internal.Log(ctx, c.opt.Logger, internal.LevelInfo, "sentinel: discovered new sentinel",
+					"sentinel", sentinelAddr, "master", c.opt.MasterName)
 				c.sentinelAddrs = append(c.sentinelAddrs, sentinelAddr)
 			}
 		}
@@ -759,7 +1058,8 @@ func (c *sentinelFailover) listen(pubsub *PubSub) {
 		if msg.Channel == "+switch-master" {
 			parts := strings.Split(msg.Payload, " ")
 			if parts[0] != c.opt.MasterName {
-				internal.Logger.Printf(pubsub.getContext(), "sentinel: ignore addr for master=%q", parts[0])
+				internal.Log(pubsub.getContext(), c.opt.Logger, internal.LevelDebug, "sentinel: ignore addr",
+					"master", parts[0])
 				continue
 			}
 			addr := net.JoinHostPort(parts[3], parts[4])
@@ -0,0 +1,405 @@
+// Package session stores web sessions in Redis hashes.
+//
+// A session expires after Options.TTL without being loaded or saved. Its
+// fields are encoded with the codec of the field, JSON by default:
+//
+//	store := session.NewStore(rdb, &session.Options{TTL: 30 * time.Minute})
+//
+//	sess, err := store.Load(ctx, id)
+//	if err == session.ErrNotFound {
+//		sess = store.New()
+//	}
+//	var cart []Item
+//	_, err = sess.Get("cart", &cart)
+//	err = sess.Set("cart", append(cart, item))
+//	err = store.Save(ctx, sess)
+//
+// Save only writes the modified fields, so concurrent requests modifying
+// different fields do not overwrite each other. Update modifies a session
+// atomically, retrying when it is modified concurrently.
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrNotFound is returned when the session does not exist or expired.
+	ErrNotFound = errors.New("session: not found")
+	// ErrConflict is returned by Update when the session kept being modified
+	// concurrently.
+	ErrConflict = errors.New("session: too many concurrent modifications")
+)
+
+// Codec encodes the values of the fields.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// JSONCodec encodes the values with encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error)   { return json.Marshal(v) }
+func (JSONCodec) Unmarshal(b []byte, v interface{}) error { return json.Unmarshal(b, v) }
+
+// loadScript returns the fields of the session and refreshes its expiration.
+var loadScript = redis.NewScript(`
+local fields = redis.call("hgetall", KEYS[1])
+if #fields > 0 then
+	redis.call("pexpire", KEYS[1], ARGV[1])
+end
+return fields
+`)
+
+// incrScript increments a field of an existing session.
+var incrScript = redis.NewScript(`
+if redis.call("exists", KEYS[1]) == 0 then
+	return false
+end
+local n = redis.call("hincrby", KEYS[1], ARGV[1], ARGV[2])
+redis.call("pexpire", KEYS[1], ARGV[3])
+return n
+`)
+
+type Options struct {
+	// Prefix of the keys of the sessions. Default is "session:".
+	Prefix string
+	// TTL is the expiration of the sessions, refreshed when they are loaded
+	// or saved. Default is 30 minutes.
+	TTL time.Duration
+	// Codec of the fields. Default is JSONCodec.
+	Codec Codec
+	// FieldCodecs are the codecs of specific fields.
+	FieldCodecs map[string]Codec
+	// LocalCacheTTL caches the loaded sessions in the process for
+	// LocalCacheTTL, which must be much smaller than TTL: the expiration of
+	// sessions loaded from the cache is not refreshed. Sessions saved by other
+	// processes are stale for up to LocalCacheTTL. Default is no cache.
+	LocalCacheTTL time.Duration
+	// MaxUpdateRetries is the number of retries of Update when a session is
+	// modified concurrently. Default is 10.
+	MaxUpdateRetries int
+}
+
+// Store loads and saves the sessions.
+type Store struct {
+	client redis.UniversalClient
+	opt    Options
+
+	mu      sync.Mutex
+	cache   map[string]cachedSession
+	purgeAt int
+}
+
+type cachedSession struct {
+	values map[string]string
+	until  time.Time
+}
+
+// NewStore returns the session store of client.
+func NewStore(client redis.UniversalClient, opt *Options) *Store {
+	s := &Store{client: client}
+	if opt != nil {
+		s.opt = *opt
+	}
+	if s.opt.Prefix == "" {
+		s.opt.Prefix = "session:"
+	}
+	if s.opt.TTL <= 0 {
+		s.opt.TTL = 30 * time.Minute
+	}
+	if s.opt.Codec == nil {
+		s.opt.Codec = JSONCodec{}
+	}
+	if s.opt.MaxUpdateRetries <= 0 {
+		s.opt.MaxUpdateRetries = 10
+	}
+	if s.opt.LocalCacheTTL > 0 {
+		s.cache = make(map[string]cachedSession)
+	}
+	return s
+}
+
+// Key returns the key of the session id.
+func (s *Store) Key(id string) string {
+	return s.opt.Prefix + id
+}
+
+func (s *Store) codec(field string) Codec {
+	if c, ok := s.opt.FieldCodecs[field]; ok {
+		return c
+	}
+	return s.opt.Codec
+}
+
+// New returns a new session with a random id. It is stored once saved.
+func (s *Store) New() *Session {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	sess := newSession(s, base64.RawURLEncoding.EncodeToString(b), nil)
+	sess.isNew = true
+	return sess
+}
+
+// Load loads the session id and refreshes its expiration. It returns
+// ErrNotFound when the session does not exist.
+func (s *Store) Load(ctx context.Context, id string) (*Session, error) {
+	if values, ok := s.cached(id); ok {
+		return newSession(s, id, values), nil
+	}
+
+	v, err := loadScript.Run(ctx, s.client, []string{s.Key(id)}, s.opt.TTL.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return nil, ErrNotFound
+	}
+	values := make(map[string]string, len(v)/2)
+	for i := 0; i+1 < len(v); i += 2 {
+		values[v[i]] = v[i+1]
+	}
+	s.store(id, values)
+	return newSession(s, id, values), nil
+}
+
+// Save writes the modified fields of sess and refreshes its expiration.
+func (s *Store) Save(ctx context.Context, sess *Session) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		s.save(ctx, pipe, sess)
+		return nil
+	})
+	if err != nil {
+		s.invalidate(sess.ID)
+		return err
+	}
+	sess.saved()
+	s.store(sess.ID, sess.values)
+	return nil
+}
+
+func (s *Store) save(ctx context.Context, pipe redis.Pipeliner, sess *Session) {
+	key := s.Key(sess.ID)
+	if len(sess.deleted) > 0 {
+		fields := make([]string, 0, len(sess.deleted))
+		for field := range sess.deleted {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		pipe.HDel(ctx, key, fields...)
+	}
+	// the empty field keeps the sessions without fields
+	args := make([]interface{}, 0, 2+2*len(sess.changed))
+	args = append(args, "", "")
+	for _, field := range sess.changedFields() {
+		args = append(args, field, sess.values[field])
+	}
+	pipe.HSet(ctx, key, args...)
+	pipe.PExpire(ctx, key, s.opt.TTL)
+}
+
+// Destroy deletes the session id.
+func (s *Store) Destroy(ctx context.Context, id string) error {
+	s.invalidate(id)
+	return s.client.Del(ctx, s.Key(id)).Err()
+}
+
+// Update loads the session id, calls fn with it and saves it atomically,
+// retrying when the session is modified concurrently. It returns ErrNotFound
+// when the session does not exist, and the errors of fn.
+func (s *Store) Update(ctx context.Context, id string, fn func(sess *Session) error) error {
+	key := s.Key(id)
+	s.invalidate(id)
+	for i := 0; i <= s.opt.MaxUpdateRetries; i++ {
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			values, err := tx.HGetAll(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			if len(values) == 0 {
+				return ErrNotFound
+			}
+			sess := newSession(s, id, values)
+			if err := fn(sess); err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				s.save(ctx, pipe, sess)
+				return nil
+			})
+			return err
+		}, key)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return ErrConflict
+}
+
+// IncrBy atomically increments the integer field of the session id by n and
+// returns its new value. It returns ErrNotFound when the session does not
+// exist. The field must use a codec storing integers as decimal strings, such
+// as JSONCodec.
+func (s *Store) IncrBy(ctx context.Context, id, field string, n int64) (int64, error) {
+	s.invalidate(id)
+	v, err := incrScript.Run(ctx, s.client, []string{s.Key(id)}, field, n, s.opt.TTL.Milliseconds()).Int64()
+	if err == redis.Nil {
+		return 0, ErrNotFound
+	}
+	return v, err
+}
+
+func (s *Store) cached(id string) (map[string]string, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cache[id]
+	if !ok || time.Now().After(c.until) {
+		return nil, false
+	}
+	return c.values, true
+}
+
+func (s *Store) store(id string, values map[string]string) {
+	if s.cache == nil {
+		return
+	}
+	copied := make(map[string]string, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= s.purgeAt {
+		for id, c := range s.cache {
+			if now.After(c.until) {
+				delete(s.cache, id)
+			}
+		}
+		s.purgeAt = 2*len(s.cache) + 1024
+	}
+	s.cache[id] = cachedSession{values: copied, until: now.Add(s.opt.LocalCacheTTL)}
+}
+
+func (s *Store) invalidate(id string) {
+	if s.cache == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.cache, id)
+	s.mu.Unlock()
+}
+
+// Session is a session loaded from a Store. It is not safe for concurrent use.
+type Session struct {
+	ID string
+
+	store   *Store
+	values  map[string]string
+	changed map[string]bool
+	deleted map[string]bool
+	isNew   bool
+}
+
+func newSession(store *Store, id string, values map[string]string) *Session {
+	sess := &Session{
+		ID:     id,
+		store:  store,
+		values: make(map[string]string, len(values)),
+	}
+	for k, v := range values {
+		if k != "" {
+			sess.values[k] = v
+		}
+	}
+	return sess
+}
+
+// IsNew reports whether the session was created by Store.New and not saved yet.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Get decodes the value of field into dst. It reports whether the field exists.
+func (s *Session) Get(field string, dst interface{}) (bool, error) {
+	v, ok := s.values[field]
+	if !ok {
+		return false, nil
+	}
+	return true, s.store.codec(field).Unmarshal([]byte(v), dst)
+}
+
+// Set sets the value of field.
+func (s *Session) Set(field string, value interface{}) error {
+	if field == "" {
+		return errors.New("session: empty field name")
+	}
+	b, err := s.store.codec(field).Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.values[field] = string(b)
+	if s.changed == nil {
+		s.changed = make(map[string]bool)
+	}
+	s.changed[field] = true
+	delete(s.deleted, field)
+	return nil
+}
+
+// Delete deletes field.
+func (s *Session) Delete(field string) {
+	if _, ok := s.values[field]; !ok {
+		return
+	}
+	delete(s.values, field)
+	delete(s.changed, field)
+	if s.deleted == nil {
+		s.deleted = make(map[string]bool)
+	}
+	s.deleted[field] = true
+}
+
+// Fields returns the sorted names of the fields.
+func (s *Session) Fields() []string {
+	fields := make([]string, 0, len(s.values))
+	for field := range s.values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Modified reports whether the session has unsaved modifications.
+func (s *Session) Modified() bool {
+	return s.isNew || len(s.changed) > 0 || len(s.deleted) > 0
+}
+
+func (s *Session) changedFields() []string {
+	fields := make([]string, 0, len(s.changed))
+	for field := range s.changed {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (s *Session) saved() {
+	s.changed, s.deleted, s.isNew = nil, nil, false
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error)   { return []byte(v.(string)), nil }
+func (rawCodec) Unmarshal(b []byte, v interface{}) error { *v.(*string) = string(b); return nil }
+
+func TestSession(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: ":6379"})
+	defer client.Close()
+	store := NewStore(client, &Options{
+		FieldCodecs:   map[string]Codec{"csrf": rawCodec{}},
+		LocalCacheTTL: time.Minute,
+	})
+
+	sess := newSession(store, "42", map[string]string{"": "", "user": `{"id":7}`, "csrf": "token"})
+	if !reflect.DeepEqual(sess.Fields(), []string{"csrf", "user"}) {
+		t.Errorf("got fields %q", sess.Fields())
+	}
+	var user struct{ ID int }
+	if ok, err := sess.Get("user", &user); !ok || err != nil || user.ID != 7 {
+		t.Errorf("got %+v, %v, %v", user, ok, err)
+	}
+	var csrf string
+	if ok, err := sess.Get("csrf", &csrf); !ok || err != nil || csrf != "token" {
+		t.Errorf("got %q, %v, %v", csrf, ok, err)
+	}
+	if ok, _ := sess.Get("missing", &csrf); ok {
+		t.Error("missing field exists")
+	}
+	if sess.Modified() {
+		t.Error("loaded session is modified")
+	}
+
+	if err := sess.Set("cart", []string{"apple"}); err != nil {
+		t.Fatal(err)
+	}
+	sess.Delete("csrf")
+	if !sess.Modified() || sess.values["cart"] != `["apple"]` {
+		t.Errorf("got values %v", sess.values)
+	}
+	if !reflect.DeepEqual(sess.changedFields(), []string{"cart"}) || !sess.deleted["csrf"] {
+		t.Errorf("got changed %v, deleted %v", sess.changed, sess.deleted)
+	}
+
+	store.store(sess.ID, sess.values)
+	sess.values["cart"] = "modified"
+	if values, ok := store.cached("42"); !ok || values["cart"] != `["apple"]` {
+		t.Errorf("got cached %v, %v", values, ok)
+	}
+	store.invalidate("42")
+	if _, ok := store.cached("42"); ok {
+		t.Error("invalidated session is cached")
+	}
+
+	if s := store.New(); !s.IsNew() || len(s.ID) != 32 || store.Key(s.ID) != "session:"+s.ID {
+		t.Errorf("got new session %q", s.ID)
+	}
+}
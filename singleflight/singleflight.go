@@ -0,0 +1,177 @@
+// Package singleflight implements a hook coalescing the concurrent identical
+// reads of a client: while a GET or HGETALL of a key is in flight, the same
+// commands of the other goroutines wait for it and share its reply instead of
+// being sent to Redis.
+//
+//	client.AddHook(singleflight.New(nil))
+//
+// It spares Redis the storms of identical reads, such as the GETs of a hot
+// cache key that just expired. Only the concurrent commands are coalesced:
+// the replies are not cached, a command sent after the reply of the in-flight
+// one is sent to Redis.
+//
+// The hook only coalesces the commands processed one by one, not the commands
+// of the pipelines and transactions. Add it before the other hooks of the
+// client for them to see only the commands sent to Redis.
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Options struct {
+	// Commands are the names of the coalesced commands, case-insensitively.
+	// Only the commands replying a string or a map of strings, such as GET,
+	// HGET, HGETALL or GETRANGE, can be coalesced. Default is GET and HGETALL.
+	Commands []string
+}
+
+// Hook coalesces the concurrent identical reads of a client.
+type Hook struct {
+	commands map[string]struct{}
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is an in-flight command whose reply is shared once done is closed.
+type call struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// New returns a hook coalescing the commands of opt, or GET and HGETALL when
+// opt is nil.
+func New(opt *Options) *Hook {
+	commands := []string{"get", "hgetall"}
+	if opt != nil && len(opt.Commands) > 0 {
+		commands = opt.Commands
+	}
+	h := &Hook{
+		commands: make(map[string]struct{}, len(commands)),
+		calls:    make(map[string]*call),
+	}
+	for _, name := range commands {
+		h.commands[strings.ToLower(name)] = struct{}{}
+	}
+	return h
+}
+
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if _, ok := h.commands[cmd.Name()]; !ok || !canShare(cmd) {
+			return next(ctx, cmd)
+		}
+
+		key := callKey(cmd)
+		h.mu.Lock()
+		if c, ok := h.calls[key]; ok {
+			h.mu.Unlock()
+			return h.wait(ctx, c, cmd, next)
+		}
+		c := &call{done: make(chan struct{})}
+		h.calls[key] = c
+		h.mu.Unlock()
+
+		err := next(ctx, cmd)
+		// the reply is copied: the caller owns cmd, and may modify its value
+		c.val, c.err = copyVal(cmd), err
+
+		h.mu.Lock()
+		delete(h.calls, key)
+		h.mu.Unlock()
+		close(c.done)
+		return err
+	}
+}
+
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// wait waits for the in-flight call c and copies its reply to cmd. When c
+// failed because its context was done, cmd is sent with its own context.
+func (h *Hook) wait(ctx context.Context, c *call, cmd redis.Cmder, next redis.ProcessHook) error {
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		cmd.SetErr(ctx.Err())
+		return ctx.Err()
+	}
+
+	if errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded) {
+		return h.ProcessHook(next)(ctx, cmd)
+	}
+	setVal(cmd, c.val)
+	if c.err != nil {
+		cmd.SetErr(c.err)
+	}
+	return c.err
+}
+
+// canShare reports whether the value of cmd can be copied by copyVal.
+func canShare(cmd redis.Cmder) bool {
+	switch cmd.(type) {
+	case *redis.StringCmd, *redis.MapStringStringCmd:
+		return true
+	}
+	return false
+}
+
+func copyVal(cmd redis.Cmder) interface{} {
+	switch cmd := cmd.(type) {
+	case *redis.StringCmd:
+		return cmd.Val()
+	case *redis.MapStringStringCmd:
+		return copyMap(cmd.Val())
+	}
+	return nil
+}
+
+func setVal(cmd redis.Cmder, val interface{}) {
+	switch cmd := cmd.(type) {
+	case *redis.StringCmd:
+		cmd.SetVal(val.(string))
+	case *redis.MapStringStringCmd:
+		cmd.SetVal(copyMap(val.(map[string]string)))
+	}
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// callKey identifies the commands with the same type and arguments.
+func callKey(cmd redis.Cmder) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%T", cmd)
+	for _, arg := range cmd.Args() {
+		s := fmt.Sprint(arg)
+		b.WriteByte(' ')
+		b.WriteString(strconv.Itoa(len(s)))
+		b.WriteByte(':')
+		b.WriteString(s)
+	}
+	return b.String()
+}
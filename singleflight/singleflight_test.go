@@ -0,0 +1,138 @@
+package singleflight_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/chaos"
+	"github.com/redis/go-redis/v9/redistest"
+	"github.com/redis/go-redis/v9/singleflight"
+)
+
+func newClient(t *testing.T) (*redis.Client, *redistest.Recorder) {
+	srv := redistest.NewServer()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), PoolSize: 20})
+	rec := redistest.NewRecorder()
+	client.AddHook(singleflight.New(nil))
+	client.AddHook(rec)
+	client.AddHook(chaos.Latency(50*time.Millisecond, 0))
+	t.Cleanup(func() {
+		_ = client.Close()
+		srv.Close()
+	})
+	return client, rec
+}
+
+func TestCoalesce(t *testing.T) {
+	ctx := context.Background()
+	client, rec := newClient(t)
+	if err := client.Set(ctx, "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.HSet(ctx, "hash", "field", "value").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if val, err := client.Get(ctx, "key").Result(); err != nil || val != "value" {
+				t.Errorf("got %q, %v", val, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			val, err := client.HGetAll(ctx, "hash").Result()
+			if err != nil || val["field"] != "value" {
+				t.Errorf("got %v, %v", val, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := rec.Count("get"); n != 1 {
+		t.Fatalf("got %d GET, want 1", n)
+	}
+	if n := rec.Count("hgetall"); n != 1 {
+		t.Fatalf("got %d HGETALL, want 1", n)
+	}
+
+	// the replies are not cached
+	if err := client.Get(ctx, "key").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := rec.Count("get"); n != 2 {
+		t.Fatalf("got %d GET, want 2", n)
+	}
+}
+
+func TestShareErrors(t *testing.T) {
+	ctx := context.Background()
+	client, rec := newClient(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Get(ctx, "missing").Err(); err != redis.Nil {
+				t.Errorf("got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := rec.Count("get"); n != 1 {
+		t.Fatalf("got %d GET, want 1", n)
+	}
+}
+
+func TestCanceledLeader(t *testing.T) {
+	ctx := context.Background()
+	client, rec := newClient(t)
+	if err := client.Set(ctx, "key", "value", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	leaderCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- client.Get(leaderCtx, "key").Err()
+	}()
+	time.Sleep(time.Millisecond)
+
+	if val, err := client.Get(ctx, "key").Result(); err != nil || val != "value" {
+		t.Fatalf("got %q, %v", val, err)
+	}
+	if err := <-done; err != context.DeadlineExceeded {
+		t.Fatalf("got %v", err)
+	}
+	if n := rec.Count("get"); n != 2 {
+		t.Fatalf("got %d GET, want 2", n)
+	}
+}
+
+func TestDistinctCommands(t *testing.T) {
+	ctx := context.Background()
+	client, rec := newClient(t)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			_ = client.Get(ctx, key).Err()
+		}(key)
+	}
+	wg.Wait()
+
+	if n := rec.Count("get"); n != 3 {
+		t.Fatalf("got %d GET, want 3", n)
+	}
+}
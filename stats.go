@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CommandStats are the cumulative statistics of the commands of a name, see
+// Client.Stats.
+type CommandStats struct {
+	// Calls is the number of commands processed.
+	Calls uint64
+	// Errors is the number of commands failed, not counting the Nil replies.
+	Errors uint64
+	// Nils is the number of Nil replies.
+	Nils uint64
+	// Retries is the number of times the commands were retried after an
+	// error or a cluster redirection. The redirections of the pipelines of a
+	// ClusterClient are not counted.
+	Retries uint64
+	// BytesOut is the number of bytes of the commands written, and BytesIn
+	// the number of bytes of their replies read.
+	BytesOut uint64
+	BytesIn  uint64
+	// Latency is the total duration of the commands, including the retries.
+	Latency time.Duration
+}
+
+// MeanLatency returns the mean duration of the commands.
+func (s CommandStats) MeanLatency() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Calls)
+}
+
+func (s *CommandStats) add(other CommandStats) {
+	s.Calls += other.Calls
+	s.Errors += other.Errors
+	s.Nils += other.Nils
+	s.Retries += other.Retries
+	s.BytesOut += other.BytesOut
+	s.BytesIn += other.BytesIn
+	s.Latency += other.Latency
+}
+
+// cmdBytes are the sizes of a command and of its reply on the wire.
+type cmdBytes struct {
+	out, in uint32
+}
+
+func (cmd *baseCmd) wireBytes() *cmdBytes {
+	return &cmd.wire
+}
+
+// cmdsStats are the statistics of the commands of a client by name.
+type cmdsStats struct {
+	mu    sync.RWMutex
+	names map[string]*cmdStats
+}
+
+type cmdStats struct {
+	calls    uint64 // atomic
+	errors   uint64 // atomic
+	nils     uint64 // atomic
+	retries  uint64 // atomic
+	bytesOut uint64 // atomic
+	bytesIn  uint64 // atomic
+	latency  int64  // atomic
+}
+
+func newCmdsStats() *cmdsStats {
+	return &cmdsStats{
+		names: make(map[string]*cmdStats),
+	}
+}
+
+func (s *cmdsStats) get(name string) *cmdStats {
+	s.mu.RLock()
+	stats := s.names[name]
+	s.mu.RUnlock()
+	if stats != nil {
+		return stats
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats = s.names[name]; stats == nil {
+		stats = new(cmdStats)
+		s.names[name] = stats
+	}
+	return stats
+}
+
+func (s *cmdsStats) record(cmd Cmder) {
+	stats := s.get(cmd.Name())
+	atomic.AddUint64(&stats.calls, 1)
+	switch err := cmd.Err(); err {
+	case nil:
+	case Nil:
+		atomic.AddUint64(&stats.nils, 1)
+	default:
+		atomic.AddUint64(&stats.errors, 1)
+	}
+	wire := cmd.wireBytes()
+	atomic.AddUint64(&stats.bytesOut, uint64(wire.out))
+	atomic.AddUint64(&stats.bytesIn, uint64(wire.in))
+	atomic.AddInt64(&stats.latency, int64(cmd.duration()))
+}
+
+func (s *cmdsStats) recordCmds(cmds []Cmder) {
+	for _, cmd := range cmds {
+		s.record(cmd)
+	}
+}
+
+func (s *cmdsStats) recordRetry(cmds ...Cmder) {
+	for _, cmd := range cmds {
+		atomic.AddUint64(&s.get(cmd.Name()).retries, 1)
+	}
+}
+
+// snapshot adds the statistics to acc.
+func (s *cmdsStats) snapshot(acc map[string]CommandStats) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for name, stats := range s.names {
+		cmdStats := acc[name]
+		cmdStats.add(CommandStats{
+			Calls:    atomic.LoadUint64(&stats.calls),
+			Errors:   atomic.LoadUint64(&stats.errors),
+			Nils:     atomic.LoadUint64(&stats.nils),
+			Retries:  atomic.LoadUint64(&stats.retries),
+			BytesOut: atomic.LoadUint64(&stats.bytesOut),
+			BytesIn:  atomic.LoadUint64(&stats.bytesIn),
+			Latency:  time.Duration(atomic.LoadInt64(&stats.latency)),
+		})
+		acc[name] = cmdStats
+	}
+}
+
+func (s *cmdsStats) reset() {
+	s.mu.Lock()
+	s.names = make(map[string]*cmdStats)
+	s.mu.Unlock()
+}
+
+//------------------------------------------------------------------------------
+
+// Stats returns the cumulative statistics of the commands processed by the
+// client by command name, e.g. "get", when Options.StatsEnabled is set.
+func (c *Client) Stats() map[string]CommandStats {
+	acc := make(map[string]CommandStats)
+	if c.stats != nil {
+		c.stats.snapshot(acc)
+	}
+	return acc
+}
+
+// ResetStats resets the statistics returned by Stats.
+func (c *Client) ResetStats() {
+	if c.stats != nil {
+		c.stats.reset()
+	}
+}
+
+// Stats returns the statistics of the commands accumulated over the nodes,
+// see Client.Stats.
+func (c *ClusterClient) Stats() map[string]CommandStats {
+	acc := make(map[string]CommandStats)
+	nodes, _ := c.nodes.All()
+	for _, node := range nodes {
+		if node.Client.stats != nil {
+			node.Client.stats.snapshot(acc)
+		}
+	}
+	return acc
+}
+
+// ResetStats resets the statistics of the nodes.
+func (c *ClusterClient) ResetStats() {
+	nodes, _ := c.nodes.All()
+	for _, node := range nodes {
+		if node.Client.stats != nil {
+			node.Client.stats.reset()
+		}
+	}
+}
+
+// Stats returns the statistics of the commands accumulated over the shards,
+// see Client.Stats.
+func (c *Ring) Stats() map[string]CommandStats {
+	acc := make(map[string]CommandStats)
+	for _, shard := range c.sharding.List() {
+		if shard.Client.stats != nil {
+			shard.Client.stats.snapshot(acc)
+		}
+	}
+	return acc
+}
+
+// ResetStats resets the statistics of the shards.
+func (c *Ring) ResetStats() {
+	for _, shard := range c.sharding.List() {
+		if shard.Client.stats != nil {
+			shard.Client.stats.reset()
+		}
+	}
+}
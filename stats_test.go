@@ -0,0 +1,118 @@
+package redis_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestStats(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr(), StatsEnabled: true})
+	defer rdb.Close()
+
+	_ = rdb.Set(ctx, "key", "v", 0).Err()
+	_ = rdb.Get(ctx, "key").Err()
+	_ = rdb.Get(ctx, "missing").Err()
+	_, _ = rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Get(ctx, "key")
+		pipe.Incr(ctx, "key") // not an integer
+		return nil
+	})
+
+	stats := rdb.Stats()
+	get := stats["get"]
+	if get.Calls != 3 || get.Nils != 1 || get.Errors != 0 {
+		t.Fatalf("got %+v, wanted 3 calls and 1 nil", get)
+	}
+	// *2\r\n$3\r\nget\r\n$3\r\nkey\r\n twice, *2\r\n$3\r\nget\r\n$7\r\nmissing\r\n
+	if get.BytesOut != 2*22+26 {
+		t.Fatalf("got %d bytes out, wanted %d", get.BytesOut, 2*22+26)
+	}
+	// $1\r\nv\r\n twice, and the nil of RESP3
+	if get.BytesIn != 2*7+3 {
+		t.Fatalf("got %d bytes in, wanted %d", get.BytesIn, 2*7+3)
+	}
+	if get.Latency <= 0 || get.MeanLatency() != get.Latency/3 {
+		t.Fatalf("got latency %s and mean %s", get.Latency, get.MeanLatency())
+	}
+	if incr := stats["incr"]; incr.Calls != 1 || incr.Errors != 1 {
+		t.Fatalf("got %+v, wanted 1 failed call", incr)
+	}
+	if set := stats["set"]; set.Calls != 1 || set.Errors != 0 || set.BytesIn != 5 {
+		t.Fatalf("got %+v, wanted 1 call replied with +OK", set)
+	}
+	if _, ok := stats["hello"]; ok {
+		t.Fatal("the commands of the handshake are counted")
+	}
+
+	rdb.ResetStats()
+	if stats := rdb.Stats(); len(stats) != 0 {
+		t.Fatalf("got %v after ResetStats", stats)
+	}
+}
+
+func TestStatsDisabled(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	_ = rdb.Ping(ctx).Err()
+	if stats := rdb.Stats(); len(stats) != 0 {
+		t.Fatalf("got %v, wanted no statistics", stats)
+	}
+}
+
+// eofConn fails the writes with io.EOF while fail is set.
+type eofConn struct {
+	net.Conn
+	fail *int32
+}
+
+func (cn eofConn) Write(b []byte) (int, error) {
+	if atomic.CompareAndSwapInt32(cn.fail, 1, 0) {
+		return 0, io.EOF
+	}
+	return cn.Conn.Write(b)
+}
+
+func TestStatsRetries(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var fail int32
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         srv.Addr(),
+		StatsEnabled: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			cn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return eofConn{Conn: cn, fail: &fail}, nil
+		},
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&fail, 1)
+	if err := rdb.Set(ctx, "key", "v", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if set := rdb.Stats()["set"]; set.Calls != 1 || set.Retries != 1 || set.Errors != 0 {
+		t.Fatalf("got %+v, wanted 1 call retried once", set)
+	}
+	if ping := rdb.Stats()["ping"]; ping.Retries != 0 {
+		t.Fatalf("got %+v, wanted no retries", ping)
+	}
+}
@@ -0,0 +1,270 @@
+// Package stream implements consumers of the consumer groups of Redis
+// streams.
+//
+// A Consumer creates its consumer group, reads the new messages of the stream
+// with XREADGROUP and claims with XAUTOCLAIM the messages left pending by the
+// consumers which stopped. The messages are delivered to a handler, which
+// acknowledges them when it succeeds, or to a channel:
+//
+//	c := stream.NewConsumer(rdb, "events", "billing", nil)
+//	err := c.Run(ctx, func(ctx context.Context, msg *stream.Message) error {
+//		return bill(msg.Values)
+//	})
+//
+// Run returns once ctx is done and the messages being handled are handled.
+// The failed messages are not acknowledged: they are delivered again once
+// claimed, after Options.ClaimMinIdle.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Options struct {
+	// Consumer is the name of the consumer in the group. Default is the host
+	// name and the process id.
+	Consumer string
+	// Start is the id of the last message of the stream considered delivered
+	// when the group is created: "$" delivers only the new messages, and "0"
+	// all the messages. Default is "$".
+	Start string
+	// Count is the maximum number of messages read at once. Default is 10.
+	Count int
+	// Block is the time waiting for new messages before claiming the pending
+	// messages again. Default is 1 second.
+	Block time.Duration
+	// ClaimMinIdle is the time after which the pending messages of the
+	// consumers, including the failed messages, are claimed. It must exceed
+	// the time taken by the handler. Default is 5 minutes. -1 disables the
+	// claiming.
+	ClaimMinIdle time.Duration
+	// Concurrency is the number of messages handled concurrently by Run.
+	// Default is 1.
+	Concurrency int
+	// OnError is called with the errors of Redis, which are retried after
+	// Block, and with the errors of the handlers. Default is to ignore them.
+	OnError func(err error)
+}
+
+// Consumer is a consumer of a consumer group of a stream.
+type Consumer struct {
+	client redis.UniversalClient
+	stream string
+	group  string
+	opt    Options
+}
+
+// NewConsumer returns a consumer of the consumer group group of stream.
+func NewConsumer(client redis.UniversalClient, stream, group string, opt *Options) *Consumer {
+	c := &Consumer{client: client, stream: stream, group: group}
+	if opt != nil {
+		c.opt = *opt
+	}
+	if c.opt.Consumer == "" {
+		host, _ := os.Hostname()
+		c.opt.Consumer = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if c.opt.Start == "" {
+		c.opt.Start = "$"
+	}
+	if c.opt.Count <= 0 {
+		c.opt.Count = 10
+	}
+	if c.opt.Block <= 0 {
+		c.opt.Block = time.Second
+	}
+	if c.opt.ClaimMinIdle == 0 {
+		c.opt.ClaimMinIdle = 5 * time.Minute
+	}
+	if c.opt.Concurrency <= 0 {
+		c.opt.Concurrency = 1
+	}
+	return c
+}
+
+// Message is a message delivered to a consumer.
+type Message struct {
+	redis.XMessage
+	// Claimed reports whether the message was claimed from the pending
+	// messages, having been delivered before.
+	Claimed bool
+
+	consumer *Consumer
+}
+
+// Ack acknowledges the message, which is not delivered again.
+func (m *Message) Ack(ctx context.Context) error {
+	return m.consumer.client.XAck(ctx, m.consumer.stream, m.consumer.group, m.ID).Err()
+}
+
+// Handler handles a message, which is acknowledged unless it returns an error.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Run delivers the messages to handler until ctx is done, waits for the
+// messages being handled, and returns ctx.Err(). The messages are
+// acknowledged after ctx is done when their handler succeeds.
+func (c *Consumer) Run(ctx context.Context, handler Handler) error {
+	msgs, err := c.Chan(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.opt.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range msgs {
+				if err := c.handle(ctx, handler, msg); err != nil && ctx.Err() == nil {
+					c.onError(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (c *Consumer) handle(ctx context.Context, handler Handler, msg *Message) error {
+	if err := callHandler(ctx, handler, msg); err != nil {
+		return err
+	}
+	return msg.Ack(detach(ctx))
+}
+
+// Chan creates the consumer group, and returns a channel of the messages,
+// which must be acknowledged with Message.Ack. The channel is closed once
+// ctx is done; the messages read but not received are claimed later.
+func (c *Consumer) Chan(ctx context.Context) (<-chan *Message, error) {
+	if err := c.createGroup(ctx); err != nil {
+		return nil, err
+	}
+	msgs := make(chan *Message)
+	go func() {
+		defer close(msgs)
+		c.read(ctx, msgs)
+	}()
+	return msgs, nil
+}
+
+func (c *Consumer) createGroup(ctx context.Context) error {
+	err := c.client.XGroupCreateMkStream(ctx, c.stream, c.group, c.opt.Start).Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+func (c *Consumer) read(ctx context.Context, msgs chan<- *Message) {
+	claimStart := "0-0"
+	for ctx.Err() == nil {
+		var claimed []redis.XMessage
+		var err error
+		if c.opt.ClaimMinIdle > 0 {
+			claimed, claimStart, err = c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   c.stream,
+				Group:    c.group,
+				MinIdle:  c.opt.ClaimMinIdle,
+				Start:    claimStart,
+				Count:    int64(c.opt.Count),
+				Consumer: c.opt.Consumer,
+			}).Result()
+			if err != nil {
+				claimStart = "0-0"
+				c.wait(ctx, err)
+				continue
+			}
+			if !c.deliver(ctx, msgs, claimed, true) {
+				return
+			}
+			if len(claimed) == c.opt.Count {
+				continue
+			}
+		}
+
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.opt.Consumer,
+			Streams:  []string{c.stream, ">"},
+			Count:    int64(c.opt.Count),
+			Block:    c.opt.Block,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			c.wait(ctx, err)
+			continue
+		}
+		for _, stream := range streams {
+			if !c.deliver(ctx, msgs, stream.Messages, false) {
+				return
+			}
+		}
+	}
+}
+
+// deliver sends the messages to msgs, and reports whether ctx is not done.
+func (c *Consumer) deliver(ctx context.Context, msgs chan<- *Message, xmsgs []redis.XMessage, claimed bool) bool {
+	for _, xmsg := range xmsgs {
+		msg := &Message{
+			XMessage: xmsg,
+			Claimed:  claimed,
+			consumer: c,
+		}
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// wait reports err and waits for Block, unless ctx is done.
+func (c *Consumer) wait(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	c.onError(err)
+	select {
+	case <-time.After(c.opt.Block):
+	case <-ctx.Done():
+	}
+}
+
+func (c *Consumer) onError(err error) {
+	if c.opt.OnError != nil {
+		c.opt.OnError(err)
+	}
+}
+
+func callHandler(ctx context.Context, handler Handler, msg *Message) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("stream: handler panicked: %v", v)
+		}
+	}()
+	return handler(ctx, msg)
+}
+
+// detachedContext is the context of a parent without its cancellation, for
+// the messages handled while the consumer is stopping to be acknowledged.
+type detachedContext struct {
+	parent context.Context
+}
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
@@ -0,0 +1,181 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeStream is a hook replying to the stream commands of a consumer group.
+type fakeStream struct {
+	mu      sync.Mutex
+	created bool
+	unread  []redis.XMessage
+	pending []pendingMessage
+	acked   []string
+}
+
+type pendingMessage struct {
+	redis.XMessage
+	deliveredAt time.Time
+}
+
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func (redisError) RedisError() {}
+
+func (s *fakeStream) deliver(msgs []redis.XMessage) {
+	for _, msg := range msgs {
+		s.pending = append(s.pending, pendingMessage{XMessage: msg, deliveredAt: time.Now()})
+	}
+}
+
+func (s *fakeStream) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("fake stream: no connection")
+	}
+}
+
+func (s *fakeStream) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (s *fakeStream) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch cmd := cmd.(type) {
+		case *redis.StatusCmd: // XGROUP CREATE
+			if s.created {
+				cmd.SetErr(redisError("BUSYGROUP Consumer Group name already exists"))
+				return cmd.Err()
+			}
+			s.created = true
+			cmd.SetVal("OK")
+		case *redis.XAutoClaimCmd:
+			minIdle := time.Duration(cmd.Args()[4].(int64)) * time.Millisecond
+			var claimed []redis.XMessage
+			for i := range s.pending {
+				if msg := &s.pending[i]; time.Since(msg.deliveredAt) >= minIdle {
+					claimed = append(claimed, msg.XMessage)
+					msg.deliveredAt = time.Now()
+				}
+			}
+			cmd.SetVal(claimed, "0-0")
+		case *redis.XStreamSliceCmd: // XREADGROUP
+			if len(s.unread) == 0 {
+				s.mu.Unlock()
+				time.Sleep(time.Millisecond)
+				s.mu.Lock()
+				cmd.SetErr(redis.Nil)
+				return redis.Nil
+			}
+			msgs := s.unread
+			s.unread = nil
+			s.deliver(msgs)
+			cmd.SetVal([]redis.XStream{{Stream: "events", Messages: msgs}})
+		case *redis.IntCmd: // XACK
+			id := cmd.Args()[3].(string)
+			for i, msg := range s.pending {
+				if msg.ID == id {
+					s.pending = append(s.pending[:i], s.pending[i+1:]...)
+					s.acked = append(s.acked, id)
+					cmd.SetVal(1)
+					break
+				}
+			}
+		default:
+			return next(ctx, cmd)
+		}
+		return nil
+	}
+}
+
+func (s *fakeStream) ackedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.acked...)
+}
+
+func TestNewConsumer(t *testing.T) {
+	c := NewConsumer(redis.NewClient(&redis.Options{}), "events", "billing", &Options{Consumer: "c1"})
+	if c.opt.Consumer != "c1" || c.opt.Start != "$" || c.opt.Count != 10 || c.opt.Block != time.Second ||
+		c.opt.ClaimMinIdle != 5*time.Minute || c.opt.Concurrency != 1 {
+		t.Fatalf("got options %+v", c.opt)
+	}
+}
+
+func TestConsumerRun(t *testing.T) {
+	fake := &fakeStream{unread: []redis.XMessage{
+		{ID: "1-0", Values: map[string]interface{}{"n": "1"}},
+		{ID: "2-0", Values: map[string]interface{}{"n": "2"}},
+		{ID: "3-0", Values: map[string]interface{}{"n": "3"}},
+	}}
+	rdb := redis.NewClient(&redis.Options{})
+	rdb.AddHook(fake)
+	defer rdb.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var handled []string
+	failed := false
+	var handlerErrs []error
+	c := NewConsumer(rdb, "events", "billing", &Options{
+		Block:        time.Millisecond,
+		ClaimMinIdle: 20 * time.Millisecond,
+		OnError:      func(err error) { handlerErrs = append(handlerErrs, err) },
+	})
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx, func(ctx context.Context, msg *Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, msg.ID)
+			if msg.ID == "2-0" && !failed {
+				failed = true
+				return errors.New("failed")
+			}
+			if msg.ID == "2-0" && !msg.Claimed {
+				t.Error("the failed message was not claimed")
+			}
+			if len(fake.ackedIDs()) == 2 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("got %v, wanted context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+
+	if acked := fake.ackedIDs(); len(acked) != 3 || acked[0] != "1-0" || acked[1] != "3-0" || acked[2] != "2-0" {
+		t.Fatalf("got acknowledged %v", acked)
+	}
+	if len(handled) != 4 {
+		t.Fatalf("got handled %v, wanted 2-0 twice", handled)
+	}
+	if len(handlerErrs) != 1 || handlerErrs[0].Error() != "failed" {
+		t.Fatalf("got errors %v", handlerErrs)
+	}
+
+	// the group is created once
+	if err := c.createGroup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,7 +2,10 @@ package redis
 
 import (
 	"context"
+	"io"
 	"time"
+
+	"github.com/redis/go-redis/v9/internal/proto"
 )
 
 type StringCmdable interface {
@@ -10,6 +13,8 @@ type StringCmdable interface {
 	Decr(ctx context.Context, key string) *IntCmd
 	DecrBy(ctx context.Context, key string, decrement int64) *IntCmd
 	Get(ctx context.Context, key string) *StringCmd
+	GetInto(ctx context.Context, key string, fn func(b []byte) error) *BytesFuncCmd
+	GetTo(ctx context.Context, key string, w io.Writer) *WriterCmd
 	GetRange(ctx context.Context, key string, start, end int64) *StringCmd
 	GetSet(ctx context.Context, key string, value interface{}) *StringCmd
 	GetEx(ctx context.Context, key string, expiration time.Duration) *StringCmd
@@ -19,9 +24,11 @@ type StringCmdable interface {
 	IncrByFloat(ctx context.Context, key string, value float64) *FloatCmd
 	LCS(ctx context.Context, q *LCSQuery) *LCSCmd
 	MGet(ctx context.Context, keys ...string) *SliceCmd
+	MGetBytes(ctx context.Context, dst [][]byte, keys ...string) *BytesSliceCmd
 	MSet(ctx context.Context, values ...interface{}) *StatusCmd
 	MSetNX(ctx context.Context, values ...interface{}) *BoolCmd
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd
+	SetFrom(ctx context.Context, key string, r io.Reader, size int64, expiration time.Duration) *StatusCmd
 	SetArgs(ctx context.Context, key string, value interface{}, a SetArgs) *StatusCmd
 	SetEx(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *BoolCmd
@@ -50,7 +57,27 @@ func (c cmdable) DecrBy(ctx context.Context, key string, decrement int64) *IntCm
 
 // Get Redis `GET key` command. It returns redis.Nil error when key does not exist.
 func (c cmdable) Get(ctx context.Context, key string) *StringCmd {
-	cmd := NewStringCmd(ctx, "get", key)
+	cmd := newPooledStringCmd(ctx, "get", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetInto calls fn with the value of the key without copying it when it fits in
+// the read buffer of the connection: the bytes are only valid until fn
+// returns. The error of the command is Nil when the key does not exist, or the
+// error returned by fn.
+func (c cmdable) GetInto(ctx context.Context, key string, fn func(b []byte) error) *BytesFuncCmd {
+	cmd := NewBytesFuncCmd(ctx, fn, "get", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// GetTo copies the value of the key to w in chunks of the read buffer of the
+// connection, so that large values are never entirely in memory. The value of
+// the command is the number of bytes written, and its error is Nil when the
+// key does not exist, or the error of w.
+func (c cmdable) GetTo(ctx context.Context, key string, w io.Writer) *WriterCmd {
+	cmd := NewWriterCmd(ctx, w, "get", key)
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -95,7 +122,7 @@ func (c cmdable) GetDel(ctx context.Context, key string) *StringCmd {
 }
 
 func (c cmdable) Incr(ctx context.Context, key string) *IntCmd {
-	cmd := NewIntCmd(ctx, "incr", key)
+	cmd := newPooledIntCmd(ctx, "incr", key)
 	_ = c(ctx, cmd)
 	return cmd
 }
@@ -129,6 +156,20 @@ func (c cmdable) MGet(ctx context.Context, keys ...string) *SliceCmd {
 	return cmd
 }
 
+// MGetBytes is MGet decoding the values into dst, see NewBytesSliceCmd, so
+// that reusing dst avoids the allocations of the values. The values of the
+// missing keys are reported by BytesSliceCmd.IsNil.
+func (c cmdable) MGetBytes(ctx context.Context, dst [][]byte, keys ...string) *BytesSliceCmd {
+	args := make([]interface{}, 1+len(keys))
+	args[0] = "mget"
+	for i, key := range keys {
+		args[1+i] = key
+	}
+	cmd := NewBytesSliceCmd(ctx, dst, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
 // MSet is like Set but accepts multiple values:
 //   - MSet("key1", "value1", "key2", "value2")
 //   - MSet([]string{"key1", "value1", "key2", "value2"})
@@ -178,11 +219,22 @@ func (c cmdable) Set(ctx context.Context, key string, value interface{}, expirat
 		args = append(args, "keepttl")
 	}
 
-	cmd := NewStatusCmd(ctx, args...)
+	cmd := newPooledStatusCmd(ctx, args...)
 	_ = c(ctx, cmd)
 	return cmd
 }
 
+// SetFrom is Set with the size bytes of r as value, which are copied to the
+// connection when the command is written instead of being in memory. When
+// the command is written again, e.g. retried, r is seeked back if it is an
+// io.Seeker, and otherwise the command fails. The command fails as well when
+// r has less than size bytes.
+func (c cmdable) SetFrom(
+	ctx context.Context, key string, r io.Reader, size int64, expiration time.Duration,
+) *StatusCmd {
+	return c.Set(ctx, key, &proto.ReaderArg{R: r, Size: size}, expiration)
+}
+
 // SetArgs provides arguments for the SetArgs function.
 type SetArgs struct {
 	// Mode can be `NX` or `XX` or empty.
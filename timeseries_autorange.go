@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"context"
+	"math"
+)
+
+// tsBucketDurations are the bucket durations, in milliseconds, TSRangeAuto
+// chooses from: the usual steps of dashboards. They are int64 as 30 days
+// overflow the int of 32-bit platforms.
+var tsBucketDurations = []int64{
+	1, 2, 5, 10, 20, 50, 100, 200, 500,
+	1000, 2000, 5000, 10000, 15000, 30000,
+	60000, 2 * 60000, 5 * 60000, 10 * 60000, 15 * 60000, 30 * 60000,
+	3600000, 2 * 3600000, 3 * 3600000, 6 * 3600000, 12 * 3600000,
+	86400000, 2 * 86400000, 7 * 86400000, 30 * 86400000,
+}
+
+type TSAutoRangeOptions struct {
+	// MaxDataPoints is the maximum number of samples returned. Default is 1000.
+	MaxDataPoints int
+	// Aggregator of the buckets. Default is Avg.
+	Aggregator Aggregator
+	// MinBucketDuration is the smallest bucket chosen, e.g. the sampling
+	// interval of the series. Ranges that fit in MaxDataPoints samples of the
+	// raw series are not aggregated unless it is set.
+	MinBucketDuration int
+	// Empty reports empty buckets, see TSRangeOptions.Empty.
+	Empty bool
+	// NoCompactions only queries the source key, ignoring its compaction rules.
+	NoCompactions bool
+}
+
+// TSAutoRange is the result of TSRangeAuto.
+type TSAutoRange struct {
+	// Key is the key that was queried: the source key or the destination key
+	// of one of its compaction rules.
+	Key string
+	// Aggregator and BucketDuration of the samples. BucketDuration is zero for
+	// raw samples.
+	Aggregator     Aggregator
+	BucketDuration int
+	DataPoints     []TSTimestampValue
+}
+
+// TSRangeAuto queries the samples of key between fromTimestamp and toTimestamp
+// at a resolution of at most MaxDataPoints samples. The bucket duration is
+// the smallest of the usual dashboard steps (1s, 5s, 1m, 1h, ...) that fits.
+//
+// When a compaction rule of key has the aggregator, a bucket duration dividing
+// the chosen one and a retention covering fromTimestamp, its destination key is
+// queried instead, re-aggregating its buckets when they are smaller. Averages
+// and other aggregations that cannot be re-aggregated exactly only use rules of
+// the chosen bucket duration.
+func TSRangeAuto(
+	ctx context.Context, client TimeseriesCmdable, key string, fromTimestamp, toTimestamp int, opt *TSAutoRangeOptions,
+) (*TSAutoRange, error) {
+	if opt == nil {
+		opt = &TSAutoRangeOptions{}
+	}
+	maxPoints := opt.MaxDataPoints
+	if maxPoints <= 0 {
+		maxPoints = 1000
+	}
+	aggregator := opt.Aggregator
+	if aggregator == Invalid {
+		aggregator = Avg
+	}
+
+	bucket := tsAutoBucket(toTimestamp-fromTimestamp, maxPoints, opt.MinBucketDuration)
+	res := &TSAutoRange{
+		Key:            key,
+		Aggregator:     aggregator,
+		BucketDuration: bucket,
+	}
+	if bucket == 0 {
+		res.Aggregator = Invalid
+	}
+
+	options := &TSRangeOptions{
+		Aggregator:     res.Aggregator,
+		BucketDuration: bucket,
+		Empty:          opt.Empty && bucket > 0,
+	}
+	if bucket > 0 && !opt.NoCompactions {
+		rule, err := tsAutoCompaction(ctx, client, key, aggregator, bucket, fromTimestamp)
+		if err != nil {
+			return nil, err
+		}
+		if rule != nil {
+			res.Key = rule.DestKey
+			options.Latest = true
+			if rule.BucketDuration == bucket {
+				// the compacted samples are the buckets
+				options.Aggregator, options.BucketDuration, options.Empty = Invalid, 0, false
+			} else {
+				options.Aggregator = tsReaggregator(aggregator)
+			}
+		}
+	}
+
+	points, err := client.TSRangeWithArgs(ctx, res.Key, fromTimestamp, toTimestamp, options).Result()
+	if err != nil {
+		return nil, err
+	}
+	res.DataPoints = points
+	return res, nil
+}
+
+// tsAutoBucket returns the bucket duration of a range of span milliseconds
+// returned in at most maxPoints samples, zero for raw samples.
+func tsAutoBucket(span, maxPoints, minBucket int) int {
+	step := (int64(span) + int64(maxPoints)) / int64(maxPoints) // ceil((span+1)/maxPoints)
+	if step <= 1 && minBucket <= 1 {
+		return 0
+	}
+	if step < int64(minBucket) {
+		step = int64(minBucket)
+	}
+	for _, d := range tsBucketDurations {
+		if d >= step {
+			return tsBucketInt(d, step)
+		}
+	}
+	// round up to a whole number of the largest step
+	last := tsBucketDurations[len(tsBucketDurations)-1]
+	return tsBucketInt((step+last-1)/last*last, step)
+}
+
+// tsBucketInt returns the bucket duration d as an int, or step when d
+// overflows it.
+func tsBucketInt(d, step int64) int {
+	if d > math.MaxInt {
+		return int(step)
+	}
+	return int(d)
+}
+
+// tsAutoCompaction returns the compaction rule of key with the largest bucket
+// usable for the bucket duration, or nil.
+func tsAutoCompaction(
+	ctx context.Context, client TimeseriesCmdable, key string, aggregator Aggregator, bucket, fromTimestamp int,
+) (*TSCompaction, error) {
+	info, err := client.TSInfo(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := parseTSInfoRules(info["rules"])
+	if err != nil {
+		return nil, err
+	}
+
+	var best *TSCompaction
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Aggregator != aggregator || rule.BucketDuration <= 0 || bucket%rule.BucketDuration != 0 {
+			continue
+		}
+		if rule.BucketDuration != bucket && tsReaggregator(aggregator) == Invalid {
+			continue
+		}
+		if best != nil && rule.BucketDuration <= best.BucketDuration {
+			continue
+		}
+
+		destInfo, err := client.TSInfo(ctx, rule.DestKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		retention, _ := toInt64(destInfo["retentionTime"])
+		last, _ := toInt64(destInfo["lastTimestamp"])
+		if retention > 0 && last-retention > int64(fromTimestamp) {
+			continue
+		}
+		best = rule
+	}
+	return best, nil
+}
+
+// tsReaggregator returns the aggregator combining buckets of aggregator into
+// larger buckets, or Invalid when they cannot be combined exactly.
+func tsReaggregator(aggregator Aggregator) Aggregator {
+	switch aggregator {
+	case Sum, Count:
+		return Sum
+	case Min, Max, First, Last:
+		return aggregator
+	default:
+		return Invalid
+	}
+}
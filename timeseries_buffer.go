@@ -0,0 +1,297 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// TSSample is a sample buffered by TSBuffer.
+type TSSample struct {
+	Key       string
+	Timestamp interface{}
+	Value     float64
+}
+
+type TSBufferOptions struct {
+	// MaxSamples flushes the buffer once it holds that many samples.
+	// Default is 1000.
+	MaxSamples int
+	// FlushInterval flushes the buffer periodically.
+	// Default is 1 second; -1 disables periodic flushes.
+	FlushInterval time.Duration
+
+	// MaxRetries is the number of times the samples rejected by a flush are retried.
+	// Default is 3; -1 disables retries.
+	MaxRetries int
+	// MinRetryBackoff and MaxRetryBackoff bound the backoff between retries.
+	// Default is 8 milliseconds and 512 milliseconds.
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
+
+	// Create, when set, creates the keys with these options (e.g. Retention,
+	// DuplicatePolicy and Labels) the first time the buffer writes to them.
+	// Keys that already exist are left untouched.
+	Create *TSOptions
+	// CreateOptions returns the options of a key, overriding Create.
+	// Returning nil leaves the key to TS.MADD, which fails unless it exists.
+	CreateOptions func(key string) *TSOptions
+
+	// OnError is called with the samples the periodic flushes failed to write.
+	OnError func(err *TSBufferError)
+}
+
+func (opt *TSBufferOptions) init() {
+	if opt.MaxSamples <= 0 {
+		opt.MaxSamples = 1000
+	}
+	switch opt.FlushInterval {
+	case -1:
+		opt.FlushInterval = 0
+	case 0:
+		opt.FlushInterval = time.Second
+	}
+	switch opt.MaxRetries {
+	case -1:
+		opt.MaxRetries = 0
+	case 0:
+		opt.MaxRetries = 3
+	}
+	if opt.MinRetryBackoff == 0 {
+		opt.MinRetryBackoff = 8 * time.Millisecond
+	}
+	if opt.MaxRetryBackoff == 0 {
+		opt.MaxRetryBackoff = 512 * time.Millisecond
+	}
+}
+
+// TSBufferError is returned by a flush that failed to write some samples.
+type TSBufferError struct {
+	// Samples are the samples that were not written, Errs their errors.
+	Samples []TSSample
+	Errs    []error
+}
+
+func (e *TSBufferError) Error() string {
+	return fmt.Sprintf("redis: failed to write %d samples: %v", len(e.Samples), e.Errs[0])
+}
+
+func (e *TSBufferError) Unwrap() error {
+	return e.Errs[0]
+}
+
+// TSBuffer accumulates time-series samples across many keys and writes them
+// with pipelined TS.MADD commands, one per key, so it works with cluster
+// clients too. The buffer is flushed when it holds MaxSamples samples, every
+// FlushInterval and on Close.
+//
+// TSBuffer is safe for concurrent use by multiple goroutines.
+type TSBuffer struct {
+	client Cmdable
+	opt    *TSBufferOptions
+
+	mu      sync.Mutex
+	samples []TSSample
+
+	flushMu sync.Mutex
+	created map[string]struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewTSBuffer returns a buffer writing the samples with client.
+func NewTSBuffer(client Cmdable, opt *TSBufferOptions) *TSBuffer {
+	if opt == nil {
+		opt = &TSBufferOptions{}
+	}
+	cp := *opt
+	cp.init()
+
+	b := &TSBuffer{
+		client:  client,
+		opt:     &cp,
+		samples: make([]TSSample, 0, cp.MaxSamples),
+		created: make(map[string]struct{}),
+		closed:  make(chan struct{}),
+	}
+	if cp.FlushInterval > 0 {
+		b.wg.Add(1)
+		go b.flusher()
+	}
+	return b
+}
+
+func (b *TSBuffer) flusher() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.opt.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Flush(context.Background()); err != nil {
+				b.onError(err)
+			}
+		case <-b.closed:
+			return
+		}
+	}
+}
+
+func (b *TSBuffer) onError(err error) {
+	if e, ok := err.(*TSBufferError); ok && b.opt.OnError != nil {
+		b.opt.OnError(e)
+		return
+	}
+	internal.Log(context.Background(), clientLogger(b.client), internal.LevelWarn,
+		"redis: TSBuffer flush failed", "error", err)
+}
+
+// Add buffers a sample of key. The buffer is flushed, and the error of the
+// flush returned, when it reaches MaxSamples.
+func (b *TSBuffer) Add(ctx context.Context, key string, timestamp interface{}, value float64) error {
+	b.mu.Lock()
+	b.samples = append(b.samples, TSSample{Key: key, Timestamp: timestamp, Value: value})
+	full := len(b.samples) >= b.opt.MaxSamples
+	b.mu.Unlock()
+
+	if full {
+		return b.Flush(ctx)
+	}
+	return nil
+}
+
+// Len returns the number of buffered samples.
+func (b *TSBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.samples)
+}
+
+// Flush writes the buffered samples. The samples still rejected after
+// MaxRetries retries are returned in a *TSBufferError.
+func (b *TSBuffer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	samples := b.samples
+	b.samples = make([]TSSample, 0, b.opt.MaxSamples)
+	b.mu.Unlock()
+
+	if len(samples) == 0 {
+		return nil
+	}
+
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	var errs []error
+	for attempt := 0; attempt <= b.opt.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := internal.Sleep(ctx, internal.RetryBackoff(attempt-1, b.opt.MinRetryBackoff, b.opt.MaxRetryBackoff)); err != nil {
+				return &TSBufferError{Samples: samples, Errs: fillErrs(len(samples), err)}
+			}
+		}
+
+		samples, errs = b.write(ctx, samples)
+		if len(samples) == 0 {
+			return nil
+		}
+	}
+	return &TSBufferError{Samples: samples, Errs: errs}
+}
+
+// write sends the samples and returns the rejected ones with their errors.
+func (b *TSBuffer) write(ctx context.Context, samples []TSSample) ([]TSSample, []error) {
+	var keys []string
+	byKey := make(map[string][]TSSample)
+	for _, s := range samples {
+		if _, ok := byKey[s.Key]; !ok {
+			keys = append(keys, s.Key)
+		}
+		byKey[s.Key] = append(byKey[s.Key], s)
+	}
+
+	pipe := b.client.Pipeline()
+	creates := make(map[string]*StatusCmd)
+	madds := make([]*Cmd, len(keys))
+	for i, key := range keys {
+		if _, ok := b.created[key]; !ok {
+			if opt := b.createOptions(key); opt != nil {
+				creates[key] = pipe.TSCreateWithArgs(ctx, key, opt)
+			} else {
+				b.created[key] = struct{}{}
+			}
+		}
+
+		keySamples := byKey[key]
+		args := make([]interface{}, 1, 1+3*len(keySamples))
+		args[0] = "TS.MADD"
+		for _, s := range keySamples {
+			args = append(args, s.Key, s.Timestamp, s.Value)
+		}
+		madds[i] = pipe.Do(ctx, args...)
+	}
+	_, _ = pipe.Exec(ctx)
+
+	for key, cmd := range creates {
+		if err := cmd.Err(); err == nil || isKeyExistsError(err) {
+			b.created[key] = struct{}{}
+		}
+	}
+
+	var rejected []TSSample
+	var errs []error
+	for i, key := range keys {
+		keySamples := byKey[key]
+		vals, err := madds[i].Slice()
+		if err == nil && len(vals) != len(keySamples) {
+			err = fmt.Errorf("redis: TS.MADD replied %d results for %d samples", len(vals), len(keySamples))
+		}
+		if err != nil {
+			rejected = append(rejected, keySamples...)
+			errs = append(errs, fillErrs(len(keySamples), err)...)
+			continue
+		}
+		for j, val := range vals {
+			if err, ok := val.(error); ok {
+				rejected = append(rejected, keySamples[j])
+				errs = append(errs, err)
+			}
+		}
+	}
+	return rejected, errs
+}
+
+func (b *TSBuffer) createOptions(key string) *TSOptions {
+	if b.opt.CreateOptions != nil {
+		return b.opt.CreateOptions(key)
+	}
+	return b.opt.Create
+}
+
+// Close stops the periodic flushes and flushes the buffered samples.
+func (b *TSBuffer) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	b.wg.Wait()
+	return b.Flush(context.Background())
+}
+
+func isKeyExistsError(err error) bool {
+	return isRedisError(err) && strings.Contains(internal.ToLower(err.Error()), "key already exists")
+}
+
+func fillErrs(n int, err error) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = err
+	}
+	return errs
+}
@@ -2,7 +2,9 @@ package redis
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/redis/go-redis/v9/internal/proto"
 )
@@ -35,6 +37,8 @@ type TimeseriesCmdable interface {
 	TSMRangeWithArgs(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRangeOptions) *MapStringSliceInterfaceCmd
 	TSMRevRange(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string) *MapStringSliceInterfaceCmd
 	TSMRevRangeWithArgs(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRevRangeOptions) *MapStringSliceInterfaceCmd
+	TSMRangeSeries(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRangeOptions) *TSSeriesSliceCmd
+	TSMRevRangeSeries(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRevRangeOptions) *TSSeriesSliceCmd
 	TSMGet(ctx context.Context, filters []string) *MapStringSliceInterfaceCmd
 	TSMGetWithArgs(ctx context.Context, filters []string, options *TSMGetOptions) *MapStringSliceInterfaceCmd
 }
@@ -743,7 +747,14 @@ func (c cmdable) TSMRange(ctx context.Context, fromTimestamp int, toTimestamp in
 // Empty, GroupByLabel and Reducer.
 // For more information - https://redis.io/commands/ts.mrange/
 func (c cmdable) TSMRangeWithArgs(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRangeOptions) *MapStringSliceInterfaceCmd {
-	args := []interface{}{"TS.MRANGE", fromTimestamp, toTimestamp}
+	args := tsMRangeArgs("TS.MRANGE", fromTimestamp, toTimestamp, filterExpr, options)
+	cmd := NewMapStringSliceInterfaceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+func tsMRangeArgs(name string, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRangeOptions) []interface{} {
+	args := []interface{}{name, fromTimestamp, toTimestamp}
 	if options != nil {
 		if options.Latest {
 			args = append(args, "LATEST")
@@ -798,9 +809,7 @@ func (c cmdable) TSMRangeWithArgs(ctx context.Context, fromTimestamp int, toTime
 			args = append(args, "REDUCE", options.Reducer)
 		}
 	}
-	cmd := NewMapStringSliceInterfaceCmd(ctx, args...)
-	_ = c(ctx, cmd)
-	return cmd
+	return args
 }
 
 // TSMRevRange - Returns a range of samples from multiple time-series keys in reverse order.
@@ -822,64 +831,228 @@ func (c cmdable) TSMRevRange(ctx context.Context, fromTimestamp int, toTimestamp
 // Empty, GroupByLabel and Reducer.
 // For more information - https://redis.io/commands/ts.mrevrange/
 func (c cmdable) TSMRevRangeWithArgs(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRevRangeOptions) *MapStringSliceInterfaceCmd {
-	args := []interface{}{"TS.MREVRANGE", fromTimestamp, toTimestamp}
-	if options != nil {
-		if options.Latest {
-			args = append(args, "LATEST")
+	args := tsMRangeArgs("TS.MREVRANGE", fromTimestamp, toTimestamp, filterExpr, (*TSMRangeOptions)(options))
+	cmd := NewMapStringSliceInterfaceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSMRangeSeries - Returns a range of samples from multiple time-series keys
+// decoded into typed series, with their labels and, for GROUPBY queries, the
+// reducer and the source keys of each group.
+// For more information - https://redis.io/commands/ts.mrange/
+func (c cmdable) TSMRangeSeries(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRangeOptions) *TSSeriesSliceCmd {
+	args := tsMRangeArgs("TS.MRANGE", fromTimestamp, toTimestamp, filterExpr, options)
+	cmd := NewTSSeriesSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSMRevRangeSeries - Returns a range of samples from multiple time-series keys
+// in reverse order decoded into typed series, see TSMRangeSeries.
+// For more information - https://redis.io/commands/ts.mrevrange/
+func (c cmdable) TSMRevRangeSeries(ctx context.Context, fromTimestamp int, toTimestamp int, filterExpr []string, options *TSMRevRangeOptions) *TSSeriesSliceCmd {
+	args := tsMRangeArgs("TS.MREVRANGE", fromTimestamp, toTimestamp, filterExpr, (*TSMRangeOptions)(options))
+	cmd := NewTSSeriesSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// TSSeries is a series returned by TS.MRANGE and TS.MREVRANGE.
+type TSSeries struct {
+	// Key is the key of the series, or "label=value" for GROUPBY queries.
+	Key string
+	// Labels are the labels returned with WITHLABELS or SELECTED_LABELS.
+	Labels map[string]string
+	// Reducer and Sources are the reducer and the keys of the series of a GROUPBY group.
+	Reducer    string
+	Sources    []string
+	DataPoints []TSTimestampValue
+}
+
+type TSSeriesSliceCmd struct {
+	baseCmd
+	val []TSSeries
+}
+
+var _ Cmder = (*TSSeriesSliceCmd)(nil)
+
+func NewTSSeriesSliceCmd(ctx context.Context, args ...interface{}) *TSSeriesSliceCmd {
+	return &TSSeriesSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *TSSeriesSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *TSSeriesSliceCmd) SetVal(val []TSSeries) {
+	cmd.val = val
+}
+
+func (cmd *TSSeriesSliceCmd) Result() ([]TSSeries, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *TSSeriesSliceCmd) Val() []TSSeries {
+	return cmd.val
+}
+
+// Map returns the series by key.
+func (cmd *TSSeriesSliceCmd) Map() map[string]TSSeries {
+	m := make(map[string]TSSeries, len(cmd.val))
+	for _, s := range cmd.val {
+		m[s.Key] = s
+	}
+	return m
+}
+
+func (cmd *TSSeriesSliceCmd) readReply(rd *proto.Reader) (err error) {
+	typ, err := rd.PeekReplyType()
+	if err != nil {
+		return err
+	}
+
+	// RESP2 replies with an array of [key, labels, samples],
+	// RESP3 with a map of the keys to [labels, metadata..., samples].
+	if typ == proto.RespMap {
+		n, err := rd.ReadMapLen()
+		if err != nil {
+			return err
 		}
-		if options.FilterByTS != nil {
-			args = append(args, "FILTER_BY_TS")
-			for _, f := range options.FilterByTS {
-				args = append(args, f)
+		cmd.val = make([]TSSeries, n)
+		for i := 0; i < n; i++ {
+			if cmd.val[i].Key, err = rd.ReadString(); err != nil {
+				return err
 			}
-		}
-		if options.FilterByValue != nil {
-			args = append(args, "FILTER_BY_VALUE")
-			for _, f := range options.FilterByValue {
-				args = append(args, f)
+			v, err := rd.ReadReply()
+			if err != nil {
+				return err
+			}
+			items, _ := v.([]interface{})
+			if err := cmd.val[i].parse(items); err != nil {
+				return err
 			}
 		}
-		if options.WithLabels {
-			args = append(args, "WITHLABELS")
-		}
-		if options.SelectedLabels != nil {
-			args = append(args, "SELECTED_LABELS")
-			args = append(args, options.SelectedLabels...)
-		}
-		if options.Count != 0 {
-			args = append(args, "COUNT", options.Count)
-		}
-		if options.Align != nil {
-			args = append(args, "ALIGN", options.Align)
+		return nil
+	}
+
+	n, err := rd.ReadArrayLen()
+	if err != nil {
+		return err
+	}
+	cmd.val = make([]TSSeries, n)
+	for i := 0; i < n; i++ {
+		v, err := rd.ReadReply()
+		if err != nil {
+			return err
 		}
-		if options.Aggregator != 0 {
-			args = append(args, "AGGREGATION", options.Aggregator.String())
+		items, _ := v.([]interface{})
+		if len(items) == 0 {
+			return fmt.Errorf("redis: unexpected series reply %v", v)
 		}
-		if options.BucketDuration != 0 {
-			args = append(args, options.BucketDuration)
+		key, ok := items[0].(string)
+		if !ok {
+			return fmt.Errorf("redis: unexpected series key %v", items[0])
 		}
-		if options.BucketTimestamp != nil {
-			args = append(args, "BUCKETTIMESTAMP", options.BucketTimestamp)
-		}
-		if options.Empty {
-			args = append(args, "EMPTY")
+		cmd.val[i].Key = key
+		if err := cmd.val[i].parse(items[1:]); err != nil {
+			return err
 		}
 	}
-	args = append(args, "FILTER")
-	for _, f := range filterExpr {
-		args = append(args, f)
+	return nil
+}
+
+// parse decodes the labels, the metadata and the samples of a series.
+// The samples are the last item of the reply, the labels the first one.
+func (s *TSSeries) parse(items []interface{}) error {
+	if len(items) == 0 {
+		return fmt.Errorf("redis: unexpected series reply for %s", s.Key)
 	}
-	if options != nil {
-		if options.GroupByLabel != nil {
-			args = append(args, "GROUPBY", options.GroupByLabel)
+
+	s.Labels = make(map[string]string)
+	for _, item := range items[:len(items)-1] {
+		switch item := item.(type) {
+		case []interface{}:
+			// RESP2 labels: [[name, value], ...].
+			for _, pair := range item {
+				kv, ok := pair.([]interface{})
+				if !ok || len(kv) != 2 {
+					continue
+				}
+				name, _ := kv[0].(string)
+				value, _ := kv[1].(string)
+				s.addLabel(name, value)
+			}
+		case map[interface{}]interface{}:
+			// RESP3 labels and metadata maps.
+			for k, v := range item {
+				name, _ := k.(string)
+				switch name {
+				case "reducers":
+					if vs, ok := v.([]interface{}); ok && len(vs) > 0 {
+						s.Reducer, _ = vs[0].(string)
+					}
+				case "sources":
+					vs, _ := v.([]interface{})
+					for _, src := range vs {
+						if src, ok := src.(string); ok {
+							s.Sources = append(s.Sources, src)
+						}
+					}
+				case "aggregators":
+				default:
+					value, _ := v.(string)
+					s.addLabel(name, value)
+				}
+			}
 		}
-		if options.Reducer != nil {
-			args = append(args, "REDUCE", options.Reducer)
+	}
+
+	samples, _ := items[len(items)-1].([]interface{})
+	s.DataPoints = make([]TSTimestampValue, 0, len(samples))
+	for _, sample := range samples {
+		pair, ok := sample.([]interface{})
+		if !ok || len(pair) != 2 {
+			return fmt.Errorf("redis: unexpected sample %v", sample)
+		}
+		ts, ok := pair[0].(int64)
+		if !ok {
+			return fmt.Errorf("redis: unexpected sample timestamp %v", pair[0])
+		}
+		var value float64
+		switch v := pair[1].(type) {
+		case float64:
+			value = v
+		case string:
+			var err error
+			if value, err = strconv.ParseFloat(v, 64); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("redis: unexpected sample value %v", pair[1])
 		}
+		s.DataPoints = append(s.DataPoints, TSTimestampValue{Timestamp: ts, Value: value})
+	}
+	return nil
+}
+
+func (s *TSSeries) addLabel(name, value string) {
+	// GROUPBY replies of RESP2 carry the reducer and the sources as labels.
+	switch name {
+	case "__reducer__":
+		s.Reducer = value
+	case "__source__":
+		if value != "" {
+			s.Sources = strings.Split(value, ",")
+		}
+	default:
+		s.Labels[name] = value
 	}
-	cmd := NewMapStringSliceInterfaceCmd(ctx, args...)
-	_ = c(ctx, cmd)
-	return cmd
 }
 
 // TSMGet - Returns the last sample of multiple time-series keys.
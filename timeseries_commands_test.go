@@ -2,6 +2,7 @@ package redis_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	. "github.com/bsm/ginkgo/v2"
@@ -320,6 +321,77 @@ var _ = Describe("RedisTimeseries commands", Label("timeseries"), func() {
 		Expect(result.Value).To(BeEquivalentTo(8))
 	})
 
+	It("should TSEnsureCompactions", Label("timeseries", "tscreaterule", "tsdeleterule"), func() {
+		Expect(client.TSCreate(ctx, "src").Err()).NotTo(HaveOccurred())
+		Expect(client.TSCreate(ctx, "old").Err()).NotTo(HaveOccurred())
+		Expect(client.TSCreateRule(ctx, "src", "old", redis.Max, 1000).Err()).NotTo(HaveOccurred())
+
+		rules := []redis.TSCompaction{
+			{DestKey: "src:avg:1m", Aggregator: redis.Avg, BucketDuration: 60000, Retention: 86400000},
+			{DestKey: "src:max:1h", Aggregator: redis.Max, BucketDuration: 3600000},
+		}
+		plan, err := redis.TSEnsureCompactions(ctx, client, "src", rules)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Create).To(HaveLen(2))
+		Expect(plan.Delete).To(HaveLen(1))
+		Expect(plan.Delete[0].DestKey).To(BeEquivalentTo("old"))
+
+		info, err := client.TSInfo(ctx, "src:avg:1m").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info["retentionTime"]).To(BeEquivalentTo(86400000))
+
+		plan, err = redis.TSPlanCompactions(ctx, client, "src", rules)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+
+		rules[0].Retention = 3600000
+		rules[1].BucketDuration = 7200000
+		plan, err = redis.TSEnsureCompactions(ctx, client, "src", rules)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Alter).To(HaveLen(1))
+		Expect(plan.Delete).To(HaveLen(1))
+		Expect(plan.Create).To(HaveLen(1))
+		Expect(plan.Create[0].BucketDuration).To(BeEquivalentTo(7200000))
+
+		plan, err = redis.TSPlanCompactions(ctx, client, "src", rules)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Empty()).To(BeTrue())
+	})
+
+	It("should TSRangeAuto", Label("timeseries", "tsrange"), func() {
+		Expect(client.TSCreate(ctx, "src").Err()).NotTo(HaveOccurred())
+		for i := 0; i < 100; i++ {
+			Expect(client.TSAdd(ctx, "src", i*1000, float64(i)).Err()).NotTo(HaveOccurred())
+		}
+
+		res, err := redis.TSRangeAuto(ctx, client, "src", 0, 99000, &redis.TSAutoRangeOptions{MaxDataPoints: 10, Aggregator: redis.Max})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Key).To(BeEquivalentTo("src"))
+		Expect(res.BucketDuration).To(BeEquivalentTo(10000))
+		Expect(res.DataPoints).To(HaveLen(10))
+		Expect(res.DataPoints[0]).To(BeEquivalentTo(redis.TSTimestampValue{Timestamp: 0, Value: 9}))
+
+		res, err = redis.TSRangeAuto(ctx, client, "src", 0, 9, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.BucketDuration).To(BeEquivalentTo(0))
+		Expect(res.DataPoints).To(HaveLen(1))
+
+		_, err = redis.TSEnsureCompactions(ctx, client, "src", []redis.TSCompaction{
+			{DestKey: "src:max:5s", Aggregator: redis.Max, BucketDuration: 5000},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		for i := 100; i < 200; i++ {
+			Expect(client.TSAdd(ctx, "src", i*1000, float64(i)).Err()).NotTo(HaveOccurred())
+		}
+
+		res, err = redis.TSRangeAuto(ctx, client, "src", 100000, 199000, &redis.TSAutoRangeOptions{MaxDataPoints: 10, Aggregator: redis.Max})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Key).To(BeEquivalentTo("src:max:5s"))
+		Expect(res.BucketDuration).To(BeEquivalentTo(10000))
+		Expect(res.DataPoints).To(HaveLen(10))
+		Expect(res.DataPoints[0]).To(BeEquivalentTo(redis.TSTimestampValue{Timestamp: 100000, Value: 109}))
+	})
+
 	It("should TSInfo", Label("timeseries", "tsinfo"), func() {
 		resultGet, err := client.TSAdd(ctx, "foo", 2265985, 151).Result()
 		Expect(err).NotTo(HaveOccurred())
@@ -757,6 +829,45 @@ var _ = Describe("RedisTimeseries commands", Label("timeseries"), func() {
 		Expect(result["a"][2]).To(BeEquivalentTo([]interface{}{[]interface{}{int64(0), 5.0}, []interface{}{int64(5), 6.0}}))
 	})
 
+	It("should TSMRangeSeries and TSMRevRangeSeries", Label("timeseries", "tsmrange", "tsmrangeseries"), func() {
+		for _, protocol := range []int{2, 3} {
+			c := redis.NewClient(&redis.Options{Addr: rediStackAddr, Protocol: protocol})
+			Expect(c.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+			opt := &redis.TSOptions{Labels: map[string]string{"Test": "This", "team": "ny"}}
+			Expect(c.TSCreateWithArgs(ctx, "a", opt).Err()).NotTo(HaveOccurred())
+			opt = &redis.TSOptions{Labels: map[string]string{"Test": "This", "team": "ny"}}
+			Expect(c.TSCreateWithArgs(ctx, "b", opt).Err()).NotTo(HaveOccurred())
+			for i := 0; i < 3; i++ {
+				Expect(c.TSAdd(ctx, "a", i, float64(i)).Err()).NotTo(HaveOccurred())
+				Expect(c.TSAdd(ctx, "b", i, float64(i*2)).Err()).NotTo(HaveOccurred())
+			}
+
+			series, err := c.TSMRangeSeries(ctx, 0, 10, []string{"Test=This"}, &redis.TSMRangeOptions{WithLabels: true}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(series).To(HaveLen(2))
+			byKey := c.TSMRangeSeries(ctx, 0, 10, []string{"Test=This"}, &redis.TSMRangeOptions{WithLabels: true}).Map()
+			Expect(byKey["a"].Labels).To(Equal(map[string]string{"Test": "This", "team": "ny"}))
+			Expect(byKey["b"].DataPoints).To(Equal([]redis.TSTimestampValue{{Timestamp: 0, Value: 0}, {Timestamp: 1, Value: 2}, {Timestamp: 2, Value: 4}}))
+
+			series, err = c.TSMRangeSeries(ctx, 0, 10, []string{"Test=This"}, &redis.TSMRangeOptions{GroupByLabel: "team", Reducer: "max"}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(series).To(HaveLen(1))
+			Expect(series[0].Key).To(Equal("team=ny"))
+			Expect(series[0].Reducer).To(Equal("max"))
+			Expect(series[0].Sources).To(ConsistOf("a", "b"))
+			Expect(series[0].DataPoints).To(Equal([]redis.TSTimestampValue{{Timestamp: 0, Value: 0}, {Timestamp: 1, Value: 2}, {Timestamp: 2, Value: 4}}))
+
+			series, err = c.TSMRevRangeSeries(ctx, 0, 10, []string{"Test=This"}, &redis.TSMRevRangeOptions{Count: 1}).Result()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(series).To(HaveLen(2))
+			Expect(series[0].DataPoints).To(HaveLen(1))
+			Expect(series[0].DataPoints[0].Timestamp).To(BeEquivalentTo(2))
+
+			Expect(c.Close()).NotTo(HaveOccurred())
+		}
+	})
+
 	It("should TSMRangeWithArgs Latest", Label("timeseries", "tsmrangeWithArgs", "tsmrangelatest", "NonRedisEnterprise"), func() {
 		resultCreate, err := client.TSCreate(ctx, "a").Result()
 		Expect(err).NotTo(HaveOccurred())
@@ -937,4 +1048,35 @@ var _ = Describe("RedisTimeseries commands", Label("timeseries"), func() {
 		Expect(result["b"][2]).To(BeEquivalentTo([]interface{}{[]interface{}{int64(10), 8.0}, []interface{}{int64(0), 4.0}}))
 		Expect(result["d"][2]).To(BeEquivalentTo([]interface{}{[]interface{}{int64(10), 8.0}, []interface{}{int64(0), 4.0}}))
 	})
+
+	It("should buffer samples with TSBuffer", Label("timeseries", "tsbuffer"), func() {
+		buf := redis.NewTSBuffer(client, &redis.TSBufferOptions{
+			MaxSamples:    4,
+			FlushInterval: -1,
+			Create:        &redis.TSOptions{Retention: 100000, DuplicatePolicy: "LAST"},
+		})
+
+		Expect(buf.Add(ctx, "a", 1, 1)).NotTo(HaveOccurred())
+		Expect(buf.Add(ctx, "b", 1, 10)).NotTo(HaveOccurred())
+		Expect(buf.Add(ctx, "a", 2, 2)).NotTo(HaveOccurred())
+		Expect(buf.Len()).To(Equal(3))
+		Expect(client.Exists(ctx, "a", "b").Val()).To(BeEquivalentTo(0))
+
+		Expect(buf.Add(ctx, "a", 2, 3)).NotTo(HaveOccurred())
+		Expect(buf.Len()).To(Equal(0))
+		Expect(client.TSRange(ctx, "a", 0, 10).Val()).To(Equal([]redis.TSTimestampValue{{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 3}}))
+		info, err := client.TSInfo(ctx, "b").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info["retentionTime"]).To(BeEquivalentTo(100000))
+
+		Expect(client.Set(ctx, "notts", "x", 0).Err()).NotTo(HaveOccurred())
+		Expect(buf.Add(ctx, "b", 2, 20)).NotTo(HaveOccurred())
+		Expect(buf.Add(ctx, "notts", 2, 20)).NotTo(HaveOccurred())
+		err = buf.Close()
+		Expect(err).To(HaveOccurred())
+		var bufErr *redis.TSBufferError
+		Expect(errors.As(err, &bufErr)).To(BeTrue())
+		Expect(bufErr.Samples).To(Equal([]redis.TSSample{{Key: "notts", Timestamp: 2, Value: 20}}))
+		Expect(client.TSRange(ctx, "b", 0, 10).Val()).To(HaveLen(2))
+	})
 })
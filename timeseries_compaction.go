@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9/internal"
+)
+
+// TSCompaction is a downsampling rule of a source key, compacting its samples
+// into DestKey with TS.CREATERULE.
+type TSCompaction struct {
+	DestKey        string
+	Aggregator     Aggregator
+	BucketDuration int
+	AlignTimestamp int64
+
+	// Retention of DestKey. Zero leaves the retention of the key as is.
+	Retention int
+	// Labels of DestKey, used when the key is created.
+	Labels map[string]string
+}
+
+func (r *TSCompaction) sameRule(other *TSCompaction) bool {
+	return r.Aggregator == other.Aggregator &&
+		r.BucketDuration == other.BucketDuration &&
+		r.AlignTimestamp == other.AlignTimestamp
+}
+
+// TSCompactionPlan lists the changes converging the rules of a source key.
+type TSCompactionPlan struct {
+	// Create are the rules to create. Their missing destination keys are created first.
+	Create []TSCompaction
+	// Delete are the existing rules to delete. A rule whose aggregation,
+	// bucket or alignment changed is deleted and created again.
+	Delete []TSCompaction
+	// Alter are the rules whose destination key retention changes.
+	Alter []TSCompaction
+}
+
+// Empty reports whether the rules are already converged.
+func (p *TSCompactionPlan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Delete) == 0 && len(p.Alter) == 0
+}
+
+// TSPlanCompactions compares the desired rules of sourceKey with the rules
+// reported by TS.INFO and returns the changes TSEnsureCompactions would make.
+// Rules of sourceKey that are not desired are deleted.
+func TSPlanCompactions(ctx context.Context, client TimeseriesCmdable, sourceKey string, rules []TSCompaction) (*TSCompactionPlan, error) {
+	if err := validateCompactions(rules); err != nil {
+		return nil, err
+	}
+
+	info, err := client.TSInfo(ctx, sourceKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	current, err := parseTSInfoRules(info["rules"])
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(TSCompactionPlan)
+	existing := make(map[string]*TSCompaction, len(current))
+	for i := range current {
+		existing[current[i].DestKey] = &current[i]
+	}
+
+	for _, rule := range rules {
+		cur, ok := existing[rule.DestKey]
+		delete(existing, rule.DestKey)
+		if ok && !cur.sameRule(&rule) {
+			plan.Delete = append(plan.Delete, *cur)
+		}
+		if !ok || !cur.sameRule(&rule) {
+			plan.Create = append(plan.Create, rule)
+			continue
+		}
+
+		if rule.Retention > 0 {
+			destInfo, err := client.TSInfo(ctx, rule.DestKey).Result()
+			if err != nil {
+				return nil, err
+			}
+			if retention, _ := toInt64(destInfo["retentionTime"]); retention != int64(rule.Retention) {
+				plan.Alter = append(plan.Alter, rule)
+			}
+		}
+	}
+
+	for _, cur := range current {
+		if _, ok := existing[cur.DestKey]; ok {
+			plan.Delete = append(plan.Delete, cur)
+		}
+	}
+	return plan, nil
+}
+
+// TSEnsureCompactions converges the rules of sourceKey to rules: missing rules
+// are created along with their destination keys, changed rules are recreated,
+// and rules that are not desired are deleted. Destination keys are never deleted.
+// It returns the plan that was applied, see TSPlanCompactions.
+func TSEnsureCompactions(ctx context.Context, client TimeseriesCmdable, sourceKey string, rules []TSCompaction) (*TSCompactionPlan, error) {
+	plan, err := TSPlanCompactions(ctx, client, sourceKey, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range plan.Delete {
+		if err := client.TSDeleteRule(ctx, sourceKey, rule.DestKey).Err(); err != nil {
+			return plan, err
+		}
+	}
+	for _, rule := range plan.Create {
+		if err := ensureCompactionKey(ctx, client, &rule); err != nil {
+			return plan, err
+		}
+		err := client.TSCreateRuleWithArgs(ctx, sourceKey, rule.DestKey, rule.Aggregator, rule.BucketDuration,
+			&TSCreateRuleOptions{alignTimestamp: rule.AlignTimestamp}).Err()
+		if err != nil {
+			return plan, err
+		}
+	}
+	for _, rule := range plan.Alter {
+		if err := client.TSAlter(ctx, rule.DestKey, &TSAlterOptions{Retention: rule.Retention}).Err(); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// ensureCompactionKey creates the destination key of rule, or updates its
+// retention when it already exists.
+func ensureCompactionKey(ctx context.Context, client TimeseriesCmdable, rule *TSCompaction) error {
+	info, err := client.TSInfo(ctx, rule.DestKey).Result()
+	if err != nil {
+		if !isKeyNotExistError(err) {
+			return err
+		}
+		return client.TSCreateWithArgs(ctx, rule.DestKey, &TSOptions{Retention: rule.Retention, Labels: rule.Labels}).Err()
+	}
+	if rule.Retention > 0 {
+		if retention, _ := toInt64(info["retentionTime"]); retention != int64(rule.Retention) {
+			return client.TSAlter(ctx, rule.DestKey, &TSAlterOptions{Retention: rule.Retention}).Err()
+		}
+	}
+	return nil
+}
+
+func validateCompactions(rules []TSCompaction) error {
+	seen := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if rule.DestKey == "" {
+			return fmt.Errorf("redis: compaction rule requires a DestKey")
+		}
+		if _, ok := seen[rule.DestKey]; ok {
+			return fmt.Errorf("redis: duplicate compaction rule for %q", rule.DestKey)
+		}
+		seen[rule.DestKey] = struct{}{}
+		if rule.Aggregator.String() == "" {
+			return fmt.Errorf("redis: compaction rule for %q requires an Aggregator", rule.DestKey)
+		}
+		if rule.BucketDuration <= 0 {
+			return fmt.Errorf("redis: compaction rule for %q requires a positive BucketDuration", rule.DestKey)
+		}
+	}
+	return nil
+}
+
+// parseTSInfoRules parses the rules of TS.INFO: a list of
+// [dest, bucket, aggregator, align] in RESP2, a map of dest to
+// [bucket, aggregator, align] in RESP3.
+func parseTSInfoRules(val interface{}) ([]TSCompaction, error) {
+	switch val := val.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		rules := make([]TSCompaction, 0, len(val))
+		for _, v := range val {
+			fields, ok := v.([]interface{})
+			if !ok || len(fields) == 0 {
+				return nil, fmt.Errorf("redis: unexpected TS.INFO rule %v", v)
+			}
+			rule, err := parseTSInfoRule(fields[0], fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+		return rules, nil
+	case map[interface{}]interface{}:
+		rules := make([]TSCompaction, 0, len(val))
+		for dest, v := range val {
+			fields, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("redis: unexpected TS.INFO rule %v", v)
+			}
+			rule, err := parseTSInfoRule(dest, fields)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+		sort.Slice(rules, func(i, j int) bool { return rules[i].DestKey < rules[j].DestKey })
+		return rules, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected type=%T for TS.INFO rules", val)
+	}
+}
+
+func parseTSInfoRule(dest interface{}, fields []interface{}) (TSCompaction, error) {
+	var rule TSCompaction
+	var err error
+	if rule.DestKey, err = toString(dest); err != nil {
+		return rule, err
+	}
+	if len(fields) < 2 {
+		return rule, fmt.Errorf("redis: unexpected TS.INFO rule of %q: %v", rule.DestKey, fields)
+	}
+
+	bucket, err := toInt64(fields[0])
+	if err != nil {
+		return rule, err
+	}
+	rule.BucketDuration = int(bucket)
+
+	name, err := toString(fields[1])
+	if err != nil {
+		return rule, err
+	}
+	if rule.Aggregator = parseAggregator(name); rule.Aggregator == Invalid {
+		return rule, fmt.Errorf("redis: unknown aggregator %q of rule %q", name, rule.DestKey)
+	}
+
+	if len(fields) > 2 {
+		if rule.AlignTimestamp, err = toInt64(fields[2]); err != nil {
+			return rule, err
+		}
+	}
+	return rule, nil
+}
+
+func parseAggregator(s string) Aggregator {
+	s = strings.ToUpper(s)
+	for a := Avg; a <= Twa; a++ {
+		if a.String() == s {
+			return a
+		}
+	}
+	return Invalid
+}
+
+func isKeyNotExistError(err error) bool {
+	return isRedisError(err) && strings.Contains(internal.ToLower(err.Error()), "key does not exist")
+}
@@ -0,0 +1,227 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal"
+	"github.com/redis/go-redis/v9/internal/pool"
+)
+
+// TLSFiles loads the client certificate and the CAs of the TLS connections
+// from PEM files, and reloads them when the files are modified, e.g. for the
+// short-lived certificates issued by a service mesh. The reloaded certificates
+// are used by the new connections; with RecycleConns, the existing connections
+// are also closed as they are released, to be dialed again.
+//
+// The files complete Options.TLSConfig, when set. A TLSFiles can be shared by
+// several clients, which then share the reloads of its files.
+type TLSFiles struct {
+	// CertFile and KeyFile are the client certificate and its private key.
+	// Default is no client certificate, or the certificates of TLSConfig.
+	CertFile, KeyFile string
+	// CAFile is the bundle of the CAs verifying the server certificates.
+	// Default is the CAs of TLSConfig, or of the system.
+	CAFile string
+
+	// ReloadInterval is the interval between the checks of the modification
+	// times of the files. Default is 1 minute.
+	ReloadInterval time.Duration
+	// RecycleConns closes the connections dialed before a reload. By default
+	// only the new connections use the reloaded certificates.
+	RecycleConns bool
+	// OnReload is called after each reload of the modified files, with the
+	// error loading them: the previous certificates are kept until the files
+	// are loaded successfully.
+	OnReload func(err error)
+
+	loadOnce sync.Once
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	roots    *x509.CertPool
+	modTimes [3]time.Time
+	loadErr  error
+
+	subsMu  sync.Mutex
+	subs    map[int]func(error)
+	nextSub int
+	stop    chan struct{}
+}
+
+func (f *TLSFiles) reloadInterval() time.Duration {
+	if f.ReloadInterval <= 0 {
+		return time.Minute
+	}
+	return f.ReloadInterval
+}
+
+// load loads the files the first time it is called, logging the error with
+// logger.
+func (f *TLSFiles) load(logger Logger) {
+	f.loadOnce.Do(func() {
+		if _, err := f.reload(); err != nil {
+			internal.Log(context.Background(), logger, internal.LevelWarn,
+				"redis: loading TLS files failed", "error", err)
+		}
+	})
+}
+
+func (f *TLSFiles) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.cert == nil {
+		return nil, f.loadErr
+	}
+	return f.cert, nil
+}
+
+// rootCAs returns the CAs of CAFile, or the error loading them.
+func (f *TLSFiles) rootCAs() (*x509.CertPool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.roots == nil {
+		return nil, f.loadErr
+	}
+	return f.roots, nil
+}
+
+// reload loads the files when they were modified since the last load, and
+// reports whether they were.
+func (f *TLSFiles) reload() (bool, error) {
+	var modTimes [3]time.Time
+	for i, name := range []string{f.CertFile, f.KeyFile, f.CAFile} {
+		if name == "" {
+			continue
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return false, f.setErr(err)
+		}
+		modTimes[i] = fi.ModTime()
+	}
+
+	f.mu.RLock()
+	modified := modTimes != f.modTimes
+	f.mu.RUnlock()
+	if !modified {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if f.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+		if err != nil {
+			return false, f.setErr(err)
+		}
+		cert = &c
+	}
+	var roots *x509.CertPool
+	if f.CAFile != "" {
+		pem, err := os.ReadFile(f.CAFile)
+		if err != nil {
+			return false, f.setErr(err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return false, f.setErr(fmt.Errorf("redis: no certificate in %s", f.CAFile))
+		}
+	}
+
+	f.mu.Lock()
+	f.cert, f.roots, f.modTimes, f.loadErr = cert, roots, modTimes, nil
+	f.mu.Unlock()
+	return true, nil
+}
+
+func (f *TLSFiles) setErr(err error) error {
+	f.mu.Lock()
+	f.loadErr = err
+	f.mu.Unlock()
+	return err
+}
+
+// subscribe calls fn after each reload of the files, with the error loading
+// them, until unsubscribe is called. The files are checked while there are
+// subscribers.
+func (f *TLSFiles) subscribe(fn func(err error)) (unsubscribe func()) {
+	f.subsMu.Lock()
+	defer f.subsMu.Unlock()
+
+	if f.subs == nil {
+		f.subs = make(map[int]func(error))
+	}
+	id := f.nextSub
+	f.nextSub++
+	f.subs[id] = fn
+	if len(f.subs) == 1 {
+		f.stop = make(chan struct{})
+		go f.watch(f.stop)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			f.subsMu.Lock()
+			defer f.subsMu.Unlock()
+			delete(f.subs, id)
+			if len(f.subs) == 0 {
+				close(f.stop)
+			}
+		})
+	}
+}
+
+func (f *TLSFiles) watch(stop chan struct{}) {
+	ticker := time.NewTicker(f.reloadInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+
+		reloaded, err := f.reload()
+		if !reloaded && err == nil {
+			continue
+		}
+		if f.OnReload != nil {
+			f.OnReload(err)
+		}
+
+		f.subsMu.Lock()
+		subs := make([]func(error), 0, len(f.subs))
+		for _, fn := range f.subs {
+			subs = append(subs, fn)
+		}
+		f.subsMu.Unlock()
+		for _, fn := range subs {
+			fn(err)
+		}
+	}
+}
+
+// watchTLSFiles subscribes the client to the reloads of its TLS files.
+func (c *baseClient) watchTLSFiles() {
+	files := c.opt.TLSFiles
+	c.unwatchTLSFiles = files.subscribe(func(err error) {
+		if err != nil {
+			// the files are shared by the clients, which log with their logger
+			internal.Log(context.Background(), c.opt.Logger, internal.LevelWarn,
+				"redis: reloading TLS files failed", "error", err)
+			return
+		}
+		if !files.RecycleConns {
+			return
+		}
+		if p, ok := c.connPool.(*pool.ConnPool); ok {
+			p.RetireConns()
+		}
+	})
+}
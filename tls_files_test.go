@@ -0,0 +1,260 @@
+package redis_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// testCA issues the certificates of the TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue returns the PEM certificate and key of name, with the URI SANs uris.
+func (ca *testCA) issue(t *testing.T, name string, serial int64, uris ...string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, uri := range uris {
+		u, err := url.Parse(uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.URIs = append(tmpl.URIs, u)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// tlsProxy terminates the TLS connections of the clients, which must present
+// a certificate of ca, and forwards them to addr. Its certificate has the URI
+// SANs uris. It records the common names of the client certificates.
+type tlsProxy struct {
+	ln net.Listener
+
+	mu    sync.Mutex
+	names []string
+}
+
+func newTLSProxy(t *testing.T, ca *testCA, addr string, uris ...string) *tlsProxy {
+	certPEM, keyPEM := ca.issue(t, "localhost", 100, uris...)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &tlsProxy{ln: ln}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			cn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go p.forward(cn.(*tls.Conn), addr)
+		}
+	}()
+	return p
+}
+
+func (p *tlsProxy) forward(cn *tls.Conn, addr string) {
+	defer cn.Close()
+	if err := cn.Handshake(); err != nil {
+		return
+	}
+	p.mu.Lock()
+	p.names = append(p.names, cn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	p.mu.Unlock()
+
+	backend, err := net.Dial("tcp", addr)
+	if err != nil {
+		return
+	}
+	defer backend.Close()
+	go func() { _, _ = io.Copy(backend, cn) }()
+	_, _ = io.Copy(cn, backend)
+}
+
+func (p *tlsProxy) clientNames() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.names...)
+}
+
+func writeFile(t *testing.T, name string, data []byte, modTime time.Time) {
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTLSFilesReload(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	ca := newTestCA(t)
+	proxy := newTLSProxy(t, ca, srv.Addr())
+
+	dir := t.TempDir()
+	files := &redis.TLSFiles{
+		CertFile:       filepath.Join(dir, "client.pem"),
+		KeyFile:        filepath.Join(dir, "client-key.pem"),
+		CAFile:         filepath.Join(dir, "ca.pem"),
+		ReloadInterval: 10 * time.Millisecond,
+		RecycleConns:   true,
+	}
+	modTime := time.Now().Add(-time.Minute)
+	certPEM, keyPEM := ca.issue(t, "first", 2)
+	writeFile(t, files.CertFile, certPEM, modTime)
+	writeFile(t, files.KeyFile, keyPEM, modTime)
+	writeFile(t, files.CAFile, ca.pem, modTime)
+
+	reloaded := make(chan error, 1)
+	files.OnReload = func(err error) { reloaded <- err }
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     proxy.ln.Addr().String(),
+		TLSFiles: files,
+	})
+	defer rdb.Close()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, keyPEM = ca.issue(t, "second", 3)
+	writeFile(t, files.CertFile, certPEM, modTime.Add(time.Second))
+	writeFile(t, files.KeyFile, keyPEM, modTime.Add(time.Second))
+	// the reload may fail between the writes of the certificate and the key
+	for {
+		var err error
+		select {
+		case err = <-reloaded:
+		case <-time.After(time.Second):
+			t.Fatal("the files were not reloaded")
+		}
+		if err == nil {
+			break
+		}
+	}
+
+	// the connection of the first certificate was recycled
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	names := proxy.clientNames()
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Fatalf("got client certificates %q", names)
+	}
+}
+
+func TestTLSFilesUnknownCA(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	proxy := newTLSProxy(t, newTestCA(t), srv.Addr())
+
+	// the client trusts another CA than the CA of the server
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	files := &redis.TLSFiles{
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+		CAFile:   filepath.Join(dir, "ca.pem"),
+	}
+	certPEM, keyPEM := ca.issue(t, "client", 2)
+	writeFile(t, files.CertFile, certPEM, time.Now())
+	writeFile(t, files.KeyFile, keyPEM, time.Now())
+	writeFile(t, files.CAFile, ca.pem, time.Now())
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       proxy.ln.Addr().String(),
+		TLSFiles:   files,
+		MaxRetries: -1,
+	})
+	defer rdb.Close()
+
+	var unknownAuthority x509.UnknownAuthorityError
+	if err := rdb.Ping(ctx).Err(); err == nil || !errors.As(err, &unknownAuthority) {
+		t.Fatalf("got %v, wanted an unknown authority error", err)
+	}
+}
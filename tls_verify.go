@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SystemCertPoolWithFiles returns the CAs of the system and the CAs of the
+// PEM files, e.g. for Options.TLSConfig.RootCAs to trust a private CA in
+// addition to the public ones.
+func SystemCertPoolWithFiles(files ...string) (*x509.CertPool, error) {
+	pems := make([][]byte, len(files))
+	for i, name := range files {
+		pem, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		pems[i] = pem
+	}
+	return SystemCertPoolWithPEM(pems...)
+}
+
+// SystemCertPoolWithPEM returns the CAs of the system and the CAs of the PEM
+// blocks. It returns an error when a block has no certificate.
+func SystemCertPoolWithPEM(pems ...[]byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		// e.g. on systems without CA bundle
+		pool = x509.NewCertPool()
+	}
+	for i, pem := range pems {
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("redis: no certificate in PEM block %d", i)
+		}
+	}
+	return pool, nil
+}
+
+// tlsOptions are the TLS options shared by the options of the clients.
+type tlsOptions struct {
+	config           *tls.Config
+	files            *TLSFiles
+	verifyConnection func(cs tls.ConnectionState) error
+	spiffeIDs        []string
+	logger           Logger
+}
+
+// newTLSConfig returns the TLS configuration of opt, or nil when TLS is not
+// enabled.
+func newTLSConfig(opt tlsOptions) *tls.Config {
+	if opt.config == nil && opt.files == nil && opt.verifyConnection == nil && len(opt.spiffeIDs) == 0 {
+		return nil
+	}
+
+	var cfg *tls.Config
+	if opt.config != nil {
+		cfg = opt.config.Clone()
+	} else {
+		cfg = new(tls.Config)
+	}
+
+	rootCAs := func() (*x509.CertPool, error) { return cfg.RootCAs, nil }
+	if opt.files != nil {
+		opt.files.load(opt.logger)
+		if opt.files.CertFile != "" {
+			cfg.Certificates = nil
+			cfg.GetClientCertificate = opt.files.getClientCertificate
+		}
+		if opt.files.CAFile != "" {
+			rootCAs = opt.files.rootCAs
+		}
+	}
+
+	// the chains are verified by VerifyConnection when their CAs are reloaded,
+	// or when their leaf is identified by its SPIFFE ID instead of its name
+	verifyChains := !cfg.InsecureSkipVerify &&
+		(opt.files != nil && opt.files.CAFile != "" || len(opt.spiffeIDs) > 0)
+	if !verifyChains && opt.verifyConnection == nil {
+		return cfg
+	}
+	if verifyChains {
+		cfg.InsecureSkipVerify = true
+	}
+
+	verify := cfg.VerifyConnection
+	spiffeIDs := opt.spiffeIDs
+	verifyConnection := opt.verifyConnection
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if verifyChains {
+			roots, err := rootCAs()
+			if err != nil {
+				return err
+			}
+			dnsName := cs.ServerName
+			if len(spiffeIDs) > 0 {
+				dnsName = ""
+			}
+			if err := verifyPeerChain(cs, roots, dnsName); err != nil {
+				return err
+			}
+			if len(spiffeIDs) > 0 {
+				if err := matchSPIFFEID(cs.PeerCertificates[0], spiffeIDs); err != nil {
+					return err
+				}
+			}
+		}
+		if verify != nil {
+			if err := verify(cs); err != nil {
+				return err
+			}
+		}
+		if verifyConnection != nil {
+			return verifyConnection(cs)
+		}
+		return nil
+	}
+	return cfg
+}
+
+// verifyPeerChain verifies the certificates of the server with roots, or the
+// CAs of the system when roots is nil, and its name unless dnsName is empty.
+func verifyPeerChain(cs tls.ConnectionState, roots *x509.CertPool, dnsName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("redis: the server has no TLS certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       dnsName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// matchSPIFFEID checks that the SPIFFE ID of cert, its URI SAN, matches one
+// of ids: an ID such as "spiffe://example.org/redis", or a trust domain such
+// as "spiffe://example.org" matching all its IDs.
+func matchSPIFFEID(cert *x509.Certificate, ids []string) error {
+	if len(cert.URIs) != 1 || cert.URIs[0].Scheme != "spiffe" {
+		return errors.New("redis: the server certificate has no SPIFFE ID")
+	}
+	uri := cert.URIs[0]
+	id := uri.String()
+	for _, want := range ids {
+		want = strings.TrimSuffix(want, "/")
+		if id == want {
+			return nil
+		}
+		if domain := strings.TrimPrefix(want, "spiffe://"); !strings.Contains(domain, "/") && domain == uri.Host {
+			return nil
+		}
+	}
+	return fmt.Errorf("redis: SPIFFE ID %s of the server certificate is not allowed", id)
+}
@@ -0,0 +1,124 @@
+package redis_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+// newTLSClient returns a client of proxy with a certificate of ca, trusting
+// ca, and with opt when set.
+func newTLSClient(t *testing.T, ca *testCA, proxy *tlsProxy, opt func(*redis.Options)) *redis.Client {
+	certPEM, keyPEM := ca.issue(t, "client", 2)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	o := &redis.Options{
+		Addr: proxy.ln.Addr().String(),
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      roots,
+		},
+		MaxRetries: -1,
+	}
+	if opt != nil {
+		opt(o)
+	}
+	rdb := redis.NewClient(o)
+	t.Cleanup(func() { _ = rdb.Close() })
+	return rdb
+}
+
+func TestTLSServerSPIFFEIDs(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	ca := newTestCA(t)
+	proxy := newTLSProxy(t, ca, srv.Addr(), "spiffe://example.org/ns/prod/redis")
+
+	for _, tt := range []struct {
+		ids []string
+		ok  bool
+	}{
+		{[]string{"spiffe://example.org/ns/prod/redis"}, true},
+		{[]string{"spiffe://other.org", "spiffe://example.org"}, true},
+		{[]string{"spiffe://example.org/ns/dev/redis"}, false},
+		{[]string{"spiffe://example"}, false},
+	} {
+		rdb := newTLSClient(t, ca, proxy, func(opt *redis.Options) {
+			// the name of the server is not verified with SPIFFE IDs
+			opt.TLSConfig.ServerName = "redis.invalid"
+			opt.TLSServerSPIFFEIDs = tt.ids
+		})
+		err := rdb.Ping(ctx).Err()
+		if tt.ok && err != nil {
+			t.Errorf("%q: got %v", tt.ids, err)
+		}
+		if !tt.ok && (err == nil || !strings.Contains(err.Error(), "is not allowed")) {
+			t.Errorf("%q: got %v, wanted a SPIFFE ID error", tt.ids, err)
+		}
+	}
+}
+
+func TestTLSServerSPIFFEIDsUnknownCA(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	proxy := newTLSProxy(t, newTestCA(t), srv.Addr(), "spiffe://example.org/redis")
+
+	rdb := newTLSClient(t, newTestCA(t), proxy, func(opt *redis.Options) {
+		opt.TLSServerSPIFFEIDs = []string{"spiffe://example.org"}
+	})
+	var unknownAuthority x509.UnknownAuthorityError
+	if err := rdb.Ping(ctx).Err(); !errors.As(err, &unknownAuthority) {
+		t.Fatalf("got %v, wanted an unknown authority error", err)
+	}
+}
+
+func TestTLSVerifyConnection(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	ca := newTestCA(t)
+	proxy := newTLSProxy(t, ca, srv.Addr())
+
+	var names []string
+	rdb := newTLSClient(t, ca, proxy, func(opt *redis.Options) {
+		opt.TLSVerifyConnection = func(cs tls.ConnectionState) error {
+			names = append(names, cs.PeerCertificates[0].Subject.CommonName)
+			return nil
+		}
+	})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "localhost" {
+		t.Fatalf("got %q", names)
+	}
+
+	errForbidden := errors.New("forbidden server")
+	rdb = newTLSClient(t, ca, proxy, func(opt *redis.Options) {
+		opt.TLSVerifyConnection = func(cs tls.ConnectionState) error {
+			return errForbidden
+		}
+	})
+	if err := rdb.Ping(ctx).Err(); !errors.Is(err, errForbidden) {
+		t.Fatalf("got %v, wanted %v", err, errForbidden)
+	}
+}
+
+func TestSystemCertPoolWithPEM(t *testing.T) {
+	ca := newTestCA(t)
+	if _, err := redis.SystemCertPoolWithPEM(ca.pem); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := redis.SystemCertPoolWithPEM([]byte("not a certificate")); err == nil {
+		t.Fatal("got nil error for an invalid PEM block")
+	}
+}
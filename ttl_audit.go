@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type TTLAuditOptions struct {
+	// Match is the SCAN pattern of the audited keys. Default is all the keys.
+	Match string
+	// BatchSize is the COUNT of SCAN and the number of PTTL per pipeline.
+	// Default is 100.
+	BatchSize int
+	// MaxTTL reports the keys expiring in more than MaxTTL. Default is no
+	// maximum.
+	MaxTTL time.Duration
+	// Buckets are the upper bounds of the TTL distributions. Default is 1
+	// minute, 1 hour, 1 day, 7 days and 30 days.
+	Buckets []time.Duration
+	// MaxExamples is the number of keys reported per group without TTL or
+	// above MaxTTL. Default is 10.
+	MaxExamples int
+}
+
+// TTLAuditReport is the report of AuditTTLs.
+type TTLAuditReport struct {
+	// Scanned is the number of scanned keys.
+	Scanned int64
+	// Groups are the TTLs of the keys matching the patterns, in the order of
+	// the patterns, followed by the keys matching no pattern.
+	Groups []TTLAuditGroup
+	// Failed is the number of keys whose TTL failed to be read.
+	Failed int64
+	// LastError is the last error reading a TTL.
+	LastError error
+}
+
+// TTLAuditGroup are the TTLs of the keys matching a pattern.
+type TTLAuditGroup struct {
+	// Pattern is the pattern of the keys, or "" for the keys matching no
+	// pattern.
+	Pattern string
+	// Keys is the number of keys matching the pattern.
+	Keys int64
+	// Persistent is the number of keys without TTL, and PersistentKeys
+	// examples of them.
+	Persistent     int64
+	PersistentKeys []string
+	// AboveMax is the number of keys expiring after TTLAuditOptions.MaxTTL,
+	// and AboveMaxKeys examples of them.
+	AboveMax     int64
+	AboveMaxKeys []string
+	// MinTTL and MaxTTL are the shortest and longest TTLs of the expiring keys.
+	MinTTL, MaxTTL time.Duration
+	// Buckets is the distribution of the TTLs of the expiring keys.
+	Buckets []TTLBucket
+}
+
+// TTLBucket is the number of keys expiring in at most Upper, and more than
+// the Upper of the previous bucket. The Upper of the last bucket is 0: it
+// counts the keys expiring after the last bound.
+type TTLBucket struct {
+	Upper time.Duration
+	Keys  int64
+}
+
+var defaultTTLBuckets = []time.Duration{
+	time.Minute,
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+}
+
+// AuditTTLs scans the keys of client and reports their TTLs grouped by the
+// first of patterns they match, e.g. to find the cache keys accidentally
+// persisted forever. client can be a Client, a ClusterClient or a Ring: the
+// keys of every master of a cluster, or every shard of a ring, are scanned
+// concurrently.
+//
+// It returns an error when scanning the keys fails, and counts the keys whose
+// TTL it failed to read in TTLAuditReport.Failed.
+func AuditTTLs(ctx context.Context, client UniversalClient, patterns []string, opt *TTLAuditOptions) (*TTLAuditReport, error) {
+	var o TTLAuditOptions
+	if opt != nil {
+		o = *opt
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 100
+	}
+	if len(o.Buckets) == 0 {
+		o.Buckets = defaultTTLBuckets
+	}
+	buckets := append([]time.Duration(nil), o.Buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	if o.MaxExamples <= 0 {
+		o.MaxExamples = 10
+	}
+
+	report := &TTLAuditReport{
+		Groups: make([]TTLAuditGroup, len(patterns)+1),
+	}
+	for i := range report.Groups {
+		g := &report.Groups[i]
+		if i < len(patterns) {
+			g.Pattern = patterns[i]
+		}
+		g.Buckets = make([]TTLBucket, len(buckets)+1)
+		for j, upper := range buckets {
+			g.Buckets[j].Upper = upper
+		}
+	}
+	var mu sync.Mutex
+
+	err := forEachScanNode(ctx, client, func(ctx context.Context, node *Client) error {
+		return scanKeys(ctx, node, o.Match, "", o.BatchSize, func(keys []string) error {
+			cmds := make([]*DurationCmd, len(keys))
+			_, _ = node.Pipelined(ctx, func(pipe Pipeliner) error {
+				for i, key := range keys {
+					cmds[i] = pipe.PTTL(ctx, key)
+				}
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			for i, key := range keys {
+				ttl, err := cmds[i].Result()
+				if err != nil {
+					report.Scanned++
+					report.Failed++
+					report.LastError = err
+					continue
+				}
+				if ttl == -2 {
+					// deleted after the scan
+					continue
+				}
+				report.Scanned++
+				report.Groups[matchGroup(patterns, key)].add(key, ttl, &o)
+			}
+			return nil
+		})
+	})
+	return report, err
+}
+
+func (g *TTLAuditGroup) add(key string, ttl time.Duration, opt *TTLAuditOptions) {
+	g.Keys++
+	if ttl < 0 {
+		g.Persistent++
+		if len(g.PersistentKeys) < opt.MaxExamples {
+			g.PersistentKeys = append(g.PersistentKeys, key)
+		}
+		return
+	}
+
+	if opt.MaxTTL > 0 && ttl > opt.MaxTTL {
+		g.AboveMax++
+		if len(g.AboveMaxKeys) < opt.MaxExamples {
+			g.AboveMaxKeys = append(g.AboveMaxKeys, key)
+		}
+	}
+	if g.MinTTL == 0 || ttl < g.MinTTL {
+		g.MinTTL = ttl
+	}
+	if ttl > g.MaxTTL {
+		g.MaxTTL = ttl
+	}
+
+	i := sort.Search(len(g.Buckets)-1, func(i int) bool { return ttl <= g.Buckets[i].Upper })
+	g.Buckets[i].Keys++
+}
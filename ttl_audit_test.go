@@ -0,0 +1,51 @@
+package redis_test
+
+import (
+	"time"
+
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("AuditTTLs", func() {
+	var client *redis.Client
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+
+		Expect(client.Set(ctx, "cache:1", "v", 0).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "cache:2", "v", 30*time.Second).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "cache:3", "v", 48*time.Hour).Err()).NotTo(HaveOccurred())
+		Expect(client.Set(ctx, "other", "v", 0).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should report the TTLs by pattern", func() {
+		report, err := redis.AuditTTLs(ctx, client, []string{"cache:*"}, &redis.TTLAuditOptions{
+			MaxTTL:  24 * time.Hour,
+			Buckets: []time.Duration{time.Minute, 24 * time.Hour},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Scanned).To(Equal(int64(4)))
+		Expect(report.Groups).To(HaveLen(2))
+
+		cache := report.Groups[0]
+		Expect(cache.Keys).To(Equal(int64(3)))
+		Expect(cache.Persistent).To(Equal(int64(1)))
+		Expect(cache.PersistentKeys).To(Equal([]string{"cache:1"}))
+		Expect(cache.AboveMaxKeys).To(Equal([]string{"cache:3"}))
+		Expect(cache.Buckets).To(Equal([]redis.TTLBucket{
+			{Upper: time.Minute, Keys: 1},
+			{Upper: 24 * time.Hour, Keys: 0},
+			{Upper: 0, Keys: 1},
+		}))
+
+		Expect(report.Groups[1].PersistentKeys).To(Equal([]string{"other"}))
+	})
+})
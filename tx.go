@@ -27,6 +27,7 @@ func (c *Client) newTx() *Tx {
 		baseClient: baseClient{
 			opt:      c.opt,
 			connPool: pool.NewStickyConnPool(c.connPool),
+			pushes:   c.pushes,
 		},
 		hooksMixin: c.hooksMixin.clone(),
 	}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// ScannableCmd is a command whose value can be scanned into a Go value, e.g.
+// *StringCmd, *SliceCmd, *MapStringStringCmd and *JSONCmd.
+type ScannableCmd interface {
+	Scan(dst interface{}) error
+}
+
+// ScanAs scans the value of cmd into a T, e.g.
+//
+//	n, err := redis.ScanAs[int64](rdb.Get(ctx, "counter"))
+//
+// It is a function rather than a method of the commands, as methods can't
+// have type parameters.
+func ScanAs[T any](cmd ScannableCmd) (T, error) {
+	var v T
+	err := cmd.Scan(&v)
+	return v, err
+}
+
+// GetAs gets the value of key as a T. The strings, numbers, booleans,
+// time.Time, time.Duration and encoding.BinaryUnmarshaler values are scanned
+// as by StringCmd.Scan, and the structs, maps, slices and pointers are
+// decoded from JSON with encoding/json, e.g.
+//
+//	user, err := redis.GetAs[User](ctx, rdb, "user:1")
+//
+// A missing key is reported as Nil.
+func GetAs[T any](ctx context.Context, c StringCmdable, key string) (T, error) {
+	var v T
+	s, err := c.Get(ctx, key).Result()
+	if err != nil {
+		return v, err
+	}
+	err = scanValue(s, &v)
+	return v, err
+}
+
+// HGetAllAs gets the fields of the hash key into a T, a struct whose fields
+// are tagged with `redis:"field"` as for MapStringStringCmd.Scan. A missing
+// key gives the zero T.
+func HGetAllAs[T any](ctx context.Context, c HashCmdable, key string) (T, error) {
+	return ScanAs[T](c.HGetAll(ctx, key))
+}
+
+// JSONGetAs gets the value at path, the root of the document when path is
+// empty, of the JSON document key as a T. It is decoded with the JSONCodec
+// of the clients having one, like by Client.JSONGetStruct, and with
+// encoding/json otherwise. A missing key or path is reported as Nil.
+func JSONGetAs[T any](ctx context.Context, c JSONCmdable, key, path string) (T, error) {
+	var v T
+	if c, ok := c.(jsonStructGetter); ok {
+		err := c.JSONGetStruct(ctx, key, path, &v)
+		return v, err
+	}
+	err := jsonGetStruct(ctx, c, nil, key, path, &v)
+	return v, err
+}
+
+// jsonStructGetter is implemented by the clients having a JSONCodec.
+type jsonStructGetter interface {
+	JSONGetStruct(ctx context.Context, key, path string, dst interface{}) error
+}
+
+// scanValue scans the string value s into dst, a pointer, decoding the
+// composite values from JSON.
+func scanValue(s string, dst interface{}) error {
+	switch dst.(type) {
+	case encoding.BinaryUnmarshaler, *[]byte, *time.Time, *net.IP:
+		return proto.Scan([]byte(s), dst)
+	}
+	switch reflect.TypeOf(dst).Elem().Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr, reflect.Interface:
+		return json.Unmarshal([]byte(s), dst)
+	}
+	return proto.Scan([]byte(s), dst)
+}
@@ -0,0 +1,80 @@
+package redis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+type typedUser struct {
+	Name string `json:"name" redis:"name"`
+	Age  int    `json:"age" redis:"age"`
+}
+
+func TestTypedResults(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer rdb.Close()
+
+	_ = rdb.Set(ctx, "counter", 42, 0).Err()
+	_ = rdb.Set(ctx, "ttl", time.Second, 0).Err()
+	_ = rdb.Set(ctx, "user", `{"name":"alice","age":30}`, 0).Err()
+	_ = rdb.Set(ctx, "tags", `["a","b"]`, 0).Err()
+	_ = rdb.HSet(ctx, "hash", "name", "bob", "age", 40).Err()
+
+	if n, err := redis.GetAs[int64](ctx, rdb, "counter"); err != nil || n != 42 {
+		t.Fatalf("GetAs[int64] = %d, %v", n, err)
+	}
+	if d, err := redis.GetAs[time.Duration](ctx, rdb, "ttl"); err != nil || d != time.Second {
+		t.Fatalf("GetAs[time.Duration] = %s, %v", d, err)
+	}
+	if u, err := redis.GetAs[typedUser](ctx, rdb, "user"); err != nil || u != (typedUser{Name: "alice", Age: 30}) {
+		t.Fatalf("GetAs[typedUser] = %+v, %v", u, err)
+	}
+	if u, err := redis.GetAs[*typedUser](ctx, rdb, "user"); err != nil || u == nil || u.Name != "alice" {
+		t.Fatalf("GetAs[*typedUser] = %+v, %v", u, err)
+	}
+	if tags, err := redis.GetAs[[]string](ctx, rdb, "tags"); err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Fatalf("GetAs[[]string] = %q, %v", tags, err)
+	}
+	if s, err := redis.GetAs[[]byte](ctx, rdb, "tags"); err != nil || string(s) != `["a","b"]` {
+		t.Fatalf("GetAs[[]byte] = %q, %v", s, err)
+	}
+	if _, err := redis.GetAs[int](ctx, rdb, "user"); err == nil {
+		t.Fatal("GetAs[int] of a JSON value succeeded")
+	}
+	if n, err := redis.GetAs[int](ctx, rdb, "missing"); err != redis.Nil || n != 0 {
+		t.Fatalf("GetAs of a missing key = %d, %v", n, err)
+	}
+
+	if u, err := redis.HGetAllAs[typedUser](ctx, rdb, "hash"); err != nil || u != (typedUser{Name: "bob", Age: 40}) {
+		t.Fatalf("HGetAllAs = %+v, %v", u, err)
+	}
+	if n, err := redis.ScanAs[float64](rdb.Get(ctx, "counter")); err != nil || n != 42 {
+		t.Fatalf("ScanAs[float64] = %v, %v", n, err)
+	}
+}
+
+func TestJSONGetAs(t *testing.T) {
+	rec := redistest.NewRecorder()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer rdb.Close()
+	rdb.AddHook(rec)
+
+	rec.On("json.get", "user", "$").Return(`[{"name":"alice","age":30}]`)
+	rec.On("json.get", "user", "$.name").Return(`["alice"]`)
+	rec.On("json.get", "missing").Error(redis.Nil)
+
+	if u, err := redis.JSONGetAs[typedUser](ctx, rdb, "user", ""); err != nil || u != (typedUser{Name: "alice", Age: 30}) {
+		t.Fatalf("JSONGetAs[typedUser] = %+v, %v", u, err)
+	}
+	if name, err := redis.JSONGetAs[string](ctx, rdb, "user", "$.name"); err != nil || name != "alice" {
+		t.Fatalf("JSONGetAs[string] = %q, %v", name, err)
+	}
+	if _, err := redis.JSONGetAs[typedUser](ctx, rdb, "missing", ""); err != redis.Nil {
+		t.Fatalf("JSONGetAs of a missing key: %v", err)
+	}
+}
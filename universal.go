@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"crypto/tls"
+	"io"
 	"net"
 	"time"
 )
@@ -26,6 +27,22 @@ type UniversalOptions struct {
 	Dialer    func(ctx context.Context, network, addr string) (net.Conn, error)
 	OnConnect func(ctx context.Context, cn *Conn) error
 
+	// OnConnectionEvent is called with the events of the connections, see
+	// Options.OnConnectionEvent.
+	OnConnectionEvent func(ev ConnEvent)
+	// OnConnectionUse is called with the events of the connections taken from
+	// the pools and given back to them, see Options.OnConnectionUse.
+	OnConnectionUse func(ev ConnEvent)
+	// Logger logs the messages of the clients, see Options.Logger.
+	Logger Logger
+	// CircuitBreaker enables a circuit breaker for each node, see
+	// Options.CircuitBreaker.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// OnPush is called with the push notifications received on the
+	// connections of each node, see Options.OnPush.
+	OnPush func(ctx context.Context, msg PushMessage)
+
 	Protocol         int
 	Username         string
 	Password         string
@@ -41,6 +58,31 @@ type UniversalOptions struct {
 	WriteTimeout          time.Duration
 	ContextTimeoutEnabled bool
 
+	// StatsEnabled enables the statistics of the commands returned by Stats.
+	StatsEnabled bool
+
+	// AutoPipeline batches the commands issued concurrently into pipelines,
+	// see Options.AutoPipeline.
+	AutoPipeline bool
+	// AutoPipelineMaxSize is the maximum number of commands of the pipelines
+	// of AutoPipeline. Default is 100 commands.
+	AutoPipelineMaxSize int
+
+	// PipelineMaxCmds and PipelineMaxBytes split the pipelines in batches,
+	// see Options.PipelineMaxCmds.
+	PipelineMaxCmds  int
+	PipelineMaxBytes int
+
+	// HedgeDelay enables the hedging of the read commands on another
+	// connection of their node, see Options.HedgeDelay.
+	HedgeDelay time.Duration
+	// HedgeCommands are the names of the commands hedged with HedgeDelay.
+	HedgeCommands []string
+
+	// DryRun marshals and logs the commands instead of sending them, see
+	// Options.DryRun.
+	DryRun bool
+
 	// PoolFIFO uses FIFO mode for each node connection pool GET/PUT (default LIFO).
 	PoolFIFO bool
 
@@ -51,8 +93,18 @@ type UniversalOptions struct {
 	MaxActiveConns  int
 	ConnMaxIdleTime time.Duration
 	ConnMaxLifetime time.Duration
+	ReadBufferSize  int
+	WriteBufferSize int
+	StringInterner  StringInterner
+
+	// AdaptivePoolSize adjusts the size of the pools to their load, see
+	// Options.AdaptivePoolSize.
+	AdaptivePoolSize bool
 
-	TLSConfig *tls.Config
+	TLSConfig           *tls.Config
+	TLSFiles            *TLSFiles
+	TLSVerifyConnection func(cs tls.ConnectionState) error
+	TLSServerSPIFFEIDs  []string
 
 	// Only cluster clients.
 
@@ -60,6 +112,7 @@ type UniversalOptions struct {
 	ReadOnly       bool
 	RouteByLatency bool
 	RouteRandomly  bool
+	RouteReadsFunc func(nodes []NodeInfo) NodeInfo
 
 	// The sentinel master name.
 	// Only failover clients.
@@ -68,6 +121,8 @@ type UniversalOptions struct {
 
 	DisableIndentity bool
 	IdentitySuffix   string
+
+	JSONCodec JSONCodec
 }
 
 // Cluster returns cluster options created from the universal options.
@@ -77,10 +132,15 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 	}
 
 	return &ClusterOptions{
-		Addrs:      o.Addrs,
-		ClientName: o.ClientName,
-		Dialer:     o.Dialer,
-		OnConnect:  o.OnConnect,
+		Addrs:             o.Addrs,
+		ClientName:        o.ClientName,
+		Dialer:            o.Dialer,
+		OnConnect:         o.OnConnect,
+		OnConnectionEvent: o.OnConnectionEvent,
+		OnConnectionUse:   o.OnConnectionUse,
+		Logger:            o.Logger,
+		CircuitBreaker:    o.CircuitBreaker,
+		OnPush:            o.OnPush,
 
 		Protocol: o.Protocol,
 		Username: o.Username,
@@ -90,6 +150,7 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		ReadOnly:       o.ReadOnly,
 		RouteByLatency: o.RouteByLatency,
 		RouteRandomly:  o.RouteRandomly,
+		RouteReadsFunc: o.RouteReadsFunc,
 
 		MaxRetries:      o.MaxRetries,
 		MinRetryBackoff: o.MinRetryBackoff,
@@ -99,6 +160,14 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		StatsEnabled:          o.StatsEnabled,
+		AutoPipeline:          o.AutoPipeline,
+		AutoPipelineMaxSize:   o.AutoPipelineMaxSize,
+		PipelineMaxCmds:       o.PipelineMaxCmds,
+		PipelineMaxBytes:      o.PipelineMaxBytes,
+		HedgeDelay:            o.HedgeDelay,
+		HedgeCommands:         o.HedgeCommands,
+		DryRun:                o.DryRun,
 
 		PoolFIFO: o.PoolFIFO,
 
@@ -109,11 +178,21 @@ func (o *UniversalOptions) Cluster() *ClusterOptions {
 		MaxActiveConns:  o.MaxActiveConns,
 		ConnMaxIdleTime: o.ConnMaxIdleTime,
 		ConnMaxLifetime: o.ConnMaxLifetime,
+		ReadBufferSize:  o.ReadBufferSize,
+		WriteBufferSize: o.WriteBufferSize,
+		StringInterner:  o.StringInterner,
+
+		AdaptivePoolSize: o.AdaptivePoolSize,
 
-		TLSConfig: o.TLSConfig,
+		TLSConfig:           o.TLSConfig,
+		TLSFiles:            o.TLSFiles,
+		TLSVerifyConnection: o.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  o.TLSServerSPIFFEIDs,
 
 		DisableIndentity: o.DisableIndentity,
 		IdentitySuffix:   o.IdentitySuffix,
+
+		JSONCodec: o.JSONCodec,
 	}
 }
 
@@ -128,8 +207,13 @@ func (o *UniversalOptions) Failover() *FailoverOptions {
 		MasterName:    o.MasterName,
 		ClientName:    o.ClientName,
 
-		Dialer:    o.Dialer,
-		OnConnect: o.OnConnect,
+		Dialer:            o.Dialer,
+		OnConnect:         o.OnConnect,
+		OnConnectionEvent: o.OnConnectionEvent,
+		OnConnectionUse:   o.OnConnectionUse,
+		Logger:            o.Logger,
+		CircuitBreaker:    o.CircuitBreaker,
+		OnPush:            o.OnPush,
 
 		DB:               o.DB,
 		Protocol:         o.Protocol,
@@ -146,6 +230,14 @@ func (o *UniversalOptions) Failover() *FailoverOptions {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		StatsEnabled:          o.StatsEnabled,
+		AutoPipeline:          o.AutoPipeline,
+		AutoPipelineMaxSize:   o.AutoPipelineMaxSize,
+		PipelineMaxCmds:       o.PipelineMaxCmds,
+		PipelineMaxBytes:      o.PipelineMaxBytes,
+		HedgeDelay:            o.HedgeDelay,
+		HedgeCommands:         o.HedgeCommands,
+		DryRun:                o.DryRun,
 
 		PoolFIFO:        o.PoolFIFO,
 		PoolSize:        o.PoolSize,
@@ -155,11 +247,21 @@ func (o *UniversalOptions) Failover() *FailoverOptions {
 		MaxActiveConns:  o.MaxActiveConns,
 		ConnMaxIdleTime: o.ConnMaxIdleTime,
 		ConnMaxLifetime: o.ConnMaxLifetime,
+		ReadBufferSize:  o.ReadBufferSize,
+		WriteBufferSize: o.WriteBufferSize,
+		StringInterner:  o.StringInterner,
+
+		AdaptivePoolSize: o.AdaptivePoolSize,
 
-		TLSConfig: o.TLSConfig,
+		TLSConfig:           o.TLSConfig,
+		TLSFiles:            o.TLSFiles,
+		TLSVerifyConnection: o.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  o.TLSServerSPIFFEIDs,
 
 		DisableIndentity: o.DisableIndentity,
 		IdentitySuffix:   o.IdentitySuffix,
+
+		JSONCodec: o.JSONCodec,
 	}
 }
 
@@ -171,10 +273,15 @@ func (o *UniversalOptions) Simple() *Options {
 	}
 
 	return &Options{
-		Addr:       addr,
-		ClientName: o.ClientName,
-		Dialer:     o.Dialer,
-		OnConnect:  o.OnConnect,
+		Addr:              addr,
+		ClientName:        o.ClientName,
+		Dialer:            o.Dialer,
+		OnConnect:         o.OnConnect,
+		OnConnectionEvent: o.OnConnectionEvent,
+		OnConnectionUse:   o.OnConnectionUse,
+		Logger:            o.Logger,
+		CircuitBreaker:    o.CircuitBreaker,
+		OnPush:            o.OnPush,
 
 		DB:       o.DB,
 		Protocol: o.Protocol,
@@ -189,6 +296,14 @@ func (o *UniversalOptions) Simple() *Options {
 		ReadTimeout:           o.ReadTimeout,
 		WriteTimeout:          o.WriteTimeout,
 		ContextTimeoutEnabled: o.ContextTimeoutEnabled,
+		StatsEnabled:          o.StatsEnabled,
+		AutoPipeline:          o.AutoPipeline,
+		AutoPipelineMaxSize:   o.AutoPipelineMaxSize,
+		PipelineMaxCmds:       o.PipelineMaxCmds,
+		PipelineMaxBytes:      o.PipelineMaxBytes,
+		HedgeDelay:            o.HedgeDelay,
+		HedgeCommands:         o.HedgeCommands,
+		DryRun:                o.DryRun,
 
 		PoolFIFO:        o.PoolFIFO,
 		PoolSize:        o.PoolSize,
@@ -198,11 +313,21 @@ func (o *UniversalOptions) Simple() *Options {
 		MaxActiveConns:  o.MaxActiveConns,
 		ConnMaxIdleTime: o.ConnMaxIdleTime,
 		ConnMaxLifetime: o.ConnMaxLifetime,
+		ReadBufferSize:  o.ReadBufferSize,
+		WriteBufferSize: o.WriteBufferSize,
+		StringInterner:  o.StringInterner,
+
+		AdaptivePoolSize: o.AdaptivePoolSize,
 
-		TLSConfig: o.TLSConfig,
+		TLSConfig:           o.TLSConfig,
+		TLSFiles:            o.TLSFiles,
+		TLSVerifyConnection: o.TLSVerifyConnection,
+		TLSServerSPIFFEIDs:  o.TLSServerSPIFFEIDs,
 
 		DisableIndentity: o.DisableIndentity,
 		IdentitySuffix:   o.IdentitySuffix,
+
+		JSONCodec: o.JSONCodec,
 	}
 }
 
@@ -215,6 +340,10 @@ func (o *UniversalOptions) Simple() *Options {
 type UniversalClient interface {
 	Cmdable
 	AddHook(Hook)
+	RemoveHook(Hook)
+	Hooks() []Hook
+	WarmUp(ctx context.Context, n int) error
+	Ready(ctx context.Context) error
 	Watch(ctx context.Context, fn func(*Tx) error, keys ...string) error
 	Do(ctx context.Context, args ...interface{}) *Cmd
 	Process(ctx context.Context, cmd Cmder) error
@@ -223,6 +352,24 @@ type UniversalClient interface {
 	SSubscribe(ctx context.Context, channels ...string) *PubSub
 	Close() error
 	PoolStats() *PoolStats
+	Stats() map[string]CommandStats
+	ResetStats()
+	ExpireMany(ctx context.Context, expiration time.Duration, keys ...string) ([]bool, error)
+	JSONSetStruct(ctx context.Context, key, path string, v interface{}) error
+	JSONSetStructMode(ctx context.Context, key, path string, v interface{}, mode string) (bool, error)
+	JSONGetStruct(ctx context.Context, key, path string, dst interface{}) error
+	JSONMGetSharded(ctx context.Context, path string, keys ...string) *JSONSliceCmd
+	JSONApplyPatch(ctx context.Context, key string, patch []JSONPatchOp) error
+	BFInsertBulk(ctx context.Context, key string, chunkSize int, options *BFInsertOptions, items ...interface{}) ([]bool, error)
+	CFInsertBulk(ctx context.Context, key string, chunkSize int, options *CFInsertOptions, items ...interface{}) ([]bool, error)
+	BFBackup(ctx context.Context, key string, w io.Writer) error
+	BFRestore(ctx context.Context, key string, r io.Reader) error
+	CFBackup(ctx context.Context, key string, w io.Writer) error
+	CFRestore(ctx context.Context, key string, r io.Reader) error
+	CMSIncrByMap(ctx context.Context, key string, increments map[string]int64, init *CMSInitOptions) (map[string]int64, error)
+	CMSQueryMany(ctx context.Context, key string, items ...string) (map[string]int64, error)
+	TDigestSummarize(ctx context.Context, key string, options *TDigestSummaryOptions) (*TDigestSummary, error)
+	TDigestMergeSummarize(ctx context.Context, destKey string, mergeOptions *TDigestMergeOptions, options *TDigestSummaryOptions, sourceKeys ...string) (*TDigestSummary, error)
 }
 
 var (
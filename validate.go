@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// optionsProblems collects the problems of options.
+type optionsProblems []string
+
+func (p *optionsProblems) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		*p = append(*p, fmt.Sprintf(format, args...))
+	}
+}
+
+func (p optionsProblems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return errors.New("redis: invalid options: " + strings.Join(p, "; "))
+}
+
+// Validate returns an error describing the nonsensical settings of opt, such
+// as a negative PoolSize or MinIdleConns greater than PoolSize, that the
+// clients otherwise replace by defaults or ignore. It returns nil for the
+// zero Options, which are all defaults.
+func (opt *Options) Validate() error {
+	var p optionsProblems
+	opt.validate(&p, 10*runtime.GOMAXPROCS(0))
+	return p.err()
+}
+
+func (opt *Options) validate(p *optionsProblems, defaultPoolSize int) {
+	if opt.Dialer == nil {
+		switch opt.Network {
+		case "", "tcp", "tcp4", "tcp6", "unix":
+		default:
+			p.check(false, "Network %q is not tcp or unix", opt.Network)
+		}
+	}
+	p.check(opt.Protocol == 0 || opt.Protocol == 2 || opt.Protocol == 3,
+		"Protocol %d is not 2 or 3", opt.Protocol)
+	p.check(opt.DB >= 0, "DB %d is negative", opt.DB)
+
+	var providers []string
+	if opt.StreamingCredentialsProvider != nil {
+		providers = append(providers, "StreamingCredentialsProvider")
+	}
+	if opt.CredentialsProviderContext != nil {
+		providers = append(providers, "CredentialsProviderContext")
+	}
+	if opt.CredentialsProvider != nil {
+		providers = append(providers, "CredentialsProvider")
+	}
+	if len(providers) > 1 {
+		p.check(false, "only %s of %s is used", providers[0], strings.Join(providers, ", "))
+	}
+	p.check(len(providers) == 0 || opt.Username == "" && opt.Password == "",
+		"Username and Password are ignored with %s", strings.Join(providers, ", "))
+
+	validateRetries(p, opt.MaxRetries, opt.MinRetryBackoff, opt.MaxRetryBackoff)
+
+	p.check(opt.DialTimeout >= 0, "DialTimeout %s is negative", opt.DialTimeout)
+	p.check(opt.ReadTimeout >= -2, "ReadTimeout %s is not -1, -2 or positive", opt.ReadTimeout)
+	p.check(opt.WriteTimeout >= -2, "WriteTimeout %s is not -1, -2 or positive", opt.WriteTimeout)
+	p.check(opt.PoolTimeout >= 0, "PoolTimeout %s is negative", opt.PoolTimeout)
+	p.check(opt.ConnMaxIdleTime >= -1, "ConnMaxIdleTime %s is not -1 or positive", opt.ConnMaxIdleTime)
+
+	p.check(opt.PoolSize >= 0, "PoolSize %d is negative", opt.PoolSize)
+	p.check(opt.MinIdleConns >= 0, "MinIdleConns %d is negative", opt.MinIdleConns)
+	p.check(opt.MaxIdleConns >= 0, "MaxIdleConns %d is negative", opt.MaxIdleConns)
+	p.check(opt.MaxActiveConns >= 0, "MaxActiveConns %d is negative", opt.MaxActiveConns)
+	p.check(opt.ReadBufferSize >= 0, "ReadBufferSize %d is negative", opt.ReadBufferSize)
+	p.check(opt.WriteBufferSize >= 0, "WriteBufferSize %d is negative", opt.WriteBufferSize)
+	poolSize := opt.PoolSize
+	if poolSize == 0 {
+		poolSize = defaultPoolSize
+	}
+	p.check(poolSize < 0 || opt.MinIdleConns <= poolSize,
+		"MinIdleConns %d is greater than PoolSize %d", opt.MinIdleConns, poolSize)
+	p.check(opt.MaxIdleConns == 0 || opt.MinIdleConns <= opt.MaxIdleConns,
+		"MinIdleConns %d is greater than MaxIdleConns %d", opt.MinIdleConns, opt.MaxIdleConns)
+	p.check(opt.MaxActiveConns == 0 || opt.MinIdleConns <= opt.MaxActiveConns,
+		"MinIdleConns %d is greater than MaxActiveConns %d", opt.MinIdleConns, opt.MaxActiveConns)
+
+	p.check(len(opt.TLSServerSPIFFEIDs) == 0 || opt.TLSConfig == nil || !opt.TLSConfig.InsecureSkipVerify,
+		"TLSServerSPIFFEIDs are not verified with TLSConfig.InsecureSkipVerify")
+	p.check(opt.MaintNotifications == nil || opt.Protocol != 2,
+		"MaintNotifications require Protocol 3")
+	p.check(opt.ClientCache == nil || opt.Protocol != 2,
+		"ClientCache requires Protocol 3")
+}
+
+func validateRetries(p *optionsProblems, maxRetries int, minBackoff, maxBackoff time.Duration) {
+	p.check(maxRetries >= -1, "MaxRetries %d is not -1 or positive", maxRetries)
+	p.check(minBackoff >= -1, "MinRetryBackoff %s is not -1 or positive", minBackoff)
+	p.check(maxBackoff >= -1, "MaxRetryBackoff %s is not -1 or positive", maxBackoff)
+	p.check(minBackoff <= 0 || maxBackoff <= 0 || minBackoff <= maxBackoff,
+		"MinRetryBackoff %s is greater than MaxRetryBackoff %s", minBackoff, maxBackoff)
+}
+
+// Validate returns an error describing the nonsensical settings of opt, as
+// Options.Validate.
+func (opt *ClusterOptions) Validate() error {
+	var p optionsProblems
+	opt.validate(&p)
+	return p.err()
+}
+
+func (opt *ClusterOptions) validate(p *optionsProblems) {
+	p.check(len(opt.Addrs) > 0 || opt.ClusterSlots != nil, "no Addrs")
+	p.check(opt.MaxRedirects >= -1, "MaxRedirects %d is not -1 or positive", opt.MaxRedirects)
+	opt.clientOptions().validate(p, 5*runtime.GOMAXPROCS(0))
+}
+
+// Validate returns an error describing the nonsensical settings of opt, as
+// Options.Validate.
+func (opt *FailoverOptions) Validate() error {
+	var p optionsProblems
+	p.check(opt.MasterName != "", "no MasterName")
+	p.check(len(opt.SentinelAddrs) > 0, "no SentinelAddrs")
+	opt.clientOptions().validate(&p, 10*runtime.GOMAXPROCS(0))
+	return p.err()
+}
+
+// Validate returns an error describing the nonsensical settings of opt for
+// the client of NewUniversalClient, as Options.Validate, e.g. DB with a
+// cluster.
+func (opt *UniversalOptions) Validate() error {
+	switch {
+	case opt.MasterName != "":
+		return opt.Failover().Validate()
+	case len(opt.Addrs) > 1:
+		var p optionsProblems
+		p.check(opt.DB == 0, "DB %d is not supported by cluster", opt.DB)
+		opt.Cluster().validate(&p)
+		return p.err()
+	default:
+		return opt.Simple().Validate()
+	}
+}
@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	provider := func() (string, string) { return "user", "pass" }
+	providerContext := func(context.Context) (string, string, error) { return "user", "pass", nil }
+
+	cases := []struct {
+		o    *Options
+		errs []string // the expected problems
+	}{
+		{o: &Options{}},
+		{o: &Options{ReadTimeout: -2, WriteTimeout: -1, MaxRetries: -1, MinRetryBackoff: -1, ConnMaxIdleTime: -1}},
+		{o: &Options{PoolSize: 4, MinIdleConns: 2, MaxIdleConns: 3, MaxActiveConns: 4}},
+		{o: &Options{Network: "udp", Dialer: NewDialer(&Options{})}},
+		{
+			o:    &Options{PoolSize: -1, MinIdleConns: -2},
+			errs: []string{"PoolSize -1 is negative", "MinIdleConns -2 is negative"},
+		}, {
+			o:    &Options{ReadBufferSize: -1, WriteBufferSize: -4096},
+			errs: []string{"ReadBufferSize -1 is negative", "WriteBufferSize -4096 is negative"},
+		}, {
+			o:    &Options{PoolSize: 4, MinIdleConns: 5, MaxIdleConns: 3},
+			errs: []string{"MinIdleConns 5 is greater than PoolSize 4", "MinIdleConns 5 is greater than MaxIdleConns 3"},
+		}, {
+			o:    &Options{CredentialsProvider: provider, CredentialsProviderContext: providerContext},
+			errs: []string{"only CredentialsProviderContext of CredentialsProviderContext, CredentialsProvider is used"},
+		}, {
+			o:    &Options{Password: "pass", CredentialsProvider: provider},
+			errs: []string{"Username and Password are ignored with CredentialsProvider"},
+		}, {
+			o:    &Options{MinRetryBackoff: time.Second, MaxRetryBackoff: time.Millisecond, MaxRetries: -2},
+			errs: []string{"MaxRetries -2 is not -1 or positive", "MinRetryBackoff 1s is greater than MaxRetryBackoff 1ms"},
+		}, {
+			o:    &Options{Network: "udp", Protocol: 4, DB: -1},
+			errs: []string{`Network "udp" is not tcp or unix`, "Protocol 4 is not 2 or 3", "DB -1 is negative"},
+		}, {
+			o:    &Options{ReadTimeout: -3, DialTimeout: -time.Second},
+			errs: []string{"DialTimeout -1s is negative", "ReadTimeout -3ns is not -1, -2 or positive"},
+		}, {
+			o: &Options{
+				TLSConfig:          &tls.Config{InsecureSkipVerify: true},
+				TLSServerSPIFFEIDs: []string{"spiffe://example.org"},
+			},
+			errs: []string{"TLSServerSPIFFEIDs are not verified with TLSConfig.InsecureSkipVerify"},
+		},
+	}
+
+	for i, tc := range cases {
+		err := tc.o.Validate()
+		if len(tc.errs) == 0 {
+			if err != nil {
+				t.Errorf("#%d: unexpected error: %s", i, err)
+			}
+			continue
+		}
+		want := "redis: invalid options: " + strings.Join(tc.errs, "; ")
+		if err == nil || err.Error() != want {
+			t.Errorf("#%d: got %v, expected %q", i, err, want)
+		}
+	}
+}
+
+func TestUniversalOptionsValidate(t *testing.T) {
+	opt := &UniversalOptions{Addrs: []string{"localhost:7000", "localhost:7001"}, DB: 1, MaxRedirects: -2}
+	want := "redis: invalid options: DB 1 is not supported by cluster; MaxRedirects -2 is not -1 or positive"
+	if err := opt.Validate(); err == nil || err.Error() != want {
+		t.Errorf("got %v, expected %q", err, want)
+	}
+
+	opt = &UniversalOptions{Addrs: []string{"localhost:26379"}, MasterName: "mymaster", DB: 1}
+	if err := opt.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if err := (&FailoverOptions{}).Validate(); err == nil ||
+		err.Error() != "redis: invalid options: no MasterName; no SentinelAddrs" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestStrictValidation(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || err.Error() != "redis: invalid options: PoolSize -1 is negative" {
+			t.Errorf("got %v", err)
+		}
+	}()
+	NewClient(&Options{PoolSize: -1, StrictValidation: true})
+}
@@ -0,0 +1,369 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+type VectorSetCmdable interface {
+	VAdd(ctx context.Context, key, element string, val Vector) *BoolCmd
+	VAddWithArgs(ctx context.Context, key, element string, val Vector, addArgs *VAddArgs) *BoolCmd
+	VCard(ctx context.Context, key string) *IntCmd
+	VDim(ctx context.Context, key string) *IntCmd
+	VEmb(ctx context.Context, key, element string) *FloatSliceCmd
+	VGetAttr(ctx context.Context, key, element string) *StringCmd
+	VInfo(ctx context.Context, key string) *MapStringInterfaceCmd
+	VRandMember(ctx context.Context, key string) *StringCmd
+	VRandMemberCount(ctx context.Context, key string, count int) *StringSliceCmd
+	VRem(ctx context.Context, key, element string) *BoolCmd
+	VSetAttr(ctx context.Context, key, element string, attr interface{}) *BoolCmd
+	VSim(ctx context.Context, key string, val Vector) *StringSliceCmd
+	VSimWithScores(ctx context.Context, key string, val Vector) *VectorScoreSliceCmd
+	VSimWithArgs(ctx context.Context, key string, val Vector, simArgs *VSimArgs) *StringSliceCmd
+	VSimWithArgsWithScores(ctx context.Context, key string, val Vector, simArgs *VSimArgs) *VectorScoreSliceCmd
+}
+
+// Vector is the vector argument of VADD and VSIM.
+type Vector interface {
+	Value() []interface{}
+}
+
+var (
+	_ Vector = (*VectorFP32)(nil)
+	_ Vector = (*VectorValues)(nil)
+	_ Vector = (*VectorRef)(nil)
+)
+
+// VectorFP32 is a vector sent as a blob of little-endian 32-bit floats.
+type VectorFP32 struct {
+	Val []byte
+}
+
+// VectorFloat32 returns the FP32 blob vector of v.
+func VectorFloat32(v []float32) *VectorFP32 {
+	return &VectorFP32{Val: EncodeFloat32Vector(v)}
+}
+
+func (v *VectorFP32) Value() []interface{} {
+	return []interface{}{"FP32", v.Val}
+}
+
+// VectorValues is a vector sent as a list of values.
+type VectorValues struct {
+	Val []float64
+}
+
+func (v *VectorValues) Value() []interface{} {
+	args := make([]interface{}, 2, 2+len(v.Val))
+	args[0] = "VALUES"
+	args[1] = len(v.Val)
+	for _, f := range v.Val {
+		args = append(args, f)
+	}
+	return args
+}
+
+// VectorRef refers to the vector of an existing element, for VSIM only.
+type VectorRef struct {
+	Name string
+}
+
+func (v *VectorRef) Value() []interface{} {
+	return []interface{}{"ELE", v.Name}
+}
+
+// VAdd adds element to the vector set key with the vector val.
+// Returns true if the element was added, false if it was updated.
+// For more information - https://redis.io/commands/vadd/
+func (c cmdable) VAdd(ctx context.Context, key, element string, val Vector) *BoolCmd {
+	return c.VAddWithArgs(ctx, key, element, val, nil)
+}
+
+type VAddArgs struct {
+	// Reduce projects the vectors to the given number of dimensions.
+	Reduce int64
+	// CAS performs the HNSW insertion in a background thread.
+	CAS bool
+
+	// NoQuant, Q8 and Bin select the quantization of the vectors. Default is Q8.
+	NoQuant bool
+	Q8      bool
+	Bin     bool
+
+	EF      int64
+	SetAttr string
+	M       int64
+}
+
+func (v *VAddArgs) reduce() []interface{} {
+	if v == nil || v.Reduce == 0 {
+		return nil
+	}
+	return []interface{}{"REDUCE", v.Reduce}
+}
+
+func (v *VAddArgs) appendArgs(args []interface{}) []interface{} {
+	if v == nil {
+		return args
+	}
+	if v.CAS {
+		args = append(args, "CAS")
+	}
+	switch {
+	case v.NoQuant:
+		args = append(args, "NOQUANT")
+	case v.Q8:
+		args = append(args, "Q8")
+	case v.Bin:
+		args = append(args, "BIN")
+	}
+	if v.EF > 0 {
+		args = append(args, "EF", v.EF)
+	}
+	if v.SetAttr != "" {
+		args = append(args, "SETATTR", v.SetAttr)
+	}
+	if v.M > 0 {
+		args = append(args, "M", v.M)
+	}
+	return args
+}
+
+// VAddWithArgs adds element to the vector set key with the vector val and the options of addArgs.
+// For more information - https://redis.io/commands/vadd/
+func (c cmdable) VAddWithArgs(ctx context.Context, key, element string, val Vector, addArgs *VAddArgs) *BoolCmd {
+	args := []interface{}{"VADD", key}
+	args = append(args, addArgs.reduce()...)
+	args = append(args, val.Value()...)
+	args = append(args, element)
+	args = addArgs.appendArgs(args)
+	cmd := NewBoolCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VCard returns the number of elements in the vector set key.
+// For more information - https://redis.io/commands/vcard/
+func (c cmdable) VCard(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd(ctx, "VCARD", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VDim returns the number of dimensions of the vectors in the vector set key.
+// For more information - https://redis.io/commands/vdim/
+func (c cmdable) VDim(ctx context.Context, key string) *IntCmd {
+	cmd := NewIntCmd(ctx, "VDIM", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VEmb returns the approximate vector of element, as stored after quantization.
+// For more information - https://redis.io/commands/vemb/
+func (c cmdable) VEmb(ctx context.Context, key, element string) *FloatSliceCmd {
+	cmd := NewFloatSliceCmd(ctx, "VEMB", key, element)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VGetAttr returns the JSON attributes of element, or redis.Nil if it has none.
+// For more information - https://redis.io/commands/vgetattr/
+func (c cmdable) VGetAttr(ctx context.Context, key, element string) *StringCmd {
+	cmd := NewStringCmd(ctx, "VGETATTR", key, element)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VInfo returns information about the vector set key.
+// For more information - https://redis.io/commands/vinfo/
+func (c cmdable) VInfo(ctx context.Context, key string) *MapStringInterfaceCmd {
+	cmd := NewMapStringInterfaceCmd(ctx, "VINFO", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VRandMember returns a random element of the vector set key.
+// For more information - https://redis.io/commands/vrandmember/
+func (c cmdable) VRandMember(ctx context.Context, key string) *StringCmd {
+	cmd := NewStringCmd(ctx, "VRANDMEMBER", key)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VRandMemberCount returns count random elements of the vector set key.
+// A negative count allows the same element to be returned several times.
+// For more information - https://redis.io/commands/vrandmember/
+func (c cmdable) VRandMemberCount(ctx context.Context, key string, count int) *StringSliceCmd {
+	cmd := NewStringSliceCmd(ctx, "VRANDMEMBER", key, count)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VRem removes element from the vector set key.
+// For more information - https://redis.io/commands/vrem/
+func (c cmdable) VRem(ctx context.Context, key, element string) *BoolCmd {
+	cmd := NewBoolCmd(ctx, "VREM", key, element)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VSetAttr sets the JSON attributes of element. attr is either a JSON string
+// or a value marshaled to JSON; an empty string removes the attributes.
+// For more information - https://redis.io/commands/vsetattr/
+func (c cmdable) VSetAttr(ctx context.Context, key, element string, attr interface{}) *BoolCmd {
+	var attrs string
+	switch attr := attr.(type) {
+	case string:
+		attrs = attr
+	case []byte:
+		attrs = string(attr)
+	default:
+		b, err := json.Marshal(attr)
+		if err != nil {
+			cmd := NewBoolCmd(ctx, "VSETATTR", key, element)
+			cmd.SetErr(err)
+			return cmd
+		}
+		attrs = string(b)
+	}
+	cmd := NewBoolCmd(ctx, "VSETATTR", key, element, attrs)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VSim returns the elements of the vector set key most similar to val.
+// For more information - https://redis.io/commands/vsim/
+func (c cmdable) VSim(ctx context.Context, key string, val Vector) *StringSliceCmd {
+	return c.VSimWithArgs(ctx, key, val, nil)
+}
+
+// VSimWithScores returns the elements of the vector set key most similar to val with their similarity scores.
+// For more information - https://redis.io/commands/vsim/
+func (c cmdable) VSimWithScores(ctx context.Context, key string, val Vector) *VectorScoreSliceCmd {
+	return c.VSimWithArgsWithScores(ctx, key, val, nil)
+}
+
+type VSimArgs struct {
+	Count   int64
+	EF      int64
+	Epsilon float64
+	// Filter is a filter expression on the attributes of the elements, e.g. ".year > 2000".
+	Filter   string
+	FilterEF int64
+	Truth    bool
+	NoThread bool
+}
+
+func (v *VSimArgs) appendArgs(args []interface{}) []interface{} {
+	if v == nil {
+		return args
+	}
+	if v.Count > 0 {
+		args = append(args, "COUNT", v.Count)
+	}
+	if v.EF > 0 {
+		args = append(args, "EF", v.EF)
+	}
+	if v.Epsilon > 0 {
+		args = append(args, "EPSILON", v.Epsilon)
+	}
+	if v.Filter != "" {
+		args = append(args, "FILTER", v.Filter)
+	}
+	if v.FilterEF > 0 {
+		args = append(args, "FILTER-EF", v.FilterEF)
+	}
+	if v.Truth {
+		args = append(args, "TRUTH")
+	}
+	if v.NoThread {
+		args = append(args, "NOTHREAD")
+	}
+	return args
+}
+
+// VSimWithArgs returns the elements of the vector set key most similar to val with the options of simArgs.
+// For more information - https://redis.io/commands/vsim/
+func (c cmdable) VSimWithArgs(ctx context.Context, key string, val Vector, simArgs *VSimArgs) *StringSliceCmd {
+	args := []interface{}{"VSIM", key}
+	args = append(args, val.Value()...)
+	args = simArgs.appendArgs(args)
+	cmd := NewStringSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+// VSimWithArgsWithScores returns the elements of the vector set key most similar to val
+// with their similarity scores and the options of simArgs.
+// For more information - https://redis.io/commands/vsim/
+func (c cmdable) VSimWithArgsWithScores(ctx context.Context, key string, val Vector, simArgs *VSimArgs) *VectorScoreSliceCmd {
+	args := []interface{}{"VSIM", key}
+	args = append(args, val.Value()...)
+	args = append(args, "WITHSCORES")
+	args = simArgs.appendArgs(args)
+	cmd := NewVectorScoreSliceCmd(ctx, args...)
+	_ = c(ctx, cmd)
+	return cmd
+}
+
+//------------------------------------------------------------------------------
+
+// VectorScore is an element of a vector set with its similarity score.
+type VectorScore struct {
+	Name  string
+	Score float64
+}
+
+type VectorScoreSliceCmd struct {
+	baseCmd
+
+	val []VectorScore
+}
+
+var _ Cmder = (*VectorScoreSliceCmd)(nil)
+
+func NewVectorScoreSliceCmd(ctx context.Context, args ...interface{}) *VectorScoreSliceCmd {
+	return &VectorScoreSliceCmd{
+		baseCmd: baseCmd{
+			ctx:  ctx,
+			args: args,
+		},
+	}
+}
+
+func (cmd *VectorScoreSliceCmd) SetVal(val []VectorScore) {
+	cmd.val = val
+}
+
+func (cmd *VectorScoreSliceCmd) Val() []VectorScore {
+	return cmd.val
+}
+
+func (cmd *VectorScoreSliceCmd) Result() ([]VectorScore, error) {
+	return cmd.val, cmd.err
+}
+
+func (cmd *VectorScoreSliceCmd) String() string {
+	return cmdString(cmd, cmd.val)
+}
+
+func (cmd *VectorScoreSliceCmd) readReply(rd *proto.Reader) error {
+	// RESP3 replies with a map of the elements to their scores, RESP2 with a flat array.
+	n, err := rd.ReadMapLen()
+	if err != nil {
+		return err
+	}
+
+	cmd.val = make([]VectorScore, n)
+	for i := 0; i < n; i++ {
+		if cmd.val[i].Name, err = rd.ReadString(); err != nil {
+			return err
+		}
+		if cmd.val[i].Score, err = rd.ReadFloat(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
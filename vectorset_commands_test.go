@@ -0,0 +1,75 @@
+package redis_test
+
+import (
+	. "github.com/bsm/ginkgo/v2"
+	. "github.com/bsm/gomega"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ = Describe("Vector set commands", Label("vectorset"), func() {
+	var client *redis.Client
+
+	BeforeEach(func() {
+		client = redis.NewClient(redisOptions())
+		Expect(client.FlushDB(ctx).Err()).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).NotTo(HaveOccurred())
+	})
+
+	It("should VAdd, VCard, VDim and VEmb", func() {
+		added, err := client.VAdd(ctx, "vset", "a", redis.VectorFloat32([]float32{1, 0})).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeTrue())
+
+		added, err = client.VAddWithArgs(ctx, "vset", "b", &redis.VectorValues{Val: []float64{0, 1}}, &redis.VAddArgs{NoQuant: true}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeTrue())
+
+		Expect(client.VCard(ctx, "vset").Val()).To(Equal(int64(2)))
+		Expect(client.VDim(ctx, "vset").Val()).To(Equal(int64(2)))
+
+		emb, err := client.VEmb(ctx, "vset", "b").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(emb).To(Equal([]float64{0, 1}))
+
+		info, err := client.VInfo(ctx, "vset").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info).To(HaveKey("size"))
+
+		Expect(client.VRem(ctx, "vset", "a").Val()).To(BeTrue())
+		Expect(client.VRem(ctx, "vset", "a").Val()).To(BeFalse())
+		Expect(client.VRandMember(ctx, "vset").Val()).To(Equal("b"))
+		Expect(client.VRandMemberCount(ctx, "vset", 2).Val()).To(Equal([]string{"b"}))
+	})
+
+	It("should VSim with scores and filters", func() {
+		for name, vec := range map[string][]float32{"a": {1, 0}, "b": {0.9, 0.1}, "c": {0, 1}} {
+			Expect(client.VAdd(ctx, "vset", name, redis.VectorFloat32(vec)).Err()).NotTo(HaveOccurred())
+		}
+		Expect(client.VSetAttr(ctx, "vset", "a", map[string]interface{}{"year": 1990}).Val()).To(BeTrue())
+		Expect(client.VSetAttr(ctx, "vset", "b", `{"year":2010}`).Val()).To(BeTrue())
+
+		attr, err := client.VGetAttr(ctx, "vset", "a").Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attr).To(MatchJSON(`{"year":1990}`))
+		_, err = client.VGetAttr(ctx, "vset", "c").Result()
+		Expect(err).To(Equal(redis.Nil))
+
+		res, err := client.VSimWithArgs(ctx, "vset", &redis.VectorRef{Name: "a"}, &redis.VSimArgs{Count: 2}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal([]string{"a", "b"}))
+
+		scores, err := client.VSimWithScores(ctx, "vset", redis.VectorFloat32([]float32{1, 0})).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scores).To(HaveLen(3))
+		Expect(scores[0].Name).To(Equal("a"))
+		Expect(scores[0].Score).To(BeNumerically("~", 1, 0.01))
+
+		res, err = client.VSimWithArgs(ctx, "vset", redis.VectorFloat32([]float32{1, 0}), &redis.VSimArgs{Filter: ".year > 2000"}).Result()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal([]string{"b"}))
+	})
+})
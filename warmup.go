@@ -0,0 +1,121 @@
+package redis
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9/internal/pool"
+	"github.com/redis/go-redis/v9/internal/proto"
+)
+
+// WarmUp dials and initializes up to n connections of the pool at once, and
+// returns once they all answered a PING, or with the first error. Unlike
+// MinIdleConns, which dials the connections in the background, it lets the
+// applications wait for their connections before serving, e.g.
+//
+//	if err := rdb.WarmUp(ctx, 10); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// n is capped to the size of the pool. The connections are kept idle in the
+// pool, unless MaxIdleConns or ConnMaxIdleTime close them.
+func (c *Client) WarmUp(ctx context.Context, n int) error {
+	return c.warmUp(ctx, n)
+}
+
+// Ready returns nil when the client can process commands, its server
+// answering a PING, and the error of the PING otherwise, e.g. for the
+// readiness probes. It does not wait for the server.
+func (c *Client) Ready(ctx context.Context) error {
+	return c.Ping(ctx).Err()
+}
+
+// WarmUp warms up n connections of the pool of each node as Client.WarmUp,
+// the masters and the replicas.
+func (c *ClusterClient) WarmUp(ctx context.Context, n int) error {
+	return c.ForEachShard(ctx, func(ctx context.Context, shard *Client) error {
+		return shard.WarmUp(ctx, n)
+	})
+}
+
+// Ready returns nil when every node of the cluster answers a PING, and the
+// error of the first one which does not otherwise, see Client.Ready.
+func (c *ClusterClient) Ready(ctx context.Context) error {
+	return c.ForEachShard(ctx, func(ctx context.Context, shard *Client) error {
+		return shard.Ready(ctx)
+	})
+}
+
+// WarmUp warms up n connections of the pool of each shard as Client.WarmUp.
+func (c *Ring) WarmUp(ctx context.Context, n int) error {
+	return c.ForEachShard(ctx, func(ctx context.Context, shard *Client) error {
+		return shard.WarmUp(ctx, n)
+	})
+}
+
+// Ready returns nil when every shard of the ring answers a PING, and the error
+// of the first one which does not otherwise, see Client.Ready.
+func (c *Ring) Ready(ctx context.Context) error {
+	return c.ForEachShard(ctx, func(ctx context.Context, shard *Client) error {
+		return shard.Ready(ctx)
+	})
+}
+
+func (c *baseClient) warmUp(ctx context.Context, n int) error {
+	if size := c.poolSize(); n > size {
+		n = size
+	}
+
+	// the connections are all held until they answered, for them to be
+	// distinct
+	cns := make([]*pool.Conn, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cns[i], errs[i] = c.getConn(ctx)
+			if errs[i] == nil {
+				errs[i] = c.pingConn(ctx, cns[i])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var firstErr error
+	for i, cn := range cns {
+		if cn != nil {
+			c.releaseConn(ctx, cn, errs[i])
+		}
+		if errs[i] != nil && firstErr == nil {
+			firstErr = errs[i]
+		}
+	}
+	return firstErr
+}
+
+// pingConn sends a PING with cn.
+func (c *baseClient) pingConn(ctx context.Context, cn *pool.Conn) error {
+	return c.connCmd(ctx, cn, NewStatusCmd(ctx, "ping"))
+}
+
+// connCmd processes cmd with cn, outside of the hooks.
+func (c *baseClient) connCmd(ctx context.Context, cn *pool.Conn, cmd Cmder) error {
+	if err := cn.WithWriter(c.context(ctx), c.opt.WriteTimeout, func(wr *proto.Writer) error {
+		return writeCmd(wr, cmd)
+	}); err != nil {
+		return err
+	}
+	return cn.WithReader(c.context(ctx), c.opt.ReadTimeout, func(rd *proto.Reader) error {
+		return c.pushes.readReply(ctx, rd, cmd)
+	})
+}
+
+// poolSize returns the number of connections of the pool used at once.
+func (c *baseClient) poolSize() int {
+	if p, ok := c.connPool.(*pool.ConnPool); ok {
+		return p.Size()
+	}
+	return c.opt.PoolSize
+}
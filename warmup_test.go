@@ -0,0 +1,71 @@
+package redis_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/redistest"
+)
+
+func TestWarmUp(t *testing.T) {
+	srv := redistest.NewServer()
+	defer srv.Close()
+
+	var dials int32
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     srv.Addr(),
+		PoolSize: 5,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return net.Dial(network, addr)
+		},
+	})
+	defer rdb.Close()
+
+	if err := rdb.WarmUp(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if stats := rdb.PoolStats(); stats.TotalConns != 3 || stats.IdleConns != 3 {
+		t.Fatalf("got %d conns, %d idle, wanted 3", stats.TotalConns, stats.IdleConns)
+	}
+
+	// capped to the pool size, reusing the idle connections
+	if err := rdb.WarmUp(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	if stats := rdb.PoolStats(); stats.TotalConns != 5 || stats.IdleConns != 5 {
+		t.Fatalf("got %d conns, %d idle, wanted 5", stats.TotalConns, stats.IdleConns)
+	}
+	if n := atomic.LoadInt32(&dials); n != 5 {
+		t.Fatalf("got %d dials, wanted 5", n)
+	}
+
+	if err := rdb.Ready(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&dials); n != 5 {
+		t.Fatalf("got %d dials, wanted 5", n)
+	}
+}
+
+func TestWarmUpError(t *testing.T) {
+	srv := redistest.NewServer()
+	addr := srv.Addr()
+	srv.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	defer rdb.Close()
+
+	if err := rdb.WarmUp(ctx, 2); err == nil {
+		t.Fatal("WarmUp succeeded without server")
+	}
+	if stats := rdb.PoolStats(); stats.TotalConns != 0 {
+		t.Fatalf("got %d conns, wanted 0", stats.TotalConns)
+	}
+	if err := rdb.Ready(ctx); err == nil {
+		t.Fatal("Ready succeeded without server")
+	}
+}